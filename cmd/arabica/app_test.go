@@ -17,12 +17,16 @@ func TestArabicaApp_OAuthScopes_matchesLegacyList(t *testing.T) {
 
 	want := []string{
 		"atproto",
+		"blob:image/*",
 		"repo:social.arabica.alpha.bean",
+		"repo:social.arabica.alpha.bookmark",
 		"repo:social.arabica.alpha.brew",
 		"repo:social.arabica.alpha.brewer",
 		"repo:social.arabica.alpha.comment",
+		"repo:social.arabica.alpha.follow",
 		"repo:social.arabica.alpha.grinder",
 		"repo:social.arabica.alpha.like",
+		"repo:social.arabica.alpha.preferences",
 		"repo:social.arabica.alpha.recipe",
 		"repo:social.arabica.alpha.roaster",
 	}
@@ -37,9 +41,11 @@ func TestArabicaApp_NSIDs(t *testing.T) {
 
 	want := []string{
 		"social.arabica.alpha.bean",
+		"social.arabica.alpha.bookmark",
 		"social.arabica.alpha.brew",
 		"social.arabica.alpha.brewer",
 		"social.arabica.alpha.comment",
+		"social.arabica.alpha.follow",
 		"social.arabica.alpha.grinder",
 		"social.arabica.alpha.like",
 		"social.arabica.alpha.recipe",
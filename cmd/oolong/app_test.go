@@ -14,8 +14,10 @@ func TestOolongApp_NSIDs(t *testing.T) {
 	sort.Strings(got)
 
 	want := []string{
+		"social.oolong.alpha.bookmark",
 		"social.oolong.alpha.brew",
 		"social.oolong.alpha.comment",
+		"social.oolong.alpha.follow",
 		"social.oolong.alpha.infuser",
 		"social.oolong.alpha.like",
 		"social.oolong.alpha.tea",
@@ -34,8 +36,10 @@ func TestOolongApp_OAuthScopes(t *testing.T) {
 
 	want := []string{
 		"atproto",
+		"repo:social.oolong.alpha.bookmark",
 		"repo:social.oolong.alpha.brew",
 		"repo:social.oolong.alpha.comment",
+		"repo:social.oolong.alpha.follow",
 		"repo:social.oolong.alpha.infuser",
 		"repo:social.oolong.alpha.like",
 		"repo:social.oolong.alpha.tea",
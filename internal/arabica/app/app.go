@@ -28,6 +28,10 @@ func New() *domain.App {
 			DisplayName: "Arabica",
 			Tagline:     "Your brew, your data",
 		},
+		// Brew photos are uploaded as blobs to the user's repo. The
+		// preferences singleton isn't a feed entity, so its repo scope has
+		// to be requested explicitly.
+		LoginScopes: []string{"blob:image/*", "repo:" + arabica.NSIDPreferences},
 		RecordStore: func(store records.Store) records.Store {
 			if atpStore, ok := store.(*atproto.AtprotoStore); ok {
 				return arabicastore.NewAtprotoStore(atpStore)
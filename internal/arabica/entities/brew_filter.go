@@ -0,0 +1,98 @@
+package arabica
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Sort orders accepted by BrewFilter.Sort.
+const (
+	BrewSortDate   = "date"   // newest first (default)
+	BrewSortRating = "rating" // highest rating first
+	BrewSortRatio  = "ratio"  // strongest (lowest water:coffee) first
+)
+
+// BrewFilter defines criteria for narrowing and ordering a brew list.
+type BrewFilter struct {
+	BeanRKey  string // exact match on the brew's bean rkey
+	Method    string // matches the brew's canonical method, or its brewer type case-insensitively
+	MinRating int    // minimum rating; unrated brews are excluded when set
+	Tag       string // brew must carry this tag, compared after NormalizeTag
+	Sort      string // one of the BrewSort* constants; empty means date
+}
+
+// IsDefault reports whether the filter would return brews unchanged in the
+// default newest-first order.
+func (f BrewFilter) IsDefault() bool {
+	return f.BeanRKey == "" && f.Method == "" && f.MinRating <= 0 && f.Tag == "" &&
+		(f.Sort == "" || f.Sort == BrewSortDate)
+}
+
+// MatchesBrewFilter returns true if the brew satisfies all non-zero filter
+// criteria. Sort is ignored.
+func MatchesBrewFilter(brew *Brew, filter BrewFilter) bool {
+	if filter.BeanRKey != "" && brew.BeanRKey != filter.BeanRKey {
+		return false
+	}
+	if filter.Method != "" && !brewMethodMatches(brew, filter.Method) &&
+		(brew.BrewerObj == nil || !strings.EqualFold(brew.BrewerObj.BrewerType, filter.Method)) {
+		return false
+	}
+	if filter.MinRating > 0 && brew.Rating < filter.MinRating {
+		return false
+	}
+	if filter.Tag != "" && !slices.Contains(brew.Tags, NormalizeTag(filter.Tag)) {
+		return false
+	}
+	return true
+}
+
+// brewMethodMatches compares methods after normalizing both sides, so a
+// filter for "Hario V60" finds brews logged as "v60". Two "other" methods
+// match only when the user's wording agrees.
+func brewMethodMatches(brew *Brew, want string) bool {
+	wantMethod, wantOther := NormalizeBrewMethod(want)
+	method, other := brew.CanonicalMethod()
+	if method != wantMethod {
+		return false
+	}
+	return method != BrewMethodOther || strings.EqualFold(other, wantOther)
+}
+
+// FilterBrews returns the brews matching the filter, sorted by filter.Sort.
+// The input slice is not modified. Ties fall back to newest first, and brews
+// without a computable ratio sort last under BrewSortRatio.
+func FilterBrews(brews []*Brew, filter BrewFilter) []*Brew {
+	result := make([]*Brew, 0, len(brews))
+	for _, b := range brews {
+		if MatchesBrewFilter(b, filter) {
+			result = append(result, b)
+		}
+	}
+
+	newer := func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) }
+	switch filter.Sort {
+	case BrewSortRating:
+		sort.SliceStable(result, func(i, j int) bool {
+			if result[i].Rating != result[j].Rating {
+				return result[i].Rating > result[j].Rating
+			}
+			return newer(i, j)
+		})
+	case BrewSortRatio:
+		sort.SliceStable(result, func(i, j int) bool {
+			ri, rj := result[i].Ratio(), result[j].Ratio()
+			if ri != rj {
+				if ri == 0 || rj == 0 {
+					return rj == 0
+				}
+				return ri < rj
+			}
+			return newer(i, j)
+		})
+	default:
+		sort.SliceStable(result, newer)
+	}
+	return result
+}
@@ -0,0 +1,75 @@
+package arabica
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterBrews(t *testing.T) {
+	base := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	brews := []*Brew{
+		{RKey: "a", BeanRKey: "bean1", Method: "V60", Rating: 7, CoffeeAmount: 15, WaterAmount: 250, CreatedAt: base},
+		{RKey: "b", BeanRKey: "bean2", Rating: 9, CoffeeAmount: 18, WaterAmount: 36, CreatedAt: base.Add(time.Hour),
+			BrewerObj: &Brewer{BrewerType: "Espresso"}},
+		{RKey: "c", BeanRKey: "bean1", Method: "v60", Tags: []string{"washed"}, CreatedAt: base.Add(2 * time.Hour)},
+		{RKey: "d", BeanRKey: "bean2", Method: "AeroPress", Rating: 9, CoffeeAmount: 15, WaterAmount: 225, CreatedAt: base.Add(3 * time.Hour)},
+	}
+
+	tests := []struct {
+		name   string
+		filter BrewFilter
+		want   []string
+	}{
+		{"default newest first", BrewFilter{}, []string{"d", "c", "b", "a"}},
+		{"by bean", BrewFilter{BeanRKey: "bean1"}, []string{"c", "a"}},
+		{"missing bean", BrewFilter{BeanRKey: "gone"}, []string{}},
+		{"method is case-insensitive", BrewFilter{Method: "V60"}, []string{"c", "a"}},
+		{"method matches aliases", BrewFilter{Method: "Hario V60"}, []string{"c", "a"}},
+		{"method matches brewer type", BrewFilter{Method: "espresso"}, []string{"b"}},
+		{"min rating excludes unrated", BrewFilter{MinRating: 8}, []string{"d", "b"}},
+		{"sort by rating", BrewFilter{Sort: BrewSortRating}, []string{"d", "b", "a", "c"}},
+		{"sort by ratio, missing last", BrewFilter{Sort: BrewSortRatio}, []string{"b", "d", "a", "c"}},
+		{"filter and sort", BrewFilter{BeanRKey: "bean2", Sort: BrewSortRatio}, []string{"b", "d"}},
+		{"by tag", BrewFilter{Tag: "washed"}, []string{"c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, b := range FilterBrews(brews, tt.filter) {
+				got = append(got, b.RKey)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, "a", brews[0].RKey, "input slice must not be reordered")
+}
+
+func TestBrewFilterIsDefault(t *testing.T) {
+	assert.True(t, BrewFilter{}.IsDefault())
+	assert.True(t, BrewFilter{Sort: BrewSortDate}.IsDefault())
+	assert.False(t, BrewFilter{Sort: BrewSortRating}.IsDefault())
+	assert.False(t, BrewFilter{BeanRKey: "x"}.IsDefault())
+	assert.False(t, BrewFilter{MinRating: 1}.IsDefault())
+}
+
+func TestMatchesBrewFilter_OtherMethod(t *testing.T) {
+	brew := &Brew{Method: string(BrewMethodOther), MethodOther: "Hario Switch"}
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{"hario switch", true},
+		{"Phin", false},
+		{"other", false},
+		{"V60", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchesBrewFilter(brew, BrewFilter{Method: tt.method}))
+		})
+	}
+}
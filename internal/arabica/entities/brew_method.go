@@ -0,0 +1,138 @@
+package arabica
+
+import "strings"
+
+// BrewMethod is the canonical name of how a brew was made. The lexicon
+// lists these as knownValues; anything else is stored as BrewMethodOther
+// with the user's own wording kept alongside in Brew.MethodOther.
+type BrewMethod string
+
+const (
+	BrewMethodV60         BrewMethod = "v60"
+	BrewMethodKalitaWave  BrewMethod = "kalita-wave"
+	BrewMethodChemex      BrewMethod = "chemex"
+	BrewMethodOrigami     BrewMethod = "origami"
+	BrewMethodPourover    BrewMethod = "pourover"
+	BrewMethodAeroPress   BrewMethod = "aeropress"
+	BrewMethodFrenchPress BrewMethod = "french-press"
+	BrewMethodClever      BrewMethod = "clever"
+	BrewMethodSiphon      BrewMethod = "siphon"
+	BrewMethodEspresso    BrewMethod = "espresso"
+	BrewMethodMokaPot     BrewMethod = "moka-pot"
+	BrewMethodColdBrew    BrewMethod = "cold-brew"
+	BrewMethodCupping     BrewMethod = "cupping"
+	BrewMethodOther       BrewMethod = "other"
+)
+
+// BrewMethodKnownValues is the ordered list for form dropdowns
+var BrewMethodKnownValues = []BrewMethod{
+	BrewMethodV60,
+	BrewMethodKalitaWave,
+	BrewMethodChemex,
+	BrewMethodOrigami,
+	BrewMethodPourover,
+	BrewMethodAeroPress,
+	BrewMethodFrenchPress,
+	BrewMethodClever,
+	BrewMethodSiphon,
+	BrewMethodEspresso,
+	BrewMethodMokaPot,
+	BrewMethodColdBrew,
+	BrewMethodCupping,
+	BrewMethodOther,
+}
+
+// BrewMethodLabels maps canonical methods to display labels
+var BrewMethodLabels = map[BrewMethod]string{
+	BrewMethodV60:         "V60",
+	BrewMethodKalitaWave:  "Kalita Wave",
+	BrewMethodChemex:      "Chemex",
+	BrewMethodOrigami:     "Origami",
+	BrewMethodPourover:    "Pour-over",
+	BrewMethodAeroPress:   "AeroPress",
+	BrewMethodFrenchPress: "French Press",
+	BrewMethodClever:      "Clever Dripper",
+	BrewMethodSiphon:      "Siphon",
+	BrewMethodEspresso:    "Espresso",
+	BrewMethodMokaPot:     "Moka Pot",
+	BrewMethodColdBrew:    "Cold Brew",
+	BrewMethodCupping:     "Cupping",
+	BrewMethodOther:       "Other",
+}
+
+// brewMethodAliases maps common spellings, after brewMethodKey folding, to
+// canonical methods. Canonical values and labels are added in init.
+var brewMethodAliases = map[string]BrewMethod{
+	"hario v60":        BrewMethodV60,
+	"hario":            BrewMethodV60,
+	"kalita":           BrewMethodKalitaWave,
+	"wave":             BrewMethodKalitaWave,
+	"origami dripper":  BrewMethodOrigami,
+	"pour over":        BrewMethodPourover,
+	"dripper":          BrewMethodPourover,
+	"aero press":       BrewMethodAeroPress,
+	"aeropress go":     BrewMethodAeroPress,
+	"press":            BrewMethodFrenchPress,
+	"press pot":        BrewMethodFrenchPress,
+	"cafetiere":        BrewMethodFrenchPress,
+	"cafetière":        BrewMethodFrenchPress,
+	"syphon":           BrewMethodSiphon,
+	"vacuum pot":       BrewMethodSiphon,
+	"espresso machine": BrewMethodEspresso,
+	"lever espresso":   BrewMethodEspresso,
+	"moka":             BrewMethodMokaPot,
+	"mokapot":          BrewMethodMokaPot,
+	"bialetti":         BrewMethodMokaPot,
+	"stovetop":         BrewMethodMokaPot,
+	"coldbrew":         BrewMethodColdBrew,
+	"cold drip":        BrewMethodColdBrew,
+}
+
+func init() {
+	for _, m := range BrewMethodKnownValues {
+		brewMethodAliases[brewMethodKey(string(m))] = m
+		brewMethodAliases[brewMethodKey(BrewMethodLabels[m])] = m
+	}
+}
+
+// brewMethodKey folds case, hyphens, underscores and repeated spaces so
+// "Hario-V60" and "hario  v60" look the same.
+func brewMethodKey(raw string) string {
+	raw = strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(raw))
+	return strings.Join(strings.Fields(raw), " ")
+}
+
+// NormalizeBrewMethod maps free-text method input to a canonical method.
+// Input that matches no known method comes back as BrewMethodOther with
+// the trimmed text as other, so nothing the user typed is lost. Blank
+// input returns "".
+func NormalizeBrewMethod(raw string) (method BrewMethod, other string) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", ""
+	}
+	if m, ok := brewMethodAliases[brewMethodKey(raw)]; ok {
+		return m, ""
+	}
+	return BrewMethodOther, raw
+}
+
+// CanonicalMethod returns the brew's method in canonical form. Records
+// written before methods were normalized still carry free text, so the
+// stored value is normalized again on the way out.
+func (b *Brew) CanonicalMethod() (method BrewMethod, other string) {
+	if BrewMethod(b.Method) == BrewMethodOther {
+		return BrewMethodOther, b.MethodOther
+	}
+	return NormalizeBrewMethod(b.Method)
+}
+
+// MethodLabel is the brew's method for display: the canonical label, or
+// the user's own wording for methods we don't know.
+func (b *Brew) MethodLabel() string {
+	method, other := b.CanonicalMethod()
+	if method == BrewMethodOther && other != "" {
+		return other
+	}
+	return BrewMethodLabels[method]
+}
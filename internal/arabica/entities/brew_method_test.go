@@ -0,0 +1,54 @@
+package arabica
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeBrewMethod(t *testing.T) {
+	tests := []struct {
+		raw       string
+		wantOther string
+		want      BrewMethod
+	}{
+		{"", "", ""},
+		{"V60", "", BrewMethodV60},
+		{"  hario-v60 ", "", BrewMethodV60},
+		{"Hario  V60", "", BrewMethodV60},
+		{"Pour Over", "", BrewMethodPourover},
+		{"French Press", "", BrewMethodFrenchPress},
+		{"french-press", "", BrewMethodFrenchPress},
+		{"Clever Dripper", "", BrewMethodClever},
+		{"Bialetti", "", BrewMethodMokaPot},
+		{"other", "", BrewMethodOther},
+		{" Hario Switch ", "Hario Switch", BrewMethodOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, other := NormalizeBrewMethod(tt.raw)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOther, other)
+		})
+	}
+}
+
+func TestBrewMethodLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		brew *Brew
+		want string
+	}{
+		{"none", &Brew{}, ""},
+		{"canonical", &Brew{Method: "aeropress"}, "AeroPress"},
+		{"legacy free text", &Brew{Method: "hario v60"}, "V60"},
+		{"legacy unknown text", &Brew{Method: "Phin filter"}, "Phin filter"},
+		{"other with wording", &Brew{Method: "other", MethodOther: "Hario Switch"}, "Hario Switch"},
+		{"other without wording", &Brew{Method: "other"}, "Other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.brew.MethodLabel())
+		})
+	}
+}
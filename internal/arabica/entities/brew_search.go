@@ -0,0 +1,69 @@
+package arabica
+
+import (
+	"sort"
+	"strings"
+)
+
+// Search match ranks, best first. A query that names the bean outright is
+// almost always what the user meant, so it beats a passing mention in notes.
+const (
+	brewMatchBeanName = iota
+	brewMatchField
+	brewMatchNotes
+	brewNoMatch
+)
+
+// SearchBrews returns the brews whose tasting notes, bean name, origin, or
+// method contain query, case-insensitively. Results are ranked with exact
+// bean-name matches first, then other field matches, then notes-only
+// matches; each rank is newest first. An empty query returns every brew
+// newest first. The input slice is not modified.
+func SearchBrews(brews []*Brew, query string) []*Brew {
+	q := strings.ToLower(strings.TrimSpace(query))
+	ranks := make(map[*Brew]int, len(brews))
+	result := make([]*Brew, 0, len(brews))
+	for _, b := range brews {
+		rank := brewMatchRank(b, q)
+		if rank == brewNoMatch {
+			continue
+		}
+		ranks[b] = rank
+		result = append(result, b)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if ri, rj := ranks[result[i]], ranks[result[j]]; ri != rj {
+			return ri < rj
+		}
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result
+}
+
+// brewMatchRank scores b against an already-lowercased query.
+func brewMatchRank(b *Brew, q string) int {
+	if q == "" {
+		return brewMatchField
+	}
+	contains := func(s string) bool { return s != "" && strings.Contains(strings.ToLower(s), q) }
+
+	if b.Bean != nil {
+		if strings.ToLower(b.Bean.Name) == q {
+			return brewMatchBeanName
+		}
+		if contains(b.Bean.Name) || contains(b.Bean.Origin) {
+			return brewMatchField
+		}
+	}
+	if contains(b.Method) || contains(b.MethodOther) || contains(b.MethodLabel()) {
+		return brewMatchField
+	}
+	if b.BrewerObj != nil && (contains(b.BrewerObj.BrewerType) || contains(b.BrewerObj.Name)) {
+		return brewMatchField
+	}
+	if contains(b.TastingNotes) {
+		return brewMatchNotes
+	}
+	return brewNoMatch
+}
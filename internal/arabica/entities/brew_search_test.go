@@ -0,0 +1,45 @@
+package arabica
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchBrews(t *testing.T) {
+	base := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	ethiopia := &Bean{Name: "Yirgacheffe", Origin: "Ethiopia"}
+	brews := []*Brew{
+		{RKey: "a", Bean: ethiopia, Method: "V60", TastingNotes: "Bright, floral", CreatedAt: base},
+		{RKey: "b", Bean: &Bean{Name: "House Blend", Origin: "Brazil"}, TastingNotes: "Chocolatey, like a Yirgacheffe", CreatedAt: base.Add(time.Hour)},
+		{RKey: "c", Bean: ethiopia, BrewerObj: &Brewer{Name: "Flair 58", BrewerType: "Espresso"}, TastingNotes: "Dark CHOCOLATE", CreatedAt: base.Add(2 * time.Hour)},
+		{RKey: "d", TastingNotes: "", CreatedAt: base.Add(3 * time.Hour)},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"empty query returns all newest first", "  ", []string{"d", "c", "b", "a"}},
+		{"notes are case-insensitive", "chocolate", []string{"c", "b"}},
+		{"origin", "ethiopia", []string{"c", "a"}},
+		{"method", "v60", []string{"a"}},
+		{"brewer type", "espresso", []string{"c"}},
+		{"exact bean name ranks above notes", "yirgacheffe", []string{"c", "a", "b"}},
+		{"no match", "fruity", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, b := range SearchBrews(brews, tt.query) {
+				got = append(got, b.RKey)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, "a", brews[0].RKey, "input slice must not be reordered")
+}
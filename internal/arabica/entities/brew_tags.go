@@ -0,0 +1,31 @@
+package arabica
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"tangled.org/arabica.social/arabica/internal/brewtags"
+)
+
+// ParseBrewTags splits a comma-separated tag field into normalized tags.
+// Tags are normalized with brewtags.NormalizeAll, so empty entries and
+// duplicates are dropped. Returns ErrTooManyTags if more than MaxBrewTags
+// remain, and ErrTagTooLong if any exceeds MaxTagLength characters.
+func ParseBrewTags(raw string) ([]string, error) {
+	tags := brewtags.NormalizeAll(strings.Split(raw, ","))
+	if len(tags) > MaxBrewTags {
+		return nil, ErrTooManyTags
+	}
+	for _, tag := range tags {
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, ErrTagTooLong
+		}
+	}
+	return tags, nil
+}
+
+// NormalizeTag returns the canonical form of a single tag, used both when
+// saving and when looking tags up from a URL.
+func NormalizeTag(tag string) string {
+	return brewtags.Normalize(tag)
+}
@@ -0,0 +1,39 @@
+package arabica
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBrewTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []string
+		wantErr error
+	}{
+		{"empty", "", nil, nil},
+		{"trims and lowercases", " Washed ,  Light Roast", []string{"washed", "light roast"}, nil},
+		{"strips hash and empties", "#morning,, ,#", []string{"morning"}, nil},
+		{"dedupes after normalizing", "Fruity,fruity,#FRUITY", []string{"fruity"}, nil},
+		{"at the limit", "a,b,c,d,e,f,g,h,i,j", []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}, nil},
+		{"duplicates don't count", "a,b,c,d,e,f,g,h,i,j,A,#b", []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}, nil},
+		{"too many", "a,b,c,d,e,f,g,h,i,j,k", nil, ErrTooManyTags},
+		{"too long", strings.Repeat("x", MaxTagLength+1), nil, ErrTagTooLong},
+		{"multibyte counts runes", strings.Repeat("é", MaxTagLength), []string{strings.Repeat("é", MaxTagLength)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBrewTags(tt.raw)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
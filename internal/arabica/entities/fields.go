@@ -26,6 +26,16 @@ func beanField(e any, field string) (string, bool) {
 		return b.Notes, true
 	case "link":
 		return b.Link, true
+	case "bag_size_grams":
+		if b.BagSizeGrams > 0 {
+			return fmt.Sprintf("%d", b.BagSizeGrams), true
+		}
+		return "", false
+	case "remaining_grams":
+		if b.RemainingGrams != nil {
+			return fmt.Sprintf("%d", *b.RemainingGrams), true
+		}
+		return "", false
 	}
 	return "", false
 }
@@ -58,6 +68,8 @@ func grinderField(e any, field string) (string, bool) {
 		return g.Notes, true
 	case "link":
 		return g.Link, true
+	case "grind_settings":
+		return FormatGrindPresets(g.GrindSettings), len(g.GrindSettings) > 0
 	}
 	return "", false
 }
@@ -0,0 +1,76 @@
+package arabica
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Grind preset limits, matching the grinder lexicon's #grindPreset def.
+const (
+	MaxGrindPresets           = 20
+	MaxGrindPresetNameLength  = 50
+	MaxGrindPresetValueLength = 50
+)
+
+var (
+	ErrTooManyGrindPresets = fmt.Errorf("a grinder can have at most %d grind presets", MaxGrindPresets)
+	ErrInvalidGrindPreset  = errors.New(`grind presets must be one "name: setting" per line`)
+)
+
+// GrindPreset is a named setting the user dials a grinder to for a kind of
+// brew, e.g. "espresso" at "8".
+type GrindPreset struct {
+	Name    string `json:"name"`
+	Setting string `json:"setting"`
+}
+
+// ParseGrindPresets reads presets from a textarea, one "name: setting" per
+// line. Blank lines are skipped and a later line replaces an earlier one
+// with the same name (ignoring case). Limits are left to Validate on the
+// grinder request.
+func ParseGrindPresets(raw string) ([]GrindPreset, error) {
+	var presets []GrindPreset
+	index := make(map[string]int)
+	for line := range strings.SplitSeq(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, setting, ok := strings.Cut(line, ":")
+		name, setting = strings.TrimSpace(name), strings.TrimSpace(setting)
+		if !ok || name == "" || setting == "" {
+			return nil, ErrInvalidGrindPreset
+		}
+		key := strings.ToLower(name)
+		if i, seen := index[key]; seen {
+			presets[i].Setting = setting
+			continue
+		}
+		index[key] = len(presets)
+		presets = append(presets, GrindPreset{Name: name, Setting: setting})
+	}
+	return presets, nil
+}
+
+// FormatGrindPresets is the inverse of ParseGrindPresets, used to fill the
+// grinder edit form.
+func FormatGrindPresets(presets []GrindPreset) string {
+	lines := make([]string, len(presets))
+	for i, p := range presets {
+		lines[i] = p.Name + ": " + p.Setting
+	}
+	return strings.Join(lines, "\n")
+}
+
+func validateGrindPresets(presets []GrindPreset) error {
+	if len(presets) > MaxGrindPresets {
+		return ErrTooManyGrindPresets
+	}
+	for _, p := range presets {
+		if len(p.Name) > MaxGrindPresetNameLength || len(p.Setting) > MaxGrindPresetValueLength {
+			return ErrFieldTooLong
+		}
+	}
+	return nil
+}
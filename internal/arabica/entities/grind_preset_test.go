@@ -0,0 +1,95 @@
+package arabica
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGrindPresets(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []GrindPreset
+		wantErr error
+	}{
+		{"empty", "  \n\n", nil, nil},
+		{
+			name: "one per line",
+			raw:  "espresso: 8\n  pourover :18 \n\nmoka pot: 2.4.0",
+			want: []GrindPreset{{"espresso", "8"}, {"pourover", "18"}, {"moka pot", "2.4.0"}},
+		},
+		{
+			name: "later duplicate wins",
+			raw:  "Espresso: 8\npourover: 18\nespresso: 9",
+			want: []GrindPreset{{"Espresso", "9"}, {"pourover", "18"}},
+		},
+		{"missing colon", "espresso 8", nil, ErrInvalidGrindPreset},
+		{"missing setting", "espresso:", nil, ErrInvalidGrindPreset},
+		{"missing name", ": 8", nil, ErrInvalidGrindPreset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGrindPresets(tt.raw)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatGrindPresetsRoundTrip(t *testing.T) {
+	presets := []GrindPreset{{"espresso", "8"}, {"pourover", "18"}}
+	text := FormatGrindPresets(presets)
+	assert.Equal(t, "espresso: 8\npourover: 18", text)
+
+	parsed, err := ParseGrindPresets(text)
+	require.NoError(t, err)
+	assert.Equal(t, presets, parsed)
+}
+
+func TestCreateGrinderRequestValidateGrindPresets(t *testing.T) {
+	tooMany := make([]GrindPreset, MaxGrindPresets+1)
+	for i := range tooMany {
+		tooMany[i] = GrindPreset{Name: "p", Setting: "1"}
+	}
+	tests := []struct {
+		name    string
+		presets []GrindPreset
+		wantErr error
+	}{
+		{"none", nil, nil},
+		{"within limits", []GrindPreset{{"espresso", "8"}}, nil},
+		{"too many", tooMany, ErrTooManyGrindPresets},
+		{"name too long", []GrindPreset{{strings.Repeat("a", MaxGrindPresetNameLength+1), "8"}}, ErrFieldTooLong},
+		{"setting too long", []GrindPreset{{"espresso", strings.Repeat("8", MaxGrindPresetValueLength+1)}}, ErrFieldTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := CreateGrinderRequest{Name: "Comandante C40", GrindSettings: tt.presets}
+			assert.ErrorIs(t, req.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestGrindSettingsRecordRoundTrip(t *testing.T) {
+	original := &Grinder{
+		Name:          "Comandante C40",
+		GrindSettings: []GrindPreset{{"espresso", "8"}, {"pourover", "18"}},
+		CreatedAt:     time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC),
+	}
+	record, err := GrinderToRecord(original)
+	require.NoError(t, err)
+
+	// Go through JSON-shaped values, as records come back from a PDS.
+	record["grindSettings"] = []any{
+		map[string]any{"name": "espresso", "setting": "8"},
+		map[string]any{"name": "pourover", "setting": "18"},
+		map[string]any{"name": "broken"},
+	}
+	restored, err := RecordToGrinder(record, "at://did:plc:test/social.arabica.alpha.grinder/grinder123")
+	require.NoError(t, err)
+	assert.Equal(t, original.GrindSettings, restored.GrindSettings)
+}
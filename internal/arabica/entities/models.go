@@ -2,9 +2,12 @@ package arabica
 
 import (
 	"errors"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"tangled.org/arabica.social/arabica/internal/brewtags"
 	"tangled.org/arabica.social/arabica/internal/notifications"
 	"tangled.org/arabica.social/arabica/internal/profileprefs"
 	"tangled.org/arabica.social/arabica/internal/social"
@@ -28,6 +31,27 @@ const (
 	MaxGrinderTypeLength  = 50
 	MaxBurrTypeLength     = 50
 	MaxBrewerTypeLength   = 100
+	MaxTagLength          = 32 // characters, matching the lexicon's maxGraphemes
+)
+
+// MaxBrewTags caps how many tags a brew can carry.
+const MaxBrewTags = brewtags.Max
+
+// MaxBrewImageBytes matches the maxSize of the brew lexicon's image blob.
+const MaxBrewImageBytes = 1000000
+
+// brewImageTypes are the MIME types accepted for brew photos.
+var brewImageTypes = []string{"image/jpeg", "image/png", "image/webp"}
+
+// IsAllowedBrewImageType reports whether mimeType may be attached to a brew.
+func IsAllowedBrewImageType(mimeType string) bool {
+	return slices.Contains(brewImageTypes, mimeType)
+}
+
+// Bean inventory limits, in grams
+const (
+	MaxBagSizeGrams = 10000
+	LowStockGrams   = 50 // remaining amount below which a bag is flagged as low
 )
 
 const MaxCommentLength = social.MaxCommentLength
@@ -100,19 +124,24 @@ func NormalizeBrewerType(raw string) string {
 
 // Validation errors
 var (
-	ErrNameRequired     = errors.New("name is required")
-	ErrNameTooLong      = errors.New("name is too long")
-	ErrLocationTooLong  = errors.New("location is too long")
-	ErrWebsiteTooLong   = errors.New("website is too long")
-	ErrLinkTooLong      = errors.New("link is too long")
-	ErrDescTooLong      = errors.New("description is too long")
-	ErrNotesTooLong     = errors.New("notes is too long")
-	ErrOriginTooLong    = errors.New("origin is too long")
-	ErrFieldTooLong     = errors.New("field value is too long")
-	ErrRatingOutOfRange = errors.New("rating must be between 1 and 10")
-	ErrInvalidRoastDate = errors.New("roast date must use YYYY-MM-DD format")
-	ErrCommentRequired  = social.ErrCommentRequired
-	ErrCommentTooLong   = social.ErrCommentTooLong
+	ErrNameRequired           = errors.New("name is required")
+	ErrNameTooLong            = errors.New("name is too long")
+	ErrLocationTooLong        = errors.New("location is too long")
+	ErrWebsiteTooLong         = errors.New("website is too long")
+	ErrLinkTooLong            = errors.New("link is too long")
+	ErrDescTooLong            = errors.New("description is too long")
+	ErrNotesTooLong           = errors.New("notes is too long")
+	ErrOriginTooLong          = errors.New("origin is too long")
+	ErrFieldTooLong           = errors.New("field value is too long")
+	ErrRatingOutOfRange       = errors.New("rating must be between 1 and 10")
+	ErrInvalidRoastDate       = errors.New("roast date must use YYYY-MM-DD format")
+	ErrInvalidInventory       = errors.New("bag size and remaining grams must be between 0 and 10000")
+	ErrTagTooLong             = errors.New("tags must be 32 characters or fewer")
+	ErrTooManyTags            = errors.New("a brew can have at most 10 tags")
+	ErrTastingScoreOutOfRange = errors.New("tasting scores must be between 1 and 10")
+	ErrUnknownFlavor          = errors.New("unknown flavor")
+	ErrCommentRequired        = social.ErrCommentRequired
+	ErrCommentTooLong         = social.ErrCommentTooLong
 )
 
 // TODO: maybe add a "rating" field that can be updated when a bag is closed
@@ -133,6 +162,10 @@ type Bean struct {
 	SourceRef   string    `json:"source_ref,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 
+	// Inventory tracking; RemainingGrams is nil when the user isn't tracking it
+	BagSizeGrams   int  `json:"bag_size_grams,omitempty"`
+	RemainingGrams *int `json:"remaining_grams,omitempty"`
+
 	// Joined data for display
 	Roaster *Roaster `json:"roaster,omitempty"`
 }
@@ -147,14 +180,15 @@ type Roaster struct {
 }
 
 type Grinder struct {
-	RKey        string    `json:"rkey"` // Record key
-	Name        string    `json:"name"`
-	GrinderType string    `json:"grinder_type"` // Hand, Electric, Portable Electric
-	BurrType    string    `json:"burr_type"`    // Conical, Flat, Blade, or empty
-	Notes       string    `json:"notes"`
-	Link        string    `json:"link"`
-	SourceRef   string    `json:"source_ref,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
+	RKey          string        `json:"rkey"` // Record key
+	Name          string        `json:"name"`
+	GrinderType   string        `json:"grinder_type"` // Hand, Electric, Portable Electric
+	BurrType      string        `json:"burr_type"`    // Conical, Flat, Blade, or empty
+	Notes         string        `json:"notes"`
+	Link          string        `json:"link"`
+	GrindSettings []GrindPreset `json:"grind_settings,omitempty"` // named presets, e.g. "espresso: 8"
+	SourceRef     string        `json:"source_ref,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
 }
 
 type Brewer struct {
@@ -250,7 +284,8 @@ type Brew struct {
 	RKey         string    `json:"rkey"` // Record key
 	BeanRKey     string    `json:"bean_rkey"`
 	RecipeRKey   string    `json:"recipe_rkey"`
-	Method       string    `json:"method,omitempty"`
+	Method       string    `json:"method,omitempty"`       // a BrewMethod, or free text on older records
+	MethodOther  string    `json:"method_other,omitempty"` // the user's wording when Method is "other"
 	Temperature  float64   `json:"temperature"`
 	WaterAmount  int       `json:"water_amount"`
 	CoffeeAmount int       `json:"coffee_amount"`
@@ -260,12 +295,20 @@ type Brew struct {
 	BrewerRKey   string    `json:"brewer_rkey"`
 	TastingNotes string    `json:"tasting_notes"`
 	Rating       int       `json:"rating"`
+	TDS          float64   `json:"tds,omitempty"` // Total dissolved solids in percent (e.g. 1.38)
+	Tags         []string  `json:"tags,omitempty"`
+	Draft        bool      `json:"draft,omitempty"` // saved mid-brew; this site shows it only to the owner, but the record is public
 	CreatedAt    time.Time `json:"created_at"`
 
+	// Tasting holds optional structured scores and flavor wheel picks
+	Tasting *TastingProfile `json:"tasting,omitempty"`
+
 	// Method-specific parameters
 	EspressoParams *EspressoParams `json:"espresso_params,omitempty"`
 	PouroverParams *PouroverParams `json:"pourover_params,omitempty"`
 
+	Image *BrewImage `json:"image,omitempty"`
+
 	// Joined data for display
 	Bean       *Bean    `json:"bean,omitempty"`
 	RecipeObj  *Recipe  `json:"recipe_obj,omitempty"`
@@ -274,11 +317,58 @@ type Brew struct {
 	Pours      []*Pour  `json:"pours,omitempty"`
 }
 
+// BrewImage is a photo attached to a brew. The bytes live as a blob in the
+// author's repo; only the reference is stored on the record.
+type BrewImage struct {
+	CID      string `json:"cid"`
+	MimeType string `json:"mime_type"`
+	Size     int64  `json:"size"`
+}
+
+// Ratio returns the water-to-coffee ratio (e.g. 16 for 1:16). Water comes
+// from WaterAmount, or the sum of pours when no total was recorded. Returns
+// 0 when either side is missing.
+func (b *Brew) Ratio() float64 {
+	water := b.WaterAmount
+	if water <= 0 {
+		water = 0
+		for _, pour := range b.Pours {
+			water += pour.WaterAmount
+		}
+	}
+	if b.CoffeeAmount <= 0 || water <= 0 {
+		return 0
+	}
+	return float64(water) / float64(b.CoffeeAmount)
+}
+
+// ExtractionYield estimates the extraction yield in percent from the
+// measured TDS: TDS × beverage mass / dose. The beverage mass is the espresso
+// yield weight when recorded, otherwise the water total, which overstates it
+// slightly since the grounds retain some water. Returns 0 when TDS, dose or
+// beverage mass is missing.
+func (b *Brew) ExtractionYield() float64 {
+	if b.TDS <= 0 || b.CoffeeAmount <= 0 {
+		return 0
+	}
+	var beverage float64
+	if b.EspressoParams != nil && b.EspressoParams.YieldWeight > 0 {
+		beverage = b.EspressoParams.YieldWeight
+	} else {
+		beverage = b.Ratio() * float64(b.CoffeeAmount)
+	}
+	if beverage <= 0 {
+		return 0
+	}
+	return b.TDS * beverage / float64(b.CoffeeAmount)
+}
+
 type CreateBrewRequest struct {
 	BeanRKey       string           `json:"bean_rkey"`
 	RecipeRKey     string           `json:"recipe_rkey"`
 	RecipeOwnerDID string           `json:"recipe_owner_did"` // DID of the recipe owner (may differ from brew author)
 	Method         string           `json:"method"`
+	MethodOther    string           `json:"method_other,omitempty"`
 	Temperature    float64          `json:"temperature"`
 	WaterAmount    int              `json:"water_amount"`
 	CoffeeAmount   int              `json:"coffee_amount"`
@@ -288,9 +378,27 @@ type CreateBrewRequest struct {
 	BrewerRKey     string           `json:"brewer_rkey"`
 	TastingNotes   string           `json:"tasting_notes"`
 	Rating         int              `json:"rating"`
+	TDS            float64          `json:"tds,omitempty"`
+	Tags           []string         `json:"tags,omitempty"`
+	Draft          bool             `json:"draft,omitempty"`
+	Tasting        *TastingProfile  `json:"tasting,omitempty"`
 	Pours          []CreatePourData `json:"pours"`
 	EspressoParams *EspressoParams  `json:"espresso_params,omitempty"`
 	PouroverParams *PouroverParams  `json:"pourover_params,omitempty"`
+
+	// Image is a freshly uploaded photo. On update, nil keeps the existing
+	// photo unless RemoveImage is set.
+	Image       *BrewImage `json:"-"`
+	RemoveImage bool       `json:"-"`
+
+	// CreatedAt backdates the brew when importing history. Not accepted
+	// from clients; zero means now.
+	CreatedAt time.Time `json:"-"`
+
+	// SwapCID is only used on update: the CID of the brew the edit form
+	// was loaded with. The update is refused if the brew has changed
+	// since; "" overwrites unconditionally.
+	SwapCID string `json:"-"`
 }
 
 type CreatePourData struct {
@@ -312,6 +420,9 @@ type CreateBeanRequest struct {
 	Rating      *int   `json:"rating,omitempty"`
 	Closed      bool   `json:"closed"`
 	SourceRef   string `json:"source_ref,omitempty"`
+
+	BagSizeGrams   int  `json:"bag_size_grams,omitempty"`
+	RemainingGrams *int `json:"remaining_grams,omitempty"`
 }
 
 type CreateRoasterRequest struct {
@@ -322,12 +433,13 @@ type CreateRoasterRequest struct {
 }
 
 type CreateGrinderRequest struct {
-	Name        string `json:"name"`
-	GrinderType string `json:"grinder_type"`
-	BurrType    string `json:"burr_type"`
-	Notes       string `json:"notes"`
-	Link        string `json:"link"`
-	SourceRef   string `json:"source_ref,omitempty"`
+	Name          string        `json:"name"`
+	GrinderType   string        `json:"grinder_type"`
+	BurrType      string        `json:"burr_type"`
+	Notes         string        `json:"notes"`
+	Link          string        `json:"link"`
+	GrindSettings []GrindPreset `json:"grind_settings,omitempty"`
+	SourceRef     string        `json:"source_ref,omitempty"`
 }
 
 type CreateBrewerRequest struct {
@@ -357,6 +469,11 @@ type UpdateRecipeRequest struct {
 	WaterAmount  float64          `json:"water_amount"`
 	Notes        string           `json:"notes"`
 	Pours        []CreatePourData `json:"pours"`
+
+	// SwapCID is the CID of the record the edit form was loaded with. The
+	// update is refused if the record has changed since; "" overwrites
+	// unconditionally.
+	SwapCID string `json:"-"`
 }
 
 type UpdateBeanRequest struct {
@@ -373,6 +490,17 @@ type UpdateBeanRequest struct {
 	Rating      *int   `json:"rating,omitempty"`
 	Closed      bool   `json:"closed"`
 	SourceRef   string `json:"source_ref,omitempty"`
+
+	// A nil RemainingGrams keeps the bean's current inventory;
+	// ClearRemainingGrams stops tracking it.
+	BagSizeGrams        int  `json:"bag_size_grams,omitempty"`
+	RemainingGrams      *int `json:"remaining_grams,omitempty"`
+	ClearRemainingGrams bool `json:"clear_remaining_grams,omitempty"`
+
+	// SwapCID is the CID of the record the edit form was loaded with. The
+	// update is refused if the record has changed since; "" overwrites
+	// unconditionally.
+	SwapCID string `json:"-"`
 }
 
 type UpdateRoasterRequest struct {
@@ -383,12 +511,13 @@ type UpdateRoasterRequest struct {
 }
 
 type UpdateGrinderRequest struct {
-	Name        string `json:"name"`
-	GrinderType string `json:"grinder_type"`
-	BurrType    string `json:"burr_type"`
-	Notes       string `json:"notes"`
-	Link        string `json:"link"`
-	SourceRef   string `json:"source_ref,omitempty"`
+	Name          string        `json:"name"`
+	GrinderType   string        `json:"grinder_type"`
+	BurrType      string        `json:"burr_type"`
+	Notes         string        `json:"notes"`
+	Link          string        `json:"link"`
+	GrindSettings []GrindPreset `json:"grind_settings,omitempty"`
+	SourceRef     string        `json:"source_ref,omitempty"`
 }
 
 type UpdateBrewerRequest struct {
@@ -399,6 +528,19 @@ type UpdateBrewerRequest struct {
 	SourceRef   string `json:"source_ref,omitempty"`
 }
 
+// IsLowStock reports whether inventory is tracked and has dropped below
+// LowStockGrams.
+func (b *Bean) IsLowStock() bool {
+	return b.RemainingGrams != nil && *b.RemainingGrams < LowStockGrams
+}
+
+func validInventory(bagSize int, remaining *int) bool {
+	if bagSize < 0 || bagSize > MaxBagSizeGrams {
+		return false
+	}
+	return remaining == nil || (*remaining >= 0 && *remaining <= MaxBagSizeGrams)
+}
+
 // IsIncomplete returns true if the bean is missing key fields beyond name/origin.
 func (b *Bean) IsIncomplete() bool {
 	return b.RoasterRKey == "" || b.RoastLevel == ""
@@ -493,6 +635,9 @@ func (r *CreateBeanRequest) Validate() error {
 	if r.Rating != nil && (*r.Rating < 1 || *r.Rating > 10) {
 		return ErrRatingOutOfRange
 	}
+	if !validInventory(r.BagSizeGrams, r.RemainingGrams) {
+		return ErrInvalidInventory
+	}
 	return nil
 }
 
@@ -533,6 +678,9 @@ func (r *UpdateBeanRequest) Validate() error {
 	if r.Rating != nil && (*r.Rating < 1 || *r.Rating > 10) {
 		return ErrRatingOutOfRange
 	}
+	if !validInventory(r.BagSizeGrams, r.RemainingGrams) {
+		return ErrInvalidInventory
+	}
 	return nil
 }
 
@@ -590,7 +738,7 @@ func (r *CreateGrinderRequest) Validate() error {
 	if len(r.Link) > MaxLinkLength {
 		return ErrLinkTooLong
 	}
-	return nil
+	return validateGrindPresets(r.GrindSettings)
 }
 
 // Validate checks that all fields are within acceptable limits
@@ -613,7 +761,7 @@ func (r *UpdateGrinderRequest) Validate() error {
 	if len(r.Link) > MaxLinkLength {
 		return ErrLinkTooLong
 	}
-	return nil
+	return validateGrindPresets(r.GrindSettings)
 }
 
 // Validate checks that all fields are within acceptable limits
@@ -672,7 +820,7 @@ func (r *UpdateRecipeRequest) Validate() error {
 
 // Validate checks that all string fields are within acceptable limits
 func (r *CreateBrewRequest) Validate() error {
-	if len(r.Method) > MaxMethodLength {
+	if len(r.Method) > MaxMethodLength || len(r.MethodOther) > MaxMethodLength {
 		return ErrFieldTooLong
 	}
 	if len(r.GrindSize) > MaxGrindSizeLength {
@@ -681,7 +829,15 @@ func (r *CreateBrewRequest) Validate() error {
 	if len(r.TastingNotes) > MaxTastingNotesLength {
 		return ErrFieldTooLong
 	}
-	return nil
+	if len(r.Tags) > MaxBrewTags {
+		return ErrTooManyTags
+	}
+	for _, tag := range r.Tags {
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return ErrTagTooLong
+		}
+	}
+	return r.Tasting.Validate()
 }
 
 // Validate checks that all fields are within acceptable limits
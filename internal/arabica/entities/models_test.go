@@ -65,6 +65,12 @@ func TestCreateBeanRequest_Validate(t *testing.T) {
 		assert.ErrorIs(t, req.Validate(), ErrDescTooLong)
 	})
 
+	t.Run("inventory out of range", func(t *testing.T) {
+		negative := -5
+		assert.ErrorIs(t, (&CreateBeanRequest{Name: "Bean", RemainingGrams: &negative}).Validate(), ErrInvalidInventory)
+		assert.ErrorIs(t, (&CreateBeanRequest{Name: "Bean", BagSizeGrams: MaxBagSizeGrams + 1}).Validate(), ErrInvalidInventory)
+	})
+
 	t.Run("all optional fields populated", func(t *testing.T) {
 		req := &CreateBeanRequest{
 			Name:        "Ethiopian Yirgacheffe",
@@ -376,6 +382,15 @@ func TestBeanIsIncomplete(t *testing.T) {
 	assert.Len(t, stub.MissingFields(), 2)
 }
 
+func TestBeanIsLowStock(t *testing.T) {
+	grams := func(g int) *int { return &g }
+
+	assert.False(t, (&Bean{}).IsLowStock(), "untracked inventory is never low")
+	assert.False(t, (&Bean{RemainingGrams: grams(LowStockGrams)}).IsLowStock())
+	assert.True(t, (&Bean{RemainingGrams: grams(LowStockGrams - 1)}).IsLowStock())
+	assert.True(t, (&Bean{RemainingGrams: grams(0)}).IsLowStock())
+}
+
 func TestGrinderIsIncomplete(t *testing.T) {
 	complete := &Grinder{Name: "Test", GrinderType: "Hand"}
 	assert.False(t, complete.IsIncomplete())
@@ -428,3 +443,43 @@ func TestNormalizeBrewerType(t *testing.T) {
 		})
 	}
 }
+
+func TestBrewRatio(t *testing.T) {
+	tests := []struct {
+		name     string
+		brew     Brew
+		expected float64
+	}{
+		{"water and coffee", Brew{CoffeeAmount: 15, WaterAmount: 250}, 250.0 / 15.0},
+		{"falls back to pours", Brew{CoffeeAmount: 20, Pours: []*Pour{{WaterAmount: 100}, {WaterAmount: 220}}}, 16},
+		{"no coffee", Brew{WaterAmount: 250}, 0},
+		{"no water", Brew{CoffeeAmount: 18}, 0},
+		{"empty brew", Brew{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, tt.brew.Ratio(), 1e-9)
+		})
+	}
+}
+
+func TestBrewExtractionYield(t *testing.T) {
+	tests := []struct {
+		name     string
+		brew     Brew
+		expected float64
+	}{
+		{"filter uses water total", Brew{TDS: 1.35, CoffeeAmount: 15, WaterAmount: 250}, 22.5},
+		{"espresso uses yield weight", Brew{TDS: 9, CoffeeAmount: 18, WaterAmount: 60, EspressoParams: &EspressoParams{YieldWeight: 36}}, 18},
+		{"no tds", Brew{CoffeeAmount: 15, WaterAmount: 250}, 0},
+		{"no dose", Brew{TDS: 1.35, WaterAmount: 250}, 0},
+		{"no beverage mass", Brew{TDS: 1.35, CoffeeAmount: 15}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expected, tt.brew.ExtractionYield(), 1e-9)
+		})
+	}
+}
@@ -12,12 +12,17 @@ const (
 	NSIDBase = "social.arabica.alpha"
 
 	// Collection NSIDs.
-	NSIDBean    = NSIDBase + ".bean"
-	NSIDBrew    = NSIDBase + ".brew"
-	NSIDBrewer  = NSIDBase + ".brewer"
-	NSIDComment = NSIDBase + ".comment"
-	NSIDGrinder = NSIDBase + ".grinder"
-	NSIDLike    = NSIDBase + ".like"
-	NSIDRecipe  = NSIDBase + ".recipe"
-	NSIDRoaster = NSIDBase + ".roaster"
+	NSIDBean     = NSIDBase + ".bean"
+	NSIDBookmark = NSIDBase + ".bookmark"
+	NSIDBrew     = NSIDBase + ".brew"
+	NSIDBrewer   = NSIDBase + ".brewer"
+	NSIDComment  = NSIDBase + ".comment"
+	NSIDFollow   = NSIDBase + ".follow"
+	NSIDGrinder  = NSIDBase + ".grinder"
+	NSIDLike     = NSIDBase + ".like"
+	NSIDRecipe   = NSIDBase + ".recipe"
+	NSIDRoaster  = NSIDBase + ".roaster"
+
+	// NSIDPreferences is a singleton per repo at rkey PreferencesRKey.
+	NSIDPreferences = NSIDBase + ".preferences"
 )
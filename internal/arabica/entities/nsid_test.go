@@ -25,6 +25,7 @@ func TestNSIDConstants(t *testing.T) {
 		{"NSIDBrewer", arabica.NSIDBrewer, "social.arabica.alpha.brewer"},
 		{"NSIDGrinder", arabica.NSIDGrinder, "social.arabica.alpha.grinder"},
 		{"NSIDRoaster", arabica.NSIDRoaster, "social.arabica.alpha.roaster"},
+		{"NSIDPreferences", arabica.NSIDPreferences, "social.arabica.alpha.preferences"},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,64 @@
+package arabica
+
+import "slices"
+
+// PourStep is one pour on a brew's timeline. TimeSeconds is when the pour
+// starts, measured from the start of the brew.
+type PourStep struct {
+	Number          int
+	WaterAmount     int
+	TimeSeconds     int
+	CumulativeWater int // water in the brewer once this pour is done
+	IntervalSeconds int // time since the previous pour, or since the start for the first
+}
+
+// PourSchedule lays a brew's pours out in time order with running totals,
+// which is what a timeline or bar chart of the pours needs.
+type PourSchedule struct {
+	Steps       []PourStep
+	TotalWater  int
+	LastPourSec int
+}
+
+// NewPourSchedule builds the schedule for pours, ordered by pour time.
+// Pours sharing a time keep their recorded order. Returns nil when there
+// are no pours.
+func NewPourSchedule(pours []*Pour) *PourSchedule {
+	ordered := make([]*Pour, 0, len(pours))
+	for _, p := range pours {
+		if p != nil {
+			ordered = append(ordered, p)
+		}
+	}
+	if len(ordered) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(ordered, func(a, b *Pour) int {
+		return a.TimeSeconds - b.TimeSeconds
+	})
+
+	s := &PourSchedule{Steps: make([]PourStep, 0, len(ordered))}
+	prev := 0
+	for i, p := range ordered {
+		s.TotalWater += p.WaterAmount
+		s.Steps = append(s.Steps, PourStep{
+			Number:          i + 1,
+			WaterAmount:     p.WaterAmount,
+			TimeSeconds:     p.TimeSeconds,
+			CumulativeWater: s.TotalWater,
+			IntervalSeconds: p.TimeSeconds - prev,
+		})
+		prev = p.TimeSeconds
+	}
+	s.LastPourSec = prev
+	return s
+}
+
+// CumulativePercent is how full the brewer is after step i, as a share of
+// the schedule's total water from 0 to 100.
+func (s *PourSchedule) CumulativePercent(i int) int {
+	if s.TotalWater <= 0 {
+		return 0
+	}
+	return s.Steps[i].CumulativeWater * 100 / s.TotalWater
+}
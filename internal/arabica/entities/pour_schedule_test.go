@@ -0,0 +1,60 @@
+package arabica
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPourSchedule(t *testing.T) {
+	tests := []struct {
+		name  string
+		pours []*Pour
+		want  *PourSchedule
+	}{
+		{"no pours", nil, nil},
+		{"only nil pours", []*Pour{nil}, nil},
+		{
+			name: "sorted by time with running totals",
+			pours: []*Pour{
+				{WaterAmount: 100, TimeSeconds: 45},
+				{WaterAmount: 50, TimeSeconds: 0},
+				{WaterAmount: 100, TimeSeconds: 90},
+			},
+			want: &PourSchedule{
+				Steps: []PourStep{
+					{Number: 1, WaterAmount: 50, TimeSeconds: 0, CumulativeWater: 50, IntervalSeconds: 0},
+					{Number: 2, WaterAmount: 100, TimeSeconds: 45, CumulativeWater: 150, IntervalSeconds: 45},
+					{Number: 3, WaterAmount: 100, TimeSeconds: 90, CumulativeWater: 250, IntervalSeconds: 45},
+				},
+				TotalWater:  250,
+				LastPourSec: 90,
+			},
+		},
+		{
+			name:  "first pour after the start",
+			pours: []*Pour{{WaterAmount: 60, TimeSeconds: 10}},
+			want: &PourSchedule{
+				Steps:       []PourStep{{Number: 1, WaterAmount: 60, TimeSeconds: 10, CumulativeWater: 60, IntervalSeconds: 10}},
+				TotalWater:  60,
+				LastPourSec: 10,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewPourSchedule(tt.pours))
+		})
+	}
+}
+
+func TestPourScheduleCumulativePercent(t *testing.T) {
+	s := NewPourSchedule([]*Pour{{WaterAmount: 50, TimeSeconds: 0}, {WaterAmount: 150, TimeSeconds: 30}})
+	require.NotNil(t, s)
+	assert.Equal(t, 25, s.CumulativePercent(0))
+	assert.Equal(t, 100, s.CumulativePercent(1))
+
+	empty := NewPourSchedule([]*Pour{{TimeSeconds: 0}})
+	assert.Equal(t, 0, empty.CumulativePercent(0))
+}
@@ -0,0 +1,121 @@
+package arabica
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"tangled.org/pdewey.com/atp"
+)
+
+// PreferencesRKey is the fixed record key of the preferences singleton.
+const PreferencesRKey = "self"
+
+var ErrInvalidPreferences = errors.New("dose, ratio and temperature must be within range")
+
+// BrewPreferences holds a user's defaults for new brews. Every field is
+// optional; the zero value means "no default".
+type BrewPreferences struct {
+	Method       string    `json:"method,omitempty"`
+	CoffeeAmount int       `json:"coffee_amount,omitempty"` // grams
+	Ratio        float64   `json:"ratio,omitempty"`         // grams of water per gram of coffee, 16 for 1:16
+	Temperature  float64   `json:"temperature,omitempty"`   // °C
+	GrinderRKey  string    `json:"grinder_rkey,omitempty"`
+	BrewerRKey   string    `json:"brewer_rkey,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Validate checks that all fields are within acceptable limits
+func (p *BrewPreferences) Validate() error {
+	if len(p.Method) > MaxMethodLength {
+		return ErrFieldTooLong
+	}
+	if p.CoffeeAmount < 0 || p.CoffeeAmount > 1000 ||
+		p.Ratio < 0 || p.Ratio > 100 ||
+		p.Temperature < 0 || p.Temperature > 100 {
+		return ErrInvalidPreferences
+	}
+	return nil
+}
+
+// DraftBrew returns an unsaved brew carrying the defaults, for pre-filling
+// the new-brew form. The default method is free text, so it is normalized
+// the same way a submitted brew's is. Water is derived from dose and ratio
+// when both are set.
+func (p *BrewPreferences) DraftBrew() *Brew {
+	method, other := NormalizeBrewMethod(p.Method)
+	brew := &Brew{
+		Method:       string(method),
+		MethodOther:  other,
+		CoffeeAmount: p.CoffeeAmount,
+		Temperature:  p.Temperature,
+		GrinderRKey:  p.GrinderRKey,
+		BrewerRKey:   p.BrewerRKey,
+	}
+	if p.CoffeeAmount > 0 && p.Ratio > 0 {
+		brew.WaterAmount = int(math.Round(float64(p.CoffeeAmount) * p.Ratio))
+	}
+	return brew
+}
+
+// PreferencesToRecord converts preferences to an ATProto record.
+func PreferencesToRecord(p *BrewPreferences, grinderURI, brewerURI string) (map[string]any, error) {
+	record := map[string]any{
+		"$type":     NSIDPreferences,
+		"updatedAt": p.UpdatedAt.Format(time.RFC3339),
+	}
+	if p.Method != "" {
+		record["method"] = p.Method
+	}
+	if p.CoffeeAmount > 0 {
+		record["coffeeAmount"] = p.CoffeeAmount
+	}
+	if p.Ratio > 0 {
+		// Stored in tenths like temperature (16.5 -> 165)
+		record["ratio"] = int(math.Round(p.Ratio * 10))
+	}
+	if p.Temperature > 0 {
+		record["temperature"] = int(math.Round(p.Temperature * 10))
+	}
+	if grinderURI != "" {
+		record["grinderRef"] = grinderURI
+	}
+	if brewerURI != "" {
+		record["brewerRef"] = brewerURI
+	}
+	return record, nil
+}
+
+// RecordToPreferences converts an ATProto record to preferences. Grinder
+// and brewer refs are reduced to their rkeys; they always point into the
+// owner's own repo.
+func RecordToPreferences(record map[string]any) (*BrewPreferences, error) {
+	p := &BrewPreferences{}
+	if updatedAt, ok := record["updatedAt"].(string); ok {
+		t, err := time.Parse(time.RFC3339, updatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid updatedAt format: %w", err)
+		}
+		p.UpdatedAt = t
+	}
+	if method, ok := record["method"].(string); ok {
+		p.Method = method
+	}
+	if v, ok := toFloat64(record["coffeeAmount"]); ok {
+		p.CoffeeAmount = int(v)
+	}
+	if v, ok := toFloat64(record["ratio"]); ok {
+		p.Ratio = v / 10.0
+	}
+	if v, ok := toFloat64(record["temperature"]); ok {
+		p.Temperature = v / 10.0
+	}
+	if ref, ok := record["grinderRef"].(string); ok {
+		p.GrinderRKey = atp.RKeyFromURI(ref)
+	}
+	if ref, ok := record["brewerRef"].(string); ok {
+		p.BrewerRKey = atp.RKeyFromURI(ref)
+	}
+	return p, nil
+}
@@ -0,0 +1,75 @@
+package arabica
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreferencesRoundTrip(t *testing.T) {
+	prefs := &BrewPreferences{
+		Method:       "V60",
+		CoffeeAmount: 15,
+		Ratio:        16.5,
+		Temperature:  93.5,
+		GrinderRKey:  "grinder1",
+		BrewerRKey:   "brewer1",
+		UpdatedAt:    time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC),
+	}
+	record, err := PreferencesToRecord(prefs,
+		"at://did:plc:test/social.arabica.alpha.grinder/grinder1",
+		"at://did:plc:test/social.arabica.alpha.brewer/brewer1")
+	require.NoError(t, err)
+	assert.Equal(t, 165, record["ratio"])
+	assert.Equal(t, 935, record["temperature"])
+
+	got, err := RecordToPreferences(record)
+	require.NoError(t, err)
+	assert.Equal(t, prefs, got)
+}
+
+func TestBrewPreferences_DraftBrew(t *testing.T) {
+	tests := []struct {
+		name            string
+		prefs           BrewPreferences
+		wantWater       int
+		wantMethod      BrewMethod
+		wantMethodOther string
+	}{
+		{"water from dose and ratio", BrewPreferences{CoffeeAmount: 15, Ratio: 16.5}, 248, "", ""},
+		{"no ratio", BrewPreferences{CoffeeAmount: 15}, 0, "", ""},
+		{"no dose", BrewPreferences{Ratio: 16}, 0, "", ""},
+		{"known method normalized", BrewPreferences{Method: "Hario V60"}, 0, BrewMethodV60, ""},
+		{"unknown method kept as other", BrewPreferences{Method: "Phin"}, 0, BrewMethodOther, "Phin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			brew := tt.prefs.DraftBrew()
+			assert.Empty(t, brew.RKey)
+			assert.Equal(t, tt.prefs.CoffeeAmount, brew.CoffeeAmount)
+			assert.Equal(t, tt.wantWater, brew.WaterAmount)
+			assert.Equal(t, string(tt.wantMethod), brew.Method)
+			assert.Equal(t, tt.wantMethodOther, brew.MethodOther)
+		})
+	}
+}
+
+func TestBrewPreferences_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefs   BrewPreferences
+		wantErr error
+	}{
+		{"empty", BrewPreferences{}, nil},
+		{"typical", BrewPreferences{Method: "V60", CoffeeAmount: 18, Ratio: 2, Temperature: 94}, nil},
+		{"temperature above boiling", BrewPreferences{Temperature: 120}, ErrInvalidPreferences},
+		{"negative dose", BrewPreferences{CoffeeAmount: -1}, ErrInvalidPreferences},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.prefs.Validate(), tt.wantErr)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package arabica
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"tangled.org/arabica.social/arabica/internal/social"
@@ -17,6 +18,8 @@ func toFloat64(v any) (float64, bool) {
 		return n, true
 	case int:
 		return float64(n), true
+	case int64:
+		return float64(n), true
 	default:
 		return 0, false
 	}
@@ -151,6 +154,9 @@ func BrewToRecord(brew *Brew, beanURI, grinderURI, brewerURI, recipeURI string)
 	if brew.Method != "" {
 		record["method"] = brew.Method
 	}
+	if brew.MethodOther != "" {
+		record["methodOther"] = brew.MethodOther
+	}
 	if brew.Temperature > 0 {
 		// Convert float to tenths (93.5 -> 935)
 		record["temperature"] = int(brew.Temperature * 10)
@@ -182,6 +188,27 @@ func BrewToRecord(brew *Brew, beanURI, grinderURI, brewerURI, recipeURI string)
 	if brew.Rating > 0 {
 		record["rating"] = brew.Rating
 	}
+	if brew.TDS > 0 {
+		// Stored in hundredths of a percent; round so 1.15 doesn't become 114
+		record["tds"] = int(math.Round(brew.TDS * 100))
+	}
+	if len(brew.Tags) > 0 {
+		record["tags"] = brew.Tags
+	}
+	if brew.Draft {
+		record["draft"] = true
+	}
+	if tasting := tastingToRecord(brew.Tasting); tasting != nil {
+		record["tasting"] = tasting
+	}
+	if brew.Image != nil && brew.Image.CID != "" {
+		record["image"] = map[string]any{
+			"$type":    "blob",
+			"ref":      map[string]any{"$link": brew.Image.CID},
+			"mimeType": brew.Image.MimeType,
+			"size":     brew.Image.Size,
+		}
+	}
 
 	// Convert pours to embedded array
 	if len(brew.Pours) > 0 {
@@ -275,6 +302,9 @@ func RecordToBrew(record map[string]any, atURI string) (*Brew, error) {
 	if method, ok := record["method"].(string); ok {
 		brew.Method = method
 	}
+	if other, ok := record["methodOther"].(string); ok {
+		brew.MethodOther = other
+	}
 	if temp, ok := record["temperature"].(float64); ok {
 		// Convert from tenths to float (935 -> 93.5)
 		brew.Temperature = temp / 10.0
@@ -297,6 +327,23 @@ func RecordToBrew(record map[string]any, atURI string) (*Brew, error) {
 	if rating, ok := record["rating"].(float64); ok {
 		brew.Rating = int(rating)
 	}
+	if tds, ok := toFloat64(record["tds"]); ok {
+		brew.TDS = tds / 100
+	}
+	if tagsRaw, ok := record["tags"].([]any); ok {
+		for _, t := range tagsRaw {
+			if tag, ok := t.(string); ok && tag != "" {
+				brew.Tags = append(brew.Tags, tag)
+			}
+		}
+	}
+	brew.Draft, _ = record["draft"].(bool)
+	if tasting, ok := record["tasting"].(map[string]any); ok {
+		brew.Tasting = tastingFromRecord(tasting)
+	}
+	if image, ok := record["image"].(map[string]any); ok {
+		brew.Image = BrewImageFromBlob(image)
+	}
 
 	// Convert pours from embedded array
 	if poursRaw, ok := record["pours"].([]any); ok {
@@ -403,6 +450,12 @@ func BeanToRecord(bean *Bean, roasterURI string) (map[string]any, error) {
 	if bean.SourceRef != "" {
 		record["sourceRef"] = bean.SourceRef
 	}
+	if bean.BagSizeGrams > 0 {
+		record["bagSizeGrams"] = bean.BagSizeGrams
+	}
+	if bean.RemainingGrams != nil {
+		record["remainingGrams"] = *bean.RemainingGrams
+	}
 
 	return record, nil
 }
@@ -473,6 +526,13 @@ func RecordToBean(record map[string]any, atURI string) (*Bean, error) {
 	if sourceRef, ok := record["sourceRef"].(string); ok {
 		bean.SourceRef = sourceRef
 	}
+	if bagSize, ok := toFloat64(record["bagSizeGrams"]); ok {
+		bean.BagSizeGrams = int(bagSize)
+	}
+	if remaining, ok := toFloat64(record["remainingGrams"]); ok {
+		g := int(remaining)
+		bean.RemainingGrams = &g
+	}
 
 	return bean, nil
 }
@@ -607,6 +667,16 @@ func GrinderToRecord(grinder *Grinder) (map[string]any, error) {
 	if grinder.Link != "" {
 		record["link"] = grinder.Link
 	}
+	if len(grinder.GrindSettings) > 0 {
+		presets := make([]map[string]any, len(grinder.GrindSettings))
+		for i, p := range grinder.GrindSettings {
+			presets[i] = map[string]any{
+				"name":    p.Name,
+				"setting": p.Setting,
+			}
+		}
+		record["grindSettings"] = presets
+	}
 	if grinder.SourceRef != "" {
 		record["sourceRef"] = grinder.SourceRef
 	}
@@ -665,6 +735,20 @@ func RecordToGrinder(record map[string]any, atURI string) (*Grinder, error) {
 	if link, ok := record["link"].(string); ok {
 		grinder.Link = link
 	}
+	if presetsRaw, ok := record["grindSettings"].([]any); ok {
+		for _, presetRaw := range presetsRaw {
+			presetMap, ok := presetRaw.(map[string]any)
+			if !ok {
+				continue
+			}
+			name, _ := presetMap["name"].(string)
+			setting, _ := presetMap["setting"].(string)
+			if name == "" || setting == "" {
+				continue
+			}
+			grinder.GrindSettings = append(grinder.GrindSettings, GrindPreset{Name: name, Setting: setting})
+		}
+	}
 	if sourceRef, ok := record["sourceRef"].(string); ok {
 		grinder.SourceRef = sourceRef
 	}
@@ -770,3 +854,27 @@ func CommentToRecord(comment *Comment) (map[string]any, error) {
 func RecordToComment(record map[string]any, atURI string) (*Comment, error) {
 	return social.RecordToComment(record, atURI)
 }
+
+// BrewImageFromBlob reads a blob ref in its record form:
+//
+//	{"$type": "blob", "ref": {"$link": "bafy..."}, "mimeType": "image/jpeg", "size": 1234}
+//
+// Returns nil when no CID can be found.
+func BrewImageFromBlob(blob map[string]any) *BrewImage {
+	var cid string
+	switch ref := blob["ref"].(type) {
+	case map[string]any:
+		cid, _ = ref["$link"].(string)
+	case string:
+		cid = ref
+	}
+	if cid == "" {
+		return nil
+	}
+	image := &BrewImage{CID: cid}
+	image.MimeType, _ = blob["mimeType"].(string)
+	if size, ok := toFloat64(blob["size"]); ok {
+		image.Size = int64(size)
+	}
+	return image
+}
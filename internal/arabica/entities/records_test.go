@@ -461,3 +461,146 @@ func TestBrewRoundTrip_PouroverParams(t *testing.T) {
 	require.NoError(t, err)
 	shutter.Snap(t, "RecordToBrew/pourover params", restored)
 }
+
+func TestBrewRoundTrip_TDS(t *testing.T) {
+	tests := []struct {
+		name    string
+		tds     float64
+		wantRaw any
+	}{
+		{"measured", 1.38, 138},
+		{"rounds to hundredths", 1.15, 115},
+		{"not measured", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := &Brew{
+				BeanRKey:  "abc123",
+				TDS:       tt.tds,
+				CreatedAt: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC),
+			}
+
+			record, err := BrewToRecord(original, "at://did:plc:test/social.arabica.alpha.bean/abc123", "", "", "")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRaw, record["tds"])
+
+			restored, err := RecordToBrew(record, "at://did:plc:test/social.arabica.alpha.brew/tid123")
+			require.NoError(t, err)
+			assert.InDelta(t, tt.tds, restored.TDS, 1e-9)
+		})
+	}
+}
+
+func TestBrewRoundTrip_Draft(t *testing.T) {
+	tests := []struct {
+		name    string
+		draft   bool
+		wantRaw any
+	}{
+		{"draft", true, true},
+		{"published omits the field", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := &Brew{
+				BeanRKey:  "abc123",
+				Draft:     tt.draft,
+				CreatedAt: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC),
+			}
+
+			record, err := BrewToRecord(original, "at://did:plc:test/social.arabica.alpha.bean/abc123", "", "", "")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRaw, record["draft"])
+
+			restored, err := RecordToBrew(record, "at://did:plc:test/social.arabica.alpha.brew/tid123")
+			require.NoError(t, err)
+			assert.Equal(t, tt.draft, restored.Draft)
+		})
+	}
+}
+
+func TestBrewRoundTrip_Image(t *testing.T) {
+	original := &Brew{
+		BeanRKey:  "abc123",
+		Image:     &BrewImage{CID: "bafkreiabc123", MimeType: "image/webp", Size: 48213},
+		CreatedAt: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC),
+	}
+
+	record, err := BrewToRecord(original, "at://did:plc:test/social.arabica.alpha.bean/abc123", "", "", "")
+	require.NoError(t, err)
+	image, ok := record["image"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "blob", image["$type"])
+
+	restored, err := RecordToBrew(record, "at://did:plc:test/social.arabica.alpha.brew/tid123")
+	require.NoError(t, err)
+	assert.Equal(t, original.Image, restored.Image)
+}
+
+func TestBrewImageFromBlob(t *testing.T) {
+	tests := []struct {
+		name string
+		blob map[string]any
+		want *BrewImage
+	}{
+		{
+			name: "wire format",
+			blob: map[string]any{"$type": "blob", "ref": map[string]any{"$link": "bafkreiabc"}, "mimeType": "image/png", "size": float64(1200)},
+			want: &BrewImage{CID: "bafkreiabc", MimeType: "image/png", Size: 1200},
+		},
+		{
+			name: "flattened ref",
+			blob: map[string]any{"ref": "bafkreiabc", "mimeType": "image/jpeg"},
+			want: &BrewImage{CID: "bafkreiabc", MimeType: "image/jpeg"},
+		},
+		{
+			name: "missing ref",
+			blob: map[string]any{"mimeType": "image/jpeg"},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, BrewImageFromBlob(tt.blob))
+		})
+	}
+}
+
+func TestBeanRoundTrip_Inventory(t *testing.T) {
+	remaining := 180
+	tests := []struct {
+		name string
+		bean *Bean
+		want map[string]any
+	}{
+		{
+			name: "tracked",
+			bean: &Bean{Name: "Kochere", BagSizeGrams: 250, RemainingGrams: &remaining},
+			want: map[string]any{"bagSizeGrams": 250, "remainingGrams": 180},
+		},
+		{
+			name: "untracked",
+			bean: &Bean{Name: "Kochere"},
+			want: map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.bean.CreatedAt = time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+			record, err := BeanToRecord(tt.bean, "")
+			require.NoError(t, err)
+			for _, key := range []string{"bagSizeGrams", "remainingGrams"} {
+				assert.Equal(t, tt.want[key], record[key], key)
+			}
+
+			restored, err := RecordToBean(record, "at://did:plc:test/social.arabica.alpha.bean/bean123")
+			require.NoError(t, err)
+			assert.Equal(t, tt.bean.BagSizeGrams, restored.BagSizeGrams)
+			assert.Equal(t, tt.bean.RemainingGrams, restored.RemainingGrams)
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package arabica
+
+import (
+	"slices"
+	"strings"
+)
+
+// Tasting scores run from MinTastingScore to MaxTastingScore. Zero means the
+// attribute wasn't scored.
+const (
+	MinTastingScore = 1
+	MaxTastingScore = 10
+)
+
+// TastingProfile is the structured half of a brew's tasting notes: a few
+// scored attributes plus flavors picked from FlavorWheel. It sits alongside
+// Brew.TastingNotes, which stays free text.
+type TastingProfile struct {
+	Acidity    int      `json:"acidity,omitempty"`
+	Body       int      `json:"body,omitempty"`
+	Sweetness  int      `json:"sweetness,omitempty"`
+	Bitterness int      `json:"bitterness,omitempty"`
+	Flavors    []string `json:"flavors,omitempty"`
+}
+
+// TastingAttribute names one scored axis of a TastingProfile. ID is the form
+// field and record key.
+type TastingAttribute struct {
+	ID    string
+	Label string
+}
+
+// TastingAttributes lists the scored axes in display order.
+var TastingAttributes = []TastingAttribute{
+	{ID: "acidity", Label: "Acidity"},
+	{ID: "body", Label: "Body"},
+	{ID: "sweetness", Label: "Sweetness"},
+	{ID: "bitterness", Label: "Bitterness"},
+}
+
+// Score returns the score recorded for the attribute with the given ID, or 0.
+func (p *TastingProfile) Score(id string) int {
+	if p == nil {
+		return 0
+	}
+	switch id {
+	case "acidity":
+		return p.Acidity
+	case "body":
+		return p.Body
+	case "sweetness":
+		return p.Sweetness
+	case "bitterness":
+		return p.Bitterness
+	}
+	return 0
+}
+
+// SetScore records a score for the attribute with the given ID. Unknown IDs
+// are ignored.
+func (p *TastingProfile) SetScore(id string, score int) {
+	switch id {
+	case "acidity":
+		p.Acidity = score
+	case "body":
+		p.Body = score
+	case "sweetness":
+		p.Sweetness = score
+	case "bitterness":
+		p.Bitterness = score
+	}
+}
+
+// IsEmpty reports whether nothing was scored or picked.
+func (p *TastingProfile) IsEmpty() bool {
+	if p == nil {
+		return true
+	}
+	for _, attr := range TastingAttributes {
+		if p.Score(attr.ID) != 0 {
+			return false
+		}
+	}
+	return len(p.Flavors) == 0
+}
+
+// Validate checks scores are in range and every flavor is on the wheel.
+func (p *TastingProfile) Validate() error {
+	if p == nil {
+		return nil
+	}
+	for _, attr := range TastingAttributes {
+		if score := p.Score(attr.ID); score != 0 && (score < MinTastingScore || score > MaxTastingScore) {
+			return ErrTastingScoreOutOfRange
+		}
+	}
+	for _, flavor := range p.Flavors {
+		if FlavorLabel(flavor) == "" {
+			return ErrUnknownFlavor
+		}
+	}
+	return nil
+}
+
+// Flavor is one selectable note on the flavor wheel. ID is what gets stored
+// on the record.
+type Flavor struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// FlavorCategory groups flavors under one slice of the wheel.
+type FlavorCategory struct {
+	Name    string
+	Flavors []Flavor
+}
+
+// FlavorWheel follows the inner two rings of the SCA and World Coffee
+// Research Coffee Taster's Flavor Wheel. The outer ring is left to the
+// free-text notes.
+var FlavorWheel = []FlavorCategory{
+	{Name: "Fruity", Flavors: []Flavor{
+		{ID: "berry", Label: "Berry"},
+		{ID: "dried-fruit", Label: "Dried fruit"},
+		{ID: "other-fruit", Label: "Other fruit"},
+		{ID: "citrus-fruit", Label: "Citrus fruit"},
+	}},
+	{Name: "Sour/Fermented", Flavors: []Flavor{
+		{ID: "sour", Label: "Sour"},
+		{ID: "fermented", Label: "Alcohol/Fermented"},
+	}},
+	{Name: "Green/Vegetative", Flavors: []Flavor{
+		{ID: "olive-oil", Label: "Olive oil"},
+		{ID: "raw", Label: "Raw"},
+		{ID: "vegetative", Label: "Green/Vegetative"},
+		{ID: "beany", Label: "Beany"},
+	}},
+	{Name: "Other", Flavors: []Flavor{
+		{ID: "papery", Label: "Papery/Musty"},
+		{ID: "chemical", Label: "Chemical"},
+	}},
+	{Name: "Roasted", Flavors: []Flavor{
+		{ID: "pipe-tobacco", Label: "Pipe tobacco"},
+		{ID: "tobacco", Label: "Tobacco"},
+		{ID: "burnt", Label: "Burnt"},
+		{ID: "cereal", Label: "Cereal"},
+	}},
+	{Name: "Spices", Flavors: []Flavor{
+		{ID: "pungent", Label: "Pungent"},
+		{ID: "pepper", Label: "Pepper"},
+		{ID: "brown-spice", Label: "Brown spice"},
+	}},
+	{Name: "Nutty/Cocoa", Flavors: []Flavor{
+		{ID: "nutty", Label: "Nutty"},
+		{ID: "cocoa", Label: "Cocoa"},
+	}},
+	{Name: "Sweet", Flavors: []Flavor{
+		{ID: "brown-sugar", Label: "Brown sugar"},
+		{ID: "vanilla", Label: "Vanilla"},
+		{ID: "vanillin", Label: "Vanillin"},
+		{ID: "overall-sweet", Label: "Overall sweet"},
+		{ID: "sweet-aromatics", Label: "Sweet aromatics"},
+	}},
+	{Name: "Floral", Flavors: []Flavor{
+		{ID: "black-tea", Label: "Black tea"},
+		{ID: "floral", Label: "Floral"},
+	}},
+}
+
+// FlavorLabel returns the display label for a flavor ID, or "" if the ID
+// isn't on the wheel.
+func FlavorLabel(id string) string {
+	for _, category := range FlavorWheel {
+		for _, f := range category.Flavors {
+			if f.ID == id {
+				return f.Label
+			}
+		}
+	}
+	return ""
+}
+
+// ParseFlavors normalizes submitted flavor IDs: blanks and duplicates are
+// dropped and the rest are put in wheel order. Returns ErrUnknownFlavor if
+// any ID isn't on the wheel.
+func ParseFlavors(values []string) ([]string, error) {
+	picked := make(map[string]bool, len(values))
+	for _, v := range values {
+		id := strings.ToLower(strings.TrimSpace(v))
+		if id == "" {
+			continue
+		}
+		if FlavorLabel(id) == "" {
+			return nil, ErrUnknownFlavor
+		}
+		picked[id] = true
+	}
+
+	var flavors []string
+	for _, category := range FlavorWheel {
+		for _, f := range category.Flavors {
+			if picked[f.ID] {
+				flavors = append(flavors, f.ID)
+			}
+		}
+	}
+	return flavors, nil
+}
+
+// tastingToRecord encodes a profile as the brew lexicon's tastingProfile
+// object, or nil when there is nothing to store.
+func tastingToRecord(p *TastingProfile) map[string]any {
+	if p.IsEmpty() {
+		return nil
+	}
+	tp := map[string]any{}
+	for _, attr := range TastingAttributes {
+		if score := p.Score(attr.ID); score > 0 {
+			tp[attr.ID] = score
+		}
+	}
+	if len(p.Flavors) > 0 {
+		tp["flavors"] = slices.Clone(p.Flavors)
+	}
+	return tp
+}
+
+// tastingFromRecord decodes a tastingProfile object. Flavors written by
+// other clients are kept even if they aren't on FlavorWheel, since the
+// lexicon only lists known values.
+func tastingFromRecord(raw map[string]any) *TastingProfile {
+	p := &TastingProfile{}
+	for _, attr := range TastingAttributes {
+		if v, ok := toFloat64(raw[attr.ID]); ok {
+			p.SetScore(attr.ID, int(v))
+		}
+	}
+	switch flavors := raw["flavors"].(type) {
+	case []any:
+		for _, f := range flavors {
+			if id, ok := f.(string); ok && id != "" {
+				p.Flavors = append(p.Flavors, id)
+			}
+		}
+	case []string:
+		p.Flavors = slices.Clone(flavors)
+	}
+	if p.IsEmpty() {
+		return nil
+	}
+	return p
+}
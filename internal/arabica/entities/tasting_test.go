@@ -0,0 +1,92 @@
+package arabica
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFlavors(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    []string
+		wantErr error
+	}{
+		{"none", nil, nil, nil},
+		{"wheel order and deduplicated", []string{"cocoa", " Berry ", "cocoa", ""}, []string{"berry", "cocoa"}, nil},
+		{"unknown", []string{"berry", "bacon"}, nil, ErrUnknownFlavor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFlavors(tt.values)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTastingProfileValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile *TastingProfile
+		wantErr error
+	}{
+		{"nil", nil, nil},
+		{"partial", &TastingProfile{Acidity: 7, Flavors: []string{"floral"}}, nil},
+		{"score too high", &TastingProfile{Body: 11}, ErrTastingScoreOutOfRange},
+		{"negative score", &TastingProfile{Sweetness: -1}, ErrTastingScoreOutOfRange},
+		{"unknown flavor", &TastingProfile{Flavors: []string{"bacon"}}, ErrUnknownFlavor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.profile.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestBrewRoundTrip_Tasting(t *testing.T) {
+	tests := []struct {
+		name    string
+		tasting *TastingProfile
+		wantRaw bool
+	}{
+		{"scores and flavors", &TastingProfile{Acidity: 8, Body: 4, Sweetness: 6, Bitterness: 2, Flavors: []string{"citrus-fruit", "floral"}}, true},
+		{"flavors only", &TastingProfile{Flavors: []string{"cocoa"}}, true},
+		{"empty profile", &TastingProfile{}, false},
+		{"no profile", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := &Brew{
+				BeanRKey:  "abc123",
+				Tasting:   tt.tasting,
+				CreatedAt: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC),
+			}
+
+			record, err := BrewToRecord(original, "at://did:plc:test/social.arabica.alpha.bean/abc123", "", "", "")
+			require.NoError(t, err)
+			_, hasRaw := record["tasting"]
+			assert.Equal(t, tt.wantRaw, hasRaw)
+
+			// Round-trip through JSON as a PDS would, so numbers and arrays
+			// come back as float64 and []any.
+			raw, err := json.Marshal(record)
+			require.NoError(t, err)
+			var decoded map[string]any
+			require.NoError(t, json.Unmarshal(raw, &decoded))
+
+			restored, err := RecordToBrew(decoded, "at://did:plc:test/social.arabica.alpha.brew/tid123")
+			require.NoError(t, err)
+			if tt.wantRaw {
+				assert.Equal(t, tt.tasting, restored.Tasting)
+			} else {
+				assert.Nil(t, restored.Tasting)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package arabica
+
+import "tangled.org/arabica.social/arabica/internal/profileprefs"
+
+// GramsPerOunce converts avoirdupois ounces to grams.
+const GramsPerOunce = 28.349523125
+
+// fahrenheitThreshold mirrors the display heuristic: brew temperatures above
+// it can only have been entered in Fahrenheit.
+const fahrenheitThreshold = 100
+
+// TemperatureIn returns the brew temperature in unit. Older records hold
+// whatever the user typed, so a value above 100 is taken to be Fahrenheit
+// already. TemperatureUnitRecorded returns the stored value unchanged.
+func (b *Brew) TemperatureIn(unit profileprefs.TemperatureUnit) float64 {
+	t := b.Temperature
+	if t <= 0 {
+		return 0
+	}
+	recordedF := t > fahrenheitThreshold
+	switch {
+	case unit == profileprefs.TemperatureUnitCelsius && recordedF:
+		return (t - 32) * 5 / 9
+	case unit == profileprefs.TemperatureUnitFahrenheit && !recordedF:
+		return t*9/5 + 32
+	}
+	return t
+}
+
+// CoffeeAmountIn returns the dose in grams for metric or ounces for imperial.
+func (b *Brew) CoffeeAmountIn(system profileprefs.UnitSystem) float64 {
+	return GramsIn(b.CoffeeAmount, system)
+}
+
+// WaterAmountIn returns the water in grams for metric or ounces for
+// imperial. Like Ratio, it falls back to the sum of pours.
+func (b *Brew) WaterAmountIn(system profileprefs.UnitSystem) float64 {
+	water := b.WaterAmount
+	if water <= 0 {
+		for _, pour := range b.Pours {
+			water += pour.WaterAmount
+		}
+	}
+	return GramsIn(water, system)
+}
+
+// GramsIn converts a gram amount to the unit system's weight unit.
+func GramsIn(grams int, system profileprefs.UnitSystem) float64 {
+	if grams <= 0 {
+		return 0
+	}
+	if system == profileprefs.UnitSystemImperial {
+		return float64(grams) / GramsPerOunce
+	}
+	return float64(grams)
+}
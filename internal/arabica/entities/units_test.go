@@ -0,0 +1,40 @@
+package arabica
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"tangled.org/arabica.social/arabica/internal/profileprefs"
+)
+
+func TestBrew_TemperatureIn(t *testing.T) {
+	tests := []struct {
+		name string
+		temp float64
+		unit profileprefs.TemperatureUnit
+		want float64
+	}{
+		{"celsius stays celsius", 93.5, profileprefs.TemperatureUnitCelsius, 93.5},
+		{"celsius to fahrenheit", 100, profileprefs.TemperatureUnitFahrenheit, 212},
+		{"fahrenheit record to celsius", 212, profileprefs.TemperatureUnitCelsius, 100},
+		{"recorded is unchanged", 200, profileprefs.TemperatureUnitRecorded, 200},
+		{"unset", 0, profileprefs.TemperatureUnitFahrenheit, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			brew := &Brew{Temperature: tt.temp}
+			assert.InDelta(t, tt.want, brew.TemperatureIn(tt.unit), 0.001)
+		})
+	}
+}
+
+func TestBrew_AmountsIn(t *testing.T) {
+	brew := &Brew{CoffeeAmount: 15, Pours: []*Pour{{WaterAmount: 50}, {WaterAmount: 200}}}
+
+	assert.Equal(t, 15.0, brew.CoffeeAmountIn(profileprefs.UnitSystemMetric))
+	assert.Equal(t, 250.0, brew.WaterAmountIn(profileprefs.UnitSystemMetric), "falls back to pour total")
+	assert.InDelta(t, 0.529, brew.CoffeeAmountIn(profileprefs.UnitSystemImperial), 0.001)
+	assert.InDelta(t, 8.818, brew.WaterAmountIn(profileprefs.UnitSystemImperial), 0.001)
+	assert.Zero(t, (&Brew{}).CoffeeAmountIn(profileprefs.UnitSystemImperial))
+}
@@ -0,0 +1,138 @@
+package coffeehandlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+
+	"github.com/rs/zerolog/log"
+)
+
+// accountExportHeader is written ahead of the record collections so the
+// archive identifies its owner even if the download is cut short.
+type accountExportHeader struct {
+	DID        string    `json:"did"`
+	Handle     string    `json:"handle,omitempty"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// accountExportSection is one collection of the archive. Its list function
+// runs only when the section is written, so a single collection is held in
+// memory at a time.
+type accountExportSection struct {
+	name  string
+	write func(context.Context, *bufio.Writer) error
+}
+
+func exportSection[T any](name string, list func(context.Context) ([]T, error)) accountExportSection {
+	return accountExportSection{name: name, write: func(ctx context.Context, bw *bufio.Writer) error {
+		items, err := list(ctx)
+		if err != nil {
+			return err
+		}
+		return writeJSONArray(bw, items)
+	}}
+}
+
+// Export every Arabica record the user owns as a single JSON download
+func (h *Handlers) HandleAccountExport(w http.ResponseWriter, r *http.Request) {
+	store, authenticated := h.GetArabicaStore(r)
+	if !authenticated {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	did, _ := atpmiddleware.GetDID(r.Context())
+
+	header := accountExportHeader{DID: did, ExportedAt: time.Now().UTC()}
+	if p := h.GetUserProfile(r.Context(), did); p != nil {
+		header.Handle = p.Handle
+	}
+	sections := []accountExportSection{
+		exportSection("beans", store.ListBeans),
+		exportSection("roasters", store.ListRoasters),
+		exportSection("grinders", func(ctx context.Context) ([]*arabica.Grinder, error) { return listGrinders(ctx, store) }),
+		exportSection("brewers", func(ctx context.Context) ([]*arabica.Brewer, error) { return listBrewers(ctx, store) }),
+		exportSection("brews", func(ctx context.Context) ([]*arabica.Brew, error) { return store.ListBrews(ctx, 0, 0, 0) }),
+		exportSection("likes", store.ListUserLikes),
+	}
+
+	filename := fmt.Sprintf("arabica-account-%s.json", header.ExportedAt.Format("20060102"))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	out := &exportWriter{w: w}
+	if err := writeAccountExport(r.Context(), out, header, sections); err != nil {
+		log.Error().Err(err).Str("did", did).Msg("Failed to write account export")
+		// Once bytes are out the status is sent and the download is simply
+		// cut short; before that, the client can still get a proper error.
+		if !out.started {
+			w.Header().Del("Content-Disposition")
+			http.Error(w, "Failed to fetch your records", http.StatusBadGateway)
+		}
+	}
+}
+
+// exportWriter notes whether any of the archive has reached the client.
+type exportWriter struct {
+	w       io.Writer
+	started bool
+}
+
+func (ew *exportWriter) Write(p []byte) (int, error) {
+	ew.started = true
+	return ew.w.Write(p)
+}
+
+// writeAccountExport streams the archive section by section, fetching each
+// collection only when it is reached and writing it one record at a time,
+// so memory is bounded by the largest collection rather than the account.
+func writeAccountExport(ctx context.Context, w io.Writer, header accountExportHeader, sections []accountExportSection) error {
+	bw := bufio.NewWriter(w)
+
+	head, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	// Reopen the header object so the collections land alongside its fields.
+	bw.Write(head[:len(head)-1])
+
+	for _, section := range sections {
+		fmt.Fprintf(bw, ",%q:", section.name)
+		if err := section.write(ctx, bw); err != nil {
+			return fmt.Errorf("write %s: %w", section.name, err)
+		}
+	}
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// writeJSONArray encodes items as a JSON array, one element at a time.
+// A nil slice is written as [] so consumers always see every collection.
+func writeJSONArray[T any](bw *bufio.Writer, items []T) error {
+	bw.WriteByte('[')
+	for i, item := range items {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		b, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		bw.Write(b)
+		// Push completed chunks to the client as we go.
+		if bw.Buffered() > 32*1024 {
+			if err := bw.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	bw.WriteByte(']')
+	return nil
+}
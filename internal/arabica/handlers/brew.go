@@ -1,12 +1,18 @@
 package coffeehandlers
 
 import (
+	"cmp"
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
 	coffeeogcard "tangled.org/arabica.social/arabica/internal/arabica/ogcard"
@@ -17,6 +23,8 @@ import (
 	"tangled.org/arabica.social/arabica/internal/atproto"
 	"tangled.org/arabica.social/arabica/internal/handlers"
 	"tangled.org/arabica.social/arabica/internal/metrics"
+	"tangled.org/arabica.social/arabica/internal/ogcard"
+	"tangled.org/arabica.social/arabica/internal/profileprefs"
 	"tangled.org/pdewey.com/atp"
 	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
 
@@ -43,7 +51,7 @@ func (h *Handlers) HandleBrewOGImage(w http.ResponseWriter, r *http.Request) {
 	if strings.HasPrefix(owner, "did:") {
 		ownerDID = owner
 	} else {
-		resolved, err := publicClient.ResolveHandle(r.Context(), owner)
+		resolved, err := atproto.DefaultHandleCache.Resolve(r.Context(), owner, publicClient.ResolveHandle)
 		if err != nil {
 			log.Warn().Err(err).Str("handle", owner).Msg("Failed to resolve handle for OG image")
 			http.Error(w, "User not found", http.StatusNotFound)
@@ -52,55 +60,66 @@ func (h *Handlers) HandleBrewOGImage(w http.ResponseWriter, r *http.Request) {
 		ownerDID = resolved
 	}
 
-	// Fetch brew (witness cache first, then PDS fallback). Refs are
-	// resolved via the source-bound lookup so both paths share one walk.
-	var brew *arabica.Brew
+	brew, cid, err := h.loadPublicBrew(r.Context(), ownerDID, rkey, "brew_og")
+	if errors.Is(err, errBrewNotFound) {
+		log.Error().Err(err).Str("did", ownerDID).Str("rkey", rkey).Msg("Failed to get brew for OG image")
+		http.Error(w, "Brew not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to convert brew record for OG image")
+		http.Error(w, "Failed to load brew", http.StatusInternalServerError)
+		return
+	}
+
+	h.ServeRecordOGImage(w, atp.BuildATURI(ownerDID, arabica.NSIDBrew, rkey), cid, func() (*ogcard.Card, error) {
+		return coffeeogcard.DrawBrewCard(brew)
+	})
+}
+
+// errBrewNotFound is returned by loadPublicBrew when neither the witness
+// cache nor the owner's PDS has the record, or when the brew is a draft.
+var errBrewNotFound = errors.New("brew not found")
+
+// loadPublicBrew fetches a brew for unauthenticated surfaces (OG images,
+// oEmbed): witness cache first, then the owner's PDS. Refs are resolved via
+// the source-bound lookup so both paths share one walk. The returned CID is
+// empty if the source didn't report one.
+func (h *Handlers) loadPublicBrew(ctx context.Context, ownerDID, rkey, metricLabel string) (*arabica.Brew, string, error) {
 	brewURI := atp.BuildATURI(ownerDID, arabica.NSIDBrew, rkey)
 	if h.WitnessCache() != nil {
-		if wr, _ := h.WitnessCache().GetWitnessRecord(r.Context(), brewURI); wr != nil {
+		if wr, _ := h.WitnessCache().GetWitnessRecord(ctx, brewURI); wr != nil {
 			if m, err := atproto.WitnessRecordToMap(wr); err == nil {
-				if b, err := arabica.RecordToBrew(m, wr.URI); err == nil {
-					metrics.WitnessCacheHitsTotal.WithLabelValues("brew_og").Inc()
-					brew = b
+				if brew, err := arabica.RecordToBrew(m, wr.URI); err == nil {
+					metrics.WitnessCacheHitsTotal.WithLabelValues(metricLabel).Inc()
+					if brew.Draft {
+						return nil, "", errBrewNotFound
+					}
 					brew.RKey = rkey
 					arabicastore.ExtractBrewRefRKeys(brew, m)
-					arabica.HydrateBrewRefs(brew, m, h.WitnessLookup(r.Context()))
+					arabica.HydrateBrewRefs(brew, m, h.WitnessLookup(ctx))
+					return brew, wr.CID, nil
 				}
 			}
 		}
 	}
-	if brew == nil {
-		metrics.WitnessCacheMissesTotal.WithLabelValues("brew_og").Inc()
-		record, err := publicClient.GetPublicRecord(r.Context(), ownerDID, arabica.NSIDBrew, rkey)
-		if err != nil {
-			log.Error().Err(err).Str("did", ownerDID).Str("rkey", rkey).Msg("Failed to get brew for OG image")
-			http.Error(w, "Brew not found", http.StatusNotFound)
-			return
-		}
-		brew, err = arabica.RecordToBrew(record.Value, record.URI)
-		if err != nil {
-			log.Error().Err(err).Msg("Failed to convert brew record for OG image")
-			http.Error(w, "Failed to load brew", http.StatusInternalServerError)
-			return
-		}
-		brew.RKey = rkey
-		arabicastore.ExtractBrewRefRKeys(brew, record.Value)
-		arabica.HydrateBrewRefs(brew, record.Value, handlers.PublicLookup(r.Context()))
-	}
 
-	// Generate card
-	card, err := coffeeogcard.DrawBrewCard(brew)
+	metrics.WitnessCacheMissesTotal.WithLabelValues(metricLabel).Inc()
+	record, err := atproto.NewPublicClient().GetPublicRecord(ctx, ownerDID, arabica.NSIDBrew, rkey)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to generate OG image")
-		http.Error(w, "Failed to generate image", http.StatusInternalServerError)
-		return
+		return nil, "", fmt.Errorf("%w: %w", errBrewNotFound, err)
 	}
-
-	w.Header().Set("Content-Type", "image/png")
-	w.Header().Set("Cache-Control", "public, max-age=86400") // 24 hours
-	if err := card.EncodePNG(w); err != nil {
-		log.Error().Err(err).Msg("Failed to encode OG image")
+	brew, err := arabica.RecordToBrew(record.Value, record.URI)
+	if err != nil {
+		return nil, "", err
+	}
+	if brew.Draft {
+		return nil, "", errBrewNotFound
 	}
+	brew.RKey = rkey
+	arabicastore.ExtractBrewRefRKeys(brew, record.Value)
+	arabica.HydrateBrewRefs(brew, record.Value, handlers.PublicLookup(ctx))
+	return brew, record.CID, nil
 }
 
 // Brew list partial (loaded async via HTMX)
@@ -128,8 +147,22 @@ func (h *Handlers) HandleBrewListPartial(w http.ResponseWriter, r *http.Request)
 		limit = 25
 	}
 
-	// Request limit+1 to detect if there are more results beyond this page.
-	brews, err := store.ListBrews(r.Context(), 1, offset, limit+1)
+	filter := parseBrewFilter(r.URL.Query())
+
+	var brews []*arabica.Brew
+	var err error
+	if filter.IsDefault() {
+		// Request limit+1 to detect if there are more results beyond this page.
+		brews, err = store.ListBrews(r.Context(), 1, offset, limit+1)
+	} else {
+		// Filters can't be pushed down to the paginated store query, so load
+		// the whole journal and page the filtered result in memory.
+		brews, err = store.ListBrews(r.Context(), 1, 0, 0)
+		if err == nil {
+			brews = arabica.FilterBrews(brews, filter)
+			brews = brews[min(offset, len(brews)):]
+		}
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to fetch brews")
 		handlers.HandleStoreError(w, err, "Failed to fetch brews")
@@ -148,12 +181,103 @@ func (h *Handlers) HandleBrewListPartial(w http.ResponseWriter, r *http.Request)
 		ProfileHandle: profileHandle,
 		HasMore:       hasMore,
 		NextOffset:    offset + limit,
+		FilterQuery:   brewFilterQuery(filter),
 	}).Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render content", http.StatusInternalServerError)
 		log.Error().Err(err).Msg("Failed to render brew list partial")
 	}
 }
 
+// maxBrewSearchResults caps the search partial. Search has no "Load More"
+// since results are ranked rather than paged by date.
+const maxBrewSearchResults = 100
+
+// HandleBrewSearch renders the user's brews matching ?q= against tasting
+// notes, bean name, origin and method. The whole journal comes from the
+// session cache, so matching happens in memory. An empty query returns the
+// full list.
+func (h *Handlers) HandleBrewSearch(w http.ResponseWriter, r *http.Request) {
+	store, authenticated := h.GetArabicaStore(r)
+	if !authenticated {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	didStr, _ := atpmiddleware.GetDID(r.Context())
+	profileHandle := didStr
+	if p := h.GetUserProfile(r.Context(), didStr); p != nil && p.Handle != "" {
+		profileHandle = p.Handle
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		// Hand back the regular paged list so clearing the box restores it.
+		h.HandleBrewListPartial(w, r)
+		return
+	}
+
+	brews, err := store.ListBrews(r.Context(), 1, 0, 0)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch brews for search")
+		handlers.HandleStoreError(w, err, "Failed to fetch brews")
+		return
+	}
+	brews = arabica.SearchBrews(brews, query)
+	if len(brews) > maxBrewSearchResults {
+		brews = brews[:maxBrewSearchResults]
+	}
+
+	if err := coffee.BrewListTablePartial(coffee.BrewListTableProps{
+		Brews:         brews,
+		IsOwnProfile:  true,
+		ProfileHandle: profileHandle,
+		SearchQuery:   query,
+	}).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render content", http.StatusInternalServerError)
+		log.Error().Err(err).Msg("Failed to render brew search results")
+	}
+}
+
+// parseBrewFilter reads the brew list filter from query params: bean,
+// method, min_rating, tag and sort. Malformed values are ignored.
+func parseBrewFilter(q url.Values) arabica.BrewFilter {
+	filter := arabica.BrewFilter{
+		BeanRKey: q.Get("bean"),
+		Method:   q.Get("method"),
+		Tag:      arabica.NormalizeTag(q.Get("tag")),
+	}
+	if v, err := strconv.Atoi(q.Get("min_rating")); err == nil && v > 0 {
+		filter.MinRating = v
+	}
+	switch s := q.Get("sort"); s {
+	case arabica.BrewSortDate, arabica.BrewSortRating, arabica.BrewSortRatio:
+		filter.Sort = s
+	}
+	return filter
+}
+
+// brewFilterQuery encodes the filter back into query params so "Load More"
+// keeps paging through the same filtered list.
+func brewFilterQuery(filter arabica.BrewFilter) string {
+	q := url.Values{}
+	if filter.BeanRKey != "" {
+		q.Set("bean", filter.BeanRKey)
+	}
+	if filter.Method != "" {
+		q.Set("method", filter.Method)
+	}
+	if filter.MinRating > 0 {
+		q.Set("min_rating", strconv.Itoa(filter.MinRating))
+	}
+	if filter.Tag != "" {
+		q.Set("tag", filter.Tag)
+	}
+	if filter.Sort != "" {
+		q.Set("sort", filter.Sort)
+	}
+	return q.Encode()
+}
+
 // List all brews
 func (h *Handlers) HandleBrewList(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/my-coffee", http.StatusMovedPermanently)
@@ -174,9 +298,10 @@ func (h *Handlers) HandleBrewNew(w http.ResponseWriter, r *http.Request) {
 
 	layoutData, _, _ := h.LayoutDataFromRequest(r, "New Brew")
 	brewFormProps := coffeepages.BrewFormProps{
-		Brew:           nil,
+		Brew:           preferencesDraftBrew(r.Context(), store),
 		RecipeRKey:     r.URL.Query().Get("recipe"),
 		RecipeOwnerDID: r.URL.Query().Get("recipe_owner"),
+		CanCrosspost:   h.HasBlueskyPostScopes(r),
 	}
 	if err := coffeepages.BrewFormPage(layoutData, brewFormProps).Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render page", http.StatusInternalServerError)
@@ -196,6 +321,12 @@ func brewNewReady(ctx context.Context, store onboarding.BrewPrerequisiteStore) b
 	return status.Ready()
 }
 
+// brewSharePath is the short permalink for a brew, /b/{actor}/{rkey}. It
+// renders the same page as /brews/{actor}/{rkey}.
+func brewSharePath(actor, rkey string) string {
+	return "/b/" + actor + "/" + rkey
+}
+
 // Show brew view page
 func (h *Handlers) HandleBrewView(w http.ResponseWriter, r *http.Request) {
 	h.RenderEntityView(w, r, h.brewViewConfig())
@@ -215,7 +346,7 @@ func (h *Handlers) HandleBrewEdit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	brew, err := store.GetBrewByRKey(r.Context(), rkey)
+	existing, err := store.GetBrewRecordByRKey(r.Context(), rkey)
 	if err != nil {
 		http.Error(w, "Brew not found", http.StatusNotFound)
 		log.Error().Err(err).Str("rkey", rkey).Msg("Failed to get brew for edit")
@@ -227,8 +358,9 @@ func (h *Handlers) HandleBrewEdit(w http.ResponseWriter, r *http.Request) {
 	layoutData, _, _ := h.LayoutDataFromRequest(r, "Edit Brew")
 
 	brewFormProps := coffeepages.BrewFormProps{
-		Brew:      brew,
-		PoursJSON: coffeepages.PoursToJSON(brew.Pours),
+		Brew:      existing.Model,
+		SwapCID:   existing.CID,
+		PoursJSON: coffeepages.PoursToJSON(existing.Model.Pours),
 	}
 
 	if err := coffeepages.BrewFormPage(layoutData, brewFormProps).Render(r.Context(), w); err != nil {
@@ -237,6 +369,46 @@ func (h *Handlers) HandleBrewEdit(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Show new brew form pre-filled from an existing brew
+func (h *Handlers) HandleBrewClone(w http.ResponseWriter, r *http.Request) {
+	rkey := handlers.ValidateRKey(w, r.PathValue("id"))
+	if rkey == "" {
+		return
+	}
+
+	store, authenticated := h.GetArabicaStore(r)
+	if !authenticated {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return
+	}
+
+	brew, err := store.GetBrewByRKey(r.Context(), rkey)
+	if err != nil {
+		http.Error(w, "Brew not found", http.StatusNotFound)
+		log.Error().Err(err).Str("rkey", rkey).Msg("Failed to get brew for clone")
+		return
+	}
+
+	// Copy so the cached brew isn't mutated. Clearing the rkey makes the form
+	// post a new record; bean/grinder/brewer/recipe rkeys carry over as-is.
+	clone := *brew
+	clone.RKey = ""
+	clone.CreatedAt = time.Time{}
+
+	layoutData, _, _ := h.LayoutDataFromRequest(r, "New Brew")
+
+	brewFormProps := coffeepages.BrewFormProps{
+		Brew:         &clone,
+		PoursJSON:    coffeepages.PoursToJSON(brew.Pours),
+		CanCrosspost: h.HasBlueskyPostScopes(r),
+	}
+
+	if err := coffeepages.BrewFormPage(layoutData, brewFormProps).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		log.Error().Err(err).Msg("Failed to render brew clone form")
+	}
+}
+
 // parseEspressoParams extracts espresso-specific params from form values.
 // Returns nil if no espresso params were provided.
 func parseEspressoParams(r *http.Request) *arabica.EspressoParams {
@@ -323,14 +495,15 @@ func parsePours(r *http.Request) []arabica.CreatePourData {
 	return pours
 }
 
-// ValidationError represents a validation error with field name and message
+// ValidationError represents a validation error with field name and message.
+// Field is the form field's name, or "" for problems not tied to one field.
 type ValidationError struct {
-	Field   string
-	Message string
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 // validateBrewRequest validates brew form input and returns any validation errors
-func validateBrewRequest(r *http.Request) (temperature float64, waterAmount, coffeeAmount, timeSeconds, rating int, pours []arabica.CreatePourData, errs []ValidationError) {
+func validateBrewRequest(r *http.Request) (temperature float64, waterAmount, coffeeAmount, timeSeconds, rating int, tds float64, tasting *arabica.TastingProfile, method arabica.BrewMethod, methodOther string, pours []arabica.CreatePourData, errs []ValidationError) {
 	// Parse and validate temperature
 	if tempStr := r.FormValue("temperature"); tempStr != "" {
 		var err error
@@ -386,73 +559,100 @@ func validateBrewRequest(r *http.Request) (temperature float64, waterAmount, cof
 		}
 	}
 
+	// Parse and validate TDS (percent)
+	if tdsStr := r.FormValue("tds"); tdsStr != "" {
+		var err error
+		tds, err = strconv.ParseFloat(tdsStr, 64)
+		if err != nil {
+			errs = append(errs, ValidationError{Field: "tds", Message: "invalid TDS"})
+		} else if tds < 0 || tds > 5 {
+			errs = append(errs, ValidationError{Field: "tds", Message: "TDS must be between 0 and 5%"})
+		}
+	}
+
+	// Parse and validate the structured tasting scores and flavor picks
+	tasting = &arabica.TastingProfile{}
+	for _, attr := range arabica.TastingAttributes {
+		scoreStr := r.FormValue(attr.ID)
+		if scoreStr == "" {
+			continue
+		}
+		score, err := strconv.Atoi(scoreStr)
+		if err != nil {
+			errs = append(errs, ValidationError{Field: attr.ID, Message: "invalid " + strings.ToLower(attr.Label) + " score"})
+		} else if score < arabica.MinTastingScore || score > arabica.MaxTastingScore {
+			errs = append(errs, ValidationError{Field: attr.ID, Message: attr.Label + " must be between 1 and 10"})
+		} else {
+			tasting.SetScore(attr.ID, score)
+		}
+	}
+	flavors, err := arabica.ParseFlavors(r.Form["flavors"])
+	if err != nil {
+		errs = append(errs, ValidationError{Field: "flavors", Message: "unknown flavor"})
+	}
+	tasting.Flavors = flavors
+	if tasting.IsEmpty() {
+		tasting = nil
+	}
+
+	// Normalize the method. Picking "other" sends the user's wording in
+	// method_other, which may still turn out to be a known method.
+	rawMethod := strings.TrimSpace(r.FormValue("method"))
+	if arabica.BrewMethod(rawMethod) == arabica.BrewMethodOther {
+		if other := strings.TrimSpace(r.FormValue("method_other")); other != "" {
+			rawMethod = other
+		}
+	}
+	if len(rawMethod) > arabica.MaxMethodLength {
+		errs = append(errs, ValidationError{Field: "method", Message: "method is too long"})
+	} else {
+		method, methodOther = arabica.NormalizeBrewMethod(rawMethod)
+	}
+
 	// Parse pours
 	pours = parsePours(r)
 
 	return
 }
 
-// Create new brew
-func (h *Handlers) HandleBrewCreate(w http.ResponseWriter, r *http.Request) {
-	// Require authentication first
-	store, authenticated := h.GetArabicaStore(r)
-	if !authenticated {
-		http.Redirect(w, r, "/login", http.StatusFound)
-		return
-	}
-
-	if err := r.ParseForm(); err != nil {
-		log.Warn().Err(err).Msg("Failed to parse brew create form")
-		http.Error(w, "Invalid form data", http.StatusBadRequest)
-		return
-	}
-
-	// Validate input
-	temperature, waterAmount, coffeeAmount, timeSeconds, rating, pours, validationErrs := validateBrewRequest(r)
-	if len(validationErrs) > 0 {
-		log.Warn().Str("field", validationErrs[0].Field).Str("error", validationErrs[0].Message).Msg("Brew create validation failed")
-		http.Error(w, validationErrs[0].Message, http.StatusBadRequest)
-		return
-	}
+// brewRequestFromForm builds a CreateBrewRequest from the brew form,
+// running validateBrewRequest, the reference checks and
+// CreateBrewRequest.Validate. Every problem is collected rather than
+// stopping at the first.
+func brewRequestFromForm(r *http.Request) (*arabica.CreateBrewRequest, []ValidationError) {
+	temperature, waterAmount, coffeeAmount, timeSeconds, rating, tds, tasting, method, methodOther, pours, errs := validateBrewRequest(r)
 
-	// Validate required fields
 	beanRKey := r.FormValue("bean_rkey")
 	if beanRKey == "" {
-		log.Warn().Msg("Brew create: missing bean_rkey")
-		http.Error(w, "Bean selection is required", http.StatusBadRequest)
-		return
-	}
-	if !atp.ValidateRKey(beanRKey) {
-		log.Warn().Str("bean_rkey", beanRKey).Msg("Brew create: invalid bean rkey format")
-		http.Error(w, "Invalid bean selection", http.StatusBadRequest)
-		return
+		errs = append(errs, ValidationError{Field: "bean_rkey", Message: "Bean selection is required"})
+	} else if !atp.ValidateRKey(beanRKey) {
+		errs = append(errs, ValidationError{Field: "bean_rkey", Message: "Invalid bean selection"})
 	}
 
-	// Validate optional rkeys
 	grinderRKey := r.FormValue("grinder_rkey")
-	if errMsg := handlers.ValidateOptionalRKey(grinderRKey, "Grinder selection"); errMsg != "" {
-		log.Warn().Str("grinder_rkey", grinderRKey).Msg("Brew create: invalid grinder rkey")
-		http.Error(w, errMsg, http.StatusBadRequest)
-		return
-	}
 	brewerRKey := r.FormValue("brewer_rkey")
-	if errMsg := handlers.ValidateOptionalRKey(brewerRKey, "Brewer selection"); errMsg != "" {
-		log.Warn().Str("brewer_rkey", brewerRKey).Msg("Brew create: invalid brewer rkey")
-		http.Error(w, errMsg, http.StatusBadRequest)
-		return
-	}
 	recipeRKey := r.FormValue("recipe_rkey")
-	if errMsg := handlers.ValidateOptionalRKey(recipeRKey, "Recipe selection"); errMsg != "" {
-		log.Warn().Str("recipe_rkey", recipeRKey).Msg("Brew create: invalid recipe rkey")
-		http.Error(w, errMsg, http.StatusBadRequest)
-		return
+	for _, ref := range []struct{ field, rkey, label string }{
+		{"grinder_rkey", grinderRKey, "Grinder selection"},
+		{"brewer_rkey", brewerRKey, "Brewer selection"},
+		{"recipe_rkey", recipeRKey, "Recipe selection"},
+	} {
+		if errMsg := handlers.ValidateOptionalRKey(ref.rkey, ref.label); errMsg != "" {
+			errs = append(errs, ValidationError{Field: ref.field, Message: errMsg})
+		}
+	}
+
+	tags, tagsErr := arabica.ParseBrewTags(r.FormValue("tags"))
+	if tagsErr != nil {
+		errs = append(errs, ValidationError{Field: "tags", Message: tagsErr.Error()})
 	}
 
 	req := &arabica.CreateBrewRequest{
 		BeanRKey:       beanRKey,
 		RecipeRKey:     recipeRKey,
 		RecipeOwnerDID: r.FormValue("recipe_owner_did"),
-		Method:         r.FormValue("method"),
+		Method:         string(method),
+		MethodOther:    methodOther,
 		Temperature:    temperature,
 		WaterAmount:    waterAmount,
 		CoffeeAmount:   coffeeAmount,
@@ -462,41 +662,161 @@ func (h *Handlers) HandleBrewCreate(w http.ResponseWriter, r *http.Request) {
 		BrewerRKey:     brewerRKey,
 		TastingNotes:   r.FormValue("tasting_notes"),
 		Rating:         rating,
+		TDS:            tds,
+		Tags:           tags,
+		Draft:          r.FormValue("draft") == "true",
+		Tasting:        tasting,
 		Pours:          pours,
 	}
 	req.EspressoParams = parseEspressoParams(r)
 	req.PouroverParams = parsePouroverParams(r)
 
 	if err := req.Validate(); err != nil {
-		log.Warn().Err(err).Msg("Brew create request validation failed")
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		errs = append(errs, ValidationError{Message: err.Error()})
+	}
+	return req, errs
+}
+
+// HandleBrewValidate runs the brew form through the same checks as create
+// and update without writing anything, so the form can flag problems
+// before it submits. The response is {"errors": [...]}: empty with 200
+// when the brew is valid, otherwise every problem with 422.
+func (h *Handlers) HandleBrewValidate(w http.ResponseWriter, r *http.Request) {
+	if _, authenticated := h.GetArabicaStore(r); !authenticated {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
 		return
 	}
+	if err := parseBrewForm(r); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	_, errs := brewRequestFromForm(r)
+	writeValidationErrors(w, errs)
+}
 
-	_, err := store.CreateBrew(r.Context(), req, 1) // User ID not used with atproto
+// writeValidationErrors writes errs as {"errors": [...]}, with 422 when
+// there is anything to report and 200 for an empty list. The brew form
+// reads the body to mark each field it names.
+func writeValidationErrors(w http.ResponseWriter, errs []ValidationError) {
+	if errs == nil {
+		errs = []ValidationError{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if len(errs) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	if err := json.NewEncoder(w).Encode(map[string][]ValidationError{"errors": errs}); err != nil {
+		log.Error().Err(err).Msg("Failed to encode validation errors")
+	}
+}
+
+// Create new brew
+func (h *Handlers) HandleBrewCreate(w http.ResponseWriter, r *http.Request) {
+	// Require authentication first
+	store, authenticated := h.GetArabicaStore(r)
+	if !authenticated {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return
+	}
+
+	if err := parseBrewForm(r); err != nil {
+		log.Warn().Err(err).Msg("Failed to parse brew create form")
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	req, validationErrs := brewRequestFromForm(r)
+	if len(validationErrs) > 0 {
+		log.Warn().Str("field", validationErrs[0].Field).Str("error", validationErrs[0].Message).Int("count", len(validationErrs)).Msg("Brew create validation failed")
+		writeValidationErrors(w, validationErrs)
+		return
+	}
+
+	image, ok := uploadBrewImage(w, r, store)
+	if !ok {
+		return
+	}
+	req.Image = image
+
+	brew, err := store.CreateBrew(r.Context(), req, 1) // User ID not used with atproto
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to create brew")
 		handlers.HandleStoreError(w, err, "Failed to create brew")
 		return
 	}
 
+	// Inventory is best-effort; the brew is already saved.
+	if req.CoffeeAmount > 0 {
+		if err := store.AdjustBeanInventory(r.Context(), req.BeanRKey, -req.CoffeeAmount); err != nil {
+			log.Warn().Err(err).Str("bean_rkey", req.BeanRKey).Msg("Failed to decrement bean inventory")
+		}
+	}
+
 	h.InvalidateFeedCache()
 
 	// Check if the bean is incomplete and include nudge info in response header.
 	// The brew form JS reads this before HTMX processes the redirect.
 	ctx := r.Context()
-	if beanRKey != "" {
-		if bean, beanErr := store.GetBeanByRKey(ctx, beanRKey); beanErr == nil && bean != nil && bean.IsIncomplete() {
+	if req.BeanRKey != "" {
+		if bean, beanErr := store.GetBeanByRKey(ctx, req.BeanRKey); beanErr == nil && bean != nil && bean.IsIncomplete() {
 			nudge := fmt.Sprintf(`{"entity_type":"bean","rkey":"%s","name":"%s","missing":"%s"}`,
 				bean.RKey, bean.Name, strings.Join(bean.MissingFields(), ", "))
 			w.Header().Set("X-Incomplete-Nudge", nudge)
 		}
 	}
 
-	w.Header().Set("HX-Redirect", "/my-coffee")
+	redirect := "/my-coffee"
+	if brew != nil && !brew.Draft && r.FormValue("crosspost_bluesky") == "true" {
+		// The brew is already saved, so a failed post only earns a warning.
+		if !h.crosspostBrew(r, store, brew) {
+			redirect = "/my-coffee?crosspost=failed"
+		}
+	}
+
+	w.Header().Set("HX-Redirect", redirect)
 	w.WriteHeader(http.StatusOK)
 }
 
+// crosspostBrew posts a summary of brew to the user's Bluesky feed and
+// reports whether it went through. Failures are logged, never returned:
+// the most common cause is a session without the post scope.
+func (h *Handlers) crosspostBrew(r *http.Request, store arabicastore.Store, brew *arabica.Brew) bool {
+	post := h.brewBlueskyPost(r, store.DID(), brew)
+	if _, err := store.CreateBlueskyPost(r.Context(), post); err != nil {
+		log.Warn().Err(err).Str("brew_rkey", brew.RKey).Msg("Failed to crosspost brew to Bluesky")
+		return false
+	}
+	return true
+}
+
+// brewBlueskyPost builds the crosspost for a newly created brew: a one-line
+// summary plus a link card pointing back at the brew page.
+func (h *Handlers) brewBlueskyPost(r *http.Request, did string, brew *arabica.Brew) atproto.BlueskyPost {
+	title := brewBeanSummary(brew)
+	if title == "" {
+		title = "a coffee"
+	}
+	text := "Brewed " + title
+	if method := brew.MethodLabel(); method != "" {
+		text += " (" + method + ")"
+	}
+	if brew.Rating > 0 {
+		text += fmt.Sprintf(" — %d/10", brew.Rating)
+	}
+	if brew.TastingNotes != "" {
+		text += "\n\n" + brew.TastingNotes
+	}
+
+	handle := h.ResolveOwnerHandle(r.Context(), did)
+	return atproto.BlueskyPost{
+		Text:            text,
+		LinkURL:         h.PublicBaseURL(r) + brewSharePath(handle, brew.RKey),
+		LinkTitle:       title,
+		LinkDescription: "A brew logged on arabica.social",
+	}
+}
+
 // Update existing brew
 func (h *Handlers) HandleBrewUpdate(w http.ResponseWriter, r *http.Request) {
 	rkey := handlers.ValidateRKey(w, r.PathValue("id"))
@@ -511,88 +831,64 @@ func (h *Handlers) HandleBrewUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := r.ParseForm(); err != nil {
+	if err := parseBrewForm(r); err != nil {
 		log.Warn().Err(err).Str("rkey", rkey).Msg("Failed to parse brew update form")
 		http.Error(w, "Invalid form data", http.StatusBadRequest)
 		return
 	}
 
-	// Validate input
-	temperature, waterAmount, coffeeAmount, timeSeconds, rating, pours, validationErrs := validateBrewRequest(r)
+	req, validationErrs := brewRequestFromForm(r)
 	if len(validationErrs) > 0 {
-		log.Warn().Str("rkey", rkey).Str("field", validationErrs[0].Field).Str("error", validationErrs[0].Message).Msg("Brew update validation failed")
-		http.Error(w, validationErrs[0].Message, http.StatusBadRequest)
+		log.Warn().Str("rkey", rkey).Str("field", validationErrs[0].Field).Str("error", validationErrs[0].Message).Int("count", len(validationErrs)).Msg("Brew update validation failed")
+		writeValidationErrors(w, validationErrs)
 		return
 	}
 
-	// Validate required fields
-	beanRKey := r.FormValue("bean_rkey")
-	if beanRKey == "" {
-		log.Warn().Str("rkey", rkey).Msg("Brew update: missing bean_rkey")
-		http.Error(w, "Bean selection is required", http.StatusBadRequest)
-		return
-	}
-	if !atp.ValidateRKey(beanRKey) {
-		log.Warn().Str("rkey", rkey).Str("bean_rkey", beanRKey).Msg("Brew update: invalid bean rkey format")
-		http.Error(w, "Invalid bean selection", http.StatusBadRequest)
+	image, ok := uploadBrewImage(w, r, store)
+	if !ok {
 		return
 	}
+	req.Image = image
+	req.RemoveImage = r.FormValue("remove_image") == "true"
+	req.SwapCID = handlers.SwapCID(r)
 
-	// Validate optional rkeys
-	grinderRKey := r.FormValue("grinder_rkey")
-	if errMsg := handlers.ValidateOptionalRKey(grinderRKey, "Grinder selection"); errMsg != "" {
-		log.Warn().Str("rkey", rkey).Str("grinder_rkey", grinderRKey).Msg("Brew update: invalid grinder rkey")
-		http.Error(w, errMsg, http.StatusBadRequest)
-		return
-	}
-	brewerRKey := r.FormValue("brewer_rkey")
-	if errMsg := handlers.ValidateOptionalRKey(brewerRKey, "Brewer selection"); errMsg != "" {
-		log.Warn().Str("rkey", rkey).Str("brewer_rkey", brewerRKey).Msg("Brew update: invalid brewer rkey")
-		http.Error(w, errMsg, http.StatusBadRequest)
-		return
-	}
-	recipeRKey := r.FormValue("recipe_rkey")
-	if errMsg := handlers.ValidateOptionalRKey(recipeRKey, "Recipe selection"); errMsg != "" {
-		log.Warn().Str("rkey", rkey).Str("recipe_rkey", recipeRKey).Msg("Brew update: invalid recipe rkey")
-		http.Error(w, errMsg, http.StatusBadRequest)
+	err := store.UpdateBrewByRKey(r.Context(), rkey, req)
+	if err != nil {
+		log.Error().Err(err).Str("rkey", rkey).Msg("Failed to update brew")
+		handlers.HandleStoreError(w, err, "Failed to update brew")
 		return
 	}
 
-	req := &arabica.CreateBrewRequest{
-		BeanRKey:       beanRKey,
-		RecipeRKey:     recipeRKey,
-		RecipeOwnerDID: r.FormValue("recipe_owner_did"),
-		Method:         r.FormValue("method"),
-		Temperature:    temperature,
-		WaterAmount:    waterAmount,
-		CoffeeAmount:   coffeeAmount,
-		TimeSeconds:    timeSeconds,
-		GrindSize:      r.FormValue("grind_size"),
-		GrinderRKey:    grinderRKey,
-		BrewerRKey:     brewerRKey,
-		TastingNotes:   r.FormValue("tasting_notes"),
-		Rating:         rating,
-		Pours:          pours,
+	h.InvalidateFeedCache()
+
+	w.Header().Set("HX-Redirect", "/my-coffee")
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleBrewPublish takes a brew out of draft so it shows up in the feed
+// and on the owner's public profile. The page is refreshed afterwards so
+// the draft badge and publish button go away wherever it was clicked.
+func (h *Handlers) HandleBrewPublish(w http.ResponseWriter, r *http.Request) {
+	rkey := handlers.ValidateRKey(w, r.PathValue("id"))
+	if rkey == "" {
+		return
 	}
-	req.EspressoParams = parseEspressoParams(r)
-	req.PouroverParams = parsePouroverParams(r)
 
-	if err := req.Validate(); err != nil {
-		log.Warn().Err(err).Str("rkey", rkey).Msg("Brew update request validation failed")
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	store, authenticated := h.GetArabicaStore(r)
+	if !authenticated {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
 		return
 	}
 
-	err := store.UpdateBrewByRKey(r.Context(), rkey, req)
-	if err != nil {
-		log.Error().Err(err).Str("rkey", rkey).Msg("Failed to update brew")
-		handlers.HandleStoreError(w, err, "Failed to update brew")
+	if err := store.PublishBrewByRKey(r.Context(), rkey); err != nil {
+		log.Error().Err(err).Str("rkey", rkey).Msg("Failed to publish brew")
+		handlers.HandleStoreError(w, err, "Failed to publish brew")
 		return
 	}
 
 	h.InvalidateFeedCache()
 
-	w.Header().Set("HX-Redirect", "/my-coffee")
+	w.Header().Set("HX-Refresh", "true")
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -606,6 +902,17 @@ func (h *Handlers) HandleBrewDelete(w http.ResponseWriter, r *http.Request) {
 	h.DeleteEntity(w, r, store.DeleteBrewByRKey, "brew", arabica.NSIDBrew)
 }
 
+// brewExport adds computed fields to an exported brew. Ratio keeps full
+// precision; rounding is a display concern. Amounts are always metric
+// whatever the viewer's display units, and Units says so; Temperature
+// shadows the embedded field so Fahrenheit-era records come out in °C.
+type brewExport struct {
+	*arabica.Brew
+	Ratio       float64 `json:"ratio,omitempty"`
+	Temperature float64 `json:"temperature"`
+	Units       string  `json:"units"`
+}
+
 // Export brews as JSON
 func (h *Handlers) HandleBrewExport(w http.ResponseWriter, r *http.Request) {
 	// Require authentication
@@ -622,12 +929,139 @@ func (h *Handlers) HandleBrewExport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	export := make([]brewExport, len(brews))
+	for i, brew := range brews {
+		export[i] = brewExport{
+			Brew:        brew,
+			Ratio:       brew.Ratio(),
+			Temperature: brew.TemperatureIn(profileprefs.TemperatureUnitCelsius),
+			Units:       string(profileprefs.UnitSystemMetric),
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Content-Disposition", "attachment; filename=arabica-brews.json")
 
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(brews); err != nil {
+	if err := encoder.Encode(export); err != nil {
 		log.Error().Err(err).Msg("Failed to encode brews for export")
 	}
 }
+
+// brewCSVHeader lists the columns written by writeBrewsCSV, in order. New
+// columns go at the end so spreadsheets built on older exports keep lining
+// up.
+var brewCSVHeader = []string{
+	"date", "bean", "roaster", "method", "coffee_g", "water_g", "ratio",
+	"temperature", "time_seconds", "rating", "tasting_notes", "pours",
+	"acidity", "body", "sweetness", "bitterness", "flavors", "temperature_unit",
+}
+
+// Export brews as CSV for spreadsheets
+func (h *Handlers) HandleBrewExportCSV(w http.ResponseWriter, r *http.Request) {
+	store, authenticated := h.GetArabicaStore(r)
+	if !authenticated {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	brews, err := store.ListBrews(r.Context(), 1, 0, 0) // limit=0 returns all
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list brews for CSV export")
+		handlers.HandleStoreError(w, err, "Failed to fetch brews")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=arabica-brews.csv")
+
+	if err := writeBrewsCSV(w, brews); err != nil {
+		log.Error().Err(err).Msg("Failed to write brews CSV")
+	}
+}
+
+// writeBrewsCSV writes one row per brew. Empty cells mean the value wasn't
+// recorded; pours are collapsed into a single "50g@30s;100g@60s" column and
+// flavor wheel picks into "berry;cocoa". Temperatures are always written in
+// Celsius, whatever the viewer's display units, and temperature_unit says
+// so next to every recorded value.
+func writeBrewsCSV(w io.Writer, brews []*arabica.Brew) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(brewCSVHeader); err != nil {
+		return err
+	}
+
+	for _, brew := range brews {
+		var bean, roaster string
+		if brew.Bean != nil {
+			bean = cmp.Or(brew.Bean.Name, brew.Bean.Origin)
+			if brew.Bean.Roaster != nil {
+				roaster = brew.Bean.Roaster.Name
+			}
+		}
+
+		pours := make([]string, 0, len(brew.Pours))
+		for _, pour := range brew.Pours {
+			pours = append(pours, fmt.Sprintf("%dg@%ds", pour.WaterAmount, pour.TimeSeconds))
+		}
+
+		row := []string{
+			brew.CreatedAt.UTC().Format(time.RFC3339),
+			csvText(bean),
+			csvText(roaster),
+			csvText(brewMethodName(brew)),
+			csvInt(brew.CoffeeAmount),
+			csvInt(brew.WaterAmount),
+			csvFloat(brew.Ratio(), 2),
+			csvFloat(brew.TemperatureIn(profileprefs.TemperatureUnitCelsius), 1),
+			csvInt(brew.TimeSeconds),
+			csvInt(brew.Rating),
+			csvText(brew.TastingNotes),
+			strings.Join(pours, ";"),
+		}
+		for _, attr := range arabica.TastingAttributes {
+			row = append(row, csvInt(brew.Tasting.Score(attr.ID)))
+		}
+		var flavors []string
+		if brew.Tasting != nil {
+			flavors = brew.Tasting.Flavors
+		}
+		row = append(row, csvText(strings.Join(flavors, ";")))
+		var temperatureUnit string
+		if brew.Temperature > 0 {
+			temperatureUnit = "C"
+		}
+		row = append(row, temperatureUnit)
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvText defuses user text that a spreadsheet would otherwise evaluate as
+// a formula by prefixing it with an apostrophe.
+func csvText(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+// csvInt leaves unrecorded (zero) values blank so spreadsheets don't chart them.
+func csvInt(v int) string {
+	if v <= 0 {
+		return ""
+	}
+	return strconv.Itoa(v)
+}
+
+func csvFloat(v float64, prec int) string {
+	if v <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', prec, 64)
+}
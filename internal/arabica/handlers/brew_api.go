@@ -0,0 +1,82 @@
+package coffeehandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/arabica.social/arabica/internal/handlers"
+	"tangled.org/pdewey.com/atp"
+
+	"github.com/rs/zerolog/log"
+)
+
+// brewAPIResponse is the JSON body for GET /api/brews/{id}: the brew with
+// its references resolved, plus its identity and social counts.
+type brewAPIResponse struct {
+	*arabica.Brew
+	URI          string `json:"uri"`
+	CID          string `json:"cid,omitempty"`
+	AuthorDID    string `json:"author_did"`
+	LikeCount    int    `json:"like_count"`
+	CommentCount int    `json:"comment_count"`
+}
+
+// HandleBrewGetAPI serves GET /api/brews/{id}?owner= for integrators that
+// want a single brew as JSON. owner may be a handle or DID. It reads the same
+// sources as the brew page, and hidden records or blocked authors are a 404.
+func (h *Handlers) HandleBrewGetAPI(w http.ResponseWriter, r *http.Request) {
+	rkey := handlers.ValidateRKey(w, r.PathValue("id"))
+	if rkey == "" {
+		return
+	}
+
+	owner := r.URL.Query().Get("owner")
+	if owner == "" {
+		http.Error(w, "owner parameter required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	ownerDID, err := handlers.ResolveOwnerDID(ctx, owner)
+	if err != nil {
+		log.Warn().Err(err).Str("owner", owner).Msg("Failed to resolve owner for brew API")
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	brew, cid, err := h.loadPublicBrew(ctx, ownerDID, rkey, "brew_api")
+	if errors.Is(err, errBrewNotFound) {
+		http.Error(w, "Brew not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("did", ownerDID).Str("rkey", rkey).Msg("Failed to load brew for API")
+		http.Error(w, "Failed to load brew", http.StatusInternalServerError)
+		return
+	}
+
+	uri := atp.BuildATURI(ownerDID, arabica.NSIDBrew, rkey)
+	if cf := h.LoadContentFilter(ctx); cf != nil && cf.ShouldHide(uri, ownerDID) {
+		http.Error(w, "Brew not found", http.StatusNotFound)
+		return
+	}
+
+	resp := brewAPIResponse{
+		Brew:      brew,
+		URI:       uri,
+		CID:       cid,
+		AuthorDID: ownerDID,
+	}
+	if idx := h.FeedIndex(); idx != nil {
+		resp.LikeCount = idx.GetLikeCount(ctx, uri)
+		resp.CommentCount = idx.GetCommentCount(ctx, uri)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error().Err(err).Msg("Failed to encode brew API response")
+	}
+}
@@ -0,0 +1,85 @@
+package coffeehandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/arabica.social/arabica/internal/firehose"
+	"tangled.org/arabica.social/arabica/internal/moderation"
+	moderationsqlite "tangled.org/arabica.social/arabica/internal/moderation/sqlite"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleBrewGetAPI(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(t.TempDir()+"/test.db", time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, idx.Close()) })
+
+	ctx := context.Background()
+	did := "did:plc:brewapi"
+	upsert := func(collection, rkey string, record map[string]any) string {
+		record["$type"] = collection
+		record["createdAt"] = "2026-05-01T00:00:00Z"
+		raw, err := json.Marshal(record)
+		require.NoError(t, err)
+		require.NoError(t, idx.UpsertRecord(ctx, did, collection, rkey, "cid-"+rkey, raw, time.Now().Unix()))
+		return "at://" + did + "/" + collection + "/" + rkey
+	}
+	upsert(arabica.NSIDRoaster, "r1", map[string]any{"name": "Onyx"})
+	upsert(arabica.NSIDBean, "b1", map[string]any{"name": "Halo", "roasterRef": "at://" + did + "/" + arabica.NSIDRoaster + "/r1"})
+	brewURI := upsert(arabica.NSIDBrew, "w1", map[string]any{"beanRef": "at://" + did + "/" + arabica.NSIDBean + "/b1", "rating": 9})
+	hiddenURI := upsert(arabica.NSIDBrew, "w2", map[string]any{"rating": 2})
+	require.NoError(t, idx.UpsertLike(ctx, "did:plc:fan", "like1", brewURI))
+	require.NoError(t, idx.UpsertComment(ctx, "did:plc:fan", "comment1", brewURI, "", "cid-c", "Tasty", time.Now(), time.Time{}))
+
+	modStore := moderationsqlite.NewModerationStore(idx.DB())
+	require.NoError(t, modStore.HideRecord(ctx, moderation.HiddenRecord{ATURI: hiddenURI, HiddenAt: time.Now(), HiddenBy: "did:plc:mod"}))
+
+	tc := NewTestContext()
+	tc.Handler.SetFeedIndex(idx)
+	tc.Handler.SetWitnessCache(idx)
+	tc.Handler.SetModeration(nil, modStore)
+
+	serve := func(rkey, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/brews/"+rkey+query, nil)
+		req.SetPathValue("id", rkey)
+		rec := httptest.NewRecorder()
+		tc.Handler.HandleBrewGetAPI(rec, req)
+		return rec
+	}
+
+	t.Run("returns the brew with counts", func(t *testing.T) {
+		rec := serve("w1", "?owner="+did)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, brewURI, resp["uri"])
+		assert.Equal(t, "cid-w1", resp["cid"])
+		assert.Equal(t, did, resp["author_did"])
+		assert.Equal(t, "w1", resp["rkey"])
+		assert.EqualValues(t, 9, resp["rating"])
+		assert.EqualValues(t, 1, resp["like_count"])
+		assert.EqualValues(t, 1, resp["comment_count"])
+
+		bean, ok := resp["bean"].(map[string]any)
+		require.True(t, ok, "bean reference should be resolved")
+		assert.Equal(t, "Halo", bean["name"])
+	})
+
+	t.Run("missing owner", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, serve("w1", "").Code)
+	})
+
+	t.Run("hidden brew", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, serve("w2", "?owner="+did).Code)
+	})
+}
@@ -0,0 +1,75 @@
+package coffeehandlers
+
+import (
+	"net/http"
+	"strings"
+
+	coffeepages "tangled.org/arabica.social/arabica/internal/arabica/web/pages"
+	"tangled.org/pdewey.com/atp"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxComparedBrews caps how many brews one comparison loads. Each is a PDS
+// round trip, and the table stops being readable past a handful of columns.
+const maxComparedBrews = 4
+
+// HandleBrewCompare shows the signed-in user's brews side by side, picked by
+// a comma-separated ?ids= list of rkeys. A brew that can't be loaded gets an
+// error column rather than failing the page.
+func (h *Handlers) HandleBrewCompare(w http.ResponseWriter, r *http.Request) {
+	store, authenticated := h.GetArabicaStore(r)
+	if !authenticated {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return
+	}
+
+	rkeys := parseCompareIDs(r.URL.Query().Get("ids"))
+	if len(rkeys) < 2 {
+		http.Error(w, "Pick at least two brews to compare", http.StatusBadRequest)
+		return
+	}
+	if len(rkeys) > maxComparedBrews {
+		http.Error(w, "Too many brews to compare", http.StatusBadRequest)
+		return
+	}
+
+	columns := make([]coffeepages.BrewCompareColumn, len(rkeys))
+	for i, rkey := range rkeys {
+		columns[i].RKey = rkey
+		if !atp.ValidateRKey(rkey) {
+			columns[i].Error = "Invalid brew ID"
+			continue
+		}
+		brew, err := store.GetBrewByRKey(r.Context(), rkey)
+		if err != nil {
+			log.Warn().Err(err).Str("rkey", rkey).Msg("Failed to get brew for comparison")
+			columns[i].Error = "Brew not found"
+			continue
+		}
+		columns[i].Brew = brew
+	}
+
+	layoutData, _, _ := h.LayoutDataFromRequest(r, "Compare Brews")
+	props := coffeepages.BrewCompareProps{Columns: columns}
+	if err := coffeepages.BrewComparePage(layoutData, props).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		log.Error().Err(err).Msg("Failed to render brew comparison")
+	}
+}
+
+// parseCompareIDs splits the ids parameter, dropping blanks and repeats
+// while keeping the order the user gave.
+func parseCompareIDs(raw string) []string {
+	var rkeys []string
+	seen := make(map[string]bool)
+	for id := range strings.SplitSeq(raw, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		rkeys = append(rkeys, id)
+	}
+	return rkeys
+}
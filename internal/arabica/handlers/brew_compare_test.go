@@ -0,0 +1,63 @@
+package coffeehandlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleBrewCompare(t *testing.T) {
+	tests := []struct {
+		name       string
+		ids        string
+		wantStatus int
+		wantBody   []string
+	}{
+		{"missing brew gets an error column", "b1,gone,b2", http.StatusOK, []string{"Brew not found", "Light Ethiopia", "Dark Brazil", "brew-compare-differs"}},
+		{"duplicates collapse below two", "b1,b1", http.StatusBadRequest, nil},
+		{"too many", "b1,b2,b3,b4,b5", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := NewTestContext()
+			tc.Handler.SetStoreOverrideForTest(tc.MockStore)
+			tc.MockStore.GetBrewByRKeyFunc = func(ctx context.Context, rkey string) (*arabica.Brew, error) {
+				switch rkey {
+				case "b1":
+					return &arabica.Brew{RKey: rkey, Bean: &arabica.Bean{Name: "Light Ethiopia"}, Rating: 8}, nil
+				case "b2":
+					return &arabica.Brew{RKey: rkey, Bean: &arabica.Bean{Name: "Dark Brazil"}, Rating: 6}, nil
+				}
+				return nil, errors.New("record not found")
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/brews/compare?ids="+tt.ids, nil)
+			req = req.WithContext(atpmiddleware.ContextWithAuth(req.Context(), "did:plc:test123456789", "test-session-id"))
+			rec := httptest.NewRecorder()
+			tc.Handler.HandleBrewCompare(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			for _, want := range tt.wantBody {
+				assert.Contains(t, rec.Body.String(), want)
+			}
+		})
+	}
+}
+
+func TestHandleBrewCompare_RequiresAuth(t *testing.T) {
+	tc := NewTestContext()
+	rec := httptest.NewRecorder()
+	tc.Handler.HandleBrewCompare(rec, NewUnauthenticatedRequest(http.MethodGet, "/brews/compare?ids=b1,b2"))
+
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "/login", rec.Header().Get("Location"))
+}
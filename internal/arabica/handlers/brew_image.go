@@ -0,0 +1,97 @@
+package coffeehandlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	arabicastore "tangled.org/arabica.social/arabica/internal/arabica/store"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	errBrewImageTooLarge = errors.New("brew image too large")
+	errBrewImageType     = errors.New("unsupported brew image type")
+)
+
+// parseBrewForm parses a brew create/update body. The form is sent as
+// multipart when a photo is attached and urlencoded otherwise.
+func parseBrewForm(r *http.Request) error {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.ParseMultipartForm(arabica.MaxBrewImageBytes + 64*1024)
+	}
+	return r.ParseForm()
+}
+
+// readBrewImage returns the contents of the optional "image" upload along
+// with its sniffed MIME type. The client-supplied Content-Type is ignored.
+// Returns nil data when no photo was attached.
+func readBrewImage(r *http.Request) ([]byte, string, error) {
+	if r.MultipartForm == nil {
+		return nil, "", nil
+	}
+	file, header, err := r.FormFile("image")
+	if errors.Is(err, http.ErrMissingFile) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+
+	if header.Size > arabica.MaxBrewImageBytes {
+		return nil, "", errBrewImageTooLarge
+	}
+	data, err := io.ReadAll(io.LimitReader(file, arabica.MaxBrewImageBytes+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > arabica.MaxBrewImageBytes {
+		return nil, "", errBrewImageTooLarge
+	}
+	if len(data) == 0 {
+		return nil, "", nil
+	}
+	mimeType := http.DetectContentType(data)
+	if !arabica.IsAllowedBrewImageType(mimeType) {
+		return nil, "", errBrewImageType
+	}
+	return data, mimeType, nil
+}
+
+// uploadBrewImage stores the photo attached to a brew form, if any. On
+// failure it writes the error response and returns ok=false.
+func uploadBrewImage(w http.ResponseWriter, r *http.Request, store arabicastore.Store) (image *arabica.BrewImage, ok bool) {
+	data, mimeType, err := readBrewImage(r)
+	switch {
+	case errors.Is(err, errBrewImageTooLarge):
+		http.Error(w, "Photo must be 1 MB or smaller", http.StatusRequestEntityTooLarge)
+		return nil, false
+	case errors.Is(err, errBrewImageType):
+		http.Error(w, "Photo must be a JPEG, PNG, or WebP image", http.StatusBadRequest)
+		return nil, false
+	case err != nil:
+		log.Warn().Err(err).Msg("Failed to read brew photo")
+		http.Error(w, "Failed to read photo", http.StatusBadRequest)
+		return nil, false
+	case data == nil:
+		return nil, true
+	}
+
+	blob, err := store.UploadBlob(r.Context(), data, mimeType)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to upload brew photo")
+		http.Error(w, "Failed to upload photo", http.StatusBadGateway)
+		return nil, false
+	}
+	image = arabica.BrewImageFromBlob(blob)
+	if image == nil {
+		log.Error().Interface("blob", blob).Msg("Uploaded brew photo returned no CID")
+		http.Error(w, "Failed to upload photo", http.StatusBadGateway)
+		return nil, false
+	}
+	return image, true
+}
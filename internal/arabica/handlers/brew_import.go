@@ -0,0 +1,277 @@
+package coffeehandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	arabicastore "tangled.org/arabica.social/arabica/internal/arabica/store"
+	coffee "tangled.org/arabica.social/arabica/internal/arabica/web/components"
+	"tangled.org/arabica.social/arabica/internal/handlers"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxBrewImportBytes caps an uploaded export file. An exported brew is well
+// under a kilobyte, so this still covers years of logging.
+const maxBrewImportBytes = 1 << 20
+
+// maxBrewImportErrors bounds the per-brew messages returned to the client so
+// a badly mangled file doesn't produce an enormous response.
+const maxBrewImportErrors = 20
+
+// brewImportResult summarizes an import run.
+type brewImportResult struct {
+	Imported int      `json:"imported"`
+	Skipped  int      `json:"skipped"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+func (res *brewImportResult) skip(index int, format string, args ...any) {
+	res.Skipped++
+	if len(res.Errors) < maxBrewImportErrors {
+		res.Errors = append(res.Errors, fmt.Sprintf("brew %d: ", index+1)+fmt.Sprintf(format, args...))
+	}
+}
+
+// Import brews from a JSON file produced by HandleBrewExport
+func (h *Handlers) HandleBrewImport(w http.ResponseWriter, r *http.Request) {
+	store, authenticated := h.GetArabicaStore(r)
+	if !authenticated {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBrewImportBytes)
+	if err := r.ParseMultipartForm(maxBrewImportBytes); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Import file is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "An export file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	var brews []*arabica.Brew
+	if err := json.NewDecoder(file).Decode(&brews); err != nil {
+		http.Error(w, "Invalid export file", http.StatusBadRequest)
+		return
+	}
+
+	createMissing := r.FormValue("create_missing") == "true"
+	result, err := importBrews(r.Context(), store, brews, createMissing)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load records for brew import")
+		handlers.HandleStoreError(w, err, "Failed to import brews")
+		return
+	}
+
+	if result.Imported > 0 {
+		h.InvalidateFeedCache()
+	}
+
+	log.Info().Int("imported", result.Imported).Int("skipped", result.Skipped).Msg("Brew import finished")
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := coffee.BrewImportResult(result.Imported, result.Skipped, result.Errors).Render(r.Context(), w); err != nil {
+			log.Error().Err(err).Msg("Failed to render brew import result")
+		}
+		return
+	}
+	handlers.WriteJSON(w, result, "brew import")
+}
+
+// brewImporter maps exported bean, grinder and brewer names to the rkeys of
+// the importing user's records, optionally creating records that are missing.
+type brewImporter struct {
+	store         arabicastore.Store
+	createMissing bool
+	beans         map[string]string
+	grinders      map[string]string
+	brewers       map[string]string
+}
+
+// importBrews recreates each exported brew in store. Brews whose bean cannot
+// be resolved, or which fail validation, are skipped; an unresolved grinder
+// or brewer only drops that reference. Recipe links are not carried over
+// since the recipe rkey may not exist for the importing user. The returned
+// error is reserved for failures loading the user's existing records.
+func importBrews(ctx context.Context, store arabicastore.Store, brews []*arabica.Brew, createMissing bool) (brewImportResult, error) {
+	imp := &brewImporter{
+		store:         store,
+		createMissing: createMissing,
+		beans:         map[string]string{},
+		grinders:      map[string]string{},
+		brewers:       map[string]string{},
+	}
+
+	beans, err := store.ListBeans(ctx)
+	if err != nil {
+		return brewImportResult{}, err
+	}
+	for _, b := range beans {
+		imp.beans[importNameKey(b.Name)] = b.RKey
+	}
+	grinders, err := listGrinders(ctx, store)
+	if err != nil {
+		return brewImportResult{}, err
+	}
+	for _, g := range grinders {
+		imp.grinders[importNameKey(g.Name)] = g.RKey
+	}
+	brewers, err := listBrewers(ctx, store)
+	if err != nil {
+		return brewImportResult{}, err
+	}
+	for _, b := range brewers {
+		imp.brewers[importNameKey(b.Name)] = b.RKey
+	}
+
+	var result brewImportResult
+	for i, brew := range brews {
+		if brew == nil {
+			result.skip(i, "empty entry")
+			continue
+		}
+		beanRKey, err := imp.beanRKey(ctx, brew.Bean)
+		if err != nil {
+			result.skip(i, "%v", err)
+			continue
+		}
+		req := brewImportRequest(brew, beanRKey, imp.grinderRKey(ctx, brew.GrinderObj), imp.brewerRKey(ctx, brew.BrewerObj))
+		if err := req.Validate(); err != nil {
+			result.skip(i, "%v", err)
+			continue
+		}
+		if _, err := store.CreateBrew(ctx, req, 1); err != nil {
+			log.Warn().Err(err).Int("index", i).Msg("Failed to create imported brew")
+			result.skip(i, "failed to save brew")
+			continue
+		}
+		result.Imported++
+	}
+	return result, nil
+}
+
+// brewImportRequest builds a create request from an exported brew, keeping
+// its original timestamp and pointing at the resolved rkeys.
+func brewImportRequest(brew *arabica.Brew, beanRKey, grinderRKey, brewerRKey string) *arabica.CreateBrewRequest {
+	method, methodOther := brew.CanonicalMethod()
+	req := &arabica.CreateBrewRequest{
+		BeanRKey:       beanRKey,
+		GrinderRKey:    grinderRKey,
+		BrewerRKey:     brewerRKey,
+		Method:         string(method),
+		MethodOther:    methodOther,
+		Temperature:    brew.Temperature,
+		WaterAmount:    brew.WaterAmount,
+		CoffeeAmount:   brew.CoffeeAmount,
+		TimeSeconds:    brew.TimeSeconds,
+		GrindSize:      brew.GrindSize,
+		TastingNotes:   brew.TastingNotes,
+		Rating:         brew.Rating,
+		TDS:            brew.TDS,
+		Tags:           brew.Tags,
+		Draft:          brew.Draft,
+		Tasting:        brew.Tasting,
+		EspressoParams: brew.EspressoParams,
+		PouroverParams: brew.PouroverParams,
+		CreatedAt:      brew.CreatedAt,
+	}
+	for _, p := range brew.Pours {
+		if p == nil {
+			continue
+		}
+		req.Pours = append(req.Pours, arabica.CreatePourData{WaterAmount: p.WaterAmount, TimeSeconds: p.TimeSeconds})
+	}
+	return req
+}
+
+func (imp *brewImporter) beanRKey(ctx context.Context, bean *arabica.Bean) (string, error) {
+	if bean == nil || strings.TrimSpace(bean.Name) == "" {
+		return "", errors.New("no bean recorded")
+	}
+	key := importNameKey(bean.Name)
+	if rkey, ok := imp.beans[key]; ok {
+		return rkey, nil
+	}
+	if !imp.createMissing {
+		return "", fmt.Errorf("bean %q not found", bean.Name)
+	}
+	req := &arabica.CreateBeanRequest{
+		Name:       bean.Name,
+		Origin:     bean.Origin,
+		Variety:    bean.Variety,
+		RoastLevel: bean.RoastLevel,
+		Process:    bean.Process,
+	}
+	if err := req.Validate(); err != nil {
+		return "", fmt.Errorf("bean %q: %w", bean.Name, err)
+	}
+	created, err := imp.store.CreateBean(ctx, req)
+	if err != nil {
+		log.Warn().Err(err).Str("bean", bean.Name).Msg("Failed to create bean during brew import")
+		return "", fmt.Errorf("could not create bean %q", bean.Name)
+	}
+	imp.beans[key] = created.RKey
+	return created.RKey, nil
+}
+
+func (imp *brewImporter) grinderRKey(ctx context.Context, grinder *arabica.Grinder) string {
+	if grinder == nil || strings.TrimSpace(grinder.Name) == "" {
+		return ""
+	}
+	key := importNameKey(grinder.Name)
+	if rkey, ok := imp.grinders[key]; ok || !imp.createMissing {
+		return rkey
+	}
+	req := &arabica.CreateGrinderRequest{Name: grinder.Name, GrinderType: grinder.GrinderType, BurrType: grinder.BurrType}
+	if req.Validate() != nil {
+		return ""
+	}
+	created, err := imp.store.CreateGrinder(ctx, req)
+	if err != nil {
+		log.Warn().Err(err).Str("grinder", grinder.Name).Msg("Failed to create grinder during brew import")
+		return ""
+	}
+	imp.grinders[key] = created.RKey
+	return created.RKey
+}
+
+func (imp *brewImporter) brewerRKey(ctx context.Context, brewer *arabica.Brewer) string {
+	if brewer == nil || strings.TrimSpace(brewer.Name) == "" {
+		return ""
+	}
+	key := importNameKey(brewer.Name)
+	if rkey, ok := imp.brewers[key]; ok || !imp.createMissing {
+		return rkey
+	}
+	req := &arabica.CreateBrewerRequest{Name: brewer.Name, BrewerType: brewer.BrewerType, Description: brewer.Description}
+	if req.Validate() != nil {
+		return ""
+	}
+	created, err := imp.store.CreateBrewer(ctx, req)
+	if err != nil {
+		log.Warn().Err(err).Str("brewer", brewer.Name).Msg("Failed to create brewer during brew import")
+		return ""
+	}
+	imp.brewers[key] = created.RKey
+	return created.RKey
+}
+
+// importNameKey normalizes a record name for matching across accounts.
+func importNameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
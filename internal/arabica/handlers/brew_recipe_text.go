@@ -0,0 +1,91 @@
+package coffeehandlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/arabica.social/arabica/internal/profileprefs"
+	"tangled.org/arabica.social/arabica/internal/web/bff"
+
+	"github.com/rs/zerolog/log"
+)
+
+// HandleBrewRecipeText serves GET /brews/{id}/recipe.txt?owner= with the
+// brew written out as a plain-text recipe, for pasting into chats and
+// forums. The brew is loaded the same way the brew page loads it.
+func (h *Handlers) HandleBrewRecipeText(w http.ResponseWriter, r *http.Request) {
+	loaded := h.LoadEntityView(w, r, h.brewViewConfig())
+	if loaded == nil {
+		return
+	}
+	brew := loaded.Record.(*arabica.Brew)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err := io.WriteString(w, brewRecipeText(brew)); err != nil {
+		log.Warn().Err(err).Str("rkey", brew.RKey).Msg("Failed to write brew recipe text")
+	}
+}
+
+// brewRecipeText formats a brew as a plain-text recipe. Amounts stay in
+// grams and temperatures in the unit they were recorded in, since the
+// reader's preferences aren't known. Lines for unrecorded values are left
+// out.
+func brewRecipeText(brew *arabica.Brew) string {
+	var b strings.Builder
+	line := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%-12s %s\n", label+":", value)
+		}
+	}
+
+	title := brewBeanSummary(brew)
+	if title == "" {
+		title = "Coffee Brew"
+	}
+	b.WriteString(title + "\n")
+	b.WriteString(strings.Repeat("=", len([]rune(title))) + "\n\n")
+
+	if brew.Bean != nil {
+		line("Bean", brew.Bean.Name)
+		if brew.Bean.Roaster != nil {
+			line("Roaster", brew.Bean.Roaster.Name)
+		}
+		line("Origin", brew.Bean.Origin)
+		line("Roast", brew.Bean.RoastLevel)
+	}
+	line("Method", brew.MethodLabel())
+	if brew.BrewerObj != nil {
+		line("Brewer", brew.BrewerObj.Name)
+	}
+	line("Dose", bff.FormatWeight(brew.CoffeeAmountIn(profileprefs.UnitSystemMetric), profileprefs.UnitSystemMetric))
+	line("Water", bff.FormatWeight(brew.WaterAmountIn(profileprefs.UnitSystemMetric), profileprefs.UnitSystemMetric))
+	line("Ratio", bff.FormatRatio(brew.Ratio()))
+	if brew.Temperature > 0 {
+		line("Temperature", bff.FormatTemp(brew.Temperature))
+	}
+	grind := brew.GrindSize
+	if grind != "" && brew.GrinderObj != nil && brew.GrinderObj.Name != "" {
+		grind += " (" + brew.GrinderObj.Name + ")"
+	}
+	line("Grind", grind)
+	if brew.TimeSeconds > 0 {
+		line("Total time", bff.FormatTime(brew.TimeSeconds))
+	}
+
+	if schedule := arabica.NewPourSchedule(brew.Pours); schedule != nil {
+		b.WriteString("\nPours\n")
+		for _, step := range schedule.Steps {
+			fmt.Fprintf(&b, "  %d:%02d  +%dg  (%dg total)\n",
+				step.TimeSeconds/60, step.TimeSeconds%60, step.WaterAmount, step.CumulativeWater)
+		}
+	}
+
+	if notes := strings.TrimSpace(brew.TastingNotes); notes != "" {
+		b.WriteString("\nNotes\n" + notes + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package coffeehandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/arabica.social/arabica/internal/firehose"
+	"tangled.org/arabica.social/arabica/internal/moderation"
+	moderationsqlite "tangled.org/arabica.social/arabica/internal/moderation/sqlite"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrewRecipeText(t *testing.T) {
+	tests := []struct {
+		name string
+		brew *arabica.Brew
+		want string
+	}{
+		{
+			name: "full recipe",
+			brew: &arabica.Brew{
+				Method:       string(arabica.BrewMethodV60),
+				CoffeeAmount: 15,
+				WaterAmount:  250,
+				Temperature:  93,
+				GrindSize:    "18 clicks",
+				TimeSeconds:  210,
+				TastingNotes: "Bright, peach.\n",
+				Bean:         &arabica.Bean{Name: "Halo", Origin: "Ethiopia", Roaster: &arabica.Roaster{Name: "Onyx"}},
+				GrinderObj:   &arabica.Grinder{Name: "Comandante"},
+				Pours: []*arabica.Pour{
+					{WaterAmount: 150, TimeSeconds: 45},
+					{WaterAmount: 50, TimeSeconds: 0},
+					{WaterAmount: 50, TimeSeconds: 90},
+				},
+			},
+			want: "Halo from Onyx\n" +
+				"==============\n\n" +
+				"Bean:        Halo\n" +
+				"Roaster:     Onyx\n" +
+				"Origin:      Ethiopia\n" +
+				"Method:      V60\n" +
+				"Dose:        15g\n" +
+				"Water:       250g\n" +
+				"Ratio:       1:16.7\n" +
+				"Temperature: 93.0°C\n" +
+				"Grind:       18 clicks (Comandante)\n" +
+				"Total time:  3m 30s\n" +
+				"\nPours\n" +
+				"  0:00  +50g  (50g total)\n" +
+				"  0:45  +150g  (200g total)\n" +
+				"  1:30  +50g  (250g total)\n" +
+				"\nNotes\nBright, peach.\n",
+		},
+		{
+			name: "bare brew",
+			brew: &arabica.Brew{CoffeeAmount: 18},
+			want: "Coffee Brew\n===========\n\nDose:        18g\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, brewRecipeText(tt.brew))
+		})
+	}
+}
+
+func TestHandleBrewRecipeText(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(t.TempDir()+"/test.db", time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, idx.Close()) })
+
+	ctx := context.Background()
+	const did = "did:plc:recipetext"
+	upsert := func(collection, rkey string, record map[string]any) string {
+		record["$type"] = collection
+		record["createdAt"] = "2026-05-01T00:00:00Z"
+		raw, err := json.Marshal(record)
+		require.NoError(t, err)
+		require.NoError(t, idx.UpsertRecord(ctx, did, collection, rkey, "cid-"+rkey, raw, time.Now().Unix()))
+		return "at://" + did + "/" + collection + "/" + rkey
+	}
+	beanRef := upsert(arabica.NSIDBean, "b1", map[string]any{"name": "Halo"})
+	upsert(arabica.NSIDBrew, "w1", map[string]any{"beanRef": beanRef, "coffeeAmount": 15, "waterAmount": 250})
+	hiddenURI := upsert(arabica.NSIDBrew, "w2", map[string]any{"beanRef": beanRef})
+	upsert(arabica.NSIDBrew, "w3", map[string]any{"beanRef": beanRef, "coffeeAmount": 15, "draft": true})
+
+	modStore := moderationsqlite.NewModerationStore(idx.DB())
+	require.NoError(t, modStore.HideRecord(ctx, moderation.HiddenRecord{ATURI: hiddenURI, HiddenAt: time.Now(), HiddenBy: "did:plc:mod"}))
+
+	tc := NewTestContext()
+	tc.Handler.SetFeedIndex(idx)
+	tc.Handler.SetWitnessCache(idx)
+	tc.Handler.SetModeration(nil, modStore)
+
+	tests := []struct {
+		name       string
+		rkey       string
+		query      string
+		wantStatus int
+		wantBody   string
+	}{
+		{"brew", "w1", "?owner=" + did, http.StatusOK, "Ratio:       1:16.7\n"},
+		{"missing owner", "w1", "", http.StatusBadRequest, "owner required"},
+		{"hidden brew", "w2", "?owner=" + did, http.StatusNotFound, "Record not found"},
+		{"someone else's draft", "w3", "?owner=" + did, http.StatusNotFound, "Record not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/brews/"+tt.rkey+"/recipe.txt"+tt.query, nil)
+			req.SetPathValue("id", tt.rkey)
+			rec := httptest.NewRecorder()
+			tc.Handler.HandleBrewRecipeText(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+				assert.Contains(t, rec.Body.String(), "Halo\n====\n")
+			}
+		})
+	}
+}
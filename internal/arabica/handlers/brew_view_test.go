@@ -0,0 +1,173 @@
+package coffeehandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/arabica.social/arabica/internal/atproto"
+	"tangled.org/arabica.social/arabica/internal/firehose"
+	"tangled.org/arabica.social/arabica/internal/moderation"
+	moderationsqlite "tangled.org/arabica.social/arabica/internal/moderation/sqlite"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+)
+
+func TestHandleBrewView_HiddenRecord(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(t.TempDir()+"/test.db", time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, idx.Close()) })
+
+	ctx := context.Background()
+	const owner = "did:plc:owner"
+	for _, did := range []string{owner, "did:plc:mod", "did:plc:viewer"} {
+		idx.StoreProfile(ctx, did, &atproto.Profile{DID: did, Handle: did[8:] + ".test"})
+	}
+	upsert := func(collection, rkey string, record map[string]any) {
+		record["$type"] = collection
+		record["createdAt"] = "2026-05-01T00:00:00Z"
+		raw, err := json.Marshal(record)
+		require.NoError(t, err)
+		require.NoError(t, idx.UpsertRecord(ctx, owner, collection, rkey, "cid-"+rkey, raw, time.Now().Unix()))
+	}
+	upsert(arabica.NSIDBean, "b1", map[string]any{"name": "Halo"})
+	upsert(arabica.NSIDBrew, "w1", map[string]any{"beanRef": "at://" + owner + "/" + arabica.NSIDBean + "/b1", "tastingNotes": "Secret notes"})
+
+	configPath := filepath.Join(t.TempDir(), "moderators.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{
+		"roles": {"moderator": {"permissions": ["hide_record"]}},
+		"users": [{"did": "did:plc:mod", "role": "moderator"}]
+	}`), 0o644))
+	modSvc, err := moderation.NewService(configPath)
+	require.NoError(t, err)
+	modStore := moderationsqlite.NewModerationStore(idx.DB())
+	require.NoError(t, modStore.HideRecord(ctx, moderation.HiddenRecord{
+		ATURI:    "at://" + owner + "/" + arabica.NSIDBrew + "/w1",
+		HiddenAt: time.Now(),
+		HiddenBy: "did:plc:mod",
+	}))
+
+	tc := NewTestContext()
+	tc.Handler.SetFeedIndex(idx)
+	tc.Handler.SetWitnessCache(idx)
+	tc.Handler.SetModeration(modSvc, modStore)
+
+	tests := []struct {
+		name       string
+		viewer     string
+		wantStatus int
+	}{
+		{"anonymous", "", http.StatusNotFound},
+		{"other user", "did:plc:viewer", http.StatusNotFound},
+		{"moderator", "did:plc:mod", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/brews/w1?owner="+owner, nil)
+			req.SetPathValue("id", "w1")
+			if tt.viewer != "" {
+				req = req.WithContext(atpmiddleware.ContextWithAuth(req.Context(), tt.viewer, "sess"))
+			}
+			rec := httptest.NewRecorder()
+			tc.Handler.HandleBrewView(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, "noindex", rec.Header().Get("X-Robots-Tag"))
+			assert.Contains(t, rec.Body.String(), `<meta name="robots" content="noindex">`)
+			if tt.wantStatus == http.StatusNotFound {
+				assert.NotContains(t, rec.Body.String(), "Secret notes")
+			} else {
+				assert.Contains(t, rec.Body.String(), "Secret notes")
+			}
+		})
+	}
+}
+
+func TestHandleBrewView_ShortShareURL(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(t.TempDir()+"/test.db", time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, idx.Close()) })
+
+	ctx := context.Background()
+	const owner = "did:plc:owner"
+	idx.StoreProfile(ctx, owner, &atproto.Profile{DID: owner, Handle: "owner.test"})
+	upsert := func(collection, rkey string, record map[string]any) {
+		record["$type"] = collection
+		record["createdAt"] = "2026-05-01T00:00:00Z"
+		raw, err := json.Marshal(record)
+		require.NoError(t, err)
+		require.NoError(t, idx.UpsertRecord(ctx, owner, collection, rkey, "cid-"+rkey, raw, time.Now().Unix()))
+	}
+	upsert(arabica.NSIDBean, "b1", map[string]any{"name": "Halo"})
+	upsert(arabica.NSIDBrew, "w1", map[string]any{"beanRef": "at://" + owner + "/" + arabica.NSIDBean + "/b1"})
+
+	tc := NewTestContext()
+	tc.Handler.SetFeedIndex(idx)
+	tc.Handler.SetWitnessCache(idx)
+
+	// Reached by DID, the brew is still shared under the owner's handle.
+	for _, path := range []string{"/brews/" + owner + "/w1", "/b/" + owner + "/w1"} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path+"?owner="+owner, nil)
+			req.SetPathValue("id", "w1")
+			rec := httptest.NewRecorder()
+			tc.Handler.HandleBrewView(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			body := rec.Body.String()
+			assert.Contains(t, body, `data-share-url="/b/owner.test/w1"`)
+			assert.Contains(t, body, `/b/owner.test/w1/og-image`)
+			assert.NotContains(t, body, "/brews/"+owner+"/w1")
+		})
+	}
+}
+
+func TestHandleBrewView_DraftHiddenFromOthers(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(t.TempDir()+"/test.db", time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, idx.Close()) })
+
+	ctx := context.Background()
+	const owner = "did:plc:owner"
+	idx.StoreProfile(ctx, owner, &atproto.Profile{DID: owner, Handle: "owner.test"})
+	upsert := func(collection, rkey string, record map[string]any) {
+		record["$type"] = collection
+		record["createdAt"] = "2026-05-01T00:00:00Z"
+		raw, err := json.Marshal(record)
+		require.NoError(t, err)
+		require.NoError(t, idx.UpsertRecord(ctx, owner, collection, rkey, "cid-"+rkey, raw, time.Now().Unix()))
+	}
+	upsert(arabica.NSIDBean, "b1", map[string]any{"name": "Halo"})
+	upsert(arabica.NSIDBrew, "w1", map[string]any{
+		"beanRef":      "at://" + owner + "/" + arabica.NSIDBean + "/b1",
+		"tastingNotes": "Unfinished notes",
+		"draft":        true,
+	})
+
+	tc := NewTestContext()
+	tc.Handler.SetFeedIndex(idx)
+	tc.Handler.SetWitnessCache(idx)
+
+	for _, viewer := range []string{"", "did:plc:viewer"} {
+		t.Run("viewer="+viewer, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/brews/w1?owner="+owner, nil)
+			req.SetPathValue("id", "w1")
+			if viewer != "" {
+				req = req.WithContext(atpmiddleware.ContextWithAuth(req.Context(), viewer, "sess"))
+			}
+			rec := httptest.NewRecorder()
+			tc.Handler.HandleBrewView(rec, req)
+
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+			assert.NotContains(t, rec.Body.String(), "Unfinished notes")
+		})
+	}
+}
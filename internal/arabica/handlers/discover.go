@@ -0,0 +1,69 @@
+package coffeehandlers
+
+import (
+	"net/http"
+
+	coffeepages "tangled.org/arabica.social/arabica/internal/arabica/web/pages"
+	"tangled.org/arabica.social/arabica/internal/firehose"
+	"tangled.org/pdewey.com/atp"
+
+	"github.com/rs/zerolog/log"
+)
+
+// HandleDiscoverRoasters lists the roasters most users have logged beans
+// from recently, each linking to its community beans page.
+func (h *Handlers) HandleDiscoverRoasters(w http.ResponseWriter, r *http.Request) {
+	_, authenticated := h.GetArabicaStore(r)
+	if !authenticated {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return
+	}
+	if h.FeedIndex() == nil {
+		http.Error(w, "Roaster discovery is unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	activity, err := h.FeedIndex().ActiveRoasters(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list active roasters")
+		http.Error(w, "Failed to load roasters", http.StatusInternalServerError)
+		return
+	}
+
+	dids := make([]string, 0, len(activity))
+	for _, a := range activity {
+		if uri, err := atp.ParseATURI(a.RoasterURI); err == nil {
+			dids = append(dids, uri.DID)
+		}
+	}
+	profiles := h.FeedIndex().GetProfiles(r.Context(), dids)
+
+	entries := make([]coffeepages.DiscoverRoasterEntry, 0, len(activity))
+	for _, a := range activity {
+		uri, err := atp.ParseATURI(a.RoasterURI)
+		if err != nil {
+			continue
+		}
+		owner := uri.DID
+		if p := profiles[uri.DID]; p != nil && p.Handle != "" {
+			owner = p.Handle
+		}
+		entries = append(entries, coffeepages.DiscoverRoasterEntry{
+			Name:       a.Name,
+			BeansURL:   "/roasters/" + owner + "/" + uri.RKey + "/beans",
+			UserCount:  a.UserCount,
+			BeanCount:  a.BeanCount,
+			LastBeanAt: a.LastBeanAt,
+		})
+	}
+
+	layoutData, _, _ := h.LayoutDataFromRequest(r, "Active roasters")
+	props := coffeepages.DiscoverRoastersProps{
+		Roasters:   entries,
+		WindowDays: int(firehose.ActiveRoasterWindow.Hours() / 24),
+	}
+	if err := coffeepages.DiscoverRoasters(layoutData, props).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		log.Error().Err(err).Msg("Failed to render roaster discovery page")
+	}
+}
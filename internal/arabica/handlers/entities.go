@@ -238,6 +238,11 @@ func (h *Handlers) HandleBeanCreate(w http.ResponseWriter, r *http.Request) {
 			Rating:      handlers.ParseOptionalInt(r.FormValue("rating")),
 			Closed:      r.FormValue("closed") == "true",
 			SourceRef:   r.FormValue("source_ref"),
+
+			RemainingGrams: handlers.ParseOptionalInt(r.FormValue("remaining_grams")),
+		}
+		if bagSize := handlers.ParseOptionalInt(r.FormValue("bag_size_grams")); bagSize != nil {
+			req.BagSizeGrams = *bagSize
 		}
 		log.Debug().
 			Str("name", req.Name).
@@ -346,7 +351,7 @@ func (h *Handlers) HandleRoasterCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	handlers.RecordCRUDWrite(
-		w, r, store, arabica.NSIDRoaster, "roaster", "", decodeRoasterCreateForm,
+		w, r, store, arabica.NSIDRoaster, "roaster", "", "", decodeRoasterCreateForm,
 		func(req *arabica.CreateRoasterRequest) *arabica.Roaster {
 			return roasterFromCreate(req, time.Now())
 		},
@@ -365,7 +370,7 @@ func (h *Handlers) HandleManage(w http.ResponseWriter, r *http.Request) {
 
 // HandleMyCoffee renders the unified My Coffee page (replaces both /brews and /manage)
 func (h *Handlers) HandleMyCoffee(w http.ResponseWriter, r *http.Request) {
-	_, authenticated := h.GetArabicaStore(r)
+	store, authenticated := h.GetArabicaStore(r)
 	if !authenticated {
 		http.Redirect(w, r, "/login", http.StatusFound)
 		return
@@ -373,7 +378,18 @@ func (h *Handlers) HandleMyCoffee(w http.ResponseWriter, r *http.Request) {
 
 	layoutData, _, _ := h.LayoutDataFromRequest(r, "My Coffee")
 
-	if err := coffeepages.MyCoffee(layoutData, coffeepages.MyCoffeeProps{}).Render(r.Context(), w); err != nil {
+	// The bean filter is a convenience; the page still renders without it.
+	beans, err := store.ListBeans(r.Context())
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to fetch beans for brew filters")
+	}
+
+	props := coffeepages.MyCoffeeProps{
+		CrosspostFailed: r.URL.Query().Get("crosspost") == "failed",
+		Filter:          parseBrewFilter(r.URL.Query()),
+		Beans:           beans,
+	}
+	if err := coffeepages.MyCoffee(layoutData, props).Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render page", http.StatusInternalServerError)
 		log.Error().Err(err).Msg("Failed to render my coffee page")
 	}
@@ -618,6 +634,14 @@ func (h *Handlers) HandleBeanUpdate(w http.ResponseWriter, r *http.Request) {
 			Rating:      handlers.ParseOptionalInt(r.FormValue("rating")),
 			Closed:      r.FormValue("closed") == "true",
 			SourceRef:   r.FormValue("source_ref"),
+
+			RemainingGrams: handlers.ParseOptionalInt(r.FormValue("remaining_grams")),
+			// The edit form always sends the field, so blanking it means
+			// the user stopped tracking inventory
+			ClearRemainingGrams: r.Form.Has("remaining_grams") && strings.TrimSpace(r.FormValue("remaining_grams")) == "",
+		}
+		if bagSize := handlers.ParseOptionalInt(r.FormValue("bag_size_grams")); bagSize != nil {
+			req.BagSizeGrams = *bagSize
 		}
 		log.Debug().
 			Str("rkey", rkey).
@@ -662,6 +686,7 @@ func (h *Handlers) HandleBeanUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	req.SwapCID = handlers.SwapCID(r)
 	if err := store.UpdateBeanByRKey(r.Context(), rkey, &req); err != nil {
 		log.Error().Err(err).Str("rkey", rkey).Msg("Failed to update bean")
 		handlers.HandleStoreError(w, err, "Failed to update bean")
@@ -670,8 +695,8 @@ func (h *Handlers) HandleBeanUpdate(w http.ResponseWriter, r *http.Request) {
 
 	bean, err := store.GetBeanByRKey(r.Context(), rkey)
 	if err != nil {
-		http.Error(w, "Failed to fetch updated bean", http.StatusInternalServerError)
 		log.Error().Err(err).Str("rkey", rkey).Msg("Failed to get bean after update")
+		handlers.HandleStoreError(w, err, "Failed to fetch updated bean")
 		return
 	}
 
@@ -705,7 +730,7 @@ func (h *Handlers) HandleRoasterUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 	createdAt := handlers.ExistingCreatedAt(r.Context(), store, arabica.NSIDRoaster, rkey)
 	handlers.RecordCRUDWrite(
-		w, r, store, arabica.NSIDRoaster, "roaster", rkey, decodeRoasterUpdateForm,
+		w, r, store, arabica.NSIDRoaster, "roaster", rkey, handlers.SwapCID(r), decodeRoasterUpdateForm,
 		func(req *arabica.UpdateRoasterRequest) *arabica.Roaster {
 			m := roasterFromUpdate(req, createdAt)
 			m.RKey = rkey
@@ -729,12 +754,13 @@ func (h *Handlers) HandleRoasterDelete(w http.ResponseWriter, r *http.Request) {
 }
 
 // Grinder CRUD handlers
-func grinderFormDecoder(r *http.Request) arabica.CreateGrinderRequest {
+func grinderFormDecoder(r *http.Request) (arabica.CreateGrinderRequest, error) {
+	presets, err := arabica.ParseGrindPresets(r.FormValue("grind_settings"))
 	return arabica.CreateGrinderRequest{
 		Name: r.FormValue("name"), GrinderType: r.FormValue("grinder_type"),
 		BurrType: r.FormValue("burr_type"), Notes: r.FormValue("notes"),
-		Link: r.FormValue("link"), SourceRef: r.FormValue("source_ref"),
-	}
+		Link: r.FormValue("link"), GrindSettings: presets, SourceRef: r.FormValue("source_ref"),
+	}, err
 }
 
 func (h *Handlers) HandleGrinderCreate(w http.ResponseWriter, r *http.Request) {
@@ -743,10 +769,11 @@ func (h *Handlers) HandleGrinderCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	handlers.RecordCRUDWrite(
-		w, r, store, arabica.NSIDGrinder, "grinder", "",
+		w, r, store, arabica.NSIDGrinder, "grinder", "", "",
 		func(r *http.Request, req *arabica.CreateGrinderRequest) error {
-			*req = grinderFormDecoder(r)
-			return nil
+			decoded, err := grinderFormDecoder(r)
+			*req = decoded
+			return err
 		},
 		func(req *arabica.CreateGrinderRequest) *arabica.Grinder { return grinderFromCreate(req, time.Now()) },
 		func(m *arabica.Grinder, rkey string) { m.RKey = rkey },
@@ -768,10 +795,11 @@ func (h *Handlers) HandleGrinderUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 	createdAt := handlers.ExistingCreatedAt(r.Context(), store, arabica.NSIDGrinder, rkey)
 	handlers.RecordCRUDWrite(
-		w, r, store, arabica.NSIDGrinder, "grinder", rkey,
+		w, r, store, arabica.NSIDGrinder, "grinder", rkey, handlers.SwapCID(r),
 		func(r *http.Request, req *arabica.UpdateGrinderRequest) error {
-			*req = arabica.UpdateGrinderRequest(grinderFormDecoder(r))
-			return nil
+			decoded, err := grinderFormDecoder(r)
+			*req = arabica.UpdateGrinderRequest(decoded)
+			return err
 		},
 		func(req *arabica.UpdateGrinderRequest) *arabica.Grinder {
 			m := grinderFromUpdate(req, createdAt)
@@ -812,7 +840,7 @@ func (h *Handlers) HandleBrewerCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	handlers.RecordCRUDWrite(
-		w, r, store, arabica.NSIDBrewer, "brewer", "",
+		w, r, store, arabica.NSIDBrewer, "brewer", "", "",
 		func(r *http.Request, req *arabica.CreateBrewerRequest) error { *req = brewerFormDecoder(r); return nil },
 		func(req *arabica.CreateBrewerRequest) *arabica.Brewer { return brewerFromCreate(req, time.Now()) },
 		func(m *arabica.Brewer, rkey string) { m.RKey = rkey },
@@ -834,7 +862,7 @@ func (h *Handlers) HandleBrewerUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 	createdAt := handlers.ExistingCreatedAt(r.Context(), store, arabica.NSIDBrewer, rkey)
 	handlers.RecordCRUDWrite(
-		w, r, store, arabica.NSIDBrewer, "brewer", rkey,
+		w, r, store, arabica.NSIDBrewer, "brewer", rkey, handlers.SwapCID(r),
 		func(r *http.Request, req *arabica.UpdateBrewerRequest) error {
 			*req = arabica.UpdateBrewerRequest(brewerFormDecoder(r))
 			return nil
@@ -3,18 +3,33 @@ package coffeehandlers
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	coffee "tangled.org/arabica.social/arabica/internal/arabica/web/components"
+	coffeepages "tangled.org/arabica.social/arabica/internal/arabica/web/pages"
+	"tangled.org/arabica.social/arabica/internal/atproto"
 	"tangled.org/arabica.social/arabica/internal/handlers"
+	"tangled.org/pdewey.com/atp"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestHandleBrewListPartial_Success tests successful brew list retrieval
@@ -57,6 +72,19 @@ func TestHandleBrewListPartial_Unauthenticated(t *testing.T) {
 	assert.Contains(t, rec.Body.String(), "Authentication required")
 }
 
+// TestHandleBrewSearch_Unauthenticated tests unauthenticated search
+func TestHandleBrewSearch_Unauthenticated(t *testing.T) {
+	tc := NewTestContext()
+
+	req := NewUnauthenticatedRequest("GET", "/brews/search?q=chocolate")
+	rec := httptest.NewRecorder()
+
+	tc.Handler.HandleBrewSearch(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Authentication required")
+}
+
 // TestHandleBrewDelete_Success tests successful brew deletion
 func TestHandleBrewDelete_Success(t *testing.T) {
 	tc := NewTestContext()
@@ -212,6 +240,62 @@ func TestHandleBrewExport(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, rec.Code)
 }
 
+func TestHandleBrewExportCSV_Unauthenticated(t *testing.T) {
+	tc := NewTestContext()
+
+	req := NewUnauthenticatedRequest("GET", "/brews/export.csv")
+	rec := httptest.NewRecorder()
+
+	tc.Handler.HandleBrewExportCSV(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestWriteBrewsCSV(t *testing.T) {
+	brews := []*arabica.Brew{
+		{
+			CreatedAt:    time.Date(2025, 2, 3, 8, 30, 0, 0, time.UTC),
+			Method:       "V60",
+			CoffeeAmount: 15,
+			WaterAmount:  250,
+			Temperature:  93.5,
+			TimeSeconds:  180,
+			Rating:       8,
+			TastingNotes: "Blueberry, \"jammy\"",
+			Tasting:      &arabica.TastingProfile{Acidity: 8, Sweetness: 6, Flavors: []string{"berry", "cocoa"}},
+			Bean: &arabica.Bean{
+				Name:    "Kochere",
+				Roaster: &arabica.Roaster{Name: "Sey"},
+			},
+			Pours: []*arabica.Pour{
+				{WaterAmount: 50, TimeSeconds: 0},
+				{WaterAmount: 200, TimeSeconds: 45},
+			},
+		},
+		{
+			CreatedAt:    time.Date(2025, 2, 4, 9, 0, 0, 0, time.UTC),
+			TastingNotes: "=HYPERLINK(\"x\")",
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeBrewsCSV(&buf, brews))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, brewCSVHeader, rows[0])
+	assert.Equal(t, []string{
+		"2025-02-03T08:30:00Z", "Kochere", "Sey", "V60", "15", "250", "16.67",
+		"93.5", "180", "8", `Blueberry, "jammy"`, "50g@0s;200g@45s",
+		"8", "", "6", "", "berry;cocoa", "C",
+	}, rows[1])
+	assert.Equal(t, []string{
+		"2025-02-04T09:00:00Z", "", "", "", "", "", "", "", "", "", `'=HYPERLINK("x")`, "",
+		"", "", "", "", "", "",
+	}, rows[2], "missing values stay blank and formulas are defused")
+}
+
 // TestHandleAPIListAll tests the API endpoint for listing all user data
 func TestHandleAPIListAll(t *testing.T) {
 	tc := NewTestContext()
@@ -430,6 +514,44 @@ func TestValidateBrewRequest(t *testing.T) {
 			},
 			wantErrs: 3,
 		},
+		{
+			name: "valid tds",
+			formData: url.Values{
+				"tds": []string{"1.38"},
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "tds out of range",
+			formData: url.Values{
+				"tds": []string{"12"},
+			},
+			wantErrs: 1,
+		},
+		{
+			name: "valid tasting profile",
+			formData: url.Values{
+				"acidity":   []string{"7"},
+				"sweetness": []string{"10"},
+				"flavors":   []string{"berry", "cocoa"},
+			},
+			wantErrs: 0,
+		},
+		{
+			name: "tasting scores out of range",
+			formData: url.Values{
+				"body":       []string{"0"},
+				"bitterness": []string{"11"},
+			},
+			wantErrs: 2,
+		},
+		{
+			name: "unknown flavor",
+			formData: url.Values{
+				"flavors": []string{"bacon"},
+			},
+			wantErrs: 1,
+		},
 	}
 
 	for _, tt := range tests {
@@ -438,9 +560,738 @@ func TestValidateBrewRequest(t *testing.T) {
 			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 			req.ParseForm()
 
-			_, _, _, _, _, _, errs := validateBrewRequest(req)
+			_, _, _, _, _, _, _, _, _, _, errs := validateBrewRequest(req)
 
 			assert.Equal(t, tt.wantErrs, len(errs))
 		})
 	}
 }
+
+func TestHandleBrewValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		formData   url.Values
+		wantStatus int
+		wantFields []string
+	}{
+		{
+			name:       "valid brew",
+			formData:   url.Values{"bean_rkey": []string{"3jzfcijpj2z2a"}, "temperature": []string{"93"}},
+			wantStatus: http.StatusOK,
+			wantFields: []string{},
+		},
+		{
+			name: "every problem reported",
+			formData: url.Values{
+				"temperature":  []string{"300"},
+				"grinder_rkey": []string{"bad/rkey"},
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: []string{"temperature", "bean_rkey", "grinder_rkey"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := NewTestContext()
+			tc.Handler.SetStoreOverrideForTest(tc.MockStore)
+
+			req := newMiddlewareAuthenticatedRequest(http.MethodPost, "/api/brews/validate")
+			req.Body = ioNopCloser(tt.formData.Encode())
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec := httptest.NewRecorder()
+
+			tc.Handler.HandleBrewValidate(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			var body struct {
+				Errors []ValidationError `json:"errors"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			fields := []string{}
+			for _, e := range body.Errors {
+				fields = append(fields, e.Field)
+			}
+			assert.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
+
+func TestHandleBrewCreate_ReportsEveryValidationError(t *testing.T) {
+	tc := NewTestContext()
+	tc.Handler.SetStoreOverrideForTest(tc.MockStore)
+
+	form := url.Values{"temperature": []string{"300"}, "rating": []string{"42"}}
+	req := newMiddlewareAuthenticatedRequest(http.MethodPost, "/brews")
+	req.Body = ioNopCloser(form.Encode())
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+
+	tc.Handler.HandleBrewCreate(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var body struct {
+		Errors []ValidationError `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, []ValidationError{
+		{Field: "temperature", Message: "temperature must be between 0 and 212"},
+		{Field: "rating", Message: "rating must be between 0 and 10"},
+		{Field: "bean_rkey", Message: "Bean selection is required"},
+	}, body.Errors)
+}
+
+func TestParseBrewFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  arabica.BrewFilter
+	}{
+		{"empty", "", arabica.BrewFilter{}},
+		{"all params", "bean=3abc&method=V60&min_rating=7&sort=ratio",
+			arabica.BrewFilter{BeanRKey: "3abc", Method: "V60", MinRating: 7, Sort: arabica.BrewSortRatio}},
+		{"malformed values ignored", "min_rating=high&sort=random", arabica.BrewFilter{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			got := parseBrewFilter(q)
+			assert.Equal(t, tt.want, got)
+
+			// The encoded filter must parse back to the same filter for "Load More"
+			again, err := url.ParseQuery(brewFilterQuery(got))
+			require.NoError(t, err)
+			assert.Equal(t, got, parseBrewFilter(again))
+		})
+	}
+}
+
+func TestHandleMyCoffeePrefillsBrewFilters(t *testing.T) {
+	tc := NewTestContext()
+	tc.Handler.SetStoreOverrideForTest(tc.MockStore)
+	tc.MockStore.ListBeansFunc = func(ctx context.Context) ([]*arabica.Bean, error) {
+		return []*arabica.Bean{tc.Fixtures.Bean}, nil
+	}
+
+	req := newMiddlewareAuthenticatedRequest(http.MethodGet, "/my-coffee?bean=test-bean-rkey&method=v60&min_rating=7&sort=rating")
+	rec := httptest.NewRecorder()
+	tc.Handler.HandleMyCoffee(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `<option value="test-bean-rkey" selected>`)
+	assert.Contains(t, body, `<option value="v60" selected>`)
+	assert.Contains(t, body, `<option value="7" selected>`)
+	assert.Contains(t, body, `<option value="rating" selected>`)
+	assert.Contains(t, body, `hx-include="#brew-filters"`, "the first list load carries the filters")
+}
+
+func TestHandleBrewDefaultsUpdateRejectsMalformedRKeys(t *testing.T) {
+	tests := []struct {
+		field string
+		value string
+	}{
+		{"grinder_rkey", "3jzfcijpj2z2a/../other"},
+		{"brewer_rkey", "not/an-rkey"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			tc := NewTestContext()
+			tc.Handler.SetStoreOverrideForTest(tc.MockStore)
+			saved := false
+			tc.MockStore.PutPreferencesFunc = func(ctx context.Context, prefs *arabica.BrewPreferences) error {
+				saved = true
+				return nil
+			}
+
+			form := url.Values{tt.field: {tt.value}, "coffee_amount": {"18"}}
+			req := newMiddlewareAuthenticatedRequest(http.MethodPost, "/settings/brew-defaults")
+			req.Body = ioNopCloser(form.Encode())
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec := httptest.NewRecorder()
+			tc.Handler.HandleBrewDefaultsUpdate(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), "invalid format")
+			assert.False(t, saved)
+		})
+	}
+}
+
+// TestHandleBrewEditRoundTripsMethod loads a brew into the edit form and
+// submits the method fields the form was prefilled with, so an edit that
+// doesn't touch the method leaves it as it was.
+func TestHandleBrewEditRoundTripsMethod(t *testing.T) {
+	tests := []struct {
+		name            string
+		stored          arabica.Brew
+		wantMethod      string
+		wantMethodOther string
+	}{
+		{"known method", arabica.Brew{Method: "v60"}, "v60", ""},
+		{"free text from an older record", arabica.Brew{Method: "Hario V60"}, "v60", ""},
+		{"other", arabica.Brew{Method: "other", MethodOther: "Phin"}, "other", "Phin"},
+	}
+	attr := func(body, name string) string {
+		m := regexp.MustCompile(name + `="([^"]*)"`).FindStringSubmatch(body)
+		require.NotNil(t, m, name)
+		return html.UnescapeString(m[1])
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := NewTestContext()
+			tc.Handler.SetStoreOverrideForTest(tc.MockStore)
+			stored := tt.stored
+			stored.RKey = "3jzfcijpj2z2a"
+			stored.BeanRKey = "3jzfcijpj2z2b"
+			tc.MockStore.GetBrewRecordByRKeyFunc = func(ctx context.Context, rkey string) (*atproto.EntityRecord[arabica.Brew], error) {
+				return &atproto.EntityRecord[arabica.Brew]{Model: &stored, CID: "cid-loaded"}, nil
+			}
+			var got *arabica.CreateBrewRequest
+			tc.MockStore.UpdateBrewByRKeyFunc = func(ctx context.Context, rkey string, req *arabica.CreateBrewRequest) error {
+				got = req
+				return nil
+			}
+
+			req := newMiddlewareAuthenticatedRequest(http.MethodGet, "/brews/3jzfcijpj2z2a/edit")
+			req.SetPathValue("id", "3jzfcijpj2z2a")
+			rec := httptest.NewRecorder()
+			tc.Handler.HandleBrewEdit(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code)
+			body := rec.Body.String()
+			assert.Contains(t, attr(body, "data-method-options"), `{"value":"other","label":"Other"}`)
+
+			form := url.Values{
+				"bean_rkey":    {stored.BeanRKey},
+				"method":       {attr(body, "data-method")},
+				"method_other": {attr(body, "data-method-other")},
+			}
+			req = newMiddlewareAuthenticatedRequest(http.MethodPut, "/brews/3jzfcijpj2z2a")
+			req.SetPathValue("id", "3jzfcijpj2z2a")
+			req.Body = ioNopCloser(form.Encode())
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec = httptest.NewRecorder()
+			tc.Handler.HandleBrewUpdate(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			require.NotNil(t, got)
+			assert.Equal(t, tt.wantMethod, got.Method)
+			assert.Equal(t, tt.wantMethodOther, got.MethodOther)
+		})
+	}
+}
+
+func TestBuildBrewRSS(t *testing.T) {
+	created := time.Date(2025, 2, 3, 8, 30, 0, 0, time.UTC)
+	brews := []*arabica.Brew{
+		{
+			RKey:         "3abc",
+			TastingNotes: "Blueberry & cocoa",
+			Rating:       8,
+			CreatedAt:    created,
+			Bean:         &arabica.Bean{Name: "Kochere"},
+		},
+		{RKey: "3def", CreatedAt: created.Add(-time.Hour)},
+	}
+
+	doc := buildBrewRSS("https://arabica.social", "alice.test", brews)
+	out, err := xml.Marshal(doc)
+	assert.NoError(t, err)
+
+	var parsed rssDocument
+	assert.NoError(t, xml.Unmarshal(out, &parsed))
+	assert.Equal(t, "2.0", parsed.Version)
+	assert.Len(t, parsed.Channel.Items, 2)
+
+	first := parsed.Channel.Items[0]
+	assert.Equal(t, "Kochere", first.Title)
+	assert.Equal(t, "https://arabica.social/brews/alice.test/3abc", first.Link)
+	assert.Equal(t, "Blueberry & cocoa\n\nRating: 8/10", first.Description)
+	assert.Equal(t, "Mon, 03 Feb 2025 08:30:00 +0000", first.PubDate)
+	assert.Equal(t, "Untitled brew", parsed.Channel.Items[1].Title)
+	assert.Empty(t, parsed.Channel.Items[1].Description)
+}
+
+func TestBuildBrewRSS_NoBrews(t *testing.T) {
+	doc := buildBrewRSS("https://arabica.social", "alice.test", nil)
+	out, err := xml.Marshal(doc)
+	assert.NoError(t, err)
+
+	var parsed rssDocument
+	assert.NoError(t, xml.Unmarshal(out, &parsed))
+	assert.Equal(t, "https://arabica.social/profile/alice.test", parsed.Channel.Link)
+	assert.Empty(t, parsed.Channel.Items)
+}
+
+// pagedLister serves brews the way a PDS does: pages of at most
+// opts.Limit records with a numeric cursor. With err set, pages starting
+// at or after failAt fail. Other collections are empty.
+type pagedLister struct {
+	records []atp.Record
+	err     error
+	failAt  int
+	calls   atomic.Int32
+}
+
+func (l *pagedLister) ListPublicRecords(ctx context.Context, did, collection string, opts atp.ListPublicRecordsOpts) ([]atp.Record, string, error) {
+	if collection != arabica.NSIDBrew {
+		return nil, "", nil
+	}
+	l.calls.Add(1)
+	start, _ := strconv.Atoi(opts.Cursor)
+	if l.err != nil && start >= l.failAt {
+		return nil, "", l.err
+	}
+	end := min(start+opts.Limit, len(l.records))
+	next := ""
+	if end < len(l.records) {
+		next = strconv.Itoa(end)
+	}
+	return l.records[start:end], next, nil
+}
+
+func testBrewRecords(n int) []atp.Record {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := make([]atp.Record, n)
+	for i := range records {
+		records[i] = atp.Record{
+			URI: atp.BuildATURI("did:plc:alice", arabica.NSIDBrew, fmt.Sprintf("brew%04d", i)),
+			Value: map[string]any{
+				"beanRef":   atp.BuildATURI("did:plc:alice", arabica.NSIDBean, "bean1"),
+				"createdAt": base.Add(time.Duration(i) * time.Hour).Format(time.RFC3339),
+			},
+		}
+	}
+	return records
+}
+
+func TestListProfileRecords(t *testing.T) {
+	errPDS := errors.New("pds unavailable")
+	tests := []struct {
+		name      string
+		lister    *pagedLister
+		wantCount int
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "single page", lister: &pagedLister{records: testBrewRecords(40)}, wantCount: 40, wantCalls: 1},
+		{name: "follows cursors past 100", lister: &pagedLister{records: testBrewRecords(250)}, wantCount: 250, wantCalls: 3},
+		{name: "capped", lister: &pagedLister{records: testBrewRecords(maxProfileRecords + 150)}, wantCount: maxProfileRecords, wantCalls: 10},
+		{name: "later page fails", lister: &pagedLister{records: testBrewRecords(250), err: errPDS, failAt: 200}, wantCount: 200, wantCalls: 3},
+		{name: "first page fails", lister: &pagedLister{records: testBrewRecords(10), err: errPDS}, wantErr: true, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := listProfileRecords(context.Background(), tt.lister, "did:plc:alice", arabica.NSIDBrew)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, records, tt.wantCount)
+			}
+			assert.Equal(t, int32(tt.wantCalls), tt.lister.calls.Load())
+		})
+	}
+}
+
+func TestFetchProfileFromPDS_PaginatesBrews(t *testing.T) {
+	h := &Handlers{}
+	lister := &pagedLister{records: testBrewRecords(150)}
+
+	bundle, err := h.fetchProfileFromPDS(context.Background(), "did:plc:alice", lister, 100, 25, true)
+	require.NoError(t, err)
+	assert.Equal(t, 150, bundle.TotalBrews)
+	require.Len(t, bundle.Brews, 25)
+	// Newest first, so the fifth page starts 100 brews back from the newest
+	assert.Equal(t, "brew0049", bundle.Brews[0].RKey)
+	assert.Equal(t, "brew0025", bundle.Brews[24].RKey)
+
+	bundle, err = h.fetchProfileFromPDS(context.Background(), "did:plc:alice", lister, 0, 0, true)
+	require.NoError(t, err)
+	assert.Len(t, bundle.Brews, 150)
+}
+
+func TestComputeBrewStats(t *testing.T) {
+	kochere := &arabica.Bean{RKey: "bean1", Name: "Kochere"}
+	huila := &arabica.Bean{RKey: "bean2", Origin: "Huila"}
+
+	tests := []struct {
+		name  string
+		brews []*arabica.Brew
+		want  coffee.BrewStats
+	}{
+		{
+			name:  "no brews",
+			brews: nil,
+			want:  coffee.BrewStats{},
+		},
+		{
+			name: "mixed brews",
+			brews: []*arabica.Brew{
+				{Method: "V60", Rating: 8, CoffeeAmount: 15, WaterAmount: 240, BeanRKey: "bean2", Bean: huila},
+				{BrewerObj: &arabica.Brewer{BrewerType: "AeroPress"}, Rating: 6, BeanRKey: "bean1", Bean: kochere},
+				{Method: "V60", CoffeeAmount: 20, WaterAmount: 360, BeanRKey: "bean1", Bean: kochere},
+			},
+			want: coffee.BrewStats{
+				TotalBrews:   3,
+				SampleSize:   3,
+				AvgRating:    7,
+				AvgRatio:     17,
+				TopMethod:    "V60",
+				FavoriteBean: "Kochere",
+			},
+		},
+		{
+			name: "ties go to the first brew",
+			brews: []*arabica.Brew{
+				{Method: "Chemex", BeanRKey: "bean2", Bean: huila},
+				{Method: "V60", BeanRKey: "bean1", Bean: kochere},
+			},
+			want: coffee.BrewStats{
+				TotalBrews:   2,
+				SampleSize:   2,
+				TopMethod:    "Chemex",
+				FavoriteBean: "Huila",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, computeBrewStats(tt.brews))
+		})
+	}
+}
+
+func TestImportBrews(t *testing.T) {
+	brewedAt := time.Date(2025, 3, 14, 8, 30, 0, 0, time.UTC)
+	export := []*arabica.Brew{
+		{
+			Bean:         &arabica.Bean{Name: "ethiopia guji "},
+			GrinderObj:   &arabica.Grinder{Name: "Comandante"},
+			CoffeeAmount: 15,
+			WaterAmount:  250,
+			Pours:        []*arabica.Pour{{WaterAmount: 50, TimeSeconds: 0}, {WaterAmount: 200, TimeSeconds: 45}},
+			CreatedAt:    brewedAt,
+		},
+		{Bean: &arabica.Bean{Name: "Kenya AA", Origin: "Kenya"}, CoffeeAmount: 18},
+		{CoffeeAmount: 18},
+		{Bean: &arabica.Bean{Name: "Ethiopia Guji"}, TastingNotes: strings.Repeat("x", arabica.MaxTastingNotesLength+1)},
+	}
+
+	tests := []struct {
+		name          string
+		createMissing bool
+		wantImported  int
+		wantSkipped   int
+		wantGrinder   string
+	}{
+		{name: "existing records only", createMissing: false, wantImported: 1, wantSkipped: 3, wantGrinder: ""},
+		{name: "create missing records", createMissing: true, wantImported: 2, wantSkipped: 2, wantGrinder: "grinder1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := NewTestContext()
+			tc.MockStore.ListBeansFunc = func(ctx context.Context) ([]*arabica.Bean, error) {
+				return []*arabica.Bean{{RKey: "bean1", Name: "Ethiopia Guji"}}, nil
+			}
+			var createdBeans []string
+			tc.MockStore.CreateBeanFunc = func(ctx context.Context, bean *arabica.CreateBeanRequest) (*arabica.Bean, error) {
+				createdBeans = append(createdBeans, bean.Name)
+				return &arabica.Bean{RKey: "bean2", Name: bean.Name}, nil
+			}
+			var createdGrinders []string
+			tc.MockStore.CreateGrinderFunc = func(ctx context.Context, grinder *arabica.CreateGrinderRequest) (*arabica.Grinder, error) {
+				createdGrinders = append(createdGrinders, grinder.Name)
+				return &arabica.Grinder{RKey: "grinder1", Name: grinder.Name}, nil
+			}
+			var created []*arabica.CreateBrewRequest
+			tc.MockStore.CreateBrewFunc = func(ctx context.Context, brew *arabica.CreateBrewRequest, userID int) (*arabica.Brew, error) {
+				created = append(created, brew)
+				return &arabica.Brew{}, nil
+			}
+
+			result, err := importBrews(context.Background(), tc.MockStore, export, tt.createMissing)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantImported, result.Imported)
+			assert.Equal(t, tt.wantSkipped, result.Skipped)
+			assert.Len(t, result.Errors, tt.wantSkipped)
+			require.Len(t, created, tt.wantImported)
+
+			first := created[0]
+			assert.Equal(t, "bean1", first.BeanRKey)
+			assert.Equal(t, tt.wantGrinder, first.GrinderRKey)
+			assert.Equal(t, brewedAt, first.CreatedAt)
+			assert.Equal(t, []arabica.CreatePourData{{WaterAmount: 50}, {WaterAmount: 200, TimeSeconds: 45}}, first.Pours)
+
+			if tt.createMissing {
+				assert.Equal(t, []string{"Kenya AA"}, createdBeans)
+				assert.Equal(t, []string{"Comandante"}, createdGrinders)
+				assert.Equal(t, "bean2", created[1].BeanRKey)
+			} else {
+				assert.Empty(t, createdBeans)
+				assert.Empty(t, createdGrinders)
+			}
+		})
+	}
+}
+
+func TestHandleBrewImport_HTMXSummary(t *testing.T) {
+	tc := NewTestContext()
+	tc.Handler.SetStoreOverrideForTest(tc.MockStore)
+	tc.MockStore.ListBeansFunc = func(ctx context.Context) ([]*arabica.Bean, error) {
+		return []*arabica.Bean{{RKey: "bean1", Name: "Ethiopia Guji"}}, nil
+	}
+	tc.MockStore.CreateBrewFunc = func(ctx context.Context, brew *arabica.CreateBrewRequest, userID int) (*arabica.Brew, error) {
+		return &arabica.Brew{}, nil
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", "brews.json")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(`[{"bean":{"name":"Ethiopia Guji"},"coffee_amount":15},{"bean":{"name":"Kenya AA"}}]`))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	req := newMiddlewareAuthenticatedRequest(http.MethodPost, "/brews/import")
+	req.Body = io.NopCloser(&body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("HX-Request", "true")
+	rec := httptest.NewRecorder()
+
+	tc.Handler.HandleBrewImport(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, rec.Body.String(), "Imported 1, skipped 1.")
+	assert.Contains(t, rec.Body.String(), `brew 2: bean &#34;Kenya AA&#34; not found`)
+}
+
+func TestHandleAccountExport_Unauthenticated(t *testing.T) {
+	tc := NewTestContext()
+
+	req := NewUnauthenticatedRequest("GET", "/account/export")
+	rec := httptest.NewRecorder()
+
+	tc.Handler.HandleAccountExport(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestHandleAccountExport(t *testing.T) {
+	tc := NewTestContext()
+	tc.Handler.SetStoreOverrideForTest(tc.MockStore)
+	tc.MockStore.ListBeansFunc = func(ctx context.Context) ([]*arabica.Bean, error) {
+		return []*arabica.Bean{{RKey: "bean1", Name: "Ethiopia Guji"}}, nil
+	}
+	tc.MockStore.ListBrewsFunc = func(ctx context.Context, userID, offset, limit int) ([]*arabica.Brew, error) {
+		assert.Zero(t, limit, "every brew is exported")
+		return []*arabica.Brew{{RKey: "brew1", CoffeeAmount: 15}, {RKey: "brew2", CoffeeAmount: 18}}, nil
+	}
+	tc.MockStore.ListUserLikesFunc = func(ctx context.Context) ([]*arabica.Like, error) {
+		return []*arabica.Like{{RKey: "like1", SubjectURI: "at://did:plc:other/social.arabica.alpha.brew/abc"}}, nil
+	}
+
+	req := newMiddlewareAuthenticatedRequest(http.MethodGet, "/account/export")
+	rec := httptest.NewRecorder()
+	tc.Handler.HandleAccountExport(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), "attachment")
+	var got struct {
+		DID        string             `json:"did"`
+		ExportedAt time.Time          `json:"exported_at"`
+		Beans      []*arabica.Bean    `json:"beans"`
+		Roasters   []*arabica.Roaster `json:"roasters"`
+		Grinders   []*arabica.Grinder `json:"grinders"`
+		Brews      []*arabica.Brew    `json:"brews"`
+		Likes      []*arabica.Like    `json:"likes"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+
+	assert.Equal(t, "did:plc:test123456789", got.DID)
+	assert.False(t, got.ExportedAt.IsZero())
+	require.Len(t, got.Beans, 1)
+	assert.Equal(t, "Ethiopia Guji", got.Beans[0].Name)
+	require.Len(t, got.Brews, 2)
+	assert.Equal(t, "brew2", got.Brews[1].RKey)
+	require.Len(t, got.Likes, 1)
+	assert.NotNil(t, got.Roasters, "empty collections are written as []")
+	assert.Empty(t, got.Grinders)
+	assert.Contains(t, rec.Body.String(), `"grinders":[]`)
+}
+
+func TestHandleAccountExport_FailureBeforeOutput(t *testing.T) {
+	tc := NewTestContext()
+	tc.Handler.SetStoreOverrideForTest(tc.MockStore)
+	var brewsListed bool
+	tc.MockStore.ListBeansFunc = func(ctx context.Context) ([]*arabica.Bean, error) {
+		return nil, errors.New("pds unavailable")
+	}
+	tc.MockStore.ListBrewsFunc = func(ctx context.Context, userID, offset, limit int) ([]*arabica.Brew, error) {
+		brewsListed = true
+		return nil, nil
+	}
+
+	req := newMiddlewareAuthenticatedRequest(http.MethodGet, "/account/export")
+	rec := httptest.NewRecorder()
+	tc.Handler.HandleAccountExport(rec, req)
+
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Disposition"))
+	assert.False(t, brewsListed, "later collections aren't fetched after a failure")
+}
+
+// newBrewPhotoRequest builds a multipart brew form with an optional photo.
+func newBrewPhotoRequest(t *testing.T, photo []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	require.NoError(t, mw.WriteField("bean_rkey", "bean1"))
+	if photo != nil {
+		part, err := mw.CreateFormFile("image", "cup.png")
+		require.NoError(t, err)
+		_, err = part.Write(photo)
+		require.NoError(t, err)
+	}
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest("POST", "/brews", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	require.NoError(t, parseBrewForm(req))
+	return req
+}
+
+func TestReadBrewImage(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+
+	tests := []struct {
+		name     string
+		photo    []byte
+		wantMIME string
+		wantErr  error
+	}{
+		{name: "no photo", photo: nil},
+		{name: "png", photo: png, wantMIME: "image/png"},
+		{name: "not an image", photo: []byte("hello, not a picture"), wantErr: errBrewImageType},
+		{name: "too large", photo: append(png, make([]byte, arabica.MaxBrewImageBytes)...), wantErr: errBrewImageTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, mimeType, err := readBrewImage(newBrewPhotoRequest(t, tt.photo))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMIME, mimeType)
+			assert.Equal(t, len(tt.photo), len(data))
+		})
+	}
+}
+
+func TestUploadBrewImage(t *testing.T) {
+	tc := NewTestContext()
+	var gotMIME string
+	tc.MockStore.UploadBlobFunc = func(ctx context.Context, data []byte, mimeType string) (map[string]any, error) {
+		gotMIME = mimeType
+		return map[string]any{"$type": "blob", "ref": map[string]any{"$link": "bafkreiphoto"}, "mimeType": mimeType, "size": float64(len(data))}, nil
+	}
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+	rec := httptest.NewRecorder()
+	image, ok := uploadBrewImage(rec, newBrewPhotoRequest(t, png), tc.MockStore)
+	require.True(t, ok)
+	require.NotNil(t, image)
+	assert.Equal(t, "bafkreiphoto", image.CID)
+	assert.Equal(t, "image/png", gotMIME)
+
+	rec = httptest.NewRecorder()
+	_, ok = uploadBrewImage(rec, newBrewPhotoRequest(t, []byte("plain text")), tc.MockStore)
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGroupRoasterBeans(t *testing.T) {
+	entry := func(name, origin, owner string) coffeepages.RoasterBeanEntry {
+		return coffeepages.RoasterBeanEntry{Bean: &arabica.Bean{Name: name, Origin: origin}, OwnerHandle: owner}
+	}
+	groups := groupRoasterBeans([]coffeepages.RoasterBeanEntry{
+		entry("Monarch", "Blend", "alice"),
+		entry("Gesha", "Panama", "bob"),
+		entry(" monarch ", "", "bob"),
+		entry("", "", "carol"),
+	})
+
+	require.Len(t, groups, 3)
+	assert.Equal(t, "Monarch", groups[0].Name)
+	assert.Equal(t, "Blend", groups[0].Origin)
+	assert.Len(t, groups[0].Entries, 2)
+	assert.Equal(t, "Gesha", groups[1].Name)
+	assert.Equal(t, "Unnamed bean", groups[2].Name)
+}
+
+func TestCrosspostBrew(t *testing.T) {
+	tc := NewTestContext()
+	brew := tc.Fixtures.Brew
+	req := httptest.NewRequest(http.MethodPost, "/brews", nil)
+	req.Host = "arabica.test"
+
+	t.Run("posts a summary with a link back", func(t *testing.T) {
+		var got atproto.BlueskyPost
+		tc.MockStore.CreateBlueskyPostFunc = func(ctx context.Context, post atproto.BlueskyPost) (string, error) {
+			got = post
+			return "at://did:plc:abcdefghijklmnopqrstuvwx/app.bsky.feed.post/3xyz", nil
+		}
+
+		require.True(t, tc.Handler.crosspostBrew(req, tc.MockStore, brew))
+		assert.True(t, strings.HasPrefix(got.Text, "Brewed Test Bean from Test Roaster (V60) — 8/10"))
+		assert.Contains(t, got.Text, "Fruity, bright")
+		assert.Equal(t, "http://arabica.test/b/did:plc:abcdefghijklmnopqrstuvwx/test-brew-rkey", got.LinkURL)
+		assert.Equal(t, "Test Bean from Test Roaster", got.LinkTitle)
+	})
+
+	t.Run("failure is reported, not returned", func(t *testing.T) {
+		tc.MockStore.CreateBlueskyPostFunc = func(ctx context.Context, post atproto.BlueskyPost) (string, error) {
+			return "", errors.New("scope missing")
+		}
+		assert.False(t, tc.Handler.crosspostBrew(req, tc.MockStore, brew))
+	})
+}
+
+func TestValidateBrewRequest_Method(t *testing.T) {
+	tests := []struct {
+		name      string
+		formData  url.Values
+		want      arabica.BrewMethod
+		wantOther string
+	}{
+		{"blank", url.Values{}, "", ""},
+		{"alias", url.Values{"method": {"Hario V60"}}, arabica.BrewMethodV60, ""},
+		{"unknown kept as other", url.Values{"method": {"Phin"}}, arabica.BrewMethodOther, "Phin"},
+		{"other with wording", url.Values{"method": {"other"}, "method_other": {"Hario Switch"}}, arabica.BrewMethodOther, "Hario Switch"},
+		{"other naming a known method", url.Values{"method": {"other"}, "method_other": {"chemex"}}, arabica.BrewMethodChemex, ""},
+		{"other without wording", url.Values{"method": {"other"}}, arabica.BrewMethodOther, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/", strings.NewReader(tt.formData.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.ParseForm()
+
+			_, _, _, _, _, _, _, method, methodOther, _, errs := validateBrewRequest(req)
+
+			assert.Empty(t, errs)
+			assert.Equal(t, tt.want, method)
+			assert.Equal(t, tt.wantOther, methodOther)
+		})
+	}
+}
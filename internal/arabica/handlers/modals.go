@@ -33,15 +33,16 @@ func (h *Handlers) arabicaModalNew(w http.ResponseWriter, r *http.Request, name
 	}
 }
 
-// arabicaModalEdit fetches a record by rkey via fetch and renders the
-// pre-filled edit modal.
+// arabicaModalEdit fetches a record and its CID by rkey via fetch and
+// renders the pre-filled edit modal, which sends the CID back as the swap
+// CID on save.
 func arabicaModalEdit[Model any](
 	h *Handlers,
 	w http.ResponseWriter,
 	r *http.Request,
 	name string,
-	fetch func(context.Context, arabicastore.Store, string) (*Model, error),
-	render func(*Model) templ.Component,
+	fetch func(context.Context, arabicastore.Store, string) (*Model, string, error),
+	render func(*Model, string) templ.Component,
 ) {
 	rkey := handlers.ValidateRKey(w, r.PathValue("id"))
 	if rkey == "" {
@@ -52,13 +53,13 @@ func arabicaModalEdit[Model any](
 		http.Error(w, "Authentication required", http.StatusUnauthorized)
 		return
 	}
-	m, err := fetch(r.Context(), store, rkey)
+	m, cid, err := fetch(r.Context(), store, rkey)
 	if err != nil {
 		http.Error(w, name+" not found", http.StatusNotFound)
 		log.Error().Err(err).Str("rkey", rkey).Msgf("Failed to get %s for modal", name)
 		return
 	}
-	if err := render(m).Render(r.Context(), w); err != nil {
+	if err := render(m, cid).Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render modal", http.StatusInternalServerError)
 		log.Error().Err(err).Msgf("Failed to render %s modal", name)
 	}
@@ -75,7 +76,7 @@ func (h *Handlers) HandleBeanModalNew(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Authentication required", http.StatusUnauthorized)
 		return
 	}
-	if err := coffee.BeanDialogModal(nil, beanModalRoasters(r.Context(), store)).Render(r.Context(), w); err != nil {
+	if err := coffee.BeanDialogModal(nil, beanModalRoasters(r.Context(), store), "").Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render modal", http.StatusInternalServerError)
 		log.Error().Err(err).Msg("Failed to render bean modal")
 	}
@@ -91,13 +92,13 @@ func (h *Handlers) HandleBeanModalEdit(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Authentication required", http.StatusUnauthorized)
 		return
 	}
-	bean, err := store.GetBeanByRKey(r.Context(), rkey)
+	bean, err := store.GetBeanRecordByRKey(r.Context(), rkey)
 	if err != nil {
 		http.Error(w, "Bean not found", http.StatusNotFound)
 		log.Error().Err(err).Str("rkey", rkey).Msg("Failed to get bean for modal")
 		return
 	}
-	if err := coffee.BeanDialogModal(bean, beanModalRoasters(r.Context(), store)).Render(r.Context(), w); err != nil {
+	if err := coffee.BeanDialogModal(bean.Model, beanModalRoasters(r.Context(), store), bean.CID).Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render modal", http.StatusInternalServerError)
 		log.Error().Err(err).Msg("Failed to render bean modal")
 	}
@@ -119,44 +120,48 @@ func beanModalRoasters(ctx context.Context, store arabicastore.Store) []arabica.
 // --- Grinder ---------------------------------------------------------
 
 func (h *Handlers) HandleGrinderModalNew(w http.ResponseWriter, r *http.Request) {
-	h.arabicaModalNew(w, r, "grinder", func() templ.Component { return coffee.GrinderDialogModal(nil) })
+	h.arabicaModalNew(w, r, "grinder", func() templ.Component { return coffee.GrinderDialogModal(nil, "") })
 }
 
 func (h *Handlers) HandleGrinderModalEdit(w http.ResponseWriter, r *http.Request) {
 	arabicaModalEdit(h, w, r, "grinder",
-		func(ctx context.Context, s arabicastore.Store, rkey string) (*arabica.Grinder, error) {
-			return getGrinder(ctx, s, rkey)
+		func(ctx context.Context, s arabicastore.Store, rkey string) (*arabica.Grinder, string, error) {
+			return getArabicaRecordCID(ctx, s, arabica.NSIDGrinder, rkey, arabica.RecordToGrinder)
 		},
-		func(g *arabica.Grinder) templ.Component { return coffee.GrinderDialogModal(g) },
+		func(g *arabica.Grinder, cid string) templ.Component { return coffee.GrinderDialogModal(g, cid) },
 	)
 }
 
 // --- Brewer ----------------------------------------------------------
 
 func (h *Handlers) HandleBrewerModalNew(w http.ResponseWriter, r *http.Request) {
-	h.arabicaModalNew(w, r, "brewer", func() templ.Component { return coffee.BrewerDialogModal(nil) })
+	h.arabicaModalNew(w, r, "brewer", func() templ.Component { return coffee.BrewerDialogModal(nil, "") })
 }
 
 func (h *Handlers) HandleBrewerModalEdit(w http.ResponseWriter, r *http.Request) {
 	arabicaModalEdit(h, w, r, "brewer",
-		func(ctx context.Context, s arabicastore.Store, rkey string) (*arabica.Brewer, error) {
-			return getBrewer(ctx, s, rkey)
+		func(ctx context.Context, s arabicastore.Store, rkey string) (*arabica.Brewer, string, error) {
+			return getArabicaRecordCID(ctx, s, arabica.NSIDBrewer, rkey, arabica.RecordToBrewer)
 		},
-		func(b *arabica.Brewer) templ.Component { return coffee.BrewerDialogModal(b) },
+		func(b *arabica.Brewer, cid string) templ.Component { return coffee.BrewerDialogModal(b, cid) },
 	)
 }
 
 // --- Roaster ---------------------------------------------------------
 
 func (h *Handlers) HandleRoasterModalNew(w http.ResponseWriter, r *http.Request) {
-	h.arabicaModalNew(w, r, "roaster", func() templ.Component { return coffee.RoasterDialogModal(nil) })
+	h.arabicaModalNew(w, r, "roaster", func() templ.Component { return coffee.RoasterDialogModal(nil, "") })
 }
 
 func (h *Handlers) HandleRoasterModalEdit(w http.ResponseWriter, r *http.Request) {
 	arabicaModalEdit(h, w, r, "roaster",
-		func(ctx context.Context, s arabicastore.Store, rkey string) (*arabica.Roaster, error) {
-			return s.GetRoasterByRKey(ctx, rkey)
+		func(ctx context.Context, s arabicastore.Store, rkey string) (*arabica.Roaster, string, error) {
+			rec, err := s.GetRoasterRecordByRKey(ctx, rkey)
+			if err != nil {
+				return nil, "", err
+			}
+			return rec.Model, rec.CID, nil
 		},
-		func(r *arabica.Roaster) templ.Component { return coffee.RoasterDialogModal(r) },
+		func(r *arabica.Roaster, cid string) templ.Component { return coffee.RoasterDialogModal(r, cid) },
 	)
 }
@@ -0,0 +1,158 @@
+package coffeehandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/arabica.social/arabica/internal/handlers"
+	"tangled.org/pdewey.com/atp"
+
+	"github.com/rs/zerolog/log"
+)
+
+// oEmbedResponse is the JSON body defined by https://oembed.com for the
+// "link" type. Thumbnails point at the same cards used for og:image.
+type oEmbedResponse struct {
+	Version         string `json:"version"`
+	Type            string `json:"type"`
+	Title           string `json:"title"`
+	AuthorName      string `json:"author_name,omitempty"`
+	AuthorURL       string `json:"author_url,omitempty"`
+	ProviderName    string `json:"provider_name"`
+	ProviderURL     string `json:"provider_url"`
+	ThumbnailURL    string `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth  int    `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight int    `json:"thumbnail_height,omitempty"`
+}
+
+// oEmbedTarget is a page URL the oEmbed endpoint knows how to describe.
+type oEmbedTarget struct {
+	Kind  string // "brew" or "profile"
+	Actor string // handle or DID as it appeared in the URL
+	RKey  string // set for brews
+}
+
+// parseOEmbedURL extracts the record a shared URL points at. Only URLs on
+// this instance's host are accepted, and only brew (/brews/{actor}/{rkey}
+// or the short /b/{actor}/{rkey}) and profile (/profile/{actor}) paths.
+func parseOEmbedURL(raw, host string) (oEmbedTarget, bool) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || !strings.EqualFold(u.Host, host) {
+		return oEmbedTarget{}, false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case len(parts) == 3 && (parts[0] == "brews" || parts[0] == "b") && looksLikeActor(parts[1]) && atp.ValidateRKey(parts[2]):
+		return oEmbedTarget{Kind: "brew", Actor: parts[1], RKey: parts[2]}, true
+	case len(parts) == 2 && parts[0] == "profile" && looksLikeActor(parts[1]):
+		return oEmbedTarget{Kind: "profile", Actor: parts[1]}, true
+	}
+	return oEmbedTarget{}, false
+}
+
+// oEmbedURL returns the discovery URL for an absolute page URL, on the same
+// host the page is served from.
+func oEmbedURL(pageURL string) string {
+	u, err := url.Parse(pageURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host + "/oembed?url=" + url.QueryEscape(pageURL)
+}
+
+// looksLikeActor rules out owner-scoped paths such as /brews/{rkey}/edit,
+// whose first segment is an rkey rather than a DID or handle.
+func looksLikeActor(s string) bool {
+	return strings.HasPrefix(s, "did:") || strings.Contains(s, ".")
+}
+
+// HandleOEmbed answers GET /oembed?url= for brew and profile links so chat
+// apps that speak oEmbed can unfurl them. Unrecognised URLs are a 404, and
+// only the JSON format is offered.
+func (h *Handlers) HandleOEmbed(w http.ResponseWriter, r *http.Request) {
+	if format := r.URL.Query().Get("format"); format != "" && format != "json" {
+		http.Error(w, "Only JSON is supported", http.StatusNotImplemented)
+		return
+	}
+
+	baseURL := h.PublicBaseURL(r)
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	target, ok := parseOEmbedURL(r.URL.Query().Get("url"), base.Host)
+	if !ok {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	ownerDID, err := handlers.ResolveOwnerDID(r.Context(), target.Actor)
+	if err != nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	handle := h.ResolveOwnerHandle(r.Context(), ownerDID)
+
+	resp := oEmbedResponse{
+		Version:      "1.0",
+		Type:         "link",
+		AuthorName:   handle,
+		AuthorURL:    baseURL + "/profile/" + handle,
+		ProviderName: "arabica.social",
+		ProviderURL:  baseURL,
+	}
+
+	switch target.Kind {
+	case "brew":
+		brew, _, err := h.loadPublicBrew(r.Context(), ownerDID, target.RKey, "brew_oembed")
+		if err != nil {
+			if !errors.Is(err, errBrewNotFound) {
+				log.Error().Err(err).Str("did", ownerDID).Str("rkey", target.RKey).Msg("Failed to load brew for oEmbed")
+			}
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		resp.Title = brewBeanSummary(brew)
+		if resp.Title == "" {
+			resp.Title = "Brew"
+		}
+		resp.ThumbnailURL = baseURL + "/brews/" + target.Actor + "/" + target.RKey + "/og-image"
+		resp.ThumbnailWidth, resp.ThumbnailHeight = 1200, 630
+	case "profile":
+		resp.Title = handle + " on arabica.social"
+		if idx := h.FeedIndex(); idx != nil {
+			if profile, err := idx.GetProfile(r.Context(), ownerDID); err == nil && profile != nil {
+				if profile.DisplayName != nil && *profile.DisplayName != "" {
+					resp.Title = *profile.DisplayName + " (@" + handle + ") on arabica.social"
+				}
+				if profile.Avatar != nil {
+					resp.ThumbnailURL = *profile.Avatar
+				}
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error().Err(err).Msg("Failed to encode oEmbed response")
+	}
+}
+
+// brewBeanSummary describes a brew by its bean and roaster, e.g.
+// "Halo from Onyx". Empty when the brew has no bean.
+func brewBeanSummary(brew *arabica.Brew) string {
+	if brew.Bean == nil {
+		return ""
+	}
+	summary := brew.Bean.Name
+	if brew.Bean.Roaster != nil && brew.Bean.Roaster.Name != "" {
+		summary += " from " + brew.Bean.Roaster.Name
+	}
+	return summary
+}
@@ -0,0 +1,109 @@
+package coffeehandlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/arabica.social/arabica/internal/atproto"
+	"tangled.org/arabica.social/arabica/internal/firehose"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOEmbedURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want oEmbedTarget
+		ok   bool
+	}{
+		{"brew", "https://arabica.test/brews/alice.test/3abc", oEmbedTarget{Kind: "brew", Actor: "alice.test", RKey: "3abc"}, true},
+		{"short brew", "https://arabica.test/b/alice.test/3abc", oEmbedTarget{Kind: "brew", Actor: "alice.test", RKey: "3abc"}, true},
+		{"brew with query", "https://arabica.test/brews/did:plc:alice/3abc?ref=x", oEmbedTarget{Kind: "brew", Actor: "did:plc:alice", RKey: "3abc"}, true},
+		{"profile", "https://arabica.test/profile/alice.test", oEmbedTarget{Kind: "profile", Actor: "alice.test"}, true},
+		{"other host", "https://evil.test/brews/alice.test/3abc", oEmbedTarget{}, false},
+		{"bean page", "https://arabica.test/beans/alice.test/3abc", oEmbedTarget{}, false},
+		{"brew edit page", "https://arabica.test/brews/3abc/edit", oEmbedTarget{}, false},
+		{"relative", "/brews/alice.test/3abc", oEmbedTarget{}, false},
+		{"garbage", "::not a url", oEmbedTarget{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseOEmbedURL(tt.url, "arabica.test")
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHandleOEmbed(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(t.TempDir()+"/test.db", time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, idx.Close()) })
+
+	ctx := context.Background()
+	did := "did:plc:oembed"
+	idx.StoreProfile(ctx, did, &atproto.Profile{DID: did, Handle: "alice.test"})
+	upsert := func(collection, rkey string, record map[string]any) {
+		record["$type"] = collection
+		record["createdAt"] = "2026-05-01T00:00:00Z"
+		raw, err := json.Marshal(record)
+		require.NoError(t, err)
+		require.NoError(t, idx.UpsertRecord(ctx, did, collection, rkey, "cid-"+rkey, raw, time.Now().Unix()))
+	}
+	upsert(arabica.NSIDRoaster, "r1", map[string]any{"name": "Onyx"})
+	upsert(arabica.NSIDBean, "b1", map[string]any{"name": "Halo", "roasterRef": "at://" + did + "/" + arabica.NSIDRoaster + "/r1"})
+	upsert(arabica.NSIDBrew, "w1", map[string]any{"beanRef": "at://" + did + "/" + arabica.NSIDBean + "/b1"})
+
+	tc := NewTestContext()
+	tc.Handler.SetFeedIndex(idx)
+	tc.Handler.SetWitnessCache(idx)
+
+	serve := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/oembed?"+query, nil)
+		req.Host = "arabica.test"
+		rec := httptest.NewRecorder()
+		tc.Handler.HandleOEmbed(rec, req)
+		return rec
+	}
+
+	t.Run("brew", func(t *testing.T) {
+		rec := serve("url=" + url.QueryEscape("http://arabica.test/brews/did:plc:oembed/w1"))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var resp oEmbedResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "1.0", resp.Version)
+		assert.Equal(t, "link", resp.Type)
+		assert.Equal(t, "Halo from Onyx", resp.Title)
+		assert.Equal(t, "alice.test", resp.AuthorName)
+		assert.Equal(t, "http://arabica.test/profile/alice.test", resp.AuthorURL)
+		assert.Equal(t, "http://arabica.test/brews/did:plc:oembed/w1/og-image", resp.ThumbnailURL)
+		assert.Equal(t, 1200, resp.ThumbnailWidth)
+	})
+
+	t.Run("unrecognized url", func(t *testing.T) {
+		rec := serve("url=" + url.QueryEscape("http://arabica.test/about"))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("xml is not offered", func(t *testing.T) {
+		rec := serve("format=xml&url=" + url.QueryEscape("http://arabica.test/brews/alice.test/w1"))
+		assert.Equal(t, http.StatusNotImplemented, rec.Code)
+	})
+}
+
+func TestOEmbedURL(t *testing.T) {
+	assert.Equal(t,
+		"https://arabica.test/oembed?url=https%3A%2F%2Farabica.test%2Fbrews%2Falice.test%2Fw1",
+		oEmbedURL("https://arabica.test/brews/alice.test/w1"))
+	assert.Empty(t, oEmbedURL("/brews/alice.test/w1"))
+}
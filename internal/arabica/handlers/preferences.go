@@ -0,0 +1,146 @@
+package coffeehandlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	arabicastore "tangled.org/arabica.social/arabica/internal/arabica/store"
+	coffeepages "tangled.org/arabica.social/arabica/internal/arabica/web/pages"
+	"tangled.org/arabica.social/arabica/internal/handlers"
+
+	"github.com/rs/zerolog/log"
+)
+
+// HandleBrewDefaults renders the form for editing the user's default brew
+// settings. Users who have never saved any see an empty form.
+func (h *Handlers) HandleBrewDefaults(w http.ResponseWriter, r *http.Request) {
+	store, authenticated := h.GetArabicaStore(r)
+	if !authenticated {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return
+	}
+
+	prefs, err := store.GetPreferences(r.Context())
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load brew preferences; showing empty defaults")
+	}
+	if prefs == nil {
+		prefs = &arabica.BrewPreferences{}
+	}
+
+	grinders, brewers := listGrindersAndBrewers(r.Context(), store)
+	layoutData, _, _ := h.LayoutDataFromRequest(r, "Brew Defaults")
+	props := coffeepages.BrewDefaultsProps{
+		Preferences: prefs,
+		Grinders:    grinders,
+		Brewers:     brewers,
+	}
+	if err := coffeepages.BrewDefaults(layoutData, props).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		log.Error().Err(err).Msg("Failed to render brew defaults page")
+	}
+}
+
+// HandleBrewDefaultsUpdate saves the default brew settings. Blank fields
+// clear the corresponding default.
+func (h *Handlers) HandleBrewDefaultsUpdate(w http.ResponseWriter, r *http.Request) {
+	store, authenticated := h.GetArabicaStore(r)
+	if !authenticated {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := parseBrewPreferences(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := store.PutPreferences(r.Context(), prefs); err != nil {
+		log.Error().Err(err).Msg("Failed to save brew preferences")
+		handlers.HandleStoreError(w, err, "Failed to save preferences")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	w.Write([]byte(`<span class="text-sm text-green-700 dark:text-green-400">Saved</span>`))
+}
+
+// parseBrewPreferences reads the brew defaults form. Numbers that don't
+// parse are treated as blank rather than rejected, matching the brew form;
+// malformed gear rkeys are rejected since they end up in AT-URIs.
+func parseBrewPreferences(r *http.Request) (*arabica.BrewPreferences, error) {
+	prefs := &arabica.BrewPreferences{
+		Method:      strings.TrimSpace(r.FormValue("method")),
+		GrinderRKey: r.FormValue("grinder_rkey"),
+		BrewerRKey:  r.FormValue("brewer_rkey"),
+	}
+	if errMsg := handlers.ValidateOptionalRKey(prefs.GrinderRKey, "Grinder selection"); errMsg != "" {
+		return nil, errors.New(errMsg)
+	}
+	if errMsg := handlers.ValidateOptionalRKey(prefs.BrewerRKey, "Brewer selection"); errMsg != "" {
+		return nil, errors.New(errMsg)
+	}
+	if v, err := strconv.Atoi(r.FormValue("coffee_amount")); err == nil {
+		prefs.CoffeeAmount = v
+	}
+	if v, err := strconv.ParseFloat(r.FormValue("ratio"), 64); err == nil {
+		prefs.Ratio = v
+	}
+	if v, err := strconv.ParseFloat(r.FormValue("temperature"), 64); err == nil {
+		prefs.Temperature = v
+	}
+	if err := prefs.Validate(); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// listGrindersAndBrewers loads the user's gear for the defaults selects.
+// Failures leave the corresponding list empty; the page still renders.
+func listGrindersAndBrewers(ctx context.Context, store arabicastore.Store) ([]*arabica.Grinder, []*arabica.Brewer) {
+	grinders, err := listGrinders(ctx, store)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to list grinders for brew defaults")
+	}
+	brewers, err := listBrewers(ctx, store)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to list brewers for brew defaults")
+	}
+	return grinders, brewers
+}
+
+// preferencesDraftBrew turns saved defaults into a brew for pre-filling the
+// new-brew form, attaching grinder and brewer names so the form's pickers
+// show labels. It returns nil when the user has no defaults.
+func preferencesDraftBrew(ctx context.Context, store arabicastore.Store) *arabica.Brew {
+	prefs, err := store.GetPreferences(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load brew preferences for new brew")
+		return nil
+	}
+	if prefs == nil {
+		return nil
+	}
+	brew := prefs.DraftBrew()
+	if brew.GrinderRKey != "" {
+		brew.GrinderObj, _ = getGrinder(ctx, store, brew.GrinderRKey)
+		if brew.GrinderObj == nil {
+			brew.GrinderRKey = "" // deleted since the defaults were saved
+		}
+	}
+	if brew.BrewerRKey != "" {
+		brew.BrewerObj, _ = getBrewer(ctx, store, brew.BrewerRKey)
+		if brew.BrewerObj == nil {
+			brew.BrewerRKey = ""
+		}
+	}
+	return brew
+}
@@ -1,8 +1,10 @@
 package coffeehandlers
 
 import (
+	"cmp"
 	"context"
 	"net/http"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -11,6 +13,7 @@ import (
 	coffee "tangled.org/arabica.social/arabica/internal/arabica/web/components"
 	coffeepages "tangled.org/arabica.social/arabica/internal/arabica/web/pages"
 	"tangled.org/arabica.social/arabica/internal/atproto"
+	"tangled.org/arabica.social/arabica/internal/handlers"
 	"tangled.org/arabica.social/arabica/internal/metrics"
 	"tangled.org/arabica.social/arabica/internal/moderation"
 	"tangled.org/arabica.social/arabica/internal/web/bff"
@@ -31,27 +34,70 @@ type ProfileDataBundle struct {
 	TotalBrews int // total brew count (may differ from len(Brews) when paginated)
 }
 
+// hasRecords reports whether the bundle holds any of the user's records.
+func (b *ProfileDataBundle) hasRecords() bool {
+	return len(b.Brews) > 0 || len(b.Beans) > 0 || len(b.Roasters) > 0 ||
+		len(b.Grinders) > 0 || len(b.Brewers) > 0
+}
+
+// hasIndexedRecords reports whether the feed index holds any feedable
+// records by did, which is enough to know the user without a PDS call.
+func (h *Handlers) hasIndexedRecords(ctx context.Context, did string) bool {
+	if h.FeedIndex() == nil {
+		return false
+	}
+	known, err := h.FeedIndex().HasFeedableRecords(ctx, did)
+	if err != nil {
+		log.Debug().Err(err).Str("did", did).Msg("feed index: record lookup failed")
+		return false
+	}
+	return known
+}
+
 // fetchUserProfileData fetches all user data for profile display.
-// Tries the witness cache first (firehose index), falling back to the PDS via publicClient.
-// Brews are sorted in reverse chronological order (newest first).
-func (h *Handlers) fetchUserProfileData(ctx context.Context, did string, publicClient *atp.PublicClient, brewsOffset, brewsLimit int) (*ProfileDataBundle, error) {
-	// Try witness cache first — all records for this user may already be indexed
-	if bundle := h.fetchProfileFromWitness(ctx, did, brewsOffset, brewsLimit); bundle != nil {
+// Users the index knows are read from the witness cache (firehose index)
+// alone; only unknown DIDs fall back to the PDS via publicClient.
+// Brews are sorted in reverse chronological order (newest first). Draft
+// brews are dropped unless withDrafts is set, before paging and counting,
+// so pages stay full and totals match what is shown.
+func (h *Handlers) fetchUserProfileData(ctx context.Context, did string, publicClient *atp.PublicClient, brewsOffset, brewsLimit int, withDrafts bool) (*ProfileDataBundle, error) {
+	if bundle := h.fetchProfileFromWitness(ctx, did, brewsOffset, brewsLimit, withDrafts); bundle != nil {
 		return bundle, nil
 	}
+	// The witness read comes back empty for a known user paged past their
+	// last brew with no gear; that is an empty page, not a reason to list
+	// five collections on their PDS.
+	if h.WitnessCache() != nil && h.hasIndexedRecords(ctx, did) {
+		_, countBrews := witnessBrewReaders(h.WitnessCache(), withDrafts)
+		totalBrews, _ := countBrews(ctx, did, arabica.NSIDBrew)
+		return &ProfileDataBundle{TotalBrews: totalBrews}, nil
+	}
+
+	return h.fetchProfileFromPDS(ctx, did, publicClient, brewsOffset, brewsLimit, withDrafts)
+}
 
-	return h.fetchProfileFromPDS(ctx, did, publicClient)
+// witnessBrewReaders returns the witness cache's list and count functions,
+// with or without drafts.
+func witnessBrewReaders(wc atproto.WitnessCache, withDrafts bool) (
+	list func(ctx context.Context, did, collection string, offset, limit int) ([]*atproto.WitnessRecord, error),
+	count func(ctx context.Context, did, collection string) (int, error),
+) {
+	if withDrafts {
+		return wc.ListWitnessRecordsPaginated, wc.CountWitnessRecords
+	}
+	return wc.ListPublishedWitnessRecords, wc.CountPublishedWitnessRecords
 }
 
 // fetchProfileFromWitness loads all profile data from the witness cache.
 // brewsOffset and brewsLimit control pagination of the brews collection;
 // other collections (beans, roasters, etc.) are always fully fetched.
 // Returns nil if the witness cache is not configured or the user has no indexed records.
-func (h *Handlers) fetchProfileFromWitness(ctx context.Context, did string, brewsOffset, brewsLimit int) *ProfileDataBundle {
+func (h *Handlers) fetchProfileFromWitness(ctx context.Context, did string, brewsOffset, brewsLimit int, withDrafts bool) *ProfileDataBundle {
 	witnessCache := h.WitnessCache()
 	if witnessCache == nil {
 		return nil
 	}
+	listBrews, countBrews := witnessBrewReaders(witnessCache, withDrafts)
 
 	// Load all collections from witness cache
 	type collectionResult struct {
@@ -74,25 +120,13 @@ func (h *Handlers) fetchProfileFromWitness(ctx context.Context, did string, brew
 	}
 
 	// Fetch brews with pagination when limit > 0
-	if brewsLimit > 0 {
-		records, err := witnessCache.ListWitnessRecordsPaginated(ctx, did, arabica.NSIDBrew, brewsOffset, brewsLimit)
-		if err != nil {
-			log.Debug().Err(err).Str("did", did).Msg("witness: profile brews paginated error")
-			return nil
-		}
-		if records != nil {
-			results[arabica.NSIDBrew] = records
-			totalRecords += len(records)
-		}
-	} else {
-		records, err := witnessCache.ListWitnessRecords(ctx, did, arabica.NSIDBrew)
-		if err != nil {
-			log.Debug().Err(err).Str("did", did).Msg("witness: profile brews error")
-			return nil
-		}
-		results[arabica.NSIDBrew] = records
-		totalRecords += len(records)
+	records, err := listBrews(ctx, did, arabica.NSIDBrew, brewsOffset, brewsLimit)
+	if err != nil {
+		log.Debug().Err(err).Str("did", did).Msg("witness: profile brews error")
+		return nil
 	}
+	results[arabica.NSIDBrew] = records
+	totalRecords += len(records)
 
 	// If the witness cache has zero records for this user, fall back to PDS
 	// (user may not have been backfilled/indexed yet)
@@ -232,7 +266,7 @@ func (h *Handlers) fetchProfileFromWitness(ctx context.Context, did string, brew
 	// Get total brew count from witness cache for accurate stats display.
 	totalBrews := len(brews)
 	if brewsLimit > 0 {
-		if c, err := witnessCache.CountWitnessRecords(ctx, did, arabica.NSIDBrew); err == nil {
+		if c, err := countBrews(ctx, did, arabica.NSIDBrew); err == nil {
 			totalBrews = c
 		}
 	}
@@ -247,8 +281,53 @@ func (h *Handlers) fetchProfileFromWitness(ctx context.Context, did string, brew
 	}
 }
 
-// fetchProfileFromPDS fetches all user data from their PDS via publicClient in parallel.
-func (h *Handlers) fetchProfileFromPDS(ctx context.Context, did string, publicClient *atp.PublicClient) (*ProfileDataBundle, error) {
+// Profile PDS reads page through each collection profileRecordsPageSize
+// records at a time, keeping at most maxProfileRecords per collection so a
+// huge repo can't stall the page.
+const (
+	profileRecordsPageSize = 100
+	maxProfileRecords      = 1000
+)
+
+// publicRecordLister is the part of atp.PublicClient the profile PDS path
+// uses, split out so paging can be tested without a PDS.
+type publicRecordLister interface {
+	ListPublicRecords(ctx context.Context, did, collection string, opts atp.ListPublicRecordsOpts) ([]atp.Record, string, error)
+}
+
+// listProfileRecords follows listRecords cursors through a collection,
+// newest first, until the PDS runs out or maxProfileRecords is reached. If
+// a later page fails, the records read so far are returned.
+func listProfileRecords(ctx context.Context, lister publicRecordLister, did, collection string) ([]atp.Record, error) {
+	var all []atp.Record
+	cursor := ""
+	for len(all) < maxProfileRecords {
+		records, next, err := lister.ListPublicRecords(ctx, did, collection, atp.ListPublicRecordsOpts{
+			Limit:   min(profileRecordsPageSize, maxProfileRecords-len(all)),
+			Cursor:  cursor,
+			Reverse: true,
+		})
+		if err != nil {
+			if len(all) == 0 {
+				return nil, err
+			}
+			log.Warn().Err(err).Str("did", did).Str("collection", collection).Int("records", len(all)).
+				Msg("Profile PDS read stopped early")
+			break
+		}
+		all = append(all, records...)
+		if next == "" || next == cursor || len(records) == 0 {
+			break
+		}
+		cursor = next
+	}
+	return all, nil
+}
+
+// fetchProfileFromPDS fetches all user data from their PDS in parallel.
+// Brews are windowed by brewsOffset and brewsLimit after sorting, like the
+// witness path; a zero limit keeps them all.
+func (h *Handlers) fetchProfileFromPDS(ctx context.Context, did string, lister publicRecordLister, brewsOffset, brewsLimit int, withDrafts bool) (*ProfileDataBundle, error) {
 	metrics.WitnessCacheMissesTotal.WithLabelValues("profile").Inc()
 
 	// Fetch all user data in parallel
@@ -269,7 +348,7 @@ func (h *Handlers) fetchProfileFromPDS(ctx context.Context, did string, publicCl
 
 	// Fetch beans
 	g.Go(func() error {
-		records, _, err := publicClient.ListPublicRecords(gCtx, did, arabica.NSIDBean, atp.ListPublicRecordsOpts{Limit: 100, Reverse: true})
+		records, err := listProfileRecords(gCtx, lister, did, arabica.NSIDBean)
 		if err != nil {
 			return err
 		}
@@ -292,7 +371,7 @@ func (h *Handlers) fetchProfileFromPDS(ctx context.Context, did string, publicCl
 
 	// Fetch roasters
 	g.Go(func() error {
-		records, _, err := publicClient.ListPublicRecords(gCtx, did, arabica.NSIDRoaster, atp.ListPublicRecordsOpts{Limit: 100, Reverse: true})
+		records, err := listProfileRecords(gCtx, lister, did, arabica.NSIDRoaster)
 		if err != nil {
 			return err
 		}
@@ -311,7 +390,7 @@ func (h *Handlers) fetchProfileFromPDS(ctx context.Context, did string, publicCl
 
 	// Fetch grinders
 	g.Go(func() error {
-		records, _, err := publicClient.ListPublicRecords(gCtx, did, arabica.NSIDGrinder, atp.ListPublicRecordsOpts{Limit: 100, Reverse: true})
+		records, err := listProfileRecords(gCtx, lister, did, arabica.NSIDGrinder)
 		if err != nil {
 			return err
 		}
@@ -330,7 +409,7 @@ func (h *Handlers) fetchProfileFromPDS(ctx context.Context, did string, publicCl
 
 	// Fetch brewers
 	g.Go(func() error {
-		records, _, err := publicClient.ListPublicRecords(gCtx, did, arabica.NSIDBrewer, atp.ListPublicRecordsOpts{Limit: 100, Reverse: true})
+		records, err := listProfileRecords(gCtx, lister, did, arabica.NSIDBrewer)
 		if err != nil {
 			return err
 		}
@@ -349,7 +428,7 @@ func (h *Handlers) fetchProfileFromPDS(ctx context.Context, did string, publicCl
 
 	// Fetch brews
 	g.Go(func() error {
-		records, _, err := publicClient.ListPublicRecords(gCtx, did, arabica.NSIDBrew, atp.ListPublicRecordsOpts{Limit: 100, Reverse: true})
+		records, err := listProfileRecords(gCtx, lister, did, arabica.NSIDBrew)
 		if err != nil {
 			return err
 		}
@@ -410,21 +489,36 @@ func (h *Handlers) fetchProfileFromPDS(ctx context.Context, did string, publicCl
 		}
 	}
 
+	if !withDrafts {
+		brews = withoutDrafts(brews)
+	}
+
 	// Sort brews in reverse chronological order (newest first)
 	sort.Slice(brews, func(i, j int) bool {
 		return brews[i].CreatedAt.After(brews[j].CreatedAt)
 	})
 
+	totalBrews := len(brews)
+	if brewsLimit > 0 {
+		start := min(max(brewsOffset, 0), totalBrews)
+		brews = brews[start:min(start+brewsLimit, totalBrews)]
+	}
+
 	return &ProfileDataBundle{
 		Beans:      beans,
 		Roasters:   roasters,
 		Grinders:   grinders,
 		Brewers:    brewers,
 		Brews:      brews,
-		TotalBrews: len(brews),
+		TotalBrews: totalBrews,
 	}, nil
 }
 
+// withoutDrafts drops draft brews, which are shown only to their owner.
+func withoutDrafts(brews []*arabica.Brew) []*arabica.Brew {
+	return slices.DeleteFunc(brews, func(b *arabica.Brew) bool { return b.Draft })
+}
+
 // HandleProfile displays a user's public profile with their brews and gear
 func (h *Handlers) HandleProfile(w http.ResponseWriter, r *http.Request) {
 	actor := r.PathValue("actor")
@@ -436,25 +530,12 @@ func (h *Handlers) HandleProfile(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	publicClient := atproto.NewPublicClient()
 
-	// Determine if actor is a DID or handle
-	var did string
-	var err error
-
-	if strings.HasPrefix(actor, "did:") {
-		did = actor
-	} else {
-		// Try feed index cache first, fall back to API
-		if h.FeedIndex() != nil {
-			did, _ = h.FeedIndex().GetDIDByHandle(ctx, actor)
-		}
-		if did == "" {
-			did, err = publicClient.ResolveHandle(ctx, actor)
-			if err != nil {
-				log.Warn().Err(err).Str("handle", actor).Msg("Failed to resolve handle")
-				http.Error(w, "User not found", http.StatusNotFound)
-				return
-			}
-		}
+	// Resolve the actor (DID or handle) to a DID
+	did, err := h.resolveActorDID(ctx, actor, publicClient)
+	if err != nil {
+		log.Warn().Err(err).Str("handle", actor).Msg("Failed to resolve handle")
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
 	}
 
 	// Check if user is blacklisted
@@ -487,23 +568,23 @@ func (h *Handlers) HandleProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Fetch all user data from their PDS (no pagination — we just need count for isArabicaUser check)
-	profileData, err := h.fetchUserProfileData(ctx, did, publicClient, 0, 0)
-	if err != nil {
-		log.Error().Err(err).Str("did", did).Msg("Failed to fetch user data")
-		http.Error(w, "Failed to load profile data", http.StatusInternalServerError)
-		return
+	// Check if this is an Arabica user (registered in feed or has records).
+	// Users the local index already knows skip the PDS; only unknown DIDs
+	// have their collections listed to find out.
+	isArabicaUser := h.FeedRegistry().IsRegistered(did) || h.hasIndexedRecords(ctx, did)
+	if !isArabicaUser {
+		profileData, err := h.fetchUserProfileData(ctx, did, publicClient, 0, 0, false)
+		if err != nil {
+			log.Error().Err(err).Str("did", did).Msg("Failed to fetch user data")
+			http.Error(w, "Failed to load profile data", http.StatusInternalServerError)
+			return
+		}
+		isArabicaUser = profileData.hasRecords()
 	}
 
 	// Check if current user is authenticated (for nav bar state)
 	_, didStr, isAuthenticated := h.LayoutDataFromRequest(r, "Profile")
 
-	// Check if this is an Arabica user (has records or is registered in feed)
-	isArabicaUser := h.FeedRegistry().IsRegistered(did) ||
-		len(profileData.Brews) > 0 || len(profileData.Beans) > 0 ||
-		len(profileData.Roasters) > 0 || len(profileData.Grinders) > 0 ||
-		len(profileData.Brewers) > 0
-
 	if !isArabicaUser {
 		layoutData, _, _ := h.LayoutDataFromRequest(r, "Profile Not Found")
 		w.WriteHeader(http.StatusNotFound)
@@ -538,6 +619,17 @@ func (h *Handlers) HandleProfile(w http.ResponseWriter, r *http.Request) {
 	profileProps := coffeepages.ProfileProps{
 		Profile:      viewedProfile,
 		IsOwnProfile: isOwnProfile,
+		ProfileDID:   did,
+		CanFollow:    isAuthenticated && !isOwnProfile,
+	}
+	if profileProps.CanFollow && h.FeedIndex() != nil {
+		profileProps.IsFollowing = h.FeedIndex().GetFollowRKey(r.Context(), didStr, did) != ""
+	}
+
+	// Records load separately via HTMX, so the page itself only changes with
+	// the profile header and the viewer's follow state.
+	if handlers.ServeNotModified(w, r, handlers.PageETag(layoutData, profileProps)) {
+		return
 	}
 
 	// Render using templ component
@@ -565,25 +657,12 @@ func (h *Handlers) HandleProfilePartial(w http.ResponseWriter, r *http.Request)
 		brewsLimit = 25
 	}
 
-	// Determine if actor is a DID or handle
-	var did string
-	var err error
-
-	if strings.HasPrefix(actor, "did:") {
-		did = actor
-	} else {
-		// Try feed index cache first, fall back to API
-		if h.FeedIndex() != nil {
-			did, _ = h.FeedIndex().GetDIDByHandle(ctx, actor)
-		}
-		if did == "" {
-			did, err = publicClient.ResolveHandle(ctx, actor)
-			if err != nil {
-				log.Warn().Err(err).Str("handle", actor).Msg("Failed to resolve handle")
-				http.Error(w, "User not found", http.StatusNotFound)
-				return
-			}
-		}
+	// Resolve the actor (DID or handle) to a DID
+	did, err := h.resolveActorDID(ctx, actor, publicClient)
+	if err != nil {
+		log.Warn().Err(err).Str("handle", actor).Msg("Failed to resolve handle")
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
 	}
 
 	// Check if user is blacklisted
@@ -593,8 +672,12 @@ func (h *Handlers) HandleProfilePartial(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Fetch all user data from their PDS
-	profileData, err := h.fetchUserProfileData(ctx, did, publicClient, brewsOffset, brewsLimit)
+	// Check if the viewing user is the profile owner; only they see drafts
+	didStr, isAuthenticated := atpmiddleware.GetDID(ctx)
+	isOwnProfile := isAuthenticated && didStr == did
+
+	// Fetch all user data, from the index when it knows the user
+	profileData, err := h.fetchUserProfileData(ctx, did, publicClient, brewsOffset, brewsLimit, isOwnProfile)
 	if err != nil {
 		log.Error().Err(err).Str("did", did).Msg("Failed to fetch user data for profile partial")
 		http.Error(w, "Failed to load profile data", http.StatusInternalServerError)
@@ -609,20 +692,12 @@ func (h *Handlers) HandleProfilePartial(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Check if this is an Arabica user (has records or is registered in feed)
-	isArabicaUser := h.FeedRegistry().IsRegistered(did) ||
-		len(profileData.Brews) > 0 || len(profileData.Beans) > 0 ||
-		len(profileData.Roasters) > 0 || len(profileData.Grinders) > 0 ||
-		len(profileData.Brewers) > 0
-
+	isArabicaUser := h.FeedRegistry().IsRegistered(did) || profileData.hasRecords()
 	if !isArabicaUser {
 		http.Error(w, "User not found", http.StatusNotFound)
 		return
 	}
 
-	// Check if the viewing user is the profile owner
-	didStr, isAuthenticated := atpmiddleware.GetDID(ctx)
-	isOwnProfile := isAuthenticated && didStr == did
-
 	// Get profile for card rendering — try feed index cache first
 	var profile *atproto.Profile
 	if h.FeedIndex() != nil {
@@ -710,7 +785,16 @@ func (h *Handlers) HandleProfilePartial(w http.ResponseWriter, r *http.Request)
 	}
 	brewEnd := min(brewsOffset+brewsLimit, totalBrews)
 	brewsHasMore := brewEnd < totalBrews
-	// Trim to page size (harmless no-op when witness already paginated).
+
+	// Stats use the first page of brews that was already fetched rather
+	// than loading more.
+	var brewStats *coffee.BrewStats
+	if brewsOffset == 0 && len(profileData.Brews) > 0 {
+		stats := computeBrewStats(profileData.Brews)
+		stats.TotalBrews = max(stats.TotalBrews, totalBrews)
+		brewStats = &stats
+	}
+	// Trim to page size (a no-op, since both paths already paginate).
 	if len(profileData.Brews) > brewsLimit {
 		profileData.Brews = profileData.Brews[:brewsLimit]
 	}
@@ -760,8 +844,67 @@ func (h *Handlers) HandleProfilePartial(w http.ResponseWriter, r *http.Request)
 		BrewsHasMore:          brewsHasMore,
 		BrewsNextOffset:       brewEnd,
 		TotalBrews:            totalBrews,
+		BrewStats:             brewStats,
 	}).Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render content", http.StatusInternalServerError)
 		log.Error().Err(err).Msg("Failed to render profile partial")
 	}
 }
+
+// computeBrewStats aggregates rating, ratio, method and bean usage across
+// brews. Brews missing a value are left out of that value's average, and
+// count ties go to whichever method or bean appears first in brews.
+func computeBrewStats(brews []*arabica.Brew) coffee.BrewStats {
+	stats := coffee.BrewStats{TotalBrews: len(brews), SampleSize: len(brews)}
+
+	var ratingSum, ratioSum float64
+	var rated, withRatio int
+	methodCounts := make(map[string]int)
+	beanCounts := make(map[string]int)
+	var topMethodCount, topBeanCount int
+
+	for _, brew := range brews {
+		if brew.Rating > 0 {
+			ratingSum += float64(brew.Rating)
+			rated++
+		}
+		if ratio := brew.Ratio(); ratio > 0 {
+			ratioSum += ratio
+			withRatio++
+		}
+		if method := brewMethodName(brew); method != "" {
+			methodCounts[method]++
+			if methodCounts[method] > topMethodCount {
+				topMethodCount = methodCounts[method]
+				stats.TopMethod = method
+			}
+		}
+		if brew.Bean != nil && brew.BeanRKey != "" {
+			beanCounts[brew.BeanRKey]++
+			if beanCounts[brew.BeanRKey] > topBeanCount {
+				topBeanCount = beanCounts[brew.BeanRKey]
+				stats.FavoriteBean = cmp.Or(brew.Bean.Name, brew.Bean.Origin)
+			}
+		}
+	}
+
+	if rated > 0 {
+		stats.AvgRating = ratingSum / float64(rated)
+	}
+	if withRatio > 0 {
+		stats.AvgRatio = ratioSum / float64(withRatio)
+	}
+	return stats
+}
+
+// brewMethodName prefers the method recorded on the brew, falling back to
+// the brewer's type and then its name.
+func brewMethodName(brew *arabica.Brew) string {
+	if method := brew.MethodLabel(); method != "" {
+		return method
+	}
+	if brew.BrewerObj != nil {
+		return cmp.Or(brew.BrewerObj.BrewerType, brew.BrewerObj.Name)
+	}
+	return ""
+}
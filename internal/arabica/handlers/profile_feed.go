@@ -0,0 +1,178 @@
+package coffeehandlers
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/arabica.social/arabica/internal/atproto"
+	"tangled.org/arabica.social/arabica/internal/moderation"
+	"tangled.org/pdewey.com/atp"
+
+	"github.com/rs/zerolog/log"
+)
+
+// rssFeedLimit caps how many brews a profile RSS feed carries. Aggregators
+// only look at the newest entries, so there is no need to page.
+const rssFeedLimit = 50
+
+// rssDocument is a minimal RSS 2.0 document.
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	PubDate     string  `xml:"pubDate"`
+	GUID        rssGUID `xml:"guid"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// resolveActorDID turns a /profile/{actor} path value into a DID, checking the
+// feed index's handle table and then the shared handle cache before asking
+// the network.
+func (h *Handlers) resolveActorDID(ctx context.Context, actor string, publicClient *atp.PublicClient) (string, error) {
+	if strings.HasPrefix(actor, "did:") {
+		return actor, nil
+	}
+	if h.FeedIndex() != nil {
+		if did, _ := h.FeedIndex().GetDIDByHandle(ctx, actor); did != "" {
+			return did, nil
+		}
+	}
+	return atproto.DefaultHandleCache.Resolve(ctx, actor, publicClient.ResolveHandle)
+}
+
+// HandleProfileFeedRSS serves a user's public brews as an RSS 2.0 feed at
+// /profile/{actor}/rss. A user with no brews gets an empty but valid channel.
+func (h *Handlers) HandleProfileFeedRSS(w http.ResponseWriter, r *http.Request) {
+	actor := r.PathValue("actor")
+	if actor == "" {
+		http.Error(w, "Actor parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	publicClient := atproto.NewPublicClient()
+
+	did, err := h.resolveActorDID(ctx, actor, publicClient)
+	if err != nil {
+		log.Warn().Err(err).Str("handle", actor).Msg("Failed to resolve handle for RSS feed")
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	cf := h.LoadContentFilter(ctx)
+	if cf != nil && cf.IsBlocked(did) {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	profileData, err := h.fetchUserProfileData(ctx, did, publicClient, 0, rssFeedLimit, false)
+	if err != nil {
+		log.Error().Err(err).Str("did", did).Msg("Failed to fetch user data for RSS feed")
+		http.Error(w, "Failed to load profile data", http.StatusInternalServerError)
+		return
+	}
+	brews := profileData.Brews
+	if cf != nil {
+		brews = moderation.FilterSlice(cf, brews, func(b *arabica.Brew) (string, string) {
+			return atp.BuildATURI(did, arabica.NSIDBrew, b.RKey), did
+		})
+	}
+	if len(brews) > rssFeedLimit {
+		brews = brews[:rssFeedLimit]
+	}
+
+	// Prefer the handle for titles and links; fall back to the DID when the
+	// profile can't be fetched so the feed still renders.
+	handle := did
+	var profile *atproto.Profile
+	if h.FeedIndex() != nil {
+		profile, _ = h.FeedIndex().GetProfile(ctx, did)
+	}
+	if profile == nil {
+		profile, _ = publicClient.GetProfile(ctx, did)
+	}
+	if profile != nil && profile.Handle != "" {
+		handle = profile.Handle
+	}
+
+	doc := buildBrewRSS(h.PublicBaseURL(r), handle, brews)
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		return
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		log.Error().Err(err).Str("did", did).Msg("Failed to encode RSS feed")
+	}
+}
+
+// buildBrewRSS assembles the RSS document for a user's brews. Brews are
+// expected newest first, matching fetchUserProfileData.
+func buildBrewRSS(baseURL, handle string, brews []*arabica.Brew) rssDocument {
+	profileURL := baseURL + "/profile/" + handle
+	channel := rssChannel{
+		Title:       "@" + handle + " on Arabica",
+		Link:        profileURL,
+		Description: "Brews logged by @" + handle,
+		Items:       make([]rssItem, 0, len(brews)),
+	}
+	if len(brews) > 0 {
+		channel.LastBuildDate = brews[0].CreatedAt.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, brew := range brews {
+		link := baseURL + "/brews/" + handle + "/" + brew.RKey
+		channel.Items = append(channel.Items, rssItem{
+			Title:       brewRSSTitle(brew),
+			Link:        link,
+			Description: brewRSSDescription(brew),
+			PubDate:     brew.CreatedAt.UTC().Format(time.RFC1123Z),
+			GUID:        rssGUID{IsPermaLink: true, Value: link},
+		})
+	}
+
+	return rssDocument{Version: "2.0", Channel: channel}
+}
+
+func brewRSSTitle(brew *arabica.Brew) string {
+	if brew.Bean != nil && brew.Bean.Name != "" {
+		return brew.Bean.Name
+	}
+	return "Untitled brew"
+}
+
+func brewRSSDescription(brew *arabica.Brew) string {
+	parts := make([]string, 0, 2)
+	if notes := strings.TrimSpace(brew.TastingNotes); notes != "" {
+		parts = append(parts, notes)
+	}
+	if brew.Rating > 0 {
+		parts = append(parts, fmt.Sprintf("Rating: %d/10", brew.Rating))
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -130,6 +130,7 @@ func (h *Handlers) HandleRecipeUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	req.SwapCID = handlers.SwapCID(r)
 	if err := store.UpdateRecipeByRKey(r.Context(), rkey, &req); err != nil {
 		log.Error().Err(err).Str("rkey", rkey).Msg("Failed to update recipe")
 		handlers.HandleStoreError(w, err, "Failed to update recipe")
@@ -730,7 +731,7 @@ func (h *Handlers) HandleRecipeModalNew(w http.ResponseWriter, r *http.Request)
 		brewersSlice[i] = *b
 	}
 
-	if err := coffee.RecipeDialogModal(nil, brewersSlice).Render(r.Context(), w); err != nil {
+	if err := coffee.RecipeDialogModal(nil, brewersSlice, "").Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render modal", http.StatusInternalServerError)
 		log.Error().Err(err).Msg("Failed to render recipe modal")
 	}
@@ -749,7 +750,7 @@ func (h *Handlers) HandleRecipeModalEdit(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	recipe, err := store.GetRecipeByRKey(r.Context(), rkey)
+	recipe, err := store.GetRecipeRecordByRKey(r.Context(), rkey)
 	if err != nil {
 		http.Error(w, "Recipe not found", http.StatusNotFound)
 		log.Error().Err(err).Str("rkey", rkey).Msg("Failed to get recipe for modal")
@@ -767,7 +768,7 @@ func (h *Handlers) HandleRecipeModalEdit(w http.ResponseWriter, r *http.Request)
 		brewersSlice[i] = *b
 	}
 
-	if err := coffee.RecipeDialogModal(recipe, brewersSlice).Render(r.Context(), w); err != nil {
+	if err := coffee.RecipeDialogModal(recipe.Model, brewersSlice, recipe.CID).Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render modal", http.StatusInternalServerError)
 		log.Error().Err(err).Msg("Failed to render recipe modal")
 	}
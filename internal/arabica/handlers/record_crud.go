@@ -34,14 +34,16 @@ func roasterFromUpdate(req *arabica.UpdateRoasterRequest, createdAt time.Time) *
 func grinderFromCreate(req *arabica.CreateGrinderRequest, createdAt time.Time) *arabica.Grinder {
 	return &arabica.Grinder{
 		Name: req.Name, GrinderType: req.GrinderType, BurrType: req.BurrType,
-		Notes: req.Notes, Link: req.Link, SourceRef: req.SourceRef, CreatedAt: createdAt,
+		Notes: req.Notes, Link: req.Link, GrindSettings: req.GrindSettings,
+		SourceRef: req.SourceRef, CreatedAt: createdAt,
 	}
 }
 
 func grinderFromUpdate(req *arabica.UpdateGrinderRequest, createdAt time.Time) *arabica.Grinder {
 	return &arabica.Grinder{
 		Name: req.Name, GrinderType: req.GrinderType, BurrType: req.BurrType,
-		Notes: req.Notes, Link: req.Link, SourceRef: req.SourceRef, CreatedAt: createdAt,
+		Notes: req.Notes, Link: req.Link, GrindSettings: req.GrindSettings,
+		SourceRef: req.SourceRef, CreatedAt: createdAt,
 	}
 }
 
@@ -78,6 +78,51 @@ func TestHandleGrinderUpdatePreservesCreatedAt(t *testing.T) {
 	assert.Equal(t, createdAt.Format(time.RFC3339), recordMap["createdAt"])
 }
 
+func TestHandleGrinderUpdateSwapsOnLoadedCID(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantSwap bool
+	}{
+		{"form loaded with a cid", "name=Updated+Grinder&swap_cid=loaded-cid", true},
+		{"no cid sent", "name=Updated+Grinder", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := NewTestContext()
+			tc.Handler.SetStoreOverrideForTest(tc.MockStore)
+			tc.MockStore.FetchRecordFunc = func(context.Context, string, string) (map[string]any, string, string, error) {
+				return map[string]any{}, "", "newer-cid", nil
+			}
+			var swapCID string
+			var swapped, put bool
+			tc.MockStore.UpdateRecordFunc = func(_ context.Context, _, _, cid string, _ any) (string, error) {
+				swapped, swapCID = true, cid
+				return "cid", nil
+			}
+			tc.MockStore.PutRecordFunc = func(context.Context, string, string, any) (string, string, error) {
+				put = true
+				return "", "", nil
+			}
+
+			req := newMiddlewareAuthenticatedRequest(http.MethodPut, "/api/grinders/3jzfcijpj2z2a")
+			req.SetPathValue("id", "3jzfcijpj2z2a")
+			req.Body = ioNopCloser(tt.body)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec := httptest.NewRecorder()
+
+			tc.Handler.HandleGrinderUpdate(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.wantSwap, swapped)
+			assert.Equal(t, !tt.wantSwap, put)
+			if tt.wantSwap {
+				assert.Equal(t, "loaded-cid", swapCID, "swaps on the CID the form was loaded with, not a fresh read")
+			}
+		})
+	}
+}
+
 func ioNopCloser(s string) io.ReadCloser {
 	return io.NopCloser(strings.NewReader(s))
 }
@@ -87,3 +132,48 @@ func newMiddlewareAuthenticatedRequest(method, path string) *http.Request {
 	ctx := atpmiddleware.ContextWithAuth(req.Context(), "did:plc:test123456789", "test-session-id")
 	return req.WithContext(ctx)
 }
+
+func TestHandleBeanUpdateRemainingGrams(t *testing.T) {
+	remaining := 120
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantGrams   *int
+		wantClear   bool
+	}{
+		{"form with value", "application/x-www-form-urlencoded", "name=Ardi&remaining_grams=120", &remaining, false},
+		{"form blanked", "application/x-www-form-urlencoded", "name=Ardi&remaining_grams=", nil, true},
+		{"form without field", "application/x-www-form-urlencoded", "name=Ardi", nil, false},
+		{"json without field", "application/json", `{"name":"Ardi"}`, nil, false},
+		{"json clear", "application/json", `{"name":"Ardi","clear_remaining_grams":true}`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := NewTestContext()
+			tc.Handler.SetStoreOverrideForTest(tc.MockStore)
+			var got *arabica.UpdateBeanRequest
+			tc.MockStore.UpdateBeanByRKeyFunc = func(_ context.Context, _ string, bean *arabica.UpdateBeanRequest) error {
+				got = bean
+				return nil
+			}
+			tc.MockStore.GetBeanByRKeyFunc = func(_ context.Context, rkey string) (*arabica.Bean, error) {
+				return &arabica.Bean{RKey: rkey, Name: "Ardi"}, nil
+			}
+
+			req := newMiddlewareAuthenticatedRequest(http.MethodPut, "/api/beans/3jzfcijpj2z2a")
+			req.SetPathValue("id", "3jzfcijpj2z2a")
+			req.Body = ioNopCloser(tt.body)
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+
+			tc.Handler.HandleBeanUpdate(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			if assert.NotNil(t, got) {
+				assert.Equal(t, tt.wantGrams, got.RemainingGrams)
+				assert.Equal(t, tt.wantClear, got.ClearRemainingGrams)
+			}
+		})
+	}
+}
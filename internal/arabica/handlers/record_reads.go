@@ -41,12 +41,23 @@ func listArabicaRecords[T any](ctx context.Context, store records.Store, nsid st
 }
 
 func getArabicaRecord[T any](ctx context.Context, store records.Store, nsid, rkey string, decode func(map[string]any, string) (*T, error)) (*T, error) {
-	rec, uri, _, err := store.FetchRecord(ctx, nsid, rkey)
+	m, _, err := getArabicaRecordCID(ctx, store, nsid, rkey, decode)
+	return m, err
+}
+
+// getArabicaRecordCID is getArabicaRecord plus the record's CID, which edit
+// forms send back as the update's swap CID.
+func getArabicaRecordCID[T any](ctx context.Context, store records.Store, nsid, rkey string, decode func(map[string]any, string) (*T, error)) (*T, string, error) {
+	rec, uri, cid, err := store.FetchRecord(ctx, nsid, rkey)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	if rec == nil {
-		return nil, fmt.Errorf("%s %s not found", nsid, rkey)
+		return nil, "", fmt.Errorf("%s %s not found", nsid, rkey)
+	}
+	m, err := decode(rec, uri)
+	if err != nil {
+		return nil, "", err
 	}
-	return decode(rec, uri)
+	return m, cid, nil
 }
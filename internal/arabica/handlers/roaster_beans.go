@@ -0,0 +1,120 @@
+package coffeehandlers
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	coffeepages "tangled.org/arabica.social/arabica/internal/arabica/web/pages"
+	"tangled.org/arabica.social/arabica/internal/handlers"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxRoasterBeans caps how many beans the community page loads.
+const maxRoasterBeans = 200
+
+// HandleRoasterBeans renders every bean logged against a roaster across the
+// community, grouped by bean name. Roaster records with the same name owned
+// by other users count as the same roaster.
+func (h *Handlers) HandleRoasterBeans(w http.ResponseWriter, r *http.Request) {
+	rkey := handlers.ValidateRKey(w, r.PathValue("id"))
+	if rkey == "" {
+		return
+	}
+	if h.FeedIndex() == nil {
+		http.Error(w, "Community beans are not available", http.StatusNotImplemented)
+		return
+	}
+
+	cfg := h.roasterViewConfig()
+	loaded, err := h.EntityViewLoader().Load(r, rkey, handlers.EntityLoadConfig{
+		Descriptor:  cfg.Descriptor,
+		FromWitness: cfg.FromWitness,
+		FromPDS:     cfg.FromPDS,
+		FromStore:   cfg.FromStore,
+	})
+	if err != nil {
+		if loadErr, ok := err.(*handlers.EntityLoadError); ok {
+			http.Error(w, loadErr.Msg, loadErr.HTTPStatus())
+		} else {
+			http.Error(w, "Failed to load roaster", http.StatusInternalServerError)
+		}
+		return
+	}
+	roaster := loaded.Record.(*arabica.Roaster)
+
+	records, err := h.FeedIndex().ListRoasterBeans(r.Context(), loaded.SubjectURI, maxRoasterBeans)
+	if err != nil {
+		log.Error().Err(err).Str("roaster", loaded.SubjectURI).Msg("Failed to list roaster beans")
+		http.Error(w, "Failed to load beans", http.StatusInternalServerError)
+		return
+	}
+
+	dids := make([]string, 0, len(records))
+	for _, rec := range records {
+		dids = append(dids, rec.DID)
+	}
+	profiles := h.FeedIndex().GetProfiles(r.Context(), dids)
+
+	entries := make([]coffeepages.RoasterBeanEntry, 0, len(records))
+	for _, rec := range records {
+		var m map[string]any
+		if err := json.Unmarshal(rec.Record, &m); err != nil {
+			continue
+		}
+		bean, err := arabica.RecordToBean(m, rec.URI)
+		if err != nil {
+			continue
+		}
+		handle := rec.DID
+		if p := profiles[rec.DID]; p != nil && p.Handle != "" {
+			handle = p.Handle
+		}
+		entries = append(entries, coffeepages.RoasterBeanEntry{
+			Bean:        bean,
+			OwnerHandle: handle,
+			URL:         "/beans/" + handle + "/" + rec.RKey,
+		})
+	}
+
+	owner := r.URL.Query().Get("owner")
+	if owner == "" {
+		owner, _ = atpmiddleware.GetDID(r.Context())
+	}
+	layoutData, _, _ := h.LayoutDataFromRequest(r, "Beans · "+roaster.Name)
+	props := coffeepages.RoasterBeansProps{
+		Roaster: roaster,
+		BackURL: "/roasters/" + owner + "/" + rkey,
+		Groups:  groupRoasterBeans(entries),
+	}
+	if err := coffeepages.RoasterBeans(layoutData, props).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		log.Error().Err(err).Msg("Failed to render roaster beans page")
+	}
+}
+
+// groupRoasterBeans buckets beans that share a name (ignoring case and
+// surrounding space), keeping first-seen order so the newest bean's group
+// leads. The group takes its display name and origin from its first entry.
+func groupRoasterBeans(entries []coffeepages.RoasterBeanEntry) []coffeepages.RoasterBeanGroup {
+	var groups []coffeepages.RoasterBeanGroup
+	byKey := make(map[string]int)
+	for _, e := range entries {
+		key := strings.ToLower(strings.TrimSpace(e.Bean.Name))
+		i, ok := byKey[key]
+		if !ok {
+			i = len(groups)
+			byKey[key] = i
+			groups = append(groups, coffeepages.RoasterBeanGroup{
+				Name:   cmp.Or(strings.TrimSpace(e.Bean.Name), "Unnamed bean"),
+				Origin: e.Bean.Origin,
+			})
+		}
+		groups[i].Entries = append(groups[i].Entries, e)
+	}
+	return groups
+}
@@ -19,7 +19,11 @@ func (Routes) RegisterAppRoutes(mux *http.ServeMux, ctx routing.AppRouteContext)
 
 	mux.HandleFunc("GET /api/data", h.HandleAPIListAll)
 
-	mux.Handle("GET /api/brews", middleware.RequireHTMXMiddleware(http.HandlerFunc(h.HandleBrewListPartial)))
+	// Not HTMX-only: the brew list filters are plain query params, so a
+	// filtered list can be fetched directly.
+	mux.HandleFunc("GET /api/brews", h.HandleBrewListPartial)
+	mux.HandleFunc("GET /api/brews/{id}", h.HandleBrewGetAPI)
+	mux.Handle("GET /brews/search", middleware.RequireHTMXMiddleware(http.HandlerFunc(h.HandleBrewSearch)))
 	mux.Handle("GET /api/manage", middleware.RequireHTMXMiddleware(http.HandlerFunc(h.HandleManagePartial)))
 	mux.Handle("GET /api/incomplete-records", middleware.RequireHTMXMiddleware(http.HandlerFunc(h.HandleIncompleteRecordsPartial)))
 	mux.Handle("GET /api/profile/{actor}", middleware.RequireHTMXMiddleware(http.HandlerFunc(h.HandleProfilePartial)))
@@ -32,16 +36,35 @@ func (Routes) RegisterAppRoutes(mux *http.ServeMux, ctx routing.AppRouteContext)
 	mux.HandleFunc("GET /add", h.HandleAddRecords)
 	mux.HandleFunc("GET /my-coffee", h.HandleMyCoffee)
 	mux.HandleFunc("GET /explore", h.HandleExplore)
+	mux.HandleFunc("GET /discover/roasters", h.HandleDiscoverRoasters)
+	mux.HandleFunc("GET /search", h.HandleSearch)
+	mux.HandleFunc("GET /tags/{tag}", h.HandleTag)
+	mux.HandleFunc("GET /settings/brew-defaults", h.HandleBrewDefaults)
+	mux.Handle("POST /settings/brew-defaults", cop.Handler(http.HandlerFunc(h.HandleBrewDefaultsUpdate)))
 	mux.HandleFunc("GET /manage", h.HandleManage)
 	mux.HandleFunc("GET /brews", h.HandleBrewList)
 	mux.HandleFunc("GET /brews/new", h.HandleBrewNew)
 	mux.HandleFunc("GET /brews/{id}/edit", h.HandleBrewEdit)
+	mux.HandleFunc("GET /brews/{id}/clone", h.HandleBrewClone)
+	mux.HandleFunc("GET /brews/{id}/recipe.txt", h.HandleBrewRecipeText)
 	mux.HandleFunc("GET /brews/{actor}/{id}/og-image", routing.RewriteActorToOwner(h.HandleBrewOGImage))
 	mux.HandleFunc("GET /brews/{actor}/{id}", routing.RewriteActorToOwner(h.HandleBrewView))
-	mux.Handle("POST /brews", cop.Handler(http.HandlerFunc(h.HandleBrewCreate)))
+	// Short permalinks, what the share button hands out.
+	mux.HandleFunc("GET /b/{actor}/{id}/og-image", routing.RewriteActorToOwner(h.HandleBrewOGImage))
+	mux.HandleFunc("GET /b/{actor}/{id}", routing.RewriteActorToOwner(h.HandleBrewView))
+	mux.HandleFunc("GET /oembed", h.HandleOEmbed)
+	mux.Handle("POST /api/brews/validate", cop.Handler(http.HandlerFunc(h.HandleBrewValidate)))
+	mux.Handle("POST /brews", ctx.Create(http.HandlerFunc(h.HandleBrewCreate)))
 	mux.Handle("PUT /brews/{id}", cop.Handler(http.HandlerFunc(h.HandleBrewUpdate)))
 	mux.Handle("DELETE /brews/{id}", cop.Handler(http.HandlerFunc(h.HandleBrewDelete)))
+	mux.Handle("POST /brews/{id}/publish", cop.Handler(http.HandlerFunc(h.HandleBrewPublish)))
 	mux.HandleFunc("GET /brews/export", h.HandleBrewExport)
+	mux.HandleFunc("GET /brews/export.csv", h.HandleBrewExportCSV)
+	mux.HandleFunc("GET /brews/compare", h.HandleBrewCompare)
+	mux.HandleFunc("GET /account/export", h.HandleAccountExport)
+	// Import is one request however many brews it carries, so it stays off
+	// the per-user create limit.
+	mux.Handle("POST /brews/import", cop.Handler(http.HandlerFunc(h.HandleBrewImport)))
 	mux.HandleFunc("GET /beans/new", h.HandleBeanNew)
 	mux.HandleFunc("GET /beans/{id}/edit", h.HandleBeanEdit)
 
@@ -53,17 +76,19 @@ func (Routes) RegisterAppRoutes(mux *http.ServeMux, ctx routing.AppRouteContext)
 	mux.HandleFunc("GET /api/recipes", h.HandleRecipeList)
 	mux.HandleFunc("GET /api/recipes/suggestions", h.HandleRecipeSuggestions)
 	mux.HandleFunc("GET /api/recipes/{id}", h.HandleRecipeGet)
-	mux.Handle("POST /api/recipes", cop.Handler(http.HandlerFunc(h.HandleRecipeCreate)))
+	mux.Handle("POST /api/recipes", ctx.Create(http.HandlerFunc(h.HandleRecipeCreate)))
 	mux.Handle("PUT /api/recipes/{id}", cop.Handler(http.HandlerFunc(h.HandleRecipeUpdate)))
 	mux.Handle("DELETE /api/recipes/{id}", cop.Handler(http.HandlerFunc(h.HandleRecipeDelete)))
-	mux.Handle("POST /api/recipes/from-brew/{id}", cop.Handler(http.HandlerFunc(h.HandleRecipeCreateFromBrew)))
-	mux.Handle("POST /api/recipes/fork/{id}", cop.Handler(http.HandlerFunc(h.HandleRecipeFork)))
+	mux.Handle("POST /api/recipes/from-brew/{id}", ctx.Create(http.HandlerFunc(h.HandleRecipeCreateFromBrew)))
+	mux.Handle("POST /api/recipes/fork/{id}", ctx.Create(http.HandlerFunc(h.HandleRecipeFork)))
 
 	mux.HandleFunc("GET /api/modals/recipe/new", h.HandleRecipeModalNew)
 	mux.HandleFunc("GET /api/modals/recipe/{id}", h.HandleRecipeModalEdit)
 
-	routing.RegisterEntityRoutes(mux, cop, ctx.App, h.EntityRouteBundles())
+	routing.RegisterEntityRoutes(mux, ctx, h.EntityRouteBundles())
+	mux.HandleFunc("GET /roasters/{actor}/{id}/beans", routing.RewriteActorToOwner(h.HandleRoasterBeans))
 	mux.HandleFunc("GET /profile/{actor}", h.HandleProfile)
+	mux.HandleFunc("GET /profile/{actor}/rss", h.HandleProfileFeedRSS)
 }
 
 // EntityRouteBundles returns the per-entity handler bundles for arabica's
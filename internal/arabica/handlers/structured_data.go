@@ -0,0 +1,138 @@
+package coffeehandlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+)
+
+// brewStructuredData is the schema.org Recipe describing a brew page. Only
+// fields search engines use for rich results are filled in.
+type brewStructuredData struct {
+	Context              string                    `json:"@context"`
+	Type                 string                    `json:"@type"`
+	Name                 string                    `json:"name"`
+	URL                  string                    `json:"url,omitempty"`
+	Image                string                    `json:"image,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	DatePublished        string                    `json:"datePublished,omitempty"`
+	Author               *schemaPerson             `json:"author,omitempty"`
+	RecipeCategory       string                    `json:"recipeCategory"`
+	CookingMethod        string                    `json:"cookingMethod,omitempty"`
+	RecipeIngredient     []string                  `json:"recipeIngredient,omitempty"`
+	TotalTime            string                    `json:"totalTime,omitempty"`
+	AggregateRating      *schemaAggregateRating    `json:"aggregateRating,omitempty"`
+	InteractionStatistic *schemaInteractionCounter `json:"interactionStatistic,omitempty"`
+}
+
+type schemaPerson struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type schemaAggregateRating struct {
+	Type        string `json:"@type"`
+	RatingValue int    `json:"ratingValue"`
+	BestRating  int    `json:"bestRating"`
+	WorstRating int    `json:"worstRating"`
+	RatingCount int    `json:"ratingCount"`
+}
+
+type schemaInteractionCounter struct {
+	Type                 string `json:"@type"`
+	InteractionType      string `json:"interactionType"`
+	UserInteractionCount int    `json:"userInteractionCount"`
+}
+
+// brewPageInfo carries the page-level details that aren't on the record.
+type brewPageInfo struct {
+	PageURL      string
+	ImageURL     string
+	AuthorHandle string
+	AuthorName   string
+	LikeCount    int
+}
+
+// buildBrewStructuredData renders the JSON-LD payload for a brew page. The
+// rating is the author's own 1-10 score, so it is reported with a count of
+// one; likes go in interactionStatistic, which is what schema.org has for
+// them. Returns "" if marshalling fails, which just omits the block.
+func buildBrewStructuredData(brew *arabica.Brew, page brewPageInfo) string {
+	data := brewStructuredData{
+		Context:        "https://schema.org",
+		Type:           "Recipe",
+		Name:           brewBeanSummary(brew),
+		URL:            page.PageURL,
+		Image:          page.ImageURL,
+		Description:    brew.TastingNotes,
+		RecipeCategory: "Coffee",
+		CookingMethod:  brew.MethodLabel(),
+	}
+	if data.Name == "" {
+		data.Name = "Brew"
+	}
+	if !brew.CreatedAt.IsZero() {
+		data.DatePublished = brew.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	if page.AuthorHandle != "" {
+		name := page.AuthorName
+		if name == "" {
+			name = page.AuthorHandle
+		}
+		data.Author = &schemaPerson{Type: "Person", Name: name}
+		if u, err := url.Parse(page.PageURL); err == nil && u.Host != "" {
+			data.Author.URL = u.Scheme + "://" + u.Host + "/profile/" + page.AuthorHandle
+		}
+	}
+	if brew.CoffeeAmount > 0 {
+		coffee := fmt.Sprintf("%dg coffee", brew.CoffeeAmount)
+		if brew.Bean != nil && brew.Bean.Name != "" {
+			coffee = fmt.Sprintf("%dg %s", brew.CoffeeAmount, brew.Bean.Name)
+		}
+		data.RecipeIngredient = append(data.RecipeIngredient, coffee)
+	}
+	if brew.WaterAmount > 0 {
+		data.RecipeIngredient = append(data.RecipeIngredient, fmt.Sprintf("%dg water", brew.WaterAmount))
+	}
+	if brew.TimeSeconds > 0 {
+		data.TotalTime = isoDuration(brew.TimeSeconds)
+	}
+	if brew.Rating > 0 {
+		data.AggregateRating = &schemaAggregateRating{
+			Type:        "AggregateRating",
+			RatingValue: brew.Rating,
+			BestRating:  10,
+			WorstRating: 1,
+			RatingCount: 1,
+		}
+	}
+	if page.LikeCount > 0 {
+		data.InteractionStatistic = &schemaInteractionCounter{
+			Type:                 "InteractionCounter",
+			InteractionType:      "https://schema.org/LikeAction",
+			UserInteractionCount: page.LikeCount,
+		}
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return ""
+	}
+	return string(payload)
+}
+
+// isoDuration formats seconds as an ISO 8601 duration, e.g. 195 -> "PT3M15S".
+func isoDuration(seconds int) string {
+	m, s := seconds/60, seconds%60
+	switch {
+	case m == 0:
+		return fmt.Sprintf("PT%dS", s)
+	case s == 0:
+		return fmt.Sprintf("PT%dM", m)
+	}
+	return fmt.Sprintf("PT%dM%dS", m, s)
+}
@@ -0,0 +1,71 @@
+package coffeehandlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildBrewStructuredData(t *testing.T) {
+	fixtures := NewTestFixtures()
+	brew := fixtures.Brew
+	brew.CreatedAt = time.Date(2026, 5, 1, 8, 30, 0, 0, time.UTC)
+	brew.TastingNotes = "Jammy </script><script>alert(1)</script>"
+
+	payload := buildBrewStructuredData(brew, brewPageInfo{
+		PageURL:      "https://arabica.test/brews/alice.test/test-brew-rkey",
+		ImageURL:     "https://arabica.test/brews/alice.test/test-brew-rkey/og-image",
+		AuthorHandle: "alice.test",
+		AuthorName:   "Alice",
+		LikeCount:    3,
+	})
+	assert.NotContains(t, payload, "</script>", "payload must be safe to inline in a script tag")
+
+	var got brewStructuredData
+	require.NoError(t, json.Unmarshal([]byte(payload), &got))
+	assert.Equal(t, "https://schema.org", got.Context)
+	assert.Equal(t, "Recipe", got.Type)
+	assert.Equal(t, "Test Bean from Test Roaster", got.Name)
+	assert.Equal(t, "V60", got.CookingMethod)
+	assert.Equal(t, "2026-05-01T08:30:00Z", got.DatePublished)
+	assert.Equal(t, []string{"15g Test Bean", "250g water"}, got.RecipeIngredient)
+	assert.Equal(t, "PT3M", got.TotalTime)
+	require.NotNil(t, got.Author)
+	assert.Equal(t, "Alice", got.Author.Name)
+	assert.Equal(t, "https://arabica.test/profile/alice.test", got.Author.URL)
+	require.NotNil(t, got.AggregateRating)
+	assert.Equal(t, 8, got.AggregateRating.RatingValue)
+	assert.Equal(t, 10, got.AggregateRating.BestRating)
+	require.NotNil(t, got.InteractionStatistic)
+	assert.Equal(t, 3, got.InteractionStatistic.UserInteractionCount)
+}
+
+func TestBuildBrewStructuredDataMinimal(t *testing.T) {
+	payload := buildBrewStructuredData(&arabica.Brew{}, brewPageInfo{})
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal([]byte(payload), &got))
+	assert.Equal(t, "Brew", got["name"])
+	for _, key := range []string{"author", "aggregateRating", "interactionStatistic", "recipeIngredient", "totalTime", "datePublished"} {
+		assert.NotContains(t, got, key)
+	}
+}
+
+func TestISODuration(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    string
+	}{
+		{45, "PT45S"},
+		{180, "PT3M"},
+		{195, "PT3M15S"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, isoDuration(tt.seconds))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 
 	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
 	coffeeogcard "tangled.org/arabica.social/arabica/internal/arabica/ogcard"
@@ -52,6 +53,7 @@ func (h *Handlers) roasterViewConfig() handlers.EntityViewConfig {
 					ownerDID = base.CurrentUserDID
 				}
 				props.BeanCount = h.FeedIndex().BeanCountsByRoasterURI(ctx, ownerDID)[base.SubjectURI]
+				props.CommunityBeansURL = "/roasters/" + ownerDID + "/" + roaster.RKey + "/beans"
 			}
 			return coffeepages.RoasterView(layoutData, props).Render(ctx, w)
 		},
@@ -118,7 +120,7 @@ func (h *Handlers) brewerViewConfig() handlers.EntityViewConfig {
 	}
 }
 
-func (h *Handlers) beanViewConfig() handlers.EntityViewConfig {
+func (h *Handlers) beanViewConfig(r *http.Request) handlers.EntityViewConfig {
 	fromWitness, fromPDS, fromStore := handlers.StandardViewTriple(
 		arabica.NSIDBean, arabica.RecordToBean,
 		func(b *arabica.Bean, k string) { b.RKey = k },
@@ -156,6 +158,7 @@ func (h *Handlers) beanViewConfig() handlers.EntityViewConfig {
 				}
 				props.BrewCount = h.FeedIndex().BrewCountsByBeanURI(ctx, ownerDID)[base.SubjectURI]
 			}
+			props.SameRoasterBeans = h.sameRoasterBeans(r, bean, base)
 			return coffeepages.BeanView(layoutData, props).Render(ctx, w)
 		},
 	}
@@ -163,11 +166,63 @@ func (h *Handlers) beanViewConfig() handlers.EntityViewConfig {
 
 // HandleBeanView shows a bean detail page with social features
 func (h *Handlers) HandleBeanView(w http.ResponseWriter, r *http.Request) {
-	h.RenderEntityView(w, r, h.beanViewConfig())
+	h.RenderEntityView(w, r, h.beanViewConfig(r))
 }
 
 func (h *Handlers) HandleBeanBacklinks(w http.ResponseWriter, r *http.Request) {
-	h.RenderBacklinksView(w, r, h.beanViewConfig())
+	h.RenderBacklinksView(w, r, h.beanViewConfig(r))
+}
+
+// maxSameRoasterBeans caps the "more from this roaster" list on a bean page.
+const maxSameRoasterBeans = 4
+
+// sameRoasterBeans lists other beans in the owner's collection that share
+// bean's roaster, newest first. The owner's own view reads the session's
+// cached beans; anyone else's goes to the owner's PDS.
+func (h *Handlers) sameRoasterBeans(r *http.Request, bean *arabica.Bean, base pages.EntityViewBase) []*arabica.Bean {
+	if bean.RoasterRKey == "" {
+		return nil
+	}
+
+	var beans []*arabica.Bean
+	if base.IsOwnProfile {
+		store, ok := h.GetArabicaStore(r)
+		if !ok {
+			return nil
+		}
+		var err error
+		beans, err = store.ListBeans(r.Context())
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to list beans for same-roaster section")
+			return nil
+		}
+	} else if base.AuthorDID != "" {
+		beans = handlers.ListPublicRecords(r.Context(), atproto.NewPublicClient(), base.AuthorDID, arabica.NSIDBean,
+			func(m map[string]any, uri string) (*arabica.Bean, error) {
+				b, err := arabica.RecordToBean(m, uri)
+				if err != nil {
+					return nil, err
+				}
+				if ref, ok := m["roasterRef"].(string); ok {
+					b.RoasterRKey = atp.RKeyFromURI(ref)
+				}
+				return b, nil
+			})
+	}
+
+	var siblings []*arabica.Bean
+	for _, b := range beans {
+		if b.RoasterRKey == bean.RoasterRKey && b.RKey != bean.RKey {
+			siblings = append(siblings, b)
+		}
+	}
+	slices.SortStableFunc(siblings, func(a, b *arabica.Bean) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	if len(siblings) > maxSameRoasterBeans {
+		siblings = siblings[:maxSameRoasterBeans]
+	}
+	return siblings
 }
 
 // HandleRoasterView shows a roaster detail page with social features
@@ -302,20 +357,15 @@ func (h *Handlers) brewViewConfig() handlers.EntityViewConfig {
 			arabicastore.ExtractBrewRefRKeys(brew, raw)
 			arabica.HydrateBrewRefs(brew, raw, lookup)
 		},
+		SharePath:   brewSharePath,
 		DisplayName: func(any) string { return "Brew Details" },
-		OGSubtitle: func(record any) string {
-			brew := record.(*arabica.Brew)
-			var sub string
-			if brew.Bean != nil {
-				sub = brew.Bean.Name
-				if brew.Bean.Roaster != nil && brew.Bean.Roaster.Name != "" {
-					sub += " from " + brew.Bean.Roaster.Name
-				}
-			}
-			return sub
-		},
+		OGSubtitle:  func(record any) string { return brewBeanSummary(record.(*arabica.Brew)) },
+		OwnerOnly:   func(record any) bool { return record.(*arabica.Brew).Draft },
 		Render: func(ctx context.Context, w http.ResponseWriter, layoutData *components.LayoutData, record any, base pages.EntityViewBase) error {
 			brew := record.(*arabica.Brew)
+			if layoutData.OGUrl != "" {
+				layoutData.OEmbedURL = oEmbedURL(layoutData.OGUrl)
+			}
 			props := coffeepages.BrewViewProps{
 				Brew:              brew,
 				IsOwnProfile:      base.IsOwnProfile,
@@ -323,6 +373,7 @@ func (h *Handlers) brewViewConfig() handlers.EntityViewConfig {
 				SubjectURI:        base.SubjectURI,
 				SubjectCID:        base.SubjectCID,
 				IsLiked:           base.IsLiked,
+				IsBookmarked:      base.IsBookmarked,
 				LikeCount:         base.LikeCount,
 				CommentCount:      base.CommentCount,
 				Comments:          base.Comments,
@@ -336,6 +387,14 @@ func (h *Handlers) brewViewConfig() handlers.EntityViewConfig {
 				AuthorHandle:      base.AuthorHandle,
 				AuthorDisplayName: base.AuthorDisplayName,
 				AuthorAvatar:      base.AuthorAvatar,
+				StructuredData: buildBrewStructuredData(brew, brewPageInfo{
+					PageURL:      layoutData.OGUrl,
+					ImageURL:     layoutData.OGImage,
+					AuthorHandle: base.AuthorHandle,
+					AuthorName:   base.AuthorDisplayName,
+					LikeCount:    base.LikeCount,
+				}),
+				PourSchedule: arabica.NewPourSchedule(brew.Pours),
 			}
 			return coffeepages.BrewView(layoutData, props).Render(ctx, w)
 		},
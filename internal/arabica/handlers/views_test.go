@@ -0,0 +1,55 @@
+package coffeehandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/arabica.social/arabica/internal/web/pages"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSameRoasterBeans(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	tc := NewTestContext()
+	tc.Handler.SetStoreOverrideForTest(tc.MockStore)
+	tc.MockStore.ListBeansFunc = func(ctx context.Context) ([]*arabica.Bean, error) {
+		return []*arabica.Bean{
+			{RKey: "current", RoasterRKey: "r1", CreatedAt: day(1)},
+			{RKey: "old", RoasterRKey: "r1", CreatedAt: day(2)},
+			{RKey: "other-roaster", RoasterRKey: "r2", CreatedAt: day(3)},
+			{RKey: "b3", RoasterRKey: "r1", CreatedAt: day(3)},
+			{RKey: "b4", RoasterRKey: "r1", CreatedAt: day(4)},
+			{RKey: "b5", RoasterRKey: "r1", CreatedAt: day(5)},
+			{RKey: "b6", RoasterRKey: "r1", CreatedAt: day(6)},
+		}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/beans/alice.test/current", nil)
+	req = req.WithContext(atpmiddleware.ContextWithAuth(req.Context(), "did:plc:test123456789", "test-session-id"))
+	base := pages.EntityViewBase{IsOwnProfile: true}
+
+	tests := []struct {
+		name  string
+		bean  *arabica.Bean
+		rkeys []string
+	}{
+		{"newest first, current excluded, capped", &arabica.Bean{RKey: "current", RoasterRKey: "r1"}, []string{"b6", "b5", "b4", "b3"}},
+		{"only bean from its roaster", &arabica.Bean{RKey: "other-roaster", RoasterRKey: "r2"}, nil},
+		{"no roaster", &arabica.Bean{RKey: "current"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rkeys []string
+			for _, b := range tc.Handler.sameRoasterBeans(req, tt.bean, base) {
+				rkeys = append(rkeys, b.RKey)
+			}
+			assert.Equal(t, tt.rkeys, rkeys)
+		})
+	}
+}
@@ -70,6 +70,10 @@ func (s *fakeBrewPrerequisiteStore) PutRecord(ctx context.Context, nsid, rkey st
 	return rkey, "", nil
 }
 
+func (s *fakeBrewPrerequisiteStore) UpdateRecord(ctx context.Context, nsid, rkey, swapCID string, record any) (string, error) {
+	return "", nil
+}
+
 func (s *fakeBrewPrerequisiteStore) RemoveRecord(ctx context.Context, nsid, rkey string) error {
 	return nil
 }
@@ -3,6 +3,7 @@ package arabicastore
 import (
 	"context"
 	"fmt"
+	"maps"
 	"time"
 
 	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
@@ -86,6 +87,7 @@ func brewModelFromRequest(req *arabica.CreateBrewRequest, createdAt time.Time) *
 		GrinderRKey:  req.GrinderRKey,
 		BrewerRKey:   req.BrewerRKey,
 		Method:       req.Method,
+		MethodOther:  req.MethodOther,
 		Temperature:  req.Temperature,
 		WaterAmount:  req.WaterAmount,
 		CoffeeAmount: req.CoffeeAmount,
@@ -93,6 +95,10 @@ func brewModelFromRequest(req *arabica.CreateBrewRequest, createdAt time.Time) *
 		GrindSize:    req.GrindSize,
 		TastingNotes: req.TastingNotes,
 		Rating:       req.Rating,
+		TDS:          req.TDS,
+		Tags:         req.Tags,
+		Draft:        req.Draft,
+		Tasting:      req.Tasting,
 		CreatedAt:    createdAt,
 	}
 	if len(req.Pours) > 0 {
@@ -106,6 +112,7 @@ func brewModelFromRequest(req *arabica.CreateBrewRequest, createdAt time.Time) *
 	}
 	brew.EspressoParams = req.EspressoParams
 	brew.PouroverParams = req.PouroverParams
+	brew.Image = req.Image
 	return brew
 }
 
@@ -163,7 +170,11 @@ func (s *AtprotoStore) CreateBrew(ctx context.Context, brew *arabica.CreateBrewR
 		recipeURI = atp.BuildATURI(recipeOwner, arabica.NSIDRecipe, brew.RecipeRKey)
 	}
 
-	model := brewModelFromRequest(brew, time.Now().UTC())
+	createdAt := time.Now().UTC()
+	if !brew.CreatedAt.IsZero() {
+		createdAt = brew.CreatedAt.UTC()
+	}
+	model := brewModelFromRequest(brew, createdAt)
 	record, err := arabica.BrewToRecord(model, beanURI, grinderURI, brewerURI, recipeURI)
 	if err != nil {
 		return nil, fmt.Errorf("convert brew: %w", err)
@@ -340,16 +351,39 @@ func (s *AtprotoStore) UpdateBrewByRKey(ctx context.Context, rkey string, brew *
 		recipeURI = atp.BuildATURI(recipeOwner, arabica.NSIDRecipe, brew.RecipeRKey)
 	}
 
-	existing, err := s.GetBrewByRKey(ctx, rkey)
+	existing, err := s.GetBrewRecordByRKey(ctx, rkey)
 	if err != nil {
 		return fmt.Errorf("get existing brew: %w", err)
 	}
-	model := brewModelFromRequest(brew, existing.CreatedAt)
+	model := brewModelFromRequest(brew, existing.Model.CreatedAt)
+	if model.Image == nil && !brew.RemoveImage {
+		model.Image = existing.Model.Image
+	}
 	record, err := arabica.BrewToRecord(model, beanURI, grinderURI, brewerURI, recipeURI)
 	if err != nil {
 		return fmt.Errorf("convert brew: %w", err)
 	}
-	_, _, err = s.AtprotoStore.PutRecord(ctx, arabica.NSIDBrew, rkey, record)
+	// Swap on the CID the edit form was loaded with so an edit made from
+	// another tab or client since then is refused instead of overwritten.
+	_, err = s.AtprotoStore.UpdateRecord(ctx, arabica.NSIDBrew, rkey, brew.SwapCID, record)
+	return err
+}
+
+// PublishBrewByRKey rewrites the stored record minus its draft flag rather
+// than round-tripping it through a request, so nothing else about the brew
+// changes. It swaps on the CID it read, so an edit landing in between is
+// refused rather than overwritten.
+func (s *AtprotoStore) PublishBrewByRKey(ctx context.Context, rkey string) error {
+	record, _, cid, err := s.AtprotoStore.FetchRecord(ctx, arabica.NSIDBrew, rkey)
+	if err != nil {
+		return fmt.Errorf("get brew: %w", err)
+	}
+	if draft, _ := record["draft"].(bool); !draft {
+		return nil
+	}
+	record = maps.Clone(record)
+	delete(record, "draft")
+	_, err = s.AtprotoStore.UpdateRecord(ctx, arabica.NSIDBrew, rkey, cid, record)
 	return err
 }
 
@@ -419,6 +453,11 @@ func (s *AtprotoStore) ResolveBeanRefs(ctx context.Context, bean *arabica.Bean,
 }
 
 func (s *AtprotoStore) CreateBean(ctx context.Context, bean *arabica.CreateBeanRequest) (*arabica.Bean, error) {
+	// A new bag with a known size starts full unless told otherwise
+	remaining := bean.RemainingGrams
+	if remaining == nil && bean.BagSizeGrams > 0 {
+		remaining = &bean.BagSizeGrams
+	}
 	return atproto.CreateEntity(ctx, s, beanCodec, &arabica.Bean{
 		Name:        bean.Name,
 		Origin:      bean.Origin,
@@ -434,6 +473,9 @@ func (s *AtprotoStore) CreateBean(ctx context.Context, bean *arabica.CreateBeanR
 		Closed:      bean.Closed,
 		SourceRef:   bean.SourceRef,
 		CreatedAt:   time.Now().UTC(),
+
+		BagSizeGrams:   bean.BagSizeGrams,
+		RemainingGrams: remaining,
 	})
 }
 
@@ -465,11 +507,15 @@ func LinkBeansToRoasters(beans []*arabica.Bean, roasters []*arabica.Roaster) {
 }
 
 func (s *AtprotoStore) UpdateBeanByRKey(ctx context.Context, rkey string, bean *arabica.UpdateBeanRequest) error {
-	existing, err := s.GetBeanByRKey(ctx, rkey)
+	existing, err := s.GetBeanRecordByRKey(ctx, rkey)
 	if err != nil {
 		return fmt.Errorf("get existing bean: %w", err)
 	}
-	return atproto.UpdateEntity(ctx, s, beanCodec, rkey, &arabica.Bean{
+	remaining := bean.RemainingGrams
+	if remaining == nil && !bean.ClearRemainingGrams {
+		remaining = existing.Model.RemainingGrams
+	}
+	return atproto.UpdateEntity(ctx, s, beanCodec, rkey, bean.SwapCID, &arabica.Bean{
 		Name:        bean.Name,
 		Origin:      bean.Origin,
 		Variety:     bean.Variety,
@@ -483,10 +529,34 @@ func (s *AtprotoStore) UpdateBeanByRKey(ctx context.Context, rkey string, bean *
 		Rating:      bean.Rating,
 		Closed:      bean.Closed,
 		SourceRef:   bean.SourceRef,
-		CreatedAt:   existing.CreatedAt,
+		CreatedAt:   existing.Model.CreatedAt,
+
+		BagSizeGrams:   bean.BagSizeGrams,
+		RemainingGrams: remaining,
 	})
 }
 
+// AdjustBeanInventory adds deltaGrams (negative to consume) to the bean's
+// remaining grams, clamping at zero, and writes the bean back. Beans that
+// don't track inventory are left untouched. PutRecord invalidates the
+// session's bean cache.
+func (s *AtprotoStore) AdjustBeanInventory(ctx context.Context, rkey string, deltaGrams int) error {
+	existing, err := s.GetBeanRecordByRKey(ctx, rkey)
+	if err != nil {
+		return fmt.Errorf("get bean: %w", err)
+	}
+	bean := existing.Model
+	if bean.RemainingGrams == nil || deltaGrams == 0 {
+		return nil
+	}
+	remaining := max(*bean.RemainingGrams+deltaGrams, 0)
+	if remaining == *bean.RemainingGrams {
+		return nil
+	}
+	bean.RemainingGrams = &remaining
+	return atproto.UpdateEntity(ctx, s, beanCodec, rkey, existing.CID, bean)
+}
+
 func (s *AtprotoStore) DeleteBeanByRKey(ctx context.Context, rkey string) error {
 	return atproto.DeleteEntity(ctx, s, arabica.NSIDBean, rkey)
 }
@@ -514,16 +584,16 @@ func (s *AtprotoStore) ListRoasters(ctx context.Context) ([]*arabica.Roaster, er
 }
 
 func (s *AtprotoStore) UpdateRoasterByRKey(ctx context.Context, rkey string, roaster *arabica.UpdateRoasterRequest) error {
-	existing, err := s.GetRoasterByRKey(ctx, rkey)
+	existing, err := s.GetRoasterRecordByRKey(ctx, rkey)
 	if err != nil {
 		return fmt.Errorf("get existing roaster: %w", err)
 	}
-	err = atproto.UpdateEntity(ctx, s, roasterCodec, rkey, &arabica.Roaster{
+	err = atproto.UpdateEntity(ctx, s, roasterCodec, rkey, existing.CID, &arabica.Roaster{
 		Name:      roaster.Name,
 		Location:  roaster.Location,
 		Website:   roaster.Website,
 		SourceRef: roaster.SourceRef,
-		CreatedAt: existing.CreatedAt,
+		CreatedAt: existing.Model.CreatedAt,
 	})
 	if err != nil {
 		return err
@@ -546,13 +616,14 @@ func (s *AtprotoStore) DeleteRoasterByRKey(ctx context.Context, rkey string) err
 
 func (s *AtprotoStore) CreateGrinder(ctx context.Context, grinder *arabica.CreateGrinderRequest) (*arabica.Grinder, error) {
 	return atproto.CreateEntity(ctx, s, grinderCodec, &arabica.Grinder{
-		Name:        grinder.Name,
-		GrinderType: grinder.GrinderType,
-		BurrType:    grinder.BurrType,
-		Notes:       grinder.Notes,
-		Link:        grinder.Link,
-		SourceRef:   grinder.SourceRef,
-		CreatedAt:   time.Now().UTC(),
+		Name:          grinder.Name,
+		GrinderType:   grinder.GrinderType,
+		BurrType:      grinder.BurrType,
+		Notes:         grinder.Notes,
+		Link:          grinder.Link,
+		GrindSettings: grinder.GrindSettings,
+		SourceRef:     grinder.SourceRef,
+		CreatedAt:     time.Now().UTC(),
 	})
 }
 
@@ -667,7 +738,7 @@ func (s *AtprotoStore) ListRecipes(ctx context.Context) ([]*arabica.Recipe, erro
 }
 
 func (s *AtprotoStore) UpdateRecipeByRKey(ctx context.Context, rkey string, req *arabica.UpdateRecipeRequest) error {
-	existing, err := s.GetRecipeByRKey(ctx, rkey)
+	existing, err := s.GetRecipeRecordByRKey(ctx, rkey)
 	if err != nil {
 		return fmt.Errorf("get existing recipe: %w", err)
 	}
@@ -678,8 +749,8 @@ func (s *AtprotoStore) UpdateRecipeByRKey(ctx context.Context, rkey string, req
 		CoffeeAmount: req.CoffeeAmount,
 		WaterAmount:  req.WaterAmount,
 		Notes:        req.Notes,
-		SourceRef:    existing.SourceRef,
-		CreatedAt:    existing.CreatedAt,
+		SourceRef:    existing.Model.SourceRef,
+		CreatedAt:    existing.Model.CreatedAt,
 	}
 	if len(req.Pours) > 0 {
 		model.Pours = make([]*arabica.Pour, len(req.Pours))
@@ -687,9 +758,48 @@ func (s *AtprotoStore) UpdateRecipeByRKey(ctx context.Context, rkey string, req
 			model.Pours[i] = &arabica.Pour{WaterAmount: p.WaterAmount, TimeSeconds: p.TimeSeconds}
 		}
 	}
-	return atproto.UpdateEntity(ctx, s, recipeCodec, rkey, model)
+	return atproto.UpdateEntity(ctx, s, recipeCodec, rkey, req.SwapCID, model)
 }
 
 func (s *AtprotoStore) DeleteRecipeByRKey(ctx context.Context, rkey string) error {
 	return atproto.DeleteEntity(ctx, s, arabica.NSIDRecipe, rkey)
 }
+
+// ========== Preferences Operations ==========
+
+// GetPreferences returns the user's brew defaults, or nil if they have never
+// saved any.
+func (s *AtprotoStore) GetPreferences(ctx context.Context) (*arabica.BrewPreferences, error) {
+	rec, _, _, err := s.AtprotoStore.FetchRecord(ctx, arabica.NSIDPreferences, arabica.PreferencesRKey)
+	if err != nil {
+		if atproto.IsRecordNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	prefs, err := arabica.RecordToPreferences(rec)
+	if err != nil {
+		return nil, fmt.Errorf("convert preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// PutPreferences creates or overwrites the preferences singleton.
+func (s *AtprotoStore) PutPreferences(ctx context.Context, prefs *arabica.BrewPreferences) error {
+	var grinderURI, brewerURI string
+	if prefs.GrinderRKey != "" {
+		grinderURI = atp.BuildATURI(s.DID(), arabica.NSIDGrinder, prefs.GrinderRKey)
+	}
+	if prefs.BrewerRKey != "" {
+		brewerURI = atp.BuildATURI(s.DID(), arabica.NSIDBrewer, prefs.BrewerRKey)
+	}
+	if prefs.UpdatedAt.IsZero() {
+		prefs.UpdatedAt = time.Now().UTC()
+	}
+	record, err := arabica.PreferencesToRecord(prefs, grinderURI, brewerURI)
+	if err != nil {
+		return fmt.Errorf("convert preferences: %w", err)
+	}
+	_, _, err = s.AtprotoStore.PutRecord(ctx, arabica.NSIDPreferences, arabica.PreferencesRKey, record)
+	return err
+}
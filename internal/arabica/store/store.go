@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/arabica.social/arabica/internal/atproto"
 	"tangled.org/arabica.social/arabica/internal/records"
 )
 
@@ -19,28 +20,50 @@ type Store interface {
 	// It remains for SQLite compatibility but should not be relied upon
 	CreateBrew(ctx context.Context, brew *arabica.CreateBrewRequest, userID int) (*arabica.Brew, error)
 	GetBrewByRKey(ctx context.Context, rkey string) (*arabica.Brew, error)
+	// GetBrewRecordByRKey is GetBrewByRKey plus the record's URI and CID;
+	// edit forms carry the CID back as the update's swap CID.
+	GetBrewRecordByRKey(ctx context.Context, rkey string) (*atproto.EntityRecord[arabica.Brew], error)
 	// When limit <= 0, returns all records.
 	ListBrews(ctx context.Context, userID int, offset, limit int) ([]*arabica.Brew, error)
 	UpdateBrewByRKey(ctx context.Context, rkey string, brew *arabica.CreateBrewRequest) error
+	// PublishBrewByRKey clears a brew's draft flag. Brews that aren't
+	// drafts are left alone.
+	PublishBrewByRKey(ctx context.Context, rkey string) error
 	DeleteBrewByRKey(ctx context.Context, rkey string) error
+	// UploadBlob stores an image for a brew and returns its blob ref in
+	// record form.
+	UploadBlob(ctx context.Context, data []byte, mimeType string) (map[string]any, error)
+	// CreateBlueskyPost crossposts to the user's Bluesky feed and returns
+	// the post's AT-URI.
+	CreateBlueskyPost(ctx context.Context, post atproto.BlueskyPost) (string, error)
 
 	// Bean operations
 	CreateBean(ctx context.Context, bean *arabica.CreateBeanRequest) (*arabica.Bean, error)
 	GetBeanByRKey(ctx context.Context, rkey string) (*arabica.Bean, error)
+	GetBeanRecordByRKey(ctx context.Context, rkey string) (*atproto.EntityRecord[arabica.Bean], error)
 	ListBeans(ctx context.Context) ([]*arabica.Bean, error)
 	UpdateBeanByRKey(ctx context.Context, rkey string, bean *arabica.UpdateBeanRequest) error
+	// AdjustBeanInventory changes remaining grams by deltaGrams, never below zero.
+	AdjustBeanInventory(ctx context.Context, rkey string, deltaGrams int) error
 	DeleteBeanByRKey(ctx context.Context, rkey string) error
 
 	// Roaster operations
 	CreateRoaster(ctx context.Context, roaster *arabica.CreateRoasterRequest) (*arabica.Roaster, error)
 	GetRoasterByRKey(ctx context.Context, rkey string) (*arabica.Roaster, error)
+	GetRoasterRecordByRKey(ctx context.Context, rkey string) (*atproto.EntityRecord[arabica.Roaster], error)
 	ListRoasters(ctx context.Context) ([]*arabica.Roaster, error)
 	UpdateRoasterByRKey(ctx context.Context, rkey string, roaster *arabica.UpdateRoasterRequest) error
 	DeleteRoasterByRKey(ctx context.Context, rkey string) error
 
+	// Grinder and brewer creation for callers outside the generic record
+	// handlers, such as brew import
+	CreateGrinder(ctx context.Context, grinder *arabica.CreateGrinderRequest) (*arabica.Grinder, error)
+	CreateBrewer(ctx context.Context, brewer *arabica.CreateBrewerRequest) (*arabica.Brewer, error)
+
 	// Recipe operations
 	CreateRecipe(ctx context.Context, recipe *arabica.CreateRecipeRequest) (*arabica.Recipe, error)
 	GetRecipeByRKey(ctx context.Context, rkey string) (*arabica.Recipe, error)
+	GetRecipeRecordByRKey(ctx context.Context, rkey string) (*atproto.EntityRecord[arabica.Recipe], error)
 	ListRecipes(ctx context.Context) ([]*arabica.Recipe, error)
 	UpdateRecipeByRKey(ctx context.Context, rkey string, recipe *arabica.UpdateRecipeRequest) error
 	DeleteRecipeByRKey(ctx context.Context, rkey string) error
@@ -57,6 +80,11 @@ type Store interface {
 	GetCommentsForSubject(ctx context.Context, subjectURI string) ([]*arabica.Comment, error)
 	ListUserComments(ctx context.Context) ([]*arabica.Comment, error)
 
+	// Preferences operations
+	// GetPreferences returns nil, nil when the user has no preferences record.
+	GetPreferences(ctx context.Context) (*arabica.BrewPreferences, error)
+	PutPreferences(ctx context.Context, prefs *arabica.BrewPreferences) error
+
 	// Close the database connection
 	Close() error
 }
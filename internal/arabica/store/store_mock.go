@@ -4,39 +4,50 @@ import (
 	"context"
 
 	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/arabica.social/arabica/internal/atproto"
 	"tangled.org/arabica.social/arabica/internal/records"
 )
 
 // MockStore is a mock implementation of the Store interface for testing.
 // Uses function fields to allow tests to inject custom behavior.
 type MockStore struct {
-	CreateBrewFunc       func(ctx context.Context, brew *arabica.CreateBrewRequest, userID int) (*arabica.Brew, error)
-	GetBrewByRKeyFunc    func(ctx context.Context, rkey string) (*arabica.Brew, error)
-	ListBrewsFunc        func(ctx context.Context, userID int, offset, limit int) ([]*arabica.Brew, error)
-	UpdateBrewByRKeyFunc func(ctx context.Context, rkey string, brew *arabica.CreateBrewRequest) error
-	DeleteBrewByRKeyFunc func(ctx context.Context, rkey string) error
-
-	CreateBeanFunc       func(ctx context.Context, bean *arabica.CreateBeanRequest) (*arabica.Bean, error)
-	GetBeanByRKeyFunc    func(ctx context.Context, rkey string) (*arabica.Bean, error)
-	ListBeansFunc        func(ctx context.Context) ([]*arabica.Bean, error)
-	UpdateBeanByRKeyFunc func(ctx context.Context, rkey string, bean *arabica.UpdateBeanRequest) error
-	DeleteBeanByRKeyFunc func(ctx context.Context, rkey string) error
-
-	CreateRoasterFunc       func(ctx context.Context, roaster *arabica.CreateRoasterRequest) (*arabica.Roaster, error)
-	GetRoasterByRKeyFunc    func(ctx context.Context, rkey string) (*arabica.Roaster, error)
-	ListRoastersFunc        func(ctx context.Context) ([]*arabica.Roaster, error)
-	UpdateRoasterByRKeyFunc func(ctx context.Context, rkey string, roaster *arabica.UpdateRoasterRequest) error
-	DeleteRoasterByRKeyFunc func(ctx context.Context, rkey string) error
-
-	ListGrindersFunc func(ctx context.Context) ([]*arabica.Grinder, error)
-
-	ListBrewersFunc func(ctx context.Context) ([]*arabica.Brewer, error)
-
-	CreateRecipeFunc       func(ctx context.Context, recipe *arabica.CreateRecipeRequest) (*arabica.Recipe, error)
-	GetRecipeByRKeyFunc    func(ctx context.Context, rkey string) (*arabica.Recipe, error)
-	ListRecipesFunc        func(ctx context.Context) ([]*arabica.Recipe, error)
-	UpdateRecipeByRKeyFunc func(ctx context.Context, rkey string, recipe *arabica.UpdateRecipeRequest) error
-	DeleteRecipeByRKeyFunc func(ctx context.Context, rkey string) error
+	CreateBrewFunc          func(ctx context.Context, brew *arabica.CreateBrewRequest, userID int) (*arabica.Brew, error)
+	GetBrewByRKeyFunc       func(ctx context.Context, rkey string) (*arabica.Brew, error)
+	GetBrewRecordByRKeyFunc func(ctx context.Context, rkey string) (*atproto.EntityRecord[arabica.Brew], error)
+	ListBrewsFunc           func(ctx context.Context, userID int, offset, limit int) ([]*arabica.Brew, error)
+	UpdateBrewByRKeyFunc    func(ctx context.Context, rkey string, brew *arabica.CreateBrewRequest) error
+	PublishBrewByRKeyFunc   func(ctx context.Context, rkey string) error
+	DeleteBrewByRKeyFunc    func(ctx context.Context, rkey string) error
+	UploadBlobFunc          func(ctx context.Context, data []byte, mimeType string) (map[string]any, error)
+	CreateBlueskyPostFunc   func(ctx context.Context, post atproto.BlueskyPost) (string, error)
+
+	CreateBeanFunc          func(ctx context.Context, bean *arabica.CreateBeanRequest) (*arabica.Bean, error)
+	GetBeanByRKeyFunc       func(ctx context.Context, rkey string) (*arabica.Bean, error)
+	GetBeanRecordByRKeyFunc func(ctx context.Context, rkey string) (*atproto.EntityRecord[arabica.Bean], error)
+	ListBeansFunc           func(ctx context.Context) ([]*arabica.Bean, error)
+	UpdateBeanByRKeyFunc    func(ctx context.Context, rkey string, bean *arabica.UpdateBeanRequest) error
+	AdjustBeanInventoryFunc func(ctx context.Context, rkey string, deltaGrams int) error
+	DeleteBeanByRKeyFunc    func(ctx context.Context, rkey string) error
+
+	CreateRoasterFunc          func(ctx context.Context, roaster *arabica.CreateRoasterRequest) (*arabica.Roaster, error)
+	GetRoasterByRKeyFunc       func(ctx context.Context, rkey string) (*arabica.Roaster, error)
+	GetRoasterRecordByRKeyFunc func(ctx context.Context, rkey string) (*atproto.EntityRecord[arabica.Roaster], error)
+	ListRoastersFunc           func(ctx context.Context) ([]*arabica.Roaster, error)
+	UpdateRoasterByRKeyFunc    func(ctx context.Context, rkey string, roaster *arabica.UpdateRoasterRequest) error
+	DeleteRoasterByRKeyFunc    func(ctx context.Context, rkey string) error
+
+	CreateGrinderFunc func(ctx context.Context, grinder *arabica.CreateGrinderRequest) (*arabica.Grinder, error)
+	ListGrindersFunc  func(ctx context.Context) ([]*arabica.Grinder, error)
+
+	CreateBrewerFunc func(ctx context.Context, brewer *arabica.CreateBrewerRequest) (*arabica.Brewer, error)
+	ListBrewersFunc  func(ctx context.Context) ([]*arabica.Brewer, error)
+
+	CreateRecipeFunc          func(ctx context.Context, recipe *arabica.CreateRecipeRequest) (*arabica.Recipe, error)
+	GetRecipeByRKeyFunc       func(ctx context.Context, rkey string) (*arabica.Recipe, error)
+	GetRecipeRecordByRKeyFunc func(ctx context.Context, rkey string) (*atproto.EntityRecord[arabica.Recipe], error)
+	ListRecipesFunc           func(ctx context.Context) ([]*arabica.Recipe, error)
+	UpdateRecipeByRKeyFunc    func(ctx context.Context, rkey string, recipe *arabica.UpdateRecipeRequest) error
+	DeleteRecipeByRKeyFunc    func(ctx context.Context, rkey string) error
 
 	CreateLikeFunc            func(ctx context.Context, req *arabica.CreateLikeRequest) (*arabica.Like, error)
 	DeleteLikeByRKeyFunc      func(ctx context.Context, rkey string) error
@@ -48,10 +59,14 @@ type MockStore struct {
 	GetCommentsForSubjectFunc func(ctx context.Context, subjectURI string) ([]*arabica.Comment, error)
 	ListUserCommentsFunc      func(ctx context.Context) ([]*arabica.Comment, error)
 
+	GetPreferencesFunc func(ctx context.Context) (*arabica.BrewPreferences, error)
+	PutPreferencesFunc func(ctx context.Context, prefs *arabica.BrewPreferences) error
+
 	DIDFunc             func() string
 	FetchRecordFunc     func(ctx context.Context, nsid, rkey string) (record map[string]any, uri, cid string, err error)
 	FetchAllRecordsFunc func(ctx context.Context, nsid string) ([]records.RawRecord, error)
 	PutRecordFunc       func(ctx context.Context, nsid, rkey string, record any) (resultRKey, cid string, err error)
+	UpdateRecordFunc    func(ctx context.Context, nsid, rkey, swapCID string, record any) (cid string, err error)
 	RemoveRecordFunc    func(ctx context.Context, nsid, rkey string) error
 
 	CloseFunc func() error
@@ -71,6 +86,13 @@ func (m *MockStore) GetBrewByRKey(ctx context.Context, rkey string) (*arabica.Br
 	return nil, nil
 }
 
+func (m *MockStore) GetBrewRecordByRKey(ctx context.Context, rkey string) (*atproto.EntityRecord[arabica.Brew], error) {
+	if m.GetBrewRecordByRKeyFunc != nil {
+		return m.GetBrewRecordByRKeyFunc(ctx, rkey)
+	}
+	return nil, nil
+}
+
 func (m *MockStore) ListBrews(ctx context.Context, userID int, offset, limit int) ([]*arabica.Brew, error) {
 	if m.ListBrewsFunc != nil {
 		return m.ListBrewsFunc(ctx, userID, offset, limit)
@@ -78,6 +100,13 @@ func (m *MockStore) ListBrews(ctx context.Context, userID int, offset, limit int
 	return []*arabica.Brew{}, nil
 }
 
+func (m *MockStore) PublishBrewByRKey(ctx context.Context, rkey string) error {
+	if m.PublishBrewByRKeyFunc != nil {
+		return m.PublishBrewByRKeyFunc(ctx, rkey)
+	}
+	return nil
+}
+
 func (m *MockStore) UpdateBrewByRKey(ctx context.Context, rkey string, brew *arabica.CreateBrewRequest) error {
 	if m.UpdateBrewByRKeyFunc != nil {
 		return m.UpdateBrewByRKeyFunc(ctx, rkey, brew)
@@ -92,6 +121,20 @@ func (m *MockStore) DeleteBrewByRKey(ctx context.Context, rkey string) error {
 	return nil
 }
 
+func (m *MockStore) UploadBlob(ctx context.Context, data []byte, mimeType string) (map[string]any, error) {
+	if m.UploadBlobFunc != nil {
+		return m.UploadBlobFunc(ctx, data, mimeType)
+	}
+	return nil, nil
+}
+
+func (m *MockStore) CreateBlueskyPost(ctx context.Context, post atproto.BlueskyPost) (string, error) {
+	if m.CreateBlueskyPostFunc != nil {
+		return m.CreateBlueskyPostFunc(ctx, post)
+	}
+	return "", nil
+}
+
 func (m *MockStore) CreateBean(ctx context.Context, bean *arabica.CreateBeanRequest) (*arabica.Bean, error) {
 	if m.CreateBeanFunc != nil {
 		return m.CreateBeanFunc(ctx, bean)
@@ -106,6 +149,13 @@ func (m *MockStore) GetBeanByRKey(ctx context.Context, rkey string) (*arabica.Be
 	return nil, nil
 }
 
+func (m *MockStore) GetBeanRecordByRKey(ctx context.Context, rkey string) (*atproto.EntityRecord[arabica.Bean], error) {
+	if m.GetBeanRecordByRKeyFunc != nil {
+		return m.GetBeanRecordByRKeyFunc(ctx, rkey)
+	}
+	return nil, nil
+}
+
 func (m *MockStore) ListBeans(ctx context.Context) ([]*arabica.Bean, error) {
 	if m.ListBeansFunc != nil {
 		return m.ListBeansFunc(ctx)
@@ -120,6 +170,13 @@ func (m *MockStore) UpdateBeanByRKey(ctx context.Context, rkey string, bean *ara
 	return nil
 }
 
+func (m *MockStore) AdjustBeanInventory(ctx context.Context, rkey string, deltaGrams int) error {
+	if m.AdjustBeanInventoryFunc != nil {
+		return m.AdjustBeanInventoryFunc(ctx, rkey, deltaGrams)
+	}
+	return nil
+}
+
 func (m *MockStore) DeleteBeanByRKey(ctx context.Context, rkey string) error {
 	if m.DeleteBeanByRKeyFunc != nil {
 		return m.DeleteBeanByRKeyFunc(ctx, rkey)
@@ -141,6 +198,13 @@ func (m *MockStore) GetRoasterByRKey(ctx context.Context, rkey string) (*arabica
 	return nil, nil
 }
 
+func (m *MockStore) GetRoasterRecordByRKey(ctx context.Context, rkey string) (*atproto.EntityRecord[arabica.Roaster], error) {
+	if m.GetRoasterRecordByRKeyFunc != nil {
+		return m.GetRoasterRecordByRKeyFunc(ctx, rkey)
+	}
+	return nil, nil
+}
+
 func (m *MockStore) ListRoasters(ctx context.Context) ([]*arabica.Roaster, error) {
 	if m.ListRoastersFunc != nil {
 		return m.ListRoastersFunc(ctx)
@@ -162,6 +226,20 @@ func (m *MockStore) DeleteRoasterByRKey(ctx context.Context, rkey string) error
 	return nil
 }
 
+func (m *MockStore) CreateGrinder(ctx context.Context, grinder *arabica.CreateGrinderRequest) (*arabica.Grinder, error) {
+	if m.CreateGrinderFunc != nil {
+		return m.CreateGrinderFunc(ctx, grinder)
+	}
+	return nil, nil
+}
+
+func (m *MockStore) CreateBrewer(ctx context.Context, brewer *arabica.CreateBrewerRequest) (*arabica.Brewer, error) {
+	if m.CreateBrewerFunc != nil {
+		return m.CreateBrewerFunc(ctx, brewer)
+	}
+	return nil, nil
+}
+
 func (m *MockStore) ListGrinders(ctx context.Context) ([]*arabica.Grinder, error) {
 	if m.ListGrindersFunc != nil {
 		return m.ListGrindersFunc(ctx)
@@ -190,6 +268,13 @@ func (m *MockStore) GetRecipeByRKey(ctx context.Context, rkey string) (*arabica.
 	return nil, nil
 }
 
+func (m *MockStore) GetRecipeRecordByRKey(ctx context.Context, rkey string) (*atproto.EntityRecord[arabica.Recipe], error) {
+	if m.GetRecipeRecordByRKeyFunc != nil {
+		return m.GetRecipeRecordByRKeyFunc(ctx, rkey)
+	}
+	return nil, nil
+}
+
 func (m *MockStore) ListRecipes(ctx context.Context) ([]*arabica.Recipe, error) {
 	if m.ListRecipesFunc != nil {
 		return m.ListRecipesFunc(ctx)
@@ -343,6 +428,13 @@ func (m *MockStore) PutRecord(ctx context.Context, nsid, rkey string, record any
 	return "test-rkey", "test-cid", nil
 }
 
+func (m *MockStore) UpdateRecord(ctx context.Context, nsid, rkey, swapCID string, record any) (string, error) {
+	if m.UpdateRecordFunc != nil {
+		return m.UpdateRecordFunc(ctx, nsid, rkey, swapCID, record)
+	}
+	return "test-cid", nil
+}
+
 func (m *MockStore) RemoveRecord(ctx context.Context, nsid, rkey string) error {
 	if m.RemoveRecordFunc != nil {
 		return m.RemoveRecordFunc(ctx, nsid, rkey)
@@ -350,6 +442,20 @@ func (m *MockStore) RemoveRecord(ctx context.Context, nsid, rkey string) error {
 	return nil
 }
 
+func (m *MockStore) GetPreferences(ctx context.Context) (*arabica.BrewPreferences, error) {
+	if m.GetPreferencesFunc != nil {
+		return m.GetPreferencesFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockStore) PutPreferences(ctx context.Context, prefs *arabica.BrewPreferences) error {
+	if m.PutPreferencesFunc != nil {
+		return m.PutPreferencesFunc(ctx, prefs)
+	}
+	return nil
+}
+
 func (m *MockStore) Close() error {
 	if m.CloseFunc != nil {
 		return m.CloseFunc()
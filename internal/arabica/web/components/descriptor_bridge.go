@@ -60,7 +60,7 @@ func FeedViews() feedviews.Registry {
 			RenderPrefs:   FeedBrewContentClickableWithPreferences,
 			CardClassNoun: "brew",
 			FilterLabel:   "Brews",
-			ShareURL:      shareURL("brews"),
+			ShareURL:      shareURL("b"),
 			DeleteURL:     pageDeleteURL("brews"),
 			EditURL:       editPageURL("brews"),
 		},
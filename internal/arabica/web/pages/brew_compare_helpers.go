@@ -0,0 +1,191 @@
+package coffeepages
+
+import (
+	"fmt"
+	"strings"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/arabica.social/arabica/internal/profileprefs"
+)
+
+// BrewCompareColumn is one brew in a comparison. Error is set instead of
+// Brew when that rkey couldn't be loaded.
+type BrewCompareColumn struct {
+	RKey  string
+	Brew  *arabica.Brew
+	Error string
+}
+
+// BrewCompareRow is one parameter across every compared brew. Values line
+// up with the columns; "" means not recorded or not loaded. Differs is set
+// when the loaded brews don't all agree.
+type BrewCompareRow struct {
+	Label   string
+	Values  []string
+	Differs bool
+}
+
+type brewCompareField struct {
+	label string
+	value func(*arabica.Brew, profileprefs.UserPreferences) string
+}
+
+// brewCompareFields lists the compared parameters in display order.
+var brewCompareFields = []brewCompareField{
+	{"Bean", func(b *arabica.Brew, _ profileprefs.UserPreferences) string {
+		if b.Bean == nil {
+			return ""
+		}
+		if b.Bean.Name != "" {
+			return b.Bean.Name
+		}
+		return b.Bean.Origin
+	}},
+	{"Roaster", func(b *arabica.Brew, _ profileprefs.UserPreferences) string {
+		if b.Bean == nil || b.Bean.Roaster == nil {
+			return ""
+		}
+		return b.Bean.Roaster.Name
+	}},
+	{"Recipe", func(b *arabica.Brew, _ profileprefs.UserPreferences) string {
+		if b.RecipeObj == nil {
+			return ""
+		}
+		return b.RecipeObj.Name
+	}},
+	{"Brewer", func(b *arabica.Brew, _ profileprefs.UserPreferences) string { return getBrewerName(b) }},
+	{"Grinder", func(b *arabica.Brew, _ profileprefs.UserPreferences) string { return getGrinderName(b) }},
+	{"Grind size", func(b *arabica.Brew, _ profileprefs.UserPreferences) string { return b.GrindSize }},
+	{"Coffee", func(b *arabica.Brew, p profileprefs.UserPreferences) string {
+		if b.CoffeeAmount <= 0 {
+			return ""
+		}
+		return getCoffeeAmountDisplay(b, p.UnitSystem)
+	}},
+	{"Water", func(b *arabica.Brew, p profileprefs.UserPreferences) string {
+		if b.WaterAmountIn(p.UnitSystem) <= 0 {
+			return ""
+		}
+		return getWaterAmountDisplay(b, p.UnitSystem)
+	}},
+	{"Ratio", func(b *arabica.Brew, _ profileprefs.UserPreferences) string { return getBrewRatioDisplay(b) }},
+	{"Temperature", func(b *arabica.Brew, p profileprefs.UserPreferences) string {
+		return getTemperatureDisplay(b, p.DisplayTemperatureUnit())
+	}},
+	{"Brew time", func(b *arabica.Brew, _ profileprefs.UserPreferences) string { return getBrewTimeDisplay(b) }},
+	{"Pours", func(b *arabica.Brew, _ profileprefs.UserPreferences) string {
+		pours := make([]string, 0, len(b.Pours))
+		for _, pour := range b.Pours {
+			pours = append(pours, fmt.Sprintf("%dg @ %ds", pour.WaterAmount, pour.TimeSeconds))
+		}
+		return strings.Join(pours, ", ")
+	}},
+	{"Bloom", func(b *arabica.Brew, _ profileprefs.UserPreferences) string {
+		if b.PouroverParams == nil {
+			return ""
+		}
+		return formatBloom(b.PouroverParams)
+	}},
+	{"Drawdown", func(b *arabica.Brew, _ profileprefs.UserPreferences) string {
+		if b.PouroverParams == nil || b.PouroverParams.DrawdownSeconds <= 0 {
+			return ""
+		}
+		return fmt.Sprintf("%ds", b.PouroverParams.DrawdownSeconds)
+	}},
+	{"Filter", func(b *arabica.Brew, _ profileprefs.UserPreferences) string {
+		if b.PouroverParams == nil {
+			return ""
+		}
+		return b.PouroverParams.Filter
+	}},
+	{"Yield", func(b *arabica.Brew, _ profileprefs.UserPreferences) string {
+		if b.EspressoParams == nil || b.EspressoParams.YieldWeight <= 0 {
+			return ""
+		}
+		return fmt.Sprintf("%.1fg", b.EspressoParams.YieldWeight)
+	}},
+	{"Pressure", func(b *arabica.Brew, _ profileprefs.UserPreferences) string {
+		if b.EspressoParams == nil || b.EspressoParams.Pressure <= 0 {
+			return ""
+		}
+		return fmt.Sprintf("%.1f bar", b.EspressoParams.Pressure)
+	}},
+	{"Pre-infusion", func(b *arabica.Brew, _ profileprefs.UserPreferences) string {
+		if b.EspressoParams == nil || b.EspressoParams.PreInfusionSeconds <= 0 {
+			return ""
+		}
+		return fmt.Sprintf("%ds", b.EspressoParams.PreInfusionSeconds)
+	}},
+	{"TDS", func(b *arabica.Brew, _ profileprefs.UserPreferences) string {
+		if b.TDS <= 0 {
+			return ""
+		}
+		return fmt.Sprintf("%.2f%%", b.TDS)
+	}},
+	{"Extraction", func(b *arabica.Brew, _ profileprefs.UserPreferences) string {
+		if ey := b.ExtractionYield(); ey > 0 {
+			return fmt.Sprintf("%.1f%%", ey)
+		}
+		return ""
+	}},
+	{"Rating", func(b *arabica.Brew, _ profileprefs.UserPreferences) string {
+		if b.Rating <= 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d/10", b.Rating)
+	}},
+	{"Acidity", tastingScoreValue("acidity")},
+	{"Body", tastingScoreValue("body")},
+	{"Sweetness", tastingScoreValue("sweetness")},
+	{"Bitterness", tastingScoreValue("bitterness")},
+	{"Flavors", func(b *arabica.Brew, _ profileprefs.UserPreferences) string {
+		if b.Tasting == nil {
+			return ""
+		}
+		labels := make([]string, 0, len(b.Tasting.Flavors))
+		for _, f := range b.Tasting.Flavors {
+			labels = append(labels, flavorDisplayLabel(f))
+		}
+		return strings.Join(labels, ", ")
+	}},
+	{"Tasting notes", func(b *arabica.Brew, _ profileprefs.UserPreferences) string { return b.TastingNotes }},
+}
+
+func tastingScoreValue(id string) func(*arabica.Brew, profileprefs.UserPreferences) string {
+	return func(b *arabica.Brew, _ profileprefs.UserPreferences) string {
+		if score := b.Tasting.Score(id); score > 0 {
+			return fmt.Sprintf("%d/10", score)
+		}
+		return ""
+	}
+}
+
+// BrewComparisonRows lays the columns' brews out parameter by parameter.
+// Rows that no loaded brew recorded are left out. A row differs when any
+// two loaded brews disagree, counting "not recorded" as a value; columns
+// that failed to load never count.
+func BrewComparisonRows(columns []BrewCompareColumn, prefs profileprefs.UserPreferences) []BrewCompareRow {
+	var rows []BrewCompareRow
+	for _, field := range brewCompareFields {
+		row := BrewCompareRow{Label: field.label, Values: make([]string, len(columns))}
+		var first *string
+		recorded := false
+		for i, col := range columns {
+			if col.Brew == nil {
+				continue
+			}
+			v := field.value(col.Brew, prefs)
+			row.Values[i] = v
+			recorded = recorded || v != ""
+			if first == nil {
+				first = &row.Values[i]
+			} else if *first != v {
+				row.Differs = true
+			}
+		}
+		if recorded {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
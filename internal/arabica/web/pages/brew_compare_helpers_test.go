@@ -0,0 +1,45 @@
+package coffeepages
+
+import (
+	"testing"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/arabica.social/arabica/internal/profileprefs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBrewComparisonRows(t *testing.T) {
+	columns := []BrewCompareColumn{
+		{RKey: "a", Brew: &arabica.Brew{GrindSize: "Medium", Rating: 8, Tasting: &arabica.TastingProfile{Acidity: 7}}},
+		{RKey: "gone", Error: "Brew not found"},
+		{RKey: "b", Brew: &arabica.Brew{GrindSize: "Medium", Rating: 6}},
+	}
+
+	rows := BrewComparisonRows(columns, profileprefs.UserPreferences{})
+	byLabel := make(map[string]BrewCompareRow, len(rows))
+	for _, row := range rows {
+		byLabel[row.Label] = row
+	}
+
+	tests := []struct {
+		label       string
+		wantValues  []string
+		wantDiffers bool
+	}{
+		{"Grind size", []string{"Medium", "", "Medium"}, false},
+		{"Rating", []string{"8/10", "", "6/10"}, true},
+		{"Acidity", []string{"7/10", "", ""}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			row, ok := byLabel[tt.label]
+			if assert.True(t, ok, "row missing") {
+				assert.Equal(t, tt.wantValues, row.Values)
+				assert.Equal(t, tt.wantDiffers, row.Differs)
+			}
+		})
+	}
+
+	assert.NotContains(t, byLabel, "Pressure", "rows no brew recorded are left out")
+}
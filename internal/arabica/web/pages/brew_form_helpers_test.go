@@ -135,3 +135,35 @@ func TestBrewFormIslandMountContractForEditBrew(t *testing.T) {
 	assert.Contains(t, html, `data-espresso-pressure="9.0"`)
 	assert.Contains(t, html, `data-espresso-pre-infusion-seconds="5"`)
 }
+
+func TestBrewFormIslandMountContractForClonedBrew(t *testing.T) {
+	props := BrewFormProps{
+		// A cloned brew keeps its references and values but has no rkey
+		Brew: &arabica.Brew{
+			BeanRKey:     "bean-123",
+			GrinderRKey:  "grinder-123",
+			BrewerRKey:   "brewer-123",
+			CoffeeAmount: 15,
+			WaterAmount:  250,
+			Rating:       7,
+			Bean:         &arabica.Bean{RKey: "bean-123", Name: "Chelbesa"},
+			GrinderObj:   &arabica.Grinder{RKey: "grinder-123", Name: "Comandante"},
+			BrewerObj:    &arabica.Brewer{RKey: "brewer-123", Name: "V60"},
+		},
+		PoursJSON: `[{"water":50,"time":30}]`,
+	}
+
+	html := renderBrewFormTestComponent(t, BrewFormCard(props))
+
+	assert.Contains(t, html, `hx-post="/brews"`)
+	assert.NotContains(t, html, `hx-put=`)
+	assert.NotContains(t, html, `data-editing="true"`)
+	assert.Contains(t, html, `New Brew`)
+	assert.Contains(t, html, `data-submit-label="Save Brew"`)
+	assert.Contains(t, html, `data-bean-rkey="bean-123"`)
+	assert.Contains(t, html, `data-grinder-rkey="grinder-123"`)
+	assert.Contains(t, html, `data-brewer-rkey="brewer-123"`)
+	assert.Contains(t, html, `data-coffee-amount="15"`)
+	assert.Contains(t, html, `data-rating="7"`)
+	assert.Contains(t, html, `data-pours="[{&#34;water&#34;:50,&#34;time&#34;:30}]"`)
+}
@@ -4,6 +4,7 @@
 package domain
 
 import (
+	"slices"
 	"strings"
 
 	"tangled.org/arabica.social/arabica/internal/entities"
@@ -18,6 +19,9 @@ type App struct {
 	EntityRoutes []EntityRoute
 	Brand        BrandConfig
 	RecordStore  func(records.Store) records.Store
+	// LoginScopes are requested at login on top of the per-NSID repo
+	// scopes, e.g. blob scopes for apps that attach images to records.
+	LoginScopes []string
 }
 
 type EntityRoute struct {
@@ -32,12 +36,14 @@ type BrandConfig struct {
 }
 
 func (a *App) NSIDs() []string {
-	out := make([]string, 0, len(a.Descriptors)+2)
+	out := make([]string, 0, len(a.Descriptors)+4)
 	for _, d := range a.Descriptors {
 		out = append(out, d.NSID)
 	}
 	out = append(out, a.NSIDBase+".like")
 	out = append(out, a.NSIDBase+".comment")
+	out = append(out, a.NSIDBase+".follow")
+	out = append(out, a.NSIDBase+".bookmark")
 	return out
 }
 
@@ -51,13 +57,24 @@ func (a *App) CommentNSID() string {
 	return a.NSIDBase + ".comment"
 }
 
+// FollowNSID returns the follow collection NSID for this app.
+func (a *App) FollowNSID() string {
+	return a.NSIDBase + ".follow"
+}
+
+// BookmarkNSID returns the bookmark collection NSID for this app.
+func (a *App) BookmarkNSID() string {
+	return a.NSIDBase + ".bookmark"
+}
+
 func (a *App) OAuthScopes() []string {
 	nsids := a.NSIDs()
-	out := make([]string, 0, len(nsids)+1)
+	out := make([]string, 0, len(nsids)+len(a.LoginScopes)+1)
 	out = append(out, "atproto")
 	for _, nsid := range nsids {
 		out = append(out, "repo:"+nsid)
 	}
+	out = append(out, a.LoginScopes...)
 	return out
 }
 
@@ -73,27 +90,46 @@ func BlueskyProfileScopes() []string {
 	}
 }
 
-// OAuthScopesWithProfile returns the union of OAuthScopes and
-// BlueskyProfileScopes. This is the full superset declared in client
-// metadata and requested by the scope-upgrade flow.
+// BlueskyPostScopes let the app write app.bsky.feed.post records, used to
+// crosspost a brew when the user ticks the box. Granted alongside the profile
+// scopes by the same upgrade flow.
+func BlueskyPostScopes() []string {
+	return []string{"repo:app.bsky.feed.post"}
+}
+
+// OAuthScopesWithProfile returns the union of OAuthScopes,
+// BlueskyProfileScopes and BlueskyPostScopes. This is the full superset
+// declared in client metadata and requested by the scope-upgrade flow.
 func (a *App) OAuthScopesWithProfile() []string {
 	base := a.OAuthScopes()
-	extra := BlueskyProfileScopes()
+	extra := append(BlueskyProfileScopes(), BlueskyPostScopes()...)
 	out := make([]string, 0, len(base)+len(extra))
 	out = append(out, base...)
-	out = append(out, extra...)
+	for _, scope := range extra {
+		if !slices.Contains(base, scope) {
+			out = append(out, scope)
+		}
+	}
 	return out
 }
 
-// HasBlueskyProfileScopes reports whether the given scope list includes the
-// scopes needed to edit the Bluesky profile record.
+// HasBlueskyProfileScopes reports whether the given scope list includes
+// everything the scope-upgrade flow grants: the scopes to edit the Bluesky
+// profile record and to crosspost. Sessions upgraded before crossposting
+// existed lack the post scope and are asked to upgrade again.
 func HasBlueskyProfileScopes(scopes []string) bool {
-	have := make(map[string]struct{}, len(scopes))
-	for _, s := range scopes {
-		have[s] = struct{}{}
-	}
-	for _, need := range BlueskyProfileScopes() {
-		if _, ok := have[need]; !ok {
+	return hasAllScopes(scopes, BlueskyProfileScopes()) && HasBlueskyPostScopes(scopes)
+}
+
+// HasBlueskyPostScopes reports whether the given scope list allows writing
+// Bluesky posts.
+func HasBlueskyPostScopes(scopes []string) bool {
+	return hasAllScopes(scopes, BlueskyPostScopes())
+}
+
+func hasAllScopes(scopes, need []string) bool {
+	for _, scope := range need {
+		if !slices.Contains(scopes, scope) {
 			return false
 		}
 	}
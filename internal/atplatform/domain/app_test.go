@@ -24,7 +24,9 @@ func TestApp_NSIDs_includesDescriptorsAndSocialCollections(t *testing.T) {
 	assert.Contains(t, nsids, "test.example.bean")
 	assert.Contains(t, nsids, "test.example.like")
 	assert.Contains(t, nsids, "test.example.comment")
-	assert.Len(t, nsids, 3)
+	assert.Contains(t, nsids, "test.example.follow")
+	assert.Contains(t, nsids, "test.example.bookmark")
+	assert.Len(t, nsids, 5)
 }
 
 func TestApp_OAuthScopes_atprotoAndRepoPerNSID(t *testing.T) {
@@ -42,7 +44,49 @@ func TestApp_OAuthScopes_atprotoAndRepoPerNSID(t *testing.T) {
 	assert.Contains(t, scopes, "repo:test.example.bean")
 	assert.Contains(t, scopes, "repo:test.example.like")
 	assert.Contains(t, scopes, "repo:test.example.comment")
-	assert.Len(t, scopes, 4)
+	assert.Contains(t, scopes, "repo:test.example.follow")
+	assert.Contains(t, scopes, "repo:test.example.bookmark")
+	assert.Len(t, scopes, 6)
+}
+
+func TestApp_OAuthScopes_loginScopes(t *testing.T) {
+	app := &domain.App{
+		NSIDBase:    "test.example",
+		LoginScopes: []string{"blob:image/*"},
+	}
+	assert.Contains(t, app.OAuthScopes(), "blob:image/*")
+
+	// The profile superset must not repeat scopes already granted at login.
+	withProfile := app.OAuthScopesWithProfile()
+	count := 0
+	for _, s := range withProfile {
+		if s == "blob:image/*" {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+	assert.Contains(t, withProfile, "repo:app.bsky.actor.profile")
+	assert.Contains(t, withProfile, "repo:app.bsky.feed.post")
+}
+
+func TestHasBlueskyScopes(t *testing.T) {
+	tests := []struct {
+		name        string
+		scopes      []string
+		wantProfile bool
+		wantPost    bool
+	}{
+		{"login only", []string{"atproto", "repo:test.example.bean"}, false, false},
+		{"profile before crossposting", []string{"atproto", "repo:app.bsky.actor.profile", "blob:image/*"}, false, false},
+		{"post only", []string{"atproto", "repo:app.bsky.feed.post"}, false, true},
+		{"full upgrade", (&domain.App{NSIDBase: "test.example"}).OAuthScopesWithProfile(), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantProfile, domain.HasBlueskyProfileScopes(tt.scopes))
+			assert.Equal(t, tt.wantPost, domain.HasBlueskyPostScopes(tt.scopes))
+		})
+	}
 }
 
 func TestApp_DescriptorByNSID(t *testing.T) {
@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -26,12 +27,15 @@ import (
 	"tangled.org/arabica.social/arabica/internal/handlers"
 	"tangled.org/arabica.social/arabica/internal/metrics"
 	"tangled.org/arabica.social/arabica/internal/moderation"
+	"tangled.org/arabica.social/arabica/internal/moderation/ozone"
 	moderationsqlite "tangled.org/arabica.social/arabica/internal/moderation/sqlite"
 	"tangled.org/arabica.social/arabica/internal/routing"
 	"tangled.org/arabica.social/arabica/internal/tracing"
 	"tangled.org/arabica.social/arabica/internal/web/assets"
+	"tangled.org/arabica.social/arabica/internal/webhooks"
 	"tangled.org/pdewey.com/atp"
 
+	"github.com/bluesky-social/indigo/atproto/syntax"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog/log"
 	"go.opentelemetry.io/otel/attribute"
@@ -149,11 +153,24 @@ func Run(ctx context.Context, app *domain.App, opts Options) error {
 			firehoseConfig.ProfileCacheTTL = int64(ttl.Seconds())
 		}
 	}
+	if aheadStr := os.Getenv(envPrefix + "_PROFILE_REFRESH_AHEAD"); aheadStr != "" {
+		if ahead, err := strconv.ParseFloat(aheadStr, 64); err == nil && ahead >= 0 && ahead <= 1 {
+			firehoseConfig.ProfileRefreshAhead = ahead
+		} else {
+			log.Warn().Str("value", aheadStr).Msg("Ignoring invalid " + envPrefix + "_PROFILE_REFRESH_AHEAD")
+		}
+	}
+	if workersStr := os.Getenv(envPrefix + "_BACKFILL_WORKERS"); workersStr != "" {
+		if workers, err := strconv.Atoi(workersStr); err == nil && workers > 0 {
+			firehoseConfig.BackfillWorkers = workers
+		}
+	}
 
 	feedIndex, err := firehose.NewFeedIndex(
 		dbPath,
 		time.Duration(firehoseConfig.ProfileCacheTTL)*time.Second,
 		firehose.WithFeedableDescriptors(app.Descriptors),
+		firehose.WithProfileRefreshAhead(firehoseConfig.ProfileRefreshAhead),
 	)
 	if err != nil {
 		return fmt.Errorf("open database at %s: %w", dbPath, err)
@@ -198,10 +215,47 @@ func Run(ctx context.Context, app *domain.App, opts Options) error {
 	}
 
 	feedRegistry := feed.NewPersistentRegistry(feedIndex)
-	feedService := feed.NewService(feedRegistry)
+	var feedOpts []feed.ServiceOption
+	if ttlStr := os.Getenv(envPrefix + "_PUBLIC_FEED_CACHE_TTL"); ttlStr != "" {
+		if ttl, err := time.ParseDuration(ttlStr); err == nil && ttl > 0 {
+			feedOpts = append(feedOpts, feed.WithPublicFeedCacheTTL(ttl))
+		} else {
+			log.Warn().Str("value", ttlStr).Msg("Ignoring invalid " + envPrefix + "_PUBLIC_FEED_CACHE_TTL")
+		}
+	}
+	if sizeStr := os.Getenv(envPrefix + "_PUBLIC_FEED_CACHE_SIZE"); sizeStr != "" {
+		if size, err := strconv.Atoi(sizeStr); err == nil && size > 0 {
+			feedOpts = append(feedOpts, feed.WithPublicFeedCacheSize(size))
+		} else {
+			log.Warn().Str("value", sizeStr).Msg("Ignoring invalid " + envPrefix + "_PUBLIC_FEED_CACHE_SIZE")
+		}
+	}
+	feedService := feed.NewService(feedRegistry, feedOpts...)
+	// Records arriving over the firehose make the cached public feed stale.
+	feedIndex.SetFeedChangeHook(feedService.InvalidatePublicFeedCache)
+	if limitStr := os.Getenv(envPrefix + "_FEED_LIMIT"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			feedService.SetDefaultLimit(min(limit, handlers.MaxFeedAPILimit))
+		} else {
+			log.Warn().Str("value", limitStr).Msg("Ignoring invalid " + envPrefix + "_FEED_LIMIT")
+		}
+	}
 	log.Info().Int("registered_users", feedRegistry.Count()).Msg("Feed service initialised")
 
+	moderationStore := moderationsqlite.NewModerationStore(feedIndex.DB())
+
+	// New feedable records are pushed to admin-managed webhooks. Delivery is
+	// queued, so a slow endpoint never holds up the consumer.
+	webhookService := webhooks.NewService(webhooks.NewStore(feedIndex.DB()), webhooks.Config{})
+	webhookService.Start(ctx)
+
 	firehoseConsumer := firehose.NewConsumer(firehoseConfig, feedIndex)
+	firehoseConsumer.SetNewRecordHook(func(uri, did, collection string, createdAt time.Time) {
+		if moderationStore.IsBlacklisted(ctx, did) {
+			return
+		}
+		webhookService.Enqueue(webhooks.Event{URI: uri, DID: did, Collection: collection, Timestamp: createdAt})
+	})
 	firehoseConsumer.Start(ctx)
 
 	profileWatcher := firehose.NewProfileWatcher(firehoseConfig, feedIndex)
@@ -209,8 +263,8 @@ func Run(ctx context.Context, app *domain.App, opts Options) error {
 
 	feedService.SetSource(feedIndex)
 
-	moderationStore := moderationsqlite.NewModerationStore(feedIndex.DB())
 	feedService.SetModerationFilter(moderationStore)
+	feedService.SetPinnedSource(moderationStore)
 	log.Info().Msg("Firehose consumer started")
 
 	// Periodic gauge collector
@@ -251,7 +305,7 @@ func Run(ctx context.Context, app *domain.App, opts Options) error {
 		feedRegistry.Register(did)
 		profileWatcher.Watch(did)
 		go func() {
-			if err := firehoseConsumer.BackfillDID(context.Background(), did); err != nil {
+			if err := firehoseConsumer.BackfillDID(context.Background(), did); err != nil && !errors.Is(err, firehose.ErrBackfillInProgress) {
 				log.Warn().Err(err).Str("did", did).Msg("Failed to backfill new user")
 			}
 		}()
@@ -268,6 +322,15 @@ func Run(ctx context.Context, app *domain.App, opts Options) error {
 	atprotoClient := atproto.NewClient(oauthApp)
 	log.Info().Msg("ATProto client initialized")
 
+	// App-password logins are opt-in: their sessions aren't scope-limited
+	// like OAuth grants (see internal/atproto/apppassword.go).
+	var passwordSessions atproto.PasswordSessionStore
+	if appPasswordsEnabled(envPrefix) {
+		passwordSessions = sessionStore
+		atprotoClient.SetPasswordSessions(sessionStore)
+		log.Info().Msg("App-password bearer tokens enabled")
+	}
+
 	sessionCache := atproto.NewSessionCache()
 	stopCacheCleanup := sessionCache.StartCleanupRoutine(10 * time.Minute)
 	defer stopCacheCleanup()
@@ -284,13 +347,22 @@ func Run(ctx context.Context, app *domain.App, opts Options) error {
 		handlers.Config{
 			SecureCookies: secureCookies,
 			PublicURL:     publicURL,
+			HandleDomain:  os.Getenv(envPrefix + "_HANDLE_DOMAIN"),
+			SignupURL:     os.Getenv(envPrefix + "_SIGNUP_URL"),
+			Automod:       automodSettingsFromEnv(envPrefix),
 		},
 	)
 	h.SetFeedIndex(feedIndex)
 	h.SetWitnessCache(feedIndex)
+	h.SetFirehoseConsumer(firehoseConsumer)
+	h.SetWebhooks(webhookService)
 	h.SetBrand(app.Brand)
 	h.SetApp(app)
 	h.SetStaticPageRenderers(opts.StaticPages)
+	if passwordSessions != nil {
+		h.SetPasswordSessions(passwordSessions)
+	}
+	h.SetSessionRevoker(sessionStore)
 
 	// Moderation
 	moderatorsConfigPath := os.Getenv(envPrefix + "_MODERATORS_CONFIG")
@@ -298,7 +370,11 @@ func Run(ctx context.Context, app *domain.App, opts Options) error {
 	if err != nil {
 		log.Warn().Err(err).Msg("Failed to initialize moderation service, moderation disabled")
 	} else {
+		if err := moderationSvc.SetStore(ctx, moderationStore); err != nil {
+			log.Warn().Err(err).Msg("Failed to load runtime moderators, using config file only")
+		}
 		h.SetModeration(moderationSvc, moderationStore)
+		h.SetLabeler(labelerFromEnv(envPrefix))
 	}
 
 	// Periodic cleanup of expired moderation labels
@@ -319,6 +395,18 @@ func Run(ctx context.Context, app *domain.App, opts Options) error {
 		}
 	}()
 
+	// Optional index retention, e.g. ARABICA_INDEX_RETENTION=2160h. Feed-only
+	// records older than the window are pruned hourly; unset keeps everything.
+	if retentionStr := os.Getenv(envPrefix + "_INDEX_RETENTION"); retentionStr != "" {
+		retention, err := time.ParseDuration(retentionStr)
+		if err != nil || retention <= 0 {
+			log.Warn().Str("value", retentionStr).Msg("Invalid " + envPrefix + "_INDEX_RETENTION, index pruning disabled")
+		} else {
+			log.Info().Dur("retention", retention).Msg("Index pruning enabled")
+			go runIndexPruning(ctx, feedIndex, retention)
+		}
+	}
+
 	// Automated backups land under the per-app data dir.
 	backupDir := filepath.Join(dataDir, "backups")
 	backupDest, err := backup.NewLocalDestination(backupDir)
@@ -357,10 +445,29 @@ func Run(ctx context.Context, app *domain.App, opts Options) error {
 	h.SetAssetManifest(assets.NewManifest(cssBundle, jsAssets))
 
 	// Router
+	// Per-user cap on record-creating requests; 0 keeps the router default.
+	var createRateLimit int
+	if v := lookupAppEnv(envPrefix, "CREATE_RATE_LIMIT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			createRateLimit = n
+		} else {
+			log.Warn().Str("value", v).Msg("Ignoring invalid CREATE_RATE_LIMIT")
+		}
+	}
+
+	// Browser origins allowed to call the JSON API with a bearer token.
+	var corsOrigins []string
+	for _, origin := range strings.Split(lookupAppEnv(envPrefix, "CORS_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			corsOrigins = append(corsOrigins, origin)
+		}
+	}
+
 	handler := routing.SetupRouter(routing.Config{
 		App:               app,
 		Handlers:          h,
 		OAuthApp:          oauthApp,
+		PasswordSessions:  passwordSessions,
 		OnAuth:            onAuth,
 		Logger:            log.Logger,
 		ModerationService: moderationSvc,
@@ -368,6 +475,9 @@ func Run(ctx context.Context, app *domain.App, opts Options) error {
 		CSSBundle:         cssBundle,
 		JSAssets:          jsAssets,
 		AppRoutes:         opts.AppRoutes,
+		CreateRateLimit:   createRateLimit,
+		CORSOrigins:       corsOrigins,
+		CSRFSecret:        []byte(lookupAppEnv(envPrefix, "CSRF_SECRET")),
 	})
 
 	// Internal metrics server (localhost-only)
@@ -515,6 +625,17 @@ func devModeEnabled(envPrefix string) bool {
 	}
 }
 
+// appPasswordsEnabled reports whether <APP>_APP_PASSWORDS is set to a
+// truthy value, turning on POST /auth/app-password and bearer auth.
+func appPasswordsEnabled(envPrefix string) bool {
+	switch lookupAppEnv(envPrefix, "APP_PASSWORDS") {
+	case "", "0", "false", "off", "no":
+		return false
+	default:
+		return true
+	}
+}
+
 // lookupAppEnv returns os.Getenv("<envPrefix>_<key>") if set, falling
 // back to os.Getenv(key). This lets a single binary running multiple
 // apps (cmd/server) keep per-app overrides like ARABICA_PORT and
@@ -527,6 +648,52 @@ func lookupAppEnv(envPrefix, key string) string {
 	return os.Getenv(key)
 }
 
+// automodSettingsFromEnv reads automod thresholds such as
+// ARABICA_AUTOHIDE_THRESHOLD. Unset, malformed, or non-positive values are
+// left at zero so the handler falls back to the built-in default.
+func automodSettingsFromEnv(envPrefix string) moderation.AutomodSettings {
+	read := func(key string) int {
+		raw := os.Getenv(envPrefix + "_" + key)
+		if raw == "" {
+			return 0
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			log.Warn().Str("var", envPrefix+"_"+key).Str("value", raw).Msg("Ignoring invalid automod setting, using default")
+			return 0
+		}
+		return n
+	}
+	return moderation.AutomodSettings{
+		AutoHideThreshold:      read("AUTOHIDE_THRESHOLD"),
+		AutoHideUserThreshold:  read("AUTOHIDE_USER_THRESHOLD"),
+		ReportRateLimitPerHour: read("REPORT_RATE_LIMIT"),
+		MaxReportReasonLength:  read("REPORT_REASON_MAX_LENGTH"),
+	}
+}
+
+// labelerFromEnv returns the Ozone labeler named by ARABICA_LABELER_URL,
+// ARABICA_LABELER_DID and ARABICA_LABELER_PASSWORD. Labels are optional, so
+// unless all three are set hidden records are not published anywhere.
+func labelerFromEnv(envPrefix string) moderation.Labeler {
+	host := os.Getenv(envPrefix + "_LABELER_URL")
+	did := os.Getenv(envPrefix + "_LABELER_DID")
+	password := os.Getenv(envPrefix + "_LABELER_PASSWORD")
+	if host == "" && did == "" && password == "" {
+		return moderation.NopLabeler{}
+	}
+	if host == "" || did == "" || password == "" {
+		log.Warn().Msg("Ignoring incomplete labeler config: " + envPrefix + "_LABELER_URL, _LABELER_DID and _LABELER_PASSWORD are all required")
+		return moderation.NopLabeler{}
+	}
+	if _, err := syntax.ParseDID(did); err != nil {
+		log.Warn().Err(err).Str("did", did).Msg("Ignoring invalid " + envPrefix + "_LABELER_DID")
+		return moderation.NopLabeler{}
+	}
+	log.Info().Str("url", host).Str("did", did).Msg("Publishing hidden records as labels")
+	return ozone.NewLabeler(host, password, did)
+}
+
 // validateAppName ensures app.Name is safe for use as an env-var prefix
 // and a path component. Allowed: lowercase letters and digits, starting
 // with a letter. Rejects empty, hyphens, underscores, dots, slashes —
@@ -546,6 +713,31 @@ func validateAppName(name string) error {
 	return nil
 }
 
+// runIndexPruning drops indexed records older than retention, once at
+// startup and then hourly until ctx is cancelled.
+func runIndexPruning(ctx context.Context, feedIndex *firehose.FeedIndex, retention time.Duration) {
+	prune := func() {
+		n, err := feedIndex.PruneOlderThan(ctx, time.Now().Add(-retention))
+		if err != nil {
+			log.Error().Err(err).Int("pruned", n).Msg("Failed to prune index")
+		} else if n > 0 {
+			log.Info().Int("count", n).Msg("Pruned old records from index")
+		}
+	}
+
+	prune()
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			prune()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // runBackfill collects DIDs from the registry and the known-dids file,
 // removes already-backfilled ones, and indexes the rest. Runs once at
 // startup (after a 5s delay for the firehose to connect first).
@@ -599,24 +791,23 @@ func runBackfill(ctx context.Context, firehoseConsumer *firehose.Consumer, feedR
 	_, execSpan := tracing.HandlerSpan(filterCtx, "backfill.execute",
 		attribute.Int("backfill.count", len(didsToBackfill)),
 	)
-	successCount := 0
+	dids := make([]string, 0, len(didsToBackfill))
 	for did := range didsToBackfill {
-		if err := firehoseConsumer.BackfillDID(backfillCtx, did); err != nil {
-			log.Warn().Err(err).Str("did", did).Msg("Failed to backfill user")
-		} else {
-			successCount++
-		}
+		dids = append(dids, did)
 	}
+	successCount, inProgressCount, failedCount := firehoseConsumer.BackfillDIDs(backfillCtx, dids, 0)
 	execSpan.SetAttributes(
 		attribute.Int("backfill.success", successCount),
-		attribute.Int("backfill.failed", len(didsToBackfill)-successCount),
+		attribute.Int("backfill.in_progress", inProgressCount),
+		attribute.Int("backfill.failed", failedCount),
 	)
 	execSpan.End()
 
 	log.Info().
 		Int("skipped", len(alreadyBackfilled)).
 		Int("backfilled", successCount).
-		Int("failed", len(didsToBackfill)-successCount).
+		Int("in_progress", inProgressCount).
+		Int("failed", failedCount).
 		Msg("Backfill complete")
 }
 
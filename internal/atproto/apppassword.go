@@ -0,0 +1,175 @@
+package atproto
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bluesky-social/indigo/atproto/atclient"
+	"github.com/bluesky-social/indigo/atproto/identity"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/rs/zerolog/log"
+	"tangled.org/pdewey.com/atp"
+)
+
+// App-password sessions let scripts act for a user without the OAuth browser
+// flow. The client logs in once with an app password; we create a PDS session
+// via com.atproto.server.createSession, keep its tokens server-side, and hand
+// back an opaque bearer token. Requests carrying that token resolve to the
+// same DID and user-scoped AtprotoStore as a cookie session would.
+//
+// Tradeoffs worth knowing before enabling this for a deployment:
+//   - App-password sessions are not scoped the way OAuth grants are. The PDS
+//     lets them write to any collection, so a leaked bearer token (or our
+//     database) exposes more than a leaked OAuth session would. We only
+//     ever call the PDS through AtprotoStore, which confines writes to the
+//     app's own collections, but the PDS doesn't enforce that.
+//   - The bearer token is shown to the client exactly once. Only its SHA-256
+//     is stored, so it can't be recovered, only revoked.
+//   - Main account passwords are refused: the session's access token must
+//     carry an app-password scope. Users revoke app passwords from their
+//     PDS settings, which also kills every session we hold for them.
+
+// PasswordSessionPrefix marks session IDs created by the app-password flow
+// so the client provider can tell them apart from OAuth session IDs.
+const PasswordSessionPrefix = "apppw-"
+
+// ErrNotAppPassword is returned when a login succeeds with the account's
+// main password instead of an app password.
+var ErrNotAppPassword = errors.New("an app password is required; main account passwords are not accepted")
+
+// PasswordSessionStore persists app-password sessions keyed by session ID.
+type PasswordSessionStore interface {
+	GetPasswordSession(ctx context.Context, sessionID string) (*atclient.PasswordSessionData, error)
+	SavePasswordSession(ctx context.Context, sessionID string, data atclient.PasswordSessionData) error
+	DeletePasswordSession(ctx context.Context, sessionID string) error
+}
+
+// NewBearerToken returns a random bearer token and the session ID it maps to.
+func NewBearerToken() (token, sessionID string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("generate token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, PasswordSessionID(token), nil
+}
+
+// PasswordSessionID derives the stored session ID from a bearer token.
+func PasswordSessionID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return PasswordSessionPrefix + hex.EncodeToString(sum[:])
+}
+
+// BearerTokenLookup returns a lookup for the bearer auth middleware that
+// resolves tokens against store.
+func BearerTokenLookup(store PasswordSessionStore) func(ctx context.Context, token string) (did, sessionID string, ok bool) {
+	return func(ctx context.Context, token string) (string, string, bool) {
+		if token == "" {
+			return "", "", false
+		}
+		sessionID := PasswordSessionID(token)
+		data, err := store.GetPasswordSession(ctx, sessionID)
+		if err != nil {
+			return "", "", false
+		}
+		return data.AccountDID.String(), sessionID, true
+	}
+}
+
+// IsPasswordSession reports whether sessionID belongs to an app-password
+// session rather than an OAuth one.
+func IsPasswordSession(sessionID string) bool {
+	return strings.HasPrefix(sessionID, PasswordSessionPrefix)
+}
+
+// LoginWithAppPassword creates a PDS session for identifier (handle or DID)
+// and stores it under a fresh bearer token. It returns the token and the
+// account's DID.
+func LoginWithAppPassword(ctx context.Context, store PasswordSessionStore, identifier, password string) (token string, did syntax.DID, err error) {
+	atid, err := syntax.ParseAtIdentifier(strings.TrimPrefix(strings.TrimSpace(identifier), "@"))
+	if err != nil {
+		return "", "", fmt.Errorf("invalid identifier: %w", err)
+	}
+
+	api, err := atclient.LoginWithPassword(ctx, identity.DefaultDirectory(), atid, password, "", nil)
+	if err != nil {
+		return "", "", err
+	}
+	auth, ok := api.Auth.(*atclient.PasswordAuth)
+	if !ok {
+		return "", "", fmt.Errorf("unexpected auth type %T", api.Auth)
+	}
+	if !isAppPasswordScope(auth.Session.AccessToken) {
+		// Don't leave a full-access session dangling on the PDS.
+		if err := auth.Logout(ctx, api.Client); err != nil {
+			log.Warn().Err(err).Msg("Failed to log out main-password session")
+		}
+		return "", "", ErrNotAppPassword
+	}
+
+	token, sessionID, err := NewBearerToken()
+	if err != nil {
+		return "", "", err
+	}
+	if err := store.SavePasswordSession(ctx, sessionID, auth.Session); err != nil {
+		return "", "", fmt.Errorf("save session: %w", err)
+	}
+	return token, auth.Session.AccountDID, nil
+}
+
+// isAppPasswordScope reports whether an access JWT was minted for an app
+// password. The token is only decoded, not verified; it came straight from
+// the PDS over TLS.
+func isAppPasswordScope(accessJWT string) bool {
+	parts := strings.Split(accessJWT, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	var claims struct {
+		Scope string `json:"scope"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return false
+	}
+	// com.atproto.appPass and com.atproto.appPassPrivileged
+	return strings.HasPrefix(claims.Scope, "com.atproto.appPass")
+}
+
+// passwordProvider resumes app-password sessions from store and hands every
+// other session ID to next.
+func passwordProvider(store PasswordSessionStore, next ClientProvider) ClientProvider {
+	return func(ctx context.Context, did syntax.DID, sessionID string) (*atp.Client, error) {
+		if !IsPasswordSession(sessionID) {
+			return next(ctx, did, sessionID)
+		}
+		data, err := store.GetPasswordSession(ctx, sessionID)
+		if err != nil {
+			return nil, errors.Join(ErrSessionExpired, err)
+		}
+		if data.AccountDID != did {
+			return nil, fmt.Errorf("%w: session belongs to a different account", ErrSessionExpired)
+		}
+		// Persist rotated tokens; the old refresh token stops working as
+		// soon as the PDS issues a new one.
+		onRefresh := func(ctx context.Context, updated atclient.PasswordSessionData) {
+			if err := store.SavePasswordSession(ctx, sessionID, updated); err != nil {
+				log.Warn().Err(err).Str("did", did.String()).Msg("Failed to save refreshed app-password session")
+			}
+		}
+		api := atclient.ResumePasswordSession(*data, onRefresh)
+		instrumentAPIClient(api)
+		refreshOnExpiredToken(api)
+		return atp.NewClient(api, did), nil
+	}
+}
@@ -0,0 +1,128 @@
+package atproto
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/bluesky-social/indigo/atproto/atclient"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"tangled.org/pdewey.com/atp"
+)
+
+type memPasswordSessions map[string]atclient.PasswordSessionData
+
+func (m memPasswordSessions) GetPasswordSession(_ context.Context, sessionID string) (*atclient.PasswordSessionData, error) {
+	data, ok := m[sessionID]
+	if !ok {
+		return nil, errors.New("session not found")
+	}
+	return &data, nil
+}
+
+func (m memPasswordSessions) SavePasswordSession(_ context.Context, sessionID string, data atclient.PasswordSessionData) error {
+	m[sessionID] = data
+	return nil
+}
+
+func (m memPasswordSessions) DeletePasswordSession(_ context.Context, sessionID string) error {
+	delete(m, sessionID)
+	return nil
+}
+
+func TestNewBearerToken(t *testing.T) {
+	token, sessionID, err := NewBearerToken()
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, token)
+	assert.Equal(t, PasswordSessionID(token), sessionID)
+	assert.True(t, IsPasswordSession(sessionID))
+	assert.NotContains(t, sessionID, token, "the stored ID must not reveal the token")
+
+	other, _, err := NewBearerToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
+
+func TestIsAppPasswordScope(t *testing.T) {
+	jwt := func(payload string) string {
+		return "eyJhbGciOiJFUzI1NksifQ." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".sig"
+	}
+	tests := []struct {
+		name string
+		jwt  string
+		want bool
+	}{
+		{"app password", jwt(`{"scope":"com.atproto.appPass"}`), true},
+		{"privileged app password", jwt(`{"scope":"com.atproto.appPassPrivileged"}`), true},
+		{"main password", jwt(`{"scope":"com.atproto.access"}`), false},
+		{"no scope", jwt(`{}`), false},
+		{"not a jwt", "garbage", false},
+		{"bad payload", "a.!!!.c", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isAppPasswordScope(tt.jwt))
+		})
+	}
+}
+
+func TestBearerTokenLookup(t *testing.T) {
+	token, sessionID, err := NewBearerToken()
+	require.NoError(t, err)
+	store := memPasswordSessions{
+		sessionID: {AccountDID: syntax.DID("did:plc:alice"), Host: "https://pds.example"},
+	}
+	lookup := BearerTokenLookup(store)
+
+	did, gotSession, ok := lookup(context.Background(), token)
+	assert.True(t, ok)
+	assert.Equal(t, "did:plc:alice", did)
+	assert.Equal(t, sessionID, gotSession)
+
+	_, _, ok = lookup(context.Background(), "unknown")
+	assert.False(t, ok)
+	_, _, ok = lookup(context.Background(), "")
+	assert.False(t, ok)
+}
+
+func TestPasswordProvider(t *testing.T) {
+	_, sessionID, err := NewBearerToken()
+	require.NoError(t, err)
+	store := memPasswordSessions{
+		sessionID: {AccountDID: syntax.DID("did:plc:alice"), Host: "https://pds.example"},
+	}
+
+	var fallbackCalls int
+	fallback := func(ctx context.Context, did syntax.DID, sessionID string) (*atp.Client, error) {
+		fallbackCalls++
+		return nil, nil
+	}
+	provider := passwordProvider(store, fallback)
+
+	t.Run("oauth sessions use the fallback", func(t *testing.T) {
+		_, err := provider(context.Background(), "did:plc:alice", "oauth-session")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, fallbackCalls)
+	})
+
+	t.Run("unknown password session is expired", func(t *testing.T) {
+		_, err := provider(context.Background(), "did:plc:alice", PasswordSessionPrefix+"missing")
+		assert.ErrorIs(t, err, ErrSessionExpired)
+	})
+
+	t.Run("session for another account is rejected", func(t *testing.T) {
+		_, err := provider(context.Background(), "did:plc:mallory", sessionID)
+		assert.ErrorIs(t, err, ErrSessionExpired)
+	})
+
+	t.Run("known password session resumes", func(t *testing.T) {
+		client, err := provider(context.Background(), "did:plc:alice", sessionID)
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+		assert.Equal(t, 1, fallbackCalls)
+	})
+}
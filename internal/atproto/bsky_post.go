@@ -0,0 +1,65 @@
+package atproto
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// BlueskyPostNSID is the collection Bluesky reads posts from.
+const BlueskyPostNSID = "app.bsky.feed.post"
+
+// blueskyPostMaxGraphemes is the post text limit enforced by the Bluesky
+// lexicon. Runes are counted here, which is never more than graphemes.
+const blueskyPostMaxGraphemes = 300
+
+// BlueskyPost is a short post with an external link card, used to crosspost
+// records to a user's Bluesky feed.
+type BlueskyPost struct {
+	Text            string
+	LinkURL         string
+	LinkTitle       string
+	LinkDescription string
+}
+
+// Record converts the post to its app.bsky.feed.post record map. Text over
+// the lexicon limit is truncated with an ellipsis; the link card is omitted
+// when LinkURL is empty.
+func (p BlueskyPost) Record(createdAt time.Time) map[string]any {
+	text := []rune(p.Text)
+	if len(text) > blueskyPostMaxGraphemes {
+		text = append(text[:blueskyPostMaxGraphemes-1], '…')
+	}
+	record := map[string]any{
+		"$type":     BlueskyPostNSID,
+		"text":      string(text),
+		"createdAt": createdAt.UTC().Format(time.RFC3339),
+	}
+	if p.LinkURL != "" {
+		record["embed"] = map[string]any{
+			"$type": "app.bsky.embed.external",
+			"external": map[string]any{
+				"uri":         p.LinkURL,
+				"title":       p.LinkTitle,
+				"description": p.LinkDescription,
+			},
+		}
+	}
+	return record
+}
+
+// CreateBlueskyPost writes post to the user's app.bsky.feed.post collection
+// and returns its AT-URI. It goes straight to the PDS: Bluesky posts are not
+// app records, so neither the witness nor the session cache is touched.
+// Requires the repo:app.bsky.feed.post OAuth scope.
+func (s *AtprotoStore) CreateBlueskyPost(ctx context.Context, post BlueskyPost) (string, error) {
+	client, err := s.atpClient(ctx)
+	if err != nil {
+		return "", err
+	}
+	uri, _, err := client.CreateRecord(ctx, BlueskyPostNSID, post.Record(time.Now()))
+	if err != nil {
+		return "", fmt.Errorf("create bluesky post: %w", err)
+	}
+	return uri, nil
+}
@@ -0,0 +1,46 @@
+package atproto
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlueskyPostRecord(t *testing.T) {
+	createdAt := time.Date(2026, 5, 1, 8, 30, 0, 0, time.UTC)
+
+	t.Run("with link card", func(t *testing.T) {
+		rec := BlueskyPost{
+			Text:            "Brewed Halo from Onyx",
+			LinkURL:         "https://arabica.test/brews/alice.test/3abc",
+			LinkTitle:       "Halo from Onyx",
+			LinkDescription: "A brew logged on arabica.social",
+		}.Record(createdAt)
+
+		assert.Equal(t, BlueskyPostNSID, rec["$type"])
+		assert.Equal(t, "Brewed Halo from Onyx", rec["text"])
+		assert.Equal(t, "2026-05-01T08:30:00Z", rec["createdAt"])
+		embed, ok := rec["embed"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "app.bsky.embed.external", embed["$type"])
+		external, ok := embed["external"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "https://arabica.test/brews/alice.test/3abc", external["uri"])
+		assert.Equal(t, "Halo from Onyx", external["title"])
+	})
+
+	t.Run("without link", func(t *testing.T) {
+		rec := BlueskyPost{Text: "hi"}.Record(createdAt)
+		assert.NotContains(t, rec, "embed")
+	})
+
+	t.Run("long text is truncated", func(t *testing.T) {
+		rec := BlueskyPost{Text: strings.Repeat("é", 400)}.Record(createdAt)
+		text := []rune(rec["text"].(string))
+		assert.Len(t, text, blueskyPostMaxGraphemes)
+		assert.Equal(t, '…', text[len(text)-1])
+	})
+}
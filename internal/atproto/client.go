@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/bluesky-social/indigo/atproto/atclient"
 	"github.com/bluesky-social/indigo/atproto/syntax"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"tangled.org/pdewey.com/atp"
@@ -37,11 +38,20 @@ var ErrSessionExpired = atp.ErrSessionExpired
 type Profile = atp.PublicProfile
 
 // NewPublicClient creates a public client with OTel-instrumented transport and
-// the arabica User-Agent header.
+// the arabica User-Agent header. Reads that fail transiently are retried
+// under DefaultRetryPolicy.
 func NewPublicClient() *atp.PublicClient {
+	return NewPublicClientWithRetry(DefaultRetryPolicy)
+}
+
+// NewPublicClientWithRetry is NewPublicClient with a custom retry policy.
+// The 30 second client timeout covers every attempt, not each one.
+func NewPublicClientWithRetry(policy RetryPolicy) *atp.PublicClient {
 	hc := &http.Client{
-		Timeout:   30 * time.Second,
-		Transport: &userAgentTransport{base: otelhttp.NewTransport(http.DefaultTransport)},
+		Timeout: 30 * time.Second,
+		Transport: &userAgentTransport{
+			base: newRetryTransport(otelhttp.NewTransport(http.DefaultTransport), policy),
+		},
 	}
 	return atp.NewPublicClientWithHTTP(hc)
 }
@@ -73,24 +83,33 @@ func oauthProvider(oauth *atp.OAuthApp) ClientProvider {
 			return nil, err
 		}
 
-		apiClient := atpClient.APIClient()
-
-		// Wrap transport with OTel instrumentation.
-		baseTransport := apiClient.Client.Transport
-		if baseTransport == nil {
-			baseTransport = http.DefaultTransport
-		}
-		apiClient.Client = &http.Client{
-			Transport:     &userAgentTransport{base: otelhttp.NewTransport(baseTransport)},
-			Timeout:       apiClient.Client.Timeout,
-			CheckRedirect: apiClient.Client.CheckRedirect,
-			Jar:           apiClient.Client.Jar,
-		}
-
+		instrumentAPIClient(atpClient.APIClient())
+		refreshOnExpiredToken(atpClient.APIClient())
 		return atpClient, nil
 	}
 }
 
+// instrumentAPIClient wraps the client's transport with OTel instrumentation
+// and the arabica User-Agent.
+func instrumentAPIClient(apiClient *atclient.APIClient) {
+	baseTransport := apiClient.Client.Transport
+	if baseTransport == nil {
+		baseTransport = http.DefaultTransport
+	}
+	apiClient.Client = &http.Client{
+		Transport:     &userAgentTransport{base: otelhttp.NewTransport(baseTransport)},
+		Timeout:       apiClient.Client.Timeout,
+		CheckRedirect: apiClient.Client.CheckRedirect,
+		Jar:           apiClient.Client.Jar,
+	}
+}
+
+// SetPasswordSessions lets the client resume app-password sessions from
+// store in addition to its existing provider. See LoginWithAppPassword.
+func (c *Client) SetPasswordSessions(store PasswordSessionStore) {
+	c.getClient = passwordProvider(store, c.getClient)
+}
+
 // getAtpClient returns an authenticated atp.Client using the configured provider.
 func (c *Client) getAtpClient(ctx context.Context, did syntax.DID, sessionID string) (*atp.Client, error) {
 	return c.getClient(ctx, did, sessionID)
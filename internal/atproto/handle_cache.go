@@ -0,0 +1,103 @@
+package atproto
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultHandleCacheTTL keeps a resolved handle long enough to cover a
+// burst of page views without pinning a handle its owner has since moved.
+const DefaultHandleCacheTTL = 5 * time.Minute
+
+// DefaultHandleCache is the process-wide handle→DID cache used when
+// resolving handles from request paths. The firehose evicts entries when
+// identity events report a handle change.
+var DefaultHandleCache = NewHandleCache(DefaultHandleCacheTTL)
+
+// HandleCache maps handles to DIDs for a fixed TTL. Handles are compared
+// case-insensitively. Failed resolutions are never cached.
+type HandleCache struct {
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.RWMutex
+	entries map[string]handleCacheEntry
+}
+
+type handleCacheEntry struct {
+	did       string
+	expiresAt time.Time
+}
+
+// NewHandleCache creates an empty cache whose entries live for ttl.
+func NewHandleCache(ttl time.Duration) *HandleCache {
+	return &HandleCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]handleCacheEntry),
+	}
+}
+
+func handleKey(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+}
+
+// Get returns the cached DID for handle, if present and unexpired.
+func (c *HandleCache) Get(handle string) (string, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[handleKey(handle)]
+	c.mu.RUnlock()
+	if !ok || !c.now().Before(entry.expiresAt) {
+		return "", false
+	}
+	return entry.did, true
+}
+
+// Set records that handle currently resolves to did.
+func (c *HandleCache) Set(handle, did string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	// Sweep expired entries now and then so one-off lookups don't pile up.
+	if len(c.entries) >= 1024 {
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	c.entries[handleKey(handle)] = handleCacheEntry{did: did, expiresAt: now.Add(c.ttl)}
+}
+
+// Invalidate drops handle from the cache.
+func (c *HandleCache) Invalidate(handle string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, handleKey(handle))
+}
+
+// InvalidateDID drops every handle currently mapped to did.
+func (c *HandleCache) InvalidateDID(did string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if e.did == did {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// Resolve returns the DID for handle from the cache, calling resolve and
+// caching its answer on a miss.
+func (c *HandleCache) Resolve(ctx context.Context, handle string, resolve func(context.Context, string) (string, error)) (string, error) {
+	if did, ok := c.Get(handle); ok {
+		return did, nil
+	}
+	did, err := resolve(ctx, handle)
+	if err != nil {
+		return "", err
+	}
+	c.Set(handle, did)
+	return did, nil
+}
@@ -0,0 +1,74 @@
+package atproto
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCacheResolve(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewHandleCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	calls := 0
+	answer, answerErr := "did:plc:alice", error(nil)
+	resolve := func(context.Context, string) (string, error) {
+		calls++
+		return answer, answerErr
+	}
+
+	tests := []struct {
+		name      string
+		handle    string
+		advance   time.Duration
+		wantDID   string
+		wantCalls int
+	}{
+		{"miss resolves", "alice.test", 0, "did:plc:alice", 1},
+		{"hit skips the resolver", "alice.test", 30 * time.Second, "did:plc:alice", 1},
+		{"handles ignore case and @", "@Alice.Test", 0, "did:plc:alice", 1},
+		{"expired entry resolves again", "alice.test", time.Minute, "did:plc:alice", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.advance)
+			did, err := cache.Resolve(ctx, tt.handle, resolve)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDID, did)
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		answer, answerErr = "", errors.New("no such handle")
+		_, err := cache.Resolve(ctx, "ghost.test", resolve)
+		assert.Error(t, err)
+		_, ok := cache.Get("ghost.test")
+		assert.False(t, ok)
+	})
+}
+
+func TestHandleCacheInvalidate(t *testing.T) {
+	cache := NewHandleCache(time.Hour)
+	cache.Set("alice.test", "did:plc:alice")
+	cache.Set("alice.example", "did:plc:alice")
+	cache.Set("bob.test", "did:plc:bob")
+
+	cache.Invalidate("ALICE.TEST")
+	_, ok := cache.Get("alice.test")
+	assert.False(t, ok)
+
+	cache.InvalidateDID("did:plc:alice")
+	_, ok = cache.Get("alice.example")
+	assert.False(t, ok)
+
+	did, ok := cache.Get("bob.test")
+	assert.True(t, ok)
+	assert.Equal(t, "did:plc:bob", did)
+}
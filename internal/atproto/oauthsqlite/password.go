@@ -0,0 +1,54 @@
+package oauthsqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/atclient"
+)
+
+// App-password sessions share the OAuth store's database so logout, cleanup
+// and backups treat both kinds of session the same way.
+
+func (s *OAuthStore) GetPasswordSession(ctx context.Context, sessionID string) (*atclient.PasswordSessionData, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT data FROM app_password_sessions WHERE session_id = ?`, sessionID,
+	).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("session not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var sess atclient.PasswordSessionData
+	if err := json.Unmarshal([]byte(data), &sess); err != nil {
+		return nil, fmt.Errorf("unmarshal password session: %w", err)
+	}
+	return &sess, nil
+}
+
+func (s *OAuthStore) SavePasswordSession(ctx context.Context, sessionID string, sess atclient.PasswordSessionData) error {
+	data, err := json.Marshal(sess)
+	if err != nil {
+		return fmt.Errorf("marshal password session: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO app_password_sessions (session_id, did, data, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET
+			data       = excluded.data,
+			updated_at = excluded.updated_at
+	`, sessionID, sess.AccountDID.String(), string(data), time.Now().UTC().Format(time.RFC3339Nano))
+	return err
+}
+
+func (s *OAuthStore) DeletePasswordSession(ctx context.Context, sessionID string) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM app_password_sessions WHERE session_id = ?`, sessionID,
+	)
+	return err
+}
@@ -136,9 +136,9 @@ func (s *OAuthStore) CountSessions(ctx context.Context) (int, error) {
 	return count, err
 }
 
-// CleanupExpired removes stale rows from both OAuth tables. Sessions whose
-// updated_at is older than sessionMaxAge are deleted; auth requests older than
-// authRequestMaxAge are deleted. Auth requests are short-lived state tokens
+// CleanupExpired removes stale rows from the OAuth and app-password tables.
+// Sessions of either kind whose updated_at is older than sessionMaxAge are
+// deleted; auth requests older than authRequestMaxAge are deleted. Auth requests are short-lived state tokens
 // from incomplete OAuth callbacks; sessions are bounded by the upstream
 // refresh-token lifetime (~90 days on bsky PDS).
 func (s *OAuthStore) CleanupExpired(ctx context.Context, sessionMaxAge, authRequestMaxAge time.Duration) (sessions, authRequests int64, err error) {
@@ -152,6 +152,13 @@ func (s *OAuthStore) CleanupExpired(ctx context.Context, sessionMaxAge, authRequ
 	}
 	sessions, _ = res.RowsAffected()
 
+	res, err = s.db.ExecContext(ctx, `DELETE FROM app_password_sessions WHERE updated_at < ?`, sessCutoff)
+	if err != nil {
+		return sessions, 0, fmt.Errorf("cleanup password sessions: %w", err)
+	}
+	passwordSessions, _ := res.RowsAffected()
+	sessions += passwordSessions
+
 	res, err = s.db.ExecContext(ctx, `DELETE FROM oauth_auth_requests WHERE created_at < ?`, reqCutoff)
 	if err != nil {
 		return sessions, 0, fmt.Errorf("cleanup auth requests: %w", err)
@@ -196,3 +203,41 @@ func (s *OAuthStore) StartCleanup(ctx context.Context, interval, sessionMaxAge,
 		Dur("auth_request_max_age", authRequestMaxAge).
 		Msg("OAuth cleanup started")
 }
+
+// DeleteAllForDID removes every session belonging to did — OAuth and
+// app-password alike — and returns the session IDs it removed so callers
+// can drop any state cached under them.
+func (s *OAuthStore) DeleteAllForDID(ctx context.Context, did syntax.DID) ([]string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var sessionIDs []string
+	for _, table := range []string{"oauth_sessions", "app_password_sessions"} {
+		rows, err := tx.QueryContext(ctx, `SELECT session_id FROM `+table+` WHERE did = ?`, did.String())
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			sessionIDs = append(sessionIDs, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE did = ?`, did.String()); err != nil {
+			return nil, fmt.Errorf("delete from %s: %w", table, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return sessionIDs, nil
+}
@@ -0,0 +1,43 @@
+package atproto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/atclient"
+	"github.com/bluesky-social/indigo/xrpc"
+)
+
+// PDSError is the failure a PDS reported for an XRPC call.
+type PDSError struct {
+	StatusCode int
+	// Name is the XRPC error name from the response body, such as
+	// "ExpiredToken" or "RecordNotFound". Empty if the body had none.
+	Name string
+	// RetryAfter is how long until a rate limit resets, or zero when the
+	// PDS did not say.
+	RetryAfter time.Duration
+}
+
+// AsPDSError finds the PDS response behind err. Both indigo clients are
+// understood: atclient.APIError and the older xrpc.Error. ok is false for
+// errors that never got an answer from the PDS, like network failures.
+func AsPDSError(err error) (pe PDSError, ok bool) {
+	var apiErr *atclient.APIError
+	if errors.As(err, &apiErr) {
+		return PDSError{StatusCode: apiErr.StatusCode, Name: apiErr.Name}, true
+	}
+	var xerr *xrpc.Error
+	if !errors.As(err, &xerr) {
+		return PDSError{}, false
+	}
+	pe.StatusCode = xerr.StatusCode
+	var body *xrpc.XRPCError
+	if errors.As(xerr.Wrapped, &body) {
+		pe.Name = body.ErrStr
+	}
+	if xerr.Ratelimit != nil {
+		pe.RetryAfter = max(time.Until(xerr.Ratelimit.Reset), 0)
+	}
+	return pe, true
+}
@@ -0,0 +1,79 @@
+package atproto
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/atclient"
+	"github.com/bluesky-social/indigo/xrpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsPDSError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   PDSError
+		wantOK bool
+	}{
+		{
+			name:   "api client",
+			err:    &atclient.APIError{StatusCode: http.StatusBadRequest, Name: "ExpiredToken", Message: "Token has expired"},
+			want:   PDSError{StatusCode: http.StatusBadRequest, Name: "ExpiredToken"},
+			wantOK: true,
+		},
+		{
+			name:   "xrpc with body",
+			err:    &xrpc.Error{StatusCode: http.StatusBadRequest, Wrapped: &xrpc.XRPCError{ErrStr: "RecordNotFound"}},
+			want:   PDSError{StatusCode: http.StatusBadRequest, Name: "RecordNotFound"},
+			wantOK: true,
+		},
+		{
+			name:   "xrpc without body",
+			err:    &xrpc.Error{StatusCode: http.StatusBadGateway},
+			want:   PDSError{StatusCode: http.StatusBadGateway},
+			wantOK: true,
+		},
+		{
+			name:   "rate limit already reset",
+			err:    &xrpc.Error{StatusCode: http.StatusTooManyRequests, Ratelimit: &xrpc.RatelimitInfo{Reset: time.Now().Add(-time.Minute)}},
+			want:   PDSError{StatusCode: http.StatusTooManyRequests},
+			wantOK: true,
+		},
+		{name: "network", err: fmt.Errorf("dial tcp: timeout")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := AsPDSError(fmt.Errorf("create record: %w", tt.err))
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAsPDSErrorRetryAfter(t *testing.T) {
+	err := &xrpc.Error{StatusCode: http.StatusTooManyRequests, Ratelimit: &xrpc.RatelimitInfo{Reset: time.Now().Add(30 * time.Second)}}
+	got, ok := AsPDSError(err)
+	assert.True(t, ok)
+	assert.InDelta(t, 30*time.Second, got.RetryAfter, float64(2*time.Second))
+}
+
+func TestIsRecordNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"xrpc record not found", &xrpc.Error{StatusCode: http.StatusBadRequest, Wrapped: &xrpc.XRPCError{ErrStr: "RecordNotFound"}}, true},
+		{"api client record not found", &atclient.APIError{StatusCode: http.StatusBadRequest, Name: "RecordNotFound"}, true},
+		{"other pds error", &xrpc.Error{StatusCode: http.StatusBadRequest, Wrapped: &xrpc.XRPCError{ErrStr: "InvalidRequest", Message: "RecordNotFound"}}, false},
+		{"untyped error mentioning it", fmt.Errorf("RecordNotFound"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRecordNotFound(fmt.Errorf("delete record: %w", tt.err)))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package atproto
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/metrics"
+)
+
+// RetryPolicy controls how public PDS reads are retried. Only GET and HEAD
+// requests are retried, and only after a network error or a 500, 502, 503
+// or 504. Rate limits (429) are never retried, to avoid piling onto a PDS
+// that already asked us to slow down.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the wait before the second attempt. It doubles for each
+	// attempt after that, up to MaxDelay, with up to half of it as jitter.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultRetryPolicy gives a flaky PDS two more chances within about a
+// second, well inside the public client's timeout.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+// maxRetryDrain caps how much of a failed response body is read so the
+// connection can be reused for the next attempt.
+const maxRetryDrain = 64 << 10
+
+// retryTransport retries idempotent requests under a RetryPolicy.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+func newRetryTransport(base http.RoundTripper, policy RetryPolicy) *retryTransport {
+	return &retryTransport{base: base, policy: policy, sleep: sleepContext}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.policy.MaxAttempts < 2 || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+		return t.base.RoundTrip(req)
+	}
+
+	delay := t.policy.BaseDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.policy.MaxAttempts || !shouldRetry(req.Context(), resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, maxRetryDrain))
+			resp.Body.Close()
+		}
+
+		metrics.PDSRetriesTotal.Inc()
+		wait := delay/2 + rand.N(delay/2+1)
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		delay = min(delay*2, t.policy.MaxDelay)
+	}
+}
+
+// shouldRetry reports whether a response or error looks transient. Once
+// the request's own context is done nothing is retried.
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err == nil {
+		switch resp.StatusCode {
+		case http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	// An unknown host won't appear on a second lookup.
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,120 @@
+package atproto
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyTransport plays back one scripted outcome per request: a status code,
+// or an error when the status is zero.
+type flakyTransport struct {
+	script []flakyStep
+	calls  int
+}
+
+type flakyStep struct {
+	status int
+	err    error
+}
+
+func (f *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	step := f.script[min(f.calls, len(f.script)-1)]
+	f.calls++
+	if step.status == 0 {
+		return nil, step.err
+	}
+	return &http.Response{
+		StatusCode: step.status,
+		Body:       io.NopCloser(strings.NewReader(`{}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestRetryTransport(t *testing.T) {
+	ok := flakyStep{status: http.StatusOK}
+	reset := flakyStep{err: syscall.ECONNRESET}
+	unavailable := flakyStep{status: http.StatusServiceUnavailable}
+
+	tests := []struct {
+		name       string
+		method     string
+		script     []flakyStep
+		wantCalls  int
+		wantStatus int
+		wantErr    bool
+	}{
+		{"fails twice then succeeds", http.MethodGet, []flakyStep{reset, unavailable, ok}, 3, http.StatusOK, false},
+		{"gives up after max attempts", http.MethodGet, []flakyStep{unavailable}, 3, http.StatusServiceUnavailable, false},
+		{"network error on every attempt", http.MethodGet, []flakyStep{reset}, 3, 0, true},
+		{"not found is not retried", http.MethodGet, []flakyStep{{status: http.StatusNotFound}}, 1, http.StatusNotFound, false},
+		{"rate limit is not retried", http.MethodGet, []flakyStep{{status: http.StatusTooManyRequests}}, 1, http.StatusTooManyRequests, false},
+		{"unknown error is not retried", http.MethodGet, []flakyStep{{err: errors.New("tls: bad certificate")}}, 1, 0, true},
+		{"POST is not retried", http.MethodPost, []flakyStep{unavailable, ok}, 1, http.StatusServiceUnavailable, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := &flakyTransport{script: tt.script}
+			var waits []time.Duration
+			rt := newRetryTransport(base, RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 150 * time.Millisecond})
+			rt.sleep = func(_ context.Context, d time.Duration) error {
+				waits = append(waits, d)
+				return nil
+			}
+
+			req, err := http.NewRequest(tt.method, "https://pds.example/xrpc/com.atproto.repo.getRecord", nil)
+			require.NoError(t, err)
+			resp, err := rt.RoundTrip(req)
+
+			assert.Equal(t, tt.wantCalls, base.calls)
+			assert.Len(t, waits, tt.wantCalls-1)
+			for _, w := range waits {
+				assert.LessOrEqual(t, w, 150*time.Millisecond, "waits stay under MaxDelay")
+			}
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			resp.Body.Close()
+		})
+	}
+}
+
+func TestRetryTransport_StopsWhenContextDone(t *testing.T) {
+	base := &flakyTransport{script: []flakyStep{{status: http.StatusBadGateway}}}
+	rt := newRetryTransport(base, DefaultRetryPolicy)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rt.sleep = func(ctx context.Context, _ time.Duration) error {
+		cancel()
+		return ctx.Err()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://pds.example/xrpc/app.bsky.actor.getProfile", nil)
+	require.NoError(t, err)
+
+	_, err = rt.RoundTrip(req)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, base.calls)
+}
+
+func TestRetryTransport_Disabled(t *testing.T) {
+	base := &flakyTransport{script: []flakyStep{{status: http.StatusBadGateway}}}
+	rt := newRetryTransport(base, RetryPolicy{MaxAttempts: 1})
+
+	req, err := http.NewRequest(http.MethodGet, "https://pds.example/xrpc/app.bsky.actor.getProfile", nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 1, base.calls)
+}
@@ -0,0 +1,109 @@
+package atproto
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/bluesky-social/indigo/atproto/atclient"
+	"github.com/bluesky-social/indigo/atproto/auth/oauth"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/rs/zerolog/log"
+)
+
+// maxAuthErrorBody caps how much of a 400/401 response is read to look for
+// an ExpiredToken error name.
+const maxAuthErrorBody = 64 << 10
+
+// refreshingAuth retries a request once, with fresh tokens, when the PDS
+// says the access token has expired. indigo already does this when the PDS
+// signals expiry the way each auth method expects (a WWW-Authenticate
+// header for OAuth, a 400 ExpiredToken for app passwords), but PDSes
+// aren't consistent about it, and an ExpiredToken that slips through
+// would otherwise reach the user as a failed write.
+//
+// Refreshed tokens are persisted by the wrapped session's own callback:
+// PersistSessionCallback for OAuth, RefreshCallback for app passwords.
+type refreshingAuth struct {
+	inner atclient.AuthMethod
+}
+
+// refreshOnExpiredToken wraps api's auth method in refreshingAuth. Clients
+// with no auth, or an auth method we can't refresh, are left alone.
+func refreshOnExpiredToken(api *atclient.APIClient) {
+	switch api.Auth.(type) {
+	case *oauth.ClientSession, *atclient.PasswordAuth:
+		api.Auth = &refreshingAuth{inner: api.Auth}
+	}
+}
+
+func (a *refreshingAuth) DoWithAuth(c *http.Client, req *http.Request, endpoint syntax.NSID) (*http.Response, error) {
+	priorRefresh := a.refreshToken()
+	resp, err := a.inner.DoWithAuth(c, req, endpoint)
+	if err != nil || !isExpiredTokenResponse(resp) || !canReplay(req) {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	if err := a.refresh(req.Context(), c, priorRefresh); err != nil {
+		log.Warn().Err(err).Str("endpoint", endpoint.String()).Msg("Failed to refresh expired PDS session")
+		return nil, errors.Join(ErrSessionExpired, err)
+	}
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, fmt.Errorf("copy request body for retry: %w", err)
+		}
+	}
+	return a.inner.DoWithAuth(c, retry, endpoint)
+}
+
+// refreshToken is the refresh token the next request goes out with, used
+// to notice when a concurrent request has already refreshed.
+func (a *refreshingAuth) refreshToken() string {
+	if pw, ok := a.inner.(*atclient.PasswordAuth); ok {
+		_, refresh := pw.GetTokens()
+		return refresh
+	}
+	return ""
+}
+
+func (a *refreshingAuth) refresh(ctx context.Context, c *http.Client, priorRefresh string) error {
+	switch auth := a.inner.(type) {
+	case *oauth.ClientSession:
+		_, err := auth.RefreshTokens(ctx)
+		return err
+	case *atclient.PasswordAuth:
+		return auth.Refresh(ctx, c, priorRefresh)
+	}
+	return fmt.Errorf("cannot refresh %T", a.inner)
+}
+
+// canReplay reports whether req can be sent a second time.
+func canReplay(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// isExpiredTokenResponse reports whether resp is an XRPC ExpiredToken
+// error. The body is put back so the caller can still read it.
+func isExpiredTokenResponse(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusBadRequest && resp.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		return false
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxAuthErrorBody))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	var eb atclient.ErrorBody
+	return json.Unmarshal(body, &eb) == nil && eb.Name == "ExpiredToken"
+}
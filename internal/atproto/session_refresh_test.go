@@ -0,0 +1,120 @@
+package atproto
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bluesky-social/indigo/atproto/atclient"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expiringPDS answers createRecord with 401 ExpiredToken until the client
+// presents the access token handed out by refreshSession.
+func expiringPDS(t *testing.T, refreshOK bool) (srv *httptest.Server, bodies *[]string) {
+	t.Helper()
+	bodies = &[]string{}
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/xrpc/com.atproto.server.refreshSession":
+			if !refreshOK || r.Header.Get("Authorization") != "Bearer refresh-1" {
+				w.WriteHeader(http.StatusBadRequest)
+				io.WriteString(w, `{"error":"ExpiredToken","message":"Refresh token has expired"}`)
+				return
+			}
+			io.WriteString(w, `{"accessJwt":"access-2","refreshJwt":"refresh-2","did":"did:plc:alice"}`)
+		case "/xrpc/com.atproto.repo.createRecord":
+			body, _ := io.ReadAll(r.Body)
+			*bodies = append(*bodies, string(body))
+			if r.Header.Get("Authorization") != "Bearer access-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				io.WriteString(w, `{"error":"ExpiredToken","message":"Token has expired"}`)
+				return
+			}
+			io.WriteString(w, `{"uri":"at://did:plc:alice/social.arabica.alpha.bean/1","cid":"bafy"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, bodies
+}
+
+func TestRefreshingAuthRetriesAfterExpiredToken(t *testing.T) {
+	srv, bodies := expiringPDS(t, true)
+
+	var saved []atclient.PasswordSessionData
+	api := atclient.ResumePasswordSession(atclient.PasswordSessionData{
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+		AccountDID:   syntax.DID("did:plc:alice"),
+		Host:         srv.URL,
+	}, func(_ context.Context, data atclient.PasswordSessionData) {
+		saved = append(saved, data)
+	})
+	refreshOnExpiredToken(api)
+
+	var out struct {
+		CID string `json:"cid"`
+	}
+	err := api.Post(context.Background(), "com.atproto.repo.createRecord", map[string]any{"rkey": "1"}, &out)
+	require.NoError(t, err)
+	assert.Equal(t, "bafy", out.CID)
+
+	require.Len(t, *bodies, 2, "the write is retried once")
+	assert.Equal(t, (*bodies)[0], (*bodies)[1], "the retry resends the same body")
+	require.Len(t, saved, 1, "refreshed tokens are persisted")
+	assert.Equal(t, "access-2", saved[0].AccessToken)
+	assert.Equal(t, "refresh-2", saved[0].RefreshToken)
+}
+
+func TestRefreshingAuthReportsExpiredSession(t *testing.T) {
+	srv, bodies := expiringPDS(t, false)
+
+	api := atclient.ResumePasswordSession(atclient.PasswordSessionData{
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+		AccountDID:   syntax.DID("did:plc:alice"),
+		Host:         srv.URL,
+	}, nil)
+	refreshOnExpiredToken(api)
+
+	err := api.Post(context.Background(), "com.atproto.repo.createRecord", map[string]any{"rkey": "1"}, nil)
+	assert.ErrorIs(t, err, ErrSessionExpired)
+	assert.Len(t, *bodies, 1)
+}
+
+func TestIsExpiredTokenResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		want        bool
+	}{
+		{"expired", http.StatusBadRequest, "application/json", `{"error":"ExpiredToken"}`, true},
+		{"expired on 401", http.StatusUnauthorized, "application/json; charset=utf-8", `{"error":"ExpiredToken"}`, true},
+		{"other error", http.StatusBadRequest, "application/json", `{"error":"InvalidRequest"}`, false},
+		{"not json", http.StatusUnauthorized, "text/plain", `ExpiredToken`, false},
+		{"success", http.StatusOK, "application/json", `{"error":"ExpiredToken"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Type", tt.contentType)
+			rec.WriteHeader(tt.status)
+			io.WriteString(rec, tt.body)
+			resp := rec.Result()
+
+			assert.Equal(t, tt.want, isExpiredTokenResponse(resp))
+			rest, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(rest), "body stays readable")
+		})
+	}
+}
@@ -23,11 +23,19 @@ type AtprotoStore struct {
 	cache        *SessionCache
 	witnessCache WitnessCache // optional; enables cache-first reads without PDS calls
 
-	// likeNSID and commentNSID are the collection NSIDs this store reads
-	// and writes for likes/comments. They must be set by app-aware
-	// production callers before shared social handlers can write records.
-	likeNSID    string
-	commentNSID string
+	// collections are the NSIDs this store reads and writes for social
+	// records. They must be set by app-aware production callers before
+	// shared social handlers can write records.
+	collections SocialCollections
+}
+
+// SocialCollections names an app's social record collections, e.g.
+// social.arabica.alpha.like for arabica.
+type SocialCollections struct {
+	Like     string
+	Comment  string
+	Follow   string
+	Bookmark string
 }
 
 // NewAtprotoStore creates a new atproto store for a specific user session.
@@ -41,25 +49,24 @@ func NewAtprotoStore(client *Client, did syntax.DID, sessionID string, cache *Se
 	}
 }
 
-// NewAtprotoStoreForApp builds a store wired with per-app like/comment NSIDs.
-func NewAtprotoStoreForApp(client *Client, did syntax.DID, sessionID string, cache *SessionCache, witness WitnessCache, likeNSID, commentNSID string) *AtprotoStore {
+// NewAtprotoStoreForApp builds a store wired with per-app social NSIDs.
+func NewAtprotoStoreForApp(client *Client, did syntax.DID, sessionID string, cache *SessionCache, witness WitnessCache, collections SocialCollections) *AtprotoStore {
 	return &AtprotoStore{
 		client:       client,
 		did:          did,
 		sessionID:    sessionID,
 		cache:        cache,
 		witnessCache: witness,
-		likeNSID:     likeNSID,
-		commentNSID:  commentNSID,
+		collections:  collections,
 	}
 }
 
 func (s *AtprotoStore) likeCollection() string {
-	return s.likeNSID
+	return s.collections.Like
 }
 
 func (s *AtprotoStore) commentCollection() string {
-	return s.commentNSID
+	return s.collections.Comment
 }
 
 // atpClient returns an *atp.Client scoped to this store's DID and session.
@@ -275,6 +282,123 @@ func (s *AtprotoStore) ListUserLikes(ctx context.Context) ([]*social.Like, error
 	return likes, nil
 }
 
+// ========== Follow Operations ==========
+
+// CreateFollow writes a follow record for subjectDID.
+func (s *AtprotoStore) CreateFollow(ctx context.Context, subjectDID string) (*social.Follow, error) {
+	if s.collections.Follow == "" {
+		return nil, fmt.Errorf("follow collection is not configured")
+	}
+	if subjectDID == s.did.String() {
+		return nil, fmt.Errorf("cannot follow yourself")
+	}
+
+	follow := &social.Follow{
+		SubjectDID: subjectDID,
+		CreatedAt:  time.Now().UTC(),
+	}
+	record, err := social.FollowToRecord(s.collections.Follow, follow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert follow to record: %w", err)
+	}
+	rkey, _, err := s.PutRecord(ctx, s.collections.Follow, "", record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create follow record: %w", err)
+	}
+	follow.RKey = rkey
+
+	return follow, nil
+}
+
+// DeleteFollow removes the follow record with the given rkey.
+func (s *AtprotoStore) DeleteFollow(ctx context.Context, rkey string) error {
+	if s.collections.Follow == "" {
+		return fmt.Errorf("follow collection is not configured")
+	}
+	if err := s.RemoveRecord(ctx, s.collections.Follow, rkey); err != nil {
+		return fmt.Errorf("failed to delete follow record: %w", err)
+	}
+	return nil
+}
+
+// ========== Bookmark Operations ==========
+
+func (s *AtprotoStore) CreateBookmark(ctx context.Context, req *social.CreateBookmarkRequest) (*social.Bookmark, error) {
+	if req.SubjectURI == "" {
+		return nil, fmt.Errorf("subject_uri is required")
+	}
+	if req.SubjectCID == "" {
+		return nil, fmt.Errorf("subject_cid is required")
+	}
+	collection := s.collections.Bookmark
+	if collection == "" {
+		return nil, fmt.Errorf("bookmark collection is not configured")
+	}
+
+	bookmark := &social.Bookmark{
+		SubjectURI: req.SubjectURI,
+		SubjectCID: req.SubjectCID,
+		CreatedAt:  time.Now().UTC(),
+	}
+	record, err := social.BookmarkToRecord(collection, bookmark)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert bookmark to record: %w", err)
+	}
+	rkey, _, err := s.PutRecord(ctx, collection, "", record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bookmark record: %w", err)
+	}
+	bookmark.RKey = rkey
+
+	return bookmark, nil
+}
+
+// DeleteBookmarkByRKey removes a bookmark. A bookmark that is already gone
+// counts as deleted, so a double-click or a stale index entry can't fail.
+func (s *AtprotoStore) DeleteBookmarkByRKey(ctx context.Context, rkey string) error {
+	collection := s.collections.Bookmark
+	if collection == "" {
+		return fmt.Errorf("bookmark collection is not configured")
+	}
+	if err := s.RemoveRecord(ctx, collection, rkey); err != nil && !IsRecordNotFound(err) {
+		return fmt.Errorf("failed to delete bookmark record: %w", err)
+	}
+	return nil
+}
+
+func (s *AtprotoStore) ListUserBookmarks(ctx context.Context) ([]*social.Bookmark, error) {
+	collection := s.collections.Bookmark
+	if collection == "" {
+		return nil, fmt.Errorf("bookmark collection is not configured")
+	}
+	atpClient, err := s.atpClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get atp client: %w", err)
+	}
+	records, err := atpClient.ListAllRecords(ctx, collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookmark records: %w", err)
+	}
+
+	bookmarks := make([]*social.Bookmark, 0, len(records))
+	for _, rec := range records {
+		bookmark, err := social.RecordToBookmark(rec.Value, rec.URI)
+		if err != nil {
+			log.Warn().Err(err).Str("uri", rec.URI).Msg("Failed to convert bookmark record")
+			continue
+		}
+		bookmarks = append(bookmarks, bookmark)
+	}
+	return bookmarks, nil
+}
+
+// IsRecordNotFound reports whether a PDS error means the record doesn't
+// exist.
+func IsRecordNotFound(err error) bool {
+	pe, ok := AsPDSError(err)
+	return ok && pe.Name == "RecordNotFound"
+}
+
 // ========== Comment Operations ==========
 
 func (s *AtprotoStore) CreateComment(ctx context.Context, req *social.CreateCommentRequest) (*social.Comment, error) {
@@ -328,6 +452,43 @@ func (s *AtprotoStore) DeleteCommentByRKey(ctx context.Context, rkey string) err
 	return nil
 }
 
+// UpdateCommentByRKey replaces the text of one of the user's comments. The
+// subject, parent, and createdAt are carried over from the stored record and
+// editedAt is stamped so readers can tell the comment changed. The returned
+// comment's CID is empty; the firehose delivers the new CID.
+func (s *AtprotoStore) UpdateCommentByRKey(ctx context.Context, rkey, text string) (*social.Comment, error) {
+	if text == "" {
+		return nil, fmt.Errorf("text is required")
+	}
+
+	collection := s.commentCollection()
+	if collection == "" {
+		return nil, fmt.Errorf("comment collection is not configured")
+	}
+
+	existing, uri, _, err := s.FetchRecord(ctx, collection, rkey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch comment record: %w", err)
+	}
+	comment, err := social.RecordToComment(existing, uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert comment record: %w", err)
+	}
+	comment.Text = text
+	comment.EditedAt = time.Now().UTC()
+
+	record, err := social.CommentToRecord(collection, comment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert comment to record: %w", err)
+	}
+	if _, _, err := s.PutRecord(ctx, collection, rkey, record); err != nil {
+		return nil, fmt.Errorf("failed to update comment record: %w", err)
+	}
+	comment.RKey = rkey
+
+	return comment, nil
+}
+
 func (s *AtprotoStore) GetCommentsForSubject(ctx context.Context, subjectURI string) ([]*social.Comment, error) {
 	// List all comments and filter by subject URI
 	// Note: This is inefficient for large numbers of comments.
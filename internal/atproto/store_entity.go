@@ -91,12 +91,15 @@ func ListEntity[M any](ctx context.Context, s entityStore, c *EntityCodec[M], ca
 // UpdateEntity overwrites an existing record. The supplied model must
 // already carry whatever fields should be preserved across the update
 // (e.g. CreatedAt copied from the existing record by the caller).
-func UpdateEntity[M any](ctx context.Context, s entityStore, c *EntityCodec[M], rkey string, model *M) error {
+// swapCID is the CID of the record the caller read; if the record has
+// changed since, the update fails with ErrRecordModified. Pass "" to
+// overwrite unconditionally.
+func UpdateEntity[M any](ctx context.Context, s entityStore, c *EntityCodec[M], rkey, swapCID string, model *M) error {
 	rec, err := c.ToRecord(s, model)
 	if err != nil {
 		return fmt.Errorf("convert %s: %w", c.NSID, err)
 	}
-	_, _, err = s.PutRecord(ctx, c.NSID, rkey, rec)
+	_, err = s.UpdateRecord(ctx, c.NSID, rkey, swapCID, rec)
 	return err
 }
 
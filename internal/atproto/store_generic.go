@@ -2,11 +2,13 @@ package atproto
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/bluesky-social/indigo/atproto/atclient"
 	"github.com/bluesky-social/indigo/atproto/syntax"
 	"github.com/bluesky-social/indigo/xrpc"
 	"github.com/rs/zerolog/log"
@@ -83,6 +85,37 @@ func (s *AtprotoStore) PutRecord(ctx context.Context, nsid, rkey string, record
 	return s.putRecord(ctx, nsid, rkey, record)
 }
 
+// UpdateRecord overwrites the record at nsid/rkey only if its current CID
+// is still swapCID, and returns the new CID. See updateRecord.
+func (s *AtprotoStore) UpdateRecord(ctx context.Context, nsid, rkey, swapCID string, record any) (cid string, err error) {
+	return s.updateRecord(ctx, nsid, rkey, swapCID, record)
+}
+
+// UploadBlob stores data in the user's repo via com.atproto.repo.uploadBlob
+// and returns the blob ref in record form, ready to embed in a record map.
+// The blob is only retained by the PDS once a record references it.
+func (s *AtprotoStore) UploadBlob(ctx context.Context, data []byte, mimeType string) (map[string]any, error) {
+	client, err := s.atpClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := client.UploadBlob(ctx, data, mimeType)
+	if err != nil {
+		return nil, fmt.Errorf("upload blob: %w", err)
+	}
+	// Round-trip through JSON so callers see the wire shape rather than the
+	// client library's struct.
+	b, err := json.Marshal(blob)
+	if err != nil {
+		return nil, fmt.Errorf("encode blob ref: %w", err)
+	}
+	var ref map[string]any
+	if err := json.Unmarshal(b, &ref); err != nil {
+		return nil, fmt.Errorf("decode blob ref: %w", err)
+	}
+	return ref, nil
+}
+
 // RemoveRecord exposes the generic delete primitive. Removes the record
 // from PDS, evicts the witness entry, and invalidates the session cache.
 func (s *AtprotoStore) RemoveRecord(ctx context.Context, nsid, rkey string) error {
@@ -281,6 +314,71 @@ func (s *AtprotoStore) putRecord(ctx context.Context, nsid, rkey string, record
 	return rkey, "", nil
 }
 
+// ErrRecordModified is returned by UpdateRecord when the record changed on
+// the PDS after the caller read it, so the write was refused rather than
+// overwriting someone else's edit.
+var ErrRecordModified = errors.New("record was modified since it was loaded")
+
+// putRecordInput is the com.atproto.repo.putRecord body. It is sent directly
+// rather than through atp.Client.PutRecord, which has no swapRecord option
+// and does not return the new CID.
+type putRecordInput struct {
+	Repo       string `json:"repo"`
+	Collection string `json:"collection"`
+	RKey       string `json:"rkey"`
+	Record     any    `json:"record"`
+	SwapRecord string `json:"swapRecord,omitempty"`
+}
+
+type putRecordOutput struct {
+	URI string `json:"uri"`
+	CID string `json:"cid"`
+}
+
+// updateRecord overwrites an existing record with the PDS comparing
+// swapCID against the record's current CID first (optimistic concurrency).
+// A mismatch comes back as ErrRecordModified. An empty swapCID writes
+// unconditionally. Because the PDS returns the new CID here, the witness
+// cache gets the real CID rather than keeping the stale one until the
+// firehose catches up, so a follow-up edit doesn't trip over itself.
+func (s *AtprotoStore) updateRecord(ctx context.Context, nsid, rkey, swapCID string, record any) (string, error) {
+	atpClient, err := s.atpClient(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get atp client: %w", err)
+	}
+	var out putRecordOutput
+	err = atpClient.APIClient().Post(ctx, syntax.NSID("com.atproto.repo.putRecord"), putRecordInput{
+		Repo:       s.did.String(),
+		Collection: nsid,
+		RKey:       rkey,
+		Record:     record,
+		SwapRecord: swapCID,
+	}, &out)
+	if err != nil {
+		if isInvalidSwapError(err) {
+			return "", fmt.Errorf("put record %s/%s: %w", nsid, rkey, ErrRecordModified)
+		}
+		return "", fmt.Errorf("put record %s/%s: %w", nsid, rkey, err)
+	}
+	s.writeThroughWitness(nsid, rkey, out.CID, record)
+	s.cache.InvalidateRecords(s.sessionID, nsid)
+	return out.CID, nil
+}
+
+// isInvalidSwapError reports whether err is the PDS refusing a write whose
+// swapRecord no longer matches.
+func isInvalidSwapError(err error) bool {
+	var apiErr *atclient.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Name == "InvalidSwap"
+	}
+	var xerr *xrpc.Error
+	if errors.As(err, &xerr) && xerr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	return strings.Contains(err.Error(), "InvalidSwap")
+}
+
 // removeRecord deletes from PDS, witness, and invalidates session cache.
 func (s *AtprotoStore) removeRecord(ctx context.Context, nsid, rkey string) error {
 	atpClient, err := s.atpClient(ctx)
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/bluesky-social/indigo/atproto/atclient"
 	"github.com/bluesky-social/indigo/xrpc"
 	"github.com/stretchr/testify/assert"
 )
@@ -32,3 +33,22 @@ func TestIsRepoNotFoundErrorRejectsOtherBadRequests(t *testing.T) {
 
 	assert.False(t, isRepoNotFoundError(err))
 }
+
+func TestIsInvalidSwapError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"api client swap", &atclient.APIError{StatusCode: http.StatusBadRequest, Name: "InvalidSwap", Message: "Record was at bafy-old"}, true},
+		{"api client other", &atclient.APIError{StatusCode: http.StatusBadRequest, Name: "InvalidRequest"}, false},
+		{"xrpc swap", &xrpc.Error{StatusCode: http.StatusBadRequest, Wrapped: &xrpc.XRPCError{ErrStr: "InvalidSwap"}}, true},
+		{"server error", &xrpc.Error{StatusCode: http.StatusInternalServerError, Wrapped: &xrpc.XRPCError{ErrStr: "InvalidSwap"}}, false},
+		{"unrelated", fmt.Errorf("dial tcp: timeout"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isInvalidSwapError(fmt.Errorf("put record: %w", tt.err)))
+		})
+	}
+}
@@ -52,6 +52,12 @@ type WitnessCache interface {
 	// DID+collection pair. Returns 0 when none are found or on error.
 	CountWitnessRecords(ctx context.Context, did, collection string) (int, error)
 
+	// ListPublishedWitnessRecords and CountPublishedWitnessRecords are
+	// ListWitnessRecordsPaginated and CountWitnessRecords without drafts,
+	// for showing a user's records to anyone else.
+	ListPublishedWitnessRecords(ctx context.Context, did, collection string, offset, limit int) ([]*WitnessRecord, error)
+	CountPublishedWitnessRecords(ctx context.Context, did, collection string) (int, error)
+
 	// UpsertWitnessRecord inserts or updates a record in the cache.
 	// Used for write-through caching after successful PDS mutations.
 	UpsertWitnessRecord(ctx context.Context, did, collection, rkey, cid string, record json.RawMessage) error
@@ -0,0 +1,33 @@
+// Package brewtags holds the tag rules shared by the brew form and the
+// firehose index. Both have to agree, or a tag saved from the form won't be
+// found from its chip.
+package brewtags
+
+import "strings"
+
+// Max caps how many tags a brew can carry, matching the lexicon.
+const Max = 10
+
+// Normalize returns the canonical form of a single tag: trimmed,
+// lowercased, and without a leading '#'.
+func Normalize(tag string) string {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// NormalizeAll normalizes tags in order, dropping empty entries and
+// duplicates. It does not apply Max; callers decide whether going over it
+// is an error or gets cut off.
+func NormalizeAll(tags []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(tags))
+	for _, raw := range tags {
+		tag := Normalize(raw)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
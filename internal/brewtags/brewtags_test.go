@@ -0,0 +1,25 @@
+package brewtags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAll(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"empty", nil, nil},
+		{"trims and lowercases", []string{" Washed ", "Light Roast"}, []string{"washed", "light roast"}},
+		{"strips hash and empties", []string{"#morning", "", " ", "#"}, []string{"morning"}},
+		{"dedupes after normalizing", []string{"Fruity", "fruity", "#FRUITY"}, []string{"fruity"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeAll(tt.tags))
+		})
+	}
+}
@@ -65,17 +65,22 @@ func (r *Registry) Register(did string) {
 	}
 }
 
-// Unregister removes a DID from the registry.
-// If a persistent store is configured, the DID is also removed from the store.
-func (r *Registry) Unregister(did string) {
+// Unregister removes a DID from the registry and, if a persistent store is
+// configured, from the store. Unlike Register, a store failure is returned
+// and the DID stays registered: a removal that only happened in memory would
+// quietly come back on the next restart.
+func (r *Registry) Unregister(did string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	delete(r.dids, did)
-
 	if r.store != nil {
-		_ = r.store.Unregister(did)
+		if err := r.store.Unregister(did); err != nil {
+			return err
+		}
 	}
+
+	delete(r.dids, did)
+	return nil
 }
 
 // IsRegistered checks if a DID is in the registry.
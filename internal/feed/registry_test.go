@@ -0,0 +1,86 @@
+package feed
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memStore is a PersistentStore whose Unregister can be made to fail.
+type memStore struct {
+	dids          map[string]struct{}
+	unregisterErr error
+}
+
+func newMemStore() *memStore { return &memStore{dids: map[string]struct{}{}} }
+
+func (m *memStore) Register(did string) error { m.dids[did] = struct{}{}; return nil }
+func (m *memStore) Unregister(did string) error {
+	if m.unregisterErr != nil {
+		return m.unregisterErr
+	}
+	delete(m.dids, did)
+	return nil
+}
+func (m *memStore) IsRegistered(did string) bool { _, ok := m.dids[did]; return ok }
+func (m *memStore) Count() int                   { return len(m.dids) }
+func (m *memStore) List() []string {
+	out := make([]string, 0, len(m.dids))
+	for did := range m.dids {
+		out = append(out, did)
+	}
+	return out
+}
+
+func TestRegistryUnregister(t *testing.T) {
+	tests := []struct {
+		name      string
+		store     *memStore
+		storeErr  error
+		wantErr   bool
+		wantCount int
+	}{
+		{name: "in-memory only", wantCount: 1},
+		{name: "persisted", store: newMemStore(), wantCount: 1},
+		{name: "store failure keeps the DID", store: newMemStore(), storeErr: errors.New("disk full"), wantErr: true, wantCount: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r *Registry
+			if tt.store != nil {
+				r = NewPersistentRegistry(tt.store)
+			} else {
+				r = NewRegistry()
+			}
+			r.Register("did:plc:alice")
+			r.Register("did:plc:bob")
+			assert.Equal(t, 2, r.Count())
+
+			if tt.store != nil {
+				tt.store.unregisterErr = tt.storeErr
+			}
+			err := r.Unregister("did:plc:alice")
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.storeErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantCount, r.Count())
+			assert.Equal(t, tt.wantErr, r.IsRegistered("did:plc:alice"))
+			assert.True(t, r.IsRegistered("did:plc:bob"))
+			if tt.store != nil {
+				assert.Equal(t, tt.wantErr, tt.store.IsRegistered("did:plc:alice"))
+			}
+		})
+	}
+}
+
+func TestRegistryUnregisterUnknownDID(t *testing.T) {
+	r := NewPersistentRegistry(newMemStore())
+	r.Register("did:plc:alice")
+
+	assert.NoError(t, r.Unregister("did:plc:nobody"))
+	assert.Equal(t, 1, r.Count())
+}
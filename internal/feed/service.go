@@ -2,7 +2,9 @@ package feed
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,17 +18,31 @@ import (
 )
 
 const (
-	// PublicFeedCacheTTL is the duration for which the public feed cache is valid.
-	// This value can be adjusted based on desired freshness vs. performance tradeoff.
-	// Consider values between 5-10 minutes for a good balance.
+	// PublicFeedCacheTTL is the default duration for which the public feed
+	// cache is valid. New records invalidate the cache sooner, so this is
+	// the longest an anonymous visitor can see a stale feed while the
+	// firehose is quiet or disconnected. Override with
+	// WithPublicFeedCacheTTL.
 	PublicFeedCacheTTL = 5 * time.Minute
 
-	// PublicFeedCacheSize is the number of items to cache in the server
+	// PublicFeedHTMLCacheTTL is how long a rendered public feed partial is
+	// served before it is re-rendered. New records invalidate it sooner; the
+	// TTL only matters when the firehose is quiet or disconnected.
+	PublicFeedHTMLCacheTTL = time.Minute
+
+	// PublicFeedCacheSize is the default number of items to cache in the
+	// server. Override with WithPublicFeedCacheSize.
 	PublicFeedCacheSize = 20
 	// PublicFeedLimit is the number of items to show for unauthenticated users
 	PublicFeedLimit = 10
 	// Number of feed items to show for authenticated users.
 	FeedLimit = 20
+	// MaxFeaturedItems caps how many pinned records are shown above the feed.
+	MaxFeaturedItems = 5
+
+	// PopularFeedWindow bounds the candidate set for FeedSortPopular so the
+	// score reflects recent engagement instead of all-time totals.
+	PopularFeedWindow = 30 * 24 * time.Hour
 )
 
 // FeedItem is a feed entry produced by the firehose layer and consumed
@@ -35,29 +51,33 @@ const (
 // populate per-request when an authenticated viewer is present and stay
 // zero otherwise.
 type FeedItem struct {
-	RecordType lexicons.RecordType // Use lexicons.RecordTypeBrew, lexicons.RecordTypeBean, etc.
-	Action     string              // "added a new brew", "added a new bean", etc.
+	RecordType lexicons.RecordType `json:"record_type"` // Use lexicons.RecordTypeBrew, lexicons.RecordTypeBean, etc.
+	Action     string              `json:"action"`      // "added a new brew", "added a new bean", etc.
 
 	// Record carries the typed model (*arabica.Brew, etc.). Access via the
 	// per-entity accessor methods (Brew(), Bean(), …).
-	Record any
+	Record any `json:"record"`
 
-	Author    *atproto.Profile
-	Timestamp time.Time
-	TimeAgo   string // "2 hours ago", "yesterday", etc.
+	Author    *atproto.Profile `json:"author"`
+	Timestamp time.Time        `json:"timestamp"`
+	TimeAgo   string           `json:"time_ago"` // "2 hours ago", "yesterday", etc.
 
 	// Like-related fields
-	LikeCount  int    // Number of likes on this record
-	SubjectURI string // AT-URI of this record (for like button)
-	SubjectCID string // CID of this record (for like button)
+	LikeCount  int    `json:"like_count"`  // Number of likes on this record
+	SubjectURI string `json:"subject_uri"` // AT-URI of this record (for like button)
+	SubjectCID string `json:"subject_cid"` // CID of this record (for like button)
 
 	// Comment-related fields
-	CommentCount int // Number of comments on this record
+	CommentCount int `json:"comment_count"` // Number of comments on this record
 
 	// Viewer-context fields, populated per-request by the feed service
 	// when an authenticated viewer is present. Zero otherwise.
-	IsLikedByViewer bool
-	IsOwner         bool
+	IsLikedByViewer bool `json:"is_liked_by_viewer"`
+	IsOwner         bool `json:"is_owner"`
+
+	// Featured is set on pinned records placed above the feed by
+	// WithFeatured.
+	Featured bool `json:"featured"`
 }
 
 // RKey returns the record key of whichever typed record is set on this
@@ -96,18 +116,31 @@ func (f *FeedItem) DisplayTitle() string {
 	return d.DisplayName
 }
 
-// publicFeedCache holds cached feed items for unauthenticated users
+// publicFeedCache holds cached feed items for unauthenticated users, plus
+// the partial rendered from them. generation is bumped on every
+// invalidation so a render that raced an invalidation is not stored.
 type publicFeedCache struct {
 	items     []*FeedItem
 	expiresAt time.Time
-	mu        sync.RWMutex
+
+	html          []byte
+	htmlExpiresAt time.Time
+	generation    uint64
+
+	mu sync.RWMutex
 }
 
 // FeedSort defines the sort order for feed queries
 type FeedSort string
 
 const (
-	FeedSortRecent  FeedSort = "recent"
+	FeedSortRecent FeedSort = "recent"
+
+	// FeedSortPopular ranks records from the last PopularFeedWindow by
+	// engagement. Ranking happens over a page-sized window of candidates, so
+	// cursor pagination is best-effort: later pages continue from the oldest
+	// candidate of the previous window and may repeat or skip items whose
+	// score changed in between.
 	FeedSortPopular FeedSort = "popular"
 )
 
@@ -118,6 +151,9 @@ type FeedQuery struct {
 	TypeFilter  lexicons.RecordType
 	TypeFilters []lexicons.RecordType
 	Sort        FeedSort
+	// FollowerDID, when set, restricts the feed to records authored by
+	// accounts this DID follows.
+	FollowerDID string
 }
 
 // FeedResult contains feed items plus pagination info
@@ -126,6 +162,30 @@ type FeedResult struct {
 	NextCursor string
 }
 
+// ErrInvalidCursor is returned when a pagination cursor is not in the
+// "created_at|uri" form produced by FormatCursor.
+var ErrInvalidCursor = errors.New("invalid feed cursor")
+
+// FormatCursor builds the opaque pagination cursor for the item that ends a
+// page. The next page starts strictly after this (timestamp, uri) pair.
+func FormatCursor(timestamp time.Time, uri string) string {
+	return timestamp.Format(time.RFC3339Nano) + "|" + uri
+}
+
+// ParseCursor splits a cursor produced by FormatCursor into its raw
+// created_at and URI parts. The timestamp is returned as the original string
+// so callers can compare it against the index's stored representation.
+func ParseCursor(cursor string) (createdAt, uri string, err error) {
+	createdAt, uri, ok := strings.Cut(cursor, "|")
+	if !ok || !strings.HasPrefix(uri, "at://") {
+		return "", "", ErrInvalidCursor
+	}
+	if _, err := time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return "", "", ErrInvalidCursor
+	}
+	return createdAt, uri, nil
+}
+
 // Source is the feed service's storage seam. Implementations hide whether
 // items come from the firehose witness index, a cache, or another reader.
 type Source interface {
@@ -134,20 +194,67 @@ type Source interface {
 	GetFeedWithQuery(ctx context.Context, q FeedQuery) (*FeedResult, error)
 }
 
+// ItemLookup is implemented by sources that can hydrate specific records
+// into feed items. Featured items are only shown when the configured Source
+// implements it.
+type ItemLookup interface {
+	GetFeedItemsByURI(ctx context.Context, uris []string) ([]*FeedItem, error)
+}
+
+// PinnedSource lists the records an admin has pinned to the top of the feed.
+type PinnedSource interface {
+	ListPinned(ctx context.Context) ([]moderation.PinnedRecord, error)
+}
+
 // Service fetches and aggregates brews from registered users
 type Service struct {
 	registry         *Registry
 	cache            *publicFeedCache
+	cacheTTL         time.Duration
+	cacheSize        int
 	source           Source
 	moderationFilter moderation.FilterSource
+	pinned           PinnedSource
+	defaultLimit     int
+}
+
+// ServiceOption configures a Service at construction.
+type ServiceOption func(*Service)
+
+// WithPublicFeedCacheTTL sets how long the public feed cache is served
+// before it is refreshed. Non-positive values keep PublicFeedCacheTTL.
+func WithPublicFeedCacheTTL(ttl time.Duration) ServiceOption {
+	return func(s *Service) {
+		if ttl > 0 {
+			s.cacheTTL = ttl
+		}
+	}
+}
+
+// WithPublicFeedCacheSize sets how many items the public feed cache holds.
+// The cache never holds fewer than PublicFeedLimit, the number shown to
+// anonymous visitors, so smaller values are raised to it. Non-positive
+// values keep PublicFeedCacheSize.
+func WithPublicFeedCacheSize(size int) ServiceOption {
+	return func(s *Service) {
+		if size > 0 {
+			s.cacheSize = max(size, PublicFeedLimit)
+		}
+	}
 }
 
 // NewService creates a new feed service
-func NewService(registry *Registry) *Service {
-	return &Service{
-		registry: registry,
-		cache:    &publicFeedCache{},
+func NewService(registry *Registry, opts ...ServiceOption) *Service {
+	s := &Service{
+		registry:  registry,
+		cache:     &publicFeedCache{},
+		cacheTTL:  PublicFeedCacheTTL,
+		cacheSize: PublicFeedCacheSize,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // SetSource configures the service's feed reader.
@@ -156,12 +263,91 @@ func (s *Service) SetSource(source Source) {
 	log.Info().Msg("feed: source configured")
 }
 
+// SetDefaultLimit overrides FeedLimit as the page size used when a query
+// doesn't ask for one. Non-positive values restore FeedLimit.
+func (s *Service) SetDefaultLimit(limit int) {
+	s.defaultLimit = limit
+}
+
+// DefaultLimit returns the page size used when a query doesn't set one.
+func (s *Service) DefaultLimit() int {
+	if s.defaultLimit > 0 {
+		return s.defaultLimit
+	}
+	return FeedLimit
+}
+
 // SetModerationFilter configures the service to filter moderated content
 func (s *Service) SetModerationFilter(filter moderation.FilterSource) {
 	s.moderationFilter = filter
 	log.Info().Msg("feed: moderation filter configured")
 }
 
+// SetPinnedSource configures where featured records are read from.
+func (s *Service) SetPinnedSource(pinned PinnedSource) {
+	s.pinned = pinned
+}
+
+// WithFeatured returns items with up to MaxFeaturedItems pinned records
+// placed in front, marked Featured. Pinned records go through the same
+// moderation filter as the rest of the feed, and are dropped from items so
+// nothing appears twice. items itself is not modified, so it is safe to
+// pass the cached public feed.
+func (s *Service) WithFeatured(ctx context.Context, items []*FeedItem) []*FeedItem {
+	featured := s.featuredItems(ctx)
+	if len(featured) == 0 {
+		return items
+	}
+
+	seen := make(map[string]bool, len(featured))
+	for _, item := range featured {
+		seen[item.SubjectURI] = true
+	}
+	merged := make([]*FeedItem, 0, len(featured)+len(items))
+	merged = append(merged, featured...)
+	for _, item := range items {
+		if !seen[item.SubjectURI] {
+			merged = append(merged, item)
+		}
+	}
+	return merged
+}
+
+// featuredItems loads the pinned records, newest pin first. Failures are
+// logged and treated as nothing being pinned; the feed itself still renders.
+func (s *Service) featuredItems(ctx context.Context) []*FeedItem {
+	lookup, ok := s.source.(ItemLookup)
+	if !ok || s.pinned == nil || !s.source.IsReady() {
+		return nil
+	}
+	pinned, err := s.pinned.ListPinned(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("feed: failed to list pinned records")
+		return nil
+	}
+	if len(pinned) == 0 {
+		return nil
+	}
+
+	uris := make([]string, len(pinned))
+	for i, p := range pinned {
+		uris[i] = p.ATURI
+	}
+	items, err := lookup.GetFeedItemsByURI(ctx, uris)
+	if err != nil {
+		log.Warn().Err(err).Msg("feed: failed to load pinned records")
+		return nil
+	}
+	items = s.filterModeratedItems(ctx, items)
+	if len(items) > MaxFeaturedItems {
+		items = items[:MaxFeaturedItems]
+	}
+	for _, item := range items {
+		item.Featured = true
+	}
+	return items
+}
+
 // filterModeratedItems removes hidden records and content from blacklisted users.
 // It loads the full blacklist and hidden URI sets upfront (2 queries total)
 // rather than checking each item individually (which would be 2N queries).
@@ -199,19 +385,53 @@ func (s *Service) getAuthorDID(item *FeedItem) string {
 	return ""
 }
 
-// InvalidatePublicFeedCache clears the cached public feed so the next request
-// re-queries the firehose index. Call this after any record is created, updated,
-// or deleted so unauthenticated users don't see stale content.
+// InvalidatePublicFeedCache clears the cached public feed and its rendered
+// HTML so the next request re-queries the firehose index. Call this after any
+// record is created, updated, deleted or moderated so unauthenticated users
+// don't see stale content.
 func (s *Service) InvalidatePublicFeedCache() {
 	s.cache.mu.Lock()
 	s.cache.items = nil
 	s.cache.expiresAt = time.Time{}
+	s.cache.html = nil
+	s.cache.htmlExpiresAt = time.Time{}
+	s.cache.generation++
 	s.cache.mu.Unlock()
 }
 
+// CachedPublicFeedHTML returns the rendered public feed partial if one is
+// cached and fresh. The generation is returned even on a miss; pass it to
+// StorePublicFeedHTML after rendering so an invalidation that lands mid-render
+// is not overwritten with stale HTML.
+func (s *Service) CachedPublicFeedHTML() (html []byte, generation uint64, ok bool) {
+	s.cache.mu.RLock()
+	defer s.cache.mu.RUnlock()
+	if s.cache.html != nil && time.Now().Before(s.cache.htmlExpiresAt) {
+		metrics.FeedHTMLCacheHitsTotal.Inc()
+		return s.cache.html, s.cache.generation, true
+	}
+	metrics.FeedHTMLCacheMissesTotal.Inc()
+	return nil, s.cache.generation, false
+}
+
+// StorePublicFeedHTML caches a rendered public feed partial for
+// PublicFeedHTMLCacheTTL. It is a no-op if the cache was invalidated since
+// generation was read.
+func (s *Service) StorePublicFeedHTML(generation uint64, html []byte) {
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+	if generation != s.cache.generation {
+		return
+	}
+	s.cache.html = html
+	s.cache.htmlExpiresAt = time.Now().Add(PublicFeedHTMLCacheTTL)
+}
+
 // GetCachedPublicFeed returns cached feed items for unauthenticated users.
 // It returns up to PublicFeedLimit items from the cache, refreshing if expired.
-// The cache stores PublicFeedCacheSize items internally but only returns PublicFeedLimit.
+// The cache stores the configured cache size internally but only returns
+// PublicFeedLimit. Refreshes go through GetRecentRecords, so the cache works
+// with whichever Source is configured.
 // Moderated content is filtered even from cached items to ensure hidden content
 // doesn't appear if it was hidden after caching.
 func (s *Service) GetCachedPublicFeed(ctx context.Context) ([]*FeedItem, error) {
@@ -245,8 +465,9 @@ func (s *Service) refreshPublicFeedCache(ctx context.Context) ([]*FeedItem, erro
 
 	// Double-check if another goroutine already refreshed the cache
 	if time.Now().Before(s.cache.expiresAt) && len(s.cache.items) > 0 {
+		metrics.FeedCacheHitsTotal.Inc()
 		// Return only the first PublicFeedLimit items
-		items := s.cache.items
+		items := s.filterModeratedItems(ctx, s.cache.items)
 		if len(items) > PublicFeedLimit {
 			items = items[:PublicFeedLimit]
 		}
@@ -256,13 +477,14 @@ func (s *Service) refreshPublicFeedCache(ctx context.Context) ([]*FeedItem, erro
 	metrics.FeedCacheMissesTotal.Inc()
 	log.Debug().Msg("feed: refreshing public feed cache")
 
-	// Fetch PublicFeedCacheSize items to cache (20 items)
-	items, err := s.GetRecentRecords(ctx, PublicFeedCacheSize)
+	items, err := s.GetRecentRecords(ctx, s.cacheSize)
 	if err != nil {
-		// If we have stale data, return it rather than failing
-		if len(s.cache.items) > 0 {
+		// If we have stale data, return it rather than failing. A cancelled
+		// request has nobody to serve, so it just reports the cancellation.
+		if len(s.cache.items) > 0 && ctx.Err() == nil {
 			log.Warn().Err(err).Msg("feed: failed to refresh cache, returning stale data")
-			cachedItems := s.cache.items
+			// Stale items predate any blocks or hides since the last refresh.
+			cachedItems := s.filterModeratedItems(ctx, s.cache.items)
 			if len(cachedItems) > PublicFeedLimit {
 				cachedItems = cachedItems[:PublicFeedLimit]
 			}
@@ -273,7 +495,7 @@ func (s *Service) refreshPublicFeedCache(ctx context.Context) ([]*FeedItem, erro
 
 	// Update cache with all fetched items
 	s.cache.items = items
-	s.cache.expiresAt = time.Now().Add(PublicFeedCacheTTL)
+	s.cache.expiresAt = time.Now().Add(s.cacheTTL)
 
 	log.Debug().
 		Int("cached_count", len(items)).
@@ -290,13 +512,21 @@ func (s *Service) refreshPublicFeedCache(ctx context.Context) ([]*FeedItem, erro
 }
 
 // GetRecentRecords fetches recent activity (brews and other records) from firehose index
-// Returns up to `limit` items sorted by most recent first
+// Returns up to `limit` items sorted by most recent first; a non-positive
+// limit means DefaultLimit
 // Moderated content (hidden records, blacklisted users) is filtered out
+// A cancelled ctx aborts the index scan and its error is returned as-is.
 func (s *Service) GetRecentRecords(ctx context.Context, limit int) ([]*FeedItem, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if s.source == nil || !s.source.IsReady() {
 		log.Warn().Msg("feed: firehose index not ready")
 		return nil, fmt.Errorf("firehose index not ready")
 	}
+	if limit <= 0 {
+		limit = s.DefaultLimit()
+	}
 
 	log.Debug().Msg("feed: using firehose index")
 
@@ -325,12 +555,15 @@ func (s *Service) GetRecentRecords(ctx context.Context, limit int) ([]*FeedItem,
 
 // GetFeedWithQuery fetches feed items with filtering, sorting, and pagination
 func (s *Service) GetFeedWithQuery(ctx context.Context, q FeedQuery) (*FeedResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if s.source == nil || !s.source.IsReady() {
 		return nil, fmt.Errorf("firehose index not ready")
 	}
 
 	if q.Limit <= 0 {
-		q.Limit = FeedLimit
+		q.Limit = s.DefaultLimit()
 	}
 	if q.Sort == "" {
 		q.Sort = FeedSortRecent
@@ -348,6 +581,7 @@ func (s *Service) GetFeedWithQuery(ctx context.Context, q FeedQuery) (*FeedResul
 		TypeFilter:  q.TypeFilter,
 		TypeFilters: q.TypeFilters,
 		Sort:        q.Sort,
+		FollowerDID: q.FollowerDID,
 	})
 	if err != nil {
 		return nil, err
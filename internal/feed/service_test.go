@@ -0,0 +1,389 @@
+package feed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/atproto"
+	"tangled.org/arabica.social/arabica/internal/moderation"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatCursorRoundTrip(t *testing.T) {
+	ts := time.Date(2025, 3, 14, 9, 26, 53, 589793000, time.UTC)
+	uri := "at://did:plc:abc123/social.arabica.alpha.brew/xyz"
+
+	createdAt, gotURI, err := ParseCursor(FormatCursor(ts, uri))
+	assert.NoError(t, err)
+	assert.Equal(t, ts.Format(time.RFC3339Nano), createdAt)
+	assert.Equal(t, uri, gotURI)
+}
+
+func TestParseCursor_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "empty", cursor: ""},
+		{name: "missing separator", cursor: "2025-03-14T09:26:53Z"},
+		{name: "bad timestamp", cursor: "yesterday|at://did:plc:abc/social.arabica.alpha.brew/xyz"},
+		{name: "not an AT URI", cursor: "2025-03-14T09:26:53Z|https://example.com/brew"},
+		{name: "empty uri", cursor: "2025-03-14T09:26:53Z|"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseCursor(tt.cursor)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
+
+type fakeFilterSource struct {
+	hidden      []string
+	blacklisted []string
+}
+
+func (f fakeFilterSource) ListHiddenURIs(context.Context) ([]string, error) {
+	return f.hidden, nil
+}
+
+func (f fakeFilterSource) ListBlacklistedDIDs(context.Context) ([]string, error) {
+	return f.blacklisted, nil
+}
+
+func TestGetCachedPublicFeedFiltersModeratedItems(t *testing.T) {
+	item := func(uri, did string) *FeedItem {
+		return &FeedItem{SubjectURI: uri, Author: &atproto.Profile{DID: did}}
+	}
+	cached := []*FeedItem{
+		item("at://did:plc:alice/social.arabica.alpha.brew/1", "did:plc:alice"),
+		item("at://did:plc:spammer/social.arabica.alpha.brew/2", "did:plc:spammer"),
+		item("at://did:plc:bob/social.arabica.alpha.brew/3", "did:plc:bob"),
+	}
+	filter := fakeFilterSource{
+		hidden:      []string{"at://did:plc:bob/social.arabica.alpha.brew/3"},
+		blacklisted: []string{"did:plc:spammer"},
+	}
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+	}{
+		{"fresh cache", time.Now().Add(time.Minute)},
+		// No source is configured, so the refresh fails and stale items are served.
+		{"stale fallback", time.Now().Add(-time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(NewRegistry())
+			svc.SetModerationFilter(filter)
+			svc.cache.items = cached
+			svc.cache.expiresAt = tt.expiresAt
+
+			items, err := svc.GetCachedPublicFeed(context.Background())
+			require.NoError(t, err)
+			require.Len(t, items, 1)
+			assert.Equal(t, "did:plc:alice", items[0].Author.DID)
+		})
+	}
+}
+
+// blockingSource stands in for a slow index: every read waits until the
+// caller's context is done.
+type blockingSource struct{}
+
+func (blockingSource) IsReady() bool { return true }
+
+func (blockingSource) GetRecentFeed(ctx context.Context, _ int) ([]*FeedItem, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (blockingSource) GetFeedWithQuery(ctx context.Context, _ FeedQuery) (*FeedResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestServiceHonorsCancelledContext(t *testing.T) {
+	svc := NewService(NewRegistry())
+	svc.SetSource(blockingSource{})
+	// Stale items must not mask the cancellation.
+	svc.cache.items = []*FeedItem{{SubjectURI: "at://did:plc:alice/social.arabica.alpha.brew/1"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"GetRecentRecords", func() error { _, err := svc.GetRecentRecords(ctx, 10); return err }},
+		{"GetFeedWithQuery", func() error { _, err := svc.GetFeedWithQuery(ctx, FeedQuery{}); return err }},
+		{"GetCachedPublicFeed", func() error { _, err := svc.GetCachedPublicFeed(ctx); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done := make(chan error, 1)
+			go func() { done <- tt.call() }()
+			select {
+			case err := <-done:
+				assert.ErrorIs(t, err, context.Canceled)
+			case <-time.After(time.Second):
+				t.Fatal("call did not return after cancellation")
+			}
+		})
+	}
+}
+
+func TestPublicFeedHTMLCache(t *testing.T) {
+	t.Run("stores and serves", func(t *testing.T) {
+		svc := NewService(NewRegistry())
+		_, gen, ok := svc.CachedPublicFeedHTML()
+		require.False(t, ok)
+
+		svc.StorePublicFeedHTML(gen, []byte("<div>feed</div>"))
+		html, _, ok := svc.CachedPublicFeedHTML()
+		require.True(t, ok)
+		assert.Equal(t, "<div>feed</div>", string(html))
+	})
+
+	t.Run("invalidation clears html", func(t *testing.T) {
+		svc := NewService(NewRegistry())
+		_, gen, _ := svc.CachedPublicFeedHTML()
+		svc.StorePublicFeedHTML(gen, []byte("<div>feed</div>"))
+
+		svc.InvalidatePublicFeedCache()
+		_, _, ok := svc.CachedPublicFeedHTML()
+		assert.False(t, ok)
+	})
+
+	t.Run("render racing an invalidation is dropped", func(t *testing.T) {
+		svc := NewService(NewRegistry())
+		_, gen, _ := svc.CachedPublicFeedHTML()
+		svc.InvalidatePublicFeedCache()
+
+		svc.StorePublicFeedHTML(gen, []byte("<div>stale</div>"))
+		_, _, ok := svc.CachedPublicFeedHTML()
+		assert.False(t, ok)
+	})
+
+	t.Run("expires without invalidation", func(t *testing.T) {
+		svc := NewService(NewRegistry())
+		_, gen, _ := svc.CachedPublicFeedHTML()
+		svc.StorePublicFeedHTML(gen, []byte("<div>feed</div>"))
+		svc.cache.htmlExpiresAt = time.Now().Add(-time.Second)
+
+		_, _, ok := svc.CachedPublicFeedHTML()
+		assert.False(t, ok)
+	})
+}
+
+// limitRecordingSource records the limit of each read it serves.
+type limitRecordingSource struct{ limits []int }
+
+func (s *limitRecordingSource) IsReady() bool { return true }
+
+func (s *limitRecordingSource) GetRecentFeed(_ context.Context, limit int) ([]*FeedItem, error) {
+	s.limits = append(s.limits, limit)
+	return nil, nil
+}
+
+func (s *limitRecordingSource) GetFeedWithQuery(_ context.Context, q FeedQuery) (*FeedResult, error) {
+	s.limits = append(s.limits, q.Limit)
+	return &FeedResult{}, nil
+}
+
+func TestServiceDefaultLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure int
+		want      int
+	}{
+		{"unset", 0, FeedLimit},
+		{"configured", 50, 50},
+		{"negative restores default", -5, FeedLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &limitRecordingSource{}
+			svc := NewService(NewRegistry())
+			svc.SetSource(src)
+			svc.SetDefaultLimit(tt.configure)
+			assert.Equal(t, tt.want, svc.DefaultLimit())
+
+			ctx := context.Background()
+			_, err := svc.GetFeedWithQuery(ctx, FeedQuery{})
+			require.NoError(t, err)
+			_, err = svc.GetRecentRecords(ctx, 0)
+			require.NoError(t, err)
+			_, err = svc.GetFeedWithQuery(ctx, FeedQuery{Limit: 7})
+			require.NoError(t, err)
+			assert.Equal(t, []int{tt.want, tt.want, 7}, src.limits)
+		})
+	}
+}
+
+// oneItemSource serves a single item and records the limit of each read.
+type oneItemSource struct{ limits []int }
+
+func (s *oneItemSource) IsReady() bool { return true }
+
+func (s *oneItemSource) GetRecentFeed(_ context.Context, limit int) ([]*FeedItem, error) {
+	s.limits = append(s.limits, limit)
+	return []*FeedItem{{}}, nil
+}
+
+func (s *oneItemSource) GetFeedWithQuery(_ context.Context, q FeedQuery) (*FeedResult, error) {
+	items, err := s.GetRecentFeed(context.Background(), q.Limit)
+	return &FeedResult{Items: items}, err
+}
+
+func TestPublicFeedCacheOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []ServiceOption
+		wantSize int
+		wantTTL  time.Duration
+	}{
+		{"defaults", nil, PublicFeedCacheSize, PublicFeedCacheTTL},
+		{"configured", []ServiceOption{WithPublicFeedCacheSize(50), WithPublicFeedCacheTTL(time.Minute)}, 50, time.Minute},
+		{"size below public limit", []ServiceOption{WithPublicFeedCacheSize(3)}, PublicFeedLimit, PublicFeedCacheTTL},
+		{"non-positive keeps defaults", []ServiceOption{WithPublicFeedCacheSize(-1), WithPublicFeedCacheTTL(0)}, PublicFeedCacheSize, PublicFeedCacheTTL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &oneItemSource{}
+			svc := NewService(NewRegistry(), tt.opts...)
+			svc.SetSource(src)
+			ctx := context.Background()
+
+			before := time.Now()
+			_, err := svc.GetCachedPublicFeed(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []int{tt.wantSize}, src.limits)
+			assert.WithinDuration(t, before.Add(tt.wantTTL), svc.cache.expiresAt, time.Second)
+
+			// Served from the cache until the TTL passes.
+			_, err = svc.GetCachedPublicFeed(ctx)
+			require.NoError(t, err)
+			assert.Len(t, src.limits, 1)
+
+			svc.cache.expiresAt = time.Now().Add(-time.Second)
+			_, err = svc.GetCachedPublicFeed(ctx)
+			require.NoError(t, err)
+			assert.Len(t, src.limits, 2)
+		})
+	}
+}
+
+// lookupSource serves a fixed set of items by URI, in request order.
+type lookupSource struct {
+	blockingSource
+	byURI map[string]*FeedItem
+}
+
+func (s lookupSource) GetFeedItemsByURI(_ context.Context, uris []string) ([]*FeedItem, error) {
+	var items []*FeedItem
+	for _, uri := range uris {
+		if item, ok := s.byURI[uri]; ok {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+type fakePinnedSource []string
+
+func (f fakePinnedSource) ListPinned(context.Context) ([]moderation.PinnedRecord, error) {
+	records := make([]moderation.PinnedRecord, len(f))
+	for i, uri := range f {
+		records[i] = moderation.PinnedRecord{ATURI: uri}
+	}
+	return records, nil
+}
+
+func TestWithFeatured(t *testing.T) {
+	item := func(did, rkey string) *FeedItem {
+		return &FeedItem{
+			SubjectURI: "at://" + did + "/social.arabica.alpha.brew/" + rkey,
+			Author:     &atproto.Profile{DID: did},
+		}
+	}
+	winner := item("did:plc:alice", "winner")
+	hidden := item("did:plc:bob", "hidden")
+	spam := item("did:plc:spammer", "spam")
+	recent := item("did:plc:carol", "recent")
+	source := lookupSource{byURI: map[string]*FeedItem{
+		winner.SubjectURI: winner,
+		hidden.SubjectURI: hidden,
+		spam.SubjectURI:   spam,
+	}}
+	filter := fakeFilterSource{
+		hidden:      []string{hidden.SubjectURI},
+		blacklisted: []string{"did:plc:spammer"},
+	}
+
+	tests := []struct {
+		name     string
+		pinned   fakePinnedSource
+		items    []*FeedItem
+		want     []string
+		featured int
+	}{
+		{
+			name:  "nothing pinned",
+			items: []*FeedItem{recent},
+			want:  []string{recent.SubjectURI},
+		},
+		{
+			name:     "pinned goes first",
+			pinned:   fakePinnedSource{winner.SubjectURI},
+			items:    []*FeedItem{recent},
+			want:     []string{winner.SubjectURI, recent.SubjectURI},
+			featured: 1,
+		},
+		{
+			name:     "pinned item also in recent feed appears once",
+			pinned:   fakePinnedSource{winner.SubjectURI},
+			items:    []*FeedItem{{SubjectURI: winner.SubjectURI, Author: winner.Author}, recent},
+			want:     []string{winner.SubjectURI, recent.SubjectURI},
+			featured: 1,
+		},
+		{
+			name:     "hidden and blacklisted pins are dropped",
+			pinned:   fakePinnedSource{hidden.SubjectURI, spam.SubjectURI, winner.SubjectURI},
+			items:    []*FeedItem{recent},
+			want:     []string{winner.SubjectURI, recent.SubjectURI},
+			featured: 1,
+		},
+		{
+			name:   "unindexed pin is skipped",
+			pinned: fakePinnedSource{"at://did:plc:gone/social.arabica.alpha.brew/x"},
+			items:  []*FeedItem{recent},
+			want:   []string{recent.SubjectURI},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(NewRegistry())
+			svc.SetSource(source)
+			svc.SetModerationFilter(filter)
+			if tt.pinned != nil {
+				svc.SetPinnedSource(tt.pinned)
+			}
+
+			got := svc.WithFeatured(context.Background(), tt.items)
+			uris := make([]string, len(got))
+			for i, item := range got {
+				uris[i] = item.SubjectURI
+				assert.Equal(t, i < tt.featured, item.Featured, item.SubjectURI)
+			}
+			assert.Equal(t, tt.want, uris)
+			assert.False(t, recent.Featured, "recent items are never marked")
+		})
+	}
+}
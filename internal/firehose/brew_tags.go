@@ -0,0 +1,106 @@
+package firehose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/brewtags"
+	"tangled.org/arabica.social/arabica/internal/feed"
+	"tangled.org/arabica.social/arabica/internal/lexicons"
+	"tangled.org/pdewey.com/atp"
+
+	"github.com/rs/zerolog/log"
+)
+
+// MaxTagResults caps GetBrewsByTag regardless of the requested limit.
+const MaxTagResults = 50
+
+// indexBrewTags refreshes the tag rows for a brew written through any of
+// the upsert paths. Other collections are ignored, and failures are only
+// logged since the record itself is already stored.
+func (idx *FeedIndex) indexBrewTags(ctx context.Context, did, collection, rkey string, record json.RawMessage) {
+	if collection != idx.recordTypeToNSID[lexicons.RecordTypeBrew] {
+		return
+	}
+	uri := atp.BuildATURI(did, collection, rkey)
+	if err := idx.reindexBrewTags(ctx, uri, did, record); err != nil {
+		log.Warn().Err(err).Str("uri", uri).Msg("failed to index brew tags")
+	}
+}
+
+// reindexBrewTags replaces the brew_tags rows for uri with the tags in
+// record, normalized so records written by other clients land in the same
+// buckets as ours. Drafts get no rows, so they stay off tag pages until
+// published. Deletes need no counterpart: the rows cascade with the
+// records row.
+func (idx *FeedIndex) reindexBrewTags(ctx context.Context, uri, did string, record json.RawMessage) error {
+	var fields struct {
+		Tags      []string `json:"tags"`
+		Draft     bool     `json:"draft"`
+		CreatedAt string   `json:"createdAt"`
+	}
+	if err := json.Unmarshal(record, &fields); err != nil {
+		return fmt.Errorf("decode brew tags: %w", err)
+	}
+	createdAt := time.Now().UTC()
+	if t, err := time.Parse(time.RFC3339, fields.CreatedAt); err == nil {
+		createdAt = t.UTC()
+	}
+
+	tx, err := idx.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM brew_tags WHERE uri = ?`, uri); err != nil {
+		return err
+	}
+	if fields.Draft {
+		return tx.Commit()
+	}
+	// Tags past brewtags.Max came from a client that ignored the schema.
+	tags := brewtags.NormalizeAll(fields.Tags)
+	for _, tag := range tags[:min(len(tags), brewtags.Max)] {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT OR IGNORE INTO brew_tags (uri, did, tag, created_at) VALUES (?, ?, ?, ?)`,
+			uri, did, tag, createdAt.Format(time.RFC3339Nano)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetBrewsByTag returns brews carrying tag, newest first, with hidden records
+// and blacklisted authors left out.
+func (idx *FeedIndex) GetBrewsByTag(ctx context.Context, tag string, limit int) ([]*feed.FeedItem, error) {
+	tag = brewtags.Normalize(tag)
+	brewNSID := idx.recordTypeToNSID[lexicons.RecordTypeBrew]
+	if tag == "" || brewNSID == "" {
+		return nil, nil
+	}
+	if limit <= 0 || limit > MaxTagResults {
+		limit = MaxTagResults
+	}
+
+	rows, err := idx.db.QueryContext(ctx, `
+		SELECT r.uri, r.did, r.collection, r.rkey, r.record, r.cid, r.indexed_at, r.created_at
+		FROM brew_tags t JOIN records r ON r.uri = t.uri
+		WHERE t.tag = ? AND r.collection = ?
+		AND r.uri NOT IN (SELECT uri FROM moderation_hidden_records)
+		AND r.did NOT IN (SELECT did FROM moderation_blacklist)
+		ORDER BY t.created_at DESC LIMIT ?`,
+		tag, brewNSID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records, refURIs, err := scanFeedRecords(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	return idx.buildFeedItems(ctx, records, refURIs)
+}
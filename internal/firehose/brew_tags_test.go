@@ -0,0 +1,155 @@
+package firehose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/atproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBrewsByTag(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	n := 0
+	upsert := func(did, collection, rkey, fields string) string {
+		n++
+		createdAt := now.Add(time.Duration(n) * time.Minute).UTC().Format(time.RFC3339)
+		record := fmt.Appendf(nil, `{"$type":%q,%s,"createdAt":%q}`, collection, fields, createdAt)
+		require.NoError(t, idx.UpsertRecord(ctx, did, collection, rkey, "cid-"+rkey, record, time.Now().Unix()))
+		return "at://" + did + "/" + collection + "/" + rkey
+	}
+
+	bean := upsert("did:plc:alice", "social.arabica.alpha.bean", "b1", `"name":"House","roastLevel":"Medium"`)
+	older := upsert("did:plc:alice", "social.arabica.alpha.brew", "w1", fmt.Sprintf(`"beanRef":%q,"tags":["washed","morning"]`, bean))
+	newer := upsert("did:plc:bob", "social.arabica.alpha.brew", "w2", fmt.Sprintf(`"beanRef":%q,"tags":[" #Washed "]`, bean))
+	retagged := upsert("did:plc:bob", "social.arabica.alpha.brew", "w3", fmt.Sprintf(`"beanRef":%q,"tags":["washed"]`, bean))
+	hidden := upsert("did:plc:alice", "social.arabica.alpha.brew", "w4", fmt.Sprintf(`"beanRef":%q,"tags":["washed"]`, bean))
+
+	// An edit that drops the tag takes the brew off the tag page.
+	upsert("did:plc:bob", "social.arabica.alpha.brew", "w3", fmt.Sprintf(`"beanRef":%q,"tags":["natural"]`, bean))
+	_, err := idx.DB().ExecContext(ctx, `INSERT INTO moderation_hidden_records (uri, hidden_at, hidden_by) VALUES (?, ?, 'mod')`, hidden, now.Format(time.RFC3339))
+	require.NoError(t, err)
+
+	items, err := idx.GetBrewsByTag(ctx, "WASHED", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{newer, older}, feedItemURIs(items))
+
+	items, err = idx.GetBrewsByTag(ctx, "natural", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{retagged}, feedItemURIs(items))
+
+	require.NoError(t, idx.DeleteRecord(ctx, "did:plc:alice", "social.arabica.alpha.brew", "w1"))
+	items, err = idx.GetBrewsByTag(ctx, "morning", 0)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestDraftBrewsStayOutOfPublicQueries(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	const (
+		did        = "did:plc:alice"
+		collection = "social.arabica.alpha.brew"
+	)
+	uri := "at://" + did + "/" + collection + "/d1"
+	bean := "at://" + did + "/social.arabica.alpha.bean/b1"
+	require.NoError(t, idx.UpsertRecord(ctx, did, "social.arabica.alpha.bean", "b1", "cid-b1",
+		[]byte(`{"$type":"social.arabica.alpha.bean","name":"House","createdAt":"2026-01-01T00:00:00Z"}`), time.Now().Unix()))
+	upsert := func(draft bool) {
+		record := fmt.Appendf(nil, `{"$type":%q,"beanRef":%q,"tags":["washed"],"tastingNotes":"jammy","rating":8,"draft":%t,"createdAt":%q}`,
+			collection, bean, draft, time.Now().UTC().Format(time.RFC3339))
+		require.NoError(t, idx.UpsertRecord(ctx, did, collection, "d1", "cid-d1", record, time.Now().Unix()))
+	}
+
+	upsert(true)
+	rec, err := idx.GetRecord(ctx, uri)
+	require.NoError(t, err)
+	assert.NotNil(t, rec, "drafts stay indexed for the owner's own reads")
+
+	feedItems, err := idx.GetRecentFeed(ctx, 10)
+	require.NoError(t, err)
+	assert.NotContains(t, feedItemURIs(feedItems), uri)
+	tagItems, err := idx.GetBrewsByTag(ctx, "washed", 0)
+	require.NoError(t, err)
+	assert.Empty(t, tagItems)
+	searchItems, err := idx.SearchRecords(ctx, "jammy", 0)
+	require.NoError(t, err)
+	assert.Empty(t, searchItems)
+	pinned, err := idx.GetFeedItemsByURI(ctx, []string{uri})
+	require.NoError(t, err)
+	assert.Empty(t, pinned)
+	usedBy, err := idx.ListUsageBacklinks(ctx, bean, collection, "beanRef")
+	require.NoError(t, err)
+	assert.Empty(t, usedBy)
+	keys, err := idx.ListRecordKeys(ctx, collection, 10)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+	assert.NotContains(t, idx.BrewCountsByBeanURI(ctx, ""), bean)
+	assert.NotContains(t, idx.AvgBrewRatingByBeanURI(ctx, ""), bean)
+	published, err := idx.ListPublishedWitnessRecords(ctx, did, collection, 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, published)
+	count, err := idx.CountPublishedWitnessRecords(ctx, did, collection)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	count, err = idx.CountWitnessRecords(ctx, did, collection)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "the owner's own count includes drafts")
+
+	// Publishing rewrites the record without the flag.
+	upsert(false)
+	feedItems, err = idx.GetRecentFeed(ctx, 10)
+	require.NoError(t, err)
+	assert.Contains(t, feedItemURIs(feedItems), uri)
+	tagItems, err = idx.GetBrewsByTag(ctx, "washed", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{uri}, feedItemURIs(tagItems))
+	assert.Equal(t, 1, idx.BrewCountsByBeanURI(ctx, "")[bean])
+	count, err = idx.CountPublishedWitnessRecords(ctx, did, collection)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestWitnessWritesIndexBrewTags(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	const (
+		did        = "did:plc:alice"
+		collection = "social.arabica.alpha.brew"
+	)
+	bean := "at://" + did + "/social.arabica.alpha.bean/b1"
+	require.NoError(t, idx.UpsertRecord(ctx, did, "social.arabica.alpha.bean", "b1", "cid-b1",
+		[]byte(`{"$type":"social.arabica.alpha.bean","name":"House","createdAt":"2026-01-01T00:00:00Z"}`), time.Now().Unix()))
+	brew := func(tags string) json.RawMessage {
+		return fmt.Appendf(nil, `{"$type":%q,"beanRef":%q,"tags":%s,"createdAt":"2026-01-01T00:00:00Z"}`, collection, bean, tags)
+	}
+
+	require.NoError(t, idx.UpsertWitnessRecordBatch(ctx, []atproto.WitnessWriteRecord{
+		{DID: did, Collection: collection, RKey: "w1", CID: "cid-w1", Record: brew(`["Washed"]`)},
+	}))
+	items, err := idx.GetBrewsByTag(ctx, "washed", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"at://" + did + "/" + collection + "/w1"}, feedItemURIs(items))
+
+	require.NoError(t, idx.UpdateWitnessRecord(ctx, did, collection, "w1", brew(`["natural"]`)))
+	items, err = idx.GetBrewsByTag(ctx, "washed", 0)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+	items, err = idx.GetBrewsByTag(ctx, "natural", 0)
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+
+	// An update for a record the index hasn't seen is a no-op.
+	require.NoError(t, idx.UpdateWitnessRecord(ctx, did, collection, "missing", brew(`["natural"]`)))
+	items, err = idx.GetBrewsByTag(ctx, "natural", 0)
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+}
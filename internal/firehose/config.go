@@ -33,18 +33,31 @@ type Config struct {
 
 	// ProfileCacheTTL is how long to cache profile data
 	ProfileCacheTTL int64 // seconds
+
+	// ProfileRefreshAhead is the fraction of ProfileCacheTTL before expiry
+	// during which a read also refreshes the profile in the background
+	ProfileRefreshAhead float64
+
+	// BackfillWorkers caps how many DIDs BackfillDIDs processes at once
+	BackfillWorkers int
 }
 
+// DefaultBackfillWorkers is the backfill concurrency used when
+// Config.BackfillWorkers is unset.
+const DefaultBackfillWorkers = 4
+
 // DefaultConfig returns a configuration with sensible defaults. Caller
 // must populate WantedCollections (typically from domain.App.NSIDs()) — a
 // nil default forces app-aware wiring at startup so the subscription tracks
 // the running app's entity set.
 func DefaultConfig() *Config {
 	return &Config{
-		Endpoints:         DefaultJetstreamEndpoints,
-		WantedCollections: nil,
-		Compress:          true, // atp/jetstream embeds the shared zstd dictionary
-		IndexPath:         "",   // Will be set based on data directory
-		ProfileCacheTTL:   3600, // 1 hour
+		Endpoints:           DefaultJetstreamEndpoints,
+		WantedCollections:   nil,
+		Compress:            true, // atp/jetstream embeds the shared zstd dictionary
+		IndexPath:           "",   // Will be set based on data directory
+		ProfileCacheTTL:     3600, // 1 hour
+		ProfileRefreshAhead: DefaultProfileRefreshAhead,
+		BackfillWorkers:     DefaultBackfillWorkers,
 	}
 }
@@ -3,8 +3,10 @@ package firehose
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"tangled.org/arabica.social/arabica/internal/metrics"
@@ -55,16 +57,32 @@ type JetstreamEvent struct {
 }
 
 // Consumer consumes events from Jetstream and indexes them. Connection
-// lifecycle, decompression, and cursor persistence are delegated to the
-// atp/jetstream package; this type owns the arabica-specific indexing
-// pipeline and metrics.
+// lifecycle and decompression are delegated to the atp/jetstream package;
+// this type owns the arabica-specific indexing pipeline, metrics, and the
+// cursor checkpoints a restart resumes from.
 type Consumer struct {
 	config    *Config
 	index     *FeedIndex
 	wantedSet map[string]struct{} // membership lookup over config.WantedCollections
 	upstream  *atpjetstream.Consumer
+	cursor    *cursorCheckpoint
+
+	backfillMu  sync.Mutex
+	backfilling map[string]struct{} // DIDs with a backfill in progress
+
+	reindex reindexJob
+
+	// onNewRecord runs after a feedable record created on the firehose is
+	// indexed.
+	onNewRecord func(uri, did, collection string, createdAt time.Time)
 }
 
+// ErrBackfillInProgress is returned by BackfillDID when another caller is
+// already backfilling the same DID.
+var ErrBackfillInProgress = errors.New("backfill already in progress")
+
+var _ atpjetstream.CursorStore = (*cursorCheckpoint)(nil)
+
 // NewConsumer creates a new Jetstream consumer
 func NewConsumer(config *Config, index *FeedIndex) *Consumer {
 	wantedSet := make(map[string]struct{}, len(config.WantedCollections))
@@ -73,20 +91,18 @@ func NewConsumer(config *Config, index *FeedIndex) *Consumer {
 	}
 
 	c := &Consumer{
-		config:    config,
-		index:     index,
-		wantedSet: wantedSet,
-	}
-
-	if cursor, err := index.GetCursor(context.Background()); err == nil && cursor > 0 {
-		log.Info().Int64("cursor", cursor).Msg("firehose: loaded cursor from index")
+		config:      config,
+		index:       index,
+		wantedSet:   wantedSet,
+		backfilling: make(map[string]struct{}),
+		cursor:      newCursorCheckpoint(index),
 	}
 
 	c.upstream = atpjetstream.New(&atpjetstream.Config{
 		Endpoints:         config.Endpoints,
 		WantedCollections: config.WantedCollections,
 		Compress:          config.Compress,
-		CursorStore:       index,
+		CursorStore:       c.cursor,
 		OnConnect: func() {
 			metrics.FirehoseConnectionState.Set(1)
 			log.Info().Str("endpoint", c.upstream.CurrentEndpoint()).Msg("firehose: connected to Jetstream")
@@ -105,14 +121,58 @@ func NewConsumer(config *Config, index *FeedIndex) *Consumer {
 	return c
 }
 
-// Start begins consuming events in a background goroutine
+// SetNewRecordHook registers fn to run for each feedable, non-draft record
+// created on the firehose, after it is indexed. Backfills, updates, and
+// deletes don't call it. fn runs on the consumer's goroutine, so it must
+// hand slow work off. Set it before Start.
+func (c *Consumer) SetNewRecordHook(fn func(uri, did, collection string, createdAt time.Time)) {
+	c.onNewRecord = fn
+}
+
+// Start begins consuming events in a background goroutine, resuming from
+// the cursor stored by the previous run when there is one.
 func (c *Consumer) Start(ctx context.Context) {
+	cursor, err := c.cursor.GetCursor(ctx)
+	switch {
+	case err != nil:
+		log.Warn().Err(err).Msg("firehose: failed to read stored cursor, starting from live")
+	case cursor > 0:
+		log.Info().Int64("cursor", cursor).Msg("firehose: resuming from stored cursor")
+	}
 	c.upstream.Start(ctx)
+	go c.flushCursorPeriodically(ctx)
 }
 
-// Stop gracefully stops the consumer
+// Stop gracefully stops the consumer and saves its cursor.
 func (c *Consumer) Stop() {
 	c.upstream.Stop()
+	if err := c.cursor.Flush(context.Background()); err != nil {
+		log.Warn().Err(err).Msg("firehose: failed to save cursor on stop")
+	}
+}
+
+// flushCursorPeriodically saves the cursor while the stream is quiet, when
+// no new event arrives to trigger a batch.
+func (c *Consumer) flushCursorPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(cursorFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.cursor.Flush(ctx); err != nil && ctx.Err() == nil {
+				log.Warn().Err(err).Msg("firehose: failed to save cursor")
+			}
+		}
+	}
+}
+
+// checkpoint marks the event at timeUS as processed.
+func (c *Consumer) checkpoint(timeUS int64) {
+	if err := c.cursor.SetCursor(context.Background(), timeUS); err != nil {
+		log.Warn().Err(err).Int64("cursor", timeUS).Msg("firehose: failed to save cursor")
+	}
 }
 
 // IsConnected returns true if currently connected to Jetstream
@@ -122,7 +182,11 @@ func (c *Consumer) IsConnected() bool {
 
 // handleEvent bridges atp/jetstream events into the arabica indexing pipeline.
 func (c *Consumer) handleEvent(_ context.Context, evt *atpjetstream.Event) error {
-	if evt == nil || evt.Kind != "commit" || evt.Commit == nil {
+	if evt == nil {
+		return nil
+	}
+	defer c.checkpoint(evt.TimeUS)
+	if evt.Kind != "commit" || evt.Commit == nil {
 		return nil
 	}
 	if !c.isWantedCollection(evt.Commit.Collection) {
@@ -157,6 +221,7 @@ func (c *Consumer) handleEvent(_ context.Context, evt *atpjetstream.Event) error
 // Exported for use in integration tests where events are fed from a test PDS
 // firehose rather than a live Jetstream connection.
 func (c *Consumer) ProcessEvent(event JetstreamEvent) error {
+	defer c.checkpoint(event.TimeUS)
 	if event.Kind != "commit" || event.Commit == nil {
 		return nil
 	}
@@ -186,6 +251,14 @@ func (c *Consumer) processCommit(event JetstreamEvent) error {
 		if commit.Record == nil {
 			return nil
 		}
+		uri := fmt.Sprintf("at://%s/%s/%s", event.DID, commit.Collection, commit.RKey)
+		// Publishing a draft is an update, so it counts as new too.
+		published := commit.Operation == "create"
+		if !published && c.onNewRecord != nil && !isDraftRecord(commit.Record) {
+			if prev, err := c.index.GetRecord(context.Background(), uri); err == nil && prev != nil {
+				published = isDraftRecord(prev.Record)
+			}
+		}
 		if err := c.index.UpsertRecord(
 			context.Background(),
 			event.DID,
@@ -197,6 +270,14 @@ func (c *Consumer) processCommit(event JetstreamEvent) error {
 		); err != nil {
 			return fmt.Errorf("failed to upsert record: %w", err)
 		}
+		if published && c.onNewRecord != nil &&
+			c.index.IsFeedableCollection(commit.Collection) && !isDraftRecord(commit.Record) {
+			createdAt := time.Now().UTC()
+			if event.TimeUS > 0 {
+				createdAt = time.UnixMicro(event.TimeUS).UTC()
+			}
+			c.onNewRecord(uri, event.DID, commit.Collection, createdAt)
+		}
 
 		// Special handling for likes - index for counts. Matches any
 		// app's like collection (arabica + oolong both use ".like" suffix).
@@ -215,6 +296,32 @@ func (c *Consumer) processCommit(event JetstreamEvent) error {
 			}
 		}
 
+		// Special handling for bookmarks - indexed for the owner's lookups only.
+		if strings.HasSuffix(commit.Collection, ".bookmark") {
+			var recordData map[string]any
+			if err := json.Unmarshal(commit.Record, &recordData); err == nil {
+				if subject, ok := recordData["subject"].(map[string]any); ok {
+					if subjectURI, ok := subject["uri"].(string); ok {
+						if err := c.index.UpsertBookmark(context.Background(), event.DID, commit.RKey, subjectURI); err != nil {
+							log.Warn().Err(err).Str("did", event.DID).Str("subject", subjectURI).Msg("failed to index bookmark")
+						}
+					}
+				}
+			}
+		}
+
+		// Special handling for follows - index for the following feed.
+		if strings.HasSuffix(commit.Collection, ".follow") {
+			var recordData map[string]any
+			if err := json.Unmarshal(commit.Record, &recordData); err == nil {
+				if subjectDID, ok := recordData["subject"].(string); ok && subjectDID != "" {
+					if err := c.index.UpsertFollow(context.Background(), event.DID, commit.RKey, subjectDID); err != nil {
+						log.Warn().Err(err).Str("did", event.DID).Str("subject", subjectDID).Msg("failed to index follow")
+					}
+				}
+			}
+		}
+
 		// Special handling for comments - index for counts and retrieval.
 		// Matches any app's comment collection.
 		if strings.HasSuffix(commit.Collection, ".comment") {
@@ -238,7 +345,11 @@ func (c *Consumer) processCommit(event JetstreamEvent) error {
 						if parent, ok := recordData["parent"].(map[string]any); ok {
 							parentURI, _ = parent["uri"].(string)
 						}
-						if err := c.index.UpsertComment(context.Background(), event.DID, commit.RKey, subjectURI, parentURI, commit.CID, text, createdAt); err != nil {
+						var editedAt time.Time
+						if editedAtStr, ok := recordData["editedAt"].(string); ok {
+							editedAt, _ = time.Parse(time.RFC3339, editedAtStr)
+						}
+						if err := c.index.UpsertComment(context.Background(), event.DID, commit.RKey, subjectURI, parentURI, commit.CID, text, createdAt, editedAt); err != nil {
 							log.Warn().Err(err).Str("did", event.DID).Str("subject", subjectURI).Msg("failed to index comment")
 						}
 						// Create notification for the comment
@@ -270,6 +381,18 @@ func (c *Consumer) processCommit(event JetstreamEvent) error {
 			}
 		}
 
+		// Bookmarks and follows are keyed by rkey in the index, so no lookup is needed
+		if strings.HasSuffix(commit.Collection, ".bookmark") {
+			if err := c.index.DeleteBookmark(context.Background(), event.DID, commit.RKey); err != nil {
+				log.Warn().Err(err).Str("did", event.DID).Str("rkey", commit.RKey).Msg("failed to delete bookmark index")
+			}
+		}
+		if strings.HasSuffix(commit.Collection, ".follow") {
+			if err := c.index.DeleteFollow(context.Background(), event.DID, commit.RKey); err != nil {
+				log.Warn().Err(err).Str("did", event.DID).Str("rkey", commit.RKey).Msg("failed to delete follow index")
+			}
+		}
+
 		// Special handling for comments - need to look up subject URI before delete
 		if strings.HasSuffix(commit.Collection, ".comment") {
 			// Try to get the existing record to find its subject
@@ -295,11 +418,11 @@ func (c *Consumer) processCommit(event JetstreamEvent) error {
 			}
 		}
 
-		if err := c.index.DeleteRecord(
+		// Cascade so likes and comments on the deleted record (including
+		// likes on a deleted comment) stop counting toward anything.
+		if err := c.index.DeleteRecordCascade(
 			context.Background(),
-			event.DID,
-			commit.Collection,
-			commit.RKey,
+			fmt.Sprintf("at://%s/%s/%s", event.DID, commit.Collection, commit.RKey),
 		); err != nil {
 			return fmt.Errorf("failed to delete record: %w", err)
 		}
@@ -310,10 +433,79 @@ func (c *Consumer) processCommit(event JetstreamEvent) error {
 
 // BackfillDID backfills records for a specific DID using the consumer's
 // configured WantedCollections (which come from app.NSIDs() at startup).
+// A call for a DID that is already being backfilled returns
+// ErrBackfillInProgress immediately, so the startup pool and login-triggered
+// backfills never race on the same DID's IsBackfilled/MarkBackfilled pair.
 func (c *Consumer) BackfillDID(ctx context.Context, did string) error {
+	if !c.claimBackfill(did) {
+		return ErrBackfillInProgress
+	}
+	defer c.releaseBackfill(did)
 	return c.index.BackfillUser(ctx, did, c.config.WantedCollections)
 }
 
+// claimBackfill marks did as being backfilled, reporting false if it
+// already was.
+func (c *Consumer) claimBackfill(did string) bool {
+	c.backfillMu.Lock()
+	defer c.backfillMu.Unlock()
+	if _, busy := c.backfilling[did]; busy {
+		return false
+	}
+	c.backfilling[did] = struct{}{}
+	return true
+}
+
+func (c *Consumer) releaseBackfill(did string) {
+	c.backfillMu.Lock()
+	delete(c.backfilling, did)
+	c.backfillMu.Unlock()
+}
+
+// BackfillDIDs backfills many DIDs using at most workers goroutines. A
+// non-positive workers falls back to Config.BackfillWorkers, then to
+// DefaultBackfillWorkers. DIDs another caller was already backfilling are
+// counted in inProgress rather than succeeded. Failures are logged per DID
+// and never stop the remaining DIDs from being processed.
+func (c *Consumer) BackfillDIDs(ctx context.Context, dids []string, workers int) (succeeded, inProgress, failed int) {
+	if workers <= 0 {
+		workers = c.config.BackfillWorkers
+	}
+	if workers <= 0 {
+		workers = DefaultBackfillWorkers
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	for _, did := range dids {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return succeeded, inProgress, len(dids) - succeeded - inProgress
+		}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			err := c.BackfillDID(ctx, did)
+			mu.Lock()
+			defer mu.Unlock()
+			if errors.Is(err, ErrBackfillInProgress) {
+				inProgress++
+				return
+			}
+			if err != nil {
+				log.Warn().Err(err).Str("did", did).Msg("Failed to backfill user")
+				failed++
+				return
+			}
+			succeeded++
+		})
+	}
+	wg.Wait()
+	return succeeded, inProgress, failed
+}
+
 // BackfilledDIDs returns the set of all DIDs that have been backfilled.
 func (c *Consumer) BackfilledDIDs(ctx context.Context) (map[string]struct{}, error) {
 	return c.index.BackfilledDIDs(ctx)
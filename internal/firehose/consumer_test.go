@@ -0,0 +1,87 @@
+package firehose
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRecordHook(t *testing.T) {
+	consumer := NewConsumer(DefaultConfig(), newTestIndex(t))
+	var got []string
+	var gotAt time.Time
+	consumer.SetNewRecordHook(func(uri, did, collection string, createdAt time.Time) {
+		got = append(got, uri)
+		gotAt = createdAt
+	})
+
+	const at = int64(1767225600000000) // 2026-01-01T00:00:00Z
+	commit := func(op, collection, rkey, record string) JetstreamEvent {
+		return JetstreamEvent{DID: "did:plc:alice", TimeUS: at, Kind: "commit", Commit: &JetstreamCommit{
+			Operation: op, Collection: collection, RKey: rkey, CID: "cid-" + rkey, Record: []byte(record),
+		}}
+	}
+	bean := `{"$type":"` + arabica.NSIDBean + `","name":"Ardi","createdAt":"2026-01-01T00:00:00Z"}`
+
+	for _, event := range []JetstreamEvent{
+		commit("create", arabica.NSIDBean, "b1", bean),
+		commit("update", arabica.NSIDBean, "b1", bean),
+		commit("create", arabica.NSIDLike, "l1", `{"$type":"`+arabica.NSIDLike+`","subject":{"uri":"at://did:plc:alice/`+arabica.NSIDBean+`/b1"},"createdAt":"2026-01-01T00:00:00Z"}`),
+		commit("create", arabica.NSIDBrew, "draft", `{"$type":"`+arabica.NSIDBrew+`","draft":true,"createdAt":"2026-01-01T00:00:00Z"}`),
+		commit("update", arabica.NSIDBrew, "draft", `{"$type":"`+arabica.NSIDBrew+`","draft":true,"rating":7,"createdAt":"2026-01-01T00:00:00Z"}`),
+		commit("update", arabica.NSIDBrew, "draft", `{"$type":"`+arabica.NSIDBrew+`","rating":7,"createdAt":"2026-01-01T00:00:00Z"}`),
+		commit("update", arabica.NSIDBrew, "draft", `{"$type":"`+arabica.NSIDBrew+`","rating":8,"createdAt":"2026-01-01T00:00:00Z"}`),
+	} {
+		require.NoError(t, consumer.ProcessEvent(event))
+	}
+
+	assert.Equal(t, []string{
+		"at://did:plc:alice/" + arabica.NSIDBean + "/b1",
+		"at://did:plc:alice/" + arabica.NSIDBrew + "/draft",
+	}, got, "only new, feedable, published records and published drafts are reported")
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), gotAt)
+}
+
+func TestConsumerResumesFromLastProcessedEvent(t *testing.T) {
+	path := t.TempDir() + "/test.db"
+	ctx := context.Background()
+	const first = int64(1767225600000000)
+
+	idx, err := NewFeedIndex(path, time.Hour)
+	require.NoError(t, err)
+	consumer := NewConsumer(DefaultConfig(), idx)
+
+	total := cursorFlushEvents + cursorFlushEvents/2
+	for i := range total {
+		rkey := fmt.Sprintf("b%d", i)
+		require.NoError(t, consumer.ProcessEvent(JetstreamEvent{
+			DID: "did:plc:alice", TimeUS: first + int64(i), Kind: "commit",
+			Commit: &JetstreamCommit{
+				Operation: "create", Collection: arabica.NSIDBean, RKey: rkey, CID: "cid-" + rkey,
+				Record: []byte(`{"$type":"` + arabica.NSIDBean + `","name":"Ardi","createdAt":"2026-01-01T00:00:00Z"}`),
+			},
+		}))
+	}
+
+	stored, err := idx.GetCursor(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first+int64(cursorFlushEvents-1), stored, "cursor is written in batches, not per event")
+
+	consumer.Stop()
+	require.NoError(t, idx.Close())
+
+	reopened, err := NewFeedIndex(path, time.Hour)
+	require.NoError(t, err)
+	defer reopened.Close()
+	restarted := NewConsumer(DefaultConfig(), reopened)
+
+	resume, err := restarted.cursor.GetCursor(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first+int64(total-1), resume, "a restart resumes at the last processed event")
+}
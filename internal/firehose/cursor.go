@@ -0,0 +1,80 @@
+package firehose
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// The consumer persists its cursor after this many processed events or this
+// much time, whichever comes first. A restart replays at most that much of
+// the stream, and upserts are idempotent, so the replay is harmless.
+const (
+	cursorFlushEvents   = 100
+	cursorFlushInterval = 5 * time.Second
+)
+
+// cursorCheckpoint tracks the time_us of the newest event the consumer has
+// processed and writes it to the index in batches rather than once per
+// event. It is also the CursorStore handed to atp/jetstream, so a
+// (re)connect resumes from the newest processed event, falling back to the
+// cursor a previous process stored.
+type cursorCheckpoint struct {
+	index *FeedIndex
+
+	mu        sync.Mutex
+	latest    int64
+	pending   int
+	flushedAt time.Time
+}
+
+func newCursorCheckpoint(index *FeedIndex) *cursorCheckpoint {
+	return &cursorCheckpoint{index: index, flushedAt: time.Now()}
+}
+
+// GetCursor returns the cursor to resume from.
+func (cp *cursorCheckpoint) GetCursor(ctx context.Context) (int64, error) {
+	cp.mu.Lock()
+	latest := cp.latest
+	cp.mu.Unlock()
+	if latest > 0 {
+		return latest, nil
+	}
+	return cp.index.GetCursor(ctx)
+}
+
+// SetCursor records cursor as processed. It only reaches the index once a
+// batch is due.
+func (cp *cursorCheckpoint) SetCursor(ctx context.Context, cursor int64) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	if cursor <= cp.latest {
+		return nil
+	}
+	cp.latest = cursor
+	cp.pending++
+	if cp.pending < cursorFlushEvents && time.Since(cp.flushedAt) < cursorFlushInterval {
+		return nil
+	}
+	return cp.flushLocked(ctx)
+}
+
+// Flush writes any unsaved cursor to the index.
+func (cp *cursorCheckpoint) Flush(ctx context.Context) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.flushLocked(ctx)
+}
+
+func (cp *cursorCheckpoint) flushLocked(ctx context.Context) error {
+	if cp.pending == 0 {
+		return nil
+	}
+	if err := cp.index.SetCursor(ctx, cp.latest); err != nil {
+		return fmt.Errorf("store firehose cursor: %w", err)
+	}
+	cp.pending = 0
+	cp.flushedAt = time.Now()
+	return nil
+}
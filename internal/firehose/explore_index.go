@@ -161,7 +161,7 @@ func (idx *FeedIndex) reindexExploreRecord(ctx context.Context, uri string) erro
 	rec.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAtStr)
 	reg := explore.NewArabicaRegistry(idx.recordTypeToNSID)
 	typ, ok := reg.TypeByNSID(rec.Collection)
-	if !ok {
+	if !ok || isDraftRecord(rec.Record) {
 		_, _ = idx.db.ExecContext(ctx, `DELETE FROM explore_values WHERE uri=?`, uri)
 		_, _ = idx.db.ExecContext(ctx, `DELETE FROM explore_documents WHERE uri=?`, uri)
 		return nil
@@ -255,7 +255,7 @@ func (idx *FeedIndex) exploreCommunityRating(ctx context.Context, recordType lex
 func (idx *FeedIndex) exploreAverageBrewRating(ctx context.Context, brewNSID, refField, refURI string) (any, int) {
 	var avg sql.NullFloat64
 	var count int
-	_ = idx.db.QueryRowContext(ctx, `SELECT AVG(CAST(json_extract(record, '$.rating') AS REAL)), COUNT(*) FROM records WHERE collection = ? AND json_extract(record, '$.`+refField+`') = ? AND json_type(record, '$.rating') IS NOT NULL`, brewNSID, refURI).Scan(&avg, &count)
+	_ = idx.db.QueryRowContext(ctx, `SELECT AVG(CAST(json_extract(record, '$.rating') AS REAL)), COUNT(*) FROM records WHERE collection = ? AND json_extract(record, '$.`+refField+`') = ? AND json_type(record, '$.rating') IS NOT NULL AND `+notDraftSQL, brewNSID, refURI).Scan(&avg, &count)
 	if !avg.Valid || count == 0 {
 		return nil, 0
 	}
@@ -270,7 +270,7 @@ func (idx *FeedIndex) exploreRoasterCommunityRating(ctx context.Context, roaster
 	}
 	var avg sql.NullFloat64
 	var count int
-	_ = idx.db.QueryRowContext(ctx, `SELECT AVG(CAST(json_extract(brew.record, '$.rating') AS REAL)), COUNT(*) FROM records brew WHERE brew.collection = ? AND json_type(brew.record, '$.rating') IS NOT NULL AND json_extract(brew.record, '$.beanRef') IN (SELECT bean.uri FROM records bean WHERE bean.collection = ? AND json_extract(bean.record, '$.roasterRef') = ?)`, brewNSID, beanNSID, roasterURI).Scan(&avg, &count)
+	_ = idx.db.QueryRowContext(ctx, `SELECT AVG(CAST(json_extract(brew.record, '$.rating') AS REAL)), COUNT(*) FROM records brew WHERE brew.collection = ? AND json_type(brew.record, '$.rating') IS NOT NULL AND `+notDraftSQLOn("brew")+` AND json_extract(brew.record, '$.beanRef') IN (SELECT bean.uri FROM records bean WHERE bean.collection = ? AND json_extract(bean.record, '$.roasterRef') = ?)`, brewNSID, beanNSID, roasterURI).Scan(&avg, &count)
 	if !avg.Valid || count == 0 {
 		return nil, 0
 	}
@@ -131,7 +131,7 @@ func TestExplorePopularAndSocialStatsRefresh(t *testing.T) {
 	second := upsertExploreRecord(t, idx, "did:plc:two", arabica.NSIDBean, "b2", map[string]any{"$type": arabica.NSIDBean, "name": "Social"}, 2)
 
 	require.NoError(t, idx.UpsertLike(ctx, "did:plc:fan", "l1", second))
-	require.NoError(t, idx.UpsertComment(ctx, "did:plc:fan", "c1", second, "", "cid", "nice", time.Now()))
+	require.NoError(t, idx.UpsertComment(ctx, "did:plc:fan", "c1", second, "", "cid", "nice", time.Now(), time.Time{}))
 	res, err := idx.GetExplore(ctx, ExploreQuery{App: "arabica", Type: lexicons.RecordTypeBean, Sort: explore.SortPopular})
 	require.NoError(t, err)
 	require.Len(t, res.Items, 2)
@@ -232,7 +232,7 @@ func TestExploreDeleteAllByDIDRefreshesExternalSocialStats(t *testing.T) {
 	ctx := context.Background()
 	target := upsertExploreRecord(t, idx, "did:plc:target", arabica.NSIDBean, "b1", map[string]any{"$type": arabica.NSIDBean, "name": "Target"}, 1)
 	require.NoError(t, idx.UpsertLike(ctx, "did:plc:deleted", "l1", target))
-	require.NoError(t, idx.UpsertComment(ctx, "did:plc:deleted", "c1", target, "", "cid", "nice", time.Now()))
+	require.NoError(t, idx.UpsertComment(ctx, "did:plc:deleted", "c1", target, "", "cid", "nice", time.Now(), time.Time{}))
 	require.NoError(t, idx.DeleteAllByDID(ctx, "did:plc:deleted"))
 
 	res, err := idx.GetExplore(ctx, ExploreQuery{App: "arabica", Type: lexicons.RecordTypeBean})
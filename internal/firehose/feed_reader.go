@@ -2,7 +2,9 @@ package firehose
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -12,13 +14,91 @@ import (
 	"tangled.org/arabica.social/arabica/internal/entities"
 	"tangled.org/arabica.social/arabica/internal/feed"
 	"tangled.org/arabica.social/arabica/internal/lexicons"
+	"tangled.org/arabica.social/arabica/internal/metrics"
 
 	"github.com/rs/zerolog/log"
 )
 
+// notDraftSQL keeps drafts out of public queries over the records table.
+// Only brews can be drafts today, but the flag is checked on any record so
+// other types can adopt it without touching the queries. json_extract gives
+// 1 for true and NULL when the field is absent.
+const notDraftSQL = `json_extract(record, '$.draft') IS NOT 1`
+
+// notDraftSQLOn is notDraftSQL for queries that join records to itself and
+// refer to the side being filtered as table.
+func notDraftSQLOn(table string) string {
+	return `json_extract(` + table + `.record, '$.draft') IS NOT 1`
+}
+
+// isDraftRecord reports whether a raw record carries draft: true.
+func isDraftRecord(record json.RawMessage) bool {
+	var fields struct {
+		Draft bool `json:"draft"`
+	}
+	return json.Unmarshal(record, &fields) == nil && fields.Draft
+}
+
 // GetRecentFeed returns recent feed items from the index
 func (idx *FeedIndex) GetRecentFeed(ctx context.Context, limit int) ([]*feed.FeedItem, error) {
-	return idx.getFeedItems(ctx, nil, limit, "")
+	return idx.getFeedItems(ctx, nil, limit, "", time.Time{}, "")
+}
+
+// GetFeedItemsByURI hydrates the given records into feed items, in the
+// order the URIs were passed. URIs that aren't indexed, that are drafts, or
+// that belong to collections outside this app's feed, are skipped.
+func (idx *FeedIndex) GetFeedItemsByURI(ctx context.Context, uris []string) ([]*feed.FeedItem, error) {
+	byURI := idx.GetRecordsBatch(ctx, uris)
+	records := make([]*IndexedRecord, 0, len(byURI))
+	refURIs := make(map[string]bool)
+	for _, uri := range uris {
+		rec := byURI[uri]
+		if rec == nil || isDraftRecord(rec.Record) {
+			continue
+		}
+		records = append(records, rec)
+		var recordData map[string]any
+		if err := json.Unmarshal(rec.Record, &recordData); err == nil {
+			collectRecordRefs(refURIs, rec.Collection, recordData)
+		}
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return idx.buildFeedItems(ctx, records, refURIs)
+}
+
+// GetFollowingFeed returns recent records authored by accounts followerDID
+// follows, paginated with the same cursor format as GetFeedWithQuery.
+func (idx *FeedIndex) GetFollowingFeed(ctx context.Context, followerDID string, limit int, cursor string) (*feed.FeedResult, error) {
+	if followerDID == "" {
+		return nil, fmt.Errorf("follower DID is required")
+	}
+	return idx.GetFeedWithQuery(ctx, feed.FeedQuery{
+		Limit:       limit,
+		Cursor:      cursor,
+		FollowerDID: followerDID,
+	})
+}
+
+// HasFeedableRecords reports whether the index holds any feedable record by
+// did. It only sees what the firehose or a backfill has indexed, so false
+// means "unknown here", not "has no records".
+func (idx *FeedIndex) HasFeedableRecords(ctx context.Context, did string) (bool, error) {
+	if len(idx.feedableCollections) == 0 {
+		return false, nil
+	}
+	args := []any{did}
+	placeholders := make([]string, len(idx.feedableCollections))
+	for i, c := range idx.feedableCollections {
+		placeholders[i] = "?"
+		args = append(args, c)
+	}
+	var exists bool
+	err := idx.db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM records WHERE did = ? AND collection IN (`+strings.Join(placeholders, ",")+`))`,
+		args...).Scan(&exists)
+	return exists, err
 }
 
 func feedableCollectionsForDescriptors(descriptors []*entities.Descriptor) (map[lexicons.RecordType]string, []string) {
@@ -42,6 +122,10 @@ func (idx *FeedIndex) GetFeedWithQuery(ctx context.Context, q feed.FeedQuery) (*
 	if q.Sort == "" {
 		q.Sort = feed.FeedSortRecent
 	}
+	start := time.Now()
+	defer func() {
+		metrics.FeedQueryDuration.WithLabelValues(string(q.Sort)).Observe(time.Since(start).Seconds())
+	}()
 
 	// Determine collection filters
 	var collectionFilters []string
@@ -61,13 +145,16 @@ func (idx *FeedIndex) GetFeedWithQuery(ctx context.Context, q feed.FeedQuery) (*
 		collectionFilters = []string{nsid}
 	}
 
-	// For popular sort, fetch more candidates to re-rank by score
+	// For popular sort, fetch more candidates from the recent window to
+	// re-rank by score
 	fetchLimit := q.Limit + 1
+	var since time.Time
 	if q.Sort == feed.FeedSortPopular {
 		fetchLimit = q.Limit * 5
+		since = time.Now().Add(-feed.PopularFeedWindow)
 	}
 
-	items, err := idx.getFeedItems(ctx, collectionFilters, fetchLimit, q.Cursor)
+	items, err := idx.getFeedItems(ctx, collectionFilters, fetchLimit, q.Cursor, since, q.FollowerDID)
 	if err != nil {
 		return nil, err
 	}
@@ -87,14 +174,16 @@ func (idx *FeedIndex) GetFeedWithQuery(ctx context.Context, q feed.FeedQuery) (*
 	if len(items) > q.Limit {
 		result.Items = items[:q.Limit]
 		last := result.Items[q.Limit-1]
-		result.NextCursor = last.Timestamp.Format(time.RFC3339Nano) + "|" + last.SubjectURI
+		result.NextCursor = feed.FormatCursor(last.Timestamp, last.SubjectURI)
 	}
 
 	return result, nil
 }
 
 // getFeedItems fetches records from SQLite, resolves references, and returns FeedItems.
-func (idx *FeedIndex) getFeedItems(ctx context.Context, collectionFilters []string, limit int, cursor string) ([]*feed.FeedItem, error) {
+// A non-zero since excludes records created before it, and a non-empty
+// followerDID keeps only records by accounts that DID follows.
+func (idx *FeedIndex) getFeedItems(ctx context.Context, collectionFilters []string, limit int, cursor string, since time.Time, followerDID string) ([]*feed.FeedItem, error) {
 	// Build query for feedable records
 	var args []any
 	query := `SELECT uri, did, collection, rkey, record, cid, indexed_at, created_at FROM records WHERE `
@@ -121,14 +210,30 @@ func (idx *FeedIndex) getFeedItems(ctx context.Context, collectionFilters []stri
 		}
 		query += `collection IN (` + strings.Join(placeholders, ",") + `) `
 	}
+	query += `AND ` + notDraftSQL + ` `
 
 	// Cursor-based pagination: cursor format is "created_at|uri"
 	if cursor != "" {
-		parts := strings.SplitN(cursor, "|", 2)
-		if len(parts) == 2 {
-			query += `AND (created_at < ? OR (created_at = ? AND uri < ?)) `
-			args = append(args, parts[0], parts[0], parts[1])
+		createdAt, uri, err := feed.ParseCursor(cursor)
+		if err != nil {
+			return nil, err
 		}
+		query += `AND (created_at < ? OR (created_at = ? AND uri < ?)) `
+		args = append(args, createdAt, createdAt, uri)
+	}
+
+	if !since.IsZero() {
+		// created_at holds RFC3339Nano text, whose variable-length fraction
+		// sorts "05.5Z" before "05Z", so compare parsed times. The prefix
+		// bound is a coarse filter that still lets SQLite use the index.
+		since = since.UTC()
+		query += `AND created_at >= ? AND julianday(created_at) >= julianday(?) `
+		args = append(args, since.Format("2006-01-02T15:04:05"), since.Format(time.RFC3339Nano))
+	}
+
+	if followerDID != "" {
+		query += `AND did IN (SELECT subject_did FROM follows WHERE follower_did = ?) `
+		args = append(args, followerDID)
 	}
 
 	query += `ORDER BY created_at DESC LIMIT ?`
@@ -140,10 +245,25 @@ func (idx *FeedIndex) getFeedItems(ctx context.Context, collectionFilters []stri
 	}
 	defer rows.Close()
 
+	records, refURIs, err := scanFeedRecords(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	return idx.buildFeedItems(ctx, records, refURIs)
+}
+
+// scanFeedRecords reads rows of (uri, did, collection, rkey, record, cid,
+// indexed_at, created_at) and collects the reference URIs each record
+// points at so they can be fetched in one pass. It stops early with the
+// context's error once ctx is cancelled.
+func scanFeedRecords(ctx context.Context, rows *sql.Rows) ([]*IndexedRecord, map[string]bool, error) {
 	var records []*IndexedRecord
 	refURIs := make(map[string]bool) // URIs we need to resolve
 
 	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
 		var rec IndexedRecord
 		var recordStr, indexedAtStr, createdAtStr string
 		if err := rows.Scan(&rec.URI, &rec.DID, &rec.Collection, &rec.RKey,
@@ -161,9 +281,16 @@ func (idx *FeedIndex) getFeedItems(ctx context.Context, collectionFilters []stri
 		}
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	return records, refURIs, nil
+}
 
+// buildFeedItems hydrates scanned records into feed items, resolving
+// references, like and comment counts, and author profiles in batches.
+// The batch lookups swallow their own errors, so ctx is checked between
+// stages to avoid hydrating a page nobody is waiting for.
+func (idx *FeedIndex) buildFeedItems(ctx context.Context, records []*IndexedRecord, refURIs map[string]bool) ([]*feed.FeedItem, error) {
 	// Build lookup map starting with the fetched records
 	recordsByURI := make(map[string]*IndexedRecord, len(records))
 	for _, r := range records {
@@ -171,28 +298,29 @@ func (idx *FeedIndex) getFeedItems(ctx context.Context, collectionFilters []stri
 	}
 
 	idx.fetchReferenceRecords(ctx, recordsByURI, refURIs)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Batch-fetch social data for all records
 	recordURIs := make([]string, 0, len(records))
-	didSet := make(map[string]struct{}, len(records))
+	authorDIDs := make([]string, 0, len(records))
 	for _, r := range records {
 		recordURIs = append(recordURIs, r.URI)
-		didSet[r.DID] = struct{}{}
+		authorDIDs = append(authorDIDs, r.DID)
 	}
 	likeCounts := idx.GetLikeCountsBatch(ctx, recordURIs)
 	commentCounts := idx.GetCommentCountsBatch(ctx, recordURIs)
 
-	// Pre-warm profile cache for all unique DIDs
-	profiles := make(map[string]*atproto.Profile, len(didSet))
-	for did := range didSet {
-		if p, err := idx.GetProfile(ctx, did); err == nil {
-			profiles[did] = p
-		}
-	}
+	// Resolve every author in one batch before building items
+	profiles := idx.GetProfiles(ctx, authorDIDs)
 
 	// Convert to FeedItems
 	items := make([]*feed.FeedItem, 0, len(records))
 	for _, record := range records {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		item, err := idx.recordToFeedItem(ctx, record, recordsByURI, profiles)
 		if err != nil {
 			log.Warn().Err(err).Str("uri", record.URI).Msg("failed to convert record to feed item")
@@ -229,7 +357,9 @@ func (idx *FeedIndex) recordToFeedItem(ctx context.Context, record *IndexedRecor
 		var err error
 		profile, err = idx.GetProfile(ctx, record.DID)
 		if err != nil {
-			log.Warn().Err(err).Str("did", record.DID).Msg("failed to get profile")
+			if !errors.Is(err, ErrProfileUnresolved) {
+				log.Warn().Err(err).Str("did", record.DID).Msg("failed to get profile")
+			}
 			profile = &atproto.Profile{
 				DID:    record.DID,
 				Handle: record.DID,
@@ -295,7 +425,7 @@ func collectRecordRefs(refURIs map[string]bool, collection string, recordData ma
 
 func (idx *FeedIndex) fetchReferenceRecords(ctx context.Context, recordsByURI map[string]*IndexedRecord, refURIs map[string]bool) {
 	attempted := make(map[string]bool)
-	for {
+	for ctx.Err() == nil {
 		missingURIs := make([]string, 0, len(refURIs))
 		for uri := range refURIs {
 			if _, found := recordsByURI[uri]; found || attempted[uri] {
@@ -0,0 +1,203 @@
+package firehose
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/feed"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func upsertTestRoaster(t *testing.T, idx *FeedIndex, rkey string, createdAt time.Time) string {
+	t.Helper()
+	did := "did:plc:roaster"
+	collection := "social.arabica.alpha.roaster"
+	record := fmt.Appendf(nil, `{"$type":%q,"name":%q,"createdAt":%q}`, collection, rkey, createdAt.UTC().Format(time.RFC3339))
+	assert.NoError(t, idx.UpsertRecord(context.Background(), did, collection, rkey, "cid-"+rkey, record, time.Now().Unix()))
+	return "at://" + did + "/" + collection + "/" + rkey
+}
+
+func TestGetFeedWithQuery_PopularExcludesOldRecords(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+
+	recentURI := upsertTestRoaster(t, idx, "recent", time.Now().Add(-24*time.Hour))
+	oldURI := upsertTestRoaster(t, idx, "old", time.Now().Add(-feed.PopularFeedWindow-24*time.Hour))
+
+	recent, err := idx.GetFeedWithQuery(ctx, feed.FeedQuery{Limit: 10, Sort: feed.FeedSortRecent})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{recentURI, oldURI}, feedItemURIs(recent.Items))
+
+	popular, err := idx.GetFeedWithQuery(ctx, feed.FeedQuery{Limit: 10, Sort: feed.FeedSortPopular})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{recentURI}, feedItemURIs(popular.Items))
+}
+
+func TestGetFeedItems_SinceComparesFractionalSeconds(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	collection := "social.arabica.alpha.roaster"
+	since := time.Date(2026, 1, 1, 12, 0, 5, 0, time.UTC)
+
+	for rkey, createdAt := range map[string]time.Time{
+		"before": since.Add(-500 * time.Millisecond),
+		"after":  since.Add(500 * time.Millisecond),
+	} {
+		record := fmt.Appendf(nil, `{"$type":%q,"name":%q,"createdAt":%q}`, collection, rkey, createdAt.Format(time.RFC3339Nano))
+		assert.NoError(t, idx.UpsertRecord(ctx, "did:plc:roaster", collection, rkey, "cid-"+rkey, record, time.Now().Unix()))
+	}
+
+	items, err := idx.getFeedItems(ctx, []string{collection}, 10, "", since, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"at://did:plc:roaster/" + collection + "/after"}, feedItemURIs(items))
+}
+
+func TestGetFeedWithQuery_InvalidCursor(t *testing.T) {
+	idx := newTestIndex(t)
+
+	_, err := idx.GetFeedWithQuery(context.Background(), feed.FeedQuery{Limit: 10, Cursor: "not-a-cursor"})
+	assert.ErrorIs(t, err, feed.ErrInvalidCursor)
+}
+
+func TestGetRecentFeed_CancelledContext(t *testing.T) {
+	idx := newTestIndex(t)
+	for i := range 50 {
+		upsertTestRoaster(t, idx, fmt.Sprintf("r%d", i), time.Now().Add(-time.Duration(i)*time.Minute))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	items, err := idx.GetRecentFeed(ctx, 50)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, items)
+}
+
+func TestFeedChangeHook(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	calls := 0
+	idx.SetFeedChangeHook(func() { calls++ })
+
+	upsertTestRoaster(t, idx, "r1", time.Now())
+	assert.Equal(t, 1, calls, "feedable upsert should fire the hook")
+
+	like := []byte(`{"$type":"social.arabica.alpha.like","subject":{"uri":"at://did:plc:roaster/social.arabica.alpha.roaster/r1","cid":"cid-r1"},"createdAt":"2026-05-01T00:00:00Z"}`)
+	assert.NoError(t, idx.UpsertRecord(ctx, "did:plc:fan", "social.arabica.alpha.like", "l1", "cid-l1", like, time.Now().Unix()))
+	assert.Equal(t, 1, calls, "likes are not feed items")
+
+	assert.NoError(t, idx.DeleteRecord(ctx, "did:plc:roaster", "social.arabica.alpha.roaster", "r1"))
+	assert.Equal(t, 2, calls, "feedable delete should fire the hook")
+}
+
+func TestGetFollowingFeed(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	collection := "social.arabica.alpha.roaster"
+	upsert := func(did, rkey string, createdAt time.Time) string {
+		record := fmt.Appendf(nil, `{"$type":%q,"name":%q,"createdAt":%q}`, collection, rkey, createdAt.UTC().Format(time.RFC3339))
+		assert.NoError(t, idx.UpsertRecord(ctx, did, collection, rkey, "cid-"+rkey, record, time.Now().Unix()))
+		return "at://" + did + "/" + collection + "/" + rkey
+	}
+
+	now := time.Now()
+	aliceNew := upsert("did:plc:alice", "a2", now.Add(-time.Hour))
+	aliceOld := upsert("did:plc:alice", "a1", now.Add(-2*time.Hour))
+	upsert("did:plc:bob", "b1", now.Add(-30*time.Minute))
+	upsert("did:plc:viewer", "v1", now)
+
+	empty, err := idx.GetFollowingFeed(ctx, "did:plc:viewer", 10, "")
+	assert.NoError(t, err)
+	assert.Empty(t, empty.Items)
+
+	assert.NoError(t, idx.UpsertFollow(ctx, "did:plc:viewer", "f1", "did:plc:alice"))
+	assert.Equal(t, "f1", idx.GetFollowRKey(ctx, "did:plc:viewer", "did:plc:alice"))
+
+	page, err := idx.GetFollowingFeed(ctx, "did:plc:viewer", 1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{aliceNew}, feedItemURIs(page.Items))
+	assert.NotEmpty(t, page.NextCursor)
+
+	next, err := idx.GetFollowingFeed(ctx, "did:plc:viewer", 1, page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{aliceOld}, feedItemURIs(next.Items))
+
+	// Deleting by rkey is what the firehose delivers, and is idempotent.
+	assert.NoError(t, idx.DeleteFollow(ctx, "did:plc:viewer", "f1"))
+	assert.NoError(t, idx.DeleteFollow(ctx, "did:plc:viewer", "f1"))
+	assert.Empty(t, idx.GetFollowRKey(ctx, "did:plc:viewer", "did:plc:alice"))
+
+	after, err := idx.GetFollowingFeed(ctx, "did:plc:viewer", 10, "")
+	assert.NoError(t, err)
+	assert.Empty(t, after.Items)
+
+	_, err = idx.GetFollowingFeed(ctx, "", 10, "")
+	assert.Error(t, err)
+}
+
+func TestHasFeedableRecords(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	record := []byte(`{"$type":"social.arabica.alpha.roaster","name":"Sey","createdAt":"2026-01-01T00:00:00Z"}`)
+	require.NoError(t, idx.UpsertRecord(ctx, "did:plc:alice", "social.arabica.alpha.roaster", "r1", "cid-r1", record, time.Now().Unix()))
+	require.NoError(t, idx.UpsertRecord(ctx, "did:plc:bob", "app.bsky.feed.post", "p1", "cid-p1", []byte(`{"text":"hi"}`), time.Now().Unix()))
+
+	for did, want := range map[string]bool{
+		"did:plc:alice":  true,
+		"did:plc:bob":    false, // only records this app doesn't show
+		"did:plc:nobody": false,
+	} {
+		got, err := idx.HasFeedableRecords(ctx, did)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, did)
+	}
+}
+
+func feedItemURIs(items []*feed.FeedItem) []string {
+	uris := make([]string, 0, len(items))
+	for _, item := range items {
+		uris = append(uris, item.SubjectURI)
+	}
+	return uris
+}
+
+func TestGetFeedItemsByURI(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+
+	older := upsertTestRoaster(t, idx, "older", time.Now().Add(-2*time.Hour))
+	newer := upsertTestRoaster(t, idx, "newer", time.Now().Add(-time.Hour))
+	missing := "at://did:plc:roaster/social.arabica.alpha.roaster/missing"
+
+	items, err := idx.GetFeedItemsByURI(ctx, []string{older, missing, newer})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{older, newer}, feedItemURIs(items), "order follows the URIs, unknown ones are skipped")
+
+	items, err = idx.GetFeedItemsByURI(ctx, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestFormatTimeAgoAt(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2026, 5, day, hour, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name     string
+		then     time.Time
+		now      time.Time
+		expected string
+	}{
+		{"hours within a day", at(19, 22), at(20, 1), "3 hours ago"},
+		{"previous date", at(19, 0), at(20, 23), "yesterday"},
+		{"25 hours over two midnights", at(18, 23), at(20, 0), "2 days ago"},
+		{"weeks", at(5, 12), at(20, 12), "2 weeks ago"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatTimeAgoAt(tt.then, tt.now))
+		})
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"database/sql"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -16,6 +18,7 @@ import (
 	"tangled.org/arabica.social/arabica/internal/atproto"
 	"tangled.org/arabica.social/arabica/internal/entities"
 	"tangled.org/arabica.social/arabica/internal/lexicons"
+	"tangled.org/arabica.social/arabica/internal/metrics"
 	"tangled.org/arabica.social/arabica/internal/profileprefs"
 	"tangled.org/arabica.social/arabica/internal/tracing"
 	"tangled.org/pdewey.com/atp"
@@ -27,6 +30,7 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
 	_ "modernc.org/sqlite"
 )
 
@@ -72,10 +76,40 @@ type FeedIndex struct {
 	recordTypeToNSID    map[lexicons.RecordType]string
 	feedableCollections []string
 
+	// onFeedChange runs after a feedable record is upserted or deleted.
+	onFeedChange func()
+
 	// In-memory cache for hot data
 	profileCache   map[string]*CachedProfile
 	profileCacheMu sync.RWMutex
 
+	// profileRefreshAhead is how close to expiry a cached profile may get
+	// before a read also starts a background refresh. profileRefreshing
+	// (guarded by profileCacheMu) holds DIDs with a refresh in flight.
+	profileRefreshAhead time.Duration
+	profileRefreshing   map[string]struct{}
+	fetchProfile        func(ctx context.Context, did string) (*atproto.Profile, error)
+
+	// profileFailures (guarded by profileCacheMu) maps DIDs whose last
+	// fetch failed to when another fetch may be tried. It is kept apart
+	// from profileCache and never persisted, so a dead DID doesn't cost a
+	// network call on every render but can't shadow a real profile.
+	profileFailureTTL time.Duration
+	profileFailures   map[string]time.Time
+
+	// Per-URI cooldown for RefreshRecord, and the PDS fetch it uses
+	// (swapped out in tests).
+	refreshedAt     map[string]time.Time
+	refreshMu       sync.Mutex
+	getPublicRecord func(ctx context.Context, did, collection, rkey string) (*atp.Record, error)
+
+	// listAllRecords pages through a collection on a PDS for RebuildDID
+	// (swapped out in tests).
+	listAllRecords func(ctx context.Context, did, collection string) ([]atp.Record, error)
+
+	// activeRoasters caches the roaster discovery list.
+	activeRoasters activeRoastersCache
+
 	ready   bool
 	readyMu sync.RWMutex
 }
@@ -84,6 +118,40 @@ type FeedIndexOption func(*feedIndexConfig)
 
 type feedIndexConfig struct {
 	feedableDescriptors []*entities.Descriptor
+	profileRefreshAhead float64
+	profileFailureTTL   time.Duration
+}
+
+// DefaultProfileRefreshAhead is the share of the profile TTL, counted back
+// from expiry, during which reads refresh a profile in the background.
+const DefaultProfileRefreshAhead = 0.1
+
+// profileRefreshTimeout bounds a background refresh-ahead fetch.
+const profileRefreshTimeout = 15 * time.Second
+
+// WithProfileRefreshAhead sets the refresh-ahead window as a fraction of the
+// profile TTL: 0.1 refreshes during the last 10% of an entry's life. Zero
+// turns refresh-ahead off; values are clamped to [0, 1].
+func WithProfileRefreshAhead(fraction float64) FeedIndexOption {
+	return func(cfg *feedIndexConfig) {
+		cfg.profileRefreshAhead = min(max(fraction, 0), 1)
+	}
+}
+
+// DefaultProfileFailureTTL is how long a DID whose profile failed to
+// resolve is answered from the negative cache before it is fetched again.
+const DefaultProfileFailureTTL = 5 * time.Minute
+
+// ErrProfileUnresolved is returned by GetProfile, without a network call,
+// for a DID whose profile fetch failed within the last profile failure TTL.
+var ErrProfileUnresolved = errors.New("profile recently failed to resolve")
+
+// WithProfileFailureTTL sets how long a failed profile fetch is remembered.
+// Zero turns negative caching off.
+func WithProfileFailureTTL(ttl time.Duration) FeedIndexOption {
+	return func(cfg *feedIndexConfig) {
+		cfg.profileFailureTTL = max(ttl, 0)
+	}
 }
 
 // WithFeedableDescriptors configures which app-owned entity descriptors should
@@ -101,6 +169,26 @@ func (idx *FeedIndex) SetCommentNSID(nsid string) {
 	idx.commentNSID = nsid
 }
 
+// SetFeedChangeHook registers fn to run whenever a feedable record is
+// upserted or deleted, e.g. to drop cached public feed renders. Set it before
+// the firehose consumer starts; it is not safe to change concurrently.
+func (idx *FeedIndex) SetFeedChangeHook(fn func()) {
+	idx.onFeedChange = fn
+}
+
+// IsFeedableCollection reports whether collection's records appear in this
+// app's feeds.
+func (idx *FeedIndex) IsFeedableCollection(collection string) bool {
+	return slices.Contains(idx.feedableCollections, collection)
+}
+
+// notifyFeedChange calls the feed change hook if collection appears in feeds.
+func (idx *FeedIndex) notifyFeedChange(collection string) {
+	if idx.onFeedChange != nil && idx.IsFeedableCollection(collection) {
+		idx.onFeedChange()
+	}
+}
+
 func (idx *FeedIndex) commentCollection() string {
 	if idx.commentNSID != "" {
 		return idx.commentNSID
@@ -128,7 +216,11 @@ func NewFeedIndex(path string, profileTTL time.Duration, opts ...FeedIndexOption
 	if path == "" {
 		return nil, fmt.Errorf("index path is required")
 	}
-	cfg := feedIndexConfig{feedableDescriptors: entities.All()}
+	cfg := feedIndexConfig{
+		feedableDescriptors: entities.All(),
+		profileRefreshAhead: DefaultProfileRefreshAhead,
+		profileFailureTTL:   DefaultProfileFailureTTL,
+	}
 	for _, opt := range opts {
 		if opt != nil {
 			opt(&cfg)
@@ -179,12 +271,18 @@ func NewFeedIndex(path string, profileTTL time.Duration, opts ...FeedIndexOption
 		_ = db.Close()
 		return nil, fmt.Errorf("failed to initialize schema: %w", err)
 	}
-	if _, err := db.Exec(`ALTER TABLE user_settings ADD COLUMN preferences TEXT NOT NULL DEFAULT '{}'`); err != nil {
-		// Existing databases already have this column. SQLite reports that as an
-		// error, so only fail for genuinely unexpected migration problems.
-		if !strings.Contains(strings.ToLower(err.Error()), "duplicate column") {
-			_ = db.Close()
-			return nil, fmt.Errorf("failed to migrate user settings: %w", err)
+	for _, migration := range []struct{ table, stmt string }{
+		{"user settings", `ALTER TABLE user_settings ADD COLUMN preferences TEXT NOT NULL DEFAULT '{}'`},
+		{"comments", `ALTER TABLE comments ADD COLUMN edited_at TEXT NOT NULL DEFAULT ''`},
+		{"moderation reports", `ALTER TABLE moderation_reports ADD COLUMN category TEXT NOT NULL DEFAULT 'other'`},
+	} {
+		if _, err := db.Exec(migration.stmt); err != nil {
+			// Existing databases already have this column. SQLite reports that as an
+			// error, so only fail for genuinely unexpected migration problems.
+			if !strings.Contains(strings.ToLower(err.Error()), "duplicate column") {
+				_ = db.Close()
+				return nil, fmt.Errorf("failed to migrate %s: %w", migration.table, err)
+			}
 		}
 	}
 
@@ -199,7 +297,15 @@ func NewFeedIndex(path string, profileTTL time.Duration, opts ...FeedIndexOption
 		recordTypeToNSID:    recordTypeToNSID,
 		feedableCollections: feedableCollections,
 		profileCache:        make(map[string]*CachedProfile),
+		profileRefreshAhead: time.Duration(float64(profileTTL) * cfg.profileRefreshAhead),
+		profileRefreshing:   make(map[string]struct{}),
+		profileFailureTTL:   cfg.profileFailureTTL,
+		profileFailures:     make(map[string]time.Time),
+		refreshedAt:         make(map[string]time.Time),
 	}
+	idx.getPublicRecord = idx.publicClient.GetPublicRecord
+	idx.listAllRecords = idx.publicClient.ListAllRecords
+	idx.fetchProfile = idx.publicClient.GetProfile
 
 	// One-time backfill: populate did_by_handle from any pre-existing profile rows
 	// so handle resolution works for users observed before this table existed.
@@ -235,20 +341,31 @@ func (idx *FeedIndex) GetWitnessRecord(ctx context.Context, uri string) (*atprot
 // ListWitnessRecords returns all indexed records for a DID+collection pair,
 // ordered by created_at descending. Returns an empty slice when none are found.
 func (idx *FeedIndex) ListWitnessRecords(ctx context.Context, did, collection string) ([]*atproto.WitnessRecord, error) {
-	return idx.witness.list(ctx, did, collection, 0, 0)
+	return idx.witness.list(ctx, did, collection, 0, 0, true)
 }
 
 // ListWitnessRecordsPaginated returns a page of cached records for a
 // DID+collection pair, ordered by created_at descending.
 // When limit <= 0, returns all records.
 func (idx *FeedIndex) ListWitnessRecordsPaginated(ctx context.Context, did, collection string, offset, limit int) ([]*atproto.WitnessRecord, error) {
-	return idx.witness.list(ctx, did, collection, offset, limit)
+	return idx.witness.list(ctx, did, collection, offset, limit, true)
 }
 
 // CountWitnessRecords returns the total count of cached records for a
 // DID+collection pair.
 func (idx *FeedIndex) CountWitnessRecords(ctx context.Context, did, collection string) (int, error) {
-	return idx.witness.count(ctx, did, collection)
+	return idx.witness.count(ctx, did, collection, true)
+}
+
+// ListPublishedWitnessRecords is ListWitnessRecordsPaginated without
+// drafts, for showing a user's records to anyone else.
+func (idx *FeedIndex) ListPublishedWitnessRecords(ctx context.Context, did, collection string, offset, limit int) ([]*atproto.WitnessRecord, error) {
+	return idx.witness.list(ctx, did, collection, offset, limit, false)
+}
+
+// CountPublishedWitnessRecords is CountWitnessRecords without drafts.
+func (idx *FeedIndex) CountPublishedWitnessRecords(ctx context.Context, did, collection string) (int, error) {
+	return idx.witness.count(ctx, did, collection, false)
 }
 
 // Close closes the index database
@@ -292,6 +409,11 @@ func (idx *FeedIndex) SetCursor(ctx context.Context, cursor int64) error {
 // UpsertRecord adds or updates a record in the index.
 // The context is used for OTel tracing; pass context.Background() for background operations.
 func (idx *FeedIndex) UpsertRecord(ctx context.Context, did, collection, rkey, cid string, record json.RawMessage, eventTime int64) error {
+	start := time.Now()
+	defer func() {
+		metrics.IndexUpsertDuration.WithLabelValues(collection).Observe(time.Since(start).Seconds())
+	}()
+
 	err := idx.witness.upsert(ctx, did, collection, rkey, cid, record, eventTime)
 	if err != nil {
 		return err
@@ -315,6 +437,8 @@ func (idx *FeedIndex) UpsertRecord(ctx context.Context, did, collection, rkey, c
 			idx.markExploreDirty(ctx, err)
 		}
 	}
+	idx.indexBrewTags(ctx, did, collection, rkey, record)
+	idx.notifyFeedChange(collection)
 
 	return nil
 
@@ -331,26 +455,117 @@ func (idx *FeedIndex) DeleteRecord(ctx context.Context, did, collection, rkey st
 
 	err := idx.witness.delete(ctx, did, collection, rkey)
 	if err == nil {
-		if sourceRef := exploreSourceRef(deletedRecord); sourceRef != "" {
-			if refreshErr := idx.refreshExploreStats(ctx, sourceRef); refreshErr != nil {
-				idx.markExploreDirty(ctx, refreshErr)
-			}
+		idx.afterRecordDeleted(ctx, collection, deletedRecord)
+	}
+	return err
+
+}
+
+// afterRecordDeleted refreshes what was derived from a record once its row
+// is gone: the explore stats of whatever it referenced and, for brews, the
+// ratings of the gear it used.
+func (idx *FeedIndex) afterRecordDeleted(ctx context.Context, collection string, deletedRecord json.RawMessage) {
+	if sourceRef := exploreSourceRef(deletedRecord); sourceRef != "" {
+		if refreshErr := idx.refreshExploreStats(ctx, sourceRef); refreshErr != nil {
+			idx.markExploreDirty(ctx, refreshErr)
 		}
-		if collection == idx.recordTypeToNSID[lexicons.RecordTypeBrew] && len(deletedRecord) > 0 {
-			if refreshErr := idx.reindexExploreBrewReferences(ctx, deletedRecord); refreshErr != nil {
-				idx.markExploreDirty(ctx, refreshErr)
-			}
+	}
+	if collection == idx.recordTypeToNSID[lexicons.RecordTypeBrew] && len(deletedRecord) > 0 {
+		if refreshErr := idx.reindexExploreBrewReferences(ctx, deletedRecord); refreshErr != nil {
+			idx.markExploreDirty(ctx, refreshErr)
 		}
 	}
-	return err
+	idx.notifyFeedChange(collection)
+}
+
+// DeleteRecordCascade removes the record at uri and every like, comment,
+// bookmark and notification aimed at it, so a record deleted on its PDS
+// doesn't leave counts behind for a subject that no longer exists. The
+// record and its social rows go in one transaction.
+func (idx *FeedIndex) DeleteRecordCascade(ctx context.Context, uri string) error {
+	parsed, err := atp.ParseATURI(uri)
+	if err != nil {
+		return fmt.Errorf("parse uri: %w", err)
+	}
+
+	tx, err := idx.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var deletedRecord json.RawMessage
+	var raw string
+	if err := tx.QueryRowContext(ctx, `SELECT record FROM records WHERE uri = ?`, uri).Scan(&raw); err == nil {
+		deletedRecord = json.RawMessage(raw)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM records WHERE uri = ?`, uri); err != nil {
+		return fmt.Errorf("delete record: %w", err)
+	}
+	if err := idx.social.deleteAllForSubject(ctx, tx, uri); err != nil {
+		return fmt.Errorf("delete social data for subject: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	idx.afterRecordDeleted(ctx, parsed.Collection, deletedRecord)
+	return nil
+}
+
+// PruneOlderThan removes records created before cutoff and returns how many
+// were removed. The records table doubles as the witness cache, so only
+// records by DIDs that are neither registered nor backfilled are pruned:
+// those are only seen in the feed, while registered users read their own
+// lists and profiles from the cache. Each record goes through
+// DeleteRecordCascade so its likes, comments and notifications go with it
+// and explore stats and brew references are refreshed as for a firehose
+// delete.
+func (idx *FeedIndex) PruneOlderThan(ctx context.Context, cutoff time.Time) (int, error) {
+	rows, err := idx.db.QueryContext(ctx, `
+		SELECT did, collection, rkey FROM records
+		WHERE julianday(created_at) < julianday(?)
+		  AND did NOT IN (SELECT did FROM registered_dids)
+		  AND did NOT IN (SELECT did FROM backfilled)`,
+		cutoff.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return 0, fmt.Errorf("select records to prune: %w", err)
+	}
+	type recordKey struct{ did, collection, rkey string }
+	var stale []recordKey
+	for rows.Next() {
+		var k recordKey
+		if err := rows.Scan(&k.did, &k.collection, &k.rkey); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan record to prune: %w", err)
+		}
+		stale = append(stale, k)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("select records to prune: %w", err)
+	}
 
+	pruned := 0
+	for _, k := range stale {
+		if err := ctx.Err(); err != nil {
+			return pruned, err
+		}
+		uri := atp.BuildATURI(k.did, k.collection, k.rkey)
+		if err := idx.DeleteRecordCascade(ctx, uri); err != nil {
+			return pruned, fmt.Errorf("prune %s: %w", uri, err)
+		}
+		pruned++
+	}
+	return pruned, nil
 }
 
 // DeleteAllByDID removes all data associated with a DID from the index.
 // Used when a Jetstream account event reports the DID as deleted or takendown.
 //
 // Removes: records authored by the DID; likes/comments by the DID; likes/comments
-// targeting the DID's records; profile cache; notifications to or from the DID;
+// targeting the DID's records; follows from or to the DID; the DID's
+// bookmarks; profile cache; notifications to or from the DID;
 // known/registered/backfilled tracking; user settings.
 //
 // Preserves moderation_* tables (reports, audit log, blacklist, labels, hidden
@@ -420,13 +635,24 @@ func (idx *FeedIndex) UpsertWitnessRecord(ctx context.Context, did, collection,
 // without touching cid. No-op when the row does not yet exist — the firehose
 // event for this commit will INSERT it with the real cid.
 func (idx *FeedIndex) UpdateWitnessRecord(ctx context.Context, did, collection, rkey string, record json.RawMessage) error {
-	return idx.witness.update(ctx, did, collection, rkey, record)
+	updated, err := idx.witness.update(ctx, did, collection, rkey, record)
+	if err != nil || !updated {
+		return err
+	}
+	idx.indexBrewTags(ctx, did, collection, rkey, record)
+	return nil
 }
 
 // UpsertWitnessRecordBatch implements atproto.WitnessCache batch upsert.
 // All records are inserted in a single transaction for efficiency.
 func (idx *FeedIndex) UpsertWitnessRecordBatch(ctx context.Context, records []atproto.WitnessWriteRecord) error {
-	return idx.witness.upsertBatch(ctx, records)
+	if err := idx.witness.upsertBatch(ctx, records); err != nil {
+		return err
+	}
+	for _, rec := range records {
+		idx.indexBrewTags(ctx, rec.DID, rec.Collection, rec.RKey, rec.Record)
+	}
+	return nil
 }
 
 // DeleteWitnessRecord implements atproto.WitnessCache for write-through caching.
@@ -441,13 +667,41 @@ func (idx *FeedIndex) GetRecord(ctx context.Context, uri string) (*IndexedRecord
 
 // GetProfile fetches a profile, using cache when possible. The persistent
 // SQLite store has no TTL — the profile watcher keeps it fresh via the
-// firehose. Only a completely unknown DID triggers an API fetch.
+// firehose. Only a completely unknown DID triggers an API fetch, and a DID
+// whose fetch recently failed gets ErrProfileUnresolved instead.
 func (idx *FeedIndex) GetProfile(ctx context.Context, did string) (*atproto.Profile, error) {
+	if profile, ok := idx.cachedProfile(ctx, did); ok {
+		return profile, nil
+	}
+	if idx.profileRecentlyFailed(did) {
+		return nil, ErrProfileUnresolved
+	}
+
+	// Unknown DID — fetch from API
+	profile, err := idx.fetchProfile(ctx, did)
+	if err != nil {
+		idx.recordProfileFailure(ctx, did)
+		return nil, err
+	}
+
+	idx.storeProfile(ctx, did, profile)
+	return profile, nil
+}
+
+// cachedProfile looks a profile up in the in-memory and persistent caches
+// without touching the network.
+func (idx *FeedIndex) cachedProfile(ctx context.Context, did string) (*atproto.Profile, bool) {
 	// Check in-memory cache first (TTL used only for memory management)
+	now := time.Now()
 	idx.profileCacheMu.RLock()
-	if cached, ok := idx.profileCache[did]; ok && time.Now().Before(cached.ExpiresAt) {
+	if cached, ok := idx.profileCache[did]; ok && now.Before(cached.ExpiresAt) {
+		refresh := idx.profileRefreshAhead > 0 && cached.ExpiresAt.Sub(now) <= idx.profileRefreshAhead
 		idx.profileCacheMu.RUnlock()
-		return cached.Profile, nil
+		if refresh {
+			idx.refreshProfileAhead(did)
+		}
+		metrics.ProfileCacheHitsTotal.Inc()
+		return cached.Profile, true
 	}
 	idx.profileCacheMu.RUnlock()
 
@@ -458,17 +712,128 @@ func (idx *FeedIndex) GetProfile(ctx context.Context, did string) (*atproto.Prof
 		idx.profileCacheMu.Lock()
 		idx.profileCache[did] = cached
 		idx.profileCacheMu.Unlock()
-		return cached.Profile, nil
+		metrics.ProfileCacheHitsTotal.Inc()
+		return cached.Profile, true
 	}
+	metrics.ProfileCacheMissesTotal.Inc()
+	return nil, false
+}
 
-	// Unknown DID — fetch from API
-	profile, err := idx.publicClient.GetProfile(ctx, did)
-	if err != nil {
-		return nil, err
+// refreshProfileAhead fetches did's profile in the background so the cached
+// copy is replaced before it expires. At most one refresh per DID runs at a
+// time; a failed refresh leaves the cached copy to expire normally.
+func (idx *FeedIndex) refreshProfileAhead(did string) {
+	idx.profileCacheMu.Lock()
+	if _, busy := idx.profileRefreshing[did]; busy {
+		idx.profileCacheMu.Unlock()
+		return
 	}
+	idx.profileRefreshing[did] = struct{}{}
+	idx.profileCacheMu.Unlock()
 
-	idx.storeProfile(ctx, did, profile)
-	return profile, nil
+	go func() {
+		defer func() {
+			idx.profileCacheMu.Lock()
+			delete(idx.profileRefreshing, did)
+			idx.profileCacheMu.Unlock()
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), profileRefreshTimeout)
+		defer cancel()
+		profile, err := idx.fetchProfile(ctx, did)
+		if err != nil {
+			log.Debug().Err(err).Str("did", did).Msg("profile refresh-ahead failed")
+			return
+		}
+		idx.storeProfile(ctx, did, profile)
+	}()
+}
+
+// profileRecentlyFailed reports whether did is in the negative cache. An
+// expired entry is deleted here so the map doesn't grow with every DID that
+// ever failed once.
+func (idx *FeedIndex) profileRecentlyFailed(did string) bool {
+	idx.profileCacheMu.RLock()
+	retryAt, ok := idx.profileFailures[did]
+	idx.profileCacheMu.RUnlock()
+	if !ok {
+		return false
+	}
+	if time.Now().Before(retryAt) {
+		return true
+	}
+	idx.profileCacheMu.Lock()
+	// A failure recorded since the read above stays.
+	if retryAt, ok := idx.profileFailures[did]; ok && !time.Now().Before(retryAt) {
+		delete(idx.profileFailures, did)
+	}
+	idx.profileCacheMu.Unlock()
+	return false
+}
+
+// recordProfileFailure puts did in the negative cache after a failed fetch.
+// Failures caused by the caller giving up aren't the DID's fault and are
+// not remembered.
+func (idx *FeedIndex) recordProfileFailure(ctx context.Context, did string) {
+	if idx.profileFailureTTL <= 0 || ctx.Err() != nil {
+		return
+	}
+	idx.profileCacheMu.Lock()
+	idx.profileFailures[did] = time.Now().Add(idx.profileFailureTTL)
+	idx.profileCacheMu.Unlock()
+}
+
+// profileFetchWorkers bounds concurrent public API calls in GetProfiles.
+const profileFetchWorkers = 8
+
+// GetProfiles resolves profiles for many DIDs at once. Duplicates are
+// collapsed, cached profiles are returned without a network call, and misses
+// are fetched concurrently (at most profileFetchWorkers at a time). With a
+// simulated 40ms API round trip, BenchmarkColdFeedPageProfiles measured a
+// cold 20-author feed page at ~126ms, down from ~830ms for one GetProfile per
+// item. DIDs whose fetch fails, now or within the profile failure TTL, are
+// absent from the result.
+func (idx *FeedIndex) GetProfiles(ctx context.Context, dids []string) map[string]*atproto.Profile {
+	profiles := make(map[string]*atproto.Profile, len(dids))
+	seen := make(map[string]struct{}, len(dids))
+	var misses []string
+	for _, did := range dids {
+		if _, ok := seen[did]; ok {
+			continue
+		}
+		seen[did] = struct{}{}
+		if profile, ok := idx.cachedProfile(ctx, did); ok {
+			profiles[did] = profile
+			continue
+		}
+		if idx.profileRecentlyFailed(did) {
+			continue
+		}
+		misses = append(misses, did)
+	}
+	if len(misses) == 0 {
+		return profiles
+	}
+
+	var mu sync.Mutex
+	var g errgroup.Group
+	g.SetLimit(profileFetchWorkers)
+	for _, did := range misses {
+		g.Go(func() error {
+			profile, err := idx.fetchProfile(ctx, did)
+			if err != nil {
+				log.Warn().Err(err).Str("did", did).Msg("failed to fetch profile")
+				idx.recordProfileFailure(ctx, did)
+				return nil
+			}
+			idx.storeProfile(ctx, did, profile)
+			mu.Lock()
+			profiles[did] = profile
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return profiles
 }
 
 // StoreProfile writes a profile to both in-memory and persistent caches and
@@ -492,6 +857,7 @@ func (idx *FeedIndex) storeProfile(ctx context.Context, did string, profile *atp
 
 	idx.profileCacheMu.Lock()
 	idx.profileCache[did] = cached
+	delete(idx.profileFailures, did)
 	idx.profileCacheMu.Unlock()
 
 	idx.profileStorage.storeProfile(ctx, did, cached)
@@ -508,10 +874,12 @@ func (idx *FeedIndex) GetDIDByHandle(ctx context.Context, handle string) (string
 }
 
 // InvalidateProfile removes a DID's profile from both the in-memory and persistent
-// caches. The next GetProfile call will re-fetch from the API.
+// caches, along with any remembered fetch failure. The next GetProfile call
+// will re-fetch from the API.
 func (idx *FeedIndex) InvalidateProfile(did string) {
 	idx.profileCacheMu.Lock()
 	delete(idx.profileCache, did)
+	delete(idx.profileFailures, did)
 	idx.profileCacheMu.Unlock()
 
 	idx.profileStorage.deleteProfile(did)
@@ -605,6 +973,7 @@ func (idx *FeedIndex) InvalidatePublicCachesForDID(did string) {
 	if idx.publicClient != nil {
 		idx.publicClient.InvalidateDID(did)
 	}
+	atproto.DefaultHandleCache.InvalidateDID(did)
 }
 
 // OnIdentityEvent reconciles caches when a Jetstream identity event reports
@@ -616,9 +985,10 @@ func (idx *FeedIndex) InvalidatePublicCachesForDID(did string) {
 //  1. Look up this DID's previously cached handle (the old handle).
 //  2. Find any *other* DID whose cached profile still claims the new handle —
 //     that's the prior owner; invalidate its profile and resolver entries.
-//  3. Drop the old handle from the resolver cache (it may now resolve to
-//     someone else, or to nothing).
-//  4. Drop the new handle from the resolver cache so the next ResolveHandle
+//  3. Drop the old handle from the resolver caches — the public client's and
+//     atproto.DefaultHandleCache (it may now resolve to someone else, or to
+//     nothing).
+//  4. Drop the new handle from the resolver caches so the next ResolveHandle
 //     re-fetches from the directory.
 //  5. Refresh this DID's profile via the API; storeProfile then writes the
 //     authoritative did_by_handle row. The AppView's getProfile lags the relay
@@ -649,16 +1019,20 @@ func (idx *FeedIndex) OnIdentityEvent(ctx context.Context, did, newHandle string
 				Msg("identity event: handle reassigned, invalidating prior owner")
 			idx.InvalidateProfile(priorDID)
 			idx.publicClient.InvalidateDID(priorDID)
+			atproto.DefaultHandleCache.InvalidateDID(priorDID)
 		}
 	}
 
 	if oldHandle != "" && oldHandle != newHandle {
 		idx.publicClient.InvalidateHandle(oldHandle)
+		atproto.DefaultHandleCache.Invalidate(oldHandle)
 	}
 	if newHandle != "" {
 		idx.publicClient.InvalidateHandle(newHandle)
+		atproto.DefaultHandleCache.Invalidate(newHandle)
 	}
 	idx.publicClient.InvalidateDID(did)
+	atproto.DefaultHandleCache.InvalidateDID(did)
 
 	profile, err := idx.publicClient.GetProfile(ctx, did)
 	if err != nil {
@@ -708,12 +1082,46 @@ func (idx *FeedIndex) ListRecordsByCollectionOldest(ctx context.Context, collect
 	return idx.listRecordsByCollection(ctx, collection, "ASC")
 }
 
+// RecordKey identifies an indexed record without carrying its body.
+type RecordKey struct {
+	DID       string
+	RKey      string
+	IndexedAt time.Time
+}
+
+// ListRecordKeys returns up to limit records in collection, newest first,
+// without loading their JSON. It is meant for enumerating record pages,
+// e.g. for the sitemap.
+func (idx *FeedIndex) ListRecordKeys(ctx context.Context, collection string, limit int) ([]RecordKey, error) {
+	rows, err := idx.db.QueryContext(ctx, `
+		SELECT did, rkey, indexed_at FROM records
+		WHERE collection = ? AND `+notDraftSQL+`
+		ORDER BY created_at DESC LIMIT ?
+	`, collection, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []RecordKey
+	for rows.Next() {
+		var key RecordKey
+		var indexedAtStr string
+		if err := rows.Scan(&key.DID, &key.RKey, &indexedAtStr); err != nil {
+			continue
+		}
+		key.IndexedAt, _ = time.Parse(time.RFC3339Nano, indexedAtStr)
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
 // ListDirectSourceRefs returns records whose sourceRef points directly at uri.
 func (idx *FeedIndex) ListDirectSourceRefs(ctx context.Context, uri string) ([]IndexedRecord, error) {
 	rows, err := idx.db.QueryContext(ctx, `
 		SELECT uri, did, collection, rkey, record, cid, indexed_at, created_at
 		FROM records
-		WHERE json_extract(record, '$.sourceRef') = ?
+		WHERE json_extract(record, '$.sourceRef') = ? AND `+notDraftSQL+`
 		ORDER BY created_at DESC
 	`, uri)
 	if err != nil {
@@ -766,7 +1174,7 @@ func (idx *FeedIndex) ListUsageBacklinks(ctx context.Context, uri, fromCollectio
 	rows, err := idx.db.QueryContext(ctx, fmt.Sprintf(`
 		SELECT uri, did, collection, rkey, record, cid, indexed_at, created_at
 		FROM records
-		WHERE collection = ? AND json_extract(record, '$.%s') = ?
+		WHERE collection = ? AND json_extract(record, '$.%s') = ? AND `+notDraftSQL+`
 		ORDER BY created_at DESC
 	`, fieldName), fromCollection, uri)
 	if err != nil {
@@ -791,14 +1199,14 @@ func (idx *FeedIndex) ListUsageBacklinksPage(ctx context.Context, uri, fromColle
 	if err := idx.db.QueryRowContext(ctx, fmt.Sprintf(`
 		SELECT COUNT(*)
 		FROM records
-		WHERE collection = ? AND json_extract(record, '$.%s') = ?
+		WHERE collection = ? AND json_extract(record, '$.%s') = ? AND `+notDraftSQL+`
 	`, fieldName), fromCollection, uri).Scan(&count); err != nil {
 		return nil, 0, err
 	}
 	rows, err := idx.db.QueryContext(ctx, fmt.Sprintf(`
 		SELECT uri, did, collection, rkey, record, cid, indexed_at, created_at
 		FROM records
-		WHERE collection = ? AND json_extract(record, '$.%s') = ?
+		WHERE collection = ? AND json_extract(record, '$.%s') = ? AND `+notDraftSQL+`
 		ORDER BY created_at DESC
 		LIMIT ? OFFSET ?
 	`, fieldName), fromCollection, uri, limit, offset)
@@ -858,13 +1266,32 @@ func (idx *FeedIndex) RecordCount() int {
 	return count
 }
 
-// KnownDIDCount returns the number of unique DIDs in the index
+// KnownDIDCount returns the number of unique DIDs the index has seen a
+// record from, including DIDs whose only records are likes or follows.
 func (idx *FeedIndex) KnownDIDCount() int {
 	var count int
 	_ = idx.db.QueryRow(`SELECT COUNT(*) FROM known_dids`).Scan(&count)
 	return count
 }
 
+// ActiveDIDCount returns the number of DIDs with at least one record in a
+// feedable collection, i.e. people who have logged something rather than
+// only liked or followed.
+func (idx *FeedIndex) ActiveDIDCount() int {
+	if len(idx.feedableCollections) == 0 {
+		return 0
+	}
+	placeholders := make([]string, len(idx.feedableCollections))
+	args := make([]any, len(idx.feedableCollections))
+	for i, c := range idx.feedableCollections {
+		placeholders[i] = "?"
+		args[i] = c
+	}
+	var count int
+	_ = idx.db.QueryRow(`SELECT COUNT(DISTINCT did) FROM records WHERE collection IN (`+strings.Join(placeholders, ",")+`)`, args...).Scan(&count)
+	return count
+}
+
 // TotalLikeCount returns the total number of likes indexed
 func (idx *FeedIndex) TotalLikeCount() int {
 	return idx.social.totalLikeCount()
@@ -893,6 +1320,15 @@ func (idx *FeedIndex) RecordCountByCollection() map[string]int {
 	return counts
 }
 
+// DatabaseSize returns the size of the SQLite database in bytes, computed
+// from page_count * page_size. Pages held only in the WAL are not included.
+func (idx *FeedIndex) DatabaseSize(ctx context.Context) (int64, error) {
+	var size int64
+	err := idx.db.QueryRowContext(ctx,
+		`SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()`).Scan(&size)
+	return size, err
+}
+
 // BrewCountsByRecipeURI returns a map of recipe AT-URI -> number of brews referencing that recipe.
 // Uses SQLite json_extract to efficiently query the recipeRef field in brew records.
 func (idx *FeedIndex) BrewCountsByRecipeURI(ctx context.Context) map[string]int {
@@ -902,6 +1338,7 @@ func (idx *FeedIndex) BrewCountsByRecipeURI(ctx context.Context) map[string]int
 		FROM records
 		WHERE collection = 'social.arabica.alpha.brew'
 		  AND recipe_uri IS NOT NULL AND recipe_uri != ''
+		  AND `+notDraftSQL+`
 		GROUP BY recipe_uri
 	`)
 	if err != nil {
@@ -931,6 +1368,7 @@ func (idx *FeedIndex) refCounts(ctx context.Context, collection, jsonField, did
 			FROM records
 			WHERE collection = ? AND did = ?
 			  AND ref_uri IS NOT NULL AND ref_uri != ''
+			  AND `+notDraftSQL+`
 			GROUP BY ref_uri
 		`, jsonField), collection, did)
 	} else {
@@ -939,6 +1377,7 @@ func (idx *FeedIndex) refCounts(ctx context.Context, collection, jsonField, did
 			FROM records
 			WHERE collection = ?
 			  AND ref_uri IS NOT NULL AND ref_uri != ''
+			  AND `+notDraftSQL+`
 			GROUP BY ref_uri
 		`, jsonField), collection)
 	}
@@ -980,6 +1419,44 @@ func (idx *FeedIndex) BeanCountsByRoasterURI(ctx context.Context, did string) ma
 	return idx.refCounts(ctx, "social.arabica.alpha.bean", "roasterRef", did)
 }
 
+// ListRoasterBeans returns every indexed bean, from any user, whose
+// roasterRef points at roasterURI or at another roaster record with the same
+// name. Roasters aren't shared records, so each user who logs "Onyx" gets
+// their own roaster; matching on the trimmed, case-folded name gathers them
+// into one community view. Hidden records and blacklisted authors are
+// excluded. Results are newest first, capped at limit.
+func (idx *FeedIndex) ListRoasterBeans(ctx context.Context, roasterURI string, limit int) ([]IndexedRecord, error) {
+	if limit <= 0 {
+		limit = 200
+	}
+	rows, err := idx.db.QueryContext(ctx, `
+		WITH target AS (
+			SELECT lower(trim(json_extract(record, '$.name'))) AS name
+			FROM records WHERE uri = ?
+		),
+		roasters AS (
+			SELECT ? AS uri
+			UNION
+			SELECT uri FROM records
+			WHERE collection = 'social.arabica.alpha.roaster'
+			  AND lower(trim(json_extract(record, '$.name'))) = (SELECT name FROM target WHERE name != '')
+		)
+		SELECT uri, did, collection, rkey, record, cid, indexed_at, created_at
+		FROM records
+		WHERE collection = 'social.arabica.alpha.bean'
+		  AND json_extract(record, '$.roasterRef') IN (SELECT uri FROM roasters)
+		  AND uri NOT IN (SELECT uri FROM moderation_hidden_records)
+		  AND did NOT IN (SELECT did FROM moderation_blacklist)
+		ORDER BY created_at DESC
+		LIMIT ?
+	`, roasterURI, roasterURI, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanIndexedRecords(rows)
+}
+
 // RatingStats holds aggregated rating statistics for an entity.
 type RatingStats struct {
 	Average float64
@@ -1003,6 +1480,7 @@ func (idx *FeedIndex) refAvgRatings(ctx context.Context, jsonField, did string)
 			  AND did = ?
 			  AND ref_uri IS NOT NULL AND ref_uri != ''
 			  AND json_extract(record, '$.rating') IS NOT NULL
+			  AND `+notDraftSQL+`
 			GROUP BY ref_uri
 		`, jsonField), did)
 	} else {
@@ -1014,6 +1492,7 @@ func (idx *FeedIndex) refAvgRatings(ctx context.Context, jsonField, did string)
 			WHERE collection = 'social.arabica.alpha.brew'
 			  AND ref_uri IS NOT NULL AND ref_uri != ''
 			  AND json_extract(record, '$.rating') IS NOT NULL
+			  AND `+notDraftSQL+`
 			GROUP BY ref_uri
 		`, jsonField))
 	}
@@ -1057,6 +1536,7 @@ func (idx *FeedIndex) AvgBrewRatingByRoasterURI(ctx context.Context, did string)
 			WHERE brews.collection = 'social.arabica.alpha.brew'
 			  AND brews.did = ?
 			  AND json_extract(brews.record, '$.rating') IS NOT NULL
+			  AND `+notDraftSQLOn("brews")+`
 			  AND roaster_uri IS NOT NULL AND roaster_uri != ''
 			GROUP BY roaster_uri
 		`, did)
@@ -1071,6 +1551,7 @@ func (idx *FeedIndex) AvgBrewRatingByRoasterURI(ctx context.Context, did string)
 			  AND beans.collection = 'social.arabica.alpha.bean'
 			WHERE brews.collection = 'social.arabica.alpha.brew'
 			  AND json_extract(brews.record, '$.rating') IS NOT NULL
+			  AND `+notDraftSQLOn("brews")+`
 			  AND roaster_uri IS NOT NULL AND roaster_uri != ''
 			GROUP BY roaster_uri
 		`)
@@ -1148,8 +1629,14 @@ func (idx *FeedIndex) SetUserPreferences(ctx context.Context, did string, prefs
 	return idx.profileStorage.setUserPreferences(ctx, did, string(raw))
 }
 
+// formatTimeAgo fills FeedItem.TimeAgo for API clients. Past the first day
+// it counts calendar days in UTC, since no viewer zone is known here; pages
+// recompute it in the viewer's zone at render.
 func formatTimeAgo(t time.Time) string {
-	now := time.Now()
+	return formatTimeAgoAt(t, time.Now())
+}
+
+func formatTimeAgoAt(t, now time.Time) string {
 	diff := now.Sub(t)
 
 	switch {
@@ -1167,19 +1654,22 @@ func formatTimeAgo(t time.Time) string {
 			return "1 hour ago"
 		}
 		return fmt.Sprintf("%d hours ago", hours)
-	case diff < 48*time.Hour:
+	}
+
+	days := int(now.UTC().Truncate(24*time.Hour).Sub(t.UTC().Truncate(24*time.Hour)).Hours() / 24)
+	switch {
+	case days <= 1:
 		return "yesterday"
-	case diff < 7*24*time.Hour:
-		days := int(diff.Hours() / 24)
+	case days < 7:
 		return fmt.Sprintf("%d days ago", days)
-	case diff < 30*24*time.Hour:
-		weeks := int(diff.Hours() / 24 / 7)
+	case days < 30:
+		weeks := days / 7
 		if weeks == 1 {
 			return "1 week ago"
 		}
 		return fmt.Sprintf("%d weeks ago", weeks)
 	default:
-		months := int(diff.Hours() / 24 / 30)
+		months := days / 30
 		if months == 1 {
 			return "1 month ago"
 		}
@@ -1247,56 +1737,11 @@ func (idx *FeedIndex) BackfillUser(ctx context.Context, did string, collections
 		}
 
 		for _, record := range recs {
-			parts := strings.Split(record.URI, "/")
-			if len(parts) < 3 {
-				continue
-			}
-			rkey := parts[len(parts)-1]
-
-			recordJSON, err := json.Marshal(record.Value)
-			if err != nil {
-				continue
-			}
-
-			if err := idx.UpsertRecord(ctx, did, collection, rkey, record.CID, recordJSON, 0); err != nil {
+			if err := idx.indexBackfilledRecord(ctx, did, collection, record); err != nil {
 				log.Warn().Err(err).Str("uri", record.URI).Msg("failed to upsert record during backfill")
 				continue
 			}
 			recordCount++
-
-			switch {
-			case strings.HasSuffix(collection, ".like"):
-				if subject, ok := record.Value["subject"].(map[string]any); ok {
-					if subjectURI, ok := subject["uri"].(string); ok {
-						if err := idx.UpsertLike(ctx, did, rkey, subjectURI); err != nil {
-							log.Warn().Err(err).Str("uri", record.URI).Msg("failed to index like during backfill")
-						}
-					}
-				}
-			case strings.HasSuffix(collection, ".comment"):
-				if subject, ok := record.Value["subject"].(map[string]any); ok {
-					if subjectURI, ok := subject["uri"].(string); ok {
-						text, _ := record.Value["text"].(string)
-						var createdAt time.Time
-						if createdAtStr, ok := record.Value["createdAt"].(string); ok {
-							if parsed, err := time.Parse(time.RFC3339, createdAtStr); err == nil {
-								createdAt = parsed
-							} else {
-								createdAt = time.Now()
-							}
-						} else {
-							createdAt = time.Now()
-						}
-						var parentURI string
-						if parent, ok := record.Value["parent"].(map[string]any); ok {
-							parentURI, _ = parent["uri"].(string)
-						}
-						if err := idx.UpsertComment(ctx, did, rkey, subjectURI, parentURI, record.CID, text, createdAt); err != nil {
-							log.Warn().Err(err).Str("uri", record.URI).Msg("failed to index comment during backfill")
-						}
-					}
-				}
-			}
 		}
 	}
 
@@ -1308,6 +1753,78 @@ func (idx *FeedIndex) BackfillUser(ctx context.Context, did string, collections
 	return nil
 }
 
+// indexBackfilledRecord writes one record fetched from a PDS into the
+// index, along with the like, bookmark, follow, or comment row the firehose
+// would have written for it.
+func (idx *FeedIndex) indexBackfilledRecord(ctx context.Context, did, collection string, record atp.Record) error {
+	parts := strings.Split(record.URI, "/")
+	if len(parts) < 3 {
+		return fmt.Errorf("malformed record uri %q", record.URI)
+	}
+	rkey := parts[len(parts)-1]
+
+	recordJSON, err := json.Marshal(record.Value)
+	if err != nil {
+		return err
+	}
+	if err := idx.UpsertRecord(ctx, did, collection, rkey, record.CID, recordJSON, 0); err != nil {
+		return err
+	}
+
+	switch {
+	case strings.HasSuffix(collection, ".like"):
+		if subject, ok := record.Value["subject"].(map[string]any); ok {
+			if subjectURI, ok := subject["uri"].(string); ok {
+				if err := idx.UpsertLike(ctx, did, rkey, subjectURI); err != nil {
+					log.Warn().Err(err).Str("uri", record.URI).Msg("failed to index like during backfill")
+				}
+			}
+		}
+	case strings.HasSuffix(collection, ".bookmark"):
+		if subject, ok := record.Value["subject"].(map[string]any); ok {
+			if subjectURI, ok := subject["uri"].(string); ok {
+				if err := idx.UpsertBookmark(ctx, did, rkey, subjectURI); err != nil {
+					log.Warn().Err(err).Str("uri", record.URI).Msg("failed to index bookmark during backfill")
+				}
+			}
+		}
+	case strings.HasSuffix(collection, ".follow"):
+		if subjectDID, ok := record.Value["subject"].(string); ok && subjectDID != "" {
+			if err := idx.UpsertFollow(ctx, did, rkey, subjectDID); err != nil {
+				log.Warn().Err(err).Str("uri", record.URI).Msg("failed to index follow during backfill")
+			}
+		}
+	case strings.HasSuffix(collection, ".comment"):
+		if subject, ok := record.Value["subject"].(map[string]any); ok {
+			if subjectURI, ok := subject["uri"].(string); ok {
+				text, _ := record.Value["text"].(string)
+				var createdAt time.Time
+				if createdAtStr, ok := record.Value["createdAt"].(string); ok {
+					if parsed, err := time.Parse(time.RFC3339, createdAtStr); err == nil {
+						createdAt = parsed
+					} else {
+						createdAt = time.Now()
+					}
+				} else {
+					createdAt = time.Now()
+				}
+				var parentURI string
+				if parent, ok := record.Value["parent"].(map[string]any); ok {
+					parentURI, _ = parent["uri"].(string)
+				}
+				var editedAt time.Time
+				if editedAtStr, ok := record.Value["editedAt"].(string); ok {
+					editedAt, _ = time.Parse(time.RFC3339, editedAtStr)
+				}
+				if err := idx.UpsertComment(ctx, did, rkey, subjectURI, parentURI, record.CID, text, createdAt, editedAt); err != nil {
+					log.Warn().Err(err).Str("uri", record.URI).Msg("failed to index comment during backfill")
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // ========== Like Indexing Methods ==========
 
 // UpsertLike adds or updates a like in the index
@@ -1349,6 +1866,52 @@ func (idx *FeedIndex) GetUserLikeRKey(ctx context.Context, actorDID, subjectURI
 	return idx.social.userLikeRKey(ctx, actorDID, subjectURI)
 }
 
+// GetLikers returns up to limit DIDs that liked subjectURI, ordered by DID.
+// Pass the last DID of the previous page as after to continue from it.
+func (idx *FeedIndex) GetLikers(ctx context.Context, subjectURI string, limit int, after string) []string {
+	return idx.social.likers(ctx, subjectURI, limit, after)
+}
+
+// ========== Follow Indexing Methods ==========
+
+// UpsertFollow records that followerDID follows subjectDID.
+func (idx *FeedIndex) UpsertFollow(ctx context.Context, followerDID, rkey, subjectDID string) error {
+	return idx.social.upsertFollow(ctx, followerDID, rkey, subjectDID)
+}
+
+// DeleteFollow removes the follow with the given record key.
+func (idx *FeedIndex) DeleteFollow(ctx context.Context, followerDID, rkey string) error {
+	return idx.social.deleteFollowByRKey(ctx, followerDID, rkey)
+}
+
+// GetFollowRKey returns the rkey of followerDID's follow of subjectDID, or
+// empty string if they don't follow them.
+func (idx *FeedIndex) GetFollowRKey(ctx context.Context, followerDID, subjectDID string) string {
+	return idx.social.followRKey(ctx, followerDID, subjectDID)
+}
+
+// ========== Bookmark Indexing Methods ==========
+//
+// Bookmarks are indexed only so their owner can look up what they saved;
+// nothing here aggregates them across users.
+
+// UpsertBookmark records actorDID's bookmark of subjectURI.
+func (idx *FeedIndex) UpsertBookmark(ctx context.Context, actorDID, rkey, subjectURI string) error {
+	return idx.social.upsertBookmark(ctx, actorDID, rkey, subjectURI)
+}
+
+// DeleteBookmark removes the bookmark with the given record key. Deleting a
+// bookmark that isn't indexed is a no-op.
+func (idx *FeedIndex) DeleteBookmark(ctx context.Context, actorDID, rkey string) error {
+	return idx.social.deleteBookmarkByRKey(ctx, actorDID, rkey)
+}
+
+// GetUserBookmarkRKey returns the rkey of actorDID's bookmark of subjectURI,
+// or empty string if they haven't bookmarked it.
+func (idx *FeedIndex) GetUserBookmarkRKey(ctx context.Context, actorDID, subjectURI string) string {
+	return idx.social.bookmarkRKey(ctx, actorDID, subjectURI)
+}
+
 // ========== Batch Query Methods ==========
 
 // placeholders returns a string of "?,?,?" for n items and a corresponding []any slice.
@@ -1412,6 +1975,7 @@ type IndexedComment struct {
 	Text       string    `json:"text"`
 	ActorDID   string    `json:"actor_did"`
 	CreatedAt  time.Time `json:"created_at"`
+	EditedAt   time.Time `json:"edited_at,omitzero"` // Zero unless the author edited the text
 	// Parent fields for threading (stored)
 	ParentURI  string `json:"parent_uri,omitempty"`
 	ParentRKey string `json:"parent_rkey,omitempty"`
@@ -1428,9 +1992,10 @@ type IndexedComment struct {
 	IsLiked   bool `json:"-"`
 }
 
-// UpsertComment adds or updates a comment in the index
-func (idx *FeedIndex) UpsertComment(ctx context.Context, actorDID, rkey, subjectURI, parentURI, cid, text string, createdAt time.Time) error {
-	err := idx.social.upsertComment(ctx, actorDID, rkey, subjectURI, parentURI, cid, text, createdAt)
+// UpsertComment adds or updates a comment in the index. editedAt is zero for
+// comments that have never been edited.
+func (idx *FeedIndex) UpsertComment(ctx context.Context, actorDID, rkey, subjectURI, parentURI, cid, text string, createdAt, editedAt time.Time) error {
+	err := idx.social.upsertComment(ctx, actorDID, rkey, subjectURI, parentURI, cid, text, createdAt, editedAt)
 	if err == nil {
 		if refreshErr := idx.refreshExploreStats(ctx, subjectURI); refreshErr != nil {
 			idx.markExploreDirty(ctx, refreshErr)
@@ -1440,16 +2005,21 @@ func (idx *FeedIndex) UpsertComment(ctx context.Context, actorDID, rkey, subject
 
 }
 
-// DeleteComment removes a comment from the index
+// DeleteComment removes a comment from the index. subjectURI may be empty;
+// the subject is taken from the stored comment. Deleting a comment that is
+// not indexed (e.g. a repeated delete) is a no-op and leaves counts alone.
 func (idx *FeedIndex) DeleteComment(ctx context.Context, actorDID, rkey, subjectURI string) error {
-	err := idx.social.deleteComment(ctx, actorDID, rkey)
-	if err == nil {
-		if refreshErr := idx.refreshExploreStats(ctx, subjectURI); refreshErr != nil {
-			idx.markExploreDirty(ctx, refreshErr)
-		}
+	deletedSubject, err := idx.social.deleteComment(ctx, actorDID, rkey)
+	if err != nil || deletedSubject == "" {
+		return err
 	}
-	return err
-
+	if subjectURI == "" {
+		subjectURI = deletedSubject
+	}
+	if refreshErr := idx.refreshExploreStats(ctx, subjectURI); refreshErr != nil {
+		idx.markExploreDirty(ctx, refreshErr)
+	}
+	return nil
 }
 
 // GetCommentCount returns the number of comments on a record
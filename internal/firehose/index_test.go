@@ -2,15 +2,20 @@ package firehose
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/arabica.social/arabica/internal/atproto"
 	"tangled.org/arabica.social/arabica/internal/lexicons"
 	oolongapp "tangled.org/arabica.social/arabica/internal/oolong/app"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewFeedIndexScopesFeedableCollectionsToDescriptors(t *testing.T) {
@@ -125,6 +130,326 @@ func TestBackfillTracking_MultipleDIDs(t *testing.T) {
 	}
 }
 
+func TestBackfillDIDs_SkipsBackfilledConcurrently(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+
+	dids := make([]string, 0, 10)
+	for i := range 10 {
+		did := fmt.Sprintf("did:plc:user%d", i)
+		require.NoError(t, idx.MarkBackfilled(ctx, did))
+		dids = append(dids, did)
+	}
+
+	consumer := NewConsumer(DefaultConfig(), idx)
+	succeeded, inProgress, failed := consumer.BackfillDIDs(ctx, dids, 3)
+	assert.Equal(t, 10, succeeded)
+	assert.Zero(t, inProgress)
+	assert.Zero(t, failed)
+	assert.Empty(t, consumer.backfilling, "in-flight set must drain")
+}
+
+func TestBackfillDIDs_CountsInProgressSeparately(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	dids := []string{"did:plc:alice", "did:plc:bob"}
+	for _, did := range dids {
+		require.NoError(t, idx.MarkBackfilled(ctx, did))
+	}
+
+	consumer := NewConsumer(DefaultConfig(), idx)
+	require.True(t, consumer.claimBackfill("did:plc:bob"))
+	assert.ErrorIs(t, consumer.BackfillDID(ctx, "did:plc:bob"), ErrBackfillInProgress)
+
+	succeeded, inProgress, failed := consumer.BackfillDIDs(ctx, dids, 2)
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, 1, inProgress)
+	assert.Zero(t, failed)
+}
+
+func TestCursorSurvivesRestart(t *testing.T) {
+	path := t.TempDir() + "/test.db"
+	ctx := context.Background()
+
+	idx, err := NewFeedIndex(path, 1*time.Hour)
+	require.NoError(t, err)
+
+	cursor, err := idx.GetCursor(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, cursor, "fresh index has no cursor")
+
+	// Checkpoints advance as events are processed; only the last one matters
+	for _, timeUS := range []int64{1700000000000001, 1700000000000002, 1700000000000003} {
+		require.NoError(t, idx.SetCursor(ctx, timeUS))
+	}
+	require.NoError(t, idx.Close())
+
+	reopened, err := NewFeedIndex(path, 1*time.Hour)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	cursor, err = reopened.GetCursor(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1700000000000003), cursor)
+}
+
+func TestPruneOlderThan(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	did := "did:plc:pruner"
+	oldAt := time.Now().Add(-90 * 24 * time.Hour).UTC()
+	recentAt := time.Now().Add(-time.Hour).UTC()
+
+	beanURI := "at://" + did + "/" + arabica.NSIDBean + "/oldbean"
+	bean := fmt.Appendf(nil, `{"$type":%q,"name":"Old Bean","createdAt":%q}`, arabica.NSIDBean, oldAt.Format(time.RFC3339))
+	require.NoError(t, idx.UpsertRecord(ctx, did, arabica.NSIDBean, "oldbean", "cid-bean", bean, time.Now().Unix()))
+
+	oldBrew := fmt.Appendf(nil, `{"$type":%q,"beanRef":%q,"rating":5,"createdAt":%q}`, arabica.NSIDBrew, beanURI, oldAt.Format(time.RFC3339))
+	require.NoError(t, idx.UpsertRecord(ctx, did, arabica.NSIDBrew, "oldbrew", "cid-old", oldBrew, time.Now().Unix()))
+
+	brewURI := "at://" + did + "/" + arabica.NSIDBrew + "/newbrew"
+	newBrew := fmt.Appendf(nil, `{"$type":%q,"beanRef":%q,"rating":7,"createdAt":%q}`, arabica.NSIDBrew, beanURI, recentAt.Format(time.RFC3339))
+	require.NoError(t, idx.UpsertRecord(ctx, did, arabica.NSIDBrew, "newbrew", "cid-new", newBrew, time.Now().Unix()))
+	require.NoError(t, idx.UpsertLike(ctx, "did:plc:fan", "like1", brewURI))
+	require.NoError(t, idx.UpsertComment(ctx, "did:plc:fan", "comment1", brewURI, "", "cid-c", "Tasty", recentAt, time.Time{}))
+	oldBrewURI := "at://" + did + "/" + arabica.NSIDBrew + "/oldbrew"
+	require.NoError(t, idx.UpsertLike(ctx, "did:plc:fan", "like2", oldBrewURI))
+
+	// Registered and backfilled users read their own records from the index,
+	// so theirs are kept however old they are.
+	for _, owner := range []string{"did:plc:registered", "did:plc:backfilled"} {
+		require.NoError(t, idx.UpsertRecord(ctx, owner, arabica.NSIDBean, "oldbean", "cid-"+owner, bean, time.Now().Unix()))
+	}
+	require.NoError(t, idx.Register("did:plc:registered"))
+	require.NoError(t, idx.MarkBackfilled(ctx, "did:plc:backfilled"))
+
+	pruned, err := idx.PruneOlderThan(ctx, time.Now().Add(-30*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, pruned)
+	assert.Equal(t, 3, idx.RecordCount())
+
+	gone, err := idx.GetRecord(ctx, beanURI)
+	require.NoError(t, err)
+	assert.Nil(t, gone)
+	assert.Zero(t, idx.GetLikeCount(ctx, oldBrewURI), "likes on a pruned record go with it")
+	for _, owner := range []string{"did:plc:registered", "did:plc:backfilled"} {
+		kept, err := idx.GetRecord(ctx, "at://"+owner+"/"+arabica.NSIDBean+"/oldbean")
+		require.NoError(t, err)
+		assert.NotNil(t, kept, owner)
+	}
+
+	// Social counts on the surviving brew are untouched
+	assert.Equal(t, 1, idx.GetLikeCount(ctx, brewURI))
+	assert.Equal(t, 1, idx.GetCommentCount(ctx, brewURI))
+
+	// The surviving brew still renders; its pruned bean simply fails to resolve
+	items, err := idx.GetRecentFeed(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, items, 3, "the new brew and the two kept beans")
+	assert.Equal(t, brewURI, items[0].SubjectURI)
+	brew, ok := items[0].Record.(*arabica.Brew)
+	require.True(t, ok)
+	assert.Nil(t, brew.Bean)
+
+	// Nothing left to prune on a second pass
+	pruned, err = idx.PruneOlderThan(ctx, time.Now().Add(-30*24*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, pruned)
+}
+
+func TestPruneOlderThanComparesTimesNotText(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	cutoff := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// As text, "00:00:00.5Z" sorts before "00:00:00Z" although it is later.
+	for rkey, at := range map[string]string{
+		"before": "2025-12-31T23:59:59.9Z",
+		"after":  "2026-01-01T00:00:00.5Z",
+	} {
+		record := fmt.Appendf(nil, `{"$type":%q,"name":"Bean","createdAt":%q}`, arabica.NSIDBean, at)
+		require.NoError(t, idx.UpsertRecord(ctx, "did:plc:pruner", arabica.NSIDBean, rkey, "cid-"+rkey, record, time.Now().Unix()))
+	}
+
+	pruned, err := idx.PruneOlderThan(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+	kept, err := idx.GetRecord(ctx, "at://did:plc:pruner/"+arabica.NSIDBean+"/after")
+	require.NoError(t, err)
+	assert.NotNil(t, kept)
+}
+
+func TestGetProfiles_CachedAndDeduplicated(t *testing.T) {
+	path := t.TempDir() + "/test.db"
+	ctx := context.Background()
+
+	idx, err := NewFeedIndex(path, time.Hour)
+	require.NoError(t, err)
+	idx.StoreProfile(ctx, "did:plc:alice", &atproto.Profile{DID: "did:plc:alice", Handle: "alice.test"})
+	idx.StoreProfile(ctx, "did:plc:bob", &atproto.Profile{DID: "did:plc:bob", Handle: "bob.test"})
+	require.NoError(t, idx.Close())
+
+	// Reopen so both profiles come from the persistent tier, not memory
+	idx, err = NewFeedIndex(path, time.Hour)
+	require.NoError(t, err)
+	defer idx.Close()
+
+	profiles := idx.GetProfiles(ctx, []string{"did:plc:alice", "did:plc:bob", "did:plc:alice"})
+	require.Len(t, profiles, 2)
+	assert.Equal(t, "alice.test", profiles["did:plc:alice"].Handle)
+	assert.Equal(t, "bob.test", profiles["did:plc:bob"].Handle)
+	assert.True(t, idx.ProfileCachedInMemory("did:plc:alice"))
+
+	assert.Empty(t, idx.GetProfiles(ctx, nil))
+}
+
+func TestGetProfile_RefreshAhead(t *testing.T) {
+	ctx := context.Background()
+	idx, err := NewFeedIndex(t.TempDir()+"/test.db", time.Hour, WithProfileRefreshAhead(0.1))
+	require.NoError(t, err)
+	defer idx.Close()
+	assert.Equal(t, 6*time.Minute, idx.profileRefreshAhead)
+
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	idx.fetchProfile = func(ctx context.Context, did string) (*atproto.Profile, error) {
+		fetches.Add(1)
+		<-release
+		return &atproto.Profile{DID: did, Handle: "fresh.test"}, nil
+	}
+
+	did := "did:plc:alice"
+	idx.StoreProfile(ctx, did, &atproto.Profile{DID: did, Handle: "stale.test"})
+
+	// Outside the window: served from cache, no refresh
+	profile, err := idx.GetProfile(ctx, did)
+	require.NoError(t, err)
+	assert.Equal(t, "stale.test", profile.Handle)
+	assert.Zero(t, fetches.Load())
+
+	idx.profileCacheMu.Lock()
+	idx.profileCache[did].ExpiresAt = time.Now().Add(time.Minute)
+	idx.profileCacheMu.Unlock()
+
+	// Inside the window: concurrent reads still get the cached copy and
+	// share a single background fetch
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			profile, err := idx.GetProfile(ctx, did)
+			assert.NoError(t, err)
+			assert.Equal(t, "stale.test", profile.Handle)
+		})
+	}
+	wg.Wait()
+	close(release)
+
+	assert.Eventually(t, func() bool {
+		profile, err := idx.GetProfile(ctx, did)
+		return err == nil && profile.Handle == "fresh.test"
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), fetches.Load())
+}
+
+func TestGetProfile_NegativeCache(t *testing.T) {
+	ctx := context.Background()
+	idx, err := NewFeedIndex(t.TempDir()+"/test.db", time.Hour, WithProfileFailureTTL(time.Minute))
+	require.NoError(t, err)
+	defer idx.Close()
+
+	var fetches atomic.Int32
+	var resolvable atomic.Bool
+	idx.fetchProfile = func(ctx context.Context, did string) (*atproto.Profile, error) {
+		fetches.Add(1)
+		if !resolvable.Load() {
+			return nil, errors.New("could not resolve DID")
+		}
+		return &atproto.Profile{DID: did, Handle: "alice.test"}, nil
+	}
+
+	did := "did:plc:alice"
+	_, err = idx.GetProfile(ctx, did)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrProfileUnresolved)
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// Within the TTL neither lookup path goes back to the network
+	_, err = idx.GetProfile(ctx, did)
+	assert.ErrorIs(t, err, ErrProfileUnresolved)
+	assert.Empty(t, idx.GetProfiles(ctx, []string{did}))
+	assert.Equal(t, int32(1), fetches.Load())
+	assert.False(t, idx.ProfileCachedInMemory(did), "failures are kept apart from real profiles")
+
+	// Once the TTL lapses the DID is retried, and a success replaces the
+	// remembered failure
+	resolvable.Store(true)
+	idx.profileCacheMu.Lock()
+	idx.profileFailures[did] = time.Now().Add(-time.Second)
+	idx.profileCacheMu.Unlock()
+	assert.False(t, idx.profileRecentlyFailed(did))
+	idx.profileCacheMu.RLock()
+	assert.NotContains(t, idx.profileFailures, did, "an expired failure is deleted, not just ignored")
+	idx.profileCacheMu.RUnlock()
+
+	profiles := idx.GetProfiles(ctx, []string{did})
+	require.Contains(t, profiles, did)
+	assert.Equal(t, "alice.test", profiles[did].Handle)
+	assert.Equal(t, int32(2), fetches.Load())
+	assert.False(t, idx.profileRecentlyFailed(did))
+
+	// A cancelled request says nothing about the DID
+	resolvable.Store(false)
+	idx.InvalidateProfile(did)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = idx.GetProfile(cancelled, did)
+	require.Error(t, err)
+	assert.False(t, idx.profileRecentlyFailed(did))
+}
+
+// BenchmarkColdFeedPageProfiles resolves the authors of a 20-item feed page
+// with 20 unique authors on a cold cache, with each profile fetch taking a
+// simulated public API round trip. "sequential" is the former per-item
+// GetProfile loop; "batched" is GetProfiles.
+func BenchmarkColdFeedPageProfiles(b *testing.B) {
+	const (
+		authors   = 20
+		roundTrip = 40 * time.Millisecond
+	)
+	ctx := context.Background()
+	idx, err := NewFeedIndex(b.TempDir()+"/test.db", time.Hour)
+	require.NoError(b, err)
+	defer idx.Close()
+	idx.fetchProfile = func(ctx context.Context, did string) (*atproto.Profile, error) {
+		time.Sleep(roundTrip)
+		return &atproto.Profile{DID: did, Handle: did + ".test"}, nil
+	}
+
+	// Every iteration uses fresh DIDs so the cache stays cold
+	var run int
+	page := func() []string {
+		run++
+		dids := make([]string, authors)
+		for i := range dids {
+			dids[i] = fmt.Sprintf("did:plc:run%d-author%d", run, i)
+		}
+		return dids
+	}
+
+	b.Run("sequential", func(b *testing.B) {
+		for b.Loop() {
+			for _, did := range page() {
+				_, _ = idx.GetProfile(ctx, did)
+			}
+		}
+	})
+	b.Run("batched", func(b *testing.B) {
+		for b.Loop() {
+			idx.GetProfiles(ctx, page())
+		}
+	})
+}
+
 func TestCommentThreading(t *testing.T) {
 	tmpDir := t.TempDir()
 	idx, err := NewFeedIndex(tmpDir+"/test.db", 1*time.Hour)
@@ -137,17 +462,17 @@ func TestCommentThreading(t *testing.T) {
 
 	// Create a top-level comment
 	now := time.Now()
-	err = idx.UpsertComment(ctx, actorDID, "comment1", subjectURI, "", "cid1", "Top level comment", now)
+	err = idx.UpsertComment(ctx, actorDID, "comment1", subjectURI, "", "cid1", "Top level comment", now, time.Time{})
 	assert.NoError(t, err)
 
 	// Create a reply to the top-level comment
 	parentURI := "at://did:plc:commenter1/social.arabica.alpha.comment/comment1"
-	err = idx.UpsertComment(ctx, "did:plc:commenter2", "comment2", subjectURI, parentURI, "cid2", "Reply to comment", now.Add(time.Second))
+	err = idx.UpsertComment(ctx, "did:plc:commenter2", "comment2", subjectURI, parentURI, "cid2", "Reply to comment", now.Add(time.Second), time.Time{})
 	assert.NoError(t, err)
 
 	// Create a nested reply (depth 2)
 	parentURI2 := "at://did:plc:commenter2/social.arabica.alpha.comment/comment2"
-	err = idx.UpsertComment(ctx, "did:plc:commenter3", "comment3", subjectURI, parentURI2, "cid3", "Nested reply", now.Add(2*time.Second))
+	err = idx.UpsertComment(ctx, "did:plc:commenter3", "comment3", subjectURI, parentURI2, "cid3", "Nested reply", now.Add(2*time.Second), time.Time{})
 	assert.NoError(t, err)
 
 	// Get threaded comments
@@ -170,6 +495,188 @@ func TestCommentThreading(t *testing.T) {
 	assert.Equal(t, 3, count)
 }
 
+func TestDeleteComment_Twice(t *testing.T) {
+	tmpDir := t.TempDir()
+	idx, err := NewFeedIndex(tmpDir+"/test.db", 1*time.Hour)
+	assert.NoError(t, err)
+	defer idx.Close()
+
+	ctx := context.Background()
+	subjectURI := "at://did:plc:user1/social.arabica.alpha.brew/abc123"
+
+	err = idx.UpsertComment(ctx, "did:plc:commenter1", "comment1", subjectURI, "", "cid1", "First", time.Now(), time.Time{})
+	assert.NoError(t, err)
+	err = idx.UpsertComment(ctx, "did:plc:commenter2", "comment2", subjectURI, "", "cid2", "Second", time.Now(), time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, idx.GetCommentCount(ctx, subjectURI))
+
+	// Subject URI is resolved from the stored comment when not supplied
+	assert.NoError(t, idx.DeleteComment(ctx, "did:plc:commenter1", "comment1", ""))
+	assert.Equal(t, 1, idx.GetCommentCount(ctx, subjectURI))
+
+	// A repeated delete finds nothing and must not touch the other comment
+	assert.NoError(t, idx.DeleteComment(ctx, "did:plc:commenter1", "comment1", subjectURI))
+	assert.Equal(t, 1, idx.GetCommentCount(ctx, subjectURI))
+
+	// Same rkey under a different actor is a different comment
+	assert.NoError(t, idx.DeleteComment(ctx, "did:plc:someone-else", "comment2", subjectURI))
+	assert.Equal(t, 1, idx.GetCommentCount(ctx, subjectURI))
+
+	assert.NoError(t, idx.DeleteComment(ctx, "did:plc:commenter2", "comment2", subjectURI))
+	assert.NoError(t, idx.DeleteComment(ctx, "did:plc:commenter2", "comment2", subjectURI))
+	assert.Equal(t, 0, idx.GetCommentCount(ctx, subjectURI))
+}
+
+func TestUpsertComment_Edit(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	subjectURI := "at://did:plc:user1/social.arabica.alpha.brew/abc123"
+	createdAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, idx.UpsertComment(ctx, "did:plc:commenter1", "comment1", subjectURI, "", "cid1", "Original", createdAt, time.Time{}))
+	comments := idx.GetCommentsForSubject(ctx, subjectURI, 0, "")
+	assert.Len(t, comments, 1)
+	assert.True(t, comments[0].EditedAt.IsZero())
+
+	editedAt := createdAt.Add(time.Hour)
+	assert.NoError(t, idx.UpsertComment(ctx, "did:plc:commenter1", "comment1", subjectURI, "", "cid1", "Fixed a typo", createdAt, editedAt))
+	comments = idx.GetCommentsForSubject(ctx, subjectURI, 0, "")
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "Fixed a typo", comments[0].Text)
+	assert.True(t, createdAt.Equal(comments[0].CreatedAt))
+	assert.True(t, editedAt.Equal(comments[0].EditedAt))
+	assert.Equal(t, 1, idx.GetCommentCount(ctx, subjectURI))
+	assert.Equal(t, "cid1", idx.GetCommentCID("did:plc:commenter1", "comment1"))
+}
+
+func TestCommentLikes(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	subjectURI := "at://did:plc:user1/social.arabica.alpha.brew/abc123"
+	commentURI := "at://did:plc:commenter1/social.arabica.alpha.comment/comment1"
+
+	assert.NoError(t, idx.UpsertComment(ctx, "did:plc:commenter1", "comment1", subjectURI, "", "cid1", "Nice brew", time.Now(), time.Time{}))
+	assert.NoError(t, idx.UpsertComment(ctx, "did:plc:commenter2", "comment2", subjectURI, "", "cid2", "Agreed", time.Now().Add(time.Second), time.Time{}))
+
+	assert.NoError(t, idx.UpsertLike(ctx, "did:plc:viewer", "like1", commentURI))
+	assert.NoError(t, idx.UpsertLike(ctx, "did:plc:other", "like2", commentURI))
+
+	comments := idx.GetThreadedCommentsForSubject(ctx, subjectURI, 100, "did:plc:viewer")
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "comment1", comments[0].RKey)
+	assert.Equal(t, 2, comments[0].LikeCount)
+	assert.True(t, comments[0].IsLiked)
+	assert.Equal(t, 0, comments[1].LikeCount)
+	assert.False(t, comments[1].IsLiked)
+
+	// Likes on a comment do not count towards the subject record
+	assert.Equal(t, 0, idx.GetLikeCount(ctx, subjectURI))
+
+	assert.NoError(t, idx.DeleteLike(ctx, "did:plc:viewer", commentURI))
+	comments = idx.GetThreadedCommentsForSubject(ctx, subjectURI, 100, "did:plc:viewer")
+	assert.Equal(t, 1, comments[0].LikeCount)
+	assert.False(t, comments[0].IsLiked)
+}
+
+func TestBookmarks(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	subjectURI := "at://did:plc:author/social.arabica.alpha.brew/abc123"
+
+	assert.NoError(t, idx.UpsertBookmark(ctx, "did:plc:viewer", "bm1", subjectURI))
+	assert.Equal(t, "bm1", idx.GetUserBookmarkRKey(ctx, "did:plc:viewer", subjectURI))
+	assert.Empty(t, idx.GetUserBookmarkRKey(ctx, "did:plc:other", subjectURI))
+
+	// Re-bookmarking the same subject replaces the old rkey
+	assert.NoError(t, idx.UpsertBookmark(ctx, "did:plc:viewer", "bm2", subjectURI))
+	assert.Equal(t, "bm2", idx.GetUserBookmarkRKey(ctx, "did:plc:viewer", subjectURI))
+
+	// Deleting is idempotent
+	assert.NoError(t, idx.DeleteBookmark(ctx, "did:plc:viewer", "bm2"))
+	assert.NoError(t, idx.DeleteBookmark(ctx, "did:plc:viewer", "bm2"))
+	assert.Empty(t, idx.GetUserBookmarkRKey(ctx, "did:plc:viewer", subjectURI))
+}
+
+func TestGetLikers(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	subjectURI := "at://did:plc:author/social.arabica.alpha.brew/abc123"
+
+	for _, did := range []string{"did:plc:c", "did:plc:a", "did:plc:b"} {
+		assert.NoError(t, idx.UpsertLike(ctx, did, "like-"+did, subjectURI))
+	}
+	assert.NoError(t, idx.UpsertLike(ctx, "did:plc:z", "other", "at://did:plc:author/social.arabica.alpha.brew/other"))
+
+	tests := []struct {
+		name  string
+		limit int
+		after string
+		want  []string
+	}{
+		{"first page", 2, "", []string{"did:plc:a", "did:plc:b"}},
+		{"next page", 2, "did:plc:b", []string{"did:plc:c"}},
+		{"past the end", 2, "did:plc:c", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, idx.GetLikers(ctx, subjectURI, tt.limit, tt.after))
+		})
+	}
+}
+
+func TestListRoasterBeans(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	n := 0
+	upsert := func(did, collection, rkey, fields string) string {
+		n++
+		createdAt := now.Add(time.Duration(n) * time.Minute).UTC().Format(time.RFC3339)
+		record := fmt.Appendf(nil, `{"$type":%q,%s,"createdAt":%q}`, collection, fields, createdAt)
+		require.NoError(t, idx.UpsertRecord(ctx, did, collection, rkey, "cid-"+rkey, record, time.Now().Unix()))
+		return "at://" + did + "/" + collection + "/" + rkey
+	}
+	const roasters, beans = "social.arabica.alpha.roaster", "social.arabica.alpha.bean"
+
+	aliceOnyx := upsert("did:plc:alice", roasters, "r1", `"name":"Onyx Coffee Lab"`)
+	bobOnyx := upsert("did:plc:bob", roasters, "r2", `"name":"  onyx coffee lab "`)
+	other := upsert("did:plc:bob", roasters, "r3", `"name":"Sey"`)
+
+	aliceBean := upsert("did:plc:alice", beans, "b1", fmt.Sprintf(`"name":"Monarch","roasterRef":%q`, aliceOnyx))
+	bobBean := upsert("did:plc:bob", beans, "b2", fmt.Sprintf(`"name":"monarch","roasterRef":%q`, bobOnyx))
+	upsert("did:plc:bob", beans, "b3", fmt.Sprintf(`"name":"Gesha","roasterRef":%q`, other))
+	hidden := upsert("did:plc:carol", beans, "b4", fmt.Sprintf(`"name":"Hidden","roasterRef":%q`, aliceOnyx))
+	_, err := idx.DB().ExecContext(ctx, `INSERT INTO moderation_hidden_records (uri, hidden_at, hidden_by) VALUES (?, ?, 'mod')`, hidden, now.Format(time.RFC3339))
+	require.NoError(t, err)
+
+	uris := func(recs []IndexedRecord) []string {
+		out := []string{}
+		for _, r := range recs {
+			out = append(out, r.URI)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		roaster string
+		want    []string
+	}{
+		{"same-name roasters from other users", aliceOnyx, []string{bobBean, aliceBean}},
+		{"works from either side", bobOnyx, []string{bobBean, aliceBean}},
+		{"different roaster", other, []string{"at://did:plc:bob/social.arabica.alpha.bean/b3"}},
+		{"unindexed roaster", "at://did:plc:x/social.arabica.alpha.roaster/none", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recs, err := idx.ListRoasterBeans(ctx, tt.roaster, 0)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, uris(recs))
+		})
+	}
+}
+
 func TestCommentThreading_DepthCap(t *testing.T) {
 	tmpDir := t.TempDir()
 	idx, err := NewFeedIndex(tmpDir+"/test.db", 1*time.Hour)
@@ -184,7 +691,7 @@ func TestCommentThreading_DepthCap(t *testing.T) {
 	parentURI := ""
 	for i := range 5 {
 		rkey := "comment" + string(rune('A'+i))
-		err = idx.UpsertComment(ctx, "did:plc:user", rkey, subjectURI, parentURI, "cid"+rkey, "Comment", now.Add(time.Duration(i)*time.Second))
+		err = idx.UpsertComment(ctx, "did:plc:user", rkey, subjectURI, parentURI, "cid"+rkey, "Comment", now.Add(time.Duration(i)*time.Second), time.Time{})
 		assert.NoError(t, err)
 		parentURI = "at://did:plc:user/social.arabica.alpha.comment/" + rkey
 	}
@@ -213,17 +720,17 @@ func TestCommentThreading_MultipleTopLevel(t *testing.T) {
 	now := time.Now()
 
 	// Create two top-level comments
-	err = idx.UpsertComment(ctx, "did:plc:user1", "topA", subjectURI, "", "cidA", "First top comment", now)
+	err = idx.UpsertComment(ctx, "did:plc:user1", "topA", subjectURI, "", "cidA", "First top comment", now, time.Time{})
 	assert.NoError(t, err)
-	err = idx.UpsertComment(ctx, "did:plc:user2", "topB", subjectURI, "", "cidB", "Second top comment", now.Add(5*time.Second))
+	err = idx.UpsertComment(ctx, "did:plc:user2", "topB", subjectURI, "", "cidB", "Second top comment", now.Add(5*time.Second), time.Time{})
 	assert.NoError(t, err)
 
 	// Reply to first top-level comment
-	err = idx.UpsertComment(ctx, "did:plc:user3", "replyA1", subjectURI, "at://did:plc:user1/social.arabica.alpha.comment/topA", "cidA1", "Reply to first", now.Add(2*time.Second))
+	err = idx.UpsertComment(ctx, "did:plc:user3", "replyA1", subjectURI, "at://did:plc:user1/social.arabica.alpha.comment/topA", "cidA1", "Reply to first", now.Add(2*time.Second), time.Time{})
 	assert.NoError(t, err)
 
 	// Reply to second top-level comment
-	err = idx.UpsertComment(ctx, "did:plc:user4", "replyB1", subjectURI, "at://did:plc:user2/social.arabica.alpha.comment/topB", "cidB1", "Reply to second", now.Add(6*time.Second))
+	err = idx.UpsertComment(ctx, "did:plc:user4", "replyB1", subjectURI, "at://did:plc:user2/social.arabica.alpha.comment/topB", "cidB1", "Reply to second", now.Add(6*time.Second), time.Time{})
 	assert.NoError(t, err)
 
 	// Get threaded comments
@@ -568,6 +1075,52 @@ func TestDeleteRecord_DoesNotAffectOtherRecords(t *testing.T) {
 	assert.Equal(t, "bean2", records[0].RKey)
 }
 
+func TestDeleteRecordCascade(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	owner := "did:plc:owner"
+	fan := "did:plc:fan"
+	now := time.Now().Unix()
+
+	brew := []byte(`{"$type":"social.arabica.alpha.brew","createdAt":"2025-01-02T00:00:00Z"}`)
+	assert.NoError(t, idx.UpsertRecord(ctx, owner, "social.arabica.alpha.brew", "br1", "cid1", brew, now))
+	assert.NoError(t, idx.UpsertRecord(ctx, owner, "social.arabica.alpha.brew", "br2", "cid2", brew, now))
+	deletedURI := "at://" + owner + "/social.arabica.alpha.brew/br1"
+	keptURI := "at://" + owner + "/social.arabica.alpha.brew/br2"
+
+	assert.NoError(t, idx.UpsertLike(ctx, fan, "lk1", deletedURI))
+	assert.NoError(t, idx.UpsertLike(ctx, fan, "lk2", keptURI))
+	assert.NoError(t, idx.UpsertComment(ctx, fan, "c1", deletedURI, "", "cidc1", "nice", time.Now(), time.Time{}))
+	assert.NoError(t, idx.UpsertComment(ctx, fan, "c2", keptURI, "", "cidc2", "also nice", time.Now(), time.Time{}))
+	assert.NoError(t, idx.UpsertBookmark(ctx, fan, "bm1", deletedURI))
+	assert.NoError(t, idx.UpsertBookmark(ctx, fan, "bm2", keptURI))
+	idx.CreateLikeNotification(fan, deletedURI)
+
+	assert.NoError(t, idx.DeleteRecordCascade(ctx, deletedURI))
+
+	rec, err := idx.GetRecord(ctx, deletedURI)
+	assert.NoError(t, err)
+	assert.Nil(t, rec)
+	assert.Equal(t, 0, idx.GetLikeCount(ctx, deletedURI))
+	assert.Equal(t, 0, idx.GetCommentCount(ctx, deletedURI))
+	assert.False(t, idx.HasUserLiked(ctx, fan, deletedURI))
+
+	var residual int
+	assert.NoError(t, idx.db.QueryRowContext(ctx,
+		`SELECT (SELECT COUNT(*) FROM likes WHERE subject_uri = ?) +
+		        (SELECT COUNT(*) FROM comments WHERE subject_uri = ?) +
+		        (SELECT COUNT(*) FROM bookmarks WHERE subject_uri = ?) +
+		        (SELECT COUNT(*) FROM notifications WHERE subject_uri = ?)`,
+		deletedURI, deletedURI, deletedURI, deletedURI).Scan(&residual))
+	assert.Zero(t, residual, "no like, comment, bookmark or notification rows should point at the deleted brew")
+
+	assert.Equal(t, 1, idx.GetLikeCount(ctx, keptURI))
+	assert.Equal(t, 1, idx.GetCommentCount(ctx, keptURI))
+	assert.Equal(t, "bm2", idx.GetUserBookmarkRKey(ctx, fan, keptURI))
+
+	assert.Error(t, idx.DeleteRecordCascade(ctx, "not-a-uri"))
+}
+
 func TestDeleteRecord_NonexistentIsNoOp(t *testing.T) {
 	tmpDir := t.TempDir()
 	idx, err := NewFeedIndex(tmpDir+"/test.db", 1*time.Hour)
@@ -652,9 +1205,9 @@ func TestDeleteAllByDID(t *testing.T) {
 
 	// Comments mirroring the like pattern
 	createdAt := time.Now()
-	assert.NoError(t, idx.UpsertComment(ctx, target, "c1", otherBeanURI, "", "cidc1", "by target", createdAt))
-	assert.NoError(t, idx.UpsertComment(ctx, other, "c2", targetBeanURI, "", "cidc2", "on target", createdAt))
-	assert.NoError(t, idx.UpsertComment(ctx, other, "c3", otherBeanURI, "", "cidc3", "untouched", createdAt))
+	assert.NoError(t, idx.UpsertComment(ctx, target, "c1", otherBeanURI, "", "cidc1", "by target", createdAt, time.Time{}))
+	assert.NoError(t, idx.UpsertComment(ctx, other, "c2", targetBeanURI, "", "cidc2", "on target", createdAt, time.Time{}))
+	assert.NoError(t, idx.UpsertComment(ctx, other, "c3", otherBeanURI, "", "cidc3", "untouched", createdAt, time.Time{}))
 
 	// Backfill marker for target
 	assert.NoError(t, idx.MarkBackfilled(ctx, target))
@@ -710,3 +1263,19 @@ func TestDeleteAllByDID_NoData(t *testing.T) {
 	// Should be a no-op for an unknown DID
 	assert.NoError(t, idx.DeleteAllByDID(context.Background(), "did:plc:ghost"))
 }
+
+func TestActiveDIDCount(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+
+	require.NoError(t, idx.UpsertRecord(ctx, "did:plc:brewer", arabica.NSIDBean, "b1", "cid-b1",
+		[]byte(`{"$type":"`+arabica.NSIDBean+`","name":"House","createdAt":"2026-01-01T00:00:00Z"}`), time.Now().Unix()))
+	require.NoError(t, idx.UpsertRecord(ctx, "did:plc:brewer", arabica.NSIDBean, "b2", "cid-b2",
+		[]byte(`{"$type":"`+arabica.NSIDBean+`","name":"Guest","createdAt":"2026-01-01T00:00:00Z"}`), time.Now().Unix()))
+	// A DID that has only liked something is seen but not active.
+	require.NoError(t, idx.UpsertRecord(ctx, "did:plc:lurker", arabica.NSIDLike, "l1", "cid-l1",
+		[]byte(`{"$type":"`+arabica.NSIDLike+`","subject":{"uri":"at://did:plc:brewer/`+arabica.NSIDBean+`/b1"},"createdAt":"2026-01-01T00:00:00Z"}`), time.Now().Unix()))
+
+	assert.Equal(t, 2, idx.KnownDIDCount())
+	assert.Equal(t, 1, idx.ActiveDIDCount())
+}
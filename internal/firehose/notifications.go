@@ -127,6 +127,18 @@ func (idx *FeedIndex) GetCommentSubjectURI(actorDID, rkey string) string {
 	return subjectURI
 }
 
+// GetCommentCID returns the indexed CID for a comment by actor+rkey.
+// Returns empty string if not found.
+func (idx *FeedIndex) GetCommentCID(actorDID, rkey string) string {
+	var cid string
+	err := idx.db.QueryRow(`SELECT cid FROM comments WHERE actor_did = ? AND rkey = ?`,
+		actorDID, rkey).Scan(&cid)
+	if err != nil {
+		return ""
+	}
+	return cid
+}
+
 // CreateLikeNotification creates a notification for a like event
 func (idx *FeedIndex) CreateLikeNotification(actorDID, subjectURI string) {
 	targetDID := parseTargetDID(subjectURI)
@@ -0,0 +1,69 @@
+package firehose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"tangled.org/pdewey.com/atp"
+)
+
+// RecordRefreshCooldown is the minimum time between two PDS fetches for the
+// same record through RefreshRecord.
+const RecordRefreshCooldown = 10 * time.Minute
+
+// maxRefreshEntries bounds the cooldown map; past it, expired entries are
+// swept before a new one is added.
+const maxRefreshEntries = 10000
+
+// RefreshRecord re-fetches the record at uri from its owner's PDS and writes
+// it back into the index. It covers edits the firehose missed, so callers
+// can use it when they notice an indexed record is old. Calls for a URI
+// refreshed within RecordRefreshCooldown return nil without fetching.
+func (idx *FeedIndex) RefreshRecord(ctx context.Context, uri string) error {
+	parsed, err := atp.ParseATURI(uri)
+	if err != nil {
+		return fmt.Errorf("parse uri: %w", err)
+	}
+	if !idx.claimRefresh(uri, time.Now()) {
+		return nil
+	}
+
+	fetched, err := idx.getPublicRecord(ctx, parsed.DID, parsed.Collection, parsed.RKey)
+	if err != nil {
+		return fmt.Errorf("fetch %s: %w", uri, err)
+	}
+	record, err := json.Marshal(fetched.Value)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", uri, err)
+	}
+
+	// An unchanged record only needs its indexed_at bumped; skipping the
+	// full upsert avoids re-running the explore and tag indexing.
+	if existing, err := idx.GetRecord(ctx, uri); err == nil && existing != nil && fetched.CID != "" && existing.CID == fetched.CID {
+		_, err := idx.witness.update(ctx, parsed.DID, parsed.Collection, parsed.RKey, record)
+		return err
+	}
+	return idx.UpsertRecord(ctx, parsed.DID, parsed.Collection, parsed.RKey, fetched.CID, record, time.Now().UnixMicro())
+}
+
+// claimRefresh reports whether uri may be refreshed at now, and if so starts
+// its cooldown. The claim happens before the fetch so concurrent views of
+// the same record only trigger one.
+func (idx *FeedIndex) claimRefresh(uri string, now time.Time) bool {
+	idx.refreshMu.Lock()
+	defer idx.refreshMu.Unlock()
+	if last, ok := idx.refreshedAt[uri]; ok && now.Sub(last) < RecordRefreshCooldown {
+		return false
+	}
+	if len(idx.refreshedAt) >= maxRefreshEntries {
+		for u, last := range idx.refreshedAt {
+			if now.Sub(last) >= RecordRefreshCooldown {
+				delete(idx.refreshedAt, u)
+			}
+		}
+	}
+	idx.refreshedAt[uri] = now
+	return true
+}
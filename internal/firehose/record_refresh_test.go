@@ -0,0 +1,81 @@
+package firehose
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/pdewey.com/atp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshRecord(t *testing.T) {
+	ctx := context.Background()
+	did := "did:plc:refresher"
+
+	tests := []struct {
+		name     string
+		cid      string
+		fetchErr error
+		wantName string
+		wantCID  string
+		wantErr  bool
+	}{
+		{"edited on the PDS", "cid-2", nil, "Edited Bean", "cid-2", false},
+		{"unchanged cid still takes the fetched body", "cid-1", nil, "Edited Bean", "cid-1", false},
+		{"fetch error leaves the index alone", "", errors.New("pds unreachable"), "Old Bean", "cid-1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := newTestIndex(t)
+			old := []byte(`{"$type":"` + arabica.NSIDBean + `","name":"Old Bean","createdAt":"2026-01-02T03:04:05Z"}`)
+			require.NoError(t, idx.UpsertRecord(ctx, did, arabica.NSIDBean, "bean1", "cid-1", old, time.Now().Unix()))
+			uri := atp.BuildATURI(did, arabica.NSIDBean, "bean1")
+
+			fetches := 0
+			idx.getPublicRecord = func(_ context.Context, gotDID, collection, rkey string) (*atp.Record, error) {
+				fetches++
+				assert.Equal(t, did, gotDID)
+				assert.Equal(t, arabica.NSIDBean, collection)
+				assert.Equal(t, "bean1", rkey)
+				if tt.fetchErr != nil {
+					return nil, tt.fetchErr
+				}
+				return &atp.Record{URI: uri, CID: tt.cid, Value: map[string]any{
+					"$type": arabica.NSIDBean, "name": "Edited Bean", "createdAt": "2026-01-02T03:04:05Z",
+				}}, nil
+			}
+
+			err := idx.RefreshRecord(ctx, uri)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			rec, err := idx.GetRecord(ctx, uri)
+			require.NoError(t, err)
+			require.NotNil(t, rec)
+			assert.Contains(t, string(rec.Record), tt.wantName)
+			assert.Equal(t, tt.wantCID, rec.CID)
+
+			// A second view inside the cooldown doesn't hit the PDS again.
+			require.NoError(t, idx.RefreshRecord(ctx, uri))
+			assert.Equal(t, 1, fetches)
+		})
+	}
+}
+
+func TestClaimRefreshCooldown(t *testing.T) {
+	idx := newTestIndex(t)
+	now := time.Now()
+
+	assert.True(t, idx.claimRefresh("at://a", now))
+	assert.False(t, idx.claimRefresh("at://a", now.Add(RecordRefreshCooldown-time.Second)))
+	assert.True(t, idx.claimRefresh("at://b", now), "cooldown is per record")
+	assert.True(t, idx.claimRefresh("at://a", now.Add(RecordRefreshCooldown)))
+}
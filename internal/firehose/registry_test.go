@@ -0,0 +1,37 @@
+package firehose
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/feed"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentRegistryUnregisterSurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.db")
+
+	idx, err := NewFeedIndex(path, time.Hour)
+	require.NoError(t, err)
+	registry := feed.NewPersistentRegistry(idx)
+	registry.Register("did:plc:alice")
+	registry.Register("did:plc:bob")
+	require.Equal(t, 2, registry.Count())
+
+	require.NoError(t, registry.Unregister("did:plc:alice"))
+	assert.False(t, registry.IsRegistered("did:plc:alice"))
+	assert.Equal(t, 1, registry.Count())
+	require.NoError(t, idx.Close())
+
+	reopened, err := NewFeedIndex(path, time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { reopened.Close() })
+	registry = feed.NewPersistentRegistry(reopened)
+
+	assert.False(t, registry.IsRegistered("did:plc:alice"))
+	assert.True(t, registry.IsRegistered("did:plc:bob"))
+	assert.Equal(t, 1, registry.Count())
+}
@@ -0,0 +1,244 @@
+package firehose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"tangled.org/pdewey.com/atp"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrReindexRunning is returned by StartReindex while an earlier rebuild is
+// still going.
+var ErrReindexRunning = errors.New("reindex already running")
+
+// ReindexStatus is a snapshot of the most recent full index rebuild.
+type ReindexStatus struct {
+	Running    bool      `json:"running"`
+	StartedAt  time.Time `json:"startedAt,omitzero"`
+	FinishedAt time.Time `json:"finishedAt,omitzero"`
+	Total      int       `json:"total"`
+	Done       int       `json:"done"`
+	Failed     int       `json:"failed"`
+	Records    int       `json:"records"`
+	LastError  string    `json:"lastError,omitempty"`
+}
+
+// IndexedDIDs returns every DID the index knows of: registered users,
+// DIDs seen on the firehose or backfilled, and authors of any stored
+// record.
+func (idx *FeedIndex) IndexedDIDs(ctx context.Context) ([]string, error) {
+	rows, err := idx.db.QueryContext(ctx, `
+		SELECT did FROM registered_dids
+		UNION SELECT did FROM known_dids
+		UNION SELECT did FROM backfilled
+		UNION SELECT did FROM records
+		ORDER BY did`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dids []string
+	for rows.Next() {
+		var did string
+		if err := rows.Scan(&did); err != nil {
+			return nil, err
+		}
+		dids = append(dids, did)
+	}
+	return dids, rows.Err()
+}
+
+// rebuildDID replaces what the index holds for did in collections with what
+// its PDS has now, returning how many records were written. Everything is
+// fetched before anything is deleted, so a PDS that can't be reached leaves
+// the existing rows alone. Rows others wrote about did, such as comments on
+// its brews, and notifications and settings are kept. Explore rows of
+// records that are gone are left for RebuildExploreIndex.
+func (idx *FeedIndex) rebuildDID(ctx context.Context, did string, collections []string) (int, error) {
+	fetched := make(map[string][]atp.Record, len(collections))
+	for _, collection := range collections {
+		recs, err := idx.listAllRecords(ctx, did, collection)
+		if err != nil {
+			return 0, fmt.Errorf("list %s: %w", collection, err)
+		}
+		fetched[collection] = recs
+	}
+
+	if err := idx.clearAuthoredRows(ctx, did, collections); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, collection := range collections {
+		for _, record := range fetched[collection] {
+			if err := idx.indexBackfilledRecord(ctx, did, collection, record); err != nil {
+				log.Warn().Err(err).Str("uri", record.URI).Msg("failed to upsert record during rebuild")
+				continue
+			}
+			count++
+		}
+	}
+	return count, idx.MarkBackfilled(ctx, did)
+}
+
+// clearAuthoredRows deletes did's records in collections, the social rows
+// derived from them, and its backfill marker, in one transaction.
+func (idx *FeedIndex) clearAuthoredRows(ctx context.Context, did string, collections []string) error {
+	if len(collections) == 0 {
+		return nil
+	}
+	tx, err := idx.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	placeholders := make([]string, len(collections))
+	args := []any{did}
+	for i, collection := range collections {
+		placeholders[i] = "?"
+		args = append(args, collection)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM records WHERE did = ? AND collection IN (`+strings.Join(placeholders, ",")+`)`, args...); err != nil {
+		return fmt.Errorf("clear records for %s: %w", did, err)
+	}
+
+	stmts := []string{`DELETE FROM backfilled WHERE did = ?`}
+	for _, collection := range collections {
+		switch {
+		case strings.HasSuffix(collection, ".like"):
+			stmts = append(stmts, `DELETE FROM likes WHERE actor_did = ?`)
+		case strings.HasSuffix(collection, ".bookmark"):
+			stmts = append(stmts, `DELETE FROM bookmarks WHERE actor_did = ?`)
+		case strings.HasSuffix(collection, ".follow"):
+			stmts = append(stmts, `DELETE FROM follows WHERE follower_did = ?`)
+		case strings.HasSuffix(collection, ".comment"):
+			stmts = append(stmts, `DELETE FROM comments WHERE actor_did = ?`)
+		}
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt, did); err != nil {
+			return fmt.Errorf("clear rows for %s: %w", did, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// reindexJob holds the state of the consumer's full rebuild.
+type reindexJob struct {
+	mu     sync.Mutex
+	status ReindexStatus
+}
+
+func (j *reindexJob) update(mutate func(*ReindexStatus)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	mutate(&j.status)
+}
+
+// ReindexStatus returns the progress of the running rebuild, or the outcome
+// of the last one. The zero value means no rebuild has run since startup.
+func (c *Consumer) ReindexStatus() ReindexStatus {
+	c.reindex.mu.Lock()
+	defer c.reindex.mu.Unlock()
+	return c.reindex.status
+}
+
+// StartReindex rebuilds the index for dids from their PDSes in the
+// background and returns at once; progress is read with ReindexStatus.
+// Each DID is replaced in place with rebuildDID rather than swapping in a
+// fresh database, which keeps moderation, notification, and settings data
+// that lives in the same file; only the DID being refilled is briefly
+// missing from the site. When every DID is done the
+// explore index is rebuilt to drop rows for records that no longer exist.
+// ctx bounds the whole run.
+func (c *Consumer) StartReindex(ctx context.Context, dids []string) error {
+	c.reindex.mu.Lock()
+	if c.reindex.status.Running {
+		c.reindex.mu.Unlock()
+		return ErrReindexRunning
+	}
+	c.reindex.status = ReindexStatus{Running: true, StartedAt: time.Now().UTC(), Total: len(dids)}
+	c.reindex.mu.Unlock()
+
+	go c.runReindex(ctx, dids)
+	return nil
+}
+
+func (c *Consumer) runReindex(ctx context.Context, dids []string) {
+	start := time.Now()
+	log.Warn().Int("dids", len(dids)).Msg("reindex: starting full rebuild")
+
+	workers := c.config.BackfillWorkers
+	if workers <= 0 {
+		workers = DefaultBackfillWorkers
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	for _, did := range dids {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			n, err := c.rebuildDID(ctx, did)
+			c.reindex.update(func(s *ReindexStatus) {
+				s.Done++
+				s.Records += n
+				if err != nil {
+					s.Failed++
+					s.LastError = fmt.Sprintf("%s: %v", did, err)
+				}
+			})
+			if err != nil {
+				log.Warn().Err(err).Str("did", did).Msg("reindex: failed to rebuild DID")
+			}
+		})
+	}
+	wg.Wait()
+
+	var exploreErr error
+	if ctx.Err() == nil {
+		exploreErr = c.index.RebuildExploreIndex(ctx)
+	}
+	c.reindex.update(func(s *ReindexStatus) {
+		s.Running = false
+		s.FinishedAt = time.Now().UTC()
+		switch {
+		case ctx.Err() != nil:
+			s.LastError = fmt.Sprintf("stopped early: %v", ctx.Err())
+		case exploreErr != nil:
+			s.LastError = fmt.Sprintf("rebuild explore index: %v", exploreErr)
+		}
+	})
+
+	status := c.ReindexStatus()
+	log.Warn().
+		Int("done", status.Done).
+		Int("failed", status.Failed).
+		Int("records", status.Records).
+		Dur("duration", time.Since(start)).
+		Msg("reindex: full rebuild finished")
+}
+
+// rebuildDID runs FeedIndex.rebuildDID for the consumer's collections while
+// holding the DID's backfill slot, so a login-triggered backfill can't
+// interleave with the delete and refill.
+func (c *Consumer) rebuildDID(ctx context.Context, did string) (int, error) {
+	if !c.claimBackfill(did) {
+		return 0, ErrBackfillInProgress
+	}
+	defer c.releaseBackfill(did)
+	return c.index.rebuildDID(ctx, did, c.config.WantedCollections)
+}
@@ -0,0 +1,74 @@
+package firehose
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+	"tangled.org/pdewey.com/atp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartReindex(t *testing.T) {
+	ctx := context.Background()
+	idx := newTestIndex(t)
+	const (
+		alice = "did:plc:alice"
+		down  = "did:plc:down"
+	)
+	upsert := func(did, collection, rkey, body string) string {
+		require.NoError(t, idx.UpsertRecord(ctx, did, collection, rkey, "cid-"+rkey, []byte(body), time.Now().Unix()))
+		return atp.BuildATURI(did, collection, rkey)
+	}
+
+	// alice deleted "stale" and a like while the firehose wasn't looking;
+	// the PDS now only has "kept".
+	stale := upsert(alice, arabica.NSIDBean, "stale", `{"$type":"`+arabica.NSIDBean+`","name":"Gone","createdAt":"2026-01-01T00:00:00Z"}`)
+	kept := atp.BuildATURI(alice, arabica.NSIDBean, "kept")
+	require.NoError(t, idx.UpsertLike(ctx, alice, "like1", stale))
+	// down's PDS fails, so its records must survive the rebuild.
+	downURI := upsert(down, arabica.NSIDBean, "b1", `{"$type":"`+arabica.NSIDBean+`","name":"Still Here","createdAt":"2026-01-01T00:00:00Z"}`)
+
+	idx.listAllRecords = func(_ context.Context, did, collection string) ([]atp.Record, error) {
+		if did == down {
+			return nil, errors.New("pds unreachable")
+		}
+		if collection != arabica.NSIDBean {
+			return nil, nil
+		}
+		return []atp.Record{{URI: kept, CID: "cid-kept", Value: map[string]any{
+			"$type": arabica.NSIDBean, "name": "Kept", "createdAt": "2026-01-02T00:00:00Z",
+		}}}, nil
+	}
+
+	dids, err := idx.IndexedDIDs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{alice, down}, dids)
+
+	cfg := DefaultConfig()
+	cfg.WantedCollections = []string{arabica.NSIDBean, arabica.NSIDLike}
+	consumer := NewConsumer(cfg, idx)
+	require.NoError(t, consumer.StartReindex(ctx, dids))
+	assert.ErrorIs(t, consumer.StartReindex(ctx, dids), ErrReindexRunning)
+
+	require.Eventually(t, func() bool { return !consumer.ReindexStatus().Running }, 5*time.Second, 10*time.Millisecond)
+	status := consumer.ReindexStatus()
+	assert.Equal(t, 2, status.Total)
+	assert.Equal(t, 2, status.Done)
+	assert.Equal(t, 1, status.Failed)
+	assert.Equal(t, 1, status.Records)
+	assert.Contains(t, status.LastError, down)
+	assert.False(t, status.FinishedAt.IsZero())
+
+	for uri, want := range map[string]bool{stale: false, kept: true, downURI: true} {
+		rec, err := idx.GetRecord(ctx, uri)
+		require.NoError(t, err)
+		assert.Equal(t, want, rec != nil, uri)
+	}
+	assert.False(t, idx.HasUserLiked(ctx, alice, stale))
+	assert.True(t, idx.IsBackfilled(ctx, alice))
+}
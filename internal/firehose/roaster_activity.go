@@ -0,0 +1,119 @@
+package firehose
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Active roaster discovery settings. Counts cover beans logged within
+// ActiveRoasterWindow; the cached list is rebuilt once it is older than
+// activeRoastersTTL.
+const (
+	ActiveRoasterWindow     = 90 * 24 * time.Hour
+	maxActiveRoasters       = 50
+	activeRoastersTTL       = time.Hour
+	activeRoastersBuildTime = 30 * time.Second
+)
+
+// RoasterActivity summarizes the beans logged against one roaster name.
+type RoasterActivity struct {
+	Name       string
+	RoasterURI string // roaster record behind the most recent bean, for linking
+	UserCount  int
+	BeanCount  int
+	LastBeanAt time.Time
+}
+
+// activeRoastersCache holds the last ActiveRoasters result.
+type activeRoastersCache struct {
+	mu          sync.Mutex
+	roasters    []RoasterActivity
+	generatedAt time.Time
+	refreshing  bool
+}
+
+// ListActiveRoasters ranks roasters by how many distinct users logged a bean
+// from them since since, then by bean count. Roasters are per-user records,
+// so they are grouped on the trimmed, case-folded name the same way
+// ListRoasterBeans matches them. Hidden beans and blacklisted authors are
+// left out.
+func (idx *FeedIndex) ListActiveRoasters(ctx context.Context, since time.Time, limit int) ([]RoasterActivity, error) {
+	if limit <= 0 {
+		limit = maxActiveRoasters
+	}
+	rows, err := idx.db.QueryContext(ctx, `
+		SELECT trim(json_extract(r.record, '$.name')), r.uri,
+		       COUNT(DISTINCT b.did), COUNT(*), MAX(b.created_at)
+		FROM records b
+		JOIN records r ON r.uri = json_extract(b.record, '$.roasterRef')
+		WHERE b.collection = 'social.arabica.alpha.bean'
+		  AND r.collection = 'social.arabica.alpha.roaster'
+		  AND trim(coalesce(json_extract(r.record, '$.name'), '')) != ''
+		  AND julianday(b.created_at) >= julianday(?)
+		  AND b.uri NOT IN (SELECT uri FROM moderation_hidden_records)
+		  AND b.did NOT IN (SELECT did FROM moderation_blacklist)
+		  AND r.did NOT IN (SELECT did FROM moderation_blacklist)
+		GROUP BY lower(trim(json_extract(r.record, '$.name')))
+		ORDER BY COUNT(DISTINCT b.did) DESC, COUNT(*) DESC, MAX(b.created_at) DESC
+		LIMIT ?
+	`, since.UTC().Format(time.RFC3339Nano), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []RoasterActivity
+	for rows.Next() {
+		var a RoasterActivity
+		var lastBeanAt string
+		if err := rows.Scan(&a.Name, &a.RoasterURI, &a.UserCount, &a.BeanCount, &lastBeanAt); err != nil {
+			return nil, err
+		}
+		a.LastBeanAt, _ = time.Parse(time.RFC3339Nano, lastBeanAt)
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// ActiveRoasters returns the cached discovery list, building it on first
+// use. A list older than activeRoastersTTL keeps being served while a
+// rebuild runs in the background.
+func (idx *FeedIndex) ActiveRoasters(ctx context.Context) ([]RoasterActivity, error) {
+	c := &idx.activeRoasters
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generatedAt.IsZero() {
+		roasters, err := idx.ListActiveRoasters(ctx, time.Now().Add(-ActiveRoasterWindow), maxActiveRoasters)
+		if err != nil {
+			return nil, err
+		}
+		c.roasters, c.generatedAt = roasters, time.Now()
+		return c.roasters, nil
+	}
+
+	if time.Since(c.generatedAt) > activeRoastersTTL && !c.refreshing {
+		c.refreshing = true
+		go idx.rebuildActiveRoasters()
+	}
+	return c.roasters, nil
+}
+
+func (idx *FeedIndex) rebuildActiveRoasters() {
+	ctx, cancel := context.WithTimeout(context.Background(), activeRoastersBuildTime)
+	defer cancel()
+	roasters, err := idx.ListActiveRoasters(ctx, time.Now().Add(-ActiveRoasterWindow), maxActiveRoasters)
+
+	c := &idx.activeRoasters
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshing = false
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to rebuild active roasters, keeping the previous list")
+		return
+	}
+	c.roasters, c.generatedAt = roasters, time.Now()
+}
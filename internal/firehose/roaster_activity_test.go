@@ -0,0 +1,91 @@
+package firehose
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListActiveRoasters(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	n := 0
+	upsert := func(did, collection, rkey string, createdAt time.Time, fields string) string {
+		n++
+		record := fmt.Appendf(nil, `{"$type":%q,%s,"createdAt":%q}`, collection, fields, createdAt.UTC().Format(time.RFC3339))
+		require.NoError(t, idx.UpsertRecord(ctx, did, collection, rkey, fmt.Sprintf("cid-%d", n), record, now.Unix()))
+		return "at://" + did + "/" + collection + "/" + rkey
+	}
+	const roasters, beans = "social.arabica.alpha.roaster", "social.arabica.alpha.bean"
+	bean := func(did, rkey, roasterURI string, age time.Duration) string {
+		return upsert(did, beans, rkey, now.Add(-age), fmt.Sprintf(`"name":"Bean","roasterRef":%q`, roasterURI))
+	}
+
+	aliceOnyx := upsert("did:plc:alice", roasters, "r1", now, `"name":"Onyx Coffee Lab"`)
+	bobOnyx := upsert("did:plc:bob", roasters, "r2", now, `"name":"  onyx coffee lab "`)
+	aliceSey := upsert("did:plc:alice", roasters, "r3", now, `"name":"Sey"`)
+	spamRoaster := upsert("did:plc:spam", roasters, "r4", now, `"name":"Spam Roasters"`)
+	upsert("did:plc:alice", roasters, "r5", now, `"name":"  "`)
+
+	bean("did:plc:alice", "b1", aliceOnyx, 3*time.Hour)
+	bean("did:plc:bob", "b2", bobOnyx, time.Hour)
+	bean("did:plc:alice", "b3", aliceSey, time.Hour)
+	bean("did:plc:alice", "b4", aliceSey, 2*time.Hour)
+	bean("did:plc:alice", "b5", aliceSey, 200*24*time.Hour) // outside the window
+	bean("did:plc:spam", "b6", spamRoaster, time.Hour)
+	bean("did:plc:spam", "b7", spamRoaster, time.Hour)
+	bean("did:plc:alice", "b8", "at://did:plc:alice/"+roasters+"/r5", time.Hour)
+	hidden := bean("did:plc:carol", "b9", aliceSey, time.Hour)
+
+	_, err := idx.DB().ExecContext(ctx, `INSERT INTO moderation_blacklist (did, blacklisted_at, blacklisted_by) VALUES ('did:plc:spam', ?, 'mod')`, now.Format(time.RFC3339))
+	require.NoError(t, err)
+	_, err = idx.DB().ExecContext(ctx, `INSERT INTO moderation_hidden_records (uri, hidden_at, hidden_by) VALUES (?, ?, 'mod')`, hidden, now.Format(time.RFC3339))
+	require.NoError(t, err)
+
+	got, err := idx.ListActiveRoasters(ctx, now.Add(-ActiveRoasterWindow), 0)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "onyx coffee lab", got[0].Name, "named after the roaster behind the latest bean")
+	assert.Equal(t, bobOnyx, got[0].RoasterURI)
+	assert.Equal(t, 2, got[0].UserCount)
+	assert.Equal(t, 2, got[0].BeanCount)
+	assert.WithinDuration(t, now.Add(-time.Hour), got[0].LastBeanAt, time.Second)
+
+	assert.Equal(t, "Sey", got[1].Name)
+	assert.Equal(t, 1, got[1].UserCount)
+	assert.Equal(t, 2, got[1].BeanCount)
+
+	limited, err := idx.ListActiveRoasters(ctx, now.Add(-ActiveRoasterWindow), 1)
+	require.NoError(t, err)
+	assert.Len(t, limited, 1)
+}
+
+func TestListActiveRoastersWindowComparesTimes(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	const roasters, beans = "social.arabica.alpha.roaster", "social.arabica.alpha.bean"
+
+	roaster := fmt.Appendf(nil, `{"$type":%q,"name":"Onyx","createdAt":"2025-06-01T00:00:00Z"}`, roasters)
+	require.NoError(t, idx.UpsertRecord(ctx, "did:plc:alice", roasters, "r1", "cid-r1", roaster, since.Unix()))
+	// As text, "00:00:00.5Z" sorts before "00:00:00Z" although it is later.
+	for rkey, at := range map[string]string{
+		"before": "2025-12-31T23:59:59.9Z",
+		"after":  "2026-01-01T00:00:00.5Z",
+	} {
+		bean := fmt.Appendf(nil, `{"$type":%q,"name":"Bean","roasterRef":"at://did:plc:alice/%s/r1","createdAt":%q}`, beans, roasters, at)
+		require.NoError(t, idx.UpsertRecord(ctx, "did:plc:alice", beans, rkey, "cid-"+rkey, bean, since.Unix()))
+	}
+
+	got, err := idx.ListActiveRoasters(ctx, since, 0)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, 1, got[0].BeanCount, "only the bean logged after the window start counts")
+}
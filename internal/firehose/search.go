@@ -0,0 +1,78 @@
+package firehose
+
+import (
+	"context"
+	"strings"
+
+	"tangled.org/arabica.social/arabica/internal/feed"
+	"tangled.org/arabica.social/arabica/internal/lexicons"
+)
+
+// MaxSearchResults caps SearchRecords regardless of the requested limit.
+const MaxSearchResults = 50
+
+// SearchRecords returns brews and beans matching query, newest first. Beans
+// match on name, origin or roaster name; brews match on tasting notes or on
+// the bean they reference, so searching "ethiopia" finds both the bean and
+// every brew made with it. Hidden records and blacklisted authors are
+// excluded.
+//
+// This is a linear scan over the brew, bean and roaster collections with
+// json_extract + LIKE rather than an FTS table. The index is small enough
+// that a scan stays well under the feed's latency budget, and it avoids a
+// second copy of every record that would need to be kept in sync with
+// creates, updates and deletes. Revisit with FTS5 if search shows up in
+// slow query logs.
+func (idx *FeedIndex) SearchRecords(ctx context.Context, query string, limit int) ([]*feed.FeedItem, error) {
+	query = strings.TrimSpace(query)
+	brewNSID := idx.recordTypeToNSID[lexicons.RecordTypeBrew]
+	beanNSID := idx.recordTypeToNSID[lexicons.RecordTypeBean]
+	if query == "" || (brewNSID == "" && beanNSID == "") {
+		return nil, nil
+	}
+	if limit <= 0 || limit > MaxSearchResults {
+		limit = MaxSearchResults
+	}
+	roasterNSID := idx.recordTypeToNSID[lexicons.RecordTypeRoaster]
+	pattern := exploreContainsPattern(query)
+
+	rows, err := idx.db.QueryContext(ctx, `
+		WITH matched_roasters AS (
+			SELECT uri FROM records
+			WHERE collection = ? AND lower(json_extract(record, '$.name')) LIKE ? ESCAPE '\'
+		),
+		matched_beans AS (
+			SELECT uri FROM records
+			WHERE collection = ? AND (
+				lower(json_extract(record, '$.name')) LIKE ? ESCAPE '\'
+				OR lower(json_extract(record, '$.origin')) LIKE ? ESCAPE '\'
+				OR json_extract(record, '$.roasterRef') IN (SELECT uri FROM matched_roasters)
+			)
+		)
+		SELECT uri, did, collection, rkey, record, cid, indexed_at, created_at FROM records
+		WHERE (
+			uri IN (SELECT uri FROM matched_beans)
+			OR (collection = ? AND (
+				lower(json_extract(record, '$.tastingNotes')) LIKE ? ESCAPE '\'
+				OR json_extract(record, '$.beanRef') IN (SELECT uri FROM matched_beans)
+			))
+		)
+		AND `+notDraftSQL+`
+		AND uri NOT IN (SELECT uri FROM moderation_hidden_records)
+		AND did NOT IN (SELECT did FROM moderation_blacklist)
+		ORDER BY created_at DESC LIMIT ?`,
+		roasterNSID, pattern,
+		beanNSID, pattern, pattern,
+		brewNSID, pattern,
+		limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records, refURIs, err := scanFeedRecords(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	return idx.buildFeedItems(ctx, records, refURIs)
+}
@@ -0,0 +1,60 @@
+package firehose
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchRecords(t *testing.T) {
+	idx := newTestIndex(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	n := 0
+	upsert := func(did, collection, rkey, fields string) string {
+		n++
+		createdAt := now.Add(time.Duration(n) * time.Minute).UTC().Format(time.RFC3339)
+		record := fmt.Appendf(nil, `{"$type":%q,%s,"createdAt":%q}`, collection, fields, createdAt)
+		require.NoError(t, idx.UpsertRecord(ctx, did, collection, rkey, "cid-"+rkey, record, time.Now().Unix()))
+		return "at://" + did + "/" + collection + "/" + rkey
+	}
+
+	roaster := upsert("did:plc:alice", "social.arabica.alpha.roaster", "r1", `"name":"Onyx Coffee Lab"`)
+	ethiopia := upsert("did:plc:alice", "social.arabica.alpha.bean", "b1", `"name":"Yirgacheffe","origin":"Ethiopia","roastLevel":"Light"`)
+	onyx := upsert("did:plc:alice", "social.arabica.alpha.bean", "b2", fmt.Sprintf(`"name":"Monarch","origin":"Blend","roasterRef":%q,"roastLevel":"Dark"`, roaster))
+	house := upsert("did:plc:bob", "social.arabica.alpha.bean", "b3", `"name":"House","origin":"Brazil","roastLevel":"Medium"`)
+	notesBrew := upsert("did:plc:bob", "social.arabica.alpha.brew", "w1", fmt.Sprintf(`"beanRef":%q,"tastingNotes":"Very chocolatey, 100%% worth it","rating":8`, house))
+	beanBrew := upsert("did:plc:alice", "social.arabica.alpha.brew", "w2", fmt.Sprintf(`"beanRef":%q,"tastingNotes":"Bright"`, ethiopia))
+	hidden := upsert("did:plc:alice", "social.arabica.alpha.brew", "w3", fmt.Sprintf(`"beanRef":%q,"tastingNotes":"Chocolate again"`, house))
+	upsert("did:plc:spam", "social.arabica.alpha.brew", "w4", fmt.Sprintf(`"beanRef":%q,"tastingNotes":"Chocolate spam"`, house))
+
+	_, err := idx.DB().ExecContext(ctx, `INSERT INTO moderation_hidden_records (uri, hidden_at, hidden_by) VALUES (?, ?, 'mod')`, hidden, now.Format(time.RFC3339))
+	require.NoError(t, err)
+	_, err = idx.DB().ExecContext(ctx, `INSERT INTO moderation_blacklist (did, blacklisted_at, blacklisted_by) VALUES ('did:plc:spam', ?, 'mod')`, now.Format(time.RFC3339))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"tasting notes, excluding moderated", "CHOCOLATE", []string{notesBrew}},
+		{"origin matches bean and its brews, newest first", "ethiopia", []string{beanBrew, ethiopia}},
+		{"roaster name matches its beans", "onyx", []string{onyx}},
+		{"like wildcards are literal", "100%", []string{notesBrew}},
+		{"blank query", "  ", []string{}},
+		{"no match", "fruity", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := idx.SearchRecords(ctx, tt.query, 10)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, feedItemURIs(items))
+		})
+	}
+}
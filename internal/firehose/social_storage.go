@@ -3,6 +3,7 @@ package firehose
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"strings"
 	"time"
 )
@@ -50,6 +51,28 @@ func (s *socialIndexStorage) userLikeRKey(ctx context.Context, actorDID, subject
 	return rkey
 }
 
+// likers pages through a subject's likers in DID order. Keyset paging on
+// the primary key keeps deep pages as cheap as the first.
+func (s *socialIndexStorage) likers(ctx context.Context, subjectURI string, limit int, after string) []string {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT actor_did FROM likes WHERE subject_uri = ? AND actor_did > ? ORDER BY actor_did LIMIT ?`,
+		subjectURI, after, limit)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var dids []string
+	for rows.Next() {
+		var did string
+		if err := rows.Scan(&did); err != nil {
+			continue
+		}
+		dids = append(dids, did)
+	}
+	return dids
+}
+
 func (s *socialIndexStorage) likeCountsBatch(ctx context.Context, uris []string) map[string]int {
 	counts := make(map[string]int, len(uris))
 	if len(uris) == 0 {
@@ -96,7 +119,53 @@ func (s *socialIndexStorage) hasUserLikedBatch(ctx context.Context, actorDID str
 	return liked
 }
 
-func (s *socialIndexStorage) upsertComment(ctx context.Context, actorDID, rkey, subjectURI, parentURI, cid, text string, createdAt time.Time) error {
+func (s *socialIndexStorage) upsertFollow(ctx context.Context, followerDID, rkey, subjectDID string) error {
+	_, err := s.db.ExecContext(ctx, `INSERT OR REPLACE INTO follows (follower_did, subject_did, rkey) VALUES (?, ?, ?)`,
+		followerDID, subjectDID, rkey)
+	return err
+}
+
+// deleteFollowByRKey removes a follow by its record key, since a firehose
+// delete only carries the rkey.
+func (s *socialIndexStorage) deleteFollowByRKey(ctx context.Context, followerDID, rkey string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM follows WHERE follower_did = ? AND rkey = ?`,
+		followerDID, rkey)
+	return err
+}
+
+func (s *socialIndexStorage) followRKey(ctx context.Context, followerDID, subjectDID string) string {
+	var rkey string
+	err := s.db.QueryRowContext(ctx, `SELECT rkey FROM follows WHERE follower_did = ? AND subject_did = ?`,
+		followerDID, subjectDID).Scan(&rkey)
+	if err != nil {
+		return ""
+	}
+	return rkey
+}
+
+func (s *socialIndexStorage) upsertBookmark(ctx context.Context, actorDID, rkey, subjectURI string) error {
+	_, err := s.db.ExecContext(ctx, `INSERT OR REPLACE INTO bookmarks (actor_did, subject_uri, rkey) VALUES (?, ?, ?)`,
+		actorDID, subjectURI, rkey)
+	return err
+}
+
+func (s *socialIndexStorage) deleteBookmarkByRKey(ctx context.Context, actorDID, rkey string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM bookmarks WHERE actor_did = ? AND rkey = ?`,
+		actorDID, rkey)
+	return err
+}
+
+func (s *socialIndexStorage) bookmarkRKey(ctx context.Context, actorDID, subjectURI string) string {
+	var rkey string
+	err := s.db.QueryRowContext(ctx, `SELECT rkey FROM bookmarks WHERE actor_did = ? AND subject_uri = ?`,
+		actorDID, subjectURI).Scan(&rkey)
+	if err != nil {
+		return ""
+	}
+	return rkey
+}
+
+func (s *socialIndexStorage) upsertComment(ctx context.Context, actorDID, rkey, subjectURI, parentURI, cid, text string, createdAt, editedAt time.Time) error {
 	var parentRKey string
 	if parentURI != "" {
 		parts := strings.Split(parentURI, "/")
@@ -106,22 +175,31 @@ func (s *socialIndexStorage) upsertComment(ctx context.Context, actorDID, rkey,
 	}
 
 	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO comments (actor_did, rkey, subject_uri, parent_uri, parent_rkey, cid, text, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO comments (actor_did, rkey, subject_uri, parent_uri, parent_rkey, cid, text, created_at, edited_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(actor_did, rkey) DO UPDATE SET
 			subject_uri = excluded.subject_uri,
 			parent_uri = excluded.parent_uri,
 			parent_rkey = excluded.parent_rkey,
 			cid = excluded.cid,
 			text = excluded.text,
-			created_at = excluded.created_at
-	`, actorDID, rkey, subjectURI, parentURI, parentRKey, cid, text, createdAt.Format(time.RFC3339Nano))
+			created_at = excluded.created_at,
+			edited_at = excluded.edited_at
+	`, actorDID, rkey, subjectURI, parentURI, parentRKey, cid, text, createdAt.Format(time.RFC3339Nano), formatOptionalTime(editedAt))
 	return err
 }
 
-func (s *socialIndexStorage) deleteComment(ctx context.Context, actorDID, rkey string) error {
-	_, err := s.db.ExecContext(ctx, `DELETE FROM comments WHERE actor_did = ? AND rkey = ?`, actorDID, rkey)
-	return err
+// deleteComment removes a comment and reports the subject it belonged to.
+// subjectURI is empty when no comment matched, so callers can skip follow-up
+// work for a delete that didn't change anything.
+func (s *socialIndexStorage) deleteComment(ctx context.Context, actorDID, rkey string) (subjectURI string, err error) {
+	err = s.db.QueryRowContext(ctx,
+		`DELETE FROM comments WHERE actor_did = ? AND rkey = ? RETURNING subject_uri`,
+		actorDID, rkey).Scan(&subjectURI)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return subjectURI, err
 }
 
 func (s *socialIndexStorage) commentCount(ctx context.Context, subjectURI string) int {
@@ -153,7 +231,7 @@ func (s *socialIndexStorage) commentCountsBatch(ctx context.Context, uris []stri
 }
 
 func (s *socialIndexStorage) commentsForSubject(ctx context.Context, subjectURI string, limit int) []IndexedComment {
-	query := `SELECT actor_did, rkey, subject_uri, parent_uri, parent_rkey, cid, text, created_at
+	query := `SELECT actor_did, rkey, subject_uri, parent_uri, parent_rkey, cid, text, created_at, edited_at
 		FROM comments WHERE subject_uri = ? ORDER BY created_at`
 	var args []any
 	args = append(args, subjectURI)
@@ -171,12 +249,15 @@ func (s *socialIndexStorage) commentsForSubject(ctx context.Context, subjectURI
 	var comments []IndexedComment
 	for rows.Next() {
 		var c IndexedComment
-		var createdAtStr string
+		var createdAtStr, editedAtStr string
 		if err := rows.Scan(&c.ActorDID, &c.RKey, &c.SubjectURI, &c.ParentURI, &c.ParentRKey,
-			&c.CID, &c.Text, &createdAtStr); err != nil {
+			&c.CID, &c.Text, &createdAtStr, &editedAtStr); err != nil {
 			continue
 		}
 		c.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAtStr)
+		if editedAtStr != "" {
+			c.EditedAt, _ = time.Parse(time.RFC3339Nano, editedAtStr)
+		}
 		comments = append(comments, c)
 	}
 	return comments
@@ -201,6 +282,8 @@ func (s *socialIndexStorage) deleteAllForDID(ctx context.Context, tx *sql.Tx, di
 	}{
 		{`DELETE FROM likes WHERE actor_did = ?`, []any{did}},
 		{`DELETE FROM likes WHERE subject_uri LIKE ?`, []any{uriPrefix}},
+		{`DELETE FROM follows WHERE follower_did = ? OR subject_did = ?`, []any{did, did}},
+		{`DELETE FROM bookmarks WHERE actor_did = ?`, []any{did}},
 		{`DELETE FROM comments WHERE actor_did = ?`, []any{did}},
 		{`DELETE FROM comments WHERE subject_uri LIKE ?`, []any{uriPrefix}},
 	}
@@ -213,3 +296,29 @@ func (s *socialIndexStorage) deleteAllForDID(ctx context.Context, tx *sql.Tx, di
 
 	return nil
 }
+
+// deleteAllForSubject drops likes, comments and bookmarks pointing at
+// subjectURI, along with the notifications they raised. The like, comment
+// and bookmark records stay on their authors' PDSes; only our index rows go.
+func (s *socialIndexStorage) deleteAllForSubject(ctx context.Context, tx *sql.Tx, subjectURI string) error {
+	for _, stmt := range []string{
+		`DELETE FROM likes WHERE subject_uri = ?`,
+		`DELETE FROM comments WHERE subject_uri = ?`,
+		`DELETE FROM bookmarks WHERE subject_uri = ?`,
+		`DELETE FROM notifications WHERE subject_uri = ?`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt, subjectURI); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatOptionalTime stores a zero time as "" so NOT NULL text columns can
+// still mean "unset".
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
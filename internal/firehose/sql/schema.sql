@@ -54,6 +54,17 @@ CREATE INDEX IF NOT EXISTS idx_explore_values_text ON explore_values(app, record
 CREATE INDEX IF NOT EXISTS idx_explore_values_num ON explore_values(app, record_type, field, value_num);
 CREATE INDEX IF NOT EXISTS idx_explore_values_uri ON explore_values(uri);
 
+-- Tags on brew records, so /tags/{tag} doesn't have to scan every brew's JSON.
+CREATE TABLE IF NOT EXISTS brew_tags (
+    uri        TEXT NOT NULL,
+    did        TEXT NOT NULL,
+    tag        TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    PRIMARY KEY (uri, tag),
+    FOREIGN KEY (uri) REFERENCES records(uri) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_brew_tags_tag ON brew_tags(tag, created_at DESC);
+
 CREATE TABLE IF NOT EXISTS meta (
     key   TEXT PRIMARY KEY,
     value BLOB
@@ -87,6 +98,25 @@ CREATE TABLE IF NOT EXISTS likes (
 );
 CREATE INDEX IF NOT EXISTS idx_likes_actor ON likes(actor_did, subject_uri);
 
+CREATE TABLE IF NOT EXISTS follows (
+    follower_did TEXT NOT NULL,
+    subject_did  TEXT NOT NULL,
+    rkey         TEXT NOT NULL,
+    PRIMARY KEY (follower_did, subject_did)
+);
+CREATE INDEX IF NOT EXISTS idx_follows_subject ON follows(subject_did);
+
+-- Bookmarks are public PDS records, but this site only ever shows them to
+-- their owner, so there is no subject index: the only lookups are "has this
+-- actor bookmarked X" and "what has this actor bookmarked".
+CREATE TABLE IF NOT EXISTS bookmarks (
+    actor_did   TEXT NOT NULL,
+    subject_uri TEXT NOT NULL,
+    rkey        TEXT NOT NULL,
+    PRIMARY KEY (actor_did, subject_uri)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookmarks_rkey ON bookmarks(actor_did, rkey);
+
 CREATE TABLE IF NOT EXISTS comments (
     actor_did   TEXT NOT NULL,
     rkey        TEXT NOT NULL,
@@ -96,6 +126,7 @@ CREATE TABLE IF NOT EXISTS comments (
     cid         TEXT NOT NULL DEFAULT '',
     text        TEXT NOT NULL,
     created_at  TEXT NOT NULL,
+    edited_at   TEXT NOT NULL DEFAULT '',
     PRIMARY KEY (actor_did, rkey)
 );
 CREATE INDEX IF NOT EXISTS idx_comments_subject ON comments(subject_uri, created_at);
@@ -124,6 +155,14 @@ CREATE TABLE IF NOT EXISTS moderation_hidden_records (
     auto_hidden INTEGER NOT NULL DEFAULT 0
 );
 
+-- Records an admin has pinned to the top of the public feed.
+CREATE TABLE IF NOT EXISTS moderation_pinned_records (
+    uri       TEXT PRIMARY KEY,
+    pinned_at TEXT NOT NULL,
+    pinned_by TEXT NOT NULL,
+    note      TEXT NOT NULL DEFAULT ''
+);
+
 CREATE TABLE IF NOT EXISTS moderation_blacklist (
     did            TEXT PRIMARY KEY,
     blacklisted_at TEXT NOT NULL,
@@ -131,11 +170,21 @@ CREATE TABLE IF NOT EXISTS moderation_blacklist (
     reason         TEXT NOT NULL DEFAULT ''
 );
 
+CREATE TABLE IF NOT EXISTS moderation_moderators (
+    did      TEXT PRIMARY KEY,
+    handle   TEXT NOT NULL DEFAULT '',
+    role     TEXT NOT NULL,
+    note     TEXT NOT NULL DEFAULT '',
+    added_by TEXT NOT NULL,
+    added_at TEXT NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS moderation_reports (
     id           TEXT PRIMARY KEY,
     subject_uri  TEXT NOT NULL DEFAULT '',
     subject_did  TEXT NOT NULL DEFAULT '',
     reporter_did TEXT NOT NULL,
+    category     TEXT NOT NULL DEFAULT 'other',
     reason       TEXT NOT NULL,
     created_at   TEXT NOT NULL,
     status       TEXT NOT NULL DEFAULT 'pending',
@@ -159,11 +208,31 @@ CREATE TABLE IF NOT EXISTS moderation_audit_log (
 );
 CREATE INDEX IF NOT EXISTS idx_modaudit_ts ON moderation_audit_log(timestamp DESC);
 
+CREATE TABLE IF NOT EXISTS moderation_appeals (
+    uri         TEXT PRIMARY KEY,
+    did         TEXT NOT NULL,
+    text        TEXT NOT NULL,
+    created_at  TEXT NOT NULL,
+    status      TEXT NOT NULL DEFAULT 'pending',
+    resolved_by TEXT NOT NULL DEFAULT '',
+    resolved_at TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_modappeals_status ON moderation_appeals(status);
+
 CREATE TABLE IF NOT EXISTS moderation_autohide_resets (
     did      TEXT PRIMARY KEY,
     reset_at TEXT NOT NULL
 );
 
+-- Runtime moderation settings edited from the admin dashboard, one JSON
+-- value per key (e.g. "automod").
+CREATE TABLE IF NOT EXISTS moderation_settings (
+    key        TEXT PRIMARY KEY,
+    value      TEXT NOT NULL,
+    updated_by TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS moderation_labels (
     id          TEXT PRIMARY KEY,
     entity_type TEXT NOT NULL,
@@ -198,3 +267,24 @@ CREATE TABLE IF NOT EXISTS oauth_auth_requests (
     data       TEXT NOT NULL,
     created_at TEXT NOT NULL
 );
+
+-- Sessions created with an app password for scripted API access. session_id
+-- is derived from a hash of the bearer token handed to the client; the token
+-- itself is never stored.
+CREATE TABLE IF NOT EXISTS app_password_sessions (
+    session_id TEXT PRIMARY KEY,
+    did        TEXT NOT NULL,
+    data       TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_app_password_sessions_did ON app_password_sessions(did);
+
+-- Admin-managed endpoints that are sent a signed POST for each new feedable
+-- record the firehose indexes. secret keys the HMAC signature.
+CREATE TABLE IF NOT EXISTS webhooks (
+    id         TEXT PRIMARY KEY,
+    url        TEXT NOT NULL,
+    secret     TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
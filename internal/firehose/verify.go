@@ -0,0 +1,70 @@
+package firehose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+)
+
+// CountMismatch is an explore document whose stored like or comment count
+// disagrees with the likes and comments tables.
+type CountMismatch struct {
+	URI            string `json:"uri"`
+	StoredLikes    int    `json:"storedLikes"`
+	Likes          int    `json:"likes"`
+	StoredComments int    `json:"storedComments"`
+	Comments       int    `json:"comments"`
+}
+
+// VerifyReport is what Verify found, and fixed when asked to.
+type VerifyReport struct {
+	Checked    int             `json:"checked"`
+	Mismatches []CountMismatch `json:"mismatches,omitempty"`
+	Repaired   int             `json:"repaired"`
+}
+
+// Verify recounts likes and comments for every explore document and
+// compares them with the like_count and comment_count stored on it. Those
+// columns are refreshed after the like or comment write commits, so a crash
+// between the two leaves them behind. With repair set, each mismatched
+// document is refreshed the same way a new like would refresh it.
+func (idx *FeedIndex) Verify(ctx context.Context, repair bool) (VerifyReport, error) {
+	var report VerifyReport
+	rows, err := idx.db.QueryContext(ctx, `
+		SELECT d.uri, d.like_count, d.comment_count, COALESCE(l.n, 0), COALESCE(c.n, 0)
+		FROM explore_documents d
+		LEFT JOIN (SELECT subject_uri, COUNT(*) AS n FROM likes GROUP BY subject_uri) l ON l.subject_uri = d.uri
+		LEFT JOIN (SELECT subject_uri, COUNT(*) AS n FROM comments GROUP BY subject_uri) c ON c.subject_uri = d.uri`)
+	if err != nil {
+		return report, fmt.Errorf("scan explore counts: %w", err)
+	}
+	for rows.Next() {
+		var m CountMismatch
+		if err := rows.Scan(&m.URI, &m.StoredLikes, &m.StoredComments, &m.Likes, &m.Comments); err != nil {
+			rows.Close()
+			return report, fmt.Errorf("scan explore counts: %w", err)
+		}
+		report.Checked++
+		if m.StoredLikes != m.Likes || m.StoredComments != m.Comments {
+			report.Mismatches = append(report.Mismatches, m)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return report, fmt.Errorf("scan explore counts: %w", err)
+	}
+
+	if !repair {
+		return report, nil
+	}
+	// Repairs run after the scan so the writes don't wait on an open read.
+	for _, m := range report.Mismatches {
+		if err := idx.refreshExploreStats(ctx, m.URI); err != nil {
+			log.Warn().Err(err).Str("uri", m.URI).Msg("verify: failed to repair counts")
+			continue
+		}
+		report.Repaired++
+	}
+	return report, nil
+}
@@ -0,0 +1,60 @@
+package firehose
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	arabica "tangled.org/arabica.social/arabica/internal/arabica/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyRepairsDriftedCounts(t *testing.T) {
+	idx := newExploreTestIndex(t)
+	ctx := context.Background()
+	beanURI := upsertExploreRecord(t, idx, "did:plc:user", arabica.NSIDBean, "b1", map[string]any{"$type": arabica.NSIDBean, "name": "Ardi"}, 1)
+	otherURI := upsertExploreRecord(t, idx, "did:plc:user", arabica.NSIDBean, "b2", map[string]any{"$type": arabica.NSIDBean, "name": "Halo"}, 2)
+	require.NoError(t, idx.UpsertLike(ctx, "did:plc:fan", "l1", beanURI))
+	require.NoError(t, idx.UpsertComment(ctx, "did:plc:fan", "c1", beanURI, "", "cid-c1", "nice", time.Now(), time.Time{}))
+
+	report, err := idx.Verify(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Checked)
+	assert.Empty(t, report.Mismatches)
+
+	// Simulate a crash between the like write and the stats refresh.
+	_, err = idx.DB().ExecContext(ctx, `UPDATE explore_documents SET like_count = 5, comment_count = 0 WHERE uri = ?`, beanURI)
+	require.NoError(t, err)
+
+	report, err = idx.Verify(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, []CountMismatch{{URI: beanURI, StoredLikes: 5, Likes: 1, StoredComments: 0, Comments: 1}}, report.Mismatches)
+	assert.Zero(t, report.Repaired)
+	doc := exploreDocument(t, idx, beanURI)
+	assert.Equal(t, 5, doc.LikeCount, "a dry run leaves the stored counts alone")
+
+	report, err = idx.Verify(ctx, true)
+	require.NoError(t, err)
+	assert.Len(t, report.Mismatches, 1)
+	assert.Equal(t, 1, report.Repaired)
+
+	doc = exploreDocument(t, idx, beanURI)
+	assert.Equal(t, 1, doc.LikeCount)
+	assert.Equal(t, 1, doc.CommentCount)
+	assert.Zero(t, exploreDocument(t, idx, otherURI).LikeCount)
+
+	report, err = idx.Verify(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, report.Mismatches)
+}
+
+func exploreDocument(t *testing.T, idx *FeedIndex, uri string) ExploreDocument {
+	t.Helper()
+	var doc ExploreDocument
+	require.NoError(t, idx.DB().QueryRowContext(context.Background(),
+		`SELECT uri, like_count, comment_count FROM explore_documents WHERE uri = ?`, uri).
+		Scan(&doc.URI, &doc.LikeCount, &doc.CommentCount))
+	return doc
+}
@@ -42,12 +42,15 @@ func (s *witnessRecordStorage) get(ctx context.Context, uri string) (*atproto.Wi
 	}, nil
 }
 
-func (s *witnessRecordStorage) list(ctx context.Context, did, collection string, offset, limit int) ([]*atproto.WitnessRecord, error) {
+func (s *witnessRecordStorage) list(ctx context.Context, did, collection string, offset, limit int, drafts bool) ([]*atproto.WitnessRecord, error) {
 	query := `
 		SELECT uri, did, collection, rkey, record, cid, indexed_at, created_at
 		FROM records WHERE did = ? AND collection = ?
-		ORDER BY created_at DESC
 	`
+	if !drafts {
+		query += ` AND ` + notDraftSQL
+	}
+	query += ` ORDER BY created_at DESC`
 	args := []any{did, collection}
 	if limit > 0 {
 		query += ` LIMIT ? OFFSET ?`
@@ -79,11 +82,13 @@ func (s *witnessRecordStorage) list(ctx context.Context, did, collection string,
 	return records, nil
 }
 
-func (s *witnessRecordStorage) count(ctx context.Context, did, collection string) (int, error) {
+func (s *witnessRecordStorage) count(ctx context.Context, did, collection string, drafts bool) (int, error) {
+	query := `SELECT COUNT(*) FROM records WHERE did = ? AND collection = ?`
+	if !drafts {
+		query += ` AND ` + notDraftSQL
+	}
 	var count int
-	err := s.db.QueryRowContext(ctx, `
-		SELECT COUNT(*) FROM records WHERE did = ? AND collection = ?
-	`, did, collection).Scan(&count)
+	err := s.db.QueryRowContext(ctx, query, did, collection).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -137,7 +142,9 @@ ON CONFLICT(uri) DO UPDATE SET
 	return nil
 }
 
-func (s *witnessRecordStorage) update(ctx context.Context, did, collection, rkey string, record json.RawMessage) error {
+// update rewrites an existing row's record body and reports whether there
+// was a row to update.
+func (s *witnessRecordStorage) update(ctx context.Context, did, collection, rkey string, record json.RawMessage) (bool, error) {
 	uri := atp.BuildATURI(did, collection, rkey)
 	ctx, span := tracing.SqliteSpan(ctx, "update", "records")
 	span.SetAttributes(
@@ -147,14 +154,18 @@ func (s *witnessRecordStorage) update(ctx context.Context, did, collection, rkey
 	)
 	defer span.End()
 
-	_, err := s.db.ExecContext(ctx,
+	res, err := s.db.ExecContext(ctx,
 		`UPDATE records SET record = ?, indexed_at = ? WHERE uri = ?`,
 		string(record), time.Now().UTC().Format(time.RFC3339Nano), uri)
 	if err != nil {
 		tracing.EndWithError(span, err)
-		return fmt.Errorf("failed to update record: %w", err)
+		return false, fmt.Errorf("failed to update record: %w", err)
 	}
-	return nil
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to update record: %w", err)
+	}
+	return n > 0, nil
 }
 
 func (s *witnessRecordStorage) upsertBatch(ctx context.Context, records []atproto.WitnessWriteRecord) error {
@@ -2,19 +2,24 @@ package handlers
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"tangled.org/arabica.social/arabica/internal/atproto"
 	"tangled.org/arabica.social/arabica/internal/backup"
+	"tangled.org/arabica.social/arabica/internal/firehose"
 	"tangled.org/arabica.social/arabica/internal/metrics"
 	"tangled.org/arabica.social/arabica/internal/middleware"
 	"tangled.org/arabica.social/arabica/internal/moderation"
 	"tangled.org/arabica.social/arabica/internal/web/components"
 	sharedpages "tangled.org/arabica.social/arabica/internal/web/pages"
+	"tangled.org/arabica.social/arabica/internal/webhooks"
 	"tangled.org/pdewey.com/atp"
 	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
 
@@ -48,43 +53,90 @@ func (h *Handler) HandleHideRecord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Hide the record
+	if err := h.hideRecord(r.Context(), req.URI, req.Reason, userDID); err != nil {
+		log.Error().Err(err).Str("uri", req.URI).Msg("Failed to hide record")
+		http.Error(w, "Failed to hide record", http.StatusInternalServerError)
+		return
+	}
+
+	log.Info().
+		Str("uri", req.URI).
+		Str("by", userDID).
+		Msg("Record hidden from feed")
+
+	w.Header().Set("HX-Trigger", `{"mod-action":null,"notify":{"message":"Record hidden from feed"}}`)
+	w.WriteHeader(http.StatusOK)
+}
+
+// hideRecord hides uri and writes the audit entry. An audit failure is only
+// logged; the record stays hidden.
+func (h *Handler) hideRecord(ctx context.Context, uri, reason, actorDID string) error {
 	entry := moderation.HiddenRecord{
-		ATURI:      req.URI,
+		ATURI:      uri,
 		HiddenAt:   time.Now(),
-		HiddenBy:   userDID,
-		Reason:     req.Reason,
+		HiddenBy:   actorDID,
+		Reason:     reason,
 		AutoHidden: false,
 	}
-
-	if err := h.moderationStore.HideRecord(r.Context(), entry); err != nil {
-		log.Error().Err(err).Str("uri", req.URI).Msg("Failed to hide record")
-		http.Error(w, "Failed to hide record", http.StatusInternalServerError)
-		return
+	if err := h.moderationStore.HideRecord(ctx, entry); err != nil {
+		return err
 	}
+	h.InvalidateFeedCache()
 
-	// Log the action
 	auditEntry := moderation.AuditEntry{
 		ID:        generateTID(),
 		Action:    moderation.AuditActionHideRecord,
-		ActorDID:  userDID,
-		TargetURI: req.URI,
-		Reason:    req.Reason,
+		ActorDID:  actorDID,
+		TargetURI: uri,
+		Reason:    reason,
 		Timestamp: time.Now(),
 		AutoMod:   false,
 	}
-	if err := h.moderationStore.LogAction(r.Context(), auditEntry); err != nil {
+	if err := h.moderationStore.LogAction(ctx, auditEntry); err != nil {
 		log.Error().Err(err).Msg("Failed to log hide action")
-		// Don't fail the request, just log the error
 	}
+	h.emitHideLabel(uri, reason, false)
+	return nil
+}
 
-	log.Info().
-		Str("uri", req.URI).
-		Str("by", userDID).
-		Msg("Record hidden from feed")
+// labelerTimeout bounds a single call to the labeler service.
+const labelerTimeout = 15 * time.Second
 
-	w.Header().Set("HX-Trigger", `{"mod-action":null,"notify":{"message":"Record hidden from feed"}}`)
-	w.WriteHeader(http.StatusOK)
+// emitHideLabel publishes a hide, or with negate an unhide, to the
+// configured labeler. It runs in the background: the local decision has
+// already been stored, so a slow or failing labeler is only logged.
+func (h *Handler) emitHideLabel(uri, reason string, negate bool) {
+	if h.labeler == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), labelerTimeout)
+		defer cancel()
+
+		// Labels name a specific version of the record, so the CID has to
+		// come from the index. Records already gone from it are skipped.
+		var cid string
+		if h.feedIndex != nil {
+			if rec, err := h.feedIndex.GetRecord(ctx, uri); err == nil && rec != nil {
+				cid = rec.CID
+			}
+		}
+		if cid == "" {
+			log.Warn().Str("uri", uri).Msg("Skipping moderation label: record not in index")
+			return
+		}
+
+		ev := moderation.LabelEvent{
+			URI:    uri,
+			CID:    cid,
+			Val:    moderation.LabelHide,
+			Negate: negate,
+			Reason: reason,
+		}
+		if err := h.labeler.EmitLabel(ctx, ev); err != nil {
+			log.Warn().Err(err).Str("uri", uri).Bool("negate", negate).Msg("Failed to emit moderation label")
+		}
+	}()
 }
 
 // HandleUnhideRecord handles POST /admin/unhide
@@ -106,11 +158,12 @@ func (h *Handler) HandleUnhideRecord(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Unhide the record
-	if err := h.moderationStore.UnhideRecord(r.Context(), req.URI); err != nil {
+	if err := h.moderationStore.UnhideRecord(r.Context(), req.URI, userDID); err != nil {
 		log.Error().Err(err).Str("uri", req.URI).Msg("Failed to unhide record")
 		http.Error(w, "Failed to unhide record", http.StatusInternalServerError)
 		return
 	}
+	h.InvalidateFeedCache()
 
 	// Log the action
 	auditEntry := moderation.AuditEntry{
@@ -125,6 +178,7 @@ func (h *Handler) HandleUnhideRecord(w http.ResponseWriter, r *http.Request) {
 	if err := h.moderationStore.LogAction(r.Context(), auditEntry); err != nil {
 		log.Error().Err(err).Msg("Failed to log unhide action")
 	}
+	h.emitHideLabel(req.URI, req.Reason, true)
 
 	log.Info().
 		Str("uri", req.URI).
@@ -135,13 +189,188 @@ func (h *Handler) HandleUnhideRecord(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// HandlePinRecord handles POST /_mod/pin: it features a record at the top
+// of the public feed. An optional "note" says why, for the audit log.
+// Auth and admin checks are handled by RequireAdmin middleware.
+func (h *Handler) HandlePinRecord(w http.ResponseWriter, r *http.Request) {
+	userDID, _ := atpmiddleware.GetDID(r.Context())
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	uri := r.FormValue("uri")
+	note := r.FormValue("note")
+	if uri == "" {
+		http.Error(w, "URI is required", http.StatusBadRequest)
+		return
+	}
+
+	entry := moderation.PinnedRecord{
+		ATURI:    uri,
+		PinnedAt: time.Now(),
+		PinnedBy: userDID,
+		Note:     note,
+	}
+	if err := h.moderationStore.Pin(r.Context(), entry); err != nil {
+		log.Error().Err(err).Str("uri", uri).Msg("Failed to pin record")
+		http.Error(w, "Failed to pin record", http.StatusInternalServerError)
+		return
+	}
+	h.InvalidateFeedCache()
+
+	auditEntry := moderation.AuditEntry{
+		ID:        generateTID(),
+		Action:    moderation.AuditActionPinRecord,
+		ActorDID:  userDID,
+		TargetURI: uri,
+		Reason:    note,
+		Timestamp: time.Now(),
+	}
+	if err := h.moderationStore.LogAction(r.Context(), auditEntry); err != nil {
+		log.Error().Err(err).Msg("Failed to log pin action")
+	}
+
+	log.Info().Str("uri", uri).Str("by", userDID).Msg("Record pinned to feed")
+
+	w.Header().Set("HX-Trigger", `{"mod-action":null,"notify":{"message":"Record featured at the top of the feed"}}`)
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleUnpinRecord handles POST /_mod/unpin.
+// Auth and admin checks are handled by RequireAdmin middleware.
+func (h *Handler) HandleUnpinRecord(w http.ResponseWriter, r *http.Request) {
+	userDID, _ := atpmiddleware.GetDID(r.Context())
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	uri := r.FormValue("uri")
+	if uri == "" {
+		http.Error(w, "URI is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.moderationStore.Unpin(r.Context(), uri); err != nil {
+		log.Error().Err(err).Str("uri", uri).Msg("Failed to unpin record")
+		http.Error(w, "Failed to unpin record", http.StatusInternalServerError)
+		return
+	}
+	h.InvalidateFeedCache()
+
+	auditEntry := moderation.AuditEntry{
+		ID:        generateTID(),
+		Action:    moderation.AuditActionUnpinRecord,
+		ActorDID:  userDID,
+		TargetURI: uri,
+		Timestamp: time.Now(),
+	}
+	if err := h.moderationStore.LogAction(r.Context(), auditEntry); err != nil {
+		log.Error().Err(err).Msg("Failed to log unpin action")
+	}
+
+	log.Info().Str("uri", uri).Str("by", userDID).Msg("Record unpinned")
+
+	w.Header().Set("HX-Trigger", `{"mod-action":null,"notify":{"message":"Record unpinned"}}`)
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleApproveAppeal handles POST /_mod/appeal/approve: it accepts the
+// owner's appeal and unhides the record.
+// Auth and permission checks are handled by RequirePermission middleware.
+func (h *Handler) HandleApproveAppeal(w http.ResponseWriter, r *http.Request) {
+	uri, ok := h.resolveAppeal(w, r, moderation.AppealStatusApproved, h.moderationStore.ApproveAppeal)
+	if !ok {
+		return
+	}
+	h.InvalidateFeedCache()
+	h.emitHideLabel(uri, "Appeal approved", true)
+
+	w.Header().Set("HX-Trigger", `{"mod-action":null,"notify":{"message":"Appeal approved, record unhidden"}}`)
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleRejectAppeal handles POST /_mod/appeal/reject. The record stays
+// hidden and the owner can't appeal it again.
+// Auth and permission checks are handled by RequirePermission middleware.
+func (h *Handler) HandleRejectAppeal(w http.ResponseWriter, r *http.Request) {
+	reject := func(ctx context.Context, uri, by string) error {
+		return h.moderationStore.ResolveAppeal(ctx, uri, moderation.AppealStatusRejected, by)
+	}
+	if _, ok := h.resolveAppeal(w, r, moderation.AppealStatusRejected, reject); !ok {
+		return
+	}
+
+	w.Header().Set("HX-Trigger", `{"mod-action":null,"notify":{"message":"Appeal rejected"}}`)
+	w.WriteHeader(http.StatusOK)
+}
+
+// resolveAppeal closes the pending appeal named by the "uri" form value with
+// resolve and logs the decision as status. It writes the error response
+// itself and returns false when there is nothing to resolve.
+func (h *Handler) resolveAppeal(w http.ResponseWriter, r *http.Request, status moderation.AppealStatus, resolve func(ctx context.Context, uri, by string) error) (string, bool) {
+	userDID, _ := atpmiddleware.GetDID(r.Context())
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return "", false
+	}
+	uri := r.FormValue("uri")
+	if uri == "" {
+		http.Error(w, "URI is required", http.StatusBadRequest)
+		return "", false
+	}
+
+	err := resolve(r.Context(), uri, userDID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "No pending appeal for this record", http.StatusNotFound)
+		return "", false
+	}
+	if err != nil {
+		log.Error().Err(err).Str("uri", uri).Msg("Failed to resolve appeal")
+		http.Error(w, "Failed to resolve appeal", http.StatusInternalServerError)
+		return "", false
+	}
+
+	action := moderation.AuditActionRejectAppeal
+	if status == moderation.AppealStatusApproved {
+		action = moderation.AuditActionApproveAppeal
+	}
+	auditEntry := moderation.AuditEntry{
+		ID:        generateTID(),
+		Action:    action,
+		ActorDID:  userDID,
+		TargetURI: uri,
+		Reason:    r.FormValue("reason"),
+		Timestamp: time.Now(),
+	}
+	if err := h.moderationStore.LogAction(r.Context(), auditEntry); err != nil {
+		log.Error().Err(err).Msg("Failed to log appeal decision")
+	}
+
+	log.Info().
+		Str("uri", uri).
+		Str("status", string(status)).
+		Str("by", userDID).
+		Msg("Appeal resolved")
+
+	return uri, true
+}
+
 // generateTID generates a TID (timestamp-based identifier) using the AT Protocol TID format.
 func generateTID() string {
 	return syntax.NewTIDNow(0).String()
 }
 
+// adminPageSize is how many reports or audit entries the dashboard shows
+// per page.
+const adminPageSize = 50
+
 // buildAdminProps builds the admin dashboard props for the given moderator.
-func (h *Handler) buildAdminProps(ctx context.Context, userDID string) sharedpages.AdminProps {
+// reportCategory narrows the pending report queue; empty or unknown values
+// show every category.
+func (h *Handler) buildAdminProps(ctx context.Context, userDID string, reportCategory string) sharedpages.AdminProps {
 	canHide := h.moderationService.HasPermission(userDID, moderation.PermissionHideRecord)
 	canUnhide := h.moderationService.HasPermission(userDID, moderation.PermissionUnhideRecord)
 	canViewLogs := h.moderationService.HasPermission(userDID, moderation.PermissionViewAuditLog)
@@ -156,16 +385,25 @@ func (h *Handler) buildAdminProps(ctx context.Context, userDID string) sharedpag
 	var enrichedReports []sharedpages.EnrichedReport
 	var blockedUsers []moderation.BlacklistedUser
 
+	var appeals map[string]moderation.Appeal
 	if (canHide || canUnhide) && h.moderationStore != nil {
 		hiddenRecords, _ = h.moderationStore.ListHiddenRecords(ctx)
+		if pending, err := h.moderationStore.ListPendingAppeals(ctx); err == nil {
+			appeals = make(map[string]moderation.Appeal, len(pending))
+			for _, a := range pending {
+				appeals[a.URI] = a
+			}
+		}
 	}
 
+	var auditCursor, reportsCursor string
 	if canViewLogs && h.moderationStore != nil {
-		auditLog, _ = h.moderationStore.ListAuditLog(ctx, 50)
+		auditLog, auditCursor, _ = h.moderationStore.ListAuditLogPage(ctx, "", adminPageSize)
 	}
 
 	if canViewReports && h.moderationStore != nil {
-		reports, _ := h.moderationStore.ListPendingReports(ctx)
+		var reports []moderation.Report
+		reports, reportsCursor, _ = h.moderationStore.ListPendingReportsPage(ctx, reportCategory, "", adminPageSize)
 		enrichedReports = h.enrichReports(ctx, reports)
 	}
 
@@ -183,21 +421,36 @@ func (h *Handler) buildAdminProps(ctx context.Context, userDID string) sharedpag
 	// Build stats for admin users
 	var stats sharedpages.AdminStats
 	var backups []backup.SourceStatus
+	var moderators []moderation.ModeratorUser
+	var automod moderation.AutomodSettings
+	var hooks []webhooks.Webhook
 	if isAdmin {
+		automod = h.automodSettings(ctx)
 		stats = h.collectAdminStats(ctx)
 		if h.backupService != nil {
 			backups = h.backupService.Status()
 		}
+		moderators = h.moderationService.ListModerators()
+		if h.webhooks != nil {
+			hooks, _ = h.webhooks.Store().List(ctx)
+		}
 	}
 
 	return sharedpages.AdminProps{
 		HiddenRecords:    hiddenRecords,
+		Appeals:          appeals,
 		AuditLog:         auditLog,
+		AuditLogCursor:   auditCursor,
 		Reports:          enrichedReports,
+		ReportsCursor:    reportsCursor,
+		ReportCategory:   reportCategory,
 		BlockedUsers:     blockedUsers,
 		Labels:           labels,
 		Stats:            stats,
 		Backups:          backups,
+		Moderators:       moderators,
+		Automod:          automod,
+		Webhooks:         hooks,
 		CanHide:          canHide,
 		CanUnhide:        canUnhide,
 		CanViewLogs:      canViewLogs,
@@ -227,7 +480,7 @@ func (h *Handler) HandleAdmin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	userProfile := h.GetUserProfile(r.Context(), userDID)
-	adminProps := h.buildAdminProps(r.Context(), userDID)
+	adminProps := h.buildAdminProps(r.Context(), userDID, r.URL.Query().Get("report_category"))
 
 	layoutData := &components.LayoutData{
 		Title:           "Moderation",
@@ -235,6 +488,7 @@ func (h *Handler) HandleAdmin(w http.ResponseWriter, r *http.Request) {
 		UserDID:         userDID,
 		UserProfile:     userProfile,
 		CSPNonce:        middleware.CSPNonceFromContext(r.Context()),
+		CSRFToken:       middleware.CSRFTokenFromContext(r.Context()),
 		IsModerator:     true,
 	}
 
@@ -248,7 +502,7 @@ func (h *Handler) HandleAdmin(w http.ResponseWriter, r *http.Request) {
 // Auth and moderator checks are handled by RequireModerator middleware.
 func (h *Handler) HandleAdminPartial(w http.ResponseWriter, r *http.Request) {
 	userDID, _ := atpmiddleware.GetDID(r.Context())
-	adminProps := h.buildAdminProps(r.Context(), userDID)
+	adminProps := h.buildAdminProps(r.Context(), userDID, r.URL.Query().Get("report_category"))
 
 	if err := sharedpages.AdminDashboardBody(adminProps).Render(r.Context(), w); err != nil {
 		log.Error().Err(err).Msg("Failed to render admin partial")
@@ -256,6 +510,77 @@ func (h *Handler) HandleAdminPartial(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// HandleAdminReportsMore renders the next page of pending reports for the
+// report queue's "load more" control (GET /_mod/reports/more).
+// Auth and permission checks are handled by RequirePermission middleware.
+func (h *Handler) HandleAdminReportsMore(w http.ResponseWriter, r *http.Request) {
+	userDID, _ := atpmiddleware.GetDID(r.Context())
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	reports, next, err := h.moderationStore.ListPendingReportsPage(ctx, q.Get("report_category"), q.Get("cursor"), adminPageSize)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list pending reports")
+		http.Error(w, "Failed to load reports", http.StatusInternalServerError)
+		return
+	}
+
+	props := sharedpages.AdminProps{
+		Reports:          h.enrichReports(ctx, reports),
+		ReportsCursor:    next,
+		ReportCategory:   q.Get("report_category"),
+		CanHide:          h.moderationService.HasPermission(userDID, moderation.PermissionHideRecord),
+		CanBlock:         h.moderationService.HasPermission(userDID, moderation.PermissionBlacklistUser),
+		CanResetAutoHide: h.moderationService.HasPermission(userDID, moderation.PermissionResetAutoHide),
+	}
+	if err := sharedpages.AdminReportsPage(props).Render(ctx, w); err != nil {
+		log.Error().Err(err).Msg("Failed to render reports page")
+	}
+}
+
+// resolvedReportsPageSize is smaller than adminPageSize because every
+// resolved report costs a few profile and record lookups to enrich.
+const resolvedReportsPageSize = 20
+
+// HandleAdminResolvedReports renders a page of resolved reports
+// (GET /_mod/reports/resolved). The tab loads it lazily on first view so the
+// dashboard itself doesn't pay for the enrichment lookups.
+// Auth and permission checks are handled by RequirePermission middleware.
+func (h *Handler) HandleAdminResolvedReports(w http.ResponseWriter, r *http.Request) {
+	reports, next, err := h.moderationStore.ListResolvedReports(r.Context(), resolvedReportsPageSize, r.URL.Query().Get("cursor"))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list resolved reports")
+		http.Error(w, "Failed to load resolved reports", http.StatusInternalServerError)
+		return
+	}
+
+	page := sharedpages.ResolvedReportsPage{
+		Reports:    h.enrichReports(r.Context(), reports),
+		NextCursor: next,
+		FirstPage:  r.URL.Query().Get("cursor") == "",
+	}
+	if err := sharedpages.AdminResolvedReports(page).Render(r.Context(), w); err != nil {
+		log.Error().Err(err).Msg("Failed to render resolved reports")
+	}
+}
+
+// HandleAdminAuditLogMore renders the next page of the activity log
+// (GET /_mod/activity/more).
+// Auth and permission checks are handled by RequirePermission middleware.
+func (h *Handler) HandleAdminAuditLogMore(w http.ResponseWriter, r *http.Request) {
+	entries, next, err := h.moderationStore.ListAuditLogPage(r.Context(), r.URL.Query().Get("cursor"), adminPageSize)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list audit log")
+		http.Error(w, "Failed to load activity", http.StatusInternalServerError)
+		return
+	}
+
+	props := sharedpages.AdminProps{AuditLog: entries, AuditLogCursor: next}
+	if err := sharedpages.AdminAuditLogPage(props).Render(r.Context(), w); err != nil {
+		log.Error().Err(err).Msg("Failed to render activity page")
+	}
+}
+
 // enrichReports resolves handles and fetches post content for reports
 func (h *Handler) enrichReports(ctx context.Context, reports []moderation.Report) []sharedpages.EnrichedReport {
 	if len(reports) == 0 {
@@ -280,6 +605,12 @@ func (h *Handler) enrichReports(ctx context.Context, reports []moderation.Report
 			er.ReporterHandle = profile.Handle
 		}
 
+		if report.ResolvedBy != "" {
+			if profile, err := publicClient.GetProfile(ctx, report.ResolvedBy); err == nil {
+				er.ResolverHandle = profile.Handle
+			}
+		}
+
 		// Fetch post content summary
 		er.PostContent = h.getPostContentSummary(ctx, publicClient, report.SubjectURI)
 
@@ -374,6 +705,7 @@ func (h *Handler) HandleBlockUser(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to block user", http.StatusInternalServerError)
 		return
 	}
+	h.InvalidateFeedCache()
 
 	// Log the action
 	auditEntry := moderation.AuditEntry{
@@ -421,6 +753,7 @@ func (h *Handler) HandleUnblockUser(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to unblock user", http.StatusInternalServerError)
 		return
 	}
+	h.InvalidateFeedCache()
 
 	// Log the action
 	auditEntry := moderation.AuditEntry{
@@ -504,33 +837,39 @@ func (h *Handler) HandleDismissReport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Dismiss the report
-	if err := h.moderationStore.ResolveReport(r.Context(), reportID, moderation.ReportStatusDismissed, userDID); err != nil {
+	if err := h.dismissReport(r.Context(), reportID, userDID); err != nil {
 		log.Error().Err(err).Str("reportID", reportID).Msg("Failed to dismiss report")
 		http.Error(w, "Failed to dismiss report", http.StatusInternalServerError)
 		return
 	}
 
-	// Log the action
+	log.Info().
+		Str("reportID", reportID).
+		Str("by", userDID).
+		Msg("Report dismissed")
+
+	w.Header().Set("HX-Trigger", "mod-action")
+	w.WriteHeader(http.StatusOK)
+}
+
+// dismissReport marks a report dismissed and writes the audit entry.
+func (h *Handler) dismissReport(ctx context.Context, reportID, actorDID string) error {
+	if err := h.moderationStore.ResolveReport(ctx, reportID, moderation.ReportStatusDismissed, actorDID); err != nil {
+		return err
+	}
+
 	auditEntry := moderation.AuditEntry{
 		ID:        generateTID(),
 		Action:    moderation.AuditActionDismissReport,
-		ActorDID:  userDID,
+		ActorDID:  actorDID,
 		TargetURI: reportID,
 		Timestamp: time.Now(),
 		AutoMod:   false,
 	}
-	if err := h.moderationStore.LogAction(r.Context(), auditEntry); err != nil {
+	if err := h.moderationStore.LogAction(ctx, auditEntry); err != nil {
 		log.Error().Err(err).Msg("Failed to log dismiss action")
 	}
-
-	log.Info().
-		Str("reportID", reportID).
-		Str("by", userDID).
-		Msg("Report dismissed")
-
-	w.Header().Set("HX-Trigger", "mod-action")
-	w.WriteHeader(http.StatusOK)
+	return nil
 }
 
 // HandleAddLabel handles POST /_mod/label/add
@@ -661,12 +1000,296 @@ func (h *Handler) HandleRemoveLabel(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// HandleAddModerator handles POST /_mod/moderators/add, granting a config-
+// defined role to a DID without a redeploy. Auth and admin checks are handled
+// by RequireAdmin.
+func (h *Handler) HandleAddModerator(w http.ResponseWriter, r *http.Request) {
+	userDID, _ := atpmiddleware.GetDID(r.Context())
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	did, err := syntax.ParseDID(strings.TrimSpace(r.FormValue("did")))
+	if err != nil {
+		http.Error(w, "missing or invalid 'did' parameter", http.StatusBadRequest)
+		return
+	}
+	role := moderation.RoleName(strings.TrimSpace(r.FormValue("role")))
+	if role == "" {
+		role = moderation.RoleModerator
+	}
+
+	user := moderation.ModeratorUser{
+		DID:     did.String(),
+		Handle:  strings.TrimSpace(r.FormValue("handle")),
+		Role:    role,
+		Note:    strings.TrimSpace(r.FormValue("note")),
+		AddedBy: userDID,
+		AddedAt: time.Now(),
+	}
+	if !h.changeModerator(w, r, moderation.AuditActionAddModerator, user, h.moderationService.AddModerator) {
+		return
+	}
+
+	log.Info().
+		Str("did", user.DID).
+		Str("role", string(role)).
+		Str("by", userDID).
+		Msg("Moderator added")
+
+	w.Header().Set("HX-Trigger", "mod-action")
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleUpdateAutomod handles POST /_mod/automod, saving an override for the
+// report and auto-hide thresholds. Every value must be a positive integer.
+// Auth and admin checks are handled by RequireAdmin.
+func (h *Handler) HandleUpdateAutomod(w http.ResponseWriter, r *http.Request) {
+	userDID, _ := atpmiddleware.GetDID(r.Context())
+
+	if h.moderationStore == nil {
+		http.Error(w, "moderation store not configured", http.StatusServiceUnavailable)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	formInt := func(key string) int {
+		n, _ := strconv.Atoi(strings.TrimSpace(r.FormValue(key)))
+		return n
+	}
+	settings := moderation.AutomodSettings{
+		AutoHideThreshold:      formInt("auto_hide_threshold"),
+		AutoHideUserThreshold:  formInt("auto_hide_user_threshold"),
+		ReportRateLimitPerHour: formInt("report_rate_limit_per_hour"),
+		MaxReportReasonLength:  formInt("max_report_reason_length"),
+	}
+	if err := settings.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.moderationStore.SaveAutomodSettings(r.Context(), settings, userDID); err != nil {
+		log.Error().Err(err).Msg("Failed to save automod settings")
+		http.Error(w, "Failed to save automod settings", http.StatusInternalServerError)
+		return
+	}
+
+	auditEntry := moderation.AuditEntry{
+		ID:       generateTID(),
+		Action:   moderation.AuditActionUpdateAutomod,
+		ActorDID: userDID,
+		Details: map[string]string{
+			"auto_hide_threshold":        strconv.Itoa(settings.AutoHideThreshold),
+			"auto_hide_user_threshold":   strconv.Itoa(settings.AutoHideUserThreshold),
+			"report_rate_limit_per_hour": strconv.Itoa(settings.ReportRateLimitPerHour),
+			"max_report_reason_length":   strconv.Itoa(settings.MaxReportReasonLength),
+		},
+		Timestamp: time.Now(),
+	}
+	if err := h.moderationStore.LogAction(r.Context(), auditEntry); err != nil {
+		log.Error().Err(err).Msg("Failed to log automod settings change")
+	}
+
+	log.Info().
+		Int("auto_hide_threshold", settings.AutoHideThreshold).
+		Int("auto_hide_user_threshold", settings.AutoHideUserThreshold).
+		Int("report_rate_limit_per_hour", settings.ReportRateLimitPerHour).
+		Int("max_report_reason_length", settings.MaxReportReasonLength).
+		Str("by", userDID).
+		Msg("Automod settings updated")
+
+	w.Header().Set("HX-Trigger", "mod-action")
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleRemoveModerator handles POST /_mod/moderators/remove. Only runtime
+// grants can be revoked here; moderators in the config file stay until the
+// file changes. Auth and admin checks are handled by RequireAdmin.
+func (h *Handler) HandleRemoveModerator(w http.ResponseWriter, r *http.Request) {
+	userDID, _ := atpmiddleware.GetDID(r.Context())
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	did, err := syntax.ParseDID(strings.TrimSpace(r.FormValue("did")))
+	if err != nil {
+		http.Error(w, "missing or invalid 'did' parameter", http.StatusBadRequest)
+		return
+	}
+	if did.String() == userDID {
+		http.Error(w, "You can't remove your own role", http.StatusBadRequest)
+		return
+	}
+
+	user := moderation.ModeratorUser{DID: did.String()}
+	if prev, ok := h.moderationService.GetModeratorUser(user.DID); ok {
+		user = *prev
+	}
+	remove := func(ctx context.Context, u moderation.ModeratorUser) error {
+		return h.moderationService.RemoveModerator(ctx, u.DID)
+	}
+	if !h.changeModerator(w, r, moderation.AuditActionRemoveModerator, user, remove) {
+		return
+	}
+
+	log.Info().
+		Str("did", user.DID).
+		Str("by", userDID).
+		Msg("Moderator removed")
+
+	w.Header().Set("HX-Trigger", "mod-action")
+	w.WriteHeader(http.StatusOK)
+}
+
+// changeModerator applies a role change and records it in the audit log,
+// writing the error response itself when the change is refused.
+func (h *Handler) changeModerator(w http.ResponseWriter, r *http.Request, action moderation.AuditAction, user moderation.ModeratorUser, apply func(context.Context, moderation.ModeratorUser) error) bool {
+	if h.moderationService == nil {
+		http.Error(w, "moderation not configured", http.StatusServiceUnavailable)
+		return false
+	}
+
+	err := apply(r.Context(), user)
+	switch {
+	case errors.Is(err, moderation.ErrUnknownRole):
+		http.Error(w, "Unknown role", http.StatusBadRequest)
+		return false
+	case errors.Is(err, moderation.ErrConfigModerator):
+		http.Error(w, "This moderator is set in the config file and can't be changed here", http.StatusConflict)
+		return false
+	case errors.Is(err, moderation.ErrNoModeratorStore):
+		http.Error(w, "moderator store not configured", http.StatusServiceUnavailable)
+		return false
+	case err != nil:
+		log.Error().Err(err).Str("did", user.DID).Str("action", string(action)).Msg("Failed to change moderator role")
+		http.Error(w, "Failed to update moderator", http.StatusInternalServerError)
+		return false
+	}
+
+	if h.moderationStore != nil {
+		actorDID, _ := atpmiddleware.GetDID(r.Context())
+		auditEntry := moderation.AuditEntry{
+			ID:        generateTID(),
+			Action:    action,
+			ActorDID:  actorDID,
+			TargetURI: user.DID,
+			Reason:    user.Note,
+			Details: map[string]string{
+				"role": string(user.Role),
+			},
+			Timestamp: time.Now(),
+		}
+		if err := h.moderationStore.LogAction(r.Context(), auditEntry); err != nil {
+			log.Error().Err(err).Str("action", string(action)).Msg("Failed to log moderator role change")
+		}
+	}
+	return true
+}
+
+// HandleAddWebhook handles POST /_mod/webhooks/add, registering a URL to be
+// sent each new feedable record. Auth and admin checks are handled by
+// RequireAdmin.
+func (h *Handler) HandleAddWebhook(w http.ResponseWriter, r *http.Request) {
+	userDID, _ := atpmiddleware.GetDID(r.Context())
+
+	if h.webhooks == nil {
+		http.Error(w, "webhooks not configured", http.StatusServiceUnavailable)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	hook, err := h.webhooks.Store().Add(r.Context(), strings.TrimSpace(r.FormValue("url")), userDID)
+	if errors.Is(err, webhooks.ErrInvalidURL) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to add webhook")
+		http.Error(w, "Failed to add webhook", http.StatusInternalServerError)
+		return
+	}
+	h.logWebhookChange(r.Context(), moderation.AuditActionAddWebhook, userDID, hook)
+
+	log.Info().Str("id", hook.ID).Str("url", hook.URL).Str("by", userDID).Msg("Webhook added")
+
+	w.Header().Set("HX-Trigger", "mod-action")
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleRemoveWebhook handles POST /_mod/webhooks/remove. Deliveries already
+// in flight still finish. Auth and admin checks are handled by RequireAdmin.
+func (h *Handler) HandleRemoveWebhook(w http.ResponseWriter, r *http.Request) {
+	userDID, _ := atpmiddleware.GetDID(r.Context())
+
+	if h.webhooks == nil {
+		http.Error(w, "webhooks not configured", http.StatusServiceUnavailable)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	id := strings.TrimSpace(r.FormValue("id"))
+	if id == "" {
+		http.Error(w, "missing 'id' parameter", http.StatusBadRequest)
+		return
+	}
+
+	hook := webhooks.Webhook{ID: id}
+	if hooks, err := h.webhooks.Store().List(r.Context()); err == nil {
+		for _, existing := range hooks {
+			if existing.ID == id {
+				hook = existing
+			}
+		}
+	}
+	if err := h.webhooks.Store().Remove(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("id", id).Msg("Failed to remove webhook")
+		http.Error(w, "Failed to remove webhook", http.StatusInternalServerError)
+		return
+	}
+	h.logWebhookChange(r.Context(), moderation.AuditActionRemoveWebhook, userDID, hook)
+
+	log.Info().Str("id", id).Str("by", userDID).Msg("Webhook removed")
+
+	w.Header().Set("HX-Trigger", "mod-action")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) logWebhookChange(ctx context.Context, action moderation.AuditAction, actorDID string, hook webhooks.Webhook) {
+	if h.moderationStore == nil {
+		return
+	}
+	auditEntry := moderation.AuditEntry{
+		ID:        generateTID(),
+		Action:    action,
+		ActorDID:  actorDID,
+		TargetURI: hook.URL,
+		Details:   map[string]string{"id": hook.ID},
+		Timestamp: time.Now(),
+	}
+	if err := h.moderationStore.LogAction(ctx, auditEntry); err != nil {
+		log.Error().Err(err).Str("action", string(action)).Msg("Failed to log webhook change")
+	}
+}
+
 // collectAdminStats gathers current system statistics from available data sources.
 func (h *Handler) collectAdminStats(ctx context.Context) sharedpages.AdminStats {
 	var stats sharedpages.AdminStats
 
 	if h.feedIndex != nil {
 		stats.KnownUsers = h.feedIndex.KnownDIDCount()
+		stats.ActiveUsers = h.feedIndex.ActiveDIDCount()
 		stats.IndexedRecords = h.feedIndex.RecordCount()
 		stats.TotalLikes = h.feedIndex.TotalLikeCount()
 		stats.TotalComments = h.feedIndex.TotalCommentCount()
@@ -710,6 +1333,47 @@ func (h *Handler) HandleAdminStats(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// adminStatsJSON is the machine-readable form of AdminStats served at
+// /_mod/stats.json for scraping into dashboards.
+type adminStatsJSON struct {
+	KnownUsers          int            `json:"known_users"`
+	ActiveUsers         int            `json:"active_users"`
+	RegisteredUsers     int            `json:"registered_users"`
+	IndexedRecords      int            `json:"indexed_records"`
+	TotalLikes          int            `json:"total_likes"`
+	TotalComments       int            `json:"total_comments"`
+	FirehoseConnected   bool           `json:"firehose_connected"`
+	RecordsByCollection map[string]int `json:"records_by_collection"`
+	DatabaseSizeBytes   int64          `json:"database_size_bytes"`
+}
+
+// HandleAdminStatsJSON returns the admin stats panel data as JSON, plus the
+// on-disk size of the index database. Admin-only via RequireAdmin.
+func (h *Handler) HandleAdminStatsJSON(w http.ResponseWriter, r *http.Request) {
+	stats := h.collectAdminStats(r.Context())
+	resp := adminStatsJSON{
+		KnownUsers:          stats.KnownUsers,
+		ActiveUsers:         stats.ActiveUsers,
+		RegisteredUsers:     stats.RegisteredUsers,
+		IndexedRecords:      stats.IndexedRecords,
+		TotalLikes:          stats.TotalLikes,
+		TotalComments:       stats.TotalComments,
+		FirehoseConnected:   stats.FirehoseConnected,
+		RecordsByCollection: stats.RecordsByCollection,
+	}
+	if resp.RecordsByCollection == nil {
+		resp.RecordsByCollection = map[string]int{}
+	}
+	if h.feedIndex != nil {
+		size, err := h.feedIndex.DatabaseSize(r.Context())
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to read index database size")
+		}
+		resp.DatabaseSizeBytes = size
+	}
+	WriteJSON(w, resp, "admin stats")
+}
+
 // exportedRecord is the per-record shape in the witness export payload.
 type exportedRecord struct {
 	URI       string          `json:"uri"`
@@ -905,6 +1569,89 @@ func (h *Handler) HandleAdminRebuildDID(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// HandleAdminReindex starts rebuilding the whole witness cache from the
+// PDSes of every DID the index knows about. It is the recovery path for a
+// corrupted index or a change to how records are indexed, short of deleting
+// the database. The rebuild runs in the background; the response is the
+// job's initial status and HandleAdminReindexStatus reports progress. Auth
+// and admin checks are handled by RequireAdmin.
+func (h *Handler) HandleAdminReindex(w http.ResponseWriter, r *http.Request) {
+	if h.feedIndex == nil || h.firehoseConsumer == nil {
+		http.Error(w, "feed index not configured", http.StatusServiceUnavailable)
+		return
+	}
+	actor, _ := atpmiddleware.GetDID(r.Context())
+
+	dids, err := h.feedIndex.IndexedDIDs(r.Context())
+	if err != nil {
+		log.Error().Err(err).Str("actor", actor).Msg("admin reindex: listing DIDs failed")
+		http.Error(w, "reindex failed", http.StatusInternalServerError)
+		return
+	}
+	// The job outlives this request, so it must not inherit its cancellation.
+	err = h.firehoseConsumer.StartReindex(context.WithoutCancel(r.Context()), dids)
+	if errors.Is(err, firehose.ErrReindexRunning) {
+		http.Error(w, "a reindex is already running", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("actor", actor).Msg("admin reindex: start failed")
+		http.Error(w, "reindex failed", http.StatusInternalServerError)
+		return
+	}
+
+	log.Warn().Str("actor", actor).Int("dids", len(dids)).Msg("admin reindex: started full rebuild")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(h.firehoseConsumer.ReindexStatus())
+}
+
+// HandleAdminReindexStatus reports the progress of the running full
+// rebuild, or how the last one ended. Auth and admin checks are handled by
+// RequireAdmin.
+func (h *Handler) HandleAdminReindexStatus(w http.ResponseWriter, r *http.Request) {
+	if h.firehoseConsumer == nil {
+		http.Error(w, "feed index not configured", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.firehoseConsumer.ReindexStatus())
+}
+
+// HandleAdminVerifyIndex checks the like and comment counts stored on
+// explore documents against the likes and comments tables. GET only
+// reports mismatches; POST also repairs them. Auth and admin checks are
+// handled by RequireAdmin.
+func (h *Handler) HandleAdminVerifyIndex(w http.ResponseWriter, r *http.Request) {
+	if h.feedIndex == nil {
+		http.Error(w, "feed index not configured", http.StatusServiceUnavailable)
+		return
+	}
+	actor, _ := atpmiddleware.GetDID(r.Context())
+	repair := r.Method == http.MethodPost
+
+	start := time.Now()
+	report, err := h.feedIndex.Verify(r.Context(), repair)
+	if err != nil {
+		log.Error().Err(err).Str("actor", actor).Msg("admin verify index: failed")
+		http.Error(w, "verify failed", http.StatusInternalServerError)
+		return
+	}
+
+	log.Info().
+		Str("actor", actor).
+		Bool("repair", repair).
+		Int("checked", report.Checked).
+		Int("mismatches", len(report.Mismatches)).
+		Int("repaired", report.Repaired).
+		Dur("duration", time.Since(start)).
+		Msg("admin verify index: complete")
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(report)
+}
+
 // HandleAdminRefreshHandles re-fetches every cached profile from the AppView so
 // stale handles get corrected. A less-destructive alternative to purge+rebuild
 // when the only thing wrong with a profile is a stale handle from an identity-
@@ -935,6 +1682,48 @@ func (h *Handler) HandleAdminRefreshHandles(w http.ResponseWriter, r *http.Reque
 	})
 }
 
+// HandleAdminRefreshProfile drops one DID's cached profile and re-fetches it,
+// for when a user reports a stale handle that didn't heal on its own. Auth
+// and moderator checks are handled by RequireModerator.
+func (h *Handler) HandleAdminRefreshProfile(w http.ResponseWriter, r *http.Request) {
+	didInput := strings.TrimSpace(r.URL.Query().Get("did"))
+	if didInput == "" {
+		if err := r.ParseForm(); err == nil {
+			didInput = strings.TrimSpace(r.FormValue("did"))
+		}
+	}
+	did, err := syntax.ParseDID(didInput)
+	if err != nil {
+		http.Error(w, "missing or invalid 'did' parameter", http.StatusBadRequest)
+		return
+	}
+	if h.feedIndex == nil {
+		http.Error(w, "feed index not configured", http.StatusServiceUnavailable)
+		return
+	}
+	actor, _ := atpmiddleware.GetDID(r.Context())
+
+	h.feedIndex.InvalidateProfile(did.String())
+	h.feedIndex.InvalidatePublicCachesForDID(did.String())
+
+	handle := ""
+	profile, err := h.feedIndex.GetProfile(r.Context(), did.String())
+	if err != nil {
+		// The cache stays empty, so the next page view retries the fetch.
+		log.Warn().Err(err).Str("did", did.String()).Str("actor", actor).Msg("admin refresh profile: fetch failed")
+	} else if profile != nil {
+		handle = profile.Handle
+	}
+	log.Info().Str("did", did.String()).Str("handle", handle).Str("actor", actor).Msg("admin refresh profile: complete")
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"did":       did.String(),
+		"handle":    handle,
+		"refetched": err == nil,
+	})
+}
+
 // pdsRecord is the per-record shape in the PDS fetch payload.
 type pdsRecord struct {
 	URI    string         `json:"uri"`
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	sharedpages "tangled.org/arabica.social/arabica/internal/web/pages"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxBulkItems caps how many items one bulk request may touch.
+const maxBulkItems = 100
+
+// HandleBulkHide handles POST /_mod/bulk/hide. It hides every "uri" value
+// plus the subject of every "id" (report ID) value, so the report queue can
+// submit its checkboxes directly. Each item is attempted independently and
+// the dashboard is re-rendered with a per-item outcome.
+// Auth and permission checks are handled by RequirePermission middleware.
+func (h *Handler) HandleBulkHide(w http.ResponseWriter, r *http.Request) {
+	userDID, _ := atpmiddleware.GetDID(r.Context())
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result := &sharedpages.BulkResult{Action: "Hide"}
+	var uris []string
+	seen := make(map[string]bool)
+	addURI := func(uri string) {
+		if uri != "" && !seen[uri] {
+			seen[uri] = true
+			uris = append(uris, uri)
+		}
+	}
+	for _, uri := range formList(r, "uri") {
+		addURI(uri)
+	}
+	for _, id := range formList(r, "id") {
+		report, err := h.moderationStore.GetReport(ctx, id)
+		if err != nil || report == nil {
+			result.Failed = append(result.Failed, sharedpages.BulkFailure{Item: id, Error: "report not found"})
+			continue
+		}
+		addURI(report.SubjectURI)
+	}
+	if !checkBulkSize(w, len(uris)+len(result.Failed)) {
+		return
+	}
+
+	reason := strings.TrimSpace(r.FormValue("reason"))
+	if reason == "" {
+		reason = "Bulk hidden by moderator"
+	}
+	h.runBulk(ctx, result, uris, func(ctx context.Context, uri string) error {
+		return h.hideRecord(ctx, uri, reason, userDID)
+	})
+
+	log.Info().
+		Int("succeeded", len(result.Succeeded)).
+		Int("failed", len(result.Failed)).
+		Str("by", userDID).
+		Msg("Bulk hide")
+
+	h.renderBulkResult(w, r, userDID, result)
+}
+
+// HandleBulkDismiss handles POST /_mod/bulk/dismiss for a list of report
+// "id" values. Like HandleBulkHide it never aborts part-way.
+// Auth and permission checks are handled by RequirePermission middleware.
+func (h *Handler) HandleBulkDismiss(w http.ResponseWriter, r *http.Request) {
+	userDID, _ := atpmiddleware.GetDID(r.Context())
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	ids := formList(r, "id")
+	if !checkBulkSize(w, len(ids)) {
+		return
+	}
+
+	result := &sharedpages.BulkResult{Action: "Dismiss"}
+	h.runBulk(r.Context(), result, ids, func(ctx context.Context, id string) error {
+		return h.dismissReport(ctx, id, userDID)
+	})
+
+	log.Info().
+		Int("succeeded", len(result.Succeeded)).
+		Int("failed", len(result.Failed)).
+		Str("by", userDID).
+		Msg("Bulk dismiss")
+
+	h.renderBulkResult(w, r, userDID, result)
+}
+
+// runBulk applies fn to each item, sorting them into result by outcome.
+func (h *Handler) runBulk(ctx context.Context, result *sharedpages.BulkResult, items []string, fn func(context.Context, string) error) {
+	for _, item := range items {
+		if err := fn(ctx, item); err != nil {
+			log.Warn().Err(err).Str("item", item).Str("action", result.Action).Msg("Bulk moderation item failed")
+			result.Failed = append(result.Failed, sharedpages.BulkFailure{Item: item, Error: err.Error()})
+			continue
+		}
+		result.Succeeded = append(result.Succeeded, item)
+	}
+}
+
+func (h *Handler) renderBulkResult(w http.ResponseWriter, r *http.Request, userDID string, result *sharedpages.BulkResult) {
+	props := h.buildAdminProps(r.Context(), userDID, r.FormValue("report_category"))
+	props.BulkResult = result
+
+	w.Header().Set("HX-Trigger", fmt.Sprintf(`{"notify":{"message":%q}}`, result.Summary()))
+	if err := sharedpages.AdminDashboardBody(props).Render(r.Context(), w); err != nil {
+		log.Error().Err(err).Msg("Failed to render bulk moderation result")
+	}
+}
+
+// formList returns the non-empty, trimmed values of a repeated form field.
+func formList(r *http.Request, key string) []string {
+	var out []string
+	for _, v := range r.Form[key] {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func checkBulkSize(w http.ResponseWriter, n int) bool {
+	switch {
+	case n == 0:
+		http.Error(w, "Select at least one item", http.StatusBadRequest)
+		return false
+	case n > maxBulkItems:
+		http.Error(w, fmt.Sprintf("At most %d items can be changed at once", maxBulkItems), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
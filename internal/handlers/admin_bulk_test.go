@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/firehose"
+	"tangled.org/arabica.social/arabica/internal/moderation"
+	moderationsqlite "tangled.org/arabica.social/arabica/internal/moderation/sqlite"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+)
+
+func TestBulkModeration(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "moderators.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{
+		"roles": {"moderator": {"permissions": ["hide_record", "view_reports", "dismiss_report", "view_audit_log"]}},
+		"users": [{"did": "did:plc:mod", "role": "moderator"}]
+	}`), 0o644))
+	svc, err := moderation.NewService(configPath)
+	require.NoError(t, err)
+
+	newHandler := func(t *testing.T) (*Handler, *moderationsqlite.ModerationStore) {
+		idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+		require.NoError(t, err)
+		t.Cleanup(func() { idx.Close() })
+		store := moderationsqlite.NewModerationStore(idx.DB())
+		for _, id := range []string{"r1", "r2"} {
+			require.NoError(t, store.CreateReport(context.Background(), moderation.Report{
+				ID:          id,
+				SubjectURI:  "at://did:plc:bob/social.arabica.alpha.brew/" + id,
+				SubjectDID:  "did:plc:bob",
+				ReporterDID: "did:plc:alice",
+				Reason:      "spam",
+				CreatedAt:   time.Now(),
+				Status:      moderation.ReportStatusPending,
+			}))
+		}
+		h := &Handler{}
+		h.SetModeration(svc, store)
+		return h, store
+	}
+
+	post := func(handler http.HandlerFunc, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/_mod/bulk", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(atpmiddleware.ContextWithAuth(req.Context(), "did:plc:mod", "sess"))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	t.Run("dismiss reports partial failure", func(t *testing.T) {
+		h, store := newHandler(t)
+		rec := post(h.HandleBulkDismiss, url.Values{"id": {"r1", "missing", "r2"}})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get("HX-Trigger"), "Dismiss: 2 done, 1 failed")
+		assert.Contains(t, rec.Body.String(), "missing")
+
+		pending, err := store.ListPendingReports(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+
+		audit, err := store.ListAuditLog(context.Background(), 10)
+		require.NoError(t, err)
+		assert.Len(t, audit, 2, "one audit entry per successful dismissal")
+	})
+
+	t.Run("hide by report id and uri", func(t *testing.T) {
+		h, store := newHandler(t)
+		extra := "at://did:plc:carol/social.arabica.alpha.bean/x"
+		rec := post(h.HandleBulkHide, url.Values{
+			"id":  {"r1", "nope"},
+			"uri": {extra, "at://did:plc:bob/social.arabica.alpha.brew/r1"},
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get("HX-Trigger"), "Hide: 2 done, 1 failed")
+		assert.True(t, store.IsRecordHidden(context.Background(), extra))
+		assert.True(t, store.IsRecordHidden(context.Background(), "at://did:plc:bob/social.arabica.alpha.brew/r1"))
+		assert.False(t, store.IsRecordHidden(context.Background(), "at://did:plc:bob/social.arabica.alpha.brew/r2"))
+	})
+
+	t.Run("empty selection is rejected", func(t *testing.T) {
+		h, _ := newHandler(t)
+		assert.Equal(t, http.StatusBadRequest, post(h.HandleBulkDismiss, url.Values{}).Code)
+		assert.Equal(t, http.StatusBadRequest, post(h.HandleBulkHide, url.Values{"id": {" "}}).Code)
+	})
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/firehose"
+	"tangled.org/arabica.social/arabica/internal/moderation"
+	moderationsqlite "tangled.org/arabica.social/arabica/internal/moderation/sqlite"
+	"tangled.org/arabica.social/arabica/internal/webhooks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+)
+
+func TestHandleAdminStatsJSON(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+
+	ctx := context.Background()
+	collection := "social.arabica.alpha.roaster"
+	record := []byte(`{"$type":"social.arabica.alpha.roaster","name":"Test","createdAt":"2025-01-01T00:00:00Z"}`)
+	require.NoError(t, idx.UpsertRecord(ctx, "did:plc:alice", collection, "r1", "cid1", record, 0))
+	require.NoError(t, idx.UpsertLike(ctx, "did:plc:bob", "l1", "at://did:plc:alice/"+collection+"/r1"))
+
+	h := &Handler{}
+	h.SetFeedIndex(idx)
+
+	w := httptest.NewRecorder()
+	h.HandleAdminStatsJSON(w, httptest.NewRequest("GET", "/_mod/stats.json", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var got adminStatsJSON
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, 1, got.IndexedRecords)
+	assert.Equal(t, 1, got.TotalLikes)
+	assert.Equal(t, map[string]int{collection: 1}, got.RecordsByCollection)
+	assert.Positive(t, got.DatabaseSizeBytes)
+}
+
+func TestHandleAdminRefreshProfileRejectsBadInput(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		withIndex  bool
+		wantStatus int
+	}{
+		{"missing did", "/_mod/refresh-profile", true, http.StatusBadRequest},
+		{"handle instead of did", "/_mod/refresh-profile?did=alice.example.com", true, http.StatusBadRequest},
+		{"no feed index", "/_mod/refresh-profile?did=did:plc:alice", false, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &Handler{}
+			if tt.withIndex {
+				idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+				require.NoError(t, err)
+				t.Cleanup(func() { idx.Close() })
+				h.SetFeedIndex(idx)
+			}
+
+			w := httptest.NewRecorder()
+			h.HandleAdminRefreshProfile(w, httptest.NewRequest(http.MethodPost, tt.target, nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestHandleAdminReindex(t *testing.T) {
+	h := &Handler{}
+	w := httptest.NewRecorder()
+	h.HandleAdminReindex(w, httptest.NewRequest(http.MethodPost, "/_mod/reindex", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+	h.SetFeedIndex(idx)
+	h.SetFirehoseConsumer(firehose.NewConsumer(firehose.DefaultConfig(), idx))
+
+	w = httptest.NewRecorder()
+	h.HandleAdminReindex(w, httptest.NewRequest(http.MethodPost, "/_mod/reindex", nil))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var started firehose.ReindexStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &started))
+	assert.False(t, started.StartedAt.IsZero())
+
+	require.Eventually(t, func() bool {
+		w := httptest.NewRecorder()
+		h.HandleAdminReindexStatus(w, httptest.NewRequest(http.MethodGet, "/_mod/reindex", nil))
+		var status firehose.ReindexStatus
+		return w.Code == http.StatusOK && json.Unmarshal(w.Body.Bytes(), &status) == nil && !status.Running
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestHandleAdminVerifyIndex(t *testing.T) {
+	h := &Handler{}
+	w := httptest.NewRecorder()
+	h.HandleAdminVerifyIndex(w, httptest.NewRequest(http.MethodGet, "/_mod/verify", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+	h.SetFeedIndex(idx)
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		t.Run(method, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.HandleAdminVerifyIndex(w, httptest.NewRequest(method, "/_mod/verify", nil))
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+			var report firehose.VerifyReport
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+			assert.Empty(t, report.Mismatches)
+		})
+	}
+}
+
+func TestHandleWebhooks(t *testing.T) {
+	post := func(h *Handler, handle http.HandlerFunc, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/_mod/webhooks", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(atpmiddleware.ContextWithAuth(req.Context(), "did:plc:admin", "sess"))
+		rec := httptest.NewRecorder()
+		handle(rec, req)
+		return rec
+	}
+
+	h := &Handler{}
+	assert.Equal(t, http.StatusServiceUnavailable, post(h, h.HandleAddWebhook, url.Values{"url": {"https://example.com/hook"}}).Code)
+
+	idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+	ctx := context.Background()
+	modStore := moderationsqlite.NewModerationStore(idx.DB())
+	h.SetModeration(nil, modStore)
+	svc := webhooks.NewService(webhooks.NewStore(idx.DB()), webhooks.Config{})
+	h.SetWebhooks(svc)
+
+	assert.Equal(t, http.StatusBadRequest, post(h, h.HandleAddWebhook, url.Values{"url": {"not a url"}}).Code)
+
+	rec := post(h, h.HandleAddWebhook, url.Values{"url": {" https://example.com/hook "}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "mod-action", rec.Header().Get("HX-Trigger"))
+	hooks, err := svc.Store().List(ctx)
+	require.NoError(t, err)
+	require.Len(t, hooks, 1)
+	assert.Equal(t, "https://example.com/hook", hooks[0].URL)
+	assert.Equal(t, "did:plc:admin", hooks[0].CreatedBy)
+
+	assert.Equal(t, http.StatusBadRequest, post(h, h.HandleRemoveWebhook, url.Values{}).Code)
+	require.Equal(t, http.StatusOK, post(h, h.HandleRemoveWebhook, url.Values{"id": {hooks[0].ID}}).Code)
+	hooks, err = svc.Store().List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, hooks)
+
+	audit, err := modStore.ListAuditLog(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, audit, 2)
+	assert.ElementsMatch(t, []moderation.AuditAction{moderation.AuditActionAddWebhook, moderation.AuditActionRemoveWebhook},
+		[]moderation.AuditAction{audit[0].Action, audit[1].Action})
+	assert.Equal(t, "https://example.com/hook", audit[0].TargetURI)
+}
+
+// chanLabeler hands every emitted label to the test.
+type chanLabeler chan moderation.LabelEvent
+
+func (c chanLabeler) EmitLabel(_ context.Context, ev moderation.LabelEvent) error {
+	c <- ev
+	return nil
+}
+
+func TestHideRecordEmitsLabel(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+	ctx := context.Background()
+
+	collection := "social.arabica.alpha.roaster"
+	indexed := "at://did:plc:bob/" + collection + "/r1"
+	require.NoError(t, idx.UpsertRecord(ctx, "did:plc:bob", collection, "r1", "cid-r1",
+		[]byte(`{"$type":"social.arabica.alpha.roaster","name":"r1","createdAt":"2026-05-01T00:00:00Z"}`), time.Now().Unix()))
+
+	labels := make(chanLabeler, 1)
+	h := &Handler{}
+	h.SetFeedIndex(idx)
+	h.SetModeration(nil, moderationsqlite.NewModerationStore(idx.DB()))
+	h.SetLabeler(labels)
+
+	post := func(handler http.HandlerFunc, uri string) {
+		form := url.Values{"uri": {uri}, "reason": {"spam"}}
+		req := httptest.NewRequest(http.MethodPost, "/_mod/hide", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(atpmiddleware.ContextWithAuth(req.Context(), "did:plc:mod", "sess"))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+	next := func() moderation.LabelEvent {
+		select {
+		case ev := <-labels:
+			return ev
+		case <-time.After(5 * time.Second):
+			t.Fatal("no label emitted")
+			return moderation.LabelEvent{}
+		}
+	}
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		negate  bool
+	}{
+		{"hide", h.HandleHideRecord, false},
+		{"unhide", h.HandleUnhideRecord, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post(tt.handler, indexed)
+			assert.Equal(t, moderation.LabelEvent{
+				URI:    indexed,
+				CID:    "cid-r1",
+				Val:    moderation.LabelHide,
+				Negate: tt.negate,
+				Reason: "spam",
+			}, next())
+		})
+	}
+
+	t.Run("record missing from the index", func(t *testing.T) {
+		post(h.HandleHideRecord, "at://did:plc:bob/"+collection+"/gone")
+		select {
+		case ev := <-labels:
+			t.Fatalf("unexpected label %+v", ev)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
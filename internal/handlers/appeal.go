@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/moderation"
+	moderationsqlite "tangled.org/arabica.social/arabica/internal/moderation/sqlite"
+	"tangled.org/arabica.social/arabica/internal/web/components"
+	"tangled.org/pdewey.com/atp"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+
+	"github.com/rs/zerolog/log"
+)
+
+// MaxAppealLength caps the free-text body of an appeal.
+const MaxAppealLength = 1000
+
+// HandleAppeal lets the owner of a hidden record ask moderators to restore
+// it. Like HandleReport it always answers 200 with an HTML partial, since
+// htmx won't swap error responses.
+func (h *Handler) HandleAppeal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := r.ParseForm(); err != nil {
+		writeAppealForm(ctx, w, "", "", "Invalid form data")
+		return
+	}
+	subjectURI := r.FormValue("subject_uri")
+	rawText := r.FormValue("text")
+
+	userDID, ok := atpmiddleware.GetDID(ctx)
+	if !ok {
+		writeAppealForm(ctx, w, subjectURI, rawText, "Authentication required")
+		return
+	}
+	if h.moderationStore == nil {
+		writeAppealForm(ctx, w, subjectURI, rawText, "Appeals are not enabled")
+		return
+	}
+	if !isRecordOwner(subjectURI, userDID) {
+		writeAppealForm(ctx, w, subjectURI, rawText, "You can only appeal your own records")
+		return
+	}
+	if !h.moderationStore.IsRecordHidden(ctx, subjectURI) {
+		writeAppealForm(ctx, w, subjectURI, rawText, "This record is not hidden")
+		return
+	}
+
+	text := strings.TrimSpace(rawText)
+	if text == "" {
+		writeAppealForm(ctx, w, subjectURI, rawText, "Please explain why the record should be restored")
+		return
+	}
+	if len(text) > MaxAppealLength {
+		text = text[:MaxAppealLength]
+	}
+
+	appeal := moderation.Appeal{
+		URI:       subjectURI,
+		DID:       userDID,
+		Text:      text,
+		CreatedAt: time.Now(),
+	}
+	err := h.moderationStore.CreateAppeal(ctx, appeal)
+	if errors.Is(err, moderationsqlite.ErrAppealExists) {
+		writeAppealForm(ctx, w, subjectURI, rawText, "You have already appealed this record")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("uri", subjectURI).Msg("moderation: failed to create appeal")
+		writeAppealForm(ctx, w, subjectURI, rawText, "Failed to save appeal")
+		return
+	}
+
+	log.Info().
+		Str("uri", subjectURI).
+		Str("did", userDID).
+		Msg("moderation: appeal submitted")
+
+	w.Header().Set("HX-Trigger", `{"notify":{"message":"Appeal submitted"}}`)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := components.AppealSubmitted().Render(ctx, w); err != nil {
+		log.Error().Err(err).Msg("moderation: failed to render appeal confirmation")
+	}
+}
+
+// isRecordOwner reports whether uri is an AT-URI in did's repo.
+func isRecordOwner(uri, did string) bool {
+	if did == "" {
+		return false
+	}
+	parts, err := atp.ParseATURI(uri)
+	return err == nil && parts.DID == did
+}
+
+func writeAppealForm(ctx context.Context, w http.ResponseWriter, subjectURI, text, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = components.AppealForm(components.AppealFormProps{
+		SubjectURI:   subjectURI,
+		Text:         text,
+		ErrorMessage: message,
+	}).Render(ctx, w)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/firehose"
+	"tangled.org/arabica.social/arabica/internal/moderation"
+	moderationsqlite "tangled.org/arabica.social/arabica/internal/moderation/sqlite"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+)
+
+func TestHandleAppeal(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+	store := moderationsqlite.NewModerationStore(idx.DB())
+	h := &Handler{}
+	h.SetModeration(nil, store)
+
+	ctx := context.Background()
+	hiddenURI := "at://did:plc:alice/social.arabica.alpha.brew/hidden"
+	require.NoError(t, store.HideRecord(ctx, moderation.HiddenRecord{
+		ATURI: hiddenURI, HiddenAt: time.Now(), HiddenBy: "automod", AutoHidden: true,
+	}))
+
+	appeal := func(did, uri, text string) string {
+		form := url.Values{"subject_uri": {uri}, "text": {text}}
+		req := httptest.NewRequest(http.MethodPost, "/api/appeal", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(atpmiddleware.ContextWithAuth(req.Context(), did, "sess"))
+		rec := httptest.NewRecorder()
+		h.HandleAppeal(rec, req)
+		return rec.Body.String()
+	}
+
+	// Order matters: the successful appeal makes the last case a duplicate.
+	tests := []struct {
+		name     string
+		did      string
+		uri      string
+		text     string
+		wantBody string
+	}{
+		{"only the owner may appeal", "did:plc:mallory", hiddenURI, "please", "only appeal your own records"},
+		{"record must be hidden", "did:plc:alice", "at://did:plc:alice/social.arabica.alpha.brew/visible", "please", "is not hidden"},
+		{"text is required", "did:plc:alice", hiddenURI, "  ", "Please explain"},
+		{"owner appeals", "did:plc:alice", hiddenURI, "It was a real brew", "Appeal submitted"},
+		{"only once per URI", "did:plc:alice", hiddenURI, "again", "already appealed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Contains(t, appeal(tt.did, tt.uri, tt.text), tt.wantBody)
+		})
+	}
+
+	got, err := store.GetAppeal(ctx, hiddenURI)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "It was a real brew", got.Text)
+	assert.Equal(t, moderation.AppealStatusPending, got.Status)
+}
+
+func TestHandleApproveAppeal(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+	store := moderationsqlite.NewModerationStore(idx.DB())
+	h := &Handler{}
+	h.SetModeration(nil, store)
+
+	ctx := context.Background()
+	uri := "at://did:plc:alice/social.arabica.alpha.brew/1"
+	require.NoError(t, store.HideRecord(ctx, moderation.HiddenRecord{ATURI: uri, HiddenAt: time.Now(), HiddenBy: "automod"}))
+	require.NoError(t, store.CreateAppeal(ctx, moderation.Appeal{URI: uri, DID: "did:plc:alice", Text: "ok", CreatedAt: time.Now()}))
+
+	post := func(handler http.HandlerFunc) int {
+		req := httptest.NewRequest(http.MethodPost, "/_mod/appeal/approve", strings.NewReader(url.Values{"uri": {uri}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(atpmiddleware.ContextWithAuth(req.Context(), "did:plc:mod", "sess"))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, post(h.HandleApproveAppeal))
+	assert.False(t, store.IsRecordHidden(ctx, uri))
+
+	got, err := store.GetAppeal(ctx, uri)
+	require.NoError(t, err)
+	assert.Equal(t, moderation.AppealStatusApproved, got.Status)
+	assert.Equal(t, "did:plc:mod", got.ResolvedBy)
+
+	entries, err := store.ListAuditLog(ctx, 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	assert.Equal(t, moderation.AuditActionApproveAppeal, entries[0].Action)
+
+	assert.Equal(t, http.StatusNotFound, post(h.HandleRejectAppeal), "resolved appeals can't be decided twice")
+}
+
+func TestHandleUnhideRecordApprovesPendingAppeal(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+	store := moderationsqlite.NewModerationStore(idx.DB())
+	h := &Handler{}
+	h.SetModeration(nil, store)
+
+	ctx := context.Background()
+	appealed := "at://did:plc:alice/social.arabica.alpha.brew/1"
+	unappealed := "at://did:plc:alice/social.arabica.alpha.brew/2"
+	for _, uri := range []string{appealed, unappealed} {
+		require.NoError(t, store.HideRecord(ctx, moderation.HiddenRecord{ATURI: uri, HiddenAt: time.Now(), HiddenBy: "automod"}))
+	}
+	require.NoError(t, store.CreateAppeal(ctx, moderation.Appeal{URI: appealed, DID: "did:plc:alice", Text: "ok", CreatedAt: time.Now()}))
+
+	post := func(handler http.HandlerFunc, uri string) int {
+		req := httptest.NewRequest(http.MethodPost, "/_mod/unhide", strings.NewReader(url.Values{"uri": {uri}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(atpmiddleware.ContextWithAuth(req.Context(), "did:plc:mod", "sess"))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusNotFound, post(h.HandleApproveAppeal, unappealed))
+	assert.True(t, store.IsRecordHidden(ctx, unappealed), "approving a missing appeal unhides nothing")
+
+	assert.Equal(t, http.StatusOK, post(h.HandleUnhideRecord, appealed))
+	assert.False(t, store.IsRecordHidden(ctx, appealed))
+	got, err := store.GetAppeal(ctx, appealed)
+	require.NoError(t, err)
+	assert.Equal(t, moderation.AppealStatusApproved, got.Status)
+	assert.Equal(t, "did:plc:mod", got.ResolvedBy)
+
+	pending, err := store.ListPendingAppeals(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
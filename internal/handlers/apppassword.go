@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"tangled.org/arabica.social/arabica/internal/atproto"
+
+	"github.com/rs/zerolog/log"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+)
+
+// appPasswordLoginRequest is the JSON body of POST /auth/app-password.
+type appPasswordLoginRequest struct {
+	Identifier string `json:"identifier"`
+	Password   string `json:"password"`
+}
+
+// appPasswordLoginResponse carries the bearer token back to the script. The
+// token is only ever shown here.
+type appPasswordLoginResponse struct {
+	Token     string `json:"token"`
+	TokenType string `json:"token_type"`
+	DID       string `json:"did"`
+}
+
+// HandleAppPasswordLogin exchanges a handle and app password for a bearer
+// token usable against the API routes. Only app passwords are accepted; see
+// the atproto package for the security tradeoffs.
+func (h *Handler) HandleAppPasswordLogin(w http.ResponseWriter, r *http.Request) {
+	if h.passwordSessions == nil {
+		http.Error(w, "App-password login is not enabled", http.StatusNotFound)
+		return
+	}
+
+	var req appPasswordLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if strings.TrimSpace(req.Identifier) == "" || req.Password == "" {
+		writeJSONError(w, http.StatusBadRequest, "identifier and password are required")
+		return
+	}
+
+	token, did, err := atproto.LoginWithAppPassword(r.Context(), h.passwordSessions, req.Identifier, req.Password)
+	if errors.Is(err, atproto.ErrNotAppPassword) {
+		writeJSONError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		// Don't log the identifier alongside a failure; it's half a
+		// credential pair.
+		log.Warn().Err(err).Msg("App-password login failed")
+		writeJSONError(w, http.StatusUnauthorized, "Invalid identifier or app password")
+		return
+	}
+	log.Info().Str("user_did", did.String()).Msg("App-password session created")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(appPasswordLoginResponse{
+		Token:     token,
+		TokenType: "Bearer",
+		DID:       did.String(),
+	}); err != nil {
+		log.Error().Err(err).Msg("Failed to encode app-password login response")
+	}
+}
+
+// HandleAppPasswordRevoke deletes the app-password session the request was
+// authenticated with. Revoking the app password on the PDS also works and
+// covers every token issued for it.
+func (h *Handler) HandleAppPasswordRevoke(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := atpmiddleware.GetSessionID(r.Context())
+	if !ok || !atproto.IsPasswordSession(sessionID) || h.passwordSessions == nil {
+		writeJSONError(w, http.StatusUnauthorized, "Bearer token required")
+		return
+	}
+	if err := h.passwordSessions.DeletePasswordSession(r.Context(), sessionID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete app-password session")
+		writeJSONError(w, http.StatusInternalServerError, "Failed to revoke token")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(map[string]string{"error": msg}); err != nil {
+		log.Error().Err(err).Msg("Failed to encode error response")
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -289,31 +290,122 @@ func (h *Handler) HandleLogout(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Clear session cookies
-	http.SetCookie(w, &http.Cookie{
-		Name:     didCookieName,
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   h.config.SecureCookies,
-		SameSite: http.SameSiteLaxMode,
-		MaxAge:   -1,
-	})
-
-	http.SetCookie(w, &http.Cookie{
-		Name:     sessCookieName,
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   h.config.SecureCookies,
-		SameSite: http.SameSiteLaxMode,
-		MaxAge:   -1,
-	})
+	h.clearAuthCookies(w)
 
 	// Redirect to home page
 	http.Redirect(w, r, "/", http.StatusFound)
 }
 
+// HandleLogoutAll signs the user out of every session, on every device,
+// including app-password tokens. Meant for use after a suspected credential
+// leak; the current browser is signed out too.
+func (h *Handler) HandleLogoutAll(w http.ResponseWriter, r *http.Request) {
+	if h.sessionRevoker == nil {
+		http.Error(w, "Session revocation not configured", http.StatusInternalServerError)
+		return
+	}
+	didStr, ok := atpmiddleware.GetDID(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	did, err := syntax.ParseDID(didStr)
+	if err != nil {
+		http.Error(w, "Invalid session", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.revokeAllSessions(r.Context(), did); err != nil {
+		log.Error().Err(err).Str("user_did", didStr).Msg("Failed to revoke sessions")
+		http.Error(w, "Failed to sign out other sessions", http.StatusInternalServerError)
+		return
+	}
+
+	h.clearAuthCookies(w)
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// revokeAllSessions deletes every stored session for did and drops the
+// per-session caches that went with them.
+func (h *Handler) revokeAllSessions(ctx context.Context, did syntax.DID) error {
+	sessionIDs, err := h.sessionRevoker.DeleteAllForDID(ctx, did)
+	if err != nil {
+		return err
+	}
+	if h.sessionCache != nil {
+		for _, id := range sessionIDs {
+			h.sessionCache.Invalidate(id)
+		}
+	}
+	log.Info().Str("user_did", did.String()).Int("sessions", len(sessionIDs)).Msg("Revoked all sessions")
+	return nil
+}
+
+// HandleAccountForget disassociates the signed-in user from this instance:
+// they leave the feed registry, everything the index holds for their DID is
+// purged, and all their sessions end. Records on their PDS are untouched.
+// Signing in again, or publishing new records the firehose picks up, brings
+// them back.
+func (h *Handler) HandleAccountForget(w http.ResponseWriter, r *http.Request) {
+	if h.sessionRevoker == nil || h.feedIndex == nil {
+		http.Error(w, "Account removal not configured", http.StatusInternalServerError)
+		return
+	}
+	didStr, ok := atpmiddleware.GetDID(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	did, err := syntax.ParseDID(didStr)
+	if err != nil {
+		http.Error(w, "Invalid session", http.StatusBadRequest)
+		return
+	}
+
+	if h.feedRegistry != nil {
+		if err := h.feedRegistry.Unregister(didStr); err != nil {
+			log.Error().Err(err).Str("user_did", didStr).Msg("Failed to unregister account")
+			http.Error(w, "Failed to remove account data", http.StatusInternalServerError)
+			return
+		}
+	}
+	if err := h.feedIndex.DeleteAllByDID(r.Context(), didStr); err != nil {
+		log.Error().Err(err).Str("user_did", didStr).Msg("Failed to purge account from index")
+		http.Error(w, "Failed to remove account data", http.StatusInternalServerError)
+		return
+	}
+	h.feedIndex.InvalidatePublicCachesForDID(didStr)
+	if err := h.revokeAllSessions(r.Context(), did); err != nil {
+		// The data is gone; stale sessions will fail on their next PDS call.
+		log.Error().Err(err).Str("user_did", didStr).Msg("Failed to revoke sessions after account purge")
+	}
+	log.Info().Str("user_did", didStr).Msg("Account forgotten at user request")
+
+	h.clearAuthCookies(w)
+	if r.Header.Get("HX-Request") == "true" {
+		w.Header().Set("HX-Redirect", "/")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// clearAuthCookies expires the DID and session cookies.
+func (h *Handler) clearAuthCookies(w http.ResponseWriter) {
+	didCookieName, sessCookieName := h.cookieNames()
+	for _, name := range []string{didCookieName, sessCookieName} {
+		http.SetCookie(w, &http.Cookie{
+			Name:     name,
+			Value:    "",
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   h.config.SecureCookies,
+			SameSite: http.SameSiteLaxMode,
+			MaxAge:   -1,
+		})
+	}
+}
+
 // HandleClientMetadata serves the OAuth client metadata
 func (h *Handler) HandleClientMetadata(w http.ResponseWriter, r *http.Request) {
 	if h.oauth == nil {
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/atproto"
+	"tangled.org/arabica.social/arabica/internal/feed"
+	"tangled.org/arabica.social/arabica/internal/firehose"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+)
+
+type fakeRevoker struct {
+	sessions map[syntax.DID][]string
+	err      error
+}
+
+func (f *fakeRevoker) DeleteAllForDID(_ context.Context, did syntax.DID) ([]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	ids := f.sessions[did]
+	delete(f.sessions, did)
+	return ids, nil
+}
+
+func TestHandleLogoutAll(t *testing.T) {
+	tests := []struct {
+		name        string
+		did         string
+		revokeErr   error
+		wantStatus  int
+		wantCleared bool
+	}{
+		{"revokes every session and clears cookies", "did:plc:alice", nil, http.StatusFound, true},
+		{"requires authentication", "", nil, http.StatusUnauthorized, false},
+		{"store failure keeps the user signed in", "did:plc:alice", errors.New("db locked"), http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoker := &fakeRevoker{
+				sessions: map[syntax.DID][]string{
+					"did:plc:alice": {"sess-1", "sess-2"},
+					"did:plc:bob":   {"sess-3"},
+				},
+				err: tt.revokeErr,
+			}
+			cache := atproto.NewSessionCache()
+			for _, id := range []string{"sess-1", "sess-2", "sess-3"} {
+				cache.Set(id, &atproto.UserCache{})
+			}
+			h := &Handler{sessionCache: cache}
+			h.SetSessionRevoker(revoker)
+
+			req := httptest.NewRequest(http.MethodPost, "/logout/all", nil)
+			if tt.did != "" {
+				req = req.WithContext(atpmiddleware.ContextWithAuth(req.Context(), tt.did, "sess-1"))
+			}
+			rec := httptest.NewRecorder()
+			h.HandleLogoutAll(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			cleared := map[string]bool{}
+			for _, c := range rec.Result().Cookies() {
+				cleared[c.Name] = c.MaxAge < 0
+			}
+			assert.Equal(t, tt.wantCleared, cleared["account_did"])
+			assert.Equal(t, tt.wantCleared, cleared["session_id"])
+
+			if tt.wantCleared {
+				assert.Nil(t, cache.Get("sess-1"))
+				assert.Nil(t, cache.Get("sess-2"))
+				assert.Empty(t, revoker.sessions["did:plc:alice"])
+			}
+			assert.NotNil(t, cache.Get("sess-3"), "other users' sessions are untouched")
+			assert.Len(t, revoker.sessions["did:plc:bob"], 1)
+		})
+	}
+}
+
+func TestHandleAccountForget(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+
+	ctx := context.Background()
+	collection := "social.arabica.alpha.brew"
+	record := []byte(`{"$type":"social.arabica.alpha.brew","createdAt":"2025-01-01T00:00:00Z"}`)
+	require.NoError(t, idx.UpsertRecord(ctx, "did:plc:alice", collection, "b1", "cid1", record, 0))
+	require.NoError(t, idx.UpsertRecord(ctx, "did:plc:bob", collection, "b2", "cid2", record, 0))
+
+	registry := feed.NewRegistry()
+	registry.Register("did:plc:alice")
+	registry.Register("did:plc:bob")
+	revoker := &fakeRevoker{sessions: map[syntax.DID][]string{
+		"did:plc:alice": {"sess-1"},
+		"did:plc:bob":   {"sess-2"},
+	}}
+
+	h := &Handler{feedRegistry: registry}
+	h.SetFeedIndex(idx)
+	h.SetSessionRevoker(revoker)
+
+	req := httptest.NewRequest(http.MethodPost, "/account/forget", nil)
+	req = req.WithContext(atpmiddleware.ContextWithAuth(req.Context(), "did:plc:alice", "sess-1"))
+	rec := httptest.NewRecorder()
+	h.HandleAccountForget(rec, req)
+
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.False(t, registry.IsRegistered("did:plc:alice"))
+	assert.True(t, registry.IsRegistered("did:plc:bob"))
+	assert.Empty(t, revoker.sessions["did:plc:alice"])
+	assert.Len(t, revoker.sessions["did:plc:bob"], 1)
+
+	gone, err := idx.GetRecord(ctx, "at://did:plc:alice/"+collection+"/b1")
+	assert.NoError(t, err)
+	assert.Nil(t, gone)
+	kept, err := idx.GetRecord(ctx, "at://did:plc:bob/"+collection+"/b2")
+	assert.NoError(t, err)
+	assert.NotNil(t, kept)
+
+	unauthenticated := httptest.NewRecorder()
+	h.HandleAccountForget(unauthenticated, httptest.NewRequest(http.MethodPost, "/account/forget", nil))
+	assert.Equal(t, http.StatusUnauthorized, unauthenticated.Code)
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+
+	"tangled.org/arabica.social/arabica/internal/atproto"
+	"tangled.org/arabica.social/arabica/internal/social"
+	"tangled.org/arabica.social/arabica/internal/web/components"
+	"tangled.org/arabica.social/arabica/internal/web/pages"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxBookmarksShown caps how many bookmarks the page resolves. Each one is a
+// public record fetch, so the oldest are dropped rather than stalling the page.
+const maxBookmarksShown = 100
+
+// bookmarkResolveConcurrency bounds parallel record fetches for the page.
+const bookmarkResolveConcurrency = 8
+
+type bookmarkStore interface {
+	CreateBookmark(ctx context.Context, req *social.CreateBookmarkRequest) (*social.Bookmark, error)
+	DeleteBookmarkByRKey(ctx context.Context, rkey string) error
+	ListUserBookmarks(ctx context.Context) ([]*social.Bookmark, error)
+}
+
+func (h *Handler) getBookmarkStore(r *http.Request) (bookmarkStore, bool) {
+	store, ok := h.GetRecordStore(r)
+	if !ok {
+		return nil, false
+	}
+	bookmarks, ok := store.(bookmarkStore)
+	return bookmarks, ok
+}
+
+// HandleBookmarkToggle bookmarks or un-bookmarks subject_uri and returns the
+// updated bookmark button.
+func (h *Handler) HandleBookmarkToggle(w http.ResponseWriter, r *http.Request) {
+	store, authenticated := h.getBookmarkStore(r)
+	if !authenticated {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	if h.feedIndex == nil {
+		http.Error(w, "Bookmarks are not available", http.StatusNotImplemented)
+		return
+	}
+
+	didStr, _ := atpmiddleware.GetDID(r.Context())
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+	subjectURI := r.FormValue("subject_uri")
+	subjectCID := r.FormValue("subject_cid")
+	if subjectURI == "" || subjectCID == "" {
+		http.Error(w, "subject_uri and subject_cid are required", http.StatusBadRequest)
+		return
+	}
+
+	isBookmarked := false
+	if rkey := h.feedIndex.GetUserBookmarkRKey(r.Context(), didStr, subjectURI); rkey != "" {
+		if err := store.DeleteBookmarkByRKey(r.Context(), rkey); err != nil {
+			log.Error().Err(err).Str("subject_uri", subjectURI).Msg("Failed to delete bookmark")
+			HandleStoreError(w, err, "Failed to remove bookmark")
+			return
+		}
+		if err := h.feedIndex.DeleteBookmark(r.Context(), didStr, rkey); err != nil {
+			log.Warn().Err(err).Str("did", didStr).Str("subject_uri", subjectURI).Msg("Failed to delete bookmark from feed index")
+		}
+	} else {
+		bookmark, err := store.CreateBookmark(r.Context(), &social.CreateBookmarkRequest{
+			SubjectURI: subjectURI,
+			SubjectCID: subjectCID,
+		})
+		if err != nil {
+			log.Error().Err(err).Str("subject_uri", subjectURI).Msg("Failed to create bookmark")
+			HandleStoreError(w, err, "Failed to bookmark")
+			return
+		}
+		isBookmarked = true
+		if err := h.feedIndex.UpsertBookmark(r.Context(), didStr, bookmark.RKey, subjectURI); err != nil {
+			log.Warn().Err(err).Str("did", didStr).Str("subject_uri", subjectURI).Msg("Failed to upsert bookmark in feed index")
+		}
+	}
+
+	if err := components.BookmarkButton(subjectURI, subjectCID, isBookmarked).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render button", http.StatusInternalServerError)
+		log.Error().Err(err).Msg("Failed to render bookmark button")
+	}
+}
+
+// HandleBookmarks renders the signed-in user's bookmarks, newest first.
+func (h *Handler) HandleBookmarks(w http.ResponseWriter, r *http.Request) {
+	layoutData, _, isAuthenticated := h.LayoutDataFromRequest(r, "Bookmarks")
+	if !isAuthenticated {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	store, ok := h.getBookmarkStore(r)
+	if !ok {
+		http.Error(w, "Bookmarks are not available", http.StatusNotImplemented)
+		return
+	}
+
+	bookmarks, err := store.ListUserBookmarks(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list bookmarks")
+		HandleStoreError(w, err, "Failed to load bookmarks")
+		return
+	}
+	sort.Slice(bookmarks, func(i, j int) bool {
+		return bookmarks[i].CreatedAt.After(bookmarks[j].CreatedAt)
+	})
+	if len(bookmarks) > maxBookmarksShown {
+		bookmarks = bookmarks[:maxBookmarksShown]
+	}
+
+	props := pages.BookmarksProps{Bookmarks: h.resolveBookmarks(r.Context(), bookmarks)}
+	if err := pages.Bookmarks(layoutData, props).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		log.Error().Err(err).Msg("Failed to render bookmarks page")
+	}
+}
+
+// resolveBookmarks fetches each bookmarked record from its owner's PDS so
+// the page can show a title, and flags records that have since been deleted.
+func (h *Handler) resolveBookmarks(ctx context.Context, bookmarks []*social.Bookmark) []pages.BookmarkItem {
+	items := make([]pages.BookmarkItem, len(bookmarks))
+	profiles := h.bookmarkAuthorProfiles(ctx, bookmarks)
+	publicClient := atproto.NewPublicClient()
+	sem := make(chan struct{}, bookmarkResolveConcurrency)
+	var wg sync.WaitGroup
+
+	for i, b := range bookmarks {
+		item := pages.BookmarkItem{
+			SubjectURI: b.SubjectURI,
+			SubjectCID: b.SubjectCID,
+			Noun:       resolveNotificationEntityName(h.app, b.SubjectURI),
+			Link:       resolveNotificationLink(h.app, b.SubjectURI),
+		}
+		item.Title = item.Noun
+		did, collection, rkey, ok := parseNotificationSubjectURI(b.SubjectURI)
+		if !ok {
+			item.Missing = true
+			items[i] = item
+			continue
+		}
+		if h.app != nil {
+			if desc := h.app.DescriptorByNSID(collection); desc != nil && desc.DisplayName != "" {
+				item.Title = desc.DisplayName
+			}
+		}
+		if p := profiles[did]; p != nil {
+			item.AuthorHandle = p.Handle
+		}
+		items[i] = item
+
+		wg.Add(1)
+		go func(i int, did, collection, rkey string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			rec, err := publicClient.GetPublicRecord(ctx, did, collection, rkey)
+			if err != nil {
+				log.Debug().Err(err).Str("uri", items[i].SubjectURI).Msg("Failed to resolve bookmarked record")
+				items[i].Missing = true
+				return
+			}
+			if name, _ := rec.Value["name"].(string); name != "" {
+				items[i].Title = name
+			}
+		}(i, did, collection, rkey)
+	}
+	wg.Wait()
+	return items
+}
+
+// bookmarkAuthorProfiles looks up every bookmarked record's author in one
+// batch. Without a feed index there is no profile cache, so handles are left
+// out rather than fetched one by one.
+func (h *Handler) bookmarkAuthorProfiles(ctx context.Context, bookmarks []*social.Bookmark) map[string]*atproto.Profile {
+	if h.feedIndex == nil {
+		return nil
+	}
+	dids := make([]string, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		if did, _, _, ok := parseNotificationSubjectURI(b.SubjectURI); ok {
+			dids = append(dids, did)
+		}
+	}
+	return h.feedIndex.GetProfiles(ctx, dids)
+}
@@ -88,6 +88,26 @@ func (h *Handler) loadBlueskyProfileForm(ctx context.Context, didStr, sessionID
 	return form
 }
 
+// HasBlueskyPostScopes reports whether the request's session may write
+// Bluesky posts, so the brew form only offers a crosspost that can succeed.
+func (h *Handler) HasBlueskyPostScopes(r *http.Request) bool {
+	if h.oauth == nil {
+		return false
+	}
+	didStr, _ := atpmiddleware.GetDID(r.Context())
+	sessionID, _ := atpmiddleware.GetSessionID(r.Context())
+	did, err := syntax.ParseDID(didStr)
+	if err != nil || sessionID == "" {
+		return false
+	}
+	scopes, err := h.oauth.SessionScopes(r.Context(), did, sessionID)
+	if err != nil {
+		log.Warn().Err(err).Str("user_did", didStr).Msg("Failed to read session scopes")
+		return false
+	}
+	return domain.HasBlueskyPostScopes(scopes)
+}
+
 // HandleUpdateBlueskyProfile handles the form submit from /settings to
 // update the user's Bluesky profile record. Expects multipart/form-data:
 //
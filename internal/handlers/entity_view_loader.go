@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"tangled.org/arabica.social/arabica/internal/atplatform/domain"
 	"tangled.org/arabica.social/arabica/internal/atproto"
@@ -204,6 +205,28 @@ func (l EntityViewLoader) loadFromWitness(ctx context.Context, loaded *LoadedEnt
 	if cfg.ResolveRefs != nil {
 		cfg.ResolveRefs(ctx, loaded.Record, m, h.WitnessLookup(ctx))
 	}
+	l.refreshIfStale(ctx, wr)
+}
+
+// staleWitnessAge is how long a witness record can go without being
+// re-indexed before a view of it triggers a refresh from the owner's PDS.
+const staleWitnessAge = 6 * time.Hour
+
+// refreshIfStale re-fetches an old witness record in the background. This
+// view still renders the cached copy; the next one sees any edit the
+// firehose missed. The feed index rate-limits refreshes per record.
+func (l EntityViewLoader) refreshIfStale(ctx context.Context, wr *atproto.WitnessRecord) {
+	idx := l.h.feedIndex
+	if idx == nil || wr.IndexedAt.IsZero() || time.Since(wr.IndexedAt) < staleWitnessAge {
+		return
+	}
+	go func() {
+		refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 15*time.Second)
+		defer cancel()
+		if err := idx.RefreshRecord(refreshCtx, wr.URI); err != nil {
+			log.Warn().Err(err).Str("uri", wr.URI).Msg("Failed to refresh stale witness record")
+		}
+	}()
 }
 
 func (l EntityViewLoader) loadFromPDS(ctx context.Context, loaded *LoadedEntity, rkey string, cfg EntityLoadConfig) error {
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net/http"
@@ -28,6 +29,7 @@ import (
 // SocialData holds the social interaction data shared across all entity view handlers
 type SocialData struct {
 	IsLiked        bool
+	IsBookmarked   bool
 	LikeCount      int
 	CommentCount   int
 	Comments       []firehose.IndexedComment
@@ -48,6 +50,7 @@ func (h *Handler) FetchSocialData(ctx context.Context, subjectURI, didStr string
 		sd.Comments = h.FilterHiddenComments(ctx, sd.Comments)
 		if isAuthenticated {
 			sd.IsLiked = h.feedIndex.HasUserLiked(ctx, didStr, subjectURI)
+			sd.IsBookmarked = h.feedIndex.GetUserBookmarkRKey(ctx, didStr, subjectURI) != ""
 		}
 	}
 
@@ -56,7 +59,8 @@ func (h *Handler) FetchSocialData(ctx context.Context, subjectURI, didStr string
 		sd.CanHideRecord = h.moderationService.HasPermission(didStr, moderation.PermissionHideRecord)
 		sd.CanBlockUser = h.moderationService.HasPermission(didStr, moderation.PermissionBlacklistUser)
 	}
-	if h.moderationStore != nil && sd.IsModerator && subjectURI != "" {
+	// Owners see their own hidden records flagged so they can appeal.
+	if h.moderationStore != nil && subjectURI != "" && (sd.IsModerator || (isAuthenticated && isRecordOwner(subjectURI, didStr))) {
 		sd.IsRecordHidden = h.moderationStore.IsRecordHidden(ctx, subjectURI)
 	}
 
@@ -64,13 +68,13 @@ func (h *Handler) FetchSocialData(ctx context.Context, subjectURI, didStr string
 }
 
 // ResolveOwnerDID resolves an owner parameter (DID or handle) to a DID string.
+// Handles go through atproto.DefaultHandleCache before the network.
 // Returns the DID and nil error on success, or empty string and error on failure.
 func ResolveOwnerDID(ctx context.Context, owner string) (string, error) {
 	if strings.HasPrefix(owner, "did:") {
 		return owner, nil
 	}
-	publicClient := atproto.NewPublicClient()
-	resolved, err := publicClient.ResolveHandle(ctx, owner)
+	resolved, err := atproto.DefaultHandleCache.Resolve(ctx, owner, atproto.NewPublicClient().ResolveHandle)
 	if err != nil {
 		return "", err
 	}
@@ -88,10 +92,21 @@ type EntityViewConfig struct {
 	FromStore   func(ctx context.Context, s *atproto.AtprotoStore, rkey string) (any, map[string]any, string, string, error)
 	ResolveRefs func(ctx context.Context, model any, raw map[string]any, lookup func(refURI string) (map[string]any, bool))
 
+	// SharePath builds the path a record is shared under, for the share
+	// button and og:url. The owner passed in is a handle whenever one can be
+	// resolved. Nil uses /{route}/{owner}/{rkey}.
+	SharePath func(owner, rkey string) string
+
 	DisplayName func(record any) string
 	OGSubtitle  func(record any) string
 	CountLookup func(ctx context.Context, ownerDID, subjectURI string) int
-	Render      func(ctx context.Context, w http.ResponseWriter, layoutData *components.LayoutData, record any, base pages.EntityViewBase) error
+
+	// OwnerOnly reports whether a loaded record may only be seen by its
+	// owner, such as a draft brew. Anyone else gets the not-found page.
+	// Nil means every record is public.
+	OwnerOnly func(record any) bool
+
+	Render func(ctx context.Context, w http.ResponseWriter, layoutData *components.LayoutData, record any, base pages.EntityViewBase) error
 }
 
 func (cfg EntityViewConfig) loadConfig() EntityLoadConfig {
@@ -128,16 +143,36 @@ func (h *Handler) RenderEntityView(w http.ResponseWriter, r *http.Request, cfg E
 		return
 	}
 
+	hidden, canView := h.recordVisibility(r.Context(), loaded.SubjectURI, didStr)
+	if cfg.ownerOnlyFor(loaded) {
+		canView = false
+	}
+	if !canView {
+		h.renderRecordUnavailable(w, r, isAuthenticated, didStr, userProfile)
+		return
+	}
+
+	ownerHandle := h.ResolveOwnerHandle(r.Context(), owner)
+
 	var shareURL string
-	if owner != "" && loaded.Route.Path != "" {
+	if cfg.SharePath != nil {
+		if ownerHandle != "" {
+			shareURL = cfg.SharePath(ownerHandle, rkey)
+		} else if userProfile != nil && userProfile.Handle != "" {
+			shareURL = cfg.SharePath(userProfile.Handle, rkey)
+		}
+	} else if owner != "" && loaded.Route.Path != "" {
 		shareURL = fmt.Sprintf("/%s/%s/%s", loaded.Route.Path, owner, rkey)
 	} else if userProfile != nil && userProfile.Handle != "" && loaded.Route.Path != "" {
 		shareURL = fmt.Sprintf("/%s/%s/%s", loaded.Route.Path, userProfile.Handle, rkey)
 	}
 
-	ownerHandle := h.ResolveOwnerHandle(r.Context(), owner)
 	layoutData := h.BuildLayoutData(r, cfg.DisplayName(loaded.Record), isAuthenticated, didStr, userProfile)
 	PopulateOGFields(layoutData, cfg.OGSubtitle(loaded.Record), loaded.EntityNoun, ownerHandle, h.PublicBaseURL(r), shareURL)
+	if hidden {
+		layoutData.NoIndex = true
+		w.Header().Set("X-Robots-Tag", "noindex")
+	}
 
 	sd := h.FetchSocialData(r.Context(), loaded.SubjectURI, didStr, isAuthenticated)
 	bl, blDetailURL := h.fetchBacklinks(r.Context(), loaded.SubjectURI, loaded.Route.Path, rkey, ownerSegment(owner, userProfile, didStr))
@@ -152,6 +187,7 @@ func (h *Handler) RenderEntityView(w http.ResponseWriter, r *http.Request, cfg E
 		SubjectURI:         loaded.SubjectURI,
 		SubjectCID:         loaded.SubjectCID,
 		IsLiked:            sd.IsLiked,
+		IsBookmarked:       sd.IsBookmarked,
 		LikeCount:          sd.LikeCount,
 		CommentCount:       sd.CommentCount,
 		Comments:           sd.Comments,
@@ -171,12 +207,45 @@ func (h *Handler) RenderEntityView(w http.ResponseWriter, r *http.Request, cfg E
 		base.AuthorAvatar = ap.Avatar
 	}
 
+	// The record carries its resolved references and base carries the CID,
+	// social state and viewer flags, so together they cover what renders,
+	// along with the zone timestamps are shown in.
+	if ServeNotModified(w, r, PageETag(layoutData, loaded.Record, base, bff.ViewerLocation(r.Context()).String())) {
+		return
+	}
+
 	if err := cfg.Render(r.Context(), w, layoutData, loaded.Record, base); err != nil {
 		http.Error(w, "Failed to render page", http.StatusInternalServerError)
 		log.Error().Err(err).Msgf("Failed to render %s view", loaded.EntityNoun)
 	}
 }
 
+// recordVisibility reports whether moderators have hidden subjectURI and
+// whether didStr may still see it. Moderators keep access so they can review
+// the record, and owners so they can appeal; everyone else is turned away.
+func (h *Handler) recordVisibility(ctx context.Context, subjectURI, didStr string) (hidden, canView bool) {
+	if h.moderationStore == nil || subjectURI == "" || !h.moderationStore.IsRecordHidden(ctx, subjectURI) {
+		return false, true
+	}
+	if didStr == "" {
+		return true, false
+	}
+	isModerator := h.moderationService != nil && h.moderationService.IsModerator(didStr)
+	return true, isModerator || isRecordOwner(subjectURI, didStr)
+}
+
+// renderRecordUnavailable answers a request for a hidden record with a
+// not-found page that crawlers are told not to index.
+func (h *Handler) renderRecordUnavailable(w http.ResponseWriter, r *http.Request, isAuthenticated bool, didStr string, userProfile *bff.UserProfile) {
+	layoutData := h.BuildLayoutData(r, "Not Available", isAuthenticated, didStr, userProfile)
+	layoutData.NoIndex = true
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.WriteHeader(http.StatusNotFound)
+	if err := pages.RecordUnavailable(layoutData).Render(r.Context(), w); err != nil {
+		log.Error().Err(err).Msg("Failed to render record unavailable page")
+	}
+}
+
 func ownerSegment(owner string, profile *bff.UserProfile, did string) string {
 	if owner != "" {
 		return owner
@@ -270,6 +339,37 @@ func (s backlinkIndexSource) GetProfile(ctx context.Context, did string) (*backl
 	return out, nil
 }
 
+// LoadEntityView loads the record RenderEntityView would show, under the
+// same visibility rules, for handlers that present it in another format.
+// On failure the error response has been written and nil is returned.
+func (h *Handler) LoadEntityView(w http.ResponseWriter, r *http.Request, cfg EntityViewConfig) *LoadedEntity {
+	rkey := ValidateRKey(w, r.PathValue("id"))
+	if rkey == "" {
+		return nil
+	}
+	loaded, err := h.EntityViewLoader().Load(r, rkey, cfg.loadConfig())
+	if err != nil {
+		if loadErr, ok := err.(*EntityLoadError); ok {
+			http.Error(w, loadErr.Msg, loadErr.HTTPStatus())
+		} else {
+			http.Error(w, "Failed to load record", http.StatusInternalServerError)
+		}
+		return nil
+	}
+	didStr, _ := atpmiddleware.GetDID(r.Context())
+	if _, canView := h.recordVisibility(r.Context(), loaded.SubjectURI, didStr); !canView || cfg.ownerOnlyFor(loaded) {
+		http.Error(w, "Record not found", http.StatusNotFound)
+		return nil
+	}
+	return loaded
+}
+
+// ownerOnlyFor reports whether cfg.OwnerOnly keeps loaded from a viewer
+// who isn't its owner.
+func (cfg EntityViewConfig) ownerOnlyFor(loaded *LoadedEntity) bool {
+	return cfg.OwnerOnly != nil && !loaded.IsOwnProfile && cfg.OwnerOnly(loaded.Record)
+}
+
 func (h *Handler) RenderBacklinksView(w http.ResponseWriter, r *http.Request, cfg EntityViewConfig) {
 	rkey := ValidateRKey(w, r.PathValue("id"))
 	if rkey == "" {
@@ -428,3 +528,52 @@ func WriteOGImage(w http.ResponseWriter, card *ogcard.Card) {
 		log.Error().Err(err).Msg("Failed to encode OG image")
 	}
 }
+
+// ServeRecordOGImage writes the preview card for the record at uri. Cards
+// are cached by URI and CID, so a record is only drawn once per version. If
+// drawing fails the site card is served instead, with a short max-age so the
+// real card gets another chance soon.
+func (h *Handler) ServeRecordOGImage(w http.ResponseWriter, uri, cid string, draw func() (*ogcard.Card, error)) {
+	if png, ok := h.ogImages.Get(uri, cid); ok {
+		writeOGImagePNG(w, png, 86400)
+		return
+	}
+
+	png, err := encodeOGCard(draw)
+	if err != nil {
+		log.Error().Err(err).Str("uri", uri).Msg("Failed to generate OG image, falling back to site card")
+		png, err = encodeOGCard(func() (*ogcard.Card, error) {
+			return ogcard.DrawSiteCard(h.siteCardOpts())
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to generate fallback OG image")
+			http.Error(w, "Failed to generate image", http.StatusInternalServerError)
+			return
+		}
+		writeOGImagePNG(w, png, 300)
+		return
+	}
+
+	h.ogImages.Put(uri, cid, png)
+	writeOGImagePNG(w, png, 86400)
+}
+
+func encodeOGCard(draw func() (*ogcard.Card, error)) ([]byte, error) {
+	card, err := draw()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := card.EncodePNG(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeOGImagePNG(w http.ResponseWriter, png []byte, maxAge int) {
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	if _, err := w.Write(png); err != nil {
+		log.Error().Err(err).Msg("Failed to write OG image")
+	}
+}
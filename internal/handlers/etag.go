@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/web/components"
+)
+
+// pageETagSeed is mixed into every page ETag so that after a deploy, whose
+// templates may render the same inputs differently, no page cached from the
+// previous build revalidates.
+var pageETagSeed = strconv.FormatInt(time.Now().UnixNano(), 36)
+
+// PageETag returns a weak ETag over everything a server-rendered page is
+// built from: the layout (which carries the viewer's DID, moderator flag and
+// unread count) plus the page's own props, each JSON-encoded. Pass the same
+// values the template receives so that any change to them, such as a new
+// record CID, a like, or a viewer-specific flag, yields a new tag. The
+// per-request CSP nonce is left out. Returns "" if a value can't be encoded.
+func PageETag(layoutData *components.LayoutData, values ...any) string {
+	sum := sha256.New()
+	sum.Write([]byte(pageETagSeed))
+	enc := json.NewEncoder(sum)
+	if layoutData != nil {
+		layout := *layoutData
+		layout.CSPNonce = ""
+		if err := enc.Encode(layout); err != nil {
+			return ""
+		}
+	}
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			return ""
+		}
+	}
+	return `W/"` + hex.EncodeToString(sum.Sum(nil)[:12]) + `"`
+}
+
+// ServeNotModified sets the ETag header and, when the request's
+// If-None-Match already holds etag, answers 304 and returns true. The 304
+// drops Content-Security-Policy so the browser keeps the policy stored with
+// its cached page, whose script nonce matches that page rather than this
+// request. An empty etag disables the check.
+func ServeNotModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	w.Header().Set("ETag", etag)
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.Header().Del("Content-Security-Policy")
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches applies the weak comparison If-None-Match calls for.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
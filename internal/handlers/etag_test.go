@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tangled.org/arabica.social/arabica/internal/web/components"
+	"tangled.org/arabica.social/arabica/internal/web/pages"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageETag(t *testing.T) {
+	base := pages.EntityViewBase{SubjectURI: "at://did:plc:alice/social.arabica.alpha.brew/b1", SubjectCID: "cid1", LikeCount: 2}
+	anon := PageETag(&components.LayoutData{Title: "Brew", CSPNonce: "n1"}, base)
+	require.NotEmpty(t, anon)
+
+	assert.Equal(t, anon, PageETag(&components.LayoutData{Title: "Brew", CSPNonce: "n2"}, base),
+		"the per-request nonce must not change the tag")
+
+	edited := base
+	edited.SubjectCID = "cid2"
+	viewer := base
+	viewer.CurrentUserDID = "did:plc:bob"
+	moderator := viewer
+	moderator.IsModerator = true
+	moderator.CanHideRecord = true
+
+	tags := map[string]string{
+		"anonymous":   anon,
+		"new cid":     PageETag(&components.LayoutData{Title: "Brew"}, edited),
+		"signed in":   PageETag(&components.LayoutData{Title: "Brew", IsAuthenticated: true, UserDID: "did:plc:bob"}, viewer),
+		"moderator":   PageETag(&components.LayoutData{Title: "Brew", IsAuthenticated: true, UserDID: "did:plc:bob", IsModerator: true}, moderator),
+		"another DID": PageETag(&components.LayoutData{Title: "Brew", IsAuthenticated: true, UserDID: "did:plc:carol"}, viewer),
+	}
+	seen := make(map[string]string)
+	for name, tag := range tags {
+		if other, ok := seen[tag]; ok {
+			t.Errorf("%s and %s share ETag %s", name, other, tag)
+		}
+		seen[tag] = name
+	}
+}
+
+func TestServeNotModified(t *testing.T) {
+	const etag = `W/"abc123"`
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{"no header", "", false},
+		{"exact", `W/"abc123"`, true},
+		{"strong form", `"abc123"`, true},
+		{"in a list", `"zzz", W/"abc123"`, true},
+		{"wildcard", "*", true},
+		{"stale", `W/"old"`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/brews/alice.test/b1", nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Content-Security-Policy", "script-src 'nonce-fresh'")
+
+			got := ServeNotModified(rec, req, etag)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, etag, rec.Header().Get("ETag"))
+			if tt.want {
+				assert.Equal(t, http.StatusNotModified, rec.Code)
+				assert.Empty(t, rec.Header().Get("Content-Security-Policy"), "cached page keeps its own nonce")
+			} else {
+				assert.NotEmpty(t, rec.Header().Get("Content-Security-Policy"))
+			}
+		})
+	}
+
+	t.Run("empty etag disables the check", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("If-None-Match", "*")
+		rec := httptest.NewRecorder()
+		assert.False(t, ServeNotModified(rec, req, ""))
+		assert.Empty(t, rec.Header().Get("ETag"))
+	})
+}
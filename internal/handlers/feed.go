@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"tangled.org/arabica.social/arabica/internal/entities"
 	"tangled.org/arabica.social/arabica/internal/feed"
@@ -12,6 +16,7 @@ import (
 	"tangled.org/arabica.social/arabica/internal/ogcard"
 	"tangled.org/arabica.social/arabica/internal/profileprefs"
 	"tangled.org/arabica.social/arabica/internal/social"
+	"tangled.org/arabica.social/arabica/internal/web/bff"
 	"tangled.org/arabica.social/arabica/internal/web/components"
 	"tangled.org/arabica.social/arabica/internal/web/pages"
 	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
@@ -38,6 +43,7 @@ func (h *Handler) buildModerationContext(ctx context.Context, viewerDID string,
 	modCtx.IsModerator = true
 	modCtx.CanHideRecord = h.moderationService.HasPermission(viewerDID, moderation.PermissionHideRecord)
 	modCtx.CanBlockUser = h.moderationService.HasPermission(viewerDID, moderation.PermissionBlacklistUser)
+	modCtx.CanPinRecord = h.moderationService.IsAdmin(viewerDID)
 
 	// Load all hidden URIs in one query and intersect with feed items
 	if h.moderationStore != nil {
@@ -143,46 +149,80 @@ func (h *Handler) HandleSiteOGImage(w http.ResponseWriter, r *http.Request) {
 
 // Community feed partial (loaded async via HTMX)
 func (h *Handler) HandleFeedPartial(w http.ResponseWriter, r *http.Request) {
+	h.serveFeedPartial(w, r, false)
+}
+
+// Feed partial limited to records by accounts the viewer follows.
+// Unauthenticated visitors follow no one, so they get the community feed.
+func (h *Handler) HandleFollowingFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveFeedPartial(w, r, true)
+}
+
+func (h *Handler) serveFeedPartial(w http.ResponseWriter, r *http.Request, following bool) {
 	var feedItems []*feed.FeedItem
 	var nextCursor string
 
 	// Check if user is authenticated
 	viewerDID, isAuthenticated := atpmiddleware.GetDID(r.Context())
+	following = following && isAuthenticated
 
-	// Parse query parameters
-	typeParam := r.URL.Query().Get("type")
-	// Filter pills send the app entity route noun (e.g. "brew", "tea").
-	// Resolve through the running app so shared nouns like "brew" map to the
-	// current product's record type instead of the global lexicon default.
-	var typeFilter lexicons.RecordType
-	if typeParam != "" {
-		if h.app != nil {
-			if route, ok := h.app.EntityRouteByNoun(typeParam); ok {
-				typeFilter = route.Type
-			} else {
-				typeFilter = lexicons.ParseRecordType(typeParam)
-			}
-		} else {
-			typeFilter = lexicons.ParseRecordType(typeParam)
-		}
-	}
+	// Parse query parameters. Unrecognised types fall back to the unfiltered
+	// feed rather than rendering an error in place of the timeline.
+	typeFilter, _ := h.resolveFeedTypeFilter(r.URL.Query().Get("type"))
 	sortBy := feed.FeedSort(r.URL.Query().Get("sort"))
 	cursor := r.URL.Query().Get("cursor")
 
 	if sortBy != feed.FeedSortPopular {
 		sortBy = feed.FeedSortRecent
 	}
+	if following {
+		// The following feed has no filter bar, so it is always newest first.
+		typeFilter, sortBy = "", feed.FeedSortRecent
+	}
+
+	// The default anonymous view is the same for every visitor, so its
+	// rendered HTML is cached until the feed changes. Relative times like
+	// "yesterday" depend on the viewer's time zone, so only UTC viewers
+	// share the cached copy.
+	cachePublicHTML := h.feedService != nil && !isAuthenticated && cursor == "" &&
+		typeFilter == "" && sortBy == feed.FeedSortRecent &&
+		bff.ViewerLocation(r.Context()) == time.UTC
+	var htmlGeneration uint64
+	if cachePublicHTML {
+		html, generation, ok := h.feedService.CachedPublicFeedHTML()
+		if ok {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write(html)
+			return
+		}
+		htmlGeneration = generation
+	}
+
+	// Signed-in viewers may ask for a bigger or smaller page. Anything that
+	// isn't a positive integer quietly gets the configured default.
+	var limit int
+	if h.feedService != nil && isAuthenticated {
+		var ok bool
+		limit, ok = parseFeedAPILimit(r.URL.Query().Get("limit"), h.feedService.DefaultLimit())
+		if !ok {
+			limit = h.feedService.DefaultLimit()
+		}
+	}
 
 	if h.feedService != nil {
 		if isAuthenticated {
-			result, err := h.feedService.GetFeedWithQuery(r.Context(), feed.FeedQuery{
-				Limit:      feed.FeedLimit,
+			q := feed.FeedQuery{
+				Limit:      limit,
 				Cursor:     cursor,
 				TypeFilter: typeFilter,
 				Sort:       sortBy,
-			})
+			}
+			if following {
+				q.FollowerDID = viewerDID
+			}
+			result, err := h.feedService.GetFeedWithQuery(r.Context(), q)
 			if err != nil {
-				log.Error().Err(err).Str("sort", string(sortBy)).Str("type", string(typeFilter)).Msg("Failed to query feed")
+				log.Error().Err(err).Str("sort", string(sortBy)).Str("type", string(typeFilter)).Bool("following", following).Msg("Failed to query feed")
 			}
 			if result != nil {
 				feedItems = result.Items
@@ -198,27 +238,15 @@ func (h *Handler) HandleFeedPartial(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Pinned records head the first page of the default feed view.
+	if h.feedService != nil && cursor == "" && typeFilter == "" &&
+		sortBy == feed.FeedSortRecent && !following {
+		feedItems = h.feedService.WithFeatured(r.Context(), feedItems)
+	}
+
 	// Populate IsLikedByViewer and IsOwner for each feed item if user is authenticated
 	if isAuthenticated {
-		// Batch fetch liked status for all feed items
-		var likedByViewer map[string]bool
-		if h.feedIndex != nil {
-			uris := make([]string, 0, len(feedItems))
-			for _, item := range feedItems {
-				if item.SubjectURI != "" {
-					uris = append(uris, item.SubjectURI)
-				}
-			}
-			likedByViewer = h.feedIndex.HasUserLikedBatch(r.Context(), viewerDID, uris)
-		}
-		for _, item := range feedItems {
-			if item.Author != nil {
-				item.IsOwner = item.Author.DID == viewerDID
-			}
-			if likedByViewer != nil {
-				item.IsLikedByViewer = likedByViewer[item.SubjectURI]
-			}
-		}
+		h.populateFeedViewerState(r.Context(), viewerDID, feedItems)
 	}
 
 	// Build moderation context for moderators
@@ -245,6 +273,10 @@ func (h *Handler) HandleFeedPartial(w http.ResponseWriter, r *http.Request) {
 		BrandName:       brandName,
 		EmptyState:      h.feedPresentation.EmptyState,
 		UserPreferences: userPrefs,
+		Following:       following,
+	}
+	if h.feedService != nil && limit != h.feedService.DefaultLimit() {
+		queryState.Limit = limit
 	}
 
 	// If this is a "load more" request (has cursor), render just the additional items
@@ -256,13 +288,175 @@ func (h *Handler) HandleFeedPartial(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := pages.FeedPartialWithModeration(feedItems, isAuthenticated, modCtx, queryState).Render(r.Context(), w); err != nil {
+	partial := pages.FeedPartialWithModeration(feedItems, isAuthenticated, modCtx, queryState)
+	if cachePublicHTML && len(feedItems) > 0 {
+		var buf bytes.Buffer
+		if err := partial.Render(r.Context(), &buf); err != nil {
+			http.Error(w, "Failed to render feed", http.StatusInternalServerError)
+			log.Error().Err(err).Msg("Failed to render feed partial")
+			return
+		}
+		h.feedService.StorePublicFeedHTML(htmlGeneration, buf.Bytes())
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(buf.Bytes())
+		return
+	}
+
+	if err := partial.Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render feed", http.StatusInternalServerError)
 		log.Error().Err(err).Msg("Failed to render feed partial")
 	}
 }
 
-// HandleLikeToggle handles creating or deleting a like on a record
+// resolveFeedTypeFilter maps a feed ?type= value to a record type. Filter
+// pills send the app entity route noun (e.g. "brew", "tea"), so the running
+// app is consulted first so shared nouns like "brew" map to the current
+// product's record type instead of the global lexicon default. An empty
+// param means "all collections" and is reported as ok. For anything the
+// running app does not put in its feed, ok is false and the returned type is
+// empty, so callers that prefer leniency can fall through to the unfiltered
+// feed.
+func (h *Handler) resolveFeedTypeFilter(typeParam string) (lexicons.RecordType, bool) {
+	if typeParam == "" {
+		return "", true
+	}
+	var typeFilter lexicons.RecordType
+	if route, ok := h.app.EntityRouteByNoun(typeParam); ok {
+		typeFilter = route.Type
+	} else {
+		typeFilter = lexicons.ParseRecordType(typeParam)
+	}
+	if typeFilter == "" {
+		return "", false
+	}
+	// The feed index only serves the app's own descriptors, so a valid
+	// lexicon type from another app would otherwise fail the query.
+	if h.app != nil && h.app.DescriptorByType(typeFilter) == nil {
+		return "", false
+	}
+	return typeFilter, true
+}
+
+// populateFeedViewerState sets IsOwner and IsLikedByViewer on each item for
+// an authenticated viewer, batch-fetching liked status in a single query.
+func (h *Handler) populateFeedViewerState(ctx context.Context, viewerDID string, items []*feed.FeedItem) {
+	var likedByViewer map[string]bool
+	if h.feedIndex != nil {
+		uris := make([]string, 0, len(items))
+		for _, item := range items {
+			if item.SubjectURI != "" {
+				uris = append(uris, item.SubjectURI)
+			}
+		}
+		likedByViewer = h.feedIndex.HasUserLikedBatch(ctx, viewerDID, uris)
+	}
+	for _, item := range items {
+		if item.Author != nil {
+			item.IsOwner = item.Author.DID == viewerDID
+		}
+		if likedByViewer != nil {
+			item.IsLikedByViewer = likedByViewer[item.SubjectURI]
+		}
+	}
+}
+
+// MaxFeedAPILimit caps the page size clients may request from the feed,
+// whether as JSON or as the HTML partial.
+const MaxFeedAPILimit = 100
+
+// parseFeedAPILimit reads a ?limit= value for the machine-readable feed
+// endpoints. Empty means defaultLimit; larger values are clamped to
+// MaxFeedAPILimit. ok is false for anything that isn't a positive integer.
+func parseFeedAPILimit(raw string, defaultLimit int) (limit int, ok bool) {
+	if raw == "" {
+		return defaultLimit, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return min(n, MaxFeedAPILimit), true
+}
+
+// feedAPIResponse is the JSON body returned by HandleFeedAPI. NextCursor is
+// omitted on the last page.
+type feedAPIResponse struct {
+	Items      []*feed.FeedItem `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// HandleFeedAPI returns one page of the community feed as JSON. Supported
+// query params are limit (capped at MaxFeedAPILimit), cursor (the
+// next_cursor from a previous page), type (an entity noun or record type;
+// empty means all collections), and sort ("recent" or "popular").
+func (h *Handler) HandleFeedAPI(w http.ResponseWriter, r *http.Request) {
+	if h.feedService == nil {
+		http.Error(w, "Feed not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit, ok := parseFeedAPILimit(query.Get("limit"), h.feedService.DefaultLimit())
+	if !ok {
+		http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+		return
+	}
+
+	cursor := query.Get("cursor")
+	if cursor != "" {
+		if _, _, err := feed.ParseCursor(cursor); err != nil {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+	}
+
+	typeFilter, ok := h.resolveFeedTypeFilter(query.Get("type"))
+	if !ok {
+		http.Error(w, "Unknown record type", http.StatusBadRequest)
+		return
+	}
+
+	var sortBy feed.FeedSort
+	switch feed.FeedSort(query.Get("sort")) {
+	case "", feed.FeedSortRecent:
+		sortBy = feed.FeedSortRecent
+	case feed.FeedSortPopular:
+		sortBy = feed.FeedSortPopular
+	default:
+		http.Error(w, "sort must be \"recent\" or \"popular\"", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.feedService.GetFeedWithQuery(r.Context(), feed.FeedQuery{
+		Limit:      limit,
+		Cursor:     cursor,
+		TypeFilter: typeFilter,
+		Sort:       sortBy,
+	})
+	if err != nil {
+		if errors.Is(err, feed.ErrInvalidCursor) {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		log.Error().Err(err).Str("sort", string(sortBy)).Str("type", string(typeFilter)).Msg("Failed to query feed API")
+		http.Error(w, "Failed to load feed", http.StatusInternalServerError)
+		return
+	}
+
+	if viewerDID, ok := atpmiddleware.GetDID(r.Context()); ok {
+		h.populateFeedViewerState(r.Context(), viewerDID, result.Items)
+	}
+
+	items := result.Items
+	if items == nil {
+		items = []*feed.FeedItem{}
+	}
+	WriteJSON(w, feedAPIResponse{Items: items, NextCursor: result.NextCursor}, "feed")
+}
+
+// HandleLikeToggle handles creating or deleting a like on a record or comment.
+// The subject is any AT-URI, so comment likes share the same flow.
 func (h *Handler) HandleLikeToggle(w http.ResponseWriter, r *http.Request) {
 	// Require authentication
 	store, authenticated := h.getSocialStore(r)
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/feed"
+
+	"github.com/rs/zerolog/log"
+)
+
+// jsonFeedVersion identifies the JSON Feed spec revision we emit.
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+// jsonFeed is a JSON Feed 1.1 document (https://jsonfeed.org/version/1.1).
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	FeedURL     string         `json:"feed_url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url,omitempty"`
+	Title         string           `json:"title,omitempty"`
+	ContentText   string           `json:"content_text"`
+	DatePublished string           `json:"date_published,omitempty"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name   string `json:"name,omitempty"`
+	URL    string `json:"url,omitempty"`
+	Avatar string `json:"avatar,omitempty"`
+}
+
+// HandleFeedJSON serves the community timeline as a JSON Feed 1.1 document at
+// /api/feed.json. It reads the same moderated feed as HandleFeedAPI and
+// honours ?limit= (capped at MaxFeedAPILimit).
+func (h *Handler) HandleFeedJSON(w http.ResponseWriter, r *http.Request) {
+	if h.feedService == nil {
+		http.Error(w, "Feed not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	limit, ok := parseFeedAPILimit(r.URL.Query().Get("limit"), h.feedService.DefaultLimit())
+	if !ok {
+		http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.feedService.GetFeedWithQuery(r.Context(), feed.FeedQuery{
+		Limit: limit,
+		Sort:  feed.FeedSortRecent,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to query feed for JSON Feed")
+		http.Error(w, "Failed to load feed", http.StatusInternalServerError)
+		return
+	}
+
+	doc := h.buildJSONFeed(h.PublicBaseURL(r), result.Items)
+
+	w.Header().Set("Content-Type", "application/feed+json")
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		log.Error().Err(err).Msg("Failed to encode JSON Feed")
+	}
+}
+
+// buildJSONFeed converts feed items into a JSON Feed document. Item text
+// comes from the FeedItem's action and DisplayTitle, so no record parsing
+// happens here.
+func (h *Handler) buildJSONFeed(baseURL string, items []*feed.FeedItem) jsonFeed {
+	doc := jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       h.brand.DisplayName,
+		HomePageURL: baseURL + "/",
+		FeedURL:     baseURL + "/api/feed.json",
+		Description: h.brand.Tagline,
+		Items:       make([]jsonFeedItem, 0, len(items)),
+	}
+
+	for _, item := range items {
+		if item == nil || item.SubjectURI == "" {
+			continue
+		}
+		title := item.DisplayTitle()
+		entry := jsonFeedItem{
+			ID:          item.SubjectURI,
+			Title:       title,
+			ContentText: jsonFeedContentText(item.Action, title),
+		}
+		if !item.Timestamp.IsZero() {
+			entry.DatePublished = item.Timestamp.UTC().Format(time.RFC3339)
+		}
+		if author := item.Author; author != nil {
+			a := jsonFeedAuthor{Name: author.Handle}
+			if author.DisplayName != nil && *author.DisplayName != "" {
+				a.Name = *author.DisplayName
+			}
+			if author.Avatar != nil {
+				a.Avatar = *author.Avatar
+			}
+			if author.Handle != "" {
+				a.URL = baseURL + "/profile/" + author.Handle
+			}
+			entry.Authors = []jsonFeedAuthor{a}
+
+			if route, ok := h.app.EntityRouteByType(item.RecordType); ok && route.Path != "" && author.Handle != "" {
+				if rkey := item.RKey(); rkey != "" {
+					entry.URL = baseURL + "/" + route.Path + "/" + author.Handle + "/" + rkey
+				}
+			}
+		}
+		doc.Items = append(doc.Items, entry)
+	}
+
+	return doc
+}
+
+func jsonFeedContentText(action, title string) string {
+	switch {
+	case action == "":
+		return title
+	case title == "":
+		return action
+	default:
+		return action + ": " + title
+	}
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/atplatform/domain"
+	"tangled.org/arabica.social/arabica/internal/atproto"
+	"tangled.org/arabica.social/arabica/internal/entities"
+	"tangled.org/arabica.social/arabica/internal/feed"
+	"tangled.org/arabica.social/arabica/internal/lexicons"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveFeedTypeFilter(t *testing.T) {
+	h := &Handler{}
+	h.SetApp(&domain.App{
+		Name: "arabica",
+		Descriptors: []*entities.Descriptor{
+			{Type: lexicons.RecordTypeBrew, NSID: "social.arabica.alpha.brew"},
+			{Type: lexicons.RecordTypeRoaster, NSID: "social.arabica.alpha.roaster"},
+		},
+		EntityRoutes: []domain.EntityRoute{
+			{Type: lexicons.RecordTypeBrew, Path: "brews", Noun: "brew"},
+			{Type: lexicons.RecordTypeRoaster, Path: "roasters", Noun: "roaster"},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		param    string
+		expected lexicons.RecordType
+		ok       bool
+	}{
+		{name: "empty means all", param: "", expected: "", ok: true},
+		{name: "route noun", param: "brew", expected: lexicons.RecordTypeBrew, ok: true},
+		{name: "record type", param: string(lexicons.RecordTypeRoaster), expected: lexicons.RecordTypeRoaster, ok: true},
+		{name: "garbage", param: "espresso-machine", expected: "", ok: false},
+		{name: "type from another app", param: string(lexicons.RecordTypeOolongTea), expected: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typeFilter, ok := h.resolveFeedTypeFilter(tt.param)
+			assert.Equal(t, tt.expected, typeFilter)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestBuildJSONFeed(t *testing.T) {
+	h := &Handler{}
+	h.SetApp(&domain.App{
+		Name: "arabica",
+		Descriptors: []*entities.Descriptor{
+			{Type: lexicons.RecordTypeBrew, NSID: "social.arabica.alpha.brew"},
+		},
+		EntityRoutes: []domain.EntityRoute{
+			{Type: lexicons.RecordTypeBrew, Path: "brews", Noun: "brew"},
+		},
+	})
+	displayName := "Alice"
+	items := []*feed.FeedItem{
+		{
+			RecordType: lexicons.RecordTypeBrew,
+			Action:     "added a new brew",
+			Author:     &atproto.Profile{DID: "did:plc:alice", Handle: "alice.test", DisplayName: &displayName},
+			Timestamp:  time.Date(2025, 2, 3, 8, 30, 0, 0, time.FixedZone("PST", -8*3600)),
+			SubjectURI: "at://did:plc:alice/social.arabica.alpha.brew/3abc",
+		},
+		{Action: "skipped", SubjectURI: ""},
+	}
+
+	doc := h.buildJSONFeed("https://arabica.social", items)
+
+	assert.Equal(t, jsonFeedVersion, doc.Version)
+	assert.Equal(t, "https://arabica.social/api/feed.json", doc.FeedURL)
+	assert.Len(t, doc.Items, 1)
+	item := doc.Items[0]
+	assert.Equal(t, "at://did:plc:alice/social.arabica.alpha.brew/3abc", item.ID)
+	assert.Equal(t, "added a new brew", item.ContentText)
+	assert.Equal(t, "2025-02-03T16:30:00Z", item.DatePublished)
+	assert.Equal(t, []jsonFeedAuthor{{Name: "Alice", URL: "https://arabica.social/profile/alice.test"}}, item.Authors)
+}
+
+func TestJSONFeedContentText(t *testing.T) {
+	tests := []struct {
+		action, title, expected string
+	}{
+		{"added a new bean", "Kochere", "added a new bean: Kochere"},
+		{"added a new bean", "", "added a new bean"},
+		{"", "Kochere", "Kochere"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, jsonFeedContentText(tt.action, tt.title))
+	}
+}
+
+func TestParseFeedAPILimit(t *testing.T) {
+	tests := []struct {
+		raw    string
+		want   int
+		wantOK bool
+	}{
+		{"", 35, true},
+		{"10", 10, true},
+		{"500", MaxFeedAPILimit, true},
+		{"0", 0, false},
+		{"-3", 0, false},
+		{"ten", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, ok := parseFeedAPILimit(tt.raw, 35)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
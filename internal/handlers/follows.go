@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"tangled.org/arabica.social/arabica/internal/social"
+	"tangled.org/arabica.social/arabica/internal/web/components"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/rs/zerolog/log"
+)
+
+// followStore is the slice of the user's store needed to follow and
+// unfollow accounts. Kept apart from socialStore so stores that only
+// support likes and comments still satisfy that interface.
+type followStore interface {
+	CreateFollow(ctx context.Context, subjectDID string) (*social.Follow, error)
+	DeleteFollow(ctx context.Context, rkey string) error
+}
+
+// HandleFollowToggle follows or unfollows the account in subject_did and
+// returns the updated follow button.
+func (h *Handler) HandleFollowToggle(w http.ResponseWriter, r *http.Request) {
+	recordStore, authenticated := h.GetRecordStore(r)
+	if !authenticated {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	store, ok := recordStore.(followStore)
+	if !ok || h.feedIndex == nil {
+		http.Error(w, "Following is not available", http.StatusNotImplemented)
+		return
+	}
+
+	didStr, _ := atpmiddleware.GetDID(r.Context())
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+	subjectDID := r.FormValue("subject_did")
+	if _, err := syntax.ParseDID(subjectDID); err != nil {
+		http.Error(w, "A valid subject_did is required", http.StatusBadRequest)
+		return
+	}
+	if subjectDID == didStr {
+		http.Error(w, "You cannot follow yourself", http.StatusBadRequest)
+		return
+	}
+
+	// The index is the source of truth for follow state so the button
+	// doesn't need to page through the user's follow records on the PDS.
+	isFollowing := false
+	if rkey := h.feedIndex.GetFollowRKey(r.Context(), didStr, subjectDID); rkey != "" {
+		if err := store.DeleteFollow(r.Context(), rkey); err != nil {
+			log.Error().Err(err).Str("subject", subjectDID).Msg("Failed to delete follow")
+			HandleStoreError(w, err, "Failed to unfollow")
+			return
+		}
+		if err := h.feedIndex.DeleteFollow(r.Context(), didStr, rkey); err != nil {
+			log.Warn().Err(err).Str("did", didStr).Str("subject", subjectDID).Msg("Failed to delete follow from feed index")
+		}
+	} else {
+		follow, err := store.CreateFollow(r.Context(), subjectDID)
+		if err != nil {
+			log.Error().Err(err).Str("subject", subjectDID).Msg("Failed to create follow")
+			HandleStoreError(w, err, "Failed to follow")
+			return
+		}
+		isFollowing = true
+		if err := h.feedIndex.UpsertFollow(r.Context(), didStr, follow.RKey, subjectDID); err != nil {
+			log.Warn().Err(err).Str("did", didStr).Str("subject", subjectDID).Msg("Failed to upsert follow in feed index")
+		}
+	}
+
+	if err := components.FollowButton(subjectDID, isFollowing).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render button", http.StatusInternalServerError)
+		log.Error().Err(err).Msg("Failed to render follow button")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"tangled.org/arabica.social/arabica/internal/atplatform/domain"
 	"tangled.org/arabica.social/arabica/internal/atproto"
@@ -28,6 +29,7 @@ import (
 	"tangled.org/arabica.social/arabica/internal/web/components"
 	"tangled.org/arabica.social/arabica/internal/web/feedviews"
 	"tangled.org/arabica.social/arabica/internal/web/pages"
+	"tangled.org/arabica.social/arabica/internal/webhooks"
 	"tangled.org/pdewey.com/atp"
 	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
 
@@ -44,6 +46,17 @@ type Config struct {
 	// PublicURL is the public-facing URL for the server (e.g., https://arabica.social)
 	// Used for constructing absolute URLs in OpenGraph metadata
 	PublicURL string
+
+	// HandleDomain and SignupURL point the first-party provider on the
+	// create-account page at a self-hosted PDS. Empty means the
+	// signup package defaults (arabica.systems).
+	HandleDomain string
+	SignupURL    string
+
+	// Automod holds the deployment's report and auto-hide thresholds. Zero
+	// fields use moderation.DefaultAutomodSettings; admins can override the
+	// result from the dashboard.
+	Automod moderation.AutomodSettings
 }
 
 type StaticPageRenderer func(context.Context, http.ResponseWriter, *components.LayoutData) error
@@ -78,13 +91,26 @@ type Handler struct {
 	feedIndex     *firehose.FeedIndex
 	witnessCache  atproto.WitnessCache
 
+	// passwordSessions backs the app-password login (optional).
+	passwordSessions atproto.PasswordSessionStore
+
+	// sessionRevoker backs "sign out everywhere" (optional).
+	sessionRevoker SessionRevoker
+
 	// Moderation dependencies (optional)
 	moderationService *moderation.Service
 	moderationStore   *moderationsqlite.ModerationStore
+	labeler           moderation.Labeler
 
 	// Backup service (optional) — exposes per-source status to admin views.
 	backupService *backup.Service
 
+	// Firehose consumer (optional) — runs the admin full-index rebuild.
+	firehoseConsumer *firehose.Consumer
+
+	// Webhook delivery (optional) — admins manage its endpoints.
+	webhooks *webhooks.Service
+
 	// Brand carries the per-app display name and tagline. Set via
 	// SetBrand at startup; consumed by buildLayoutData so templ
 	// components can read brand strings without hardcoding "Arabica".
@@ -105,6 +131,12 @@ type Handler struct {
 	assets           assets.Manifest
 	feedViews        feedviews.Registry
 
+	// ogImages caches rendered record preview cards by URI and CID.
+	ogImages *ogcard.ImageCache
+
+	// sitemap caches the generated /sitemap.xml files.
+	sitemap sitemapCache
+
 	// storeOverride supports focused handler tests without constructing an
 	// OAuth-backed ATProto client. Production code leaves it nil.
 	storeOverride records.Store
@@ -229,6 +261,7 @@ func NewHandler(
 		config:        config,
 		feedService:   feedService,
 		feedRegistry:  feedRegistry,
+		ogImages:      ogcard.NewImageCache(ogcard.DefaultImageCacheSize),
 	}
 }
 
@@ -247,6 +280,23 @@ func (h *Handler) SetWitnessCache(wc atproto.WitnessCache) {
 	h.witnessCache = wc
 }
 
+// SetPasswordSessions enables app-password logins, storing their sessions in
+// store.
+func (h *Handler) SetPasswordSessions(store atproto.PasswordSessionStore) {
+	h.passwordSessions = store
+}
+
+// SessionRevoker deletes every stored session for a DID and reports the
+// session IDs it removed.
+type SessionRevoker interface {
+	DeleteAllForDID(ctx context.Context, did syntax.DID) ([]string, error)
+}
+
+// SetSessionRevoker enables HandleLogoutAll.
+func (h *Handler) SetSessionRevoker(r SessionRevoker) {
+	h.sessionRevoker = r
+}
+
 // WitnessCache exposes the witness cache for per-app handler packages.
 func (h *Handler) WitnessCache() atproto.WitnessCache { return h.witnessCache }
 
@@ -269,12 +319,30 @@ func (h *Handler) SetModeration(svc *moderation.Service, store *moderationsqlite
 	h.moderationStore = store
 }
 
+// SetLabeler configures where hide and unhide decisions are published as
+// atproto labels. Without one, moderation stays local to this instance.
+func (h *Handler) SetLabeler(l moderation.Labeler) {
+	h.labeler = l
+}
+
 // SetBackupService wires the backup service so admin handlers can surface
 // per-source backup status. Optional — handlers tolerate a nil service.
 func (h *Handler) SetBackupService(svc *backup.Service) {
 	h.backupService = svc
 }
 
+// SetFirehoseConsumer wires the consumer that runs full index rebuilds for
+// the admin reindex endpoints. Without one, those endpoints answer 503.
+func (h *Handler) SetFirehoseConsumer(c *firehose.Consumer) {
+	h.firehoseConsumer = c
+}
+
+// SetWebhooks wires the webhook service whose endpoints the admin dashboard
+// lists and edits. Without one, the webhook endpoints answer 503.
+func (h *Handler) SetWebhooks(svc *webhooks.Service) {
+	h.webhooks = svc
+}
+
 // invalidateFeedCache clears the public feed cache after a mutation.
 func (h *Handler) InvalidateFeedCache() {
 	if h.feedService != nil {
@@ -428,13 +496,18 @@ func (h *Handler) GetRecordStore(r *http.Request) (records.Store, bool) {
 
 	// Create user-scoped atproto store with injected cache. App-specific
 	// social NSIDs are plumbed in so oolong stores write to
-	// social.oolong.alpha.{like,comment} rather than arabica's collections.
-	var likeNSID, commentNSID string
+	// social.oolong.alpha.{like,comment,...} rather than arabica's
+	// collections.
+	var collections atproto.SocialCollections
 	if h.app != nil {
-		likeNSID = h.app.LikeNSID()
-		commentNSID = h.app.CommentNSID()
+		collections = atproto.SocialCollections{
+			Like:     h.app.LikeNSID(),
+			Comment:  h.app.CommentNSID(),
+			Follow:   h.app.FollowNSID(),
+			Bookmark: h.app.BookmarkNSID(),
+		}
 	}
-	store := atproto.NewAtprotoStoreForApp(h.atprotoClient, did, sessionID, h.sessionCache, h.witnessCache, likeNSID, commentNSID)
+	store := atproto.NewAtprotoStoreForApp(h.atprotoClient, did, sessionID, h.sessionCache, h.witnessCache, collections)
 	if h.app != nil && h.app.RecordStore != nil {
 		return h.app.RecordStore(store), true
 	}
@@ -448,6 +521,7 @@ type socialStore interface {
 	GetUserLikeForSubject(ctx context.Context, subjectURI string) (*social.Like, error)
 	CreateComment(ctx context.Context, req *social.CreateCommentRequest) (*social.Comment, error)
 	DeleteCommentByRKey(ctx context.Context, rkey string) error
+	UpdateCommentByRKey(ctx context.Context, rkey, text string) (*social.Comment, error)
 }
 
 func (h *Handler) getSocialStore(r *http.Request) (socialStore, bool) {
@@ -477,16 +551,66 @@ func (h *Handler) LayoutDataFromRequest(r *http.Request, title string) (layoutDa
 }
 
 // HandleStoreError writes the appropriate HTTP error for a store operation failure.
-// If the error indicates an expired OAuth session, it returns 401 Unauthorized with
-// a user-friendly message. Otherwise it returns 500 with the fallbackMessage.
+// An expired OAuth session, or a PDS rejecting our token, returns 401 Unauthorized
+// so the client can send the user back through login, and a lost swapRecord race
+// returns 409 Conflict asking the user to reload. Other PDS answers keep their
+// meaning, see pdsErrorStatus. Anything else returns 500 with the fallbackMessage.
 func HandleStoreError(w http.ResponseWriter, err error, fallbackMessage string) {
 	if errors.Is(err, atproto.ErrSessionExpired) {
 		http.Error(w, "Your session has expired. Please log in again.", http.StatusUnauthorized)
 		return
 	}
+	if errors.Is(err, atproto.ErrRecordModified) {
+		http.Error(w, "This record was modified since you loaded it. Please reload and try again.", http.StatusConflict)
+		return
+	}
+	if pe, ok := atproto.AsPDSError(err); ok {
+		if status, message := pdsErrorStatus(pe); status != 0 {
+			if status == http.StatusTooManyRequests && pe.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(pe.RetryAfter.Seconds())+1))
+			}
+			http.Error(w, message, status)
+			return
+		}
+	}
 	http.Error(w, fallbackMessage, http.StatusInternalServerError)
 }
 
+// pdsErrorStatus picks the status and message to pass on for an error the
+// user's PDS returned. The XRPC error name is trusted over the HTTP status,
+// since PDSes don't all use the same status for a given failure. A zero
+// status means the caller's fallback is as good as it gets.
+func pdsErrorStatus(pe atproto.PDSError) (int, string) {
+	status := pe.StatusCode
+	switch pe.Name {
+	case "ExpiredToken", "InvalidToken", "AuthRequired", "AuthMissing":
+		status = http.StatusUnauthorized
+	case "RecordNotFound":
+		status = http.StatusNotFound
+	case "InvalidSwap":
+		status = http.StatusConflict
+	case "RateLimitExceeded":
+		status = http.StatusTooManyRequests
+	case "BlobTooLarge", "PayloadTooLarge":
+		status = http.StatusRequestEntityTooLarge
+	}
+	switch {
+	case status == http.StatusUnauthorized:
+		return status, "Your session has expired. Please log in again."
+	case status == http.StatusNotFound:
+		return status, "Record not found"
+	case status == http.StatusConflict:
+		return status, "This record was modified since you loaded it. Please reload and try again."
+	case status == http.StatusTooManyRequests:
+		return status, "Your PDS is rate limiting requests. Please wait a moment and try again."
+	case status == http.StatusRequestEntityTooLarge:
+		return status, "The upload is too large for your PDS"
+	case status >= 500:
+		return http.StatusBadGateway, "Your PDS is not responding right now. Please try again later."
+	}
+	return 0, ""
+}
+
 // deleteEntity validates the rkey, calls the delete function, removes the record
 // from the firehose feed index, and returns 200.
 func (h *Handler) DeleteEntity(w http.ResponseWriter, r *http.Request, deleteFn func(context.Context, string) error, entityName string, collection string) {
@@ -587,6 +711,7 @@ func (h *Handler) BuildLayoutData(r *http.Request, title string, isAuthenticated
 		UserDID:                 didStr,
 		UserProfile:             userProfile,
 		CSPNonce:                middleware.CSPNonceFromContext(r.Context()),
+		CSRFToken:               middleware.CSRFTokenFromContext(r.Context()),
 		IsModerator:             isModerator,
 		UnreadNotificationCount: unreadNotifCount,
 		UserPreferences:         userPrefs,
@@ -664,24 +789,19 @@ func (h *Handler) HandleCommentCreate(w http.ResponseWriter, r *http.Request) {
 
 	// Update firehose index (pass parent URI and comment's CID for threading)
 	if h.feedIndex != nil {
-		if err := h.feedIndex.UpsertComment(r.Context(), didStr, comment.RKey, subjectURI, parentURI, comment.CID, text, comment.CreatedAt); err != nil {
+		if err := h.feedIndex.UpsertComment(r.Context(), didStr, comment.RKey, subjectURI, parentURI, comment.CID, text, comment.CreatedAt, time.Time{}); err != nil {
 			log.Warn().Err(err).Str("did", didStr).Str("rkey", comment.RKey).Str("subject_uri", subjectURI).Msg("Failed to upsert comment in feed index")
 		}
 		// Create notification for the comment/reply
 		h.feedIndex.CreateCommentNotification(didStr, subjectURI, parentURI)
 	}
 
-	// Return the updated comment section with threaded comments
-	comments := h.feedIndex.GetThreadedCommentsForSubject(r.Context(), subjectURI, 100, didStr)
-
-	// Build moderation context
-	var modCtx components.CommentModerationContext
-	if h.moderationService != nil {
-		if h.moderationService.IsModerator(didStr) {
-			modCtx.IsModerator = true
-			modCtx.CanHideRecord = h.moderationService.HasPermission(didStr, moderation.PermissionHideRecord)
-			modCtx.CanBlockUser = h.moderationService.HasPermission(didStr, moderation.PermissionBlacklistUser)
-		}
+	// Return the updated comment section with threaded comments so HTMX can
+	// swap it in place, including the new comment under its parent.
+	var comments []firehose.IndexedComment
+	if h.feedIndex != nil {
+		comments = h.feedIndex.GetThreadedCommentsForSubject(r.Context(), subjectURI, 100, didStr)
+		comments = h.FilterHiddenComments(r.Context(), comments)
 	}
 
 	if err := components.CommentSection(components.CommentSectionProps{
@@ -690,7 +810,7 @@ func (h *Handler) HandleCommentCreate(w http.ResponseWriter, r *http.Request) {
 		Comments:        comments,
 		IsAuthenticated: true,
 		CurrentUserDID:  didStr,
-		ModCtx:          modCtx,
+		ModCtx:          h.commentModerationContext(didStr),
 	}).Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render", http.StatusInternalServerError)
 		log.Error().Err(err).Msg("Failed to render comment section")
@@ -728,7 +848,7 @@ func (h *Handler) HandleCommentDelete(w http.ResponseWriter, r *http.Request) {
 		// Look up subject URI before deletion for notification cleanup
 		subjectURI := h.feedIndex.GetCommentSubjectURI(didStr, rkey)
 
-		if err := h.feedIndex.DeleteComment(r.Context(), didStr, rkey, ""); err != nil {
+		if err := h.feedIndex.DeleteComment(r.Context(), didStr, rkey, subjectURI); err != nil {
 			log.Warn().Err(err).Str("did", didStr).Str("rkey", rkey).Msg("Failed to delete comment from feed index")
 		}
 
@@ -742,6 +862,79 @@ func (h *Handler) HandleCommentDelete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// HandleCommentEdit replaces the text of the authenticated user's comment and
+// returns the refreshed comment section.
+func (h *Handler) HandleCommentEdit(w http.ResponseWriter, r *http.Request) {
+	store, authenticated := h.getSocialStore(r)
+	if !authenticated {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	didStr, _ := atpmiddleware.GetDID(r.Context())
+
+	rkey := ValidateRKey(w, r.PathValue("id"))
+	if rkey == "" {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		log.Warn().Err(err).Msg("Failed to parse comment edit form")
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	text := strings.TrimSpace(r.FormValue("text"))
+	if text == "" {
+		http.Error(w, "comment text is required", http.StatusBadRequest)
+		return
+	}
+	if len(text) > social.MaxCommentLength {
+		http.Error(w, "comment text is too long", http.StatusBadRequest)
+		return
+	}
+
+	// Comments are indexed per author, so a miss here means the comment is
+	// not the caller's to edit (or doesn't exist at all).
+	if h.feedIndex != nil && h.feedIndex.GetCommentSubjectURI(didStr, rkey) == "" {
+		http.Error(w, "Comment not found", http.StatusNotFound)
+		return
+	}
+
+	comment, err := store.UpdateCommentByRKey(r.Context(), rkey, text)
+	if err != nil {
+		log.Error().Err(err).Str("rkey", rkey).Str("did", didStr).Msg("Failed to update comment on PDS")
+		HandleStoreError(w, err, "Failed to update comment")
+		return
+	}
+
+	metrics.CommentsTotal.WithLabelValues("edit").Inc()
+
+	// Keep the stored CID: PutRecord doesn't return the new one, and replies
+	// reference the comment by URI+CID. The firehose update fills it in.
+	var comments []firehose.IndexedComment
+	if h.feedIndex != nil {
+		cid := h.feedIndex.GetCommentCID(didStr, rkey)
+		if err := h.feedIndex.UpsertComment(r.Context(), didStr, rkey, comment.SubjectURI, comment.ParentURI, cid, comment.Text, comment.CreatedAt, comment.EditedAt); err != nil {
+			log.Warn().Err(err).Str("did", didStr).Str("rkey", rkey).Msg("Failed to re-index edited comment")
+		}
+		comments = h.feedIndex.GetThreadedCommentsForSubject(r.Context(), comment.SubjectURI, 100, didStr)
+		comments = h.FilterHiddenComments(r.Context(), comments)
+	}
+
+	if err := components.CommentSection(components.CommentSectionProps{
+		SubjectURI:      comment.SubjectURI,
+		SubjectCID:      comment.SubjectCID,
+		Comments:        comments,
+		IsAuthenticated: true,
+		CurrentUserDID:  didStr,
+		ModCtx:          h.commentModerationContext(didStr),
+	}).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render", http.StatusInternalServerError)
+		log.Error().Err(err).Msg("Failed to render comment section")
+	}
+}
+
 // filterHiddenComments removes comments that have been hidden by moderation.
 // Children of hidden comments are kept but shifted up in depth.
 func (h *Handler) FilterHiddenComments(ctx context.Context, comments []firehose.IndexedComment) []firehose.IndexedComment {
@@ -780,6 +973,19 @@ func (h *Handler) FilterHiddenComments(ctx context.Context, comments []firehose.
 	return filtered
 }
 
+// commentModerationContext returns the moderation controls a viewer may use on
+// a comment section. It is empty for anonymous viewers and non-moderators.
+func (h *Handler) commentModerationContext(viewerDID string) components.CommentModerationContext {
+	var modCtx components.CommentModerationContext
+	if h.moderationService == nil || viewerDID == "" || !h.moderationService.IsModerator(viewerDID) {
+		return modCtx
+	}
+	modCtx.IsModerator = true
+	modCtx.CanHideRecord = h.moderationService.HasPermission(viewerDID, moderation.PermissionHideRecord)
+	modCtx.CanBlockUser = h.moderationService.HasPermission(viewerDID, moderation.PermissionBlacklistUser)
+	return modCtx
+}
+
 // HandleCommentList returns the comment section for a subject
 func (h *Handler) HandleCommentList(w http.ResponseWriter, r *http.Request) {
 	subjectURI := r.URL.Query().Get("subject_uri")
@@ -801,23 +1007,13 @@ func (h *Handler) HandleCommentList(w http.ResponseWriter, r *http.Request) {
 		comments = h.FilterHiddenComments(r.Context(), comments)
 	}
 
-	// Build moderation context
-	var modCtx components.CommentModerationContext
-	if h.moderationService != nil && isAuthenticated {
-		if h.moderationService.IsModerator(didStr) {
-			modCtx.IsModerator = true
-			modCtx.CanHideRecord = h.moderationService.HasPermission(didStr, moderation.PermissionHideRecord)
-			modCtx.CanBlockUser = h.moderationService.HasPermission(didStr, moderation.PermissionBlacklistUser)
-		}
-	}
-
 	if err := components.CommentSection(components.CommentSectionProps{
 		SubjectURI:      subjectURI,
 		SubjectCID:      subjectCID,
 		Comments:        comments,
 		IsAuthenticated: isAuthenticated,
 		CurrentUserDID:  didStr,
-		ModCtx:          modCtx,
+		ModCtx:          h.commentModerationContext(didStr),
 	}).Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render", http.StatusInternalServerError)
 		log.Error().Err(err).Msg("Failed to render comment section")
@@ -831,7 +1027,7 @@ func (h *Handler) HandleCreateAccount(w http.ResponseWriter, r *http.Request) {
 
 	props := pages.CreateAccountProps{
 		Error:      r.URL.Query().Get("error"),
-		Categories: signup.Categories(h.devMode),
+		Categories: signup.Categories(h.signupOptions()),
 	}
 
 	if err := pages.CreateAccount(layoutData, props).Render(r.Context(), w); err != nil {
@@ -840,6 +1036,15 @@ func (h *Handler) HandleCreateAccount(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// signupOptions returns the provider catalog options for this deployment.
+func (h *Handler) signupOptions() signup.Options {
+	return signup.Options{
+		DevMode:      h.devMode,
+		HandleDomain: h.config.HandleDomain,
+		SignupURL:    h.config.SignupURL,
+	}
+}
+
 // HandleCreateAccountSubmit initiates the OAuth prompt=create flow (POST /join/create).
 func (h *Handler) HandleCreateAccountSubmit(w http.ResponseWriter, r *http.Request) {
 	if h.oauth == nil {
@@ -858,7 +1063,7 @@ func (h *Handler) HandleCreateAccountSubmit(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	if !signup.IsAllowedPDSURL(pdsURL, h.devMode) {
+	if !signup.IsAllowedPDSURL(pdsURL, h.signupOptions()) {
 		log.Warn().Str("pds_url", pdsURL).Msg("Signup attempt with unlisted PDS URL")
 		http.Redirect(w, r, "/join/create?error=Invalid+server+selection", http.StatusSeeOther)
 		return
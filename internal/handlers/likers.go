@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"tangled.org/arabica.social/arabica/internal/web/components"
+
+	"github.com/rs/zerolog/log"
+)
+
+// likersPageSize is how many likers are shown per page of the "liked by"
+// modal. Popular records can have thousands, so they are paged by DID.
+const likersPageSize = 50
+
+// HandleLikers renders the accounts that liked the record in ?uri=. Without
+// a cursor it returns the full modal; with one it returns the next page only.
+func (h *Handler) HandleLikers(w http.ResponseWriter, r *http.Request) {
+	subjectURI := r.URL.Query().Get("uri")
+	if !strings.HasPrefix(subjectURI, "at://") {
+		http.Error(w, "A valid uri is required", http.StatusBadRequest)
+		return
+	}
+	if h.feedIndex == nil {
+		http.Error(w, "Likes are not available", http.StatusNotImplemented)
+		return
+	}
+	cursor := r.URL.Query().Get("cursor")
+
+	// Fetch one extra to learn whether another page exists.
+	dids := h.feedIndex.GetLikers(r.Context(), subjectURI, likersPageSize+1, cursor)
+	props := components.LikersProps{SubjectURI: subjectURI}
+	if len(dids) > likersPageSize {
+		dids = dids[:likersPageSize]
+		props.NextCursor = dids[len(dids)-1]
+	}
+
+	profiles := h.feedIndex.GetProfiles(r.Context(), dids)
+	props.Likers = make([]components.Liker, 0, len(dids))
+	for _, did := range dids {
+		liker := components.Liker{Handle: did}
+		if p := profiles[did]; p != nil {
+			liker.Handle = p.Handle
+			if p.DisplayName != nil {
+				liker.DisplayName = *p.DisplayName
+			}
+			if p.Avatar != nil {
+				liker.AvatarURL = *p.Avatar
+			}
+		}
+		props.Likers = append(props.Likers, liker)
+	}
+
+	component := components.LikersModal(props)
+	if cursor != "" {
+		component = components.LikersPage(props)
+	}
+	if err := component.Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render likers", http.StatusInternalServerError)
+		log.Error().Err(err).Msg("Failed to render likers")
+	}
+}
@@ -39,7 +39,7 @@ func (h *Handler) HandleNotifications(w http.ResponseWriter, r *http.Request) {
 		for _, notif := range notifications {
 			item := pages.NotificationItem{
 				Notification: notif,
-				Link:         resolveNotificationLink(h.app, notif.SubjectURI),
+				Link:         h.notificationLink(notif.SubjectURI),
 				ActionText:   notifActionText(h.app, notif),
 			}
 
@@ -91,6 +91,21 @@ func (h *Handler) HandleNotificationsMarkRead(w http.ResponseWriter, r *http.Req
 	http.Redirect(w, r, "/notifications", http.StatusSeeOther)
 }
 
+// notificationLink resolves the page a notification should open. Comments
+// have no page of their own, so a notification about a comment (e.g. a like)
+// links to the record the comment was left on, anchored at the comment.
+func (h *Handler) notificationLink(subjectURI string) string {
+	did, collection, rkey, ok := parseNotificationSubjectURI(subjectURI)
+	if !ok || h.app == nil || h.feedIndex == nil || collection != h.app.CommentNSID() {
+		return resolveNotificationLink(h.app, subjectURI)
+	}
+	link := resolveNotificationLink(h.app, h.feedIndex.GetCommentSubjectURI(did, rkey))
+	if link == "" {
+		return ""
+	}
+	return link + "#comment-" + rkey
+}
+
 // resolveNotificationLink converts a SubjectURI (AT-URI) to a local page URL.
 // Format: at://did:plc:xxx/social.arabica.alpha.brew/rkey -> /brews/did:plc:xxx/rkey
 func resolveNotificationLink(app *domain.App, subjectURI string) string {
@@ -111,6 +126,9 @@ func resolveNotificationEntityName(app *domain.App, subjectURI string) string {
 	if !ok || app == nil {
 		return "content"
 	}
+	if collection == app.CommentNSID() {
+		return "comment"
+	}
 	if route, ok := app.EntityRouteByNSID(collection); ok && route.Noun != "" {
 		return route.Noun
 	}
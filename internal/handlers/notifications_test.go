@@ -5,6 +5,7 @@ import (
 
 	"tangled.org/arabica.social/arabica/internal/atplatform/domain"
 	"tangled.org/arabica.social/arabica/internal/entities"
+	"tangled.org/arabica.social/arabica/internal/notifications"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -54,3 +55,13 @@ func TestResolveNotificationEntityNameUsesDescriptorNounWithFallback(t *testing.
 	assert.Equal(t, "recipe", resolveNotificationEntityName(app, "at://did:plc:alice/social.arabica.alpha.recipe/3abc"))
 	assert.Equal(t, "content", resolveNotificationEntityName(app, "at://did:plc:alice/social.oolong.alpha.tea/3abc"))
 }
+
+func TestNotifActionTextForCommentLike(t *testing.T) {
+	app := &domain.App{NSIDBase: "social.arabica.alpha"}
+	notif := notifications.Notification{
+		Type:       notifications.Like,
+		SubjectURI: "at://did:plc:alice/social.arabica.alpha.comment/3abc",
+	}
+
+	assert.Equal(t, "liked your comment", notifActionText(app, notif))
+}
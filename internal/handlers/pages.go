@@ -111,6 +111,7 @@ func (h *Handler) HandleSettingsPreferences(w http.ResponseWriter, r *http.Reque
 
 	prefs := profileprefs.UserPreferences{
 		TemperatureUnit: profileprefs.TemperatureUnit(r.FormValue("temperature_unit")),
+		UnitSystem:      profileprefs.UnitSystem(r.FormValue("unit_system")),
 	}.WithDefaults()
 
 	if h.feedIndex != nil {
@@ -40,6 +40,18 @@ func ExistingCreatedAt(ctx context.Context, store records.Store, nsid, rkey stri
 	return t
 }
 
+// SwapCIDField is the form field edit forms use to send back the CID of the
+// record they were loaded with.
+const SwapCIDField = "swap_cid"
+
+// SwapCID returns the CID the edit form was rendered from, for use as an
+// update's swap CID: a save from a page loaded before someone else's edit
+// is then refused instead of overwriting it. It is "" when the client
+// didn't send one, which makes the update unconditional.
+func SwapCID(r *http.Request) string {
+	return r.FormValue(SwapCIDField)
+}
+
 // RequestValidator is a pointer constraint for request types with Validate.
 type RequestValidator[T any] interface {
 	*T
@@ -48,16 +60,24 @@ type RequestValidator[T any] interface {
 
 // PutRecord is the shared create/update primitive for handlers that encode a
 // typed model into an app record and write it through a generic record store.
+// For updates, a non-empty swapCID makes the write conditional on the record
+// still having that CID; see records.Store.UpdateRecord.
 func PutRecord(
 	ctx context.Context,
 	store records.Store,
-	nsid, rkey string,
+	nsid, rkey, swapCID string,
 	encode func(records.Store) (map[string]any, error),
 ) (resultRKey string, err error) {
 	rec, err := encode(store)
 	if err != nil {
 		return "", err
 	}
+	if rkey != "" && swapCID != "" {
+		if _, err := store.UpdateRecord(ctx, nsid, rkey, swapCID, rec); err != nil {
+			return "", err
+		}
+		return rkey, nil
+	}
 	newRKey, _, err := store.PutRecord(ctx, nsid, rkey, rec)
 	if err != nil {
 		return "", err
@@ -71,11 +91,12 @@ func PutRecord(
 // RecordCRUDWrite is the common body for standard entity Create + Update
 // handlers: decode, validate, build model, encode to AT Protocol record,
 // write, invalidate, and respond with JSON or an optional HX redirect.
+// swapCID is passed through to PutRecord; creates pass "".
 func RecordCRUDWrite[Req any, PReq RequestValidator[Req], Model any](
 	w http.ResponseWriter,
 	r *http.Request,
 	store records.Store,
-	nsid, jsonKey, rkey string,
+	nsid, jsonKey, rkey, swapCID string,
 	decodeForm func(*http.Request, *Req) error,
 	build func(req *Req) *Model,
 	setRKey func(*Model, string),
@@ -102,7 +123,7 @@ func RecordCRUDWrite[Req any, PReq RequestValidator[Req], Model any](
 		return
 	}
 	model := build(&req)
-	newRKey, err := PutRecord(r.Context(), store, nsid, rkey, func(s records.Store) (map[string]any, error) {
+	newRKey, err := PutRecord(r.Context(), store, nsid, rkey, swapCID, func(s records.Store) (map[string]any, error) {
 		return encode(s, &req, model)
 	})
 	if err != nil {
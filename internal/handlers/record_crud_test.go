@@ -3,12 +3,17 @@ package handlers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/bluesky-social/indigo/atproto/atclient"
+	"github.com/bluesky-social/indigo/xrpc"
 	"github.com/stretchr/testify/assert"
+	"tangled.org/arabica.social/arabica/internal/atproto"
 	"tangled.org/arabica.social/arabica/internal/records"
 )
 
@@ -17,6 +22,7 @@ type crudTestStore struct {
 	putNSID   string
 	putRKey   string
 	putRecord any
+	swapCID   string
 }
 
 func (s *crudTestStore) DID() string { return "did:plc:test" }
@@ -42,6 +48,17 @@ func (s *crudTestStore) PutRecord(_ context.Context, nsid, rkey string, record a
 	return "", "", nil
 }
 
+func (s *crudTestStore) UpdateRecord(_ context.Context, nsid, rkey, swapCID string, record any) (string, error) {
+	s.putNSID = nsid
+	s.putRKey = rkey
+	s.putRecord = record
+	s.swapCID = swapCID
+	if s.putErr != nil {
+		return "", s.putErr
+	}
+	return "new-cid", nil
+}
+
 func (s *crudTestStore) RemoveRecord(context.Context, string, string) error { return nil }
 
 type crudTestRequest struct {
@@ -68,7 +85,7 @@ func TestRecordCRUDWriteCreateSuccess(t *testing.T) {
 	w := httptest.NewRecorder()
 
 	RecordCRUDWrite[crudTestRequest, *crudTestRequest, crudTestModel](
-		w, req, store, "social.test.thing", "thing", "", nil,
+		w, req, store, "social.test.thing", "thing", "", "", nil,
 		func(req *crudTestRequest) *crudTestModel { return &crudTestModel{Name: req.Name} },
 		func(m *crudTestModel, rkey string) { m.RKey = rkey },
 		func(_ records.Store, _ *crudTestRequest, m *crudTestModel) (map[string]any, error) {
@@ -92,7 +109,7 @@ func TestRecordCRUDWriteUpdateSuccessFallsBackToExistingRKey(t *testing.T) {
 	w := httptest.NewRecorder()
 
 	RecordCRUDWrite[crudTestRequest, *crudTestRequest, crudTestModel](
-		w, req, store, "social.test.thing", "thing", "existing", nil,
+		w, req, store, "social.test.thing", "thing", "existing", "", nil,
 		func(req *crudTestRequest) *crudTestModel { return &crudTestModel{Name: req.Name} },
 		func(m *crudTestModel, rkey string) { m.RKey = rkey },
 		func(_ records.Store, _ *crudTestRequest, m *crudTestModel) (map[string]any, error) {
@@ -113,7 +130,7 @@ func TestRecordCRUDWriteDecodeError(t *testing.T) {
 	w := httptest.NewRecorder()
 
 	RecordCRUDWrite[crudTestRequest, *crudTestRequest, crudTestModel](
-		w, req, store, "social.test.thing", "thing", "", nil,
+		w, req, store, "social.test.thing", "thing", "", "", nil,
 		func(req *crudTestRequest) *crudTestModel { return &crudTestModel{Name: req.Name} },
 		func(m *crudTestModel, rkey string) { m.RKey = rkey },
 		func(_ records.Store, _ *crudTestRequest, _ *crudTestModel) (map[string]any, error) { return nil, nil },
@@ -131,7 +148,7 @@ func TestRecordCRUDWriteValidationError(t *testing.T) {
 	w := httptest.NewRecorder()
 
 	RecordCRUDWrite[crudTestRequest, *crudTestRequest, crudTestModel](
-		w, req, store, "social.test.thing", "thing", "", nil,
+		w, req, store, "social.test.thing", "thing", "", "", nil,
 		func(req *crudTestRequest) *crudTestModel { return &crudTestModel{Name: req.Name} },
 		func(m *crudTestModel, rkey string) { m.RKey = rkey },
 		func(_ records.Store, _ *crudTestRequest, _ *crudTestModel) (map[string]any, error) { return nil, nil },
@@ -149,7 +166,7 @@ func TestRecordCRUDWriteStoreError(t *testing.T) {
 	w := httptest.NewRecorder()
 
 	RecordCRUDWrite[crudTestRequest, *crudTestRequest, crudTestModel](
-		w, req, store, "social.test.thing", "thing", "", nil,
+		w, req, store, "social.test.thing", "thing", "", "", nil,
 		func(req *crudTestRequest) *crudTestModel { return &crudTestModel{Name: req.Name} },
 		func(m *crudTestModel, rkey string) { m.RKey = rkey },
 		func(_ records.Store, _ *crudTestRequest, m *crudTestModel) (map[string]any, error) {
@@ -161,3 +178,67 @@ func TestRecordCRUDWriteStoreError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	assert.Contains(t, w.Body.String(), "Failed to save thing")
 }
+
+func TestRecordCRUDWriteUpdateSwap(t *testing.T) {
+	tests := []struct {
+		name     string
+		putErr   error
+		wantCode int
+		wantBody string
+	}{
+		{"unchanged record", nil, http.StatusOK, `"rkey":"existing"`},
+		{"modified elsewhere", fmt.Errorf("put record: %w", atproto.ErrRecordModified), http.StatusConflict, "Please reload"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &crudTestStore{putErr: tt.putErr}
+			req := httptest.NewRequest(http.MethodPut, "/api/things/existing", strings.NewReader(`{"name":"updated"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			RecordCRUDWrite[crudTestRequest, *crudTestRequest, crudTestModel](
+				w, req, store, "social.test.thing", "thing", "existing", "old-cid", nil,
+				func(req *crudTestRequest) *crudTestModel { return &crudTestModel{Name: req.Name} },
+				func(m *crudTestModel, rkey string) { m.RKey = rkey },
+				func(_ records.Store, _ *crudTestRequest, m *crudTestModel) (map[string]any, error) {
+					return map[string]any{"name": m.Name}, nil
+				},
+				nil, false,
+			)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+			assert.Equal(t, "existing", store.putRKey)
+			assert.Equal(t, "old-cid", store.swapCID)
+		})
+	}
+}
+
+func TestHandleStoreError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   int
+		wantBody   string
+		retryAfter bool
+	}{
+		{"session expired", atproto.ErrSessionExpired, http.StatusUnauthorized, "log in again", false},
+		{"expired token", &atclient.APIError{StatusCode: http.StatusBadRequest, Name: "ExpiredToken"}, http.StatusUnauthorized, "log in again", false},
+		{"unauthorized status", &xrpc.Error{StatusCode: http.StatusUnauthorized}, http.StatusUnauthorized, "log in again", false},
+		{"record not found", &xrpc.Error{StatusCode: http.StatusBadRequest, Wrapped: &xrpc.XRPCError{ErrStr: "RecordNotFound"}}, http.StatusNotFound, "Record not found", false},
+		{"rate limited", &xrpc.Error{StatusCode: http.StatusTooManyRequests, Ratelimit: &xrpc.RatelimitInfo{Reset: time.Now().Add(time.Minute)}}, http.StatusTooManyRequests, "rate limiting", true},
+		{"pds down", &atclient.APIError{StatusCode: http.StatusServiceUnavailable}, http.StatusBadGateway, "not responding", false},
+		{"other bad request", &atclient.APIError{StatusCode: http.StatusBadRequest, Name: "InvalidRequest"}, http.StatusInternalServerError, "Failed to save thing", false},
+		{"not from the pds", errors.New("boom"), http.StatusInternalServerError, "Failed to save thing", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			HandleStoreError(w, fmt.Errorf("create record: %w", tt.err), "Failed to save thing")
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+			assert.Equal(t, tt.retryAfter, w.Header().Get("Retry-After") != "")
+		})
+	}
+}
@@ -16,17 +16,20 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// Automod thresholds for automatic content hiding
-const (
-	// AutoHideThreshold is the number of reports on a single record before auto-hiding
-	AutoHideThreshold = 3
-	// AutoHideUserThreshold is the total reports across a user's records before auto-hiding new reports
-	AutoHideUserThreshold = 5
-	// ReportRateLimitPerHour is the maximum reports a user can submit per hour
-	ReportRateLimitPerHour = 10
-	// MaxReportReasonLength is the maximum length of a report reason
-	MaxReportReasonLength = 500
-)
+// automodSettings returns the thresholds in effect: an admin override saved
+// from the dashboard if there is a valid one, otherwise the deployment's
+// configured values (which themselves fall back to the built-in defaults).
+func (h *Handler) automodSettings(ctx context.Context) moderation.AutomodSettings {
+	if h.moderationStore != nil {
+		saved, err := h.moderationStore.GetAutomodSettings(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("moderation: failed to load automod settings, using configured values")
+		} else if saved != nil && saved.Validate() == nil {
+			return *saved
+		}
+	}
+	return h.config.Automod.WithDefaults()
+}
 
 // HandleReport handles content report submissions from the in-app dialog.
 // Renders HTML partials for htmx: the form re-rendered with an inline error,
@@ -36,71 +39,74 @@ func (h *Handler) HandleReport(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	if err := r.ParseForm(); err != nil {
-		writeReportError(ctx, w, "", "", "", "", "Invalid form data")
+		writeReportError(ctx, w, "", "", "", "", "", "Invalid form data")
 		return
 	}
 	subjectURI := r.FormValue("subject_uri")
 	subjectCID := r.FormValue("subject_cid")
 	rawReason := r.FormValue("reason")
+	rawCategory := r.FormValue("category")
 	dialogID := r.FormValue("dialog_id")
 
 	reporterDID, ok := atpmiddleware.GetDID(ctx)
 	if !ok {
-		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, "Authentication required")
+		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, rawCategory, "Authentication required")
 		return
 	}
 
 	if h.moderationStore == nil {
 		log.Error().Msg("moderation: store not configured")
-		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, "Reports are not enabled")
+		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, rawCategory, "Reports are not enabled")
 		return
 	}
 
 	if subjectURI == "" {
-		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, "subject_uri is required")
+		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, rawCategory, "subject_uri is required")
 		return
 	}
 
 	uriParts, err := atp.ParseATURI(subjectURI)
 	if err != nil {
-		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, "Invalid subject_uri format")
+		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, rawCategory, "Invalid subject_uri format")
 		return
 	}
 	subjectDID := uriParts.DID
 
 	if subjectDID == reporterDID {
-		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, "You cannot report your own content")
+		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, rawCategory, "You cannot report your own content")
 		return
 	}
 
+	automod := h.automodSettings(ctx)
+	category := moderation.ParseReportCategory(rawCategory)
 	reason := strings.TrimSpace(rawReason)
 	if reason == "" {
 		reason = "No reason provided"
 	}
-	if len(reason) > MaxReportReasonLength {
-		reason = reason[:MaxReportReasonLength]
+	if len(reason) > automod.MaxReportReasonLength {
+		reason = reason[:automod.MaxReportReasonLength]
 	}
 
 	oneHourAgo := time.Now().Add(-1 * time.Hour)
 	recentCount, err := h.moderationStore.CountReportsFromUserSince(ctx, reporterDID, oneHourAgo)
 	if err != nil {
 		log.Error().Err(err).Str("reporter", reporterDID).Msg("moderation: failed to check rate limit")
-		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, "Failed to process report")
+		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, rawCategory, "Failed to process report")
 		return
 	}
-	if recentCount >= ReportRateLimitPerHour {
-		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, "Rate limit exceeded. Please try again later.")
+	if recentCount >= automod.ReportRateLimitPerHour {
+		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, rawCategory, "Rate limit exceeded. Please try again later.")
 		return
 	}
 
 	alreadyReported, err := h.moderationStore.HasReportedURI(ctx, reporterDID, subjectURI)
 	if err != nil {
 		log.Error().Err(err).Str("reporter", reporterDID).Msg("moderation: failed to check duplicate")
-		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, "Failed to process report")
+		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, rawCategory, "Failed to process report")
 		return
 	}
 	if alreadyReported {
-		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, "You have already reported this content")
+		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, rawCategory, "You have already reported this content")
 		return
 	}
 
@@ -109,6 +115,7 @@ func (h *Handler) HandleReport(w http.ResponseWriter, r *http.Request) {
 		SubjectURI:  subjectURI,
 		SubjectDID:  subjectDID,
 		ReporterDID: reporterDID,
+		Category:    category,
 		Reason:      reason,
 		CreatedAt:   time.Now(),
 		Status:      moderation.ReportStatusPending,
@@ -116,7 +123,7 @@ func (h *Handler) HandleReport(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.moderationStore.CreateReport(ctx, report); err != nil {
 		log.Error().Err(err).Str("reporter", reporterDID).Msg("moderation: failed to create report")
-		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, "Failed to save report")
+		writeReportError(ctx, w, dialogID, subjectURI, subjectCID, rawReason, rawCategory, "Failed to save report")
 		return
 	}
 
@@ -127,10 +134,11 @@ func (h *Handler) HandleReport(w http.ResponseWriter, r *http.Request) {
 		Str("subject_uri", report.SubjectURI).
 		Str("subject_did", report.SubjectDID).
 		Str("reporter_did", report.ReporterDID).
+		Str("category", string(report.Category)).
 		Str("reason", report.Reason).
 		Msg("moderation: report created")
 
-	h.checkAutomod(ctx, report)
+	h.checkAutomod(ctx, report, automod)
 
 	// Toast + delayed close. The success partial stays visible for the delay
 	// so the user sees the confirmation before the dialog disappears.
@@ -146,7 +154,7 @@ func (h *Handler) HandleReport(w http.ResponseWriter, r *http.Request) {
 }
 
 // checkAutomod checks if automod thresholds are met and auto-hides content if needed.
-func (h *Handler) checkAutomod(ctx context.Context, report moderation.Report) {
+func (h *Handler) checkAutomod(ctx context.Context, report moderation.Report, automod moderation.AutomodSettings) {
 	// Skip if record is already hidden
 	if h.moderationStore.IsRecordHidden(ctx, report.SubjectURI) {
 		return
@@ -180,10 +188,10 @@ func (h *Handler) checkAutomod(ctx context.Context, report moderation.Report) {
 	shouldAutoHide := false
 	autoHideReason := ""
 
-	if uriReportCount >= AutoHideThreshold {
+	if uriReportCount >= automod.AutoHideThreshold {
 		shouldAutoHide = true
 		autoHideReason = fmt.Sprintf("Auto-hidden: %d reports on this record", uriReportCount)
-	} else if didReportCount >= AutoHideUserThreshold {
+	} else if didReportCount >= automod.AutoHideUserThreshold {
 		shouldAutoHide = true
 		autoHideReason = fmt.Sprintf("Auto-hidden: %d total reports against user's content", didReportCount)
 	}
@@ -202,6 +210,7 @@ func (h *Handler) checkAutomod(ctx context.Context, report moderation.Report) {
 			log.Error().Err(err).Str("uri", report.SubjectURI).Msg("moderation: automod failed to hide record")
 			return
 		}
+		h.InvalidateFeedCache()
 
 		// Log the automod action
 		auditEntry := moderation.AuditEntry{
@@ -217,6 +226,7 @@ func (h *Handler) checkAutomod(ctx context.Context, report moderation.Report) {
 		if err := h.moderationStore.LogAction(ctx, auditEntry); err != nil {
 			log.Error().Err(err).Msg("moderation: failed to log automod action")
 		}
+		h.emitHideLabel(report.SubjectURI, autoHideReason, false)
 
 		log.Warn().
 			Str("uri", report.SubjectURI).
@@ -230,14 +240,15 @@ func (h *Handler) checkAutomod(ctx context.Context, report moderation.Report) {
 
 // writeReportError re-renders the report form with an inline error message.
 // Always returns 200 OK — htmx ignores 4xx/5xx by default and won't swap. The
-// reason field is preserved so the user doesn't lose their typing.
-func writeReportError(ctx context.Context, w http.ResponseWriter, dialogID, subjectURI, subjectCID, reason, message string) {
+// reason and category are preserved so the user doesn't lose their input.
+func writeReportError(ctx context.Context, w http.ResponseWriter, dialogID, subjectURI, subjectCID, reason, category, message string) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	_ = components.ReportForm(components.ReportFormProps{
 		DialogID:     dialogID,
 		SubjectURI:   subjectURI,
 		SubjectCID:   subjectCID,
 		Reason:       reason,
+		Category:     category,
 		ErrorMessage: message,
 	}).Render(ctx, w)
 }
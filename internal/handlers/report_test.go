@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/firehose"
+	"tangled.org/arabica.social/arabica/internal/moderation"
+	moderationsqlite "tangled.org/arabica.social/arabica/internal/moderation/sqlite"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+)
+
+func TestHandleReportStoresCategory(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+	store := moderationsqlite.NewModerationStore(idx.DB())
+
+	h := &Handler{}
+	h.SetModeration(nil, store)
+
+	tests := []struct {
+		name     string
+		rkey     string
+		category string
+		want     moderation.ReportCategory
+	}{
+		{"known category", "a", "harassment", moderation.ReportCategoryHarassment},
+		{"missing category defaults to other", "b", "", moderation.ReportCategoryOther},
+		{"unknown category defaults to other", "c", "rude", moderation.ReportCategoryOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{
+				"subject_uri": {"at://did:plc:bob/social.arabica.alpha.brew/" + tt.rkey},
+				"reason":      {"details"},
+				"category":    {tt.category},
+			}
+			req := httptest.NewRequest(http.MethodPost, "/api/report", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req = req.WithContext(atpmiddleware.ContextWithAuth(req.Context(), "did:plc:alice", "sess"))
+			h.HandleReport(httptest.NewRecorder(), req)
+
+			reports, err := store.ListPendingReports(req.Context())
+			require.NoError(t, err)
+			var got *moderation.Report
+			for i := range reports {
+				if reports[i].SubjectURI == form.Get("subject_uri") {
+					got = &reports[i]
+				}
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, tt.want, got.Category)
+		})
+	}
+}
+
+func TestListPendingReportsPage(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+	store := moderationsqlite.NewModerationStore(idx.DB())
+	ctx := t.Context()
+
+	// Reports 1 and 2 share a timestamp so paging has to break the tie.
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, r := range []moderation.Report{
+		{ID: "1", Category: moderation.ReportCategorySpam, CreatedAt: base},
+		{ID: "2", Category: moderation.ReportCategoryOther, CreatedAt: base},
+		{ID: "3", Category: moderation.ReportCategorySpam, CreatedAt: base.Add(time.Minute)},
+	} {
+		r.SubjectURI = fmt.Sprintf("at://did:plc:bob/social.arabica.alpha.brew/%d", i)
+		r.Status = moderation.ReportStatusPending
+		require.NoError(t, store.CreateReport(ctx, r))
+	}
+
+	t.Run("category", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			category string
+			wantIDs  []string
+		}{
+			{"no filter", "", []string{"3", "2", "1"}},
+			{"spam only", "spam", []string{"3", "1"}},
+			{"nothing matches", "illegal", []string{}},
+			{"unknown filter shows all", "bogus", []string{"3", "2", "1"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				reports, next, err := store.ListPendingReportsPage(ctx, tt.category, "", 10)
+				require.NoError(t, err)
+				assert.Empty(t, next)
+				ids := []string{}
+				for _, r := range reports {
+					ids = append(ids, r.ID)
+				}
+				assert.Equal(t, tt.wantIDs, ids)
+			})
+		}
+	})
+
+	t.Run("pages are stable", func(t *testing.T) {
+		var ids []string
+		cursor := ""
+		for range 3 {
+			reports, next, err := store.ListPendingReportsPage(ctx, "", cursor, 1)
+			require.NoError(t, err)
+			require.Len(t, reports, 1)
+			ids = append(ids, reports[0].ID)
+			cursor = next
+		}
+		assert.Equal(t, []string{"3", "2", "1"}, ids)
+		assert.Empty(t, cursor, "no cursor after the last page")
+	})
+}
+
+func TestListAuditLogPage(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+	store := moderationsqlite.NewModerationStore(idx.DB())
+	ctx := t.Context()
+
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		require.NoError(t, store.LogAction(ctx, moderation.AuditEntry{
+			ID:        fmt.Sprintf("a%d", i),
+			Action:    moderation.AuditActionHideRecord,
+			ActorDID:  "did:plc:mod",
+			Timestamp: base.Add(time.Duration(i/2) * time.Second),
+		}))
+	}
+
+	first, cursor, err := store.ListAuditLogPage(ctx, "", 3)
+	require.NoError(t, err)
+	require.NotEmpty(t, cursor)
+	second, cursor, err := store.ListAuditLogPage(ctx, cursor, 3)
+	require.NoError(t, err)
+	assert.Empty(t, cursor)
+
+	var ids []string
+	for _, e := range append(first, second...) {
+		ids = append(ids, e.ID)
+	}
+	assert.Equal(t, []string{"a4", "a3", "a2", "a1", "a0"}, ids)
+}
+
+func TestListResolvedReports(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+	store := moderationsqlite.NewModerationStore(idx.DB())
+	ctx := t.Context()
+
+	for _, id := range []string{"1", "2", "3"} {
+		require.NoError(t, store.CreateReport(ctx, moderation.Report{
+			ID:          id,
+			SubjectURI:  "at://did:plc:bob/social.arabica.alpha.brew/" + id,
+			SubjectDID:  "did:plc:bob",
+			ReporterDID: "did:plc:alice",
+			CreatedAt:   time.Now(),
+			Status:      moderation.ReportStatusPending,
+		}))
+	}
+	require.NoError(t, store.ResolveReport(ctx, "1", moderation.ReportStatusDismissed, "did:plc:mod"))
+	require.NoError(t, store.ResolveReport(ctx, "3", moderation.ReportStatusActioned, "did:plc:mod"))
+
+	first, cursor, err := store.ListResolvedReports(ctx, 1, "")
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+	assert.Equal(t, "3", first[0].ID, "most recently resolved first")
+	assert.Equal(t, moderation.ReportStatusActioned, first[0].Status)
+	assert.Equal(t, "did:plc:mod", first[0].ResolvedBy)
+	require.NotEmpty(t, cursor)
+
+	second, cursor, err := store.ListResolvedReports(ctx, 1, cursor)
+	require.NoError(t, err)
+	require.Len(t, second, 1)
+	assert.Equal(t, "1", second[0].ID)
+	assert.Empty(t, cursor, "pending report 2 is not part of the history")
+}
+
+func TestAutomodSettings(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+	store := moderationsqlite.NewModerationStore(idx.DB())
+
+	h := &Handler{config: Config{Automod: moderation.AutomodSettings{AutoHideThreshold: 2}}}
+	h.SetModeration(nil, store)
+	ctx := t.Context()
+
+	t.Run("config overrides defaults", func(t *testing.T) {
+		got := h.automodSettings(ctx)
+		assert.Equal(t, 2, got.AutoHideThreshold)
+		assert.Equal(t, moderation.DefaultAutomodSettings().ReportRateLimitPerHour, got.ReportRateLimitPerHour)
+	})
+
+	t.Run("admin form", func(t *testing.T) {
+		post := func(form url.Values) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/_mod/automod", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req = req.WithContext(atpmiddleware.ContextWithAuth(req.Context(), "did:plc:admin", "sess"))
+			rec := httptest.NewRecorder()
+			h.HandleUpdateAutomod(rec, req)
+			return rec
+		}
+		valid := url.Values{
+			"auto_hide_threshold":        {"4"},
+			"auto_hide_user_threshold":   {"8"},
+			"report_rate_limit_per_hour": {"20"},
+			"max_report_reason_length":   {"1000"},
+		}
+
+		invalid := url.Values{}
+		for k, v := range valid {
+			invalid[k] = v
+		}
+		invalid.Set("auto_hide_threshold", "0")
+		rec := post(invalid)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, 2, h.automodSettings(ctx).AutoHideThreshold, "rejected values are not saved")
+
+		rec = post(valid)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, moderation.AutomodSettings{
+			AutoHideThreshold:      4,
+			AutoHideUserThreshold:  8,
+			ReportRateLimitPerHour: 20,
+			MaxReportReasonLength:  1000,
+		}, h.automodSettings(ctx), "saved settings take precedence over config")
+
+		audit, err := store.ListAuditLog(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, audit, 1)
+		assert.Equal(t, moderation.AuditActionUpdateAutomod, audit[0].Action)
+	})
+}
+
+func TestCheckAutomod_RespectsAutoHideReset(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+	store := moderationsqlite.NewModerationStore(idx.DB())
+	h := &Handler{}
+	h.SetModeration(nil, store)
+	ctx := t.Context()
+
+	// Only the per-user threshold can fire: two reports against bob's content.
+	automod := moderation.AutomodSettings{AutoHideThreshold: 100, AutoHideUserThreshold: 2}.WithDefaults()
+	report := func(rkey string) string {
+		uri := "at://did:plc:bob/social.arabica.alpha.brew/" + rkey
+		r := moderation.Report{
+			ID:          generateTID(),
+			SubjectURI:  uri,
+			SubjectDID:  "did:plc:bob",
+			ReporterDID: "did:plc:alice-" + rkey,
+			CreatedAt:   time.Now(),
+			Status:      moderation.ReportStatusPending,
+		}
+		require.NoError(t, store.CreateReport(ctx, r))
+		h.checkAutomod(ctx, r, automod)
+		return uri
+	}
+
+	first := report("a")
+	assert.False(t, store.IsRecordHidden(ctx, first))
+	second := report("b")
+	assert.True(t, store.IsRecordHidden(ctx, second), "second report reaches the user threshold")
+
+	require.NoError(t, store.SetAutoHideReset(ctx, "did:plc:bob", time.Now()))
+
+	third := report("c")
+	assert.False(t, store.IsRecordHidden(ctx, third), "reports before the reset no longer count")
+	fourth := report("d")
+	assert.True(t, store.IsRecordHidden(ctx, fourth), "new reports after the reset accumulate again")
+}
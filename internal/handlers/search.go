@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"tangled.org/arabica.social/arabica/internal/profileprefs"
+	"tangled.org/arabica.social/arabica/internal/web/pages"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxSearchQueryLength bounds ?q= so a pasted essay can't turn into a
+// pathological LIKE pattern.
+const maxSearchQueryLength = 100
+
+// HandleSearch renders the global search page. With ?q= it lists indexed
+// brews and beans matching the query, newest first.
+func (h *Handler) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	layoutData, viewerDID, isAuthenticated := h.LayoutDataFromRequest(r, "Search")
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if runes := []rune(query); len(runes) > maxSearchQueryLength {
+		query = string(runes[:maxSearchQueryLength])
+	}
+
+	props := pages.SearchProps{
+		Query: query,
+		QueryState: pages.FeedQueryState{
+			IsAuthenticated: isAuthenticated,
+			FeedViews:       h.feedViews,
+			BrandName:       h.brand.DisplayName,
+			UserPreferences: profileprefs.DefaultUserPreferences(),
+		},
+	}
+	if h.app != nil {
+		props.QueryState.Descriptors = h.app.Descriptors
+	}
+
+	if query != "" && h.feedIndex != nil {
+		items, err := h.feedIndex.SearchRecords(r.Context(), query, 0)
+		if err != nil {
+			log.Error().Err(err).Str("query", query).Msg("Failed to search records")
+			http.Error(w, "Search failed", http.StatusInternalServerError)
+			return
+		}
+		if isAuthenticated {
+			h.populateFeedViewerState(r.Context(), viewerDID, items)
+			props.QueryState.UserPreferences = h.feedIndex.GetUserPreferences(r.Context(), viewerDID)
+		}
+		props.Items = items
+		props.ModCtx = h.buildModerationContext(r.Context(), viewerDID, items)
+	}
+
+	if err := pages.Search(layoutData, props).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		log.Error().Err(err).Msg("Failed to render search page")
+	}
+}
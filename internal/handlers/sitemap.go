@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/firehose"
+	"tangled.org/arabica.social/arabica/internal/moderation"
+	"tangled.org/pdewey.com/atp"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// sitemapURLsPerFile keeps each file well under the protocol's limit of
+	// 50,000 URLs and 50MB.
+	sitemapURLsPerFile = 10000
+
+	// sitemapMaxURLs caps the whole sitemap. Profiles are listed first and
+	// the newest brews fill the remainder.
+	sitemapMaxURLs = 100000
+
+	// sitemapTTL is how long a generated sitemap is served before a
+	// background rebuild is started.
+	sitemapTTL = 6 * time.Hour
+
+	// sitemapBuildTimeout bounds a rebuild, most of which is resolving
+	// handles for DIDs missing from the profile cache.
+	sitemapBuildTimeout = 5 * time.Minute
+
+	sitemapXMLNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+)
+
+// sitemapEntry is one page in the sitemap. Paths are stored without the
+// host so one cached sitemap serves whichever public URL the request used.
+type sitemapEntry struct {
+	Path    string
+	LastMod time.Time
+}
+
+// sitemapCache holds the last generated sitemap, already split into files.
+type sitemapCache struct {
+	mu          sync.Mutex
+	files       [][]sitemapEntry
+	generatedAt time.Time
+	rebuilding  bool
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+// HandleSitemap serves GET /sitemap.xml. While everything fits in one file
+// it is a plain urlset; past that it becomes a sitemap index pointing at
+// /sitemaps/{n}.xml.
+func (h *Handler) HandleSitemap(w http.ResponseWriter, r *http.Request) {
+	files, err := h.sitemapFiles(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to build sitemap")
+		http.Error(w, "Sitemap unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	baseURL := h.PublicBaseURL(r)
+	if len(files) == 1 {
+		writeSitemapXML(w, sitemapURLSetFor(baseURL, files[0]))
+		return
+	}
+
+	index := sitemapIndex{XMLNS: sitemapXMLNS}
+	for i, file := range files {
+		ref := sitemapURL{Loc: baseURL + "/sitemaps/" + strconv.Itoa(i+1) + ".xml"}
+		if lastMod := latestSitemapMod(file); !lastMod.IsZero() {
+			ref.LastMod = lastMod.UTC().Format(time.RFC3339)
+		}
+		index.Sitemaps = append(index.Sitemaps, ref)
+	}
+	writeSitemapXML(w, index)
+}
+
+// HandleSitemapFile serves GET /sitemaps/{file}, where file is "{n}.xml" and
+// n counts from 1 in the order the sitemap index lists them.
+func (h *Handler) HandleSitemapFile(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(strings.TrimSuffix(r.PathValue("file"), ".xml"))
+	if err != nil || n < 1 {
+		http.NotFound(w, r)
+		return
+	}
+
+	files, err := h.sitemapFiles(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to build sitemap")
+		http.Error(w, "Sitemap unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if n > len(files) {
+		http.NotFound(w, r)
+		return
+	}
+	writeSitemapXML(w, sitemapURLSetFor(h.PublicBaseURL(r), files[n-1]))
+}
+
+func sitemapURLSetFor(baseURL string, entries []sitemapEntry) sitemapURLSet {
+	set := sitemapURLSet{XMLNS: sitemapXMLNS, URLs: make([]sitemapURL, 0, len(entries))}
+	for _, e := range entries {
+		u := sitemapURL{Loc: baseURL + e.Path}
+		if !e.LastMod.IsZero() {
+			u.LastMod = e.LastMod.UTC().Format(time.RFC3339)
+		}
+		set.URLs = append(set.URLs, u)
+	}
+	return set
+}
+
+func latestSitemapMod(entries []sitemapEntry) time.Time {
+	var latest time.Time
+	for _, e := range entries {
+		if e.LastMod.After(latest) {
+			latest = e.LastMod
+		}
+	}
+	return latest
+}
+
+func writeSitemapXML(w http.ResponseWriter, doc any) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Write([]byte(xml.Header))
+	if err := xml.NewEncoder(w).Encode(doc); err != nil {
+		log.Error().Err(err).Msg("Failed to encode sitemap")
+	}
+}
+
+// sitemapFiles returns the cached sitemap, building it on first use. Once
+// it is older than sitemapTTL the stale copy keeps being served while a
+// rebuild runs in the background, so crawlers never wait on handle lookups
+// after the first request.
+func (h *Handler) sitemapFiles(ctx context.Context) ([][]sitemapEntry, error) {
+	c := &h.sitemap
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.files == nil {
+		entries, err := h.buildSitemap(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.files, c.generatedAt = chunkSitemap(entries), time.Now()
+		return c.files, nil
+	}
+
+	if time.Since(c.generatedAt) > sitemapTTL && !c.rebuilding {
+		c.rebuilding = true
+		go h.rebuildSitemap()
+	}
+	return c.files, nil
+}
+
+func (h *Handler) rebuildSitemap() {
+	ctx, cancel := context.WithTimeout(context.Background(), sitemapBuildTimeout)
+	defer cancel()
+	entries, err := h.buildSitemap(ctx)
+
+	c := &h.sitemap
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rebuilding = false
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to rebuild sitemap, keeping the previous one")
+		return
+	}
+	c.files, c.generatedAt = chunkSitemap(entries), time.Now()
+}
+
+// chunkSitemap splits entries into files of at most sitemapURLsPerFile. It
+// always returns at least one file so an empty site still gets a valid,
+// empty urlset.
+func chunkSitemap(entries []sitemapEntry) [][]sitemapEntry {
+	files := slices.Collect(slices.Chunk(entries, sitemapURLsPerFile))
+	if len(files) == 0 {
+		files = [][]sitemapEntry{{}}
+	}
+	return files
+}
+
+// buildSitemap lists every known user's profile followed by their brews,
+// newest first, up to sitemapMaxURLs. Blocked users and hidden records are
+// left out, and URLs use handles where one is known.
+func (h *Handler) buildSitemap(ctx context.Context) ([]sitemapEntry, error) {
+	if h.feedIndex == nil {
+		return nil, errors.New("feed index not configured")
+	}
+
+	dids, err := h.feedIndex.GetKnownDIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(dids)
+	if len(dids) > sitemapMaxURLs {
+		dids = dids[:sitemapMaxURLs]
+	}
+
+	var brewPath, brewNSID string
+	if h.app != nil {
+		if route, ok := h.app.EntityRouteByPath("brews"); ok {
+			if desc := h.app.DescriptorByType(route.Type); desc != nil {
+				brewPath, brewNSID = route.Path, desc.NSID
+			}
+		}
+	}
+	var brews []firehose.RecordKey
+	if brewNSID != "" && len(dids) < sitemapMaxURLs {
+		brews, err = h.feedIndex.ListRecordKeys(ctx, brewNSID, sitemapMaxURLs-len(dids))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cf := h.LoadContentFilter(ctx)
+	if cf == nil {
+		cf, _ = moderation.LoadFilter(ctx, nil)
+	}
+
+	authors := slices.Clone(dids)
+	for _, b := range brews {
+		authors = append(authors, b.DID)
+	}
+	profiles := h.feedIndex.GetProfiles(ctx, authors)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	actor := func(did string) string {
+		if p := profiles[did]; p != nil && p.Handle != "" && p.Handle != "handle.invalid" {
+			return p.Handle
+		}
+		return did
+	}
+
+	entries := make([]sitemapEntry, 0, len(dids)+len(brews))
+	for _, did := range dids {
+		if cf.IsBlocked(did) {
+			continue
+		}
+		entries = append(entries, sitemapEntry{Path: "/profile/" + actor(did)})
+	}
+	for _, b := range brews {
+		if cf.ShouldHide(atp.BuildATURI(b.DID, brewNSID, b.RKey), b.DID) {
+			continue
+		}
+		entries = append(entries, sitemapEntry{
+			Path:    "/" + brewPath + "/" + actor(b.DID) + "/" + b.RKey,
+			LastMod: b.IndexedAt,
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tangled.org/arabica.social/arabica/internal/atplatform/domain"
+	"tangled.org/arabica.social/arabica/internal/atproto"
+	"tangled.org/arabica.social/arabica/internal/entities"
+	"tangled.org/arabica.social/arabica/internal/firehose"
+	"tangled.org/arabica.social/arabica/internal/lexicons"
+	"tangled.org/arabica.social/arabica/internal/moderation"
+	moderationsqlite "tangled.org/arabica.social/arabica/internal/moderation/sqlite"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSitemap(t *testing.T) {
+	idx, err := firehose.NewFeedIndex(t.TempDir()+"/test.db", time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() { idx.Close() })
+
+	ctx := context.Background()
+	const brewNSID = "social.arabica.alpha.brew"
+	addBrew := func(did, handle, rkey string) {
+		idx.StoreProfile(ctx, did, &atproto.Profile{DID: did, Handle: handle})
+		record := fmt.Appendf(nil, `{"$type":%q,"rating":7,"createdAt":"2026-05-01T00:00:00Z"}`, brewNSID)
+		require.NoError(t, idx.UpsertRecord(ctx, did, brewNSID, rkey, "cid-"+rkey, record, time.Now().Unix()))
+	}
+	addBrew("did:plc:alice", "alice.test", "a1")
+	addBrew("did:plc:alice", "alice.test", "a2")
+	addBrew("did:plc:spammer", "spam.test", "s1")
+
+	modStore := moderationsqlite.NewModerationStore(idx.DB())
+	require.NoError(t, modStore.BlacklistUser(ctx, moderation.BlacklistedUser{DID: "did:plc:spammer", BlacklistedAt: time.Now()}))
+	require.NoError(t, modStore.HideRecord(ctx, moderation.HiddenRecord{ATURI: "at://did:plc:alice/" + brewNSID + "/a2", HiddenAt: time.Now()}))
+
+	h := &Handler{config: Config{PublicURL: "https://arabica.test"}}
+	h.SetFeedIndex(idx)
+	h.SetModeration(nil, modStore)
+	h.SetApp(&domain.App{
+		Name:         "arabica",
+		Descriptors:  []*entities.Descriptor{{Type: lexicons.RecordTypeBrew, NSID: brewNSID}},
+		EntityRoutes: []domain.EntityRoute{{Type: lexicons.RecordTypeBrew, Path: "brews", Noun: "brew"}},
+	})
+
+	rec := httptest.NewRecorder()
+	h.HandleSitemap(rec, httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	var set sitemapURLSet
+	require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &set))
+	var locs []string
+	for _, u := range set.URLs {
+		locs = append(locs, u.Loc)
+	}
+	assert.Equal(t, []string{
+		"https://arabica.test/profile/alice.test",
+		"https://arabica.test/brews/alice.test/a1",
+	}, locs)
+	assert.NotEmpty(t, set.URLs[1].LastMod)
+}
+
+func TestChunkSitemap(t *testing.T) {
+	entries := make([]sitemapEntry, sitemapURLsPerFile+1)
+	files := chunkSitemap(entries)
+	require.Len(t, files, 2)
+	assert.Len(t, files[0], sitemapURLsPerFile)
+	assert.Len(t, files[1], 1)
+
+	assert.Len(t, chunkSitemap(nil), 1, "an empty site still gets one urlset")
+}
+
+func TestHandleSitemapFile(t *testing.T) {
+	h := &Handler{config: Config{PublicURL: "https://arabica.test"}}
+	h.sitemap.files = [][]sitemapEntry{
+		{{Path: "/profile/alice.test"}},
+		{{Path: "/brews/alice.test/a1"}},
+	}
+	h.sitemap.generatedAt = time.Now()
+
+	serve := func(file string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/sitemaps/"+file, nil)
+		req.SetPathValue("file", file)
+		rec := httptest.NewRecorder()
+		h.HandleSitemapFile(rec, req)
+		return rec
+	}
+
+	rec := serve("2.xml")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "<loc>https://arabica.test/brews/alice.test/a1</loc>")
+
+	for _, file := range []string{"0.xml", "3.xml", "index.xml"} {
+		assert.Equal(t, http.StatusNotFound, serve(file).Code, file)
+	}
+
+	index := httptest.NewRecorder()
+	h.HandleSitemap(index, httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+	assert.Contains(t, index.Body.String(), "<sitemapindex")
+	assert.Contains(t, index.Body.String(), "<loc>https://arabica.test/sitemaps/2.xml</loc>")
+}
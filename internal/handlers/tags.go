@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"tangled.org/arabica.social/arabica/internal/brewtags"
+	"tangled.org/arabica.social/arabica/internal/profileprefs"
+	"tangled.org/arabica.social/arabica/internal/web/pages"
+
+	"github.com/rs/zerolog/log"
+)
+
+// HandleTag renders the discovery page for one brew tag: every indexed brew
+// carrying it, newest first. The path goes through the same normalizer as
+// saved tags, so /tags/%23Washed and /tags/washed land on the same page.
+func (h *Handler) HandleTag(w http.ResponseWriter, r *http.Request) {
+	tag := brewtags.Normalize(r.PathValue("tag"))
+	if tag == "" || len([]rune(tag)) > maxSearchQueryLength {
+		http.Error(w, "Invalid tag", http.StatusBadRequest)
+		return
+	}
+
+	layoutData, viewerDID, isAuthenticated := h.LayoutDataFromRequest(r, "#"+tag)
+	props := pages.TagProps{
+		Tag: tag,
+		QueryState: pages.FeedQueryState{
+			IsAuthenticated: isAuthenticated,
+			FeedViews:       h.feedViews,
+			BrandName:       h.brand.DisplayName,
+			UserPreferences: profileprefs.DefaultUserPreferences(),
+		},
+	}
+	if h.app != nil {
+		props.QueryState.Descriptors = h.app.Descriptors
+	}
+
+	if h.feedIndex != nil {
+		items, err := h.feedIndex.GetBrewsByTag(r.Context(), tag, 0)
+		if err != nil {
+			log.Error().Err(err).Str("tag", tag).Msg("Failed to list brews by tag")
+			http.Error(w, "Failed to load tag", http.StatusInternalServerError)
+			return
+		}
+		if isAuthenticated {
+			h.populateFeedViewerState(r.Context(), viewerDID, items)
+			props.QueryState.UserPreferences = h.feedIndex.GetUserPreferences(r.Context(), viewerDID)
+		}
+		props.Items = items
+		props.ModCtx = h.buildModerationContext(r.Context(), viewerDID, items)
+	}
+
+	if err := pages.Tag(layoutData, props).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		log.Error().Err(err).Msg("Failed to render tag page")
+	}
+}
@@ -43,6 +43,11 @@ var (
 		Name: "arabica_pds_requests_total",
 		Help: "Total number of PDS requests",
 	}, []string{"method", "collection"})
+
+	PDSRetriesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "arabica_pds_retries_total",
+		Help: "Total number of public PDS reads retried after a transient failure",
+	})
 )
 
 // Witness cache metrics
@@ -69,6 +74,41 @@ var (
 		Name: "arabica_feed_cache_misses_total",
 		Help: "Total number of feed cache misses",
 	})
+
+	FeedHTMLCacheHitsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "arabica_feed_html_cache_hits_total",
+		Help: "Total number of rendered public feed cache hits",
+	})
+
+	FeedHTMLCacheMissesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "arabica_feed_html_cache_misses_total",
+		Help: "Total number of rendered public feed cache misses",
+	})
+)
+
+// Feed index metrics
+var (
+	FeedQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "arabica_feed_query_duration_seconds",
+		Help:    "Feed index query duration in seconds",
+		Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+	}, []string{"sort"})
+
+	IndexUpsertDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "arabica_index_upsert_duration_seconds",
+		Help:    "Feed index record upsert duration in seconds, including explore reindexing",
+		Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+	}, []string{"collection"})
+
+	ProfileCacheHitsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "arabica_profile_cache_hits_total",
+		Help: "Total profile lookups served from the memory or SQLite cache",
+	})
+
+	ProfileCacheMissesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "arabica_profile_cache_misses_total",
+		Help: "Total profile lookups that had to fetch from the public API",
+	})
 )
 
 // Business metrics (gauges updated periodically by collector)
@@ -168,13 +208,20 @@ func NormalizePath(path string) string {
 	switch segments[0] {
 	case "brews":
 		if len(segments) == 2 {
-			if segments[1] == "new" || segments[1] == "export" {
+			if segments[1] == "new" || segments[1] == "export" || segments[1] == "export.csv" || segments[1] == "import" {
 				return path
 			}
 			return "/brews/:id"
 		}
-		if len(segments) == 3 && segments[2] == "edit" {
-			return "/brews/:id/edit"
+		if len(segments) == 3 && (segments[2] == "edit" || segments[2] == "clone" || segments[2] == "recipe.txt") {
+			return "/brews/:id/" + segments[2]
+		}
+	case "b":
+		if len(segments) == 3 {
+			return "/b/:actor/:id"
+		}
+		if len(segments) == 4 && segments[3] == "og-image" {
+			return "/b/:actor/:id/og-image"
 		}
 	case "beans", "roasters", "grinders", "brewers":
 		if len(segments) == 2 {
@@ -26,8 +26,14 @@ func TestNormalizePath(t *testing.T) {
 		// Brews with IDs
 		{"/brews/abc123", "/brews/:id"},
 		{"/brews/abc123/edit", "/brews/:id/edit"},
+		{"/brews/abc123/clone", "/brews/:id/clone"},
+		{"/brews/abc123/recipe.txt", "/brews/:id/recipe.txt"},
 		{"/brews/new", "/brews/new"},
 		{"/brews/export", "/brews/export"},
+		{"/brews/export.csv", "/brews/export.csv"},
+		{"/brews/import", "/brews/import"},
+		{"/b/alice.test/abc123", "/b/:actor/:id"},
+		{"/b/did:plc:alice/abc123/og-image", "/b/:actor/:id/og-image"},
 
 		// Entity record views
 		{"/beans/abc123", "/beans/:id"},
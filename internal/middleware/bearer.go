@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+)
+
+type bearerAuthKeyType struct{}
+
+var bearerAuthKey = bearerAuthKeyType{}
+
+// IsBearerAuthenticated reports whether BearerAuthMiddleware authenticated
+// the request from its token. A bearer header alone proves nothing: it is
+// only trusted once the token has been looked up.
+func IsBearerAuthenticated(ctx context.Context) bool {
+	v, _ := ctx.Value(bearerAuthKey).(bool)
+	return v
+}
+
+func contextWithBearerAuth(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bearerAuthKey, true)
+}
+
+// BearerLookup resolves a bearer token to the DID and session ID it was
+// issued for. ok is false for unknown or revoked tokens.
+type BearerLookup func(ctx context.Context, token string) (did, sessionID string, ok bool)
+
+// BearerAuthMiddleware authenticates requests carrying an
+// "Authorization: Bearer" header, for scripts using app-password sessions.
+// Requests without the header pass through untouched so cookie auth still
+// applies; a header with an unknown token is rejected outright rather than
+// silently treated as anonymous. Place it inside the cookie auth middleware
+// so a bearer token wins when both are present.
+func BearerAuthMiddleware(lookup BearerLookup, onAuth func(did string)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !found {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			did, sessionID, ok := lookup(r.Context(), strings.TrimSpace(token))
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				http.Error(w, "Invalid or revoked token", http.StatusUnauthorized)
+				return
+			}
+			if onAuth != nil {
+				onAuth(did)
+			}
+
+			ctx := atpmiddleware.ContextWithAuth(contextWithBearerAuth(r.Context()), did, sessionID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
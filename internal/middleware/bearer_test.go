@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+)
+
+func TestBearerAuthMiddleware(t *testing.T) {
+	lookup := func(_ context.Context, token string) (string, string, bool) {
+		if token == "good-token" {
+			return "did:plc:alice", "apppw-abc", true
+		}
+		return "", "", false
+	}
+
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantDID    string
+		wantAuthed []string
+	}{
+		{"no header passes through", "", http.StatusOK, "", nil},
+		{"other scheme passes through", "Basic dXNlcjpwYXNz", http.StatusOK, "", nil},
+		{"valid token authenticates", "Bearer good-token", http.StatusOK, "did:plc:alice", []string{"did:plc:alice"}},
+		{"unknown token is rejected", "Bearer nope", http.StatusUnauthorized, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var authed []string
+			var gotDID string
+			var gotBearer bool
+			handler := BearerAuthMiddleware(lookup, func(did string) { authed = append(authed, did) })(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					gotDID, _ = atpmiddleware.GetDID(r.Context())
+					gotBearer = IsBearerAuthenticated(r.Context())
+					w.WriteHeader(http.StatusOK)
+				}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantDID, gotDID)
+			assert.Equal(t, tt.wantAuthed, authed)
+			assert.Equal(t, tt.wantDID != "", gotBearer, "only a verified token marks the request")
+		})
+	}
+}
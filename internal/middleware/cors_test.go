@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	const client = "https://client.example"
+
+	tests := []struct {
+		name        string
+		origins     []string
+		method      string
+		path        string
+		origin      string
+		preflight   bool
+		wantStatus  int
+		wantAllow   string
+		wantCalled  bool
+		wantCookies bool
+	}{
+		{name: "unset allows nothing", method: http.MethodGet, path: "/api/feed", origin: client, wantStatus: http.StatusOK, wantCalled: true, wantCookies: true},
+		{name: "allowed origin gets headers without cookies", origins: []string{client}, method: http.MethodGet, path: "/api/feed", origin: client, wantStatus: http.StatusOK, wantAllow: client, wantCalled: true},
+		{name: "unknown origin gets nothing", origins: []string{client}, method: http.MethodGet, path: "/api/feed", origin: "https://evil.example", wantStatus: http.StatusOK, wantCalled: true, wantCookies: true},
+		{name: "pages are not covered", origins: []string{client}, method: http.MethodGet, path: "/brews", origin: client, wantStatus: http.StatusOK, wantCalled: true, wantCookies: true},
+		{name: "same origin is untouched", origins: []string{client}, method: http.MethodPost, path: "/api/likes", origin: "http://example.com", wantStatus: http.StatusOK, wantCalled: true, wantCookies: true},
+		{name: "preflight is answered", origins: []string{client}, method: http.MethodOptions, path: "/api/likes", origin: client, preflight: true, wantStatus: http.StatusNoContent, wantAllow: client},
+		{name: "preflight from unknown origin falls through", origins: []string{client}, method: http.MethodOptions, path: "/api/likes", origin: "https://evil.example", preflight: true, wantStatus: http.StatusOK, wantCalled: true, wantCookies: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called, gotCookies bool
+			handler := CORSMiddleware(tt.origins)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				gotCookies = r.Header.Get("Cookie") != ""
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Cookie", "session=abc")
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantAllow, rec.Header().Get("Access-Control-Allow-Origin"))
+			assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+			assert.Equal(t, tt.wantCalled, called)
+			assert.Equal(t, tt.wantCookies, gotCookies)
+			if tt.preflight && tt.wantAllow != "" {
+				assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+			}
+		})
+	}
+}
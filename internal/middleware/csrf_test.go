@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	key := []byte("test-key")
+	valid := csrfToken(key, "sess-1")
+
+	tests := []struct {
+		name        string
+		method      string
+		session     string
+		header      string
+		form        url.Values
+		contentType string
+		bearer      bool
+		authHeader  string
+		wantStatus  int
+		wantToken   string
+	}{
+		{name: "anonymous post passes", method: http.MethodPost, wantStatus: http.StatusOK},
+		{name: "get exposes token", method: http.MethodGet, session: "sess-1", wantStatus: http.StatusOK, wantToken: valid},
+		{name: "post without token is rejected", method: http.MethodPost, session: "sess-1", wantStatus: http.StatusForbidden},
+		{name: "post with header token passes", method: http.MethodPost, session: "sess-1", header: valid, wantStatus: http.StatusOK, wantToken: valid},
+		{name: "delete with header token passes", method: http.MethodDelete, session: "sess-1", header: valid, wantStatus: http.StatusOK, wantToken: valid},
+		{name: "token from another session is rejected", method: http.MethodPost, session: "sess-2", header: valid, wantStatus: http.StatusForbidden},
+		{
+			name: "urlencoded form field passes", method: http.MethodPost, session: "sess-1",
+			form: url.Values{CSRFFormField: {valid}}, contentType: "application/x-www-form-urlencoded",
+			wantStatus: http.StatusOK, wantToken: valid,
+		},
+		{
+			name: "multipart needs the header", method: http.MethodPost, session: "sess-1",
+			form: url.Values{CSRFFormField: {valid}}, contentType: "multipart/form-data; boundary=x",
+			wantStatus: http.StatusForbidden,
+		},
+		{name: "bearer request passes", method: http.MethodPost, session: "apppw-1", bearer: true, authHeader: "Bearer tok", wantStatus: http.StatusOK},
+		{name: "unverified bearer header is still checked", method: http.MethodPost, session: "sess-1", authHeader: "Bearer tok", wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotToken string
+			handler := CSRFMiddleware(key)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotToken = CSRFTokenFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(tt.method, "/", strings.NewReader(tt.form.Encode()))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.header != "" {
+				req.Header.Set(CSRFHeader, tt.header)
+			}
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			ctx := context.Background()
+			if tt.bearer {
+				ctx = contextWithBearerAuth(ctx)
+			}
+			if tt.session != "" {
+				req = req.WithContext(atpmiddleware.ContextWithAuth(ctx, "did:plc:alice", tt.session))
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantToken, gotToken)
+		})
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
 )
 
 type cspNonceKeyType struct{}
@@ -233,6 +236,9 @@ func RequireHTMXMiddleware(next http.Handler) http.Handler {
 const (
 	MaxJSONBodySize = 1 << 20 // 1 MB for JSON requests
 	MaxFormBodySize = 1 << 20 // 1 MB for form submissions
+	// Multipart forms carry image uploads (avatars, brew photos) capped at
+	// about 1 MB each, plus the surrounding form fields.
+	MaxMultipartBodySize = 2 << 20
 )
 
 // LimitBodyMiddleware limits request body size to prevent DoS
@@ -245,9 +251,10 @@ func LimitBodyMiddleware(next http.Handler) http.Handler {
 			switch {
 			case strings.HasPrefix(contentType, "application/json"):
 				maxSize = MaxJSONBodySize
-			case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"),
-				strings.HasPrefix(contentType, "multipart/form-data"):
+			case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
 				maxSize = MaxFormBodySize
+			case strings.HasPrefix(contentType, "multipart/form-data"):
+				maxSize = MaxMultipartBodySize
 			default:
 				maxSize = MaxJSONBodySize // Default limit
 			}
@@ -258,3 +265,25 @@ func LimitBodyMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// DefaultCreateRateLimit is how many records a user may create per minute
+// when no override is configured.
+const DefaultCreateRateLimit = 60
+
+// UserRateLimitMiddleware limits authenticated requests per DID rather than
+// per IP, so one account can't flood its PDS (and our index) through record
+// creation. Anonymous requests pass through; the handlers behind it reject
+// them anyway.
+func UserRateLimitMiddleware(limiter *RateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			did, ok := atpmiddleware.GetDID(r.Context())
+			if ok && !limiter.Allow(did) {
+				w.Header().Set("Retry-After", strconv.Itoa(int(limiter.window.Seconds())))
+				http.Error(w, "You're creating records too quickly. Please wait a minute and try again.", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	atpmiddleware "tangled.org/pdewey.com/atp/middleware"
 )
 
 func TestSecurityHeadersMiddleware(t *testing.T) {
@@ -224,6 +225,46 @@ func TestRateLimitMiddleware(t *testing.T) {
 	})
 }
 
+func TestUserRateLimitMiddleware(t *testing.T) {
+	limiter := &RateLimiter{visitors: make(map[string]*visitor), rate: 2, window: time.Minute, cleanup: 2 * time.Minute}
+	wrapped := UserRateLimitMiddleware(limiter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	tests := []struct {
+		name       string
+		did        string
+		remoteAddr string
+		wantStatus int
+	}{
+		{"first create", "did:plc:alice", "1.1.1.1:1234", http.StatusCreated},
+		{"second create", "did:plc:alice", "1.1.1.1:1234", http.StatusCreated},
+		{"over the limit", "did:plc:alice", "1.1.1.1:1234", http.StatusTooManyRequests},
+		{"new IP doesn't reset the bucket", "did:plc:alice", "9.9.9.9:1234", http.StatusTooManyRequests},
+		{"other users have their own bucket", "did:plc:bob", "1.1.1.1:1234", http.StatusCreated},
+		{"anonymous requests pass through", "", "1.1.1.1:1234", http.StatusCreated},
+		{"anonymous requests are not counted", "", "1.1.1.1:1234", http.StatusCreated},
+		{"anonymous requests stay unlimited", "", "1.1.1.1:1234", http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/beans", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.did != "" {
+				req = req.WithContext(atpmiddleware.ContextWithAuth(req.Context(), tt.did, "sess"))
+			}
+			rec := httptest.NewRecorder()
+			wrapped.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusTooManyRequests {
+				assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+				assert.Contains(t, rec.Body.String(), "too quickly")
+			}
+		})
+	}
+}
+
 func TestRequireHTMXMiddleware(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"net/http"
+
+	"tangled.org/arabica.social/arabica/internal/web/bff"
+)
+
+// ViewerTimezoneMiddleware puts the viewer's time zone, from the
+// bff.TimezoneCookie, on the request context so templates can render
+// absolute times in it.
+func ViewerTimezoneMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		loc := bff.ViewerLocationFromRequest(r)
+		next.ServeHTTP(w, r.WithContext(bff.WithViewerLocation(r.Context(), loc)))
+	})
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tangled.org/arabica.social/arabica/internal/web/bff"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewerTimezoneMiddleware(t *testing.T) {
+	var got string
+	handler := ViewerTimezoneMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = bff.ViewerLocation(r.Context()).String()
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: bff.TimezoneCookie, Value: "Europe/Berlin"})
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "Europe/Berlin", got)
+}
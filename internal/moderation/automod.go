@@ -0,0 +1,75 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidAutomodSettings is returned by AutomodSettings.Validate when a
+// value is zero or negative.
+var ErrInvalidAutomodSettings = errors.New("invalid automod settings")
+
+// AutomodSettings tunes report rate limiting and automatic hiding. Defaults
+// come from DefaultAutomodSettings, can be overridden per deployment through
+// the environment, and admins can override them again from the dashboard.
+type AutomodSettings struct {
+	// AutoHideThreshold is the number of reports on a single record before
+	// it is hidden automatically.
+	AutoHideThreshold int `json:"auto_hide_threshold"`
+	// AutoHideUserThreshold is the number of reports across a user's records
+	// (since their last reset) before newly reported records are hidden.
+	AutoHideUserThreshold int `json:"auto_hide_user_threshold"`
+	// ReportRateLimitPerHour caps how many reports one user can submit per hour.
+	ReportRateLimitPerHour int `json:"report_rate_limit_per_hour"`
+	// MaxReportReasonLength truncates report reasons, in bytes.
+	MaxReportReasonLength int `json:"max_report_reason_length"`
+}
+
+// DefaultAutomodSettings returns the built-in thresholds.
+func DefaultAutomodSettings() AutomodSettings {
+	return AutomodSettings{
+		AutoHideThreshold:      3,
+		AutoHideUserThreshold:  5,
+		ReportRateLimitPerHour: 10,
+		MaxReportReasonLength:  500,
+	}
+}
+
+// WithDefaults fills any unset (zero or negative) value from
+// DefaultAutomodSettings.
+func (s AutomodSettings) WithDefaults() AutomodSettings {
+	d := DefaultAutomodSettings()
+	if s.AutoHideThreshold <= 0 {
+		s.AutoHideThreshold = d.AutoHideThreshold
+	}
+	if s.AutoHideUserThreshold <= 0 {
+		s.AutoHideUserThreshold = d.AutoHideUserThreshold
+	}
+	if s.ReportRateLimitPerHour <= 0 {
+		s.ReportRateLimitPerHour = d.ReportRateLimitPerHour
+	}
+	if s.MaxReportReasonLength <= 0 {
+		s.MaxReportReasonLength = d.MaxReportReasonLength
+	}
+	return s
+}
+
+// Validate rejects zero or negative values. A zero threshold would hide
+// every reported record on the first report, and a zero rate limit would
+// block reporting entirely.
+func (s AutomodSettings) Validate() error {
+	for _, f := range []struct {
+		name  string
+		value int
+	}{
+		{"auto-hide threshold", s.AutoHideThreshold},
+		{"user auto-hide threshold", s.AutoHideUserThreshold},
+		{"report rate limit", s.ReportRateLimitPerHour},
+		{"report reason length", s.MaxReportReasonLength},
+	} {
+		if f.value <= 0 {
+			return fmt.Errorf("%w: %s must be at least 1", ErrInvalidAutomodSettings, f.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package moderation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutomodSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*AutomodSettings)
+		wantErr bool
+	}{
+		{"defaults", func(*AutomodSettings) {}, false},
+		{"zero record threshold", func(s *AutomodSettings) { s.AutoHideThreshold = 0 }, true},
+		{"zero user threshold", func(s *AutomodSettings) { s.AutoHideUserThreshold = 0 }, true},
+		{"negative rate limit", func(s *AutomodSettings) { s.ReportRateLimitPerHour = -1 }, true},
+		{"zero reason length", func(s *AutomodSettings) { s.MaxReportReasonLength = 0 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := DefaultAutomodSettings()
+			tt.modify(&s)
+			err := s.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAutomodSettings)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAutomodSettings_WithDefaults(t *testing.T) {
+	got := AutomodSettings{AutoHideThreshold: 7}.WithDefaults()
+	want := DefaultAutomodSettings()
+	want.AutoHideThreshold = 7
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,29 @@
+package moderation
+
+import "context"
+
+// LabelHide is the label value emitted for hidden records. It is one of the
+// global atproto label values, so any client subscribed to the labeler
+// hides the record without further configuration.
+const LabelHide = "!hide"
+
+// LabelEvent applies a label to a record, or removes one emitted earlier.
+type LabelEvent struct {
+	URI    string // AT-URI of the labelled record
+	CID    string // CID of the version that was moderated
+	Val    string // Label value, e.g. LabelHide
+	Negate bool   // true to remove the label instead of applying it
+	Reason string
+}
+
+// Labeler publishes moderation decisions as atproto labels
+// (com.atproto.label) so other apps can respect them. Arabica's own
+// hidden-record table stays the source of truth; labels are a copy.
+type Labeler interface {
+	EmitLabel(ctx context.Context, ev LabelEvent) error
+}
+
+// NopLabeler is the Labeler used when no labeler service is configured.
+type NopLabeler struct{}
+
+func (NopLabeler) EmitLabel(context.Context, LabelEvent) error { return nil }
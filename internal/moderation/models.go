@@ -60,6 +60,11 @@ type ModeratorUser struct {
 	Handle string   `json:"handle,omitempty"`
 	Role   RoleName `json:"role"`
 	Note   string   `json:"note,omitempty"`
+
+	// AddedBy is the admin DID that granted the role at runtime. Empty for
+	// moderators listed in the config file.
+	AddedBy string    `json:"added_by,omitempty"`
+	AddedAt time.Time `json:"added_at,omitzero"`
 }
 
 // Config represents the moderation configuration loaded from JSON
@@ -111,6 +116,15 @@ type HiddenRecord struct {
 	AutoHidden bool      `json:"auto_hidden"` // true if hidden by automod
 }
 
+// PinnedRecord is a record an admin has featured at the top of the public
+// feed. Pinning does not override hides or blacklists.
+type PinnedRecord struct {
+	ATURI    string    `json:"at_uri"`
+	PinnedAt time.Time `json:"pinned_at"`
+	PinnedBy string    `json:"pinned_by"` // DID of admin
+	Note     string    `json:"note,omitempty"`
+}
+
 // BlacklistedUser represents a user who has been blacklisted
 type BlacklistedUser struct {
 	DID           string    `json:"did"`
@@ -128,17 +142,94 @@ const (
 	ReportStatusActioned  ReportStatus = "actioned"
 )
 
+// ReportCategory is the reporter's choice of what kind of problem the
+// content has. The free-text reason sits alongside it.
+type ReportCategory string
+
+const (
+	ReportCategorySpam       ReportCategory = "spam"
+	ReportCategoryHarassment ReportCategory = "harassment"
+	ReportCategoryOffTopic   ReportCategory = "off_topic"
+	ReportCategoryIllegal    ReportCategory = "illegal"
+	ReportCategoryOther      ReportCategory = "other"
+)
+
+// ReportCategories returns every category in the order the report form
+// lists them.
+func ReportCategories() []ReportCategory {
+	return []ReportCategory{
+		ReportCategorySpam,
+		ReportCategoryHarassment,
+		ReportCategoryOffTopic,
+		ReportCategoryIllegal,
+		ReportCategoryOther,
+	}
+}
+
+// IsValid reports whether c is one of the known categories.
+func (c ReportCategory) IsValid() bool {
+	return slices.Contains(ReportCategories(), c)
+}
+
+// ParseReportCategory maps form input to a category. Anything unrecognised,
+// including the empty string sent by older clients, becomes "other".
+func ParseReportCategory(s string) ReportCategory {
+	if c := ReportCategory(s); c.IsValid() {
+		return c
+	}
+	return ReportCategoryOther
+}
+
+// Label returns the human-readable name shown in the report form and the
+// moderation queue.
+func (c ReportCategory) Label() string {
+	switch c {
+	case ReportCategorySpam:
+		return "Spam"
+	case ReportCategoryHarassment:
+		return "Harassment"
+	case ReportCategoryOffTopic:
+		return "Off-topic"
+	case ReportCategoryIllegal:
+		return "Illegal content"
+	default:
+		return "Other"
+	}
+}
+
 // Report represents a user report on content
 type Report struct {
-	ID          string       `json:"id"`          // TID
-	SubjectURI  string       `json:"subject_uri"` // AT-URI of reported content
-	SubjectDID  string       `json:"subject_did"` // DID of content owner
-	ReporterDID string       `json:"reporter_did"`
-	Reason      string       `json:"reason"`
-	CreatedAt   time.Time    `json:"created_at"`
-	Status      ReportStatus `json:"status"`
-	ResolvedBy  string       `json:"resolved_by,omitempty"`
-	ResolvedAt  *time.Time   `json:"resolved_at,omitempty"`
+	ID          string         `json:"id"`          // TID
+	SubjectURI  string         `json:"subject_uri"` // AT-URI of reported content
+	SubjectDID  string         `json:"subject_did"` // DID of content owner
+	ReporterDID string         `json:"reporter_did"`
+	Category    ReportCategory `json:"category"`
+	Reason      string         `json:"reason"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Status      ReportStatus   `json:"status"`
+	ResolvedBy  string         `json:"resolved_by,omitempty"`
+	ResolvedAt  *time.Time     `json:"resolved_at,omitempty"`
+}
+
+// AppealStatus represents the state of an appeal against a hidden record
+type AppealStatus string
+
+const (
+	AppealStatusPending  AppealStatus = "pending"
+	AppealStatusApproved AppealStatus = "approved"
+	AppealStatusRejected AppealStatus = "rejected"
+)
+
+// Appeal is a record owner's request to have a hidden record restored.
+// Each URI can be appealed once.
+type Appeal struct {
+	URI        string       `json:"uri"` // AT-URI of the hidden record
+	DID        string       `json:"did"` // DID of the record owner
+	Text       string       `json:"text"`
+	CreatedAt  time.Time    `json:"created_at"`
+	Status     AppealStatus `json:"status"`
+	ResolvedBy string       `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time   `json:"resolved_at,omitempty"`
 }
 
 // AuditAction represents a type of moderation action
@@ -147,6 +238,8 @@ type AuditAction string
 const (
 	AuditActionHideRecord         AuditAction = "hide_record"
 	AuditActionUnhideRecord       AuditAction = "unhide_record"
+	AuditActionPinRecord          AuditAction = "pin_record"
+	AuditActionUnpinRecord        AuditAction = "unpin_record"
 	AuditActionBlacklistUser      AuditAction = "blacklist_user"
 	AuditActionUnblacklistUser    AuditAction = "unblacklist_user"
 	AuditActionDismissReport      AuditAction = "dismiss_report"
@@ -156,6 +249,13 @@ const (
 	AuditActionCreateInvite       AuditAction = "create_invite"
 	AuditActionAddLabel           AuditAction = "add_label"
 	AuditActionRemoveLabel        AuditAction = "remove_label"
+	AuditActionAddModerator       AuditAction = "add_moderator"
+	AuditActionRemoveModerator    AuditAction = "remove_moderator"
+	AuditActionApproveAppeal      AuditAction = "approve_appeal"
+	AuditActionRejectAppeal       AuditAction = "reject_appeal"
+	AuditActionUpdateAutomod      AuditAction = "update_automod"
+	AuditActionAddWebhook         AuditAction = "add_webhook"
+	AuditActionRemoveWebhook      AuditAction = "remove_webhook"
 )
 
 // AuditEntry represents a logged moderation action
@@ -0,0 +1,60 @@
+// Package ozone emits moderation labels through an Ozone labeler service.
+package ozone
+
+import (
+	"context"
+	"fmt"
+
+	"tangled.org/arabica.social/arabica/internal/moderation"
+
+	comatproto "github.com/bluesky-social/indigo/api/atproto"
+	toolsozone "github.com/bluesky-social/indigo/api/ozone"
+	"github.com/bluesky-social/indigo/atproto/atclient"
+)
+
+// Labeler emits labels as tools.ozone.moderation.emitEvent label events.
+// Ozone signs them with the labeler's key and serves them to subscribers
+// over com.atproto.label.subscribeLabels.
+type Labeler struct {
+	client    *atclient.APIClient
+	createdBy string
+}
+
+var _ moderation.Labeler = (*Labeler)(nil)
+
+// NewLabeler returns a Labeler for the Ozone instance at host. It
+// authenticates with the instance's admin password, and events are
+// attributed to labelerDID.
+func NewLabeler(host, adminPassword, labelerDID string) *Labeler {
+	return &Labeler{
+		client:    atclient.NewAdminClient(host, adminPassword),
+		createdBy: labelerDID,
+	}
+}
+
+func (l *Labeler) EmitLabel(ctx context.Context, ev moderation.LabelEvent) error {
+	label := &toolsozone.ModerationDefs_ModEventLabel{
+		CreateLabelVals: []string{},
+		NegateLabelVals: []string{},
+	}
+	if ev.Negate {
+		label.NegateLabelVals = append(label.NegateLabelVals, ev.Val)
+	} else {
+		label.CreateLabelVals = append(label.CreateLabelVals, ev.Val)
+	}
+	if ev.Reason != "" {
+		label.Comment = &ev.Reason
+	}
+
+	_, err := toolsozone.ModerationEmitEvent(ctx, l.client, &toolsozone.ModerationEmitEvent_Input{
+		CreatedBy: l.createdBy,
+		Event:     &toolsozone.ModerationEmitEvent_Input_Event{ModerationDefs_ModEventLabel: label},
+		Subject: &toolsozone.ModerationEmitEvent_Input_Subject{
+			RepoStrongRef: &comatproto.RepoStrongRef{Uri: ev.URI, Cid: ev.CID},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("emit %s label for %s: %w", ev.Val, ev.URI, err)
+	}
+	return nil
+}
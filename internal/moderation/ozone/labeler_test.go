@@ -0,0 +1,81 @@
+package ozone
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tangled.org/arabica.social/arabica/internal/moderation"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelerEmitLabel(t *testing.T) {
+	tests := []struct {
+		name       string
+		ev         moderation.LabelEvent
+		wantCreate []string
+		wantNegate []string
+		wantNote   any
+	}{
+		{
+			name:       "hide",
+			ev:         moderation.LabelEvent{URI: "at://did:plc:a/social.arabica.alpha.brew/1", CID: "bafy1", Val: moderation.LabelHide, Reason: "spam"},
+			wantCreate: []string{"!hide"},
+			wantNegate: []string{},
+			wantNote:   "spam",
+		},
+		{
+			name:       "unhide",
+			ev:         moderation.LabelEvent{URI: "at://did:plc:a/social.arabica.alpha.brew/1", CID: "bafy1", Val: moderation.LabelHide, Negate: true},
+			wantCreate: []string{},
+			wantNegate: []string{"!hide"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]any
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/xrpc/tools.ozone.moderation.emitEvent", r.URL.Path)
+				user, pass, ok := r.BasicAuth()
+				assert.True(t, ok)
+				assert.Equal(t, "admin", user)
+				assert.Equal(t, "secret", pass)
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"id":1}`))
+			}))
+			defer srv.Close()
+
+			l := NewLabeler(srv.URL, "secret", "did:plc:labeler")
+			require.NoError(t, l.EmitLabel(context.Background(), tt.ev))
+
+			assert.Equal(t, "did:plc:labeler", body["createdBy"])
+			event := body["event"].(map[string]any)
+			assert.Equal(t, "tools.ozone.moderation.defs#modEventLabel", event["$type"])
+			assert.ElementsMatch(t, tt.wantCreate, event["createLabelVals"])
+			assert.ElementsMatch(t, tt.wantNegate, event["negateLabelVals"])
+			assert.Equal(t, tt.wantNote, event["comment"])
+			subject := body["subject"].(map[string]any)
+			assert.Equal(t, "com.atproto.repo.strongRef", subject["$type"])
+			assert.Equal(t, tt.ev.URI, subject["uri"])
+			assert.Equal(t, tt.ev.CID, subject["cid"])
+		})
+	}
+}
+
+func TestLabelerEmitLabel_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"AuthRequired","message":"bad password"}`))
+	}))
+	defer srv.Close()
+
+	l := NewLabeler(srv.URL, "wrong", "did:plc:labeler")
+	err := l.EmitLabel(context.Background(), moderation.LabelEvent{URI: "at://did:plc:a/social.arabica.alpha.brew/1", CID: "bafy1", Val: moderation.LabelHide})
+	assert.ErrorContains(t, err, "emit !hide label")
+}
@@ -1,7 +1,9 @@
 package moderation
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -9,12 +11,36 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+var (
+	// ErrUnknownRole is returned when granting a role the config doesn't define.
+	ErrUnknownRole = errors.New("unknown moderation role")
+	// ErrConfigModerator is returned when removing a moderator that comes from
+	// the config file; those can only be changed by editing the file.
+	ErrConfigModerator = errors.New("moderator is defined in the config file")
+	// ErrNoModeratorStore is returned by runtime role changes when no store
+	// has been attached with SetStore.
+	ErrNoModeratorStore = errors.New("moderator store not configured")
+)
+
+// ModeratorStore persists moderators granted at runtime. Roles, and so the
+// permissions behind them, still come from the config file.
+type ModeratorStore interface {
+	ListModerators(ctx context.Context) ([]ModeratorUser, error)
+	SaveModerator(ctx context.Context, user ModeratorUser) error
+	DeleteModerator(ctx context.Context, did string) error
+}
+
 // Service provides moderation functionality with role-based access control
 type Service struct {
 	mu         sync.RWMutex
 	config     *Config
 	configPath string
 
+	// Runtime-granted moderators, merged into the lookup maps after the
+	// config users so the file always wins for a given DID.
+	store  ModeratorStore
+	stored []ModeratorUser
+
 	// Quick lookup maps built from config
 	userRoles map[string]*Role          // DID -> Role
 	userInfos map[string]*ModeratorUser // DID -> ModeratorUser
@@ -95,6 +121,107 @@ func (s *Service) rebuildLookupMaps() {
 			s.userInfos[user.DID] = user
 		}
 	}
+	for i := range s.stored {
+		user := &s.stored[i]
+		if _, fromConfig := s.userInfos[user.DID]; fromConfig {
+			continue
+		}
+		if role, ok := s.config.Roles[user.Role]; ok {
+			s.userRoles[user.DID] = role
+			s.userInfos[user.DID] = user
+		}
+	}
+}
+
+// SetStore attaches the store holding runtime-granted moderators and loads
+// them. Stored moderators whose role has since been removed from the config
+// are kept in the store but grant nothing.
+func (s *Service) SetStore(ctx context.Context, store ModeratorStore) error {
+	users, err := store.ListModerators(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load stored moderators: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.store = store
+	s.stored = users
+	s.rebuildLookupMaps()
+	return nil
+}
+
+// AddModerator grants user.Role to user.DID and persists it. Granting a new
+// role to an existing runtime moderator replaces the old one.
+func (s *Service) AddModerator(ctx context.Context, user ModeratorUser) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.store == nil {
+		return ErrNoModeratorStore
+	}
+	if s.config == nil || s.config.Roles[user.Role] == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownRole, user.Role)
+	}
+	if s.isConfigUser(user.DID) {
+		return ErrConfigModerator
+	}
+
+	if err := s.store.SaveModerator(ctx, user); err != nil {
+		return fmt.Errorf("failed to save moderator: %w", err)
+	}
+
+	stored := make([]ModeratorUser, 0, len(s.stored)+1)
+	for _, u := range s.stored {
+		if u.DID != user.DID {
+			stored = append(stored, u)
+		}
+	}
+	s.stored = append(stored, user)
+	s.rebuildLookupMaps()
+	return nil
+}
+
+// RemoveModerator revokes a runtime-granted role. Removing a DID that holds
+// no runtime role is not an error.
+func (s *Service) RemoveModerator(ctx context.Context, did string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.store == nil {
+		return ErrNoModeratorStore
+	}
+	if s.isConfigUser(did) {
+		return ErrConfigModerator
+	}
+
+	if err := s.store.DeleteModerator(ctx, did); err != nil {
+		return fmt.Errorf("failed to delete moderator: %w", err)
+	}
+
+	stored := s.stored[:0:0]
+	for _, u := range s.stored {
+		if u.DID != did {
+			stored = append(stored, u)
+		}
+	}
+	s.stored = stored
+	s.rebuildLookupMaps()
+	return nil
+}
+
+// isConfigUser reports whether did is listed in the config file.
+// Caller must hold the lock.
+func (s *Service) isConfigUser(did string) bool {
+	if s.config == nil {
+		return false
+	}
+	for _, u := range s.config.Users {
+		if u.DID == did {
+			return true
+		}
+	}
+	return false
 }
 
 // Reload reloads the configuration from disk
@@ -109,7 +236,7 @@ func (s *Service) Reload() error {
 func (s *Service) IsEnabled() bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.config != nil && len(s.config.Users) > 0
+	return len(s.userRoles) > 0
 }
 
 // IsAdmin returns true if the given DID has the admin role
@@ -174,7 +301,8 @@ func (s *Service) GetModeratorUser(did string) (*ModeratorUser, bool) {
 	return &userCopy, true
 }
 
-// ListModerators returns all configured moderator users
+// ListModerators returns all moderator users, config-defined first followed
+// by those granted at runtime
 func (s *Service) ListModerators() []ModeratorUser {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -184,8 +312,13 @@ func (s *Service) ListModerators() []ModeratorUser {
 	}
 
 	// Return a copy to prevent external modification
-	result := make([]ModeratorUser, len(s.config.Users))
+	result := make([]ModeratorUser, len(s.config.Users), len(s.config.Users)+len(s.stored))
 	copy(result, s.config.Users)
+	for _, u := range s.stored {
+		if !s.isConfigUser(u.DID) {
+			result = append(result, u)
+		}
+	}
 	return result
 }
 
@@ -1,6 +1,8 @@
 package moderation
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -204,6 +206,111 @@ func TestListModerators(t *testing.T) {
 	assert.Equal(t, "admin.test", originalUser.Handle)
 }
 
+type memModeratorStore struct {
+	users   map[string]ModeratorUser
+	saveErr error
+}
+
+func (m *memModeratorStore) ListModerators(_ context.Context) ([]ModeratorUser, error) {
+	var out []ModeratorUser
+	for _, u := range m.users {
+		out = append(out, u)
+	}
+	return out, nil
+}
+
+func (m *memModeratorStore) SaveModerator(_ context.Context, user ModeratorUser) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.users[user.DID] = user
+	return nil
+}
+
+func (m *memModeratorStore) DeleteModerator(_ context.Context, did string) error {
+	delete(m.users, did)
+	return nil
+}
+
+func TestSetStore_LoadsStoredModerators(t *testing.T) {
+	svc := createTestService(t)
+	store := &memModeratorStore{users: map[string]ModeratorUser{
+		"did:plc:runtime": {DID: "did:plc:runtime", Role: RoleModerator, AddedBy: "did:plc:admin1"},
+		"did:plc:mod1":    {DID: "did:plc:mod1", Role: RoleAdmin, AddedBy: "did:plc:admin1"},
+		"did:plc:stale":   {DID: "did:plc:stale", Role: "retired", AddedBy: "did:plc:admin1"},
+	}}
+	require.NoError(t, svc.SetStore(context.Background(), store))
+
+	assert.True(t, svc.IsModerator("did:plc:runtime"))
+	assert.True(t, svc.HasPermission("did:plc:runtime", PermissionHideRecord))
+	assert.False(t, svc.IsAdmin("did:plc:mod1"), "config file wins over a stored grant")
+	assert.False(t, svc.IsModerator("did:plc:stale"), "roles removed from config grant nothing")
+	assert.Len(t, svc.ListModerators(), 4)
+}
+
+func TestAddRemoveModerator(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		user      ModeratorUser
+		remove    bool
+		saveErr   error
+		wantErr   error
+		wantIsMod bool
+	}{
+		{"grant moderator", ModeratorUser{DID: "did:plc:new", Role: RoleModerator}, false, nil, nil, true},
+		{"unknown role", ModeratorUser{DID: "did:plc:new", Role: "owner"}, false, nil, ErrUnknownRole, false},
+		{"config user can't be changed", ModeratorUser{DID: "did:plc:mod1", Role: RoleAdmin}, false, nil, ErrConfigModerator, true},
+		{"config user can't be removed", ModeratorUser{DID: "did:plc:mod1"}, true, nil, ErrConfigModerator, true},
+		{"store failure grants nothing", ModeratorUser{DID: "did:plc:new", Role: RoleModerator}, false, errors.New("disk full"), nil, false},
+		{"removing unknown DID is fine", ModeratorUser{DID: "did:plc:nobody"}, true, nil, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := createTestService(t)
+			store := &memModeratorStore{users: map[string]ModeratorUser{}, saveErr: tt.saveErr}
+			require.NoError(t, svc.SetStore(ctx, store))
+
+			var err error
+			if tt.remove {
+				err = svc.RemoveModerator(ctx, tt.user.DID)
+			} else {
+				err = svc.AddModerator(ctx, tt.user)
+			}
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.saveErr != nil:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantIsMod, svc.IsModerator(tt.user.DID))
+		})
+	}
+
+	t.Run("grant then revoke", func(t *testing.T) {
+		svc := createTestService(t)
+		store := &memModeratorStore{users: map[string]ModeratorUser{}}
+		require.NoError(t, svc.SetStore(ctx, store))
+
+		require.NoError(t, svc.AddModerator(ctx, ModeratorUser{DID: "did:plc:new", Role: RoleModerator}))
+		require.NoError(t, svc.AddModerator(ctx, ModeratorUser{DID: "did:plc:new", Role: RoleAdmin}))
+		assert.True(t, svc.IsAdmin("did:plc:new"), "re-granting replaces the role")
+		assert.Len(t, store.users, 1)
+
+		require.NoError(t, svc.RemoveModerator(ctx, "did:plc:new"))
+		assert.False(t, svc.IsModerator("did:plc:new"))
+		assert.Empty(t, store.users)
+	})
+
+	t.Run("no store attached", func(t *testing.T) {
+		svc := createTestService(t)
+		assert.ErrorIs(t, svc.AddModerator(ctx, ModeratorUser{DID: "did:plc:new", Role: RoleModerator}), ErrNoModeratorStore)
+	})
+}
+
 func TestListRoles(t *testing.T) {
 	svc := createTestService(t)
 
@@ -289,6 +396,24 @@ func TestRole_HasPermission(t *testing.T) {
 	assert.False(t, role.HasPermission(PermissionBlacklistUser))
 }
 
+func TestParseReportCategory(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ReportCategory
+	}{
+		{"spam", ReportCategorySpam},
+		{"off_topic", ReportCategoryOffTopic},
+		{"", ReportCategoryOther},
+		{"SPAM", ReportCategoryOther},
+		{"something-else", ReportCategoryOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseReportCategory(tt.in))
+		})
+	}
+}
+
 func TestConfig_Validate(t *testing.T) {
 	t.Run("nil roles map", func(t *testing.T) {
 		config := &Config{
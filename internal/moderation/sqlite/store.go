@@ -4,8 +4,11 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"tangled.org/arabica.social/arabica/internal/moderation"
@@ -45,9 +48,11 @@ func (s *ModerationStore) HideRecord(ctx context.Context, entry moderation.Hidde
 	return nil
 }
 
-func (s *ModerationStore) UnhideRecord(ctx context.Context, atURI string) error {
-	_, err := s.db.ExecContext(ctx, `DELETE FROM moderation_hidden_records WHERE uri = ?`, atURI)
-	return err
+// UnhideRecord removes atURI from the hidden list. A pending appeal against
+// it is approved in the same transaction, credited to unhiddenBy, since
+// there is nothing left to appeal.
+func (s *ModerationStore) UnhideRecord(ctx context.Context, atURI, unhiddenBy string) error {
+	return s.unhide(ctx, atURI, unhiddenBy, false)
 }
 
 func (s *ModerationStore) IsRecordHidden(ctx context.Context, atURI string) bool {
@@ -119,6 +124,54 @@ func (s *ModerationStore) ListHiddenURIs(ctx context.Context) ([]string, error)
 	return uris, rows.Err()
 }
 
+// ========== Pinned Records ==========
+
+// Pin features a record at the top of the public feed. Pinning an already
+// pinned URI refreshes its timestamp and note, moving it back to the front.
+func (s *ModerationStore) Pin(ctx context.Context, entry moderation.PinnedRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO moderation_pinned_records (uri, pinned_at, pinned_by, note)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(uri) DO UPDATE SET
+			pinned_at = excluded.pinned_at,
+			pinned_by = excluded.pinned_by,
+			note      = excluded.note
+	`, entry.ATURI, entry.PinnedAt.Format(time.RFC3339Nano), entry.PinnedBy, entry.Note)
+	if err != nil {
+		return fmt.Errorf("pin record: %w", err)
+	}
+	return nil
+}
+
+func (s *ModerationStore) Unpin(ctx context.Context, atURI string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM moderation_pinned_records WHERE uri = ?`, atURI)
+	return err
+}
+
+// ListPinned returns pinned records, most recently pinned first.
+func (s *ModerationStore) ListPinned(ctx context.Context) ([]moderation.PinnedRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT uri, pinned_at, pinned_by, note
+		FROM moderation_pinned_records ORDER BY pinned_at DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []moderation.PinnedRecord
+	for rows.Next() {
+		var r moderation.PinnedRecord
+		var pinnedAtStr string
+		if err := rows.Scan(&r.ATURI, &pinnedAtStr, &r.PinnedBy, &r.Note); err != nil {
+			continue
+		}
+		r.PinnedAt, _ = time.Parse(time.RFC3339Nano, pinnedAtStr)
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
 // ========== Blacklist ==========
 
 func (s *ModerationStore) BlacklistUser(ctx context.Context, entry moderation.BlacklistedUser) error {
@@ -206,14 +259,67 @@ func (s *ModerationStore) ListBlacklistedUsers(ctx context.Context) ([]moderatio
 	return users, rows.Err()
 }
 
+// ========== Moderators ==========
+
+// SaveModerator records a runtime-granted moderator, replacing any earlier
+// grant for the same DID.
+func (s *ModerationStore) SaveModerator(ctx context.Context, user moderation.ModeratorUser) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO moderation_moderators (did, handle, role, note, added_by, added_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(did) DO UPDATE SET
+			handle   = excluded.handle,
+			role     = excluded.role,
+			note     = excluded.note,
+			added_by = excluded.added_by,
+			added_at = excluded.added_at
+	`, user.DID, user.Handle, string(user.Role), user.Note, user.AddedBy, user.AddedAt.Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("save moderator: %w", err)
+	}
+	return nil
+}
+
+func (s *ModerationStore) DeleteModerator(ctx context.Context, did string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM moderation_moderators WHERE did = ?`, did)
+	if err != nil {
+		return fmt.Errorf("delete moderator: %w", err)
+	}
+	return nil
+}
+
+func (s *ModerationStore) ListModerators(ctx context.Context) ([]moderation.ModeratorUser, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT did, handle, role, note, added_by, added_at
+		FROM moderation_moderators ORDER BY added_at
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []moderation.ModeratorUser
+	for rows.Next() {
+		var u moderation.ModeratorUser
+		var addedAtStr string
+		if err := rows.Scan(&u.DID, &u.Handle, &u.Role, &u.Note, &u.AddedBy, &addedAtStr); err != nil {
+			continue
+		}
+		u.AddedAt, _ = time.Parse(time.RFC3339Nano, addedAtStr)
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
 // ========== Reports ==========
 
 func (s *ModerationStore) CreateReport(ctx context.Context, report moderation.Report) error {
 	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO moderation_reports
-			(id, subject_uri, subject_did, reporter_did, reason, created_at, status, resolved_by, resolved_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, report.ID, report.SubjectURI, report.SubjectDID, report.ReporterDID, report.Reason,
+			(id, subject_uri, subject_did, reporter_did, category, reason, created_at, status, resolved_by, resolved_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, report.ID, report.SubjectURI, report.SubjectDID, report.ReporterDID,
+		string(moderation.ParseReportCategory(string(report.Category))), report.Reason,
 		report.CreatedAt.Format(time.RFC3339Nano), string(report.Status), report.ResolvedBy, nil)
 	if err != nil {
 		return fmt.Errorf("create report: %w", err)
@@ -226,9 +332,9 @@ func (s *ModerationStore) GetReport(ctx context.Context, id string) (*moderation
 	var createdAtStr string
 	var resolvedAtStr sql.NullString
 	err := s.db.QueryRowContext(ctx, `
-		SELECT id, subject_uri, subject_did, reporter_did, reason, created_at, status, resolved_by, resolved_at
+		SELECT id, subject_uri, subject_did, reporter_did, category, reason, created_at, status, resolved_by, resolved_at
 		FROM moderation_reports WHERE id = ?
-	`, id).Scan(&r.ID, &r.SubjectURI, &r.SubjectDID, &r.ReporterDID, &r.Reason,
+	`, id).Scan(&r.ID, &r.SubjectURI, &r.SubjectDID, &r.ReporterDID, &r.Category, &r.Reason,
 		&createdAtStr, &r.Status, &r.ResolvedBy, &resolvedAtStr)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -248,13 +354,85 @@ func (s *ModerationStore) ListPendingReports(ctx context.Context) ([]moderation.
 	return s.listReports(ctx, `WHERE status = 'pending' ORDER BY created_at DESC`)
 }
 
+// ListPendingReportsPage returns up to limit pending reports, newest first,
+// starting after cursor. An empty cursor starts from the newest report. A
+// valid category narrows the queue; empty or unknown values match every
+// category. The returned cursor is empty once there is nothing more to load.
+func (s *ModerationStore) ListPendingReportsPage(ctx context.Context, category, cursor string, limit int) ([]moderation.Report, string, error) {
+	where := `WHERE status = 'pending'`
+	var args []any
+	if c := moderation.ReportCategory(category); c.IsValid() {
+		where += ` AND category = ?`
+		args = append(args, string(c))
+	}
+	if ts, id, ok := decodePageCursor(cursor); ok {
+		where += ` AND (created_at < ? OR (created_at = ? AND id < ?))`
+		args = append(args, ts, ts, id)
+	}
+	args = append(args, limit+1)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, subject_uri, subject_did, reporter_did, category, reason, created_at, status, resolved_by, resolved_at
+		FROM moderation_reports `+where+` ORDER BY created_at DESC, id DESC LIMIT ?`, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+	reports, err := scanReports(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(reports) > limit {
+		reports = reports[:limit]
+		last := reports[limit-1]
+		next = encodePageCursor(last.CreatedAt, last.ID)
+	}
+	return reports, next, nil
+}
+
+// ListResolvedReports returns up to limit dismissed or actioned reports,
+// most recently resolved first, starting after cursor. The returned cursor
+// is empty on the last page.
+func (s *ModerationStore) ListResolvedReports(ctx context.Context, limit int, cursor string) ([]moderation.Report, string, error) {
+	where := `WHERE status != 'pending' AND resolved_at IS NOT NULL`
+	var args []any
+	if ts, id, ok := decodePageCursor(cursor); ok {
+		where += ` AND (resolved_at < ? OR (resolved_at = ? AND id < ?))`
+		args = append(args, ts, ts, id)
+	}
+	args = append(args, limit+1)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, subject_uri, subject_did, reporter_did, category, reason, created_at, status, resolved_by, resolved_at
+		FROM moderation_reports `+where+` ORDER BY resolved_at DESC, id DESC LIMIT ?`, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+	reports, err := scanReports(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(reports) > limit {
+		reports = reports[:limit]
+		if last := reports[limit-1]; last.ResolvedAt != nil {
+			next = encodePageCursor(*last.ResolvedAt, last.ID)
+		}
+	}
+	return reports, next, nil
+}
+
 func (s *ModerationStore) ListAllReports(ctx context.Context) ([]moderation.Report, error) {
 	return s.listReports(ctx, `ORDER BY created_at DESC`)
 }
 
 func (s *ModerationStore) listReports(ctx context.Context, clause string) ([]moderation.Report, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, subject_uri, subject_did, reporter_did, reason, created_at, status, resolved_by, resolved_at
+		SELECT id, subject_uri, subject_did, reporter_did, category, reason, created_at, status, resolved_by, resolved_at
 		FROM moderation_reports `+clause)
 	if err != nil {
 		return nil, err
@@ -269,7 +447,7 @@ func scanReports(rows *sql.Rows) ([]moderation.Report, error) {
 		var r moderation.Report
 		var createdAtStr string
 		var resolvedAtStr sql.NullString
-		if err := rows.Scan(&r.ID, &r.SubjectURI, &r.SubjectDID, &r.ReporterDID, &r.Reason,
+		if err := rows.Scan(&r.ID, &r.SubjectURI, &r.SubjectDID, &r.ReporterDID, &r.Category, &r.Reason,
 			&createdAtStr, &r.Status, &r.ResolvedBy, &resolvedAtStr); err != nil {
 			continue
 		}
@@ -360,12 +538,28 @@ func (s *ModerationStore) LogAction(ctx context.Context, entry moderation.AuditE
 }
 
 func (s *ModerationStore) ListAuditLog(ctx context.Context, limit int) ([]moderation.AuditEntry, error) {
+	entries, _, err := s.ListAuditLogPage(ctx, "", limit)
+	return entries, err
+}
+
+// ListAuditLogPage returns up to limit audit entries, newest first, starting
+// after cursor. Entries sharing a timestamp are ordered by ID so pages never
+// overlap or skip. The returned cursor is empty on the last page.
+func (s *ModerationStore) ListAuditLogPage(ctx context.Context, cursor string, limit int) ([]moderation.AuditEntry, string, error) {
+	where := ""
+	args := []any{}
+	if ts, id, ok := decodePageCursor(cursor); ok {
+		where = `WHERE timestamp < ? OR (timestamp = ? AND id < ?)`
+		args = append(args, ts, ts, id)
+	}
+	args = append(args, limit+1)
+
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT id, action, actor_did, target_uri, reason, details, timestamp, auto_mod
-		FROM moderation_audit_log ORDER BY timestamp DESC LIMIT ?
-	`, limit)
+		FROM moderation_audit_log `+where+` ORDER BY timestamp DESC, id DESC LIMIT ?
+	`, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer rows.Close()
 
@@ -383,7 +577,156 @@ func (s *ModerationStore) ListAuditLog(ctx context.Context, limit int) ([]modera
 		_ = json.Unmarshal([]byte(detailsStr), &e.Details)
 		entries = append(entries, e)
 	}
-	return entries, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(entries) > limit {
+		entries = entries[:limit]
+		last := entries[limit-1]
+		next = encodePageCursor(last.Timestamp, last.ID)
+	}
+	return entries, next, nil
+}
+
+// encodePageCursor packs the sort key of the last row on a page. Timestamps
+// are stored as RFC3339Nano text, so the cursor compares against the same
+// representation the ORDER BY uses.
+func encodePageCursor(t time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(t.Format(time.RFC3339Nano) + "|" + id))
+}
+
+func decodePageCursor(cursor string) (timestamp, id string, ok bool) {
+	if cursor == "" {
+		return "", "", false
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", false
+	}
+	return strings.Cut(string(b), "|")
+}
+
+// ========== Appeals ==========
+
+// ErrAppealExists is returned by CreateAppeal when the URI was already appealed.
+var ErrAppealExists = errors.New("record already appealed")
+
+// CreateAppeal stores a pending appeal. A URI can only be appealed once, even
+// after the first appeal is resolved.
+func (s *ModerationStore) CreateAppeal(ctx context.Context, appeal moderation.Appeal) error {
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO moderation_appeals (uri, did, text, created_at, status)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(uri) DO NOTHING
+	`, appeal.URI, appeal.DID, appeal.Text, appeal.CreatedAt.Format(time.RFC3339Nano), string(moderation.AppealStatusPending))
+	if err != nil {
+		return fmt.Errorf("create appeal: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrAppealExists
+	}
+	return nil
+}
+
+func (s *ModerationStore) GetAppeal(ctx context.Context, uri string) (*moderation.Appeal, error) {
+	var a moderation.Appeal
+	var createdAtStr string
+	var resolvedAtStr sql.NullString
+	err := s.db.QueryRowContext(ctx, `
+		SELECT uri, did, text, created_at, status, resolved_by, resolved_at
+		FROM moderation_appeals WHERE uri = ?
+	`, uri).Scan(&a.URI, &a.DID, &a.Text, &createdAtStr, &a.Status, &a.ResolvedBy, &resolvedAtStr)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	a.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAtStr)
+	if resolvedAtStr.Valid {
+		t, _ := time.Parse(time.RFC3339Nano, resolvedAtStr.String)
+		a.ResolvedAt = &t
+	}
+	return &a, nil
+}
+
+func (s *ModerationStore) ListPendingAppeals(ctx context.Context) ([]moderation.Appeal, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT uri, did, text, created_at, status, resolved_by, resolved_at
+		FROM moderation_appeals WHERE status = 'pending' ORDER BY created_at
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanAppeals(rows)
+}
+
+func scanAppeals(rows *sql.Rows) ([]moderation.Appeal, error) {
+	var appeals []moderation.Appeal
+	for rows.Next() {
+		var a moderation.Appeal
+		var createdAtStr string
+		var resolvedAtStr sql.NullString
+		if err := rows.Scan(&a.URI, &a.DID, &a.Text, &createdAtStr, &a.Status, &a.ResolvedBy, &resolvedAtStr); err != nil {
+			continue
+		}
+		a.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAtStr)
+		if resolvedAtStr.Valid {
+			t, _ := time.Parse(time.RFC3339Nano, resolvedAtStr.String)
+			a.ResolvedAt = &t
+		}
+		appeals = append(appeals, a)
+	}
+	return appeals, rows.Err()
+}
+
+// ApproveAppeal unhides the record under a pending appeal and marks the
+// appeal approved, in one transaction. It returns sql.ErrNoRows, leaving the
+// record hidden, if there is no pending appeal for the URI.
+func (s *ModerationStore) ApproveAppeal(ctx context.Context, uri, resolvedBy string) error {
+	return s.unhide(ctx, uri, resolvedBy, true)
+}
+
+func (s *ModerationStore) unhide(ctx context.Context, uri, by string, requireAppeal bool) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unhide record: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM moderation_hidden_records WHERE uri = ?`, uri); err != nil {
+		return fmt.Errorf("unhide record: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, `
+		UPDATE moderation_appeals SET status = ?, resolved_by = ?, resolved_at = ?
+		WHERE uri = ? AND status = 'pending'
+	`, string(moderation.AppealStatusApproved), by, time.Now().Format(time.RFC3339Nano), uri)
+	if err != nil {
+		return fmt.Errorf("approve appeal: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 && requireAppeal {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+// ResolveAppeal marks a pending appeal approved or rejected. It returns
+// sql.ErrNoRows if there is no pending appeal for the URI.
+func (s *ModerationStore) ResolveAppeal(ctx context.Context, uri string, status moderation.AppealStatus, resolvedBy string) error {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE moderation_appeals SET status = ?, resolved_by = ?, resolved_at = ?
+		WHERE uri = ? AND status = 'pending'
+	`, string(status), resolvedBy, time.Now().Format(time.RFC3339Nano), uri)
+	if err != nil {
+		return fmt.Errorf("resolve appeal: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
 
 // ========== Auto-hide Resets ==========
@@ -412,6 +755,46 @@ func (s *ModerationStore) GetAutoHideReset(ctx context.Context, did string) (tim
 	return t, nil
 }
 
+// ========== Settings ==========
+
+const automodSettingsKey = "automod"
+
+// GetAutomodSettings returns the admin override for automod thresholds, or
+// nil when none has been saved.
+func (s *ModerationStore) GetAutomodSettings(ctx context.Context) (*moderation.AutomodSettings, error) {
+	var value string
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM moderation_settings WHERE key = ?`, automodSettingsKey).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var settings moderation.AutomodSettings
+	if err := json.Unmarshal([]byte(value), &settings); err != nil {
+		return nil, fmt.Errorf("decode automod settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// SaveAutomodSettings stores an admin override for automod thresholds.
+// Callers validate first; the store persists whatever it is given.
+func (s *ModerationStore) SaveAutomodSettings(ctx context.Context, settings moderation.AutomodSettings, updatedBy string) error {
+	value, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("encode automod settings: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO moderation_settings (key, value, updated_by, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_by = excluded.updated_by, updated_at = excluded.updated_at
+	`, automodSettingsKey, string(value), updatedBy, time.Now().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("save automod settings: %w", err)
+	}
+	return nil
+}
+
 // ========== Labels ==========
 
 func (s *ModerationStore) AddLabel(ctx context.Context, label moderation.Label) error {
@@ -32,6 +32,20 @@ func setupTestDB(t *testing.T) *ModerationStore {
 		);
 		CREATE INDEX idx_modlabels_entity ON moderation_labels(entity_type, entity_id);
 		CREATE INDEX idx_modlabels_expires ON moderation_labels(expires_at) WHERE expires_at IS NOT NULL;
+		CREATE TABLE moderation_moderators (
+			did      TEXT PRIMARY KEY,
+			handle   TEXT NOT NULL DEFAULT '',
+			role     TEXT NOT NULL,
+			note     TEXT NOT NULL DEFAULT '',
+			added_by TEXT NOT NULL,
+			added_at TEXT NOT NULL
+		);
+		CREATE TABLE moderation_pinned_records (
+			uri       TEXT PRIMARY KEY,
+			pinned_at TEXT NOT NULL,
+			pinned_by TEXT NOT NULL,
+			note      TEXT NOT NULL DEFAULT ''
+		);
 	`)
 	assert.NoError(t, err)
 	return NewModerationStore(db)
@@ -215,3 +229,76 @@ func TestAddLabel_Upsert(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, labels, 1)
 }
+
+func TestSaveListDeleteModerator(t *testing.T) {
+	store := setupTestDB(t)
+	ctx := context.Background()
+
+	added := time.Now().UTC().Truncate(time.Second)
+	err := store.SaveModerator(ctx, moderation.ModeratorUser{
+		DID:     "did:plc:alice",
+		Handle:  "alice.test",
+		Role:    moderation.RoleModerator,
+		AddedBy: "did:plc:admin",
+		AddedAt: added,
+	})
+	assert.NoError(t, err)
+
+	// Saving again replaces the role
+	err = store.SaveModerator(ctx, moderation.ModeratorUser{
+		DID:     "did:plc:alice",
+		Role:    moderation.RoleAdmin,
+		AddedBy: "did:plc:admin",
+		AddedAt: added,
+	})
+	assert.NoError(t, err)
+
+	users, err := store.ListModerators(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, moderation.RoleAdmin, users[0].Role)
+	assert.Equal(t, "did:plc:admin", users[0].AddedBy)
+	assert.True(t, added.Equal(users[0].AddedAt))
+
+	assert.NoError(t, store.DeleteModerator(ctx, "did:plc:alice"))
+	users, err = store.ListModerators(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+func TestPinListUnpin(t *testing.T) {
+	store := setupTestDB(t)
+	ctx := context.Background()
+
+	first := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	second := first.Add(30 * time.Minute)
+	assert.NoError(t, store.Pin(ctx, moderation.PinnedRecord{
+		ATURI: "at://did:plc:a/social.arabica.alpha.brew/1", PinnedAt: first, PinnedBy: "did:plc:admin",
+	}))
+	assert.NoError(t, store.Pin(ctx, moderation.PinnedRecord{
+		ATURI: "at://did:plc:b/social.arabica.alpha.brew/2", PinnedAt: second, PinnedBy: "did:plc:admin", Note: "challenge winner",
+	}))
+
+	pinned, err := store.ListPinned(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, pinned, 2)
+	assert.Equal(t, "at://did:plc:b/social.arabica.alpha.brew/2", pinned[0].ATURI, "newest pin first")
+	assert.Equal(t, "challenge winner", pinned[0].Note)
+	assert.True(t, second.Equal(pinned[0].PinnedAt))
+
+	// Pinning again moves the record back to the front
+	assert.NoError(t, store.Pin(ctx, moderation.PinnedRecord{
+		ATURI: "at://did:plc:a/social.arabica.alpha.brew/1", PinnedAt: second.Add(time.Minute), PinnedBy: "did:plc:admin2",
+	}))
+	pinned, err = store.ListPinned(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, pinned, 2)
+	assert.Equal(t, "at://did:plc:a/social.arabica.alpha.brew/1", pinned[0].ATURI)
+	assert.Equal(t, "did:plc:admin2", pinned[0].PinnedBy)
+
+	assert.NoError(t, store.Unpin(ctx, "at://did:plc:a/social.arabica.alpha.brew/1"))
+	pinned, err = store.ListPinned(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, pinned, 1)
+	assert.Equal(t, "at://did:plc:b/social.arabica.alpha.brew/2", pinned[0].ATURI)
+}
@@ -0,0 +1,73 @@
+package ogcard
+
+import "sync"
+
+// DefaultImageCacheSize bounds how many encoded cards an ImageCache keeps.
+// A card is typically 50-150KB, so the default stays well under 32MB.
+const DefaultImageCacheSize = 200
+
+// ImageCache holds encoded PNG cards keyed by record URI and CID. A CID pins
+// the exact record content, so a cached card never goes stale: an edit
+// produces a new CID and therefore a new key. Entries are evicted oldest
+// first once the cache is full.
+//
+// A nil *ImageCache is valid and caches nothing.
+type ImageCache struct {
+	mu      sync.Mutex
+	max     int
+	entries map[string][]byte
+	order   []string
+}
+
+// NewImageCache returns a cache holding at most max cards.
+func NewImageCache(max int) *ImageCache {
+	if max <= 0 {
+		max = DefaultImageCacheSize
+	}
+	return &ImageCache{max: max, entries: make(map[string][]byte, max)}
+}
+
+func imageCacheKey(uri, cid string) string {
+	return uri + "@" + cid
+}
+
+// Get returns the cached PNG for uri at cid, if present.
+func (c *ImageCache) Get(uri, cid string) ([]byte, bool) {
+	if c == nil || cid == "" {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	png, ok := c.entries[imageCacheKey(uri, cid)]
+	return png, ok
+}
+
+// Put stores an encoded PNG. Records without a CID are not cached since
+// there is nothing to tell two versions apart.
+func (c *ImageCache) Put(uri, cid string, png []byte) {
+	if c == nil || cid == "" {
+		return
+	}
+	key := imageCacheKey(uri, cid)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	for len(c.order) >= c.max {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.entries[key] = png
+	c.order = append(c.order, key)
+}
+
+// Len reports the number of cached cards.
+func (c *ImageCache) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
@@ -0,0 +1,52 @@
+package ogcard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageCache(t *testing.T) {
+	const uri = "at://did:plc:alice/social.arabica.alpha.brew/b1"
+
+	t.Run("keys on CID", func(t *testing.T) {
+		c := NewImageCache(4)
+		c.Put(uri, "cid1", []byte("v1"))
+
+		got, ok := c.Get(uri, "cid1")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("v1"), got)
+
+		_, ok = c.Get(uri, "cid2")
+		assert.False(t, ok, "an edited record must not reuse the old card")
+	})
+
+	t.Run("skips records without a CID", func(t *testing.T) {
+		c := NewImageCache(4)
+		c.Put(uri, "", []byte("v1"))
+		_, ok := c.Get(uri, "")
+		assert.False(t, ok)
+		assert.Zero(t, c.Len())
+	})
+
+	t.Run("evicts oldest first", func(t *testing.T) {
+		c := NewImageCache(2)
+		c.Put(uri, "a", []byte("a"))
+		c.Put(uri, "b", []byte("b"))
+		c.Put(uri, "c", []byte("c"))
+
+		assert.Equal(t, 2, c.Len())
+		_, ok := c.Get(uri, "a")
+		assert.False(t, ok)
+		_, ok = c.Get(uri, "c")
+		assert.True(t, ok)
+	})
+
+	t.Run("nil cache is a no-op", func(t *testing.T) {
+		var c *ImageCache
+		c.Put(uri, "a", []byte("a"))
+		_, ok := c.Get(uri, "a")
+		assert.False(t, ok)
+		assert.Zero(t, c.Len())
+	})
+}
@@ -24,6 +24,8 @@ const (
 
 	// Social NSIDs in oolong's own namespace. Mirror arabica's shape
 	// minus the lexicon id.
-	NSIDLike    = NSIDBase + ".like"
-	NSIDComment = NSIDBase + ".comment"
+	NSIDLike     = NSIDBase + ".like"
+	NSIDComment  = NSIDBase + ".comment"
+	NSIDFollow   = NSIDBase + ".follow"
+	NSIDBookmark = NSIDBase + ".bookmark"
 )
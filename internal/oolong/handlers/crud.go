@@ -38,7 +38,7 @@ func (h *Handlers) HandleTeaCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	tea := teaFromCreateRequest(&req)
-	rkey, err := handlers.PutRecord(r.Context(), store, oolong.NSIDTea, "", func(s records.Store) (map[string]any, error) {
+	rkey, err := handlers.PutRecord(r.Context(), store, oolong.NSIDTea, "", "", func(s records.Store) (map[string]any, error) {
 		var vendorURI string
 		if req.VendorRKey != "" {
 			vendorURI = atp.BuildATURI(s.DID(), oolong.NSIDVendor, req.VendorRKey)
@@ -82,7 +82,7 @@ func (h *Handlers) HandleTeaUpdate(w http.ResponseWriter, r *http.Request) {
 	tea := teaFromCreateRequest(&createReq)
 	tea.RKey = rkey
 	tea.CreatedAt = handlers.ExistingCreatedAt(r.Context(), store, oolong.NSIDTea, rkey)
-	if _, err := handlers.PutRecord(r.Context(), store, oolong.NSIDTea, rkey, func(s records.Store) (map[string]any, error) {
+	if _, err := handlers.PutRecord(r.Context(), store, oolong.NSIDTea, rkey, "", func(s records.Store) (map[string]any, error) {
 		var vendorURI string
 		if createReq.VendorRKey != "" {
 			vendorURI = atp.BuildATURI(s.DID(), oolong.NSIDVendor, createReq.VendorRKey)
@@ -298,7 +298,7 @@ func (h *Handlers) HandleOolongBrewCreate(w http.ResponseWriter, r *http.Request
 		return
 	}
 	b := brewFromCreateRequest(&req)
-	rkey, err := handlers.PutRecord(r.Context(), store, oolong.NSIDBrew, "", func(s records.Store) (map[string]any, error) {
+	rkey, err := handlers.PutRecord(r.Context(), store, oolong.NSIDBrew, "", "", func(s records.Store) (map[string]any, error) {
 		teaURI := buildOolongRef(s, req.TeaRKey, oolong.NSIDTea)
 		vesselURI := buildOolongRef(s, req.VesselRKey, oolong.NSIDVessel)
 		infuserURI := buildOolongRef(s, req.InfuserRKey, oolong.NSIDInfuser)
@@ -340,7 +340,7 @@ func (h *Handlers) HandleOolongBrewUpdate(w http.ResponseWriter, r *http.Request
 	b := brewFromCreateRequest(&req)
 	b.RKey = rkey
 	b.CreatedAt = handlers.ExistingCreatedAt(r.Context(), store, oolong.NSIDBrew, rkey)
-	if _, err := handlers.PutRecord(r.Context(), store, oolong.NSIDBrew, rkey, func(s records.Store) (map[string]any, error) {
+	if _, err := handlers.PutRecord(r.Context(), store, oolong.NSIDBrew, rkey, "", func(s records.Store) (map[string]any, error) {
 		teaURI := buildOolongRef(s, req.TeaRKey, oolong.NSIDTea)
 		vesselURI := buildOolongRef(s, req.VesselRKey, oolong.NSIDVessel)
 		infuserURI := buildOolongRef(s, req.InfuserRKey, oolong.NSIDInfuser)
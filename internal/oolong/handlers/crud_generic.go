@@ -35,7 +35,7 @@ func oolongCRUDWrite[Req any, PReq handlers.RequestValidator[Req], Model any](
 		return
 	}
 	handlers.RecordCRUDWrite[Req, PReq, Model](
-		w, r, store, nsid, jsonKey, rkey,
+		w, r, store, nsid, jsonKey, rkey, "",
 		func(r *http.Request, req *Req) error { return decodeOolongForm(r, req) },
 		build, setRKey, encode, h.InvalidateFeedCache, allowRedirect,
 	)
@@ -36,6 +36,10 @@ func (s *fakeRecordStore) PutRecord(_ context.Context, nsid, rkey string, record
 	return s.putResult, "cid", nil
 }
 
+func (s *fakeRecordStore) UpdateRecord(context.Context, string, string, string, any) (string, error) {
+	return "cid", nil
+}
+
 func (s *fakeRecordStore) RemoveRecord(_ context.Context, nsid, rkey string) error {
 	s.removeNSID = nsid
 	s.removeRKey = rkey
@@ -46,7 +50,7 @@ func TestPutOolongRecordCreateUsesReturnedRKey(t *testing.T) {
 	store := &fakeRecordStore{did: "did:plc:test", putResult: "new-rkey"}
 	record := map[string]any{"name": "tea"}
 
-	rkey, err := handlers.PutRecord(context.Background(), store, "social.oolong.alpha.tea", "", func(records.Store) (map[string]any, error) {
+	rkey, err := handlers.PutRecord(context.Background(), store, "social.oolong.alpha.tea", "", "", func(records.Store) (map[string]any, error) {
 		return record, nil
 	})
 
@@ -61,7 +65,7 @@ func TestPutOolongRecordUpdateFallsBackToExistingRKey(t *testing.T) {
 	store := &fakeRecordStore{did: "did:plc:test"}
 	record := map[string]any{"name": "updated"}
 
-	rkey, err := handlers.PutRecord(context.Background(), store, "social.oolong.alpha.tea", "existing-rkey", func(records.Store) (map[string]any, error) {
+	rkey, err := handlers.PutRecord(context.Background(), store, "social.oolong.alpha.tea", "existing-rkey", "", func(records.Store) (map[string]any, error) {
 		return record, nil
 	})
 
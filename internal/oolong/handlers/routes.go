@@ -46,7 +46,7 @@ func (Routes) RegisterAppRoutes(mux *http.ServeMux, ctx routing.AppRouteContext)
 	mux.HandleFunc("GET /teas/new", h.HandleOolongTeaNew)
 	mux.HandleFunc("GET /teas/{id}/edit", h.HandleOolongTeaEdit)
 
-	routing.RegisterEntityRoutes(mux, cop, ctx.App, h.EntityRouteBundles())
+	routing.RegisterEntityRoutes(mux, ctx, h.EntityRouteBundles())
 	mux.HandleFunc("GET /profile/{actor}", h.HandleOolongProfile)
 }
 
@@ -23,6 +23,20 @@ func DefaultTemperatureUnit() TemperatureUnit {
 	return TemperatureUnitRecorded
 }
 
+// UnitSystem picks the units weights (and, unless a temperature unit is
+// chosen explicitly, temperatures) are displayed in. Records always store
+// metric; only rendering converts.
+type UnitSystem string
+
+const (
+	UnitSystemMetric   UnitSystem = "metric"
+	UnitSystemImperial UnitSystem = "imperial"
+)
+
+func (u UnitSystem) IsValid() bool {
+	return u == UnitSystemMetric || u == UnitSystemImperial
+}
+
 type Visibility string
 
 const (
@@ -58,15 +72,30 @@ func DefaultProfileStatsVisibility() ProfileStatsVisibility {
 // intentionally stay outside this struct.
 type UserPreferences struct {
 	TemperatureUnit TemperatureUnit `json:"temperature_unit"`
+	UnitSystem      UnitSystem      `json:"unit_system"`
 }
 
 func DefaultUserPreferences() UserPreferences {
-	return UserPreferences{TemperatureUnit: DefaultTemperatureUnit()}
+	return UserPreferences{TemperatureUnit: DefaultTemperatureUnit(), UnitSystem: UnitSystemMetric}
 }
 
 func (p UserPreferences) WithDefaults() UserPreferences {
 	if !p.TemperatureUnit.IsValid() {
 		p.TemperatureUnit = DefaultTemperatureUnit()
 	}
+	if !p.UnitSystem.IsValid() {
+		p.UnitSystem = UnitSystemMetric
+	}
 	return p
 }
+
+// DisplayTemperatureUnit resolves the unit temperatures render in. An
+// explicit Celsius or Fahrenheit choice wins; otherwise imperial users see
+// Fahrenheit and everyone else sees the unit the brew was recorded in.
+func (p UserPreferences) DisplayTemperatureUnit() TemperatureUnit {
+	p = p.WithDefaults()
+	if p.TemperatureUnit == TemperatureUnitRecorded && p.UnitSystem == UnitSystemImperial {
+		return TemperatureUnitFahrenheit
+	}
+	return p.TemperatureUnit
+}
@@ -19,5 +19,8 @@ type Store interface {
 	FetchRecord(ctx context.Context, nsid, rkey string) (record map[string]any, uri, cid string, err error)
 	FetchAllRecords(ctx context.Context, nsid string) ([]RawRecord, error)
 	PutRecord(ctx context.Context, nsid, rkey string, record any) (resultRKey, cid string, err error)
+	// UpdateRecord overwrites an existing record only while its CID is
+	// still swapCID ("" skips the check) and returns the new CID.
+	UpdateRecord(ctx context.Context, nsid, rkey, swapCID string, record any) (cid string, err error)
 	RemoveRecord(ctx context.Context, nsid, rkey string) error
 }
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"tangled.org/arabica.social/arabica/internal/atplatform/domain"
+	"tangled.org/arabica.social/arabica/internal/atproto"
 	"tangled.org/arabica.social/arabica/internal/firehose"
 	"tangled.org/arabica.social/arabica/internal/handlers"
 	"tangled.org/arabica.social/arabica/internal/metrics"
@@ -26,6 +28,7 @@ type Config struct {
 	App               *domain.App
 	Handlers          *handlers.Handler
 	OAuthApp          *atp.OAuthApp
+	PasswordSessions  atproto.PasswordSessionStore
 	OnAuth            func(did string)
 	Logger            zerolog.Logger
 	ModerationService *moderation.Service
@@ -33,6 +36,18 @@ type Config struct {
 	CSSBundle         *assets.Bundle
 	JSAssets          *assets.JSAssets
 	AppRoutes         AppRoutes
+
+	// CreateRateLimit caps record-creating requests per user per minute.
+	// Zero means middleware.DefaultCreateRateLimit.
+	CreateRateLimit int
+
+	// CORSOrigins lists the browser origins allowed to call /api/ routes
+	// cross-origin with a bearer token. Empty disables CORS.
+	CORSOrigins []string
+
+	// CSRFSecret keys the per-session CSRF tokens. Empty uses a random key,
+	// so pages left open across a restart must be reloaded before posting.
+	CSRFSecret []byte
 }
 
 // AppRoutes is implemented by app-owned packages that register routes whose
@@ -46,7 +61,57 @@ type AppRoutes interface {
 type AppRouteContext struct {
 	App      *domain.App
 	Handlers *handlers.Handler
-	CSRF     *http.CrossOriginProtection
+	CSRF     *CSRFProtection
+
+	// CreateLimit wraps handlers that create records with the per-user
+	// rate limit. Nil disables limiting.
+	CreateLimit func(http.Handler) http.Handler
+}
+
+// Create wraps a record-creating handler with CSRF protection and the
+// per-user create limit.
+func (c AppRouteContext) Create(h http.Handler) http.Handler {
+	if c.CreateLimit != nil {
+		h = c.CreateLimit(h)
+	}
+	return c.CSRF.Handler(h)
+}
+
+// CSRFProtection applies cross-origin protection to state-changing routes.
+// CORS origins are trusted only on /api/ routes, where CORSMiddleware strips
+// their cookies; every other route still rejects them.
+type CSRFProtection struct {
+	site, api *http.CrossOriginProtection
+}
+
+// NewCSRFProtection builds a CSRFProtection trusting corsOrigins on /api/
+// routes. It logs and skips invalid origins and returns the valid ones.
+func NewCSRFProtection(corsOrigins []string, logger zerolog.Logger) (*CSRFProtection, []string) {
+	c := &CSRFProtection{
+		site: http.NewCrossOriginProtection(),
+		api:  http.NewCrossOriginProtection(),
+	}
+	var valid []string
+	for _, origin := range corsOrigins {
+		if err := c.api.AddTrustedOrigin(origin); err != nil {
+			logger.Warn().Err(err).Str("origin", origin).Msg("Ignoring invalid CORS origin")
+			continue
+		}
+		valid = append(valid, origin)
+	}
+	return c, valid
+}
+
+// Handler wraps h with the protection for the request's path.
+func (c *CSRFProtection) Handler(h http.Handler) http.Handler {
+	site, api := c.site.Handler(h), c.api.Handler(h)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			api.ServeHTTP(w, r)
+			return
+		}
+		site.ServeHTTP(w, r)
+	})
 }
 
 // SetupRouter creates and configures the HTTP router with all routes and middleware
@@ -54,15 +119,31 @@ func SetupRouter(cfg Config) http.Handler {
 	h := cfg.Handlers
 	mux := http.NewServeMux()
 
-	// Create CrossOriginProtection for CSRF protection
-	cop := http.NewCrossOriginProtection()
+	// Cross-origin protection for CSRF. Origins allowed to use the API
+	// cross-origin are trusted on /api/ routes only.
+	cop, corsOrigins := NewCSRFProtection(cfg.CORSOrigins, cfg.Logger)
+
+	createRate := cfg.CreateRateLimit
+	if createRate <= 0 {
+		createRate = middleware.DefaultCreateRateLimit
+	}
+	routeCtx := AppRouteContext{
+		App:         cfg.App,
+		Handlers:    h,
+		CSRF:        cop,
+		CreateLimit: middleware.UserRateLimitMiddleware(middleware.NewRateLimiter(createRate, time.Minute)),
+	}
 
 	// OAuth routes (no CSRF protection needed for GET and callback)
 	mux.HandleFunc("GET /login", h.HandleLogin)
 	mux.Handle("POST /auth/login", cop.Handler(http.HandlerFunc(h.HandleLoginSubmit)))
 	mux.HandleFunc("GET /oauth/callback", h.HandleOAuthCallback)
 	mux.Handle("POST /logout", cop.Handler(http.HandlerFunc(h.HandleLogout)))
+	mux.Handle("POST /logout/all", cop.Handler(http.HandlerFunc(h.HandleLogoutAll)))
+	mux.Handle("POST /account/forget", cop.Handler(http.HandlerFunc(h.HandleAccountForget)))
 	mux.Handle("POST /reauth", cop.Handler(http.HandlerFunc(h.HandleReauth)))
+	mux.Handle("POST /auth/app-password", cop.Handler(http.HandlerFunc(h.HandleAppPasswordLogin)))
+	mux.HandleFunc("POST /auth/app-password/revoke", h.HandleAppPasswordRevoke)
 	mux.HandleFunc("GET /.well-known/oauth-client-metadata.json", h.HandleClientMetadata)
 	mux.HandleFunc("GET /.well-known/oauth-protected-resource/.well-known/oauth-client-metadata.json", h.HandleClientMetadata)
 	mux.HandleFunc("GET /.well-known/client-metadata.json", h.HandleClientMetadata)
@@ -74,7 +155,10 @@ func SetupRouter(cfg Config) http.Handler {
 	mux.HandleFunc("GET /robots.txt", func(w http.ResponseWriter, r *http.Request) {
 		http.ServeFile(w, r, "static/robots.txt")
 	})
-	mux.HandleFunc("GET /healthz", handleHealthz(h, cfg.FirehoseConsumer))
+	mux.HandleFunc("GET /sitemap.xml", h.HandleSitemap)
+	mux.HandleFunc("GET /sitemaps/{file}", h.HandleSitemapFile)
+	mux.HandleFunc("GET /healthz", handleHealthz())
+	mux.HandleFunc("GET /readyz", handleReadyz(h, cfg.FirehoseConsumer))
 
 	// API routes for handle resolution (used by login autocomplete)
 	// These are intentionally public and don't require HTMX headers
@@ -84,9 +168,17 @@ func SetupRouter(cfg Config) http.Handler {
 	// Suggestion routes for entity typeahead (auth-protected, read-only GET)
 	mux.HandleFunc("GET /api/suggestions/{entity}", h.HandleEntitySuggestions)
 
-	// HTMX partials (loaded async via HTMX)
-	// These return HTML fragments and should only be accessed via HTMX
-	mux.Handle("GET /api/feed", middleware.RequireHTMXMiddleware(http.HandlerFunc(h.HandleFeedPartial)))
+	// Community feed: HTMX requests get the rendered partial, everything else
+	// gets the paginated JSON API.
+	mux.HandleFunc("GET /api/feed", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("HX-Request") == "true" {
+			h.HandleFeedPartial(w, r)
+			return
+		}
+		h.HandleFeedAPI(w, r)
+	})
+	mux.HandleFunc("GET /api/feed.json", h.HandleFeedJSON)
+	mux.HandleFunc("GET /feed/following", h.HandleFollowingFeed)
 
 	// Page routes (must come before static files)
 	mux.HandleFunc("GET /{$}", h.HandleHome) // {$} means exact match
@@ -98,15 +190,15 @@ func SetupRouter(cfg Config) http.Handler {
 	mux.HandleFunc("GET /atproto", h.HandleATProto)
 
 	if cfg.AppRoutes != nil {
-		cfg.AppRoutes.RegisterAppRoutes(mux, AppRouteContext{
-			App:      cfg.App,
-			Handlers: h,
-			CSRF:     cop,
-		})
+		cfg.AppRoutes.RegisterAppRoutes(mux, routeCtx)
 	}
 
-	mux.Handle("POST /api/likes/toggle", cop.Handler(http.HandlerFunc(h.HandleLikeToggle)))
+	mux.Handle("POST /api/likes/toggle", routeCtx.Create(http.HandlerFunc(h.HandleLikeToggle)))
+	mux.HandleFunc("GET /likes", h.HandleLikers)
+	mux.Handle("POST /api/follows/toggle", routeCtx.Create(http.HandlerFunc(h.HandleFollowToggle)))
+	mux.Handle("POST /api/bookmarks/toggle", routeCtx.Create(http.HandlerFunc(h.HandleBookmarkToggle)))
 	mux.Handle("POST /api/report", cop.Handler(http.HandlerFunc(h.HandleReport)))
+	mux.Handle("POST /api/appeal", cop.Handler(http.HandlerFunc(h.HandleAppeal)))
 
 	// AT-URI shaped redirect: /at/{nsid}/{actor}/{rkey} -> /{slug}/{actor}/{rkey}.
 	// Lets power users paste the lexicon-shaped URL and land on the canonical
@@ -129,11 +221,13 @@ func SetupRouter(cfg Config) http.Handler {
 
 	// Comment routes
 	mux.Handle("GET /api/comments", middleware.RequireHTMXMiddleware(http.HandlerFunc(h.HandleCommentList)))
-	mux.Handle("POST /api/comments", cop.Handler(http.HandlerFunc(h.HandleCommentCreate)))
+	mux.Handle("POST /api/comments", routeCtx.Create(http.HandlerFunc(h.HandleCommentCreate)))
+	mux.Handle("PUT /api/comments/{id}", cop.Handler(http.HandlerFunc(h.HandleCommentEdit)))
 	mux.Handle("DELETE /api/comments/{id}", cop.Handler(http.HandlerFunc(h.HandleCommentDelete)))
 
 	// Notification routes
 	mux.HandleFunc("GET /notifications", h.HandleNotifications)
+	mux.HandleFunc("GET /bookmarks", h.HandleBookmarks)
 	mux.Handle("POST /api/notifications/read", cop.Handler(http.HandlerFunc(h.HandleNotificationsMarkRead)))
 
 	// Settings
@@ -149,10 +243,28 @@ func SetupRouter(cfg Config) http.Handler {
 	mux.HandleFunc("GET /_mod", h.HandleAdmin)
 	mux.Handle("GET /_mod/content", middleware.RequireModerator(modSvc,
 		middleware.RequireHTMXMiddleware(http.HandlerFunc(h.HandleAdminPartial))))
+	mux.Handle("GET /_mod/reports/more", middleware.RequirePermission(modSvc, moderation.PermissionViewReports,
+		middleware.RequireHTMXMiddleware(http.HandlerFunc(h.HandleAdminReportsMore))))
+	mux.Handle("GET /_mod/reports/resolved", middleware.RequirePermission(modSvc, moderation.PermissionViewReports,
+		middleware.RequireHTMXMiddleware(http.HandlerFunc(h.HandleAdminResolvedReports))))
+	mux.Handle("GET /_mod/activity/more", middleware.RequirePermission(modSvc, moderation.PermissionViewAuditLog,
+		middleware.RequireHTMXMiddleware(http.HandlerFunc(h.HandleAdminAuditLogMore))))
 	mux.Handle("POST /_mod/hide", cop.Handler(
 		middleware.RequirePermission(modSvc, moderation.PermissionHideRecord, http.HandlerFunc(h.HandleHideRecord))))
 	mux.Handle("POST /_mod/unhide", cop.Handler(
 		middleware.RequirePermission(modSvc, moderation.PermissionUnhideRecord, http.HandlerFunc(h.HandleUnhideRecord))))
+	mux.Handle("POST /_mod/pin", cop.Handler(
+		middleware.RequireAdmin(modSvc, http.HandlerFunc(h.HandlePinRecord))))
+	mux.Handle("POST /_mod/unpin", cop.Handler(
+		middleware.RequireAdmin(modSvc, http.HandlerFunc(h.HandleUnpinRecord))))
+	mux.Handle("POST /_mod/appeal/approve", cop.Handler(
+		middleware.RequirePermission(modSvc, moderation.PermissionUnhideRecord, http.HandlerFunc(h.HandleApproveAppeal))))
+	mux.Handle("POST /_mod/appeal/reject", cop.Handler(
+		middleware.RequirePermission(modSvc, moderation.PermissionUnhideRecord, http.HandlerFunc(h.HandleRejectAppeal))))
+	mux.Handle("POST /_mod/bulk/hide", cop.Handler(
+		middleware.RequirePermission(modSvc, moderation.PermissionHideRecord, http.HandlerFunc(h.HandleBulkHide))))
+	mux.Handle("POST /_mod/bulk/dismiss", cop.Handler(
+		middleware.RequirePermission(modSvc, moderation.PermissionDismissReport, http.HandlerFunc(h.HandleBulkDismiss))))
 	mux.Handle("POST /_mod/dismiss-report", cop.Handler(
 		middleware.RequirePermission(modSvc, moderation.PermissionDismissReport, http.HandlerFunc(h.HandleDismissReport))))
 	mux.Handle("POST /_mod/reset-autohide", cop.Handler(
@@ -167,14 +279,36 @@ func SetupRouter(cfg Config) http.Handler {
 		middleware.RequirePermission(modSvc, moderation.PermissionManageLabels, http.HandlerFunc(h.HandleRemoveLabel))))
 	mux.Handle("GET /_mod/stats", middleware.RequireAdmin(modSvc,
 		middleware.RequireHTMXMiddleware(http.HandlerFunc(h.HandleAdminStats))))
+	mux.Handle("GET /_mod/stats.json", middleware.RequireAdmin(modSvc,
+		http.HandlerFunc(h.HandleAdminStatsJSON)))
 	mux.Handle("GET /_mod/export", middleware.RequireAdmin(modSvc,
 		http.HandlerFunc(h.HandleAdminExportDID)))
 	mux.Handle("POST /_mod/purge", cop.Handler(
 		middleware.RequireAdmin(modSvc, http.HandlerFunc(h.HandleAdminPurgeDID))))
 	mux.Handle("POST /_mod/rebuild", cop.Handler(
 		middleware.RequireAdmin(modSvc, http.HandlerFunc(h.HandleAdminRebuildDID))))
+	mux.Handle("POST /_mod/reindex", cop.Handler(
+		middleware.RequireAdmin(modSvc, http.HandlerFunc(h.HandleAdminReindex))))
+	mux.Handle("GET /_mod/reindex", middleware.RequireAdmin(modSvc,
+		http.HandlerFunc(h.HandleAdminReindexStatus)))
+	mux.Handle("GET /_mod/verify", middleware.RequireAdmin(modSvc,
+		http.HandlerFunc(h.HandleAdminVerifyIndex)))
+	mux.Handle("POST /_mod/verify", cop.Handler(
+		middleware.RequireAdmin(modSvc, http.HandlerFunc(h.HandleAdminVerifyIndex))))
 	mux.Handle("POST /_mod/refresh-handles", cop.Handler(
 		middleware.RequireAdmin(modSvc, http.HandlerFunc(h.HandleAdminRefreshHandles))))
+	mux.Handle("POST /_mod/automod", cop.Handler(
+		middleware.RequireAdmin(modSvc, http.HandlerFunc(h.HandleUpdateAutomod))))
+	mux.Handle("POST /_mod/moderators/add", cop.Handler(
+		middleware.RequireAdmin(modSvc, http.HandlerFunc(h.HandleAddModerator))))
+	mux.Handle("POST /_mod/moderators/remove", cop.Handler(
+		middleware.RequireAdmin(modSvc, http.HandlerFunc(h.HandleRemoveModerator))))
+	mux.Handle("POST /_mod/webhooks/add", cop.Handler(
+		middleware.RequireAdmin(modSvc, http.HandlerFunc(h.HandleAddWebhook))))
+	mux.Handle("POST /_mod/webhooks/remove", cop.Handler(
+		middleware.RequireAdmin(modSvc, http.HandlerFunc(h.HandleRemoveWebhook))))
+	mux.Handle("POST /_mod/refresh-profile", cop.Handler(
+		middleware.RequireModerator(modSvc, http.HandlerFunc(h.HandleAdminRefreshProfile))))
 	mux.Handle("GET /_mod/pds-records", middleware.RequireModerator(modSvc,
 		http.HandlerFunc(h.HandleAdminFetchPDSRecords)))
 
@@ -203,14 +337,28 @@ func SetupRouter(cfg Config) http.Handler {
 	// Apply middleware in order (outermost first, innermost last)
 	var handler http.Handler = mux
 
-	// 1. Limit request body size (innermost - runs first on request)
+	// 1. Check CSRF tokens on state-changing requests. Innermost so the body
+	// limit applies before a form body is read, and inside the auth
+	// middlewares so the session the token is tied to is known.
+	handler = middleware.CSRFMiddleware(cfg.CSRFSecret)(handler)
+
+	// 2. Limit request body size
 	handler = middleware.LimitBodyMiddleware(handler)
 
-	// 2. Add authenticated user attributes to the active HTTP span. This must
+	// 3. Read the viewer's time zone cookie for server-rendered timestamps
+	handler = middleware.ViewerTimezoneMiddleware(handler)
+
+	// 4. Add authenticated user attributes to the active HTTP span. This must
 	// sit inside CookieAuth so the request context already contains the DID.
 	handler = middleware.UserDIDSpanMiddleware(handler)
 
-	// 3. Apply OAuth middleware to add auth context
+	// 5. Authenticate app-password bearer tokens. Inside CookieAuth so an
+	// explicit token takes precedence over any cookie session.
+	if cfg.PasswordSessions != nil {
+		handler = middleware.BearerAuthMiddleware(atproto.BearerTokenLookup(cfg.PasswordSessions), cfg.OnAuth)(handler)
+	}
+
+	// 6. Apply OAuth middleware to add auth context
 	if cfg.OAuthApp != nil {
 		appName := ""
 		if cfg.App != nil {
@@ -225,23 +373,28 @@ func SetupRouter(cfg Config) http.Handler {
 		})(handler)
 	}
 
-	// 4. Apply rate limiting
+	// 7. Answer CORS preflights for /api/ and drop cookies from allowed
+	// cross-origin API calls. Outside the auth middlewares so those calls
+	// can only authenticate with a bearer token.
+	handler = middleware.CORSMiddleware(corsOrigins)(handler)
+
+	// 8. Apply rate limiting
 	rateLimitConfig := middleware.NewDefaultRateLimitConfig()
 	handler = middleware.RateLimitMiddleware(rateLimitConfig)(handler)
 
-	// 5. Apply security headers
+	// 9. Apply security headers
 	handler = middleware.SecurityHeadersMiddleware(handler)
 
-	// 6. Apply logging middleware
+	// 10. Apply logging middleware
 	handler = middleware.LoggingMiddleware(cfg.Logger, metrics.HTTPRequestObserver{})(handler)
 
-	// 7. Inject trace_id into zerolog context (runs after otelhttp creates the span)
+	// 11. Inject trace_id into zerolog context (runs after otelhttp creates the span)
 	handler = middleware.RequestIDMiddleware(cfg.Logger)(handler)
 
-	// 8. Enrich trace spans with client page context (runs inside otelhttp span)
+	// 12. Enrich trace spans with client page context (runs inside otelhttp span)
 	handler = pageContextMiddleware(handler)
 
-	// 9. Apply OpenTelemetry HTTP instrumentation (outermost - wraps everything)
+	// 13. Apply OpenTelemetry HTTP instrumentation (outermost - wraps everything)
 	handler = otelhttp.NewHandler(handler, "arabica",
 		otelhttp.WithFilter(func(r *http.Request) bool {
 			return !strings.HasPrefix(r.URL.Path, "/static/") && r.URL.Path != "/favicon.ico"
@@ -254,44 +407,57 @@ func SetupRouter(cfg Config) http.Handler {
 	return handler
 }
 
-func handleHealthz(h *handlers.Handler, consumer *firehose.Consumer) http.HandlerFunc {
+// handleHealthz is the liveness probe. It answers 200 whenever the process
+// is serving HTTP; dependency checks belong in handleReadyz so a slow
+// firehose reconnect never gets the container restarted.
+func handleHealthz() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": "ok"})
+	}
+}
+
+// handleReadyz is the readiness probe: 200 only when the SQLite index answers
+// a ping and, when a firehose consumer is running, the index has been
+// populated and Jetstream is connected. Each check is reported separately so
+// a failing probe shows which subsystem is holding it back.
+func handleReadyz(h *handlers.Handler, consumer *firehose.Consumer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		status := "ok"
-		httpStatus := http.StatusOK
+		ready := true
 
-		// Check firehose connection
-		firehoseCheck := map[string]any{"connected": false}
+		feedIndexCheck := map[string]any{"healthy": false, "ready": false}
+		if idx := h.FeedIndex(); idx != nil {
+			healthy := idx.DB().PingContext(r.Context()) == nil
+			feedIndexCheck["healthy"] = healthy
+			feedIndexCheck["ready"] = idx.IsReady()
+			if !healthy || (consumer != nil && !idx.IsReady()) {
+				ready = false
+			}
+		} else {
+			ready = false
+		}
+
+		firehoseCheck := map[string]any{"enabled": consumer != nil, "connected": false}
 		if consumer != nil {
 			connected := consumer.IsConnected()
 			firehoseCheck["connected"] = connected
 			if !connected {
-				status = "degraded"
+				ready = false
 			}
 		}
 
-		// Check SQLite feed index
-		feedIndexCheck := map[string]any{"healthy": false, "ready": false}
-		if idx := h.FeedIndex(); idx != nil {
-			feedIndexCheck["ready"] = idx.IsReady()
-			// feedIndexCheck["explore"] = idx.ExploreHealth(r.Context())
-			if err := idx.DB().PingContext(r.Context()); err != nil {
-				feedIndexCheck["healthy"] = false
-				status = "error"
-				httpStatus = http.StatusServiceUnavailable
-			} else {
-				feedIndexCheck["healthy"] = true
-			}
+		status, httpStatus := "ready", http.StatusOK
+		if !ready {
+			status, httpStatus = "not_ready", http.StatusServiceUnavailable
 		}
 
-		resp := map[string]any{
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(httpStatus)
+		_ = json.NewEncoder(w).Encode(map[string]any{
 			"status":     status,
 			"firehose":   firehoseCheck,
 			"feed_index": feedIndexCheck,
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(httpStatus)
-		_ = json.NewEncoder(w).Encode(resp)
+		})
 	}
 }
 
@@ -304,7 +470,8 @@ func handleHealthz(h *handlers.Handler, consumer *firehose.Consumer) http.Handle
 // nil handler in a bundle field skips the corresponding route, letting
 // future entities omit (say) modal partials without forcing every app
 // to publish stubs.
-func RegisterEntityRoutes(mux *http.ServeMux, cop *http.CrossOriginProtection, app *domain.App, bundles []handlers.EntityRouteBundle) {
+func RegisterEntityRoutes(mux *http.ServeMux, ctx AppRouteContext, bundles []handlers.EntityRouteBundle) {
+	app, cop := ctx.App, ctx.CSRF
 	for _, b := range bundles {
 		if app.DescriptorByType(b.RecordType) == nil {
 			// Bundle declared a route for an entity this app doesn't run.
@@ -327,7 +494,7 @@ func RegisterEntityRoutes(mux *http.ServeMux, cop *http.CrossOriginProtection, a
 			mux.HandleFunc("GET /"+urlPath+"/{actor}/{id}/og-image", RewriteActorToOwner(b.OGImage))
 		}
 		if b.Create != nil {
-			mux.Handle("POST /api/"+urlPath, cop.Handler(b.Create))
+			mux.Handle("POST /api/"+urlPath, ctx.Create(b.Create))
 		}
 		if b.Update != nil {
 			mux.Handle("PUT /api/"+urlPath+"/{id}", cop.Handler(b.Update))
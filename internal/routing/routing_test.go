@@ -1,12 +1,18 @@
 package routing
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	arabicaapp "tangled.org/arabica.social/arabica/internal/arabica/app"
+	"tangled.org/arabica.social/arabica/internal/firehose"
 	"tangled.org/arabica.social/arabica/internal/handlers"
 	"tangled.org/arabica.social/arabica/internal/lexicons"
 	oolongapp "tangled.org/arabica.social/arabica/internal/oolong/app"
@@ -19,16 +25,46 @@ func TestRegisterEntityRoutesFiltersUnionByAppDescriptors(t *testing.T) {
 	}
 
 	arabicaMux := http.NewServeMux()
-	RegisterEntityRoutes(arabicaMux, http.NewCrossOriginProtection(), arabicaapp.New(), bundles)
+	RegisterEntityRoutes(arabicaMux, AppRouteContext{App: arabicaapp.New(), CSRF: newTestCSRF(t)}, bundles)
 	assertRouteStatus(t, arabicaMux, "GET", "/beans/alice.test/r1", http.StatusOK)
 	assertRouteStatus(t, arabicaMux, "GET", "/teas/alice.test/r1", http.StatusNotFound)
 
 	oolongMux := http.NewServeMux()
-	RegisterEntityRoutes(oolongMux, http.NewCrossOriginProtection(), oolongapp.New(), bundles)
+	RegisterEntityRoutes(oolongMux, AppRouteContext{App: oolongapp.New(), CSRF: newTestCSRF(t)}, bundles)
 	assertRouteStatus(t, oolongMux, "GET", "/beans/alice.test/r1", http.StatusNotFound)
 	assertRouteStatus(t, oolongMux, "GET", "/teas/alice.test/r1", http.StatusOK)
 }
 
+func newTestCSRF(t *testing.T, origins ...string) *CSRFProtection {
+	t.Helper()
+	cop, valid := NewCSRFProtection(origins, zerolog.Nop())
+	require.Equal(t, len(origins), len(valid))
+	return cop
+}
+
+func TestCSRFProtectionTrustsCORSOriginsOnlyOnAPI(t *testing.T) {
+	cop := newTestCSRF(t, "https://app.example")
+	h := cop.Handler(okHandler("ok"))
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/api/brews", want: http.StatusOK},
+		{path: "/account/forget", want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, nil)
+			req.Header.Set("Origin", "https://app.example")
+			req.Header.Set("Sec-Fetch-Site", "cross-site")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
 func okHandler(body string) http.HandlerFunc {
 	return func(w http.ResponseWriter, _ *http.Request) {
 		_, _ = w.Write([]byte(body))
@@ -42,3 +78,45 @@ func assertRouteStatus(t *testing.T, h http.Handler, method, path string, want i
 	h.ServeHTTP(w, req)
 	assert.Equal(t, want, w.Code)
 }
+
+func TestHealthzAlwaysOK(t *testing.T) {
+	assertRouteStatus(t, handleHealthz(), "GET", "/healthz", http.StatusOK)
+}
+
+func TestReadyzReportsSubsystems(t *testing.T) {
+	tests := []struct {
+		name       string
+		withIndex  bool
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "no index", withIndex: false, wantStatus: http.StatusServiceUnavailable, wantBody: "not_ready"},
+		{name: "index open, firehose disabled", withIndex: true, wantStatus: http.StatusOK, wantBody: "ready"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &handlers.Handler{}
+			if tt.withIndex {
+				idx, err := firehose.NewFeedIndex(filepath.Join(t.TempDir(), "test.db"), time.Hour)
+				require.NoError(t, err)
+				t.Cleanup(func() { idx.Close() })
+				h.SetFeedIndex(idx)
+			}
+
+			req := httptest.NewRequest("GET", "/readyz", nil)
+			w := httptest.NewRecorder()
+			handleReadyz(h, nil).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			var body struct {
+				Status    string         `json:"status"`
+				Firehose  map[string]any `json:"firehose"`
+				FeedIndex map[string]any `json:"feed_index"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantBody, body.Status)
+			assert.Equal(t, false, body.Firehose["enabled"])
+			assert.Equal(t, tt.withIndex, body.FeedIndex["healthy"])
+		})
+	}
+}
@@ -16,6 +16,34 @@ type Provider struct {
 	SignupURL    string // Optional: if set, link directly to this URL instead of using prompt=create
 }
 
+// Defaults for the first-party provider, used when Options leaves them empty.
+const (
+	DefaultHandleDomain = "arabica.systems"
+	DefaultSignupURL    = "https://arabica.systems"
+)
+
+// Options tailors the catalog to a deployment. The zero value lists the
+// production providers with the default first-party PDS.
+type Options struct {
+	DevMode      bool   // Include DevOnly categories
+	HandleDomain string // Handle domain of the first-party PDS (e.g. "arabica.systems")
+	SignupURL    string // URL of the first-party PDS used for prompt=create
+}
+
+func (o Options) handleDomain() string {
+	if o.HandleDomain != "" {
+		return o.HandleDomain
+	}
+	return DefaultHandleDomain
+}
+
+func (o Options) signupURL() string {
+	if o.SignupURL != "" {
+		return o.SignupURL
+	}
+	return DefaultSignupURL
+}
+
 // Category groups providers under a heading.
 type Category struct {
 	Title       string
@@ -26,11 +54,11 @@ type Category struct {
 
 // Categories returns the list of PDS provider categories shown on the
 // create account page. This is the single source of truth for both the
-// rendered view and the server-side allowlist. When devMode is false,
+// rendered view and the server-side allowlist. Unless opts.DevMode is set,
 // categories flagged DevOnly are excluded.
-func Categories(devMode bool) []Category {
-	all := allCategories()
-	if devMode {
+func Categories(opts Options) []Category {
+	all := allCategories(opts)
+	if opts.DevMode {
 		return all
 	}
 	out := make([]Category, 0, len(all))
@@ -43,7 +71,7 @@ func Categories(devMode bool) []Category {
 	return out
 }
 
-func allCategories() []Category {
+func allCategories(opts Options) []Category {
 	return []Category{
 		{
 			Title:       "Recommended",
@@ -67,9 +95,9 @@ func allCategories() []Category {
 			Description: "These apps host your account and data for you.",
 			Providers: []Provider{
 				{
-					URL:         "https://arabica.systems",
+					URL:         opts.signupURL(),
 					Name:        "Arabica",
-					Domain:      "arabica.systems",
+					Domain:      opts.handleDomain(),
 					Description: "The official Arabica provider.",
 					Location:    "United States",
 					Badge:       "Invite Only",
@@ -147,9 +175,9 @@ func allCategories() []Category {
 // a prompt=create destination (i.e. a provider without an external
 // SignupURL override). External-redirect providers are excluded because
 // they never POST to the signup handler. DevOnly categories are only
-// considered when devMode is true.
-func IsAllowedPDSURL(url string, devMode bool) bool {
-	for _, cat := range Categories(devMode) {
+// considered when opts.DevMode is true.
+func IsAllowedPDSURL(url string, opts Options) bool {
+	for _, cat := range Categories(opts) {
 		for _, p := range cat.Providers {
 			if p.SignupURL == "" && p.URL == url {
 				return true
@@ -0,0 +1,44 @@
+package signup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func firstPartyProvider(t *testing.T, opts Options) Provider {
+	t.Helper()
+	for _, cat := range Categories(opts) {
+		for _, p := range cat.Providers {
+			if p.Name == "Arabica" {
+				return p
+			}
+		}
+	}
+	require.FailNow(t, "first-party provider missing from catalog")
+	return Provider{}
+}
+
+func TestCategories_FirstPartyProvider(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		p := firstPartyProvider(t, Options{})
+		assert.Equal(t, DefaultSignupURL, p.URL)
+		assert.Equal(t, DefaultHandleDomain, p.Domain)
+		assert.True(t, IsAllowedPDSURL(DefaultSignupURL, Options{}))
+	})
+
+	t.Run("self-hosted", func(t *testing.T) {
+		opts := Options{HandleDomain: "coffee.example", SignupURL: "https://pds.coffee.example"}
+		p := firstPartyProvider(t, opts)
+		assert.Equal(t, "https://pds.coffee.example", p.URL)
+		assert.Equal(t, "coffee.example", p.Domain)
+		assert.True(t, IsAllowedPDSURL("https://pds.coffee.example", opts))
+		assert.False(t, IsAllowedPDSURL(DefaultSignupURL, opts))
+	})
+}
+
+func TestCategories_DevOnly(t *testing.T) {
+	assert.False(t, IsAllowedPDSURL("https://pds.rip", Options{}))
+	assert.True(t, IsAllowedPDSURL("https://pds.rip", Options{DevMode: true}))
+}
@@ -64,6 +64,82 @@ func RecordToLike(record map[string]any, atURI string) (*Like, error) {
 	return like, nil
 }
 
+// FollowToRecord converts a Follow to an atproto record map.
+func FollowToRecord(collection string, follow *Follow) (map[string]any, error) {
+	if _, err := syntax.ParseDID(follow.SubjectDID); err != nil {
+		return nil, fmt.Errorf("invalid subject DID: %w", err)
+	}
+
+	return map[string]any{
+		"$type":     collection,
+		"subject":   follow.SubjectDID,
+		"createdAt": follow.CreatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// RecordToFollow converts an atproto record map to a Follow.
+func RecordToFollow(record map[string]any, atURI string) (*Follow, error) {
+	follow := &Follow{}
+	if atURI != "" {
+		parsedURI, err := syntax.ParseATURI(atURI)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AT-URI: %w", err)
+		}
+		follow.RKey = parsedURI.RecordKey().String()
+	}
+
+	subject, ok := record["subject"].(string)
+	if !ok || subject == "" {
+		return nil, fmt.Errorf("subject is required")
+	}
+	follow.SubjectDID = subject
+
+	createdAtStr, ok := record["createdAt"].(string)
+	if !ok {
+		return nil, fmt.Errorf("createdAt is required")
+	}
+	createdAt, err := time.Parse(time.RFC3339, createdAtStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid createdAt format: %w", err)
+	}
+	follow.CreatedAt = createdAt
+	return follow, nil
+}
+
+// BookmarkToRecord converts a Bookmark to an atproto record map.
+func BookmarkToRecord(collection string, bookmark *Bookmark) (map[string]any, error) {
+	if bookmark.SubjectURI == "" {
+		return nil, fmt.Errorf("subject URI is required")
+	}
+	if bookmark.SubjectCID == "" {
+		return nil, fmt.Errorf("subject CID is required")
+	}
+
+	return map[string]any{
+		"$type": collection,
+		"subject": map[string]any{
+			"uri": bookmark.SubjectURI,
+			"cid": bookmark.SubjectCID,
+		},
+		"createdAt": bookmark.CreatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// RecordToBookmark converts an atproto record map to a Bookmark. Bookmarks
+// share the like record's shape, so the like decoder does the work.
+func RecordToBookmark(record map[string]any, atURI string) (*Bookmark, error) {
+	like, err := RecordToLike(record, atURI)
+	if err != nil {
+		return nil, err
+	}
+	return &Bookmark{
+		RKey:       like.RKey,
+		SubjectURI: like.SubjectURI,
+		SubjectCID: like.SubjectCID,
+		CreatedAt:  like.CreatedAt,
+	}, nil
+}
+
 // CommentToRecord converts a Comment to an atproto record map.
 func CommentToRecord(collection string, comment *Comment) (map[string]any, error) {
 	if comment.SubjectURI == "" {
@@ -85,6 +161,9 @@ func CommentToRecord(collection string, comment *Comment) (map[string]any, error
 		"text":      comment.Text,
 		"createdAt": comment.CreatedAt.Format(time.RFC3339),
 	}
+	if !comment.EditedAt.IsZero() {
+		record["editedAt"] = comment.EditedAt.Format(time.RFC3339)
+	}
 	if comment.ParentURI != "" && comment.ParentCID != "" {
 		record["parent"] = map[string]any{
 			"uri": comment.ParentURI,
@@ -136,6 +215,14 @@ func RecordToComment(record map[string]any, atURI string) (*Comment, error) {
 	}
 	comment.CreatedAt = createdAt
 
+	if editedAtStr, ok := record["editedAt"].(string); ok && editedAtStr != "" {
+		editedAt, err := time.Parse(time.RFC3339, editedAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid editedAt format: %w", err)
+		}
+		comment.EditedAt = editedAt
+	}
+
 	if parent, ok := record["parent"].(map[string]any); ok {
 		if parentURI, ok := parent["uri"].(string); ok && parentURI != "" {
 			comment.ParentURI = parentURI
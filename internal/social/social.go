@@ -27,6 +27,29 @@ type CreateLikeRequest struct {
 	SubjectCID string `json:"subject_cid"`
 }
 
+// Follow represents one user following another. Follows drive the
+// personal "following" feed.
+type Follow struct {
+	RKey       string    `json:"rkey"`
+	SubjectDID string    `json:"subject_did"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Bookmark is a record the user saved to revisit later. Bookmarks are only
+// surfaced to their owner, so unlike likes they carry no public count.
+type Bookmark struct {
+	RKey       string    `json:"rkey"`
+	SubjectURI string    `json:"subject_uri"`
+	SubjectCID string    `json:"subject_cid"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CreateBookmarkRequest contains the data needed to create a bookmark.
+type CreateBookmarkRequest struct {
+	SubjectURI string `json:"subject_uri"`
+	SubjectCID string `json:"subject_cid"`
+}
+
 // Comment represents a comment on a record.
 type Comment struct {
 	RKey       string    `json:"rkey"`
@@ -38,6 +61,8 @@ type Comment struct {
 	ActorDID   string    `json:"actor_did,omitempty"`
 	ParentURI  string    `json:"parent_uri,omitempty"`
 	ParentCID  string    `json:"parent_cid,omitempty"`
+	// EditedAt is set when the author has changed the text since posting.
+	EditedAt time.Time `json:"edited_at,omitzero"`
 }
 
 // CreateCommentRequest contains the data needed to create a comment.
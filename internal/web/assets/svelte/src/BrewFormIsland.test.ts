@@ -221,4 +221,52 @@ describe("BrewFormIsland", () => {
       "espresso_yield_weight",
     );
   });
+
+  it("shows server validation errors by field and clears them on resubmit", async () => {
+    installAppCache();
+    const { form, target } = mountTarget();
+
+    render(BrewFormIsland, { target, props: { target } });
+
+    form.dispatchEvent(
+      new CustomEvent("htmx:afterRequest", {
+        bubbles: true,
+        detail: {
+          successful: false,
+          xhr: {
+            status: 422,
+            responseText: JSON.stringify({
+              errors: [
+                {
+                  field: "temperature",
+                  message: "temperature must be between 0 and 212",
+                },
+                { field: "bean_rkey", message: "Invalid bean selection" },
+                { field: "", message: "espresso params are invalid" },
+              ],
+            }),
+          },
+        },
+      }),
+    );
+
+    expect(
+      await screen.findByText("temperature must be between 0 and 212"),
+    ).toBeInTheDocument();
+    expect(screen.getByText("Invalid bean selection")).toBeInTheDocument();
+    expect(screen.getByRole("alert")).toHaveTextContent(
+      "espresso params are invalid",
+    );
+
+    form.dispatchEvent(
+      new CustomEvent("htmx:beforeRequest", { bubbles: true }),
+    );
+
+    await waitFor(() => {
+      expect(
+        screen.queryByText("temperature must be between 0 and 212"),
+      ).not.toBeInTheDocument();
+    });
+    expect(screen.queryByRole("alert")).not.toBeInTheDocument();
+  });
 });
@@ -1,5 +1,6 @@
 import { afterEach, describe, expect, it, vi } from "vitest";
 import {
+  csrfHeaders,
   extractFragment,
   fetchHTMXPartial,
   formToURLSearchParams,
@@ -53,6 +54,33 @@ describe("domContracts", () => {
     expect((options.body as URLSearchParams).toString()).toBe("name=Test+Bean");
   });
 
+  it("sends the page's CSRF token with form posts", async () => {
+    const fetchMock = vi
+      .spyOn(globalThis, "fetch")
+      .mockResolvedValue(new Response("ok"));
+    const meta = document.createElement("meta");
+    meta.name = "csrf-token";
+    meta.content = "tok123";
+    document.head.append(meta);
+    const form = document.createElement("form");
+    form.method = "post";
+    form.action = "/api/comments";
+
+    try {
+      expect(csrfHeaders()).toEqual({ "X-CSRF-Token": "tok123" });
+      await postURLEncodedForm(form);
+    } finally {
+      meta.remove();
+    }
+
+    const options = fetchMock.mock.calls[0]?.[1] as RequestInit;
+    expect(options.headers).toEqual({
+      "Content-Type": "application/x-www-form-urlencoded",
+      "X-CSRF-Token": "tok123",
+    });
+    expect(csrfHeaders()).toEqual({});
+  });
+
   it("sets the HTMX header when fetching protected partials", async () => {
     const fetchMock = vi
       .spyOn(globalThis, "fetch")
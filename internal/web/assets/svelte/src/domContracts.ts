@@ -9,16 +9,28 @@ export function formToURLSearchParams(form: HTMLFormElement) {
   return params;
 }
 
+// csrfHeaders returns the header the server checks on state-changing
+// requests, read from the meta tag the layout renders for signed-in users.
+export function csrfHeaders(): Record<string, string> {
+  const token = document
+    .querySelector<HTMLMetaElement>('meta[name="csrf-token"]')
+    ?.content;
+  return token ? { "X-CSRF-Token": token } : {};
+}
+
 export async function postURLEncodedForm(
   form: HTMLFormElement,
   body: URLSearchParams = formToURLSearchParams(form),
 ) {
-  const method = (form.method || "POST").toUpperCase();
+  // HTML forms can only declare GET/POST; data-method lets a form target
+  // PUT/DELETE routes when submitted through fetch.
+  const method = (form.dataset.method || form.method || "POST").toUpperCase();
   return fetch(form.action, {
     method,
     credentials: "same-origin",
     headers: {
       "Content-Type": "application/x-www-form-urlencoded",
+      ...csrfHeaders(),
     },
     body,
   });
@@ -3,6 +3,7 @@
 package bff
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strings"
@@ -59,6 +60,23 @@ func tempUnitSuffix(unit profileprefs.TemperatureUnit) string {
 	return "C"
 }
 
+// FormatWeight formats an amount already converted to system's unit:
+// whole grams for metric, ounces for imperial. Small ounce values keep a
+// second decimal so a 15g dose doesn't collapse to "0.5 oz". Returns "" for
+// a zero amount.
+func FormatWeight(amount float64, system profileprefs.UnitSystem) string {
+	if amount <= 0 {
+		return ""
+	}
+	if system != profileprefs.UnitSystemImperial {
+		return fmt.Sprintf("%.0fg", amount)
+	}
+	if amount < 10 {
+		return fmt.Sprintf("%.2f oz", amount)
+	}
+	return fmt.Sprintf("%.1f oz", amount)
+}
+
 // FormatTime formats seconds into a human-readable time string (e.g., "3m 30s").
 // Returns "N/A" if seconds is 0.
 func FormatTime(seconds int) string {
@@ -76,6 +94,15 @@ func FormatTime(seconds int) string {
 	return fmt.Sprintf("%dm %ds", minutes, remaining)
 }
 
+// FormatRatio formats a water:coffee ratio as "1:16.5", rounded to one
+// decimal place. Returns "" for a zero ratio.
+func FormatRatio(ratio float64) string {
+	if ratio <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("1:%.1f", ratio)
+}
+
 // FormatBeanRating formats a bean's optional rating as "X/10".
 // Returns empty string if rating is nil (unrated).
 func FormatBeanRating(rating *int) string {
@@ -145,6 +172,25 @@ func SafeAvatarURL(avatarURL string) string {
 	return ""
 }
 
+// BlobImageURL returns a Bluesky CDN URL for an image blob in did's repo,
+// or "" if either identifier looks malformed. Thumbnails are served
+// downscaled; otherwise the full-size rendition is used.
+func BlobImageURL(did, cid string, thumbnail bool) string {
+	if !strings.HasPrefix(did, "did:") || strings.ContainsAny(did, "/?#") || cid == "" {
+		return ""
+	}
+	for _, r := range cid {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return ""
+		}
+	}
+	size := "feed_fullsize"
+	if thumbnail {
+		size = "feed_thumbnail"
+	}
+	return "https://cdn.bsky.app/img/" + size + "/plain/" + did + "/" + cid + "@jpeg"
+}
+
 // SafeWebsiteURL validates and sanitizes website URLs for display.
 // Only allows HTTP/HTTPS URLs and performs basic validation.
 // Returns a safe URL or empty string if invalid.
@@ -178,11 +224,15 @@ func FormatISO(t time.Time) string {
 	return t.UTC().Format(time.RFC3339)
 }
 
-// FormatTimeAgo returns a human-readable relative time string
-func FormatTimeAgo(t time.Time) string {
-	now := time.Now()
-	diff := now.Sub(t)
+// FormatTimeAgo returns a human-readable relative time string. Within the
+// last day it counts minutes or hours; past that it counts calendar days in
+// the viewer's time zone, so "yesterday" is always the previous date there.
+func FormatTimeAgo(ctx context.Context, t time.Time) string {
+	return formatTimeAgoAt(t, time.Now(), ViewerLocation(ctx))
+}
 
+func formatTimeAgoAt(t, now time.Time, loc *time.Location) string {
+	diff := now.Sub(t)
 	switch {
 	case diff < time.Minute:
 		return "just now"
@@ -198,28 +248,40 @@ func FormatTimeAgo(t time.Time) string {
 			return "1 hour ago"
 		}
 		return fmt.Sprintf("%d hours ago", hours)
-	case diff < 48*time.Hour:
+	}
+
+	days := calendarDaysBetween(t.In(loc), now.In(loc))
+	switch {
+	case days <= 1:
 		return "yesterday"
-	case diff < 7*24*time.Hour:
-		days := int(diff.Hours() / 24)
+	case days < 7:
 		return fmt.Sprintf("%d days ago", days)
-	case diff < 30*24*time.Hour:
-		weeks := int(diff.Hours() / 24 / 7)
+	case days < 30:
+		weeks := days / 7
 		if weeks == 1 {
 			return "1 week ago"
 		}
 		return fmt.Sprintf("%d weeks ago", weeks)
-	case diff < 365*24*time.Hour:
-		months := int(diff.Hours() / 24 / 30)
+	case days < 365:
+		months := days / 30
 		if months == 1 {
 			return "1 month ago"
 		}
 		return fmt.Sprintf("%d months ago", months)
 	default:
-		years := int(diff.Hours() / 24 / 365)
+		years := days / 365
 		if years == 1 {
 			return "1 year ago"
 		}
 		return fmt.Sprintf("%d years ago", years)
 	}
 }
+
+// calendarDaysBetween counts date changes from a to b, reading each in its
+// own zone. The dates are compared as UTC midnights so a DST change between
+// them can't make a day 23 or 25 hours long.
+func calendarDaysBetween(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
@@ -1,12 +1,14 @@
 package bff
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"tangled.org/arabica.social/arabica/internal/profileprefs"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFormatTemp(t *testing.T) {
@@ -65,6 +67,21 @@ func TestFormatTime(t *testing.T) {
 	}
 }
 
+func TestFormatRatio(t *testing.T) {
+	assert.Equal(t, "1:16.7", FormatRatio(250.0/15.0))
+	assert.Equal(t, "1:16.0", FormatRatio(16))
+	assert.Equal(t, "", FormatRatio(0))
+}
+
+func TestFormatWeight(t *testing.T) {
+	assert.Equal(t, "15g", FormatWeight(15, profileprefs.UnitSystemMetric))
+	assert.Equal(t, "250g", FormatWeight(250, ""))
+	assert.Equal(t, "0.53 oz", FormatWeight(15/28.349523125, profileprefs.UnitSystemImperial))
+	assert.Equal(t, "8.82 oz", FormatWeight(250/28.349523125, profileprefs.UnitSystemImperial))
+	assert.Equal(t, "17.6 oz", FormatWeight(500/28.349523125, profileprefs.UnitSystemImperial))
+	assert.Equal(t, "", FormatWeight(0, profileprefs.UnitSystemImperial))
+}
+
 func TestHasTemp(t *testing.T) {
 	assert.False(t, HasTemp(0))
 	assert.False(t, HasTemp(-1))
@@ -98,6 +115,29 @@ func TestSafeAvatarURL(t *testing.T) {
 	}
 }
 
+func TestBlobImageURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		did       string
+		cid       string
+		thumbnail bool
+		expected  string
+	}{
+		{"full size", "did:plc:abc", "bafkreiabc123", false, "https://cdn.bsky.app/img/feed_fullsize/plain/did:plc:abc/bafkreiabc123@jpeg"},
+		{"thumbnail", "did:plc:abc", "bafkreiabc123", true, "https://cdn.bsky.app/img/feed_thumbnail/plain/did:plc:abc/bafkreiabc123@jpeg"},
+		{"missing cid", "did:plc:abc", "", false, ""},
+		{"not a did", "alice.bsky.social", "bafkreiabc123", false, ""},
+		{"did with path", "did:plc:abc/../x", "bafkreiabc123", false, ""},
+		{"cid with path", "did:plc:abc", "bafk/../../x", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, BlobImageURL(tt.did, tt.cid, tt.thumbnail))
+		})
+	}
+}
+
 func TestSafeWebsiteURL(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -122,7 +162,7 @@ func TestSafeWebsiteURL(t *testing.T) {
 }
 
 func TestFormatTimeAgo(t *testing.T) {
-	now := time.Now()
+	now := time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name     string
@@ -130,11 +170,12 @@ func TestFormatTimeAgo(t *testing.T) {
 		expected string
 	}{
 		{"just now", now.Add(-30 * time.Second), "just now"},
+		{"future", now.Add(time.Hour), "just now"},
 		{"1 minute ago", now.Add(-1 * time.Minute), "1 minute ago"},
 		{"5 minutes ago", now.Add(-5 * time.Minute), "5 minutes ago"},
 		{"1 hour ago", now.Add(-1 * time.Hour), "1 hour ago"},
 		{"3 hours ago", now.Add(-3 * time.Hour), "3 hours ago"},
-		{"yesterday", now.Add(-36 * time.Hour), "yesterday"},
+		{"yesterday", now.Add(-30 * time.Hour), "yesterday"},
 		{"3 days ago", now.Add(-3 * 24 * time.Hour), "3 days ago"},
 		{"1 week ago", now.Add(-8 * 24 * time.Hour), "1 week ago"},
 		{"3 weeks ago", now.Add(-22 * 24 * time.Hour), "3 weeks ago"},
@@ -146,7 +187,48 @@ func TestFormatTimeAgo(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.expected, FormatTimeAgo(tt.input))
+			assert.Equal(t, tt.expected, formatTimeAgoAt(tt.input, now, time.UTC))
 		})
 	}
 }
+
+func TestFormatTimeAgo_CalendarDays(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, ny)
+	}
+
+	// Clocks in New York went forward at 2am on March 8, 2026 and back at
+	// 2am on November 1.
+	tests := []struct {
+		name     string
+		then     time.Time
+		now      time.Time
+		loc      *time.Location
+		expected string
+	}{
+		{"10pm seen at 1am is hours", at(5, 19, 22, 0), at(5, 20, 1, 0), ny, "3 hours ago"},
+		{"10pm seen at 1am next night is yesterday", at(5, 18, 22, 0), at(5, 19, 23, 0), ny, "yesterday"},
+		{"25 hours over two midnights", at(5, 18, 23, 30), at(5, 20, 0, 30), ny, "2 days ago"},
+		{"just before midnight", at(5, 18, 23, 59), at(5, 19, 23, 59), ny, "yesterday"},
+		{"just after midnight", at(5, 18, 0, 1), at(5, 19, 23, 59), ny, "yesterday"},
+		{"47 hours is two days", at(5, 18, 0, 30), at(5, 19, 23, 30), ny, "yesterday"},
+		{"48 hours across midnights", at(5, 18, 23, 0), at(5, 20, 23, 0), ny, "2 days ago"},
+		{"23 hours across spring forward", at(3, 7, 12, 0), at(3, 8, 12, 0), ny, "23 hours ago"},
+		{"day before spring forward", at(3, 7, 1, 0), at(3, 8, 23, 0), ny, "yesterday"},
+		{"25 hours across fall back", at(10, 31, 12, 0), at(11, 1, 12, 0), ny, "yesterday"},
+		{"week across fall back", at(10, 28, 23, 30), at(11, 4, 0, 30), ny, "1 week ago"},
+		{"viewer zone sets the date", at(5, 18, 19, 0), at(5, 19, 21, 0), ny, "yesterday"},
+		{"same instants read in utc", at(5, 18, 19, 0), at(5, 19, 21, 0), time.UTC, "2 days ago"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatTimeAgoAt(tt.then, tt.now, tt.loc))
+		})
+	}
+}
+
+func TestFormatTimeAgo_Context(t *testing.T) {
+	assert.Equal(t, "just now", FormatTimeAgo(context.Background(), time.Now()))
+}
@@ -0,0 +1,66 @@
+package bff
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	// Embed the zone database so viewer zones resolve on hosts without
+	// /usr/share/zoneinfo, like minimal containers.
+	_ "time/tzdata"
+)
+
+// TimezoneCookie holds the viewer's IANA time zone name, e.g.
+// "Europe/Berlin". The layout script sets it from the browser.
+const TimezoneCookie = "tz"
+
+type viewerLocationKey struct{}
+
+// locations caches loaded zones by name; LoadLocation parses zone data on
+// every call.
+var locations sync.Map
+
+// ViewerLocationFromRequest returns the zone named by the request's
+// TimezoneCookie, or UTC when the cookie is missing or names no zone.
+func ViewerLocationFromRequest(r *http.Request) *time.Location {
+	c, err := r.Cookie(TimezoneCookie)
+	if err != nil || c.Value == "" || len(c.Value) > 64 {
+		return time.UTC
+	}
+	if loc, ok := locations.Load(c.Value); ok {
+		return loc.(*time.Location)
+	}
+	loc, err := time.LoadLocation(c.Value)
+	if err != nil || loc == time.Local {
+		return time.UTC
+	}
+	locations.Store(c.Value, loc)
+	return loc
+}
+
+// WithViewerLocation returns ctx carrying the viewer's time zone.
+func WithViewerLocation(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, viewerLocationKey{}, loc)
+}
+
+// ViewerLocation returns the viewer's time zone from ctx, or UTC.
+func ViewerLocation(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(viewerLocationKey{}).(*time.Location); ok && loc != nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// FormatViewerTime formats t with layout in the viewer's time zone. Pages
+// still rewrite <time data-local> elements in the browser; this is what
+// shows before that runs, and for readers without JavaScript.
+func FormatViewerTime(ctx context.Context, t time.Time, layout string) string {
+	return t.In(ViewerLocation(ctx)).Format(layout)
+}
+
+// FormatViewerTimeTitle formats t for a tooltip: the full time in the
+// viewer's zone, with the zone's abbreviation so it's unambiguous.
+func FormatViewerTimeTitle(ctx context.Context, t time.Time) string {
+	return FormatViewerTime(ctx, t, "Monday, January 2, 2006 at 3:04 PM MST")
+}
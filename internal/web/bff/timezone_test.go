@@ -0,0 +1,57 @@
+package bff
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewerLocationFromRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie string
+		want   string
+	}{
+		{"no cookie", "", "UTC"},
+		{"iana zone", "America/New_York", "America/New_York"},
+		{"unknown zone", "Mars/Olympus_Mons", "UTC"},
+		{"server local", "Local", "UTC"},
+		{"path traversal", "../../etc/passwd", "UTC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: TimezoneCookie, Value: tt.cookie})
+			}
+			assert.Equal(t, tt.want, ViewerLocationFromRequest(req).String())
+		})
+	}
+}
+
+func TestFormatViewerTime(t *testing.T) {
+	created := time.Date(2026, 6, 30, 23, 30, 0, 0, time.UTC)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantText  string
+		wantTitle string
+	}{
+		{"defaults to utc", context.Background(), "June 30, 2026", "Tuesday, June 30, 2026 at 11:30 PM UTC"},
+		{"viewer zone", WithViewerLocation(context.Background(), tokyo), "July 1, 2026", "Wednesday, July 1, 2026 at 8:30 AM JST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantText, FormatViewerTime(tt.ctx, created, "January 2, 2006"))
+			assert.Equal(t, tt.wantTitle, FormatViewerTimeTitle(tt.ctx, created))
+		})
+	}
+}
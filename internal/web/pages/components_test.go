@@ -160,3 +160,28 @@ func renderToString(t *testing.T, ctx context.Context, component templ.Component
 
 	return buf.String()
 }
+
+// TestFeedMoreItems tests the appended-page rendering used by infinite scroll
+func TestFeedMoreItems(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("trailing loader carries escaped cursor", func(t *testing.T) {
+		qs := FeedQueryState{
+			TypeFilter: "brew",
+			Sort:       "popular",
+			NextCursor: "2025-03-14T09:26:53Z|at://did:plc:abc/social.arabica.alpha.brew/xyz",
+		}
+
+		html := renderToString(t, ctx, FeedMoreItems(nil, true, FeedModerationContext{}, qs))
+
+		assert.Contains(t, html, "data-feed-loader")
+		assert.Contains(t, html, `hx-trigger="revealed, click"`)
+		assert.Contains(t, html, "/api/feed?type=brew&amp;sort=popular&amp;cursor=2025-03-14T09%3A26%3A53Z%7Cat%3A%2F%2Fdid%3Aplc%3Aabc%2Fsocial.arabica.alpha.brew%2Fxyz")
+	})
+
+	t.Run("last page omits loader", func(t *testing.T) {
+		html := renderToString(t, ctx, FeedMoreItems(nil, true, FeedModerationContext{}, FeedQueryState{}))
+
+		assert.NotContains(t, html, "data-feed-loader")
+	})
+}
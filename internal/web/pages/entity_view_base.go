@@ -15,6 +15,7 @@ type EntityViewBase struct {
 	SubjectURI         string
 	SubjectCID         string
 	IsLiked            bool
+	IsBookmarked       bool
 	LikeCount          int
 	CommentCount       int
 	Comments           []firehose.IndexedComment
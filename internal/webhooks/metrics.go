@@ -0,0 +1,18 @@
+package webhooks
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	eventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arabica_webhook_events_total",
+		Help: "New-record events handed to webhook delivery, partitioned by whether they were dispatched or dropped on a full queue.",
+	}, []string{"result"})
+
+	deliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arabica_webhook_deliveries_total",
+		Help: "Webhook deliveries after retries, partitioned by whether they were delivered, failed, or dropped on a full webhook queue.",
+	}, []string{"result"})
+)
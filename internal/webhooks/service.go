@@ -0,0 +1,268 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the request
+// body, keyed with the webhook's secret.
+const SignatureHeader = "X-Webhook-Signature"
+
+// Event is the JSON body POSTed to every webhook for a new record.
+type Event struct {
+	URI        string    `json:"uri"`
+	DID        string    `json:"did"`
+	Collection string    `json:"collection"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Config tunes delivery. Zero values take the defaults below.
+type Config struct {
+	QueueSize     int           // Events buffered before new ones are dropped -- Default: 1024
+	HookQueueSize int           // Deliveries buffered per webhook before new ones are dropped -- Default: 256
+	MaxAttempts   int           // Tries per webhook and event -- Default: 4
+	Backoff       time.Duration // Wait before the first retry, doubling after -- Default: 2s
+	Client        *http.Client  // Default: 10s timeout
+}
+
+// Service queues events and delivers them in the background. Enqueue never
+// blocks, so a slow or failing endpoint can't hold up the firehose consumer;
+// once the queue is full, events are dropped and counted. Each webhook has
+// its own queue and goroutine, and retries wait on a timer rather than on
+// that goroutine, so one endpoint can't delay deliveries to the others.
+type Service struct {
+	config Config
+	store  *Store
+	queue  chan Event
+
+	mu    sync.Mutex
+	hooks map[string]*hookWorker // by webhook ID
+
+	// done, when set, is called with the outcome of each delivery once it
+	// has succeeded or given up. Tests use it to wait for retries.
+	done func(h Webhook, err error)
+}
+
+// delivery is one event on its way to one webhook.
+type delivery struct {
+	uri     string
+	body    []byte
+	attempt int // attempts made so far
+}
+
+// hookWorker posts deliveries to a single webhook, one at a time.
+type hookWorker struct {
+	hook  Webhook
+	queue chan delivery
+	stop  chan struct{} // closed once the webhook has been removed
+}
+
+// NewService creates a Service reading webhooks from store. Call Start to
+// begin delivering.
+func NewService(store *Store, cfg Config) *Service {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1024
+	}
+	if cfg.HookQueueSize <= 0 {
+		cfg.HookQueueSize = 256
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 4
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = 2 * time.Second
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Service{
+		config: cfg,
+		store:  store,
+		queue:  make(chan Event, cfg.QueueSize),
+		hooks:  make(map[string]*hookWorker),
+	}
+}
+
+// Store returns the store webhooks are read from, for the admin handlers.
+func (s *Service) Store() *Store {
+	return s.store
+}
+
+// Enqueue schedules ev for delivery and reports whether it fit in the queue.
+func (s *Service) Enqueue(ev Event) bool {
+	select {
+	case s.queue <- ev:
+		return true
+	default:
+		eventsTotal.WithLabelValues("dropped").Inc()
+		log.Warn().Str("uri", ev.URI).Msg("webhooks: queue full, dropping event")
+		return false
+	}
+}
+
+// Start launches the dispatcher, which fans queued events out to the
+// per-webhook workers. Everything exits when ctx is done, leaving anything
+// still queued or waiting to retry undelivered.
+func (s *Service) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case ev := <-s.queue:
+				s.dispatch(ctx, ev)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// dispatch hands ev to the worker of every configured webhook. Webhooks are
+// read for each event so admin changes apply without a restart.
+func (s *Service) dispatch(ctx context.Context, ev Event) {
+	eventsTotal.WithLabelValues("dispatched").Inc()
+	hooks, err := s.store.List(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("webhooks: failed to load webhooks")
+		return
+	}
+	workers := s.syncWorkers(ctx, hooks)
+	if len(workers) == 0 {
+		return
+	}
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	for _, w := range workers {
+		if !w.enqueue(delivery{uri: ev.URI, body: body}) {
+			deliveriesTotal.WithLabelValues("dropped").Inc()
+			log.Warn().Str("webhook", w.hook.ID).Str("uri", ev.URI).Msg("webhooks: webhook queue full, dropping delivery")
+		}
+	}
+}
+
+// syncWorkers returns a running worker for each of hooks, starting any that
+// are missing and stopping those whose webhook has been removed.
+func (s *Service) syncWorkers(ctx context.Context, hooks []Webhook) []*hookWorker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	workers := make([]*hookWorker, 0, len(hooks))
+	current := make(map[string]bool, len(hooks))
+	for _, h := range hooks {
+		current[h.ID] = true
+		w, ok := s.hooks[h.ID]
+		if !ok {
+			w = &hookWorker{
+				hook:  h,
+				queue: make(chan delivery, s.config.HookQueueSize),
+				stop:  make(chan struct{}),
+			}
+			s.hooks[h.ID] = w
+			go s.run(ctx, w)
+		}
+		workers = append(workers, w)
+	}
+	for id, w := range s.hooks {
+		if !current[id] {
+			close(w.stop)
+			delete(s.hooks, id)
+		}
+	}
+	return workers
+}
+
+// run makes delivery attempts for w until its webhook is removed or ctx is
+// done.
+func (s *Service) run(ctx context.Context, w *hookWorker) {
+	for {
+		select {
+		case d := <-w.queue:
+			s.attempt(ctx, w, d)
+		case <-w.stop:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// attempt POSTs d once. Network errors, 429s, and 5xx answers are retried
+// with exponential backoff; the retry is put back on w's queue when its
+// timer fires, so the worker moves on to other deliveries meanwhile.
+func (s *Service) attempt(ctx context.Context, w *hookWorker, d delivery) {
+	retry, err := s.post(ctx, w.hook.URL, Sign(w.hook.Secret, d.body), d.body)
+	d.attempt++
+	if err != nil && retry && d.attempt < s.config.MaxAttempts {
+		time.AfterFunc(s.config.Backoff<<(d.attempt-1), func() {
+			if !w.enqueue(d) {
+				s.finish(w.hook, d, fmt.Errorf("webhook queue full, dropping retry: %w", err))
+			}
+		})
+		return
+	}
+	s.finish(w.hook, d, err)
+}
+
+// finish records the final outcome of d.
+func (s *Service) finish(h Webhook, d delivery, err error) {
+	if err != nil {
+		deliveriesTotal.WithLabelValues("failed").Inc()
+		log.Warn().Err(err).Str("webhook", h.ID).Str("uri", d.uri).Int("attempts", d.attempt).Msg("webhooks: delivery failed")
+	} else {
+		deliveriesTotal.WithLabelValues("delivered").Inc()
+	}
+	if s.done != nil {
+		s.done(h, err)
+	}
+}
+
+// enqueue adds d to w's queue and reports whether it fit.
+func (w *hookWorker) enqueue(d delivery) bool {
+	select {
+	case w.queue <- d:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Service) post(ctx context.Context, url, signature string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, signature)
+	resp, err := s.config.Client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("unexpected status %d", resp.StatusCode)
+}
+
+// Sign returns the SignatureHeader value for body. Receivers recompute it
+// with their copy of the secret and compare in constant time.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
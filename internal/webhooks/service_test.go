@@ -0,0 +1,214 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func setupTestStore(t *testing.T) *Store {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`
+		CREATE TABLE webhooks (
+			id         TEXT PRIMARY KEY,
+			url        TEXT NOT NULL,
+			secret     TEXT NOT NULL,
+			created_by TEXT NOT NULL,
+			created_at TEXT NOT NULL
+		)`)
+	require.NoError(t, err)
+	return NewStore(db)
+}
+
+func TestStoreAdd(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"https://example.com/hook", false},
+		{"http://localhost:8080/hook", false},
+		{"ftp://example.com/hook", true},
+		{"/relative", true},
+		{"https://", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			store := setupTestStore(t)
+			hook, err := store.Add(context.Background(), tt.url, "did:plc:admin")
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidURL)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, hook.ID)
+			assert.Len(t, hook.Secret, 64)
+
+			hooks, err := store.List(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, []Webhook{hook}, hooks)
+
+			require.NoError(t, store.Remove(context.Background(), hook.ID))
+			hooks, err = store.List(context.Background())
+			require.NoError(t, err)
+			assert.Empty(t, hooks)
+		})
+	}
+}
+
+func TestServiceDeliversSignedEvents(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantAttempts int32
+		wantBody     bool
+	}{
+		{"delivered first time", []int{http.StatusOK}, 1, true},
+		{"retries server errors", []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusNoContent}, 3, true},
+		{"gives up on client errors", []int{http.StatusGone}, 1, false},
+		{"stops after max attempts", []int{500, 500, 500, 500}, 3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			delivered := make(chan Event, 1)
+			var secret string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := attempts.Add(1)
+				body, _ := io.ReadAll(r.Body)
+				assert.Equal(t, Sign(secret, body), r.Header.Get(SignatureHeader))
+				status := tt.statuses[min(int(n), len(tt.statuses))-1]
+				w.WriteHeader(status)
+				if status < 300 {
+					var ev Event
+					assert.NoError(t, json.Unmarshal(body, &ev))
+					delivered <- ev
+				}
+			}))
+			defer srv.Close()
+
+			store := setupTestStore(t)
+			hook, err := store.Add(context.Background(), srv.URL, "did:plc:admin")
+			require.NoError(t, err)
+			secret = hook.Secret
+
+			svc := NewService(store, Config{MaxAttempts: 3, Backoff: time.Millisecond})
+			finished := make(chan error, 1)
+			svc.done = func(_ Webhook, err error) { finished <- err }
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			svc.Start(ctx)
+
+			ev := Event{
+				URI:        "at://did:plc:alice/social.arabica.alpha.bean/b1",
+				DID:        "did:plc:alice",
+				Collection: "social.arabica.alpha.bean",
+				Timestamp:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			}
+			require.True(t, svc.Enqueue(ev))
+			waitForDelivery(t, finished)
+
+			assert.Equal(t, tt.wantAttempts, attempts.Load())
+			if tt.wantBody {
+				assert.Equal(t, ev, <-delivered)
+			} else {
+				assert.Empty(t, delivered)
+			}
+		})
+	}
+}
+
+func TestServiceSlowWebhookDoesNotDelayOthers(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	defer close(release)
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer fast.Close()
+
+	store := setupTestStore(t)
+	_, err := store.Add(context.Background(), slow.URL, "did:plc:admin")
+	require.NoError(t, err)
+	fastHook, err := store.Add(context.Background(), fast.URL, "did:plc:admin")
+	require.NoError(t, err)
+
+	svc := NewService(store, Config{})
+	finished := make(chan Webhook, 2)
+	svc.done = func(h Webhook, err error) {
+		if err == nil {
+			finished <- h
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	svc.Start(ctx)
+
+	require.True(t, svc.Enqueue(Event{URI: "at://a"}))
+	select {
+	case h := <-finished:
+		assert.Equal(t, fastHook.ID, h.ID, "the hook listed second is delivered while the first hangs")
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery to the fast webhook waited on the slow one")
+	}
+}
+
+func TestServiceRetriesDoNotHoldUpLaterEvents(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	store := setupTestStore(t)
+	_, err := store.Add(context.Background(), srv.URL, "did:plc:admin")
+	require.NoError(t, err)
+
+	// The first event's retry is an hour out; the second must not wait for it.
+	svc := NewService(store, Config{Backoff: time.Hour})
+	finished := make(chan error, 1)
+	svc.done = func(_ Webhook, err error) { finished <- err }
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	svc.Start(ctx)
+
+	require.True(t, svc.Enqueue(Event{URI: "at://a"}))
+	require.True(t, svc.Enqueue(Event{URI: "at://b"}))
+	assert.NoError(t, waitForDelivery(t, finished))
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+// waitForDelivery returns the outcome of the next delivery to finish.
+func waitForDelivery(t *testing.T, finished <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-finished:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery did not finish")
+		return nil
+	}
+}
+
+func TestServiceEnqueueDoesNotBlock(t *testing.T) {
+	svc := NewService(setupTestStore(t), Config{QueueSize: 1})
+	assert.True(t, svc.Enqueue(Event{URI: "at://a"}))
+	assert.False(t, svc.Enqueue(Event{URI: "at://b"}), "a full queue drops instead of waiting")
+}
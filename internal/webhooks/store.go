@@ -0,0 +1,100 @@
+// Package webhooks notifies admin-configured URLs when the firehose indexes
+// a new community record, so integrators don't have to poll the feed.
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Webhook is an endpoint that receives new-record events. Secret keys the
+// signature sent with each delivery.
+type Webhook struct {
+	ID        string
+	URL       string
+	Secret    string
+	CreatedBy string
+	CreatedAt time.Time
+}
+
+// ErrInvalidURL is returned by Add for anything but an absolute http or
+// https URL.
+var ErrInvalidURL = errors.New("webhook URL must be an absolute http or https URL")
+
+// Store persists webhooks in SQLite. It shares the database connection with
+// the firehose FeedIndex, whose schema creates the webhooks table.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a Store backed by db.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// List returns every webhook, oldest first.
+func (s *Store) List(ctx context.Context) ([]Webhook, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, url, secret, created_by, created_at FROM webhooks ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var hooks []Webhook
+	for rows.Next() {
+		var (
+			h         Webhook
+			createdAt string
+		)
+		if err := rows.Scan(&h.ID, &h.URL, &h.Secret, &h.CreatedBy, &createdAt); err != nil {
+			return nil, fmt.Errorf("list webhooks: %w", err)
+		}
+		h.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+		hooks = append(hooks, h)
+	}
+	return hooks, rows.Err()
+}
+
+// Add registers rawURL with a freshly generated secret and returns the new
+// webhook.
+func (s *Store) Add(ctx context.Context, rawURL, createdBy string) (Webhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Webhook{}, ErrInvalidURL
+	}
+	h := Webhook{
+		ID:        randomHex(8),
+		URL:       u.String(),
+		Secret:    randomHex(32),
+		CreatedBy: createdBy,
+		CreatedAt: time.Now().UTC(),
+	}
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO webhooks (id, url, secret, created_by, created_at) VALUES (?, ?, ?, ?, ?)`,
+		h.ID, h.URL, h.Secret, h.CreatedBy, h.CreatedAt.Format(time.RFC3339Nano)); err != nil {
+		return Webhook{}, fmt.Errorf("add webhook: %w", err)
+	}
+	return h, nil
+}
+
+// Remove deletes the webhook with id. Removing an unknown id is not an
+// error.
+func (s *Store) Remove(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("remove webhook: %w", err)
+	}
+	return nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
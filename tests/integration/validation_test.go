@@ -3,6 +3,7 @@
 package integration
 
 import (
+	"encoding/json"
 	"net/url"
 	"strings"
 	"testing"
@@ -148,32 +149,39 @@ func TestHTTP_BrewValidationErrors(t *testing.T) {
 	)), "bean")
 
 	cases := []struct {
-		name string
-		form url.Values
+		name  string
+		form  url.Values
+		field string
 	}{
 		{
-			name: "missing_bean_rkey",
-			form: form("method", "Pour Over", "water_amount", "300"),
+			name:  "missing_bean_rkey",
+			form:  form("method", "Pour Over", "water_amount", "300"),
+			field: "bean_rkey",
 		},
 		{
-			name: "invalid_bean_rkey_format",
-			form: form("bean_rkey", "not a valid rkey!"),
+			name:  "invalid_bean_rkey_format",
+			form:  form("bean_rkey", "not a valid rkey!"),
+			field: "bean_rkey",
 		},
 		{
-			name: "invalid_grinder_rkey_format",
-			form: form("bean_rkey", beanRKey, "grinder_rkey", "bad rkey"),
+			name:  "invalid_grinder_rkey_format",
+			form:  form("bean_rkey", beanRKey, "grinder_rkey", "bad rkey"),
+			field: "grinder_rkey",
 		},
 		{
-			name: "temperature_out_of_range",
-			form: form("bean_rkey", beanRKey, "temperature", "999"),
+			name:  "temperature_out_of_range",
+			form:  form("bean_rkey", beanRKey, "temperature", "999"),
+			field: "temperature",
 		},
 		{
-			name: "water_amount_out_of_range",
-			form: form("bean_rkey", beanRKey, "water_amount", "999999"),
+			name:  "water_amount_out_of_range",
+			form:  form("bean_rkey", beanRKey, "water_amount", "999999"),
+			field: "water_amount",
 		},
 		{
-			name: "rating_out_of_range",
-			form: form("bean_rkey", beanRKey, "rating", "42"),
+			name:  "rating_out_of_range",
+			form:  form("bean_rkey", beanRKey, "rating", "42"),
+			field: "rating",
 		},
 	}
 
@@ -181,7 +189,16 @@ func TestHTTP_BrewValidationErrors(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			resp := h.PostForm("/brews", tc.form)
 			body := ReadBody(t, resp)
-			require.Equal(t, 400, resp.StatusCode, statusErr(resp, body))
+			require.Equal(t, 422, resp.StatusCode, statusErr(resp, body))
+			var got struct {
+				Errors []struct {
+					Field   string `json:"field"`
+					Message string `json:"message"`
+				} `json:"errors"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(body), &got), body)
+			require.NotEmpty(t, got.Errors)
+			assert.Equal(t, tc.field, got.Errors[0].Field)
 		})
 	}
 
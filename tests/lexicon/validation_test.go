@@ -20,6 +20,7 @@ import (
 
 	"tangled.org/arabica.social/arabica/internal/arabica/entities"
 	"tangled.org/arabica.social/arabica/internal/oolong/entities"
+	"tangled.org/arabica.social/arabica/internal/social"
 )
 
 const sampleCID = "bafyreigh2akiscaildcqabsyg3dfr6chu3fgpregiymsck7e7aqa4s52zy"
@@ -196,6 +197,7 @@ func TestArabicaLexicons(t *testing.T) {
 			GrindSize:    "Medium",
 			TastingNotes: "Fruity and bright",
 			Rating:       8,
+			Image:        &arabica.BrewImage{CID: sampleCID, MimeType: "image/jpeg", Size: 48213},
 			CreatedAt:    createdAt,
 			Pours: []*arabica.Pour{
 				{WaterAmount: 50, TimeSeconds: 30},
@@ -217,6 +219,27 @@ func TestArabicaLexicons(t *testing.T) {
 		samples = append(samples, sample{"like/full", arabica.NSIDLike, like})
 	}
 
+	// Follow
+	{
+		follow, err := social.FollowToRecord(arabica.NSIDFollow, &social.Follow{
+			SubjectDID: "did:plc:followed",
+			CreatedAt:  createdAt,
+		})
+		require.NoError(t, err)
+		samples = append(samples, sample{"follow/full", arabica.NSIDFollow, follow})
+	}
+
+	// Bookmark
+	{
+		bookmark, err := social.BookmarkToRecord(arabica.NSIDBookmark, &social.Bookmark{
+			SubjectURI: beanURI,
+			SubjectCID: sampleCID,
+			CreatedAt:  createdAt,
+		})
+		require.NoError(t, err)
+		samples = append(samples, sample{"bookmark/full", arabica.NSIDBookmark, bookmark})
+	}
+
 	// Comment
 	{
 		minimal, err := arabica.CommentToRecord(&arabica.Comment{
@@ -240,6 +263,23 @@ func TestArabicaLexicons(t *testing.T) {
 		samples = append(samples, sample{"comment/full", arabica.NSIDComment, full})
 	}
 
+	// Preferences
+	{
+		minimal, err := arabica.PreferencesToRecord(&arabica.BrewPreferences{UpdatedAt: createdAt}, "", "")
+		require.NoError(t, err)
+		samples = append(samples, sample{"preferences/minimal", arabica.NSIDPreferences, minimal})
+
+		full, err := arabica.PreferencesToRecord(&arabica.BrewPreferences{
+			Method:       "V60",
+			CoffeeAmount: 15,
+			Ratio:        16.5,
+			Temperature:  93.5,
+			UpdatedAt:    createdAt,
+		}, grinderURI, brewerURI)
+		require.NoError(t, err)
+		samples = append(samples, sample{"preferences/full", arabica.NSIDPreferences, full})
+	}
+
 	runSamples(t, cat, samples)
 }
 
@@ -351,6 +391,27 @@ func TestOolongLexicons(t *testing.T) {
 		samples = append(samples, sample{"oolong-like/full", oolong.NSIDLike, like})
 	}
 
+	// Follow
+	{
+		follow, err := social.FollowToRecord(oolong.NSIDFollow, &social.Follow{
+			SubjectDID: "did:plc:followed",
+			CreatedAt:  createdAt,
+		})
+		require.NoError(t, err)
+		samples = append(samples, sample{"oolong-follow/full", oolong.NSIDFollow, follow})
+	}
+
+	// Bookmark
+	{
+		bookmark, err := social.BookmarkToRecord(oolong.NSIDBookmark, &social.Bookmark{
+			SubjectURI: teaURI,
+			SubjectCID: sampleCID,
+			CreatedAt:  createdAt,
+		})
+		require.NoError(t, err)
+		samples = append(samples, sample{"oolong-bookmark/full", oolong.NSIDBookmark, bookmark})
+	}
+
 	// Comment
 	{
 		commentURI := "at://did:plc:test/social.oolong.alpha.comment/comment123"